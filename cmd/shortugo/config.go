@@ -0,0 +1,80 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/apetsko/shortugo/internal/config"
+)
+
+// configUsage describes the config subcommands.
+const configUsage = `usage:
+  shortugo config check [flags]
+      validate the configuration the server would run with
+  shortugo config print [-effective] [-format json|yaml|toml] [flags]
+      print the defaults, or with -effective the configuration the server would run with;
+      secrets are redacted
+
+The configuration is read like the server does: defaults < -config file < environment < flags.`
+
+// runConfig runs the config subcommand of args and returns the exit status.
+func runConfig(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, configUsage)
+		return 2
+	}
+
+	fs := flag.NewFlagSet("shortugo config "+args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	switch args[0] {
+	case "check":
+		cfg, err := config.Load(fs, args[1:])
+		if err == nil {
+			err = cfg.Validate()
+		}
+		if err != nil {
+			return configError(stderr, err)
+		}
+		source := "defaults, environment and flags"
+		if cfg.Config != "" {
+			source = cfg.Config + ", " + source
+		}
+		fmt.Fprintf(stdout, "configuration is valid (%s)\n", source)
+		return 0
+
+	case "print":
+		effective := fs.Bool("effective", false, "print the configuration the server would run with rather than the defaults")
+		format := fs.String("format", config.FormatYAML, "output format: json, yaml or toml")
+		cfg, err := config.Load(fs, args[1:])
+		if err != nil {
+			return configError(stderr, err)
+		}
+		if !*effective {
+			cfg = config.Defaults()
+		}
+		if err := config.Print(stdout, cfg, *format); err != nil {
+			return configError(stderr, err)
+		}
+		// An invalid configuration is printed, so it can be looked into, but still reported
+		if err := cfg.Validate(); err != nil && *effective {
+			return configError(stderr, err)
+		}
+		return 0
+
+	default:
+		fmt.Fprintln(stderr, configUsage)
+		return 2
+	}
+}
+
+// configError reports err and returns the exit status of an invalid configuration.
+func configError(stderr io.Writer, err error) int {
+	if errors.Is(err, flag.ErrHelp) {
+		return 2
+	}
+	fmt.Fprintln(stderr, "invalid configuration: "+err.Error())
+	return 1
+}
@@ -2,18 +2,29 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
+	"io"
 	"log"
+	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
+	"github.com/apetsko/shortugo/internal/auth"
+	"github.com/apetsko/shortugo/internal/blocklist"
 	"github.com/apetsko/shortugo/internal/config"
+	"github.com/apetsko/shortugo/internal/geoip"
+	"github.com/apetsko/shortugo/internal/health"
 	"github.com/apetsko/shortugo/internal/logging"
+	"github.com/apetsko/shortugo/internal/qrcode"
+	"github.com/apetsko/shortugo/internal/ratelimit"
+	"github.com/apetsko/shortugo/internal/reload"
 	"github.com/apetsko/shortugo/internal/server/grpc"
 	"github.com/apetsko/shortugo/internal/server/http"
 	"github.com/apetsko/shortugo/internal/server/http/handlers"
 	"github.com/apetsko/shortugo/internal/storages"
-	"go.uber.org/zap/zapcore"
+	"github.com/apetsko/shortugo/internal/tracing"
 )
 
 // Build info vars
@@ -24,23 +35,104 @@ var (
 )
 
 func main() {
+	// Subcommands checking and printing the configuration
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		os.Exit(runConfig(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
 	fmt.Println("Build version: " + BuildVersion)
 	fmt.Println("Build date: " + BuildDate)
 	fmt.Println("Build commit: " + BuildCommit)
 
-	logger, err := logging.New(zapcore.DebugLevel)
+	cfg, err := config.New()
+	if err != nil {
+		log.Fatal("Invalid configuration: ", err)
+	}
+
+	logOpts, err := cfg.LogOptions()
+	if err != nil {
+		log.Fatal("Invalid log level: ", err)
+	}
+	logger, err := logging.NewWithOptions(logOpts)
 	if err != nil {
 		log.Fatal("Failed to initialize logger:", err)
 	}
+	defer func() {
+		_ = logger.Close()
+	}()
 
-	logger.Infof("Starting server with LogLevel: %s", zapcore.DebugLevel)
+	logger.Infof("Starting server with LogLevel: %s", logOpts.Level)
 
-	cfg, err := config.New()
+	// Tracing of HTTP, gRPC and storage calls
+	tracer, err := tracing.Setup(context.Background(), tracing.Options{
+		Exporter:    cfg.TraceExporter,
+		Endpoint:    cfg.TraceEndpoint,
+		File:        cfg.TraceFile,
+		SampleRatio: cfg.TraceSampleRatio,
+		Service:     "shortugo",
+		Version:     BuildVersion,
+	})
 	if err != nil {
 		logger.Fatal(err.Error())
 	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := tracer.Shutdown(ctx); err != nil {
+			logger.Error("failed to flush traces: " + err.Error())
+		}
+	}()
+
+	// Readiness of the service: not ready until the storage is, and from the start of shutdown
+	checker := health.New(logger)
+
+	// The storage is set once it is ready; the servers turn requests away until then
+	handler := handlers.NewURLHandler(cfg.BaseURL, nil, logger, cfg.Secret, cfg.TrustedSubnet)
+	handler.Health = checker
+	handler.QRCodes = qrcode.NewCache(cfg.QRCacheSize)
+	handler.RedirectType = cfg.RedirectType
+	handler.RedirectMode = cfg.RedirectMode
+	handler.PasswordAttempts = ratelimit.New(cfg.PasswordAttempts, cfg.PasswordWindow)
+
+	// Country database for routing rules
+	if cfg.GeoIPPath != "" {
+		db, err := geoip.Open(cfg.GeoIPPath)
+		if err != nil {
+			logger.Fatal(err.Error())
+		}
+		defer func() {
+			if err := db.Close(); err != nil {
+				logger.Error("failed to close GeoIP database: " + err.Error())
+			}
+		}()
+		handler.GeoIP = db
+	}
+
+	// Domains links may not lead to
+	handler.Blocklist = blocklist.New()
+	if cfg.BlocklistPath != "" {
+		if handler.Blocklist, err = blocklist.Load(cfg.BlocklistPath); err != nil {
+			logger.Fatal(err.Error())
+		}
+	}
+
+	// Users and API keys allowed to use the admin API
+	handler.Admins = auth.NewAdmins(cfg.AdminUsers, cfg.AdminKeys)
 
-	storage, err := storages.Init(cfg.DatabaseDSN, cfg.FileStoragePath, logger)
+	// Start HTTP server
+	httpSrv, err := http.Run(cfg, handler, logger)
+	if err != nil {
+		logger.Fatal("HTTP server failed: " + err.Error())
+	}
+
+	// Start gRPC server
+	grpcSrv, err := grpc.Run(cfg, handler, logger)
+	if err != nil {
+		logger.Fatal("gRPC server failed: " + err.Error())
+	}
+
+	// Probes are answered while the storage is initialized and the database migrated
+	storage, err := storages.Init(cfg.DatabaseDSN, cfg.FileStoragePath, logger, checker.Migrating)
 	if err != nil {
 		logger.Fatal(err.Error())
 	}
@@ -52,27 +144,51 @@ func main() {
 		}
 	}()
 
-	handler := handlers.NewURLHandler(cfg.BaseURL, storage, logger, cfg.Secret, cfg.TrustedSubnet)
+	handler.Storage = tracing.NewStorage(storage)
+
+	// Audit log of mutations and admin actions
+	if handler.AuditLog, err = storages.InitAudit(cfg.AuditFile, storage, logger); err != nil {
+		logger.Fatal(err.Error())
+	}
+	if c, ok := handler.AuditLog.(io.Closer); ok {
+		defer func() {
+			if err := c.Close(); err != nil {
+				logger.Error("failed to close audit log: " + err.Error())
+			}
+		}()
+	}
 
 	// Batch deletion
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	go storages.StartBatchDeleteProcessor(ctx, storage, handler.ToDelete, logger)
 
-	// Start HTTP server
-	if _, err := http.Run(cfg, handler, logger); err != nil {
-		logger.Fatal("HTTP server failed: " + err.Error())
-	}
+	// Ready as long as the storage answers
+	checker.Start(ctx, storage.Ping, cfg.HealthInterval)
+	logger.Info("Service is ready")
 
-	// Start gRPC server
-	if _, err := grpc.Run(cfg, handler, logger); err != nil {
-		logger.Fatal("gRPC server failed: " + err.Error())
-	}
+	// Settings safe to change at runtime are applied on SIGHUP and when the configuration file changes
+	reloader := reload.New(cfg, func() (*config.Config, error) {
+		return config.Load(flag.NewFlagSet(os.Args[0], flag.ContinueOnError), os.Args[1:])
+	}, handler, logger)
+	go reloader.Watch(ctx, cfg.ReloadInterval)
 
 	// Graceful shutdown
-	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT, syscall.SIGQUIT)
+	sigCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT, syscall.SIGQUIT)
 	defer stop()
 
-	<-ctx.Done()
+	<-sigCtx.Done()
+
+	// Load balancers stop sending requests before the servers stop taking them
+	checker.Drain()
+	logger.Info(fmt.Sprintf("Draining for %s...", cfg.ShutdownDrain))
+	time.Sleep(cfg.ShutdownDrain)
+
 	logger.Info("Shutting down servers...")
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelShutdown()
+	if err := httpSrv.Shutdown(shutdownCtx); err != nil {
+		logger.Error("failed to shut down HTTP server: " + err.Error())
+	}
+	grpcSrv.GracefulStop()
 }
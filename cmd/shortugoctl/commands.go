@@ -0,0 +1,418 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+
+	pb "github.com/apetsko/shortugo/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// command is a subcommand of shortugoctl.
+type command struct {
+	summary string
+	run     func(ctx context.Context, c *client, name string, args []string) (*result, error)
+}
+
+// commands are the subcommands by name.
+var commands = map[string]command{
+	"shorten": {summary: "shorten URLs for a user", run: shorten},
+	"expand":  {summary: "show the URLs short links lead to", run: expand},
+	"list":    {summary: "list the links of a user", run: list},
+	"delete":  {summary: "delete links of a user", run: deleteLinks},
+	"stats":   {summary: "count the links and users (trusted subnet)", run: stats},
+	"ping":    {summary: "check the storage of the server", run: ping},
+	"purge":   {summary: "remove deleted links for good (trusted subnet)", run: purge},
+	"block":   {summary: "block domains and their subdomains (trusted subnet)", run: block},
+	"unblock": {summary: "unblock domains (trusted subnet)", run: unblock},
+	"blocked": {summary: "list the blocked domains (trusted subnet)", run: blocked},
+	"owner":   {summary: "show who created links (trusted subnet)", run: owner},
+
+	"link":       {summary: "show links with their owner and state (admin API key)", run: adminLink},
+	"disable":    {summary: "stop the redirects of links (admin API key)", run: disableLinks},
+	"enable":     {summary: "let disabled links redirect again (admin API key)", run: enableLinks},
+	"user-links": {summary: "list the links of a user with their state (admin API key)", run: userLinks},
+}
+
+// commandNames returns the names of the commands in alphabetical order.
+func commandNames() []string {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// parseArgs parses the flags of a command and checks that at least minArgs arguments follow them.
+// On failure the usage of the command is printed and errUsage returned.
+func parseArgs(fs *flag.FlagSet, args []string, argsUsage string, minArgs int) error {
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: shortugoctl %s [flags] %s\n", fs.Name(), argsUsage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	if fs.NArg() < minArgs {
+		fs.Usage()
+		return errUsage
+	}
+	return nil
+}
+
+// itemError describes the failure of a call for one of several arguments of a command.
+func itemError(item string, err error) error {
+	st := status.Convert(err)
+	return fmt.Errorf("%s: %s: %s", item, st.Code(), st.Message())
+}
+
+// newFlagSet returns the flag set of the command name, printing its usage and errors to stderr.
+func newFlagSet(name string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	return fs
+}
+
+// shorten shortens the URL arguments for the user given by -user.
+func shorten(ctx context.Context, c *client, name string, args []string) (*result, error) {
+	fs := newFlagSet(name, c.stderr)
+	userID := fs.String("user", "", "user ID owning the links (required)")
+	maxClicks := fs.Int("max-clicks", 0, "make the links gone after that many redirects")
+	password := fs.String("password", "", "put the links behind a password form")
+	if err := parseArgs(fs, args, "URL...", 1); err != nil {
+		return nil, err
+	}
+	if *userID == "" {
+		fs.Usage()
+		return nil, errUsage
+	}
+
+	maxClicks32 := int32(*maxClicks)
+	req := &pb.ShortenBatchRequest{UserId: userID}
+	for i, u := range fs.Args() {
+		correlationID := strconv.Itoa(i)
+		req.Urls = append(req.Urls, &pb.URLPair{
+			CorrelationId: &correlationID,
+			OriginalUrl:   &u,
+			Options:       &pb.LinkOptions{MaxClicks: &maxClicks32, Password: password},
+		})
+	}
+
+	resp, err := c.ShortenBatch(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Items the server refused carry the reason instead of the short URL
+	res := &result{columns: []string{"original_url", "short_url", "error"}}
+	for _, r := range resp.GetResults() {
+		i, _ := strconv.Atoi(r.GetCorrelationId())
+		if i < 0 || i >= fs.NArg() {
+			continue
+		}
+		if r.GetOriginalUrl() == "" {
+			res.add(fs.Arg(i), "", r.GetShortUrl())
+		} else {
+			res.add(fs.Arg(i), r.GetShortUrl(), "")
+		}
+	}
+	return res, nil
+}
+
+// expand resolves the short link ID arguments.
+func expand(ctx context.Context, c *client, name string, args []string) (*result, error) {
+	fs := newFlagSet(name, c.stderr)
+	password := fs.String("password", "", "password of protected links")
+	if err := parseArgs(fs, args, "ID...", 1); err != nil {
+		return nil, err
+	}
+
+	res := &result{columns: []string{"id", "original_url"}}
+	for _, id := range fs.Args() {
+		resp, err := c.Expand(ctx, &pb.ExpandRequest{ShortUrlId: &id, Password: password})
+		if err != nil {
+			return nil, itemError(id, err)
+		}
+		res.add(id, resp.GetOriginalUrl())
+	}
+	return res, nil
+}
+
+// list streams the links of the user given by -user.
+func list(ctx context.Context, c *client, name string, args []string) (*result, error) {
+	fs := newFlagSet(name, c.stderr)
+	userID := fs.String("user", "", "user ID owning the links (required)")
+	tag := fs.String("tag", "", "only list links with this tag")
+	folder := fs.String("folder", "", "only list links in this folder")
+	if err := parseArgs(fs, args, "", 0); err != nil {
+		return nil, err
+	}
+	if *userID == "" {
+		fs.Usage()
+		return nil, errUsage
+	}
+
+	stream, err := c.StreamUserURLs(ctx, &pb.ListUserURLsRequest{UserId: userID, Tag: tag, Folder: folder})
+	if err != nil {
+		return nil, err
+	}
+
+	res := &result{columns: []string{"short_url", "original_url", "title", "folder", "tags"}}
+	for {
+		u, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return res, nil
+		}
+		// A user without links is an empty list
+		if status.Code(err) == codes.NotFound {
+			return res, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		tags := u.GetTags()
+		if tags == nil {
+			tags = []string{}
+		}
+		res.add(u.GetShortUrl(), u.GetOriginalUrl(), u.GetTitle(), u.GetFolder(), tags)
+	}
+}
+
+// deleteLinks deletes the link ID arguments of the user given by -user.
+// The server deletes links in the background, so the IDs are reported as queued.
+func deleteLinks(ctx context.Context, c *client, name string, args []string) (*result, error) {
+	fs := newFlagSet(name, c.stderr)
+	userID := fs.String("user", "", "user ID owning the links (required)")
+	if err := parseArgs(fs, args, "ID...", 1); err != nil {
+		return nil, err
+	}
+	if *userID == "" {
+		fs.Usage()
+		return nil, errUsage
+	}
+
+	if _, err := c.DeleteUserURLs(ctx, &pb.DeleteUserURLsRequest{UserId: userID, ShortUrlIds: fs.Args()}); err != nil {
+		return nil, err
+	}
+
+	res := &result{columns: []string{"id", "status"}}
+	for _, id := range fs.Args() {
+		res.add(id, "queued")
+	}
+	return res, nil
+}
+
+// stats counts the links and users of the server.
+func stats(ctx context.Context, c *client, name string, args []string) (*result, error) {
+	if err := parseArgs(newFlagSet(name, c.stderr), args, "", 0); err != nil {
+		return nil, err
+	}
+	ctx = c.trustedContext(ctx)
+
+	resp, err := c.Stats(ctx, &pb.StatsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	res := &result{columns: []string{"url_count", "user_count"}}
+	res.add(resp.GetUrlCount(), resp.GetUserCount())
+	return res, nil
+}
+
+// ping checks the storage of the server.
+func ping(ctx context.Context, c *client, name string, args []string) (*result, error) {
+	if err := parseArgs(newFlagSet(name, c.stderr), args, "", 0); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.Ping(ctx, &pb.PingRequest{})
+	if err != nil {
+		return nil, err
+	}
+	res := &result{columns: []string{"status"}}
+	res.add(resp.GetStatus())
+	return res, nil
+}
+
+// purge removes the deleted links for good.
+func purge(ctx context.Context, c *client, name string, args []string) (*result, error) {
+	if err := parseArgs(newFlagSet(name, c.stderr), args, "", 0); err != nil {
+		return nil, err
+	}
+	ctx = c.trustedContext(ctx)
+
+	resp, err := c.PurgeDeleted(ctx, &pb.PurgeDeletedRequest{})
+	if err != nil {
+		return nil, err
+	}
+	res := &result{columns: []string{"purged"}}
+	res.add(resp.GetPurged())
+	return res, nil
+}
+
+// block blocks the domain arguments and lists all blocked domains.
+func block(ctx context.Context, c *client, name string, args []string) (*result, error) {
+	return changeBlocklist(ctx, c, name, args, c.BlockDomain)
+}
+
+// unblock unblocks the domain arguments and lists the domains still blocked.
+func unblock(ctx context.Context, c *client, name string, args []string) (*result, error) {
+	return changeBlocklist(ctx, c, name, args, c.UnblockDomain)
+}
+
+// changeBlocklist calls change for every domain argument and lists the blocked domains after the last call.
+func changeBlocklist(
+	ctx context.Context, c *client, name string, args []string,
+	change func(ctx context.Context, req *pb.BlockDomainRequest, opts ...grpc.CallOption) (*pb.BlockedDomainsResponse, error),
+) (*result, error) {
+	fs := newFlagSet(name, c.stderr)
+	if err := parseArgs(fs, args, "DOMAIN...", 1); err != nil {
+		return nil, err
+	}
+	ctx = c.trustedContext(ctx)
+
+	var (
+		resp *pb.BlockedDomainsResponse
+		err  error
+	)
+	for _, domain := range fs.Args() {
+		if resp, err = change(ctx, &pb.BlockDomainRequest{Domain: &domain}); err != nil {
+			return nil, itemError(domain, err)
+		}
+	}
+	return domainsResult(resp), nil
+}
+
+// blocked lists the blocked domains.
+func blocked(ctx context.Context, c *client, name string, args []string) (*result, error) {
+	if err := parseArgs(newFlagSet(name, c.stderr), args, "", 0); err != nil {
+		return nil, err
+	}
+	ctx = c.trustedContext(ctx)
+
+	resp, err := c.ListBlockedDomains(ctx, &pb.ListBlockedDomainsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return domainsResult(resp), nil
+}
+
+// domainsResult lists the domains of resp.
+func domainsResult(resp *pb.BlockedDomainsResponse) *result {
+	res := &result{columns: []string{"domain"}}
+	for _, d := range resp.GetDomains() {
+		res.add(d)
+	}
+	return res
+}
+
+// owner looks up who created the link ID arguments.
+func owner(ctx context.Context, c *client, name string, args []string) (*result, error) {
+	fs := newFlagSet(name, c.stderr)
+	if err := parseArgs(fs, args, "ID...", 1); err != nil {
+		return nil, err
+	}
+	ctx = c.trustedContext(ctx)
+
+	res := &result{columns: []string{"id", "user_id", "original_url", "created_at"}}
+	for _, id := range fs.Args() {
+		resp, err := c.GetLinkOwner(ctx, &pb.GetLinkOwnerRequest{ShortUrlId: &id})
+		if err != nil {
+			return nil, itemError(id, err)
+		}
+		res.add(resp.GetShortUrlId(), resp.GetUserId(), resp.GetOriginalUrl(), resp.GetCreatedAt())
+	}
+	return res, nil
+}
+
+// adminColumns are the columns of links of the Admin service.
+var adminColumns = []string{"id", "user_id", "original_url", "created_at", "deleted", "disabled", "exhausted"}
+
+// addAdminLink adds a row for a link of the Admin service.
+func (r *result) addAdminLink(l *pb.AdminLink) {
+	r.add(l.GetShortUrlId(), l.GetUserId(), l.GetOriginalUrl(), l.GetCreatedAt(), l.GetDeleted(), l.GetDisabled(), l.GetExhausted())
+}
+
+// adminLink looks up the link ID arguments whatever their state.
+func adminLink(ctx context.Context, c *client, name string, args []string) (*result, error) {
+	return eachLink(ctx, c, name, args, nil, func(ctx context.Context, id string) (*pb.AdminLink, error) {
+		return c.admin.GetLink(ctx, &pb.AdminLinkRequest{ShortUrlId: &id})
+	})
+}
+
+// disableLinks disables the link ID arguments, with the reason given by -reason.
+func disableLinks(ctx context.Context, c *client, name string, args []string) (*result, error) {
+	var reason *string
+	return eachLink(ctx, c, name, args, func(fs *flag.FlagSet) {
+		reason = fs.String("reason", "", "reason recorded in the audit log")
+	}, func(ctx context.Context, id string) (*pb.AdminLink, error) {
+		return c.admin.DisableLink(ctx, &pb.DisableLinkRequest{ShortUrlId: &id, Reason: reason})
+	})
+}
+
+// enableLinks enables the link ID arguments again.
+func enableLinks(ctx context.Context, c *client, name string, args []string) (*result, error) {
+	return eachLink(ctx, c, name, args, nil, func(ctx context.Context, id string) (*pb.AdminLink, error) {
+		return c.admin.EnableLink(ctx, &pb.AdminLinkRequest{ShortUrlId: &id})
+	})
+}
+
+// eachLink calls the Admin service method call for every link ID argument and lists the links it returns.
+// flags, when set, defines the flags of the command.
+func eachLink(
+	ctx context.Context, c *client, name string, args []string, flags func(fs *flag.FlagSet),
+	call func(ctx context.Context, id string) (*pb.AdminLink, error),
+) (*result, error) {
+	fs := newFlagSet(name, c.stderr)
+	if flags != nil {
+		flags(fs)
+	}
+	if err := parseArgs(fs, args, "ID...", 1); err != nil {
+		return nil, err
+	}
+	ctx, err := c.adminContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &result{columns: adminColumns}
+	for _, id := range fs.Args() {
+		l, err := call(ctx, id)
+		if err != nil {
+			return nil, itemError(id, err)
+		}
+		res.addAdminLink(l)
+	}
+	return res, nil
+}
+
+// userLinks lists the links of the user ID arguments whatever their state.
+func userLinks(ctx context.Context, c *client, name string, args []string) (*result, error) {
+	fs := newFlagSet(name, c.stderr)
+	if err := parseArgs(fs, args, "USER_ID...", 1); err != nil {
+		return nil, err
+	}
+	ctx, err := c.adminContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &result{columns: adminColumns}
+	for _, userID := range fs.Args() {
+		resp, err := c.admin.ListUserLinks(ctx, &pb.AdminUserLinksRequest{UserId: &userID})
+		if err != nil {
+			return nil, itemError(userID, err)
+		}
+		for _, l := range resp.GetLinks() {
+			res.addAdminLink(l)
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,209 @@
+// Command shortugoctl manages a shortugo server over its gRPC API.
+//
+// Usage:
+//
+//	shortugoctl [flags] <command> [command flags] [arguments]
+//
+// Commands shorten, expand, list, delete, stats and ping call the URLShortener service like any client;
+// purge, block, unblock, blocked and owner are administration commands. Stats and the administration
+// commands are only answered for clients connecting from the trusted subnet of the server, or sending
+// the admin API key given by -api-key. Commands link, disable, enable and user-links call the Admin
+// service, which always requires the key.
+//
+// The connection uses TLS with -s, trusting the certificate given by -cert, so the certificate the server
+// is started with can be used. -g, -s and -cert default to the GRPC_SERVER_ADDRESS, ENABLE_HTTPS or GRPC_ENABLE_TLS,
+// and GRPC_CERT_FILE or CERT_FILE environment variables, like the flags of the server, and -api-key to ADMIN_API_KEY.
+// A server requiring client certificates is sent the one of -client-cert and -client-key. Results are printed as a table,
+// JSON or CSV, chosen by -o.
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	pb "github.com/apetsko/shortugo/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// errUsage is returned by commands called with wrong arguments; their usage has been printed.
+var errUsage = errors.New("usage")
+
+// dialOptions are added to the options of every connection; tests dial in-memory listeners with them.
+var dialOptions []grpc.DialOption
+
+// options are the global flags.
+type options struct {
+	addr       string
+	apiKey     string
+	certPath   string
+	clientCert string
+	clientKey  string
+	serverName string
+	format     string
+	timeout    time.Duration
+	tls        bool
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command line args, printing results to stdout and errors to stderr, and returns the exit status.
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("shortugoctl", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() { usage(fs) }
+
+	var opts options
+	fs.StringVar(&opts.addr, "g", envOr("GRPC_SERVER_ADDRESS", "localhost:9090"), "gRPC server address with port")
+	fs.BoolVar(&opts.tls, "s", envBool("ENABLE_HTTPS") || envBool("GRPC_ENABLE_TLS"), "connect with TLS")
+	fs.StringVar(&opts.certPath, "cert", envOr("GRPC_CERT_FILE", envOr("CERT_FILE", "certs/cert.crt")), "certificate the server is trusted by, with -s")
+	fs.StringVar(&opts.clientCert, "client-cert", "", "client certificate sent to servers requiring one, with -s")
+	fs.StringVar(&opts.clientKey, "client-key", "", "private key of -client-cert")
+	fs.StringVar(&opts.serverName, "server-name", "", "server name expected in the certificate, with -s; the host of -g by default")
+	fs.StringVar(&opts.apiKey, "api-key", os.Getenv("ADMIN_API_KEY"), "admin API key for the Admin service commands, and for stats and the administration commands outside the trusted subnet")
+	fs.StringVar(&opts.format, "o", formatTable, "output format: table, json or csv")
+	fs.DurationVar(&opts.timeout, "timeout", 10*time.Second, "timeout of a command")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if fs.NArg() == 0 {
+		usage(fs)
+		return 2
+	}
+	cmd, ok := commands[fs.Arg(0)]
+	if !ok {
+		fmt.Fprintf(stderr, "shortugoctl: unknown command %q\n", fs.Arg(0))
+		usage(fs)
+		return 2
+	}
+	if !validFormat(opts.format) {
+		fmt.Fprintf(stderr, "shortugoctl: unknown output format %q\n", opts.format)
+		return 2
+	}
+
+	conn, err := dial(opts)
+	if err != nil {
+		fmt.Fprintln(stderr, "shortugoctl:", err)
+		return 1
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.timeout)
+	defer cancel()
+
+	c := &client{URLShortenerClient: pb.NewURLShortenerClient(conn), admin: pb.NewAdminClient(conn), opts: opts, stderr: stderr}
+	res, err := cmd.run(ctx, c, fs.Arg(0), fs.Args()[1:])
+	if errors.Is(err, errUsage) {
+		return 2
+	}
+	if err != nil {
+		if st, ok := status.FromError(err); ok {
+			fmt.Fprintf(stderr, "shortugoctl: %s: %s\n", st.Code(), st.Message())
+		} else {
+			fmt.Fprintln(stderr, "shortugoctl:", err)
+		}
+		return 1
+	}
+
+	if err := res.write(stdout, opts.format); err != nil {
+		fmt.Fprintln(stderr, "shortugoctl:", err)
+		return 1
+	}
+	return 0
+}
+
+// usage prints the global flags and the commands.
+func usage(fs *flag.FlagSet) {
+	out := fs.Output()
+	fmt.Fprintln(out, "Usage: shortugoctl [flags] <command> [command flags] [arguments]")
+	fmt.Fprintln(out, "\nCommands:")
+	for _, name := range commandNames() {
+		fmt.Fprintf(out, "  %-10s %s\n", name, commands[name].summary)
+	}
+	fmt.Fprintln(out, "\nFlags:")
+	fs.PrintDefaults()
+}
+
+// dial creates the connection to the server, with TLS when opts.tls is set.
+func dial(opts options) (*grpc.ClientConn, error) {
+	creds := insecure.NewCredentials()
+	if opts.tls {
+		pem, err := os.ReadFile(opts.certPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read certificate: %w", err)
+		}
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in %s", opts.certPath)
+		}
+		config := &tls.Config{
+			RootCAs:    roots,
+			ServerName: opts.serverName,
+			MinVersion: tls.VersionTLS12,
+		}
+		if opts.clientCert != "" {
+			cert, err := tls.LoadX509KeyPair(opts.clientCert, opts.clientKey)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load client certificate: %w", err)
+			}
+			config.Certificates = []tls.Certificate{cert}
+		}
+		creds = credentials.NewTLS(config)
+	}
+	return grpc.NewClient(opts.addr, append([]grpc.DialOption{grpc.WithTransportCredentials(creds)}, dialOptions...)...)
+}
+
+// client is the connection to the server with the global flags.
+type client struct {
+	pb.URLShortenerClient
+	admin  pb.AdminClient
+	opts   options
+	stderr io.Writer // Where commands print their usage and flag errors.
+}
+
+// adminContext returns ctx sending the admin API key of -api-key.
+func (c *client) adminContext(ctx context.Context) (context.Context, error) {
+	if c.opts.apiKey == "" {
+		return nil, errors.New("an admin API key is required, use -api-key")
+	}
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+c.opts.apiKey), nil
+}
+
+// trustedContext returns ctx for Stats and the administration commands: it sends the admin API key
+// of -api-key if one is given, otherwise the server trusts the address the client connects from.
+func (c *client) trustedContext(ctx context.Context) context.Context {
+	if c.opts.apiKey == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+c.opts.apiKey)
+}
+
+// envOr returns the environment variable key, or def when it is not set.
+func envOr(key, def string) string {
+	if v, ok := os.LookupEnv(key); ok {
+		return v
+	}
+	return def
+}
+
+// envBool returns the environment variable key as a boolean; unset or invalid values are false.
+func envBool(key string) bool {
+	v, _ := strconv.ParseBool(os.Getenv(key))
+	return v
+}
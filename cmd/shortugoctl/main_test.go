@@ -0,0 +1,277 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	pb "github.com/apetsko/shortugo/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// fakeServer answers the calls of shortugoctl and records how it was called.
+type fakeServer struct {
+	pb.UnimplementedURLShortenerServer
+	pb.UnimplementedAdminServer
+
+	mu       sync.Mutex
+	calls    []string // method and argument of every call, in order
+	auth     []string // authorization metadata of the last call
+	blocked  []string
+	reason   string
+	password string
+}
+
+// record notes a call of method with arg and its authorization metadata.
+func (s *fakeServer) record(ctx context.Context, method, arg string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	s.calls = append(s.calls, method+" "+arg)
+	s.auth = md.Get("authorization")
+}
+
+func (s *fakeServer) ShortenBatch(ctx context.Context, req *pb.ShortenBatchRequest) (*pb.ShortenBatchResponse, error) {
+	s.record(ctx, "ShortenBatch", req.GetUserId())
+	s.password = req.GetUrls()[0].GetOptions().GetPassword()
+
+	resp := &pb.ShortenBatchResponse{}
+	for _, u := range req.GetUrls() {
+		id, orig := u.GetCorrelationId(), u.GetOriginalUrl()
+		short := "http://short/" + id
+		if strings.Contains(orig, "blocked") {
+			// Refused items carry the reason instead of the short URL
+			orig, short = "", "domain is blocked"
+		}
+		resp.Results = append(resp.Results, &pb.URLPair{CorrelationId: &id, OriginalUrl: &orig, ShortUrl: &short})
+	}
+	return resp, nil
+}
+
+func (s *fakeServer) Expand(ctx context.Context, req *pb.ExpandRequest) (*pb.ExpandResponse, error) {
+	s.record(ctx, "Expand", req.GetShortUrlId())
+	if req.GetShortUrlId() == "missing" {
+		return nil, status.Error(codes.NotFound, "link not found")
+	}
+	orig := "https://example.com/" + req.GetShortUrlId()
+	return &pb.ExpandResponse{OriginalUrl: &orig}, nil
+}
+
+func (s *fakeServer) StreamUserURLs(req *pb.ListUserURLsRequest, stream grpc.ServerStreamingServer[pb.URLPair]) error {
+	s.record(stream.Context(), "StreamUserURLs", req.GetUserId())
+	if req.GetUserId() == "nobody" {
+		return status.Error(codes.NotFound, "no links")
+	}
+	short, orig, title, folder := "http://short/a", "https://a.com", "A", "work"
+	return stream.Send(&pb.URLPair{ShortUrl: &short, OriginalUrl: &orig, Title: &title, Folder: &folder, Tags: []string{"x", "y"}})
+}
+
+func (s *fakeServer) Stats(ctx context.Context, _ *pb.StatsRequest) (*pb.StatsResponse, error) {
+	s.record(ctx, "Stats", "")
+	urls, users := int64(5), int64(2)
+	return &pb.StatsResponse{UrlCount: &urls, UserCount: &users}, nil
+}
+
+func (s *fakeServer) BlockDomain(ctx context.Context, req *pb.BlockDomainRequest) (*pb.BlockedDomainsResponse, error) {
+	s.record(ctx, "BlockDomain", req.GetDomain())
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.blocked = append(s.blocked, req.GetDomain())
+	return &pb.BlockedDomainsResponse{Domains: s.blocked}, nil
+}
+
+func (s *fakeServer) DisableLink(ctx context.Context, req *pb.DisableLinkRequest) (*pb.AdminLink, error) {
+	s.record(ctx, "DisableLink", req.GetShortUrlId())
+	s.reason = req.GetReason()
+
+	id, user, disabled := req.GetShortUrlId(), "u1", true
+	return &pb.AdminLink{ShortUrlId: &id, UserId: &user, Disabled: &disabled}, nil
+}
+
+// startFakeServer serves a fakeServer on an in-memory listener that run dials.
+func startFakeServer(t *testing.T) *fakeServer {
+	t.Helper()
+
+	// The flags default to these variables; the tests give the flags they need
+	for _, key := range []string{"ADMIN_API_KEY", "ENABLE_HTTPS", "GRPC_ENABLE_TLS"} {
+		t.Setenv(key, "")
+	}
+
+	fake := &fakeServer{}
+	lis := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer()
+	pb.RegisterURLShortenerServer(srv, fake)
+	pb.RegisterAdminServer(srv, fake)
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+	t.Cleanup(srv.Stop)
+
+	dialOptions = []grpc.DialOption{
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+	}
+	t.Cleanup(func() { dialOptions = nil })
+	return fake
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantOut    string
+		wantErr    string
+		wantCalls  []string
+		wantAuth   []string
+		wantReason string
+	}{
+		{
+			name:     "no command",
+			args:     nil,
+			wantCode: 2,
+			wantErr:  "Usage: shortugoctl",
+		},
+		{
+			name:     "unknown command",
+			args:     []string{"frobnicate"},
+			wantCode: 2,
+			wantErr:  `unknown command "frobnicate"`,
+		},
+		{
+			name:     "unknown output format",
+			args:     []string{"-o", "yaml", "ping"},
+			wantCode: 2,
+			wantErr:  `unknown output format "yaml"`,
+		},
+		{
+			name:     "shorten without user",
+			args:     []string{"shorten", "https://a.com"},
+			wantCode: 2,
+			wantErr:  "Usage: shortugoctl shorten [flags] URL...",
+		},
+		{
+			name:     "bad command flag",
+			args:     []string{"expand", "-bogus", "a"},
+			wantCode: 2,
+			wantErr:  "flag provided but not defined: -bogus\nUsage: shortugoctl expand [flags] ID...",
+		},
+		{
+			name:     "bad flag value",
+			args:     []string{"shorten", "-user", "u1", "-max-clicks", "many", "https://a.com"},
+			wantCode: 2,
+			wantErr:  `invalid value "many" for flag -max-clicks`,
+		},
+		{
+			name: "shorten",
+			args: []string{"-o", "csv", "shorten", "-user", "u1", "https://a.com", "https://blocked.com"},
+			wantOut: "original_url,short_url,error\n" +
+				"https://a.com,http://short/0,\n" +
+				"https://blocked.com,,domain is blocked\n",
+			wantCalls: []string{"ShortenBatch u1"},
+		},
+		{
+			name:      "expand",
+			args:      []string{"-o", "csv", "expand", "a", "b"},
+			wantOut:   "id,original_url\na,https://example.com/a\nb,https://example.com/b\n",
+			wantCalls: []string{"Expand a", "Expand b"},
+		},
+		{
+			name:      "expand stops at the first error",
+			args:      []string{"expand", "missing", "b"},
+			wantCode:  1,
+			wantErr:   "shortugoctl: missing: NotFound: link not found",
+			wantCalls: []string{"Expand missing"},
+		},
+		{
+			name:      "list",
+			args:      []string{"-o", "json", "list", "-user", "u1"},
+			wantOut:   `[{"short_url": "http://short/a", "original_url": "https://a.com", "title": "A", "folder": "work", "tags": ["x", "y"]}]`,
+			wantCalls: []string{"StreamUserURLs u1"},
+		},
+		{
+			name:      "list of a user without links",
+			args:      []string{"-o", "json", "list", "-user", "nobody"},
+			wantOut:   `[]`,
+			wantCalls: []string{"StreamUserURLs nobody"},
+		},
+		{
+			name:      "stats trusts the connection without a key",
+			args:      []string{"-api-key", "", "stats"},
+			wantOut:   "URL_COUNT  USER_COUNT\n5          2\n",
+			wantCalls: []string{"Stats "},
+		},
+		{
+			name:      "stats sends the key",
+			args:      []string{"-api-key", "k1", "stats"},
+			wantOut:   "URL_COUNT  USER_COUNT\n5          2\n",
+			wantCalls: []string{"Stats "},
+			wantAuth:  []string{"Bearer k1"},
+		},
+		{
+			name:      "block lists the domains after the last call",
+			args:      []string{"-o", "csv", "block", "a.com", "b.com"},
+			wantOut:   "domain\na.com\nb.com\n",
+			wantCalls: []string{"BlockDomain a.com", "BlockDomain b.com"},
+		},
+		{
+			name:     "admin command without key",
+			args:     []string{"-api-key", "", "disable", "a"},
+			wantCode: 1,
+			wantErr:  "an admin API key is required",
+		},
+		{
+			name:       "disable",
+			args:       []string{"-api-key", "k1", "-o", "csv", "disable", "-reason", "phishing", "a"},
+			wantOut:    "id,user_id,original_url,created_at,deleted,disabled,exhausted\na,u1,,,false,true,false\n",
+			wantCalls:  []string{"DisableLink a"},
+			wantAuth:   []string{"Bearer k1"},
+			wantReason: "phishing",
+		},
+		{
+			name:     "unimplemented method",
+			args:     []string{"ping"},
+			wantCode: 1,
+			wantErr:  "shortugoctl: Unimplemented",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := startFakeServer(t)
+
+			var stdout, stderr bytes.Buffer
+			code := run(append([]string{"-g", "passthrough:///bufnet"}, tt.args...), &stdout, &stderr)
+
+			require.Equal(t, tt.wantCode, code, stderr.String())
+			if strings.HasPrefix(tt.wantOut, "[") {
+				assert.JSONEq(t, tt.wantOut, stdout.String())
+			} else {
+				assert.Equal(t, tt.wantOut, stdout.String())
+			}
+			assert.Contains(t, stderr.String(), tt.wantErr)
+			assert.Equal(t, tt.wantCalls, fake.calls)
+			assert.Equal(t, tt.wantAuth, fake.auth)
+			assert.Equal(t, tt.wantReason, fake.reason)
+		})
+	}
+}
+
+func TestRun_ShortenPassword(t *testing.T) {
+	fake := startFakeServer(t)
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"-g", "passthrough:///bufnet", "shorten", "-user", "u1", "-password", "secret", "https://a.com"}, &stdout, &stderr)
+	require.Equal(t, 0, code, stderr.String())
+	assert.Equal(t, "secret", fake.password)
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// Output formats chosen by -o.
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatCSV   = "csv"
+)
+
+// validFormat reports whether format is an output format.
+func validFormat(format string) bool {
+	return format == formatTable || format == formatJSON || format == formatCSV
+}
+
+// result is the output of a command: rows of values under named columns.
+type result struct {
+	columns []string
+	rows    [][]any
+}
+
+// add appends a row with one value per column.
+func (r *result) add(values ...any) {
+	r.rows = append(r.rows, values)
+}
+
+// write prints the result to w in format.
+func (r *result) write(w io.Writer, format string) error {
+	switch format {
+	case formatJSON:
+		return r.writeJSON(w)
+	case formatCSV:
+		return r.writeCSV(w)
+	default:
+		return r.writeTable(w)
+	}
+}
+
+// writeTable prints the result as aligned columns under an upper-case header.
+func (r *result) writeTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.ToUpper(strings.Join(r.columns, "\t")))
+	for _, row := range r.rows {
+		fmt.Fprintln(tw, strings.Join(r.text(row), "\t"))
+	}
+	return tw.Flush()
+}
+
+// writeCSV prints the result as CSV with a header row.
+func (r *result) writeCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(r.columns); err != nil {
+		return err
+	}
+	for _, row := range r.rows {
+		if err := cw.Write(r.text(row)); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeJSON prints the result as a JSON array of objects keyed by column, keeping numbers and lists.
+func (r *result) writeJSON(w io.Writer) error {
+	objects := make([]map[string]any, 0, len(r.rows))
+	for _, row := range r.rows {
+		obj := make(map[string]any, len(r.columns))
+		for i, col := range r.columns {
+			obj[col] = row[i]
+		}
+		objects = append(objects, obj)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(objects)
+}
+
+// text returns the values of row as text; lists are joined with |, like tags in the CSV export.
+func (r *result) text(row []any) []string {
+	text := make([]string, len(row))
+	for i, v := range row {
+		if list, ok := v.([]string); ok {
+			text[i] = strings.Join(list, "|")
+		} else {
+			text[i] = fmt.Sprint(v)
+		}
+	}
+	return text
+}
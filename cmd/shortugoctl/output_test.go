@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResult_Write(t *testing.T) {
+	links := &result{columns: []string{"id", "tags", "clicks"}}
+	links.add("a", []string{"x", "y"}, 3)
+	links.add("long-id", []string{}, 10)
+
+	empty := &result{columns: []string{"domain"}}
+
+	tests := []struct {
+		name   string
+		res    *result
+		format string
+		want   string
+		isJSON bool
+	}{
+		{
+			name:   "table",
+			res:    links,
+			format: formatTable,
+			want: "ID       TAGS  CLICKS\n" +
+				"a        x|y   3\n" +
+				"long-id        10\n",
+		},
+		{
+			name:   "unknown format is a table",
+			res:    empty,
+			format: "yaml",
+			want:   "DOMAIN\n",
+		},
+		{
+			name:   "csv",
+			res:    links,
+			format: formatCSV,
+			want:   "id,tags,clicks\na,x|y,3\nlong-id,,10\n",
+		},
+		{
+			name:   "csv without rows",
+			res:    empty,
+			format: formatCSV,
+			want:   "domain\n",
+		},
+		{
+			name:   "json keeps numbers and lists",
+			res:    links,
+			format: formatJSON,
+			want:   `[{"id": "a", "tags": ["x", "y"], "clicks": 3}, {"id": "long-id", "tags": [], "clicks": 10}]`,
+			isJSON: true,
+		},
+		{
+			name:   "json without rows",
+			res:    empty,
+			format: formatJSON,
+			want:   `[]`,
+			isJSON: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, tt.res.write(&buf, tt.format))
+			if tt.isJSON {
+				assert.JSONEq(t, tt.want, buf.String())
+			} else {
+				assert.Equal(t, tt.want, buf.String())
+			}
+		})
+	}
+}
+
+func TestValidFormat(t *testing.T) {
+	for _, format := range []string{formatTable, formatJSON, formatCSV} {
+		assert.True(t, validFormat(format), format)
+	}
+	assert.False(t, validFormat("yaml"))
+	assert.False(t, validFormat(""))
+}
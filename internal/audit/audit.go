@@ -0,0 +1,134 @@
+// Package audit records who changed what: the mutations of users and the actions of admins.
+// Entries are appended to a JSONL file or a database table and can be queried by admins.
+package audit
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/apetsko/shortugo/internal/logging"
+)
+
+// Actions of users.
+const (
+	ActionCreateLinks = "link.create"
+	ActionUpdateLink  = "link.update"
+	ActionDeleteLinks = "link.delete"
+	ActionUpdateUTM   = "utm.update"
+)
+
+// Actions of admins.
+const (
+	ActionLookupLink    = "link.lookup"
+	ActionDisableLink   = "link.disable"
+	ActionEnableLink    = "link.enable"
+	ActionListUserLinks = "user.links"
+	ActionPurgeDeleted  = "links.purge"
+	ActionBlockDomain   = "domain.block"
+	ActionUnblockDomain = "domain.unblock"
+	ActionListBlocked   = "domain.list"
+	ActionQueryAudit    = "audit.query"
+)
+
+// Protocols an action can come through.
+const (
+	ProtocolHTTP = "http"
+	ProtocolGRPC = "grpc"
+)
+
+// Entry is one audited action.
+type Entry struct {
+	Time     time.Time         `json:"time"`               // When the action was taken; set by Record when zero.
+	Actor    string            `json:"actor"`              // User ID, "key:<hash prefix>" for admin API keys or "ip:<address>" for trusted subnet clients.
+	Remote   string            `json:"remote,omitempty"`   // IP address of the client the action came from.
+	Protocol string            `json:"protocol,omitempty"` // ProtocolHTTP or ProtocolGRPC.
+	Action   string            `json:"action"`             // One of the Action constants.
+	Targets  []string          `json:"targets,omitempty"`  // IDs of the links, users or domains acted on.
+	Details  map[string]string `json:"details,omitempty"`  // Extra facts such as the changed field or the reason of a disable.
+}
+
+// Recorder stores audit entries.
+type Recorder interface {
+	// Record appends e.
+	Record(ctx context.Context, e Entry) error
+}
+
+// Querier reads stored audit entries.
+type Querier interface {
+	// Query returns the entries matching f, newest first, at most f.Limit.
+	Query(ctx context.Context, f Filter) ([]Entry, error)
+}
+
+// DefaultLimit and MaxLimit bound the number of entries a query returns.
+const (
+	DefaultLimit = 100
+	MaxLimit     = 1000
+)
+
+// Filter selects audit entries. Empty fields match every entry.
+type Filter struct {
+	Since    time.Time // Entries at or after Since.
+	Until    time.Time // Entries before Until.
+	Actor    string    // Entries of this actor.
+	Action   string    // Entries of this action.
+	Target   string    // Entries acting on this ID among others.
+	Protocol string    // Entries coming through this protocol.
+	Limit    int       // Maximum number of entries; DefaultLimit when zero, at most MaxLimit.
+}
+
+// Matches reports whether e passes the filter; the limit is not checked.
+func (f Filter) Matches(e Entry) bool {
+	return (f.Since.IsZero() || !e.Time.Before(f.Since)) &&
+		(f.Until.IsZero() || e.Time.Before(f.Until)) &&
+		(f.Actor == "" || e.Actor == f.Actor) &&
+		(f.Action == "" || e.Action == f.Action) &&
+		(f.Target == "" || slices.Contains(e.Targets, f.Target)) &&
+		(f.Protocol == "" || e.Protocol == f.Protocol)
+}
+
+// Size returns the number of entries a query of f returns at most.
+func (f Filter) Size() int {
+	switch {
+	case f.Limit <= 0:
+		return DefaultLimit
+	case f.Limit > MaxLimit:
+		return MaxLimit
+	default:
+		return f.Limit
+	}
+}
+
+// Log is a Recorder writing entries to the application log. It cannot be queried;
+// it is used when neither an audit file nor a database is configured.
+type Log struct {
+	logger *logging.Logger
+}
+
+// NewLog returns a Recorder writing entries to logger.
+func NewLog(logger *logging.Logger) *Log {
+	return &Log{logger: logger}
+}
+
+// Record writes e to the log at info level.
+func (l *Log) Record(_ context.Context, e Entry) error {
+	e = stamp(e)
+	l.logger.Info("audit",
+		"time", e.Time.Format(time.RFC3339),
+		"actor", e.Actor,
+		"remote", e.Remote,
+		"protocol", e.Protocol,
+		"action", e.Action,
+		"targets", e.Targets,
+		"details", e.Details,
+	)
+	return nil
+}
+
+// stamp sets the time of e to now when it is zero.
+func stamp(e Entry) Entry {
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	return e
+}
@@ -0,0 +1,97 @@
+package audit
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter_Matches(t *testing.T) {
+	at := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	e := Entry{Time: at, Actor: "user1", Protocol: ProtocolHTTP, Action: ActionDeleteLinks, Targets: []string{"abc", "def"}}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   bool
+	}{
+		{name: "empty", filter: Filter{}, want: true},
+		{name: "actor", filter: Filter{Actor: "user1"}, want: true},
+		{name: "other actor", filter: Filter{Actor: "user2"}, want: false},
+		{name: "action", filter: Filter{Action: ActionDeleteLinks}, want: true},
+		{name: "other action", filter: Filter{Action: ActionCreateLinks}, want: false},
+		{name: "one of the targets", filter: Filter{Target: "def"}, want: true},
+		{name: "other target", filter: Filter{Target: "ghi"}, want: false},
+		{name: "protocol", filter: Filter{Protocol: ProtocolGRPC}, want: false},
+		{name: "since is included", filter: Filter{Since: at}, want: true},
+		{name: "until is excluded", filter: Filter{Until: at}, want: false},
+		{name: "window", filter: Filter{Since: at.Add(-time.Hour), Until: at.Add(time.Hour)}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.filter.Matches(e))
+		})
+	}
+}
+
+func TestFilter_Size(t *testing.T) {
+	assert.Equal(t, DefaultLimit, Filter{}.Size())
+	assert.Equal(t, DefaultLimit, Filter{Limit: -1}.Size())
+	assert.Equal(t, 5, Filter{Limit: 5}.Size())
+	assert.Equal(t, MaxLimit, Filter{Limit: MaxLimit + 1}.Size())
+}
+
+func TestFile(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	f, err := OpenFile(path)
+	require.NoError(t, err)
+	for i := range 5 {
+		action := ActionCreateLinks
+		if i%2 == 1 {
+			action = ActionDeleteLinks
+		}
+		require.NoError(t, f.Record(ctx, Entry{Actor: "user1", Action: action, Targets: []string{"id" + strconv.Itoa(i)}}))
+	}
+	require.NoError(t, f.Close())
+
+	// Entries survive a restart and new ones are appended
+	f, err = OpenFile(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = f.Close() })
+	require.NoError(t, f.Record(ctx, Entry{Actor: "key:0123abcd", Action: ActionDisableLink, Targets: []string{"id0"}}))
+
+	entries, err := f.Query(ctx, Filter{})
+	require.NoError(t, err)
+	require.Len(t, entries, 6)
+	assert.Equal(t, ActionDisableLink, entries[0].Action)
+	assert.False(t, entries[0].Time.IsZero())
+	assert.Equal(t, []string{"id0"}, entries[5].Targets)
+
+	// The newest matches are kept
+	entries, err = f.Query(ctx, Filter{Action: ActionCreateLinks, Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, []string{"id4"}, entries[0].Targets)
+	assert.Equal(t, []string{"id2"}, entries[1].Targets)
+
+	entries, err = f.Query(ctx, Filter{Target: "id0"})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "key:0123abcd", entries[0].Actor)
+	assert.Equal(t, "user1", entries[1].Actor)
+
+	// One JSON line per entry
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 6, strings.Count(string(data), "\n"))
+}
@@ -0,0 +1,106 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+)
+
+// File is an append-only JSONL audit log: one entry per line, never rewritten.
+type File struct {
+	file *os.File
+	mu   sync.Mutex
+}
+
+// OpenFile opens the audit log at path for appending, creating it when missing.
+// The file is only readable by its owner, as entries hold client addresses.
+func OpenFile(path string) (*File, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	return &File{file: f}, nil
+}
+
+// Record appends e as a line of the file and syncs it to disk.
+func (f *File) Record(ctx context.Context, e Entry) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	line, err := json.Marshal(stamp(e))
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit entry: %w", err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, err := f.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+	return f.file.Sync()
+}
+
+// Query scans the file for the entries matching filter and returns the newest first.
+func (f *File) Query(ctx context.Context, filter Filter) ([]Entry, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// Reads go through a separate descriptor, so the offset of appends is left alone
+	r, err := os.Open(f.file.Name())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer func() {
+		_ = r.Close()
+	}()
+
+	// Keep the last matches only, in a ring; entries are in time order
+	size := filter.Size()
+	ring := make([]Entry, 0, size)
+	matched := 0
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal audit entry: %w", err)
+		}
+		if !filter.Matches(e) {
+			continue
+		}
+		if len(ring) < size {
+			ring = append(ring, e)
+		} else {
+			ring[matched%size] = e
+		}
+		matched++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	start := 0
+	if matched > size {
+		start = matched % size
+	}
+	entries := slices.Concat(ring[start:], ring[:start])
+	slices.Reverse(entries)
+	return entries, nil
+}
+
+// Close closes the file.
+func (f *File) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.file.Close()
+}
@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+)
+
+// Admins knows the users and API keys allowed to use the admin API.
+// A nil Admins allows nobody.
+type Admins struct {
+	users map[string]struct{}
+	keys  [][sha256.Size]byte
+}
+
+// NewAdmins returns the admins made of the user IDs and API keys. Empty values are ignored.
+func NewAdmins(userIDs, apiKeys []string) *Admins {
+	a := &Admins{users: make(map[string]struct{}, len(userIDs))}
+	for _, id := range userIDs {
+		if id = strings.TrimSpace(id); id != "" {
+			a.users[id] = struct{}{}
+		}
+	}
+	for _, key := range apiKeys {
+		if key = strings.TrimSpace(key); key != "" {
+			a.keys = append(a.keys, sha256.Sum256([]byte(key)))
+		}
+	}
+	return a
+}
+
+// User reports whether the user ID belongs to an admin.
+func (a *Admins) User(userID string) bool {
+	if a == nil || userID == "" {
+		return false
+	}
+	_, ok := a.users[userID]
+	return ok
+}
+
+// Key reports whether key is an admin API key and returns the actor audit entries name it by:
+// "key:" followed by the start of the key hash, so the key itself never shows up in logs.
+// Keys are compared in constant time.
+func (a *Admins) Key(key string) (actor string, ok bool) {
+	if a == nil || key == "" {
+		return "", false
+	}
+
+	sum := sha256.Sum256([]byte(key))
+	for _, k := range a.keys {
+		if subtle.ConstantTimeCompare(sum[:], k[:]) == 1 {
+			ok = true
+		}
+	}
+	if !ok {
+		return "", false
+	}
+	return "key:" + hex.EncodeToString(sum[:4]), true
+}
+
+// BearerToken returns the token of an "Authorization: Bearer <token>" header value, or "" for other values.
+func BearerToken(header string) string {
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
@@ -0,0 +1,55 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdmins(t *testing.T) {
+	admins := NewAdmins([]string{"admin1", " admin2 ", ""}, []string{"key-one", "", "key-two"})
+
+	assert.True(t, admins.User("admin1"))
+	assert.True(t, admins.User("admin2"))
+	assert.False(t, admins.User("user1"))
+	assert.False(t, admins.User(""))
+
+	actor, ok := admins.Key("key-one")
+	assert.True(t, ok)
+	assert.True(t, strings.HasPrefix(actor, "key:"))
+	assert.Len(t, actor, len("key:")+8)
+
+	other, ok := admins.Key("key-two")
+	assert.True(t, ok)
+	assert.NotEqual(t, actor, other)
+
+	_, ok = admins.Key("key-three")
+	assert.False(t, ok)
+	_, ok = admins.Key("")
+	assert.False(t, ok)
+
+	var none *Admins
+	assert.False(t, none.User("admin1"))
+	_, ok = none.Key("key-one")
+	assert.False(t, ok)
+}
+
+func TestBearerToken(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected string
+	}{
+		{header: "Bearer abc", expected: "abc"},
+		{header: "bearer  abc ", expected: "abc"},
+		{header: "Basic abc", expected: ""},
+		{header: "Bearer", expected: ""},
+		{header: "", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			assert.Equal(t, tt.expected, BearerToken(tt.header))
+		})
+	}
+}
@@ -3,6 +3,7 @@ package auth
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/apetsko/shortugo/internal/utils"
@@ -163,3 +164,46 @@ func TestAuth_CookieGetUserID_EmptyDecodedValue(t *testing.T) {
 	_, err = auth.CookieGetUserID(r, secret)
 	assert.ErrorIs(t, err, errNoUserIDFound)
 }
+
+func TestHashPassword(t *testing.T) {
+	hash, err := HashPassword("s3cret")
+	require.NoError(t, err)
+	assert.NotEqual(t, "s3cret", hash)
+
+	assert.True(t, CheckPassword(hash, "s3cret"))
+	assert.False(t, CheckPassword(hash, "S3cret"))
+	assert.False(t, CheckPassword("not-a-hash", "s3cret"))
+
+	_, err = HashPassword(strings.Repeat("a", MaxPasswordLength+1))
+	assert.Error(t, err)
+}
+
+func TestVariantCookie(t *testing.T) {
+	secret := "test_secret"
+
+	w := httptest.NewRecorder()
+	require.NoError(t, SetVariantCookie(w, secret, "abc123", "blue"))
+
+	resp := w.Result()
+	require.NoError(t, resp.Body.Close())
+	cookies := resp.Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, "/abc123", cookies[0].Path)
+	assert.True(t, cookies[0].HttpOnly)
+
+	r := httptest.NewRequest(http.MethodGet, "/abc123", nil)
+	r.AddCookie(cookies[0])
+
+	variant, ok := VariantCookie(r, secret, "abc123")
+	assert.True(t, ok)
+	assert.Equal(t, "blue", variant)
+
+	// The cookie of one link is not accepted for another, nor with another secret
+	_, ok = VariantCookie(r, secret, "other")
+	assert.False(t, ok)
+	_, ok = VariantCookie(r, "other_secret", "abc123")
+	assert.False(t, ok)
+
+	_, ok = VariantCookie(httptest.NewRequest(http.MethodGet, "/abc123", nil), secret, "abc123")
+	assert.False(t, ok)
+}
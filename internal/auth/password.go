@@ -0,0 +1,22 @@
+package auth
+
+import (
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MaxPasswordLength is the longest link password accepted; bcrypt ignores anything past 72 bytes.
+const MaxPasswordLength = 72
+
+// HashPassword returns the bcrypt hash of a link password.
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// CheckPassword reports whether password matches the bcrypt hash.
+func CheckPassword(hash, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
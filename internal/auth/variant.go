@@ -0,0 +1,46 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// variantCookieName is the cookie that remembers the A/B variant a visitor was sent to.
+// Every link has its own cookie, scoped to the path of the short link.
+const variantCookieName = "shortugo_ab"
+
+// variantCookieMaxAge keeps a visitor on the same variant for 30 days.
+const variantCookieMaxAge = 30 * 24 * 60 * 60
+
+// VariantCookie returns the A/B variant remembered for the link, if the request carries a valid cookie for it.
+func VariantCookie(r *http.Request, secret, linkID string) (string, bool) {
+	cookie, err := r.Cookie(variantCookieName)
+	if err != nil {
+		return "", false
+	}
+
+	// The link ID is part of the encoded name, so a cookie of one link is not accepted for another
+	var variant string
+	if err = securedCookie(secret).Decode(variantCookieName+"/"+linkID, cookie.Value, &variant); err != nil || variant == "" {
+		return "", false
+	}
+	return variant, true
+}
+
+// SetVariantCookie remembers the A/B variant the visitor was sent to for the link.
+func SetVariantCookie(w http.ResponseWriter, secret, linkID, variant string) error {
+	encoded, err := securedCookie(secret).Encode(variantCookieName+"/"+linkID, variant)
+	if err != nil {
+		return fmt.Errorf("error encoding variant cookie: %w", err)
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     variantCookieName,
+		Value:    encoded,
+		HttpOnly: true,
+		Path:     "/" + linkID,
+		MaxAge:   variantCookieMaxAge,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return nil
+}
@@ -0,0 +1,249 @@
+// Package blocklist keeps the domains short links may not lead to, e.g. phishing or malware hosts.
+// Blocking a domain also blocks its subdomains. The list may be kept in a file with one domain per line,
+// which is rewritten whenever the list changes.
+package blocklist
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// ErrInvalidDomain is returned for domains that are not host names or IP addresses.
+var ErrInvalidDomain = errors.New("invalid domain")
+
+// List is a set of blocked domains. A nil List blocks nothing.
+type List struct {
+	domains map[string]bool
+	path    string // File the list is saved to; empty keeps it in memory only.
+	mu      sync.RWMutex
+}
+
+// New creates an empty List kept in memory only.
+func New() *List {
+	return &List{domains: make(map[string]bool)}
+}
+
+// Load reads the list saved at path; a missing file is an empty list. Empty lines and lines starting
+// with # are skipped. Later changes of the list are saved to path.
+func Load(path string) (*List, error) {
+	domains, err := read(path)
+	if err != nil {
+		return nil, err
+	}
+	return &List{domains: domains, path: path}, nil
+}
+
+// Reload replaces the domains of l with the ones saved in its file, e.g. after the file was edited,
+// while l is in use. The list is left as it was when the file is invalid; a list without a file is unchanged.
+func (l *List) Reload() error {
+	if l == nil || l.path == "" {
+		return nil
+	}
+
+	domains, err := read(l.path)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.domains = domains
+	return nil
+}
+
+// read returns the domains of the list saved at path.
+func read(path string) (map[string]bool, error) {
+	domains := make(map[string]bool)
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return domains, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open blocklist: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	scanner := bufio.NewScanner(file)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		domain, err := Normalize(line)
+		if err != nil {
+			return nil, fmt.Errorf("blocklist line %d: %w", n, err)
+		}
+		domains[domain] = true
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read blocklist: %w", err)
+	}
+	return domains, nil
+}
+
+// Normalize returns domain in the form the list keeps it: lower case, without a trailing dot
+// and without a leading *. wildcard, which is implied.
+func Normalize(domain string) (string, error) {
+	d := strings.ToLower(strings.TrimSpace(domain))
+	d = strings.TrimSuffix(strings.TrimPrefix(d, "*."), ".")
+	if d == "" {
+		return "", ErrInvalidDomain
+	}
+	if net.ParseIP(d) != nil {
+		return d, nil
+	}
+
+	for _, label := range strings.Split(d, ".") {
+		if label == "" || len(label) > 63 || strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return "", fmt.Errorf("%w: %q", ErrInvalidDomain, domain)
+		}
+		for _, c := range label {
+			if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' {
+				return "", fmt.Errorf("%w: %q", ErrInvalidDomain, domain)
+			}
+		}
+	}
+	return d, nil
+}
+
+// Block adds domain to the list and saves the list. Blocking a blocked domain changes nothing.
+func (l *List) Block(domain string) error {
+	d, err := Normalize(domain)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.domains[d] {
+		return nil
+	}
+	l.domains[d] = true
+	if err := l.save(); err != nil {
+		delete(l.domains, d)
+		return err
+	}
+	return nil
+}
+
+// Unblock removes domain from the list and saves the list. Subdomains blocked on their own stay blocked.
+func (l *List) Unblock(domain string) error {
+	d, err := Normalize(domain)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.domains[d] {
+		return nil
+	}
+	delete(l.domains, d)
+	if err := l.save(); err != nil {
+		l.domains[d] = true
+		return err
+	}
+	return nil
+}
+
+// Domains returns the blocked domains in alphabetical order.
+func (l *List) Domains() []string {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	domains := make([]string, 0, len(l.domains))
+	for d := range l.domains {
+		domains = append(domains, d)
+	}
+	slices.Sort(domains)
+	return domains
+}
+
+// Blocked reports whether host or one of its parent domains is blocked.
+func (l *List) Blocked(host string) bool {
+	if l == nil {
+		return false
+	}
+
+	h := strings.TrimSuffix(strings.ToLower(host), ".")
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if len(l.domains) == 0 {
+		return false
+	}
+	if net.ParseIP(h) != nil {
+		return l.domains[h]
+	}
+	for {
+		if l.domains[h] {
+			return true
+		}
+		_, parent, ok := strings.Cut(h, ".")
+		if !ok {
+			return false
+		}
+		h = parent
+	}
+}
+
+// BlockedURL reports whether rawURL leads to a blocked domain. URLs that do not parse are not blocked.
+func (l *List) BlockedURL(rawURL string) bool {
+	if l == nil {
+		return false
+	}
+
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return false
+	}
+	return l.Blocked(u.Hostname())
+}
+
+// save writes the list to its file, if it has one. The file is written next to the list and renamed,
+// so an interrupted save keeps the previous list. The caller must hold l.mu.
+func (l *List) save() error {
+	if l.path == "" {
+		return nil
+	}
+
+	domains := make([]string, 0, len(l.domains))
+	for d := range l.domains {
+		domains = append(domains, d)
+	}
+	slices.Sort(domains)
+
+	var b strings.Builder
+	for _, d := range domains {
+		b.WriteString(d)
+		b.WriteByte('\n')
+	}
+
+	tmp := l.path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(b.String()), 0o644); err != nil {
+		return fmt.Errorf("write blocklist: %w", err)
+	}
+	if err := os.Rename(tmp, l.path); err != nil {
+		return fmt.Errorf("save blocklist: %w", err)
+	}
+	return nil
+}
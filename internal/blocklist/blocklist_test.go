@@ -0,0 +1,118 @@
+package blocklist
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		domain  string
+		want    string
+		wantErr bool
+	}{
+		{domain: "Example.COM", want: "example.com"},
+		{domain: " *.example.com. ", want: "example.com"},
+		{domain: "localhost", want: "localhost"},
+		{domain: "10.0.0.1", want: "10.0.0.1"},
+		{domain: "xn--e1afmkfd.xn--p1ai", want: "xn--e1afmkfd.xn--p1ai"},
+		{domain: "", wantErr: true},
+		{domain: "https://example.com", wantErr: true},
+		{domain: "example..com", wantErr: true},
+		{domain: "-example.com", wantErr: true},
+		{domain: "exa mple.com", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			got, err := Normalize(tt.domain)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidDomain)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestList_Blocked(t *testing.T) {
+	l := New()
+	require.NoError(t, l.Block("evil.com"))
+	require.NoError(t, l.Block("192.0.2.1"))
+
+	assert.True(t, l.Blocked("evil.com"))
+	assert.True(t, l.Blocked("WWW.Evil.com."))
+	assert.True(t, l.Blocked("192.0.2.1"))
+	assert.False(t, l.Blocked("notevil.com"))
+	assert.False(t, l.Blocked("evil.com.example.org"))
+
+	assert.True(t, l.BlockedURL("https://login.evil.com:8443/account?next=/"))
+	assert.True(t, l.BlockedURL("http://192.0.2.1/x"))
+	assert.False(t, l.BlockedURL("https://example.com/evil.com"))
+
+	require.NoError(t, l.Unblock("EVIL.com"))
+	assert.False(t, l.Blocked("www.evil.com"))
+	assert.Equal(t, []string{"192.0.2.1"}, l.Domains())
+
+	assert.ErrorIs(t, l.Block("not a domain"), ErrInvalidDomain)
+
+	var none *List
+	assert.False(t, none.Blocked("evil.com"))
+	assert.False(t, none.BlockedURL("https://evil.com"))
+	assert.Empty(t, none.Domains())
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blocklist.txt")
+
+	// A missing file is an empty list
+	l, err := Load(path)
+	require.NoError(t, err)
+	assert.Empty(t, l.Domains())
+
+	require.NoError(t, l.Block("b.example"))
+	require.NoError(t, l.Block("a.example"))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "a.example\nb.example\n", string(data))
+
+	require.NoError(t, os.WriteFile(path, []byte("# phishing\n\nEvil.com\n*.malware.example\n"), 0o600))
+	l, err = Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"evil.com", "malware.example"}, l.Domains())
+
+	require.NoError(t, os.WriteFile(path, []byte("evil.com\nnot a domain\n"), 0o600))
+	_, err = Load(path)
+	assert.ErrorIs(t, err, ErrInvalidDomain)
+	assert.ErrorContains(t, err, "line 2")
+}
+
+func TestList_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blocklist.txt")
+	require.NoError(t, os.WriteFile(path, []byte("evil.com\n"), 0o600))
+
+	l, err := Load(path)
+	require.NoError(t, err)
+	assert.True(t, l.Blocked("www.evil.com"))
+
+	// Edited file
+	require.NoError(t, os.WriteFile(path, []byte("evil.com\nmalware.example\n"), 0o600))
+	require.NoError(t, l.Reload())
+	assert.Equal(t, []string{"evil.com", "malware.example"}, l.Domains())
+
+	// Invalid file keeps the list
+	require.NoError(t, os.WriteFile(path, []byte("not a domain\n"), 0o600))
+	assert.ErrorIs(t, l.Reload(), ErrInvalidDomain)
+	assert.Equal(t, []string{"evil.com", "malware.example"}, l.Domains())
+
+	// A list without a file is kept
+	memory := New()
+	require.NoError(t, memory.Block("phishing.example"))
+	require.NoError(t, memory.Reload())
+	assert.Equal(t, []string{"phishing.example"}, memory.Domains())
+}
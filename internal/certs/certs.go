@@ -0,0 +1,140 @@
+// Package certs serves the TLS certificates of the HTTP and gRPC servers and checks the certificates of gRPC clients.
+//
+// A Manager keeps a certificate and its key loaded from files and reloads them when the files change,
+// so certificates are rotated without a restart. Client certificates are verified against a CA bundle
+// and their common name is mapped to the identity of the calling service.
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/apetsko/shortugo/internal/logging"
+)
+
+// DefaultInterval is how often the certificate files are checked for changes.
+const DefaultInterval = 30 * time.Second
+
+// Manager keeps the certificate of a server, reloaded when its files change.
+type Manager struct {
+	certPath string
+	keyPath  string
+	logger   *logging.Logger
+	cert     atomic.Pointer[tls.Certificate]
+	mu       sync.Mutex // Serializes reloads.
+	stamp    [2]fileStamp
+}
+
+// fileStamp tells whether a file changed since it was read.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// NewManager loads the certificate and key at certPath and keyPath.
+func NewManager(certPath, keyPath string, logger *logging.Logger) (*Manager, error) {
+	m := &Manager{certPath: certPath, keyPath: keyPath, logger: logger}
+	if err := m.Reload(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// GetCertificate returns the current certificate; it is meant for tls.Config.GetCertificate.
+func (m *Manager) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return m.cert.Load(), nil
+}
+
+// Certificate returns the current certificate.
+func (m *Manager) Certificate() *tls.Certificate {
+	return m.cert.Load()
+}
+
+// TLSConfig returns a server configuration serving the current certificate.
+func (m *Manager) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: m.GetCertificate,
+	}
+}
+
+// Reload loads the certificate and key files again. The current certificate is kept when they are invalid,
+// such as while only one of them has been replaced.
+func (m *Manager) Reload() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stamp, err := m.stat()
+	if err != nil {
+		return err
+	}
+	cert, err := tls.LoadX509KeyPair(m.certPath, m.keyPath)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+
+	m.cert.Store(&cert)
+	m.stamp = stamp
+	return nil
+}
+
+// Watch reloads the certificate every interval when its files changed, until ctx is done.
+// Failed reloads are logged and tried again at the next change. A zero interval is DefaultInterval.
+func (m *Manager) Watch(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			changed, err := m.changed()
+			if err != nil {
+				m.logger.Error("Failed to check TLS certificate", "cert", m.certPath, "error", err.Error())
+				continue
+			}
+			if !changed {
+				continue
+			}
+			if err := m.Reload(); err != nil {
+				m.logger.Error("Failed to reload TLS certificate, keeping the current one", "cert", m.certPath, "error", err.Error())
+				continue
+			}
+			m.logger.Info("Reloaded TLS certificate", "cert", m.certPath, "not_after", m.Certificate().Leaf.NotAfter.Format(time.RFC3339))
+		}
+	}
+}
+
+// changed reports whether the files differ from the ones last loaded.
+func (m *Manager) changed() (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stamp, err := m.stat()
+	if err != nil {
+		return false, err
+	}
+	return stamp != m.stamp, nil
+}
+
+// stat returns the stamps of the certificate and key files.
+func (m *Manager) stat() ([2]fileStamp, error) {
+	var stamp [2]fileStamp
+	for i, path := range []string{m.certPath, m.keyPath} {
+		info, err := os.Stat(path)
+		if err != nil {
+			return stamp, fmt.Errorf("failed to read TLS file: %w", err)
+		}
+		stamp[i] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+	}
+	return stamp, nil
+}
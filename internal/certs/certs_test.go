@@ -0,0 +1,137 @@
+package certs
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/apetsko/shortugo/internal/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// testCA is a certificate authority issuing the certificates of the tests.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newCA(t *testing.T) *testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns the PEM certificate and key of cn, valid for localhost when it is a server.
+func (ca *testCA) issue(t *testing.T, cn string, server bool) (certPEM, keyPEM []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	if server {
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+		template.DNSNames = []string{"localhost"}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// writeFile writes data to name in dir and returns its path.
+func writeFile(t *testing.T, dir, name string, data []byte) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+func TestManager_Reload(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+	ca := newCA(t)
+	dir := t.TempDir()
+
+	certPEM, keyPEM := ca.issue(t, "first", true)
+	certPath := writeFile(t, dir, "cert.crt", certPEM)
+	keyPath := writeFile(t, dir, "cert.key", keyPEM)
+
+	m, err := NewManager(certPath, keyPath, logger)
+	require.NoError(t, err)
+	cert, err := m.GetCertificate(&tls.ClientHelloInfo{})
+	require.NoError(t, err)
+	assert.Equal(t, "first", cert.Leaf.Subject.CommonName)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go m.Watch(ctx, 10*time.Millisecond)
+
+	// A certificate without its key is not loaded, the current one is kept
+	certPEM, keyPEM = ca.issue(t, "second", true)
+	writeFile(t, dir, "cert.crt", certPEM)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, "first", m.Certificate().Leaf.Subject.CommonName)
+
+	// Once both are replaced the new certificate is served
+	writeFile(t, dir, "cert.key", keyPEM)
+	assert.Eventually(t, func() bool {
+		return m.Certificate().Leaf.Subject.CommonName == "second"
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestNewManager_Errors(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+	dir := t.TempDir()
+	certPEM, _ := newCA(t).issue(t, "server", true)
+	certPath := writeFile(t, dir, "cert.crt", certPEM)
+	otherKeyPath := writeFile(t, dir, "other.key", func() []byte {
+		_, keyPEM := newCA(t).issue(t, "other", true)
+		return keyPEM
+	}())
+
+	_, err := NewManager(filepath.Join(dir, "missing.crt"), otherKeyPath, logger)
+	assert.Error(t, err)
+	_, err = NewManager(certPath, otherKeyPath, logger)
+	assert.ErrorContains(t, err, "failed to load TLS certificate")
+}
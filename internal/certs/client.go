@@ -0,0 +1,116 @@
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// ClientAuth verifies the certificates of clients against a CA bundle and maps their common name (CN)
+// to the identity of the calling service.
+type ClientAuth struct {
+	pool       *x509.CertPool
+	identities map[string]string
+}
+
+// NewClientAuth loads the PEM CA bundle at caPath. identities maps the CN of client certificates
+// to service identities: when it is empty any CN is accepted as the identity, otherwise clients
+// with a CN missing from it are refused.
+func NewClientAuth(caPath string, identities map[string]string) (*ClientAuth, error) {
+	bundle, err := os.ReadFile(caPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client CA bundle: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(bundle) {
+		return nil, fmt.Errorf("no certificate found in client CA bundle %s", caPath)
+	}
+	return &ClientAuth{pool: pool, identities: identities}, nil
+}
+
+// Apply makes cfg require a client certificate signed by the CA bundle whose CN has an identity.
+func (a *ClientAuth) Apply(cfg *tls.Config) {
+	cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	cfg.ClientCAs = a.pool
+	cfg.VerifyConnection = func(cs tls.ConnectionState) error {
+		_, err := a.Identity(cs)
+		return err
+	}
+}
+
+// Identity returns the identity of the client of a verified connection.
+func (a *ClientAuth) Identity(cs tls.ConnectionState) (string, error) {
+	if len(cs.VerifiedChains) == 0 || len(cs.VerifiedChains[0]) == 0 {
+		return "", errors.New("no verified client certificate")
+	}
+	cn := cs.VerifiedChains[0][0].Subject.CommonName
+	if len(a.identities) == 0 {
+		if cn == "" {
+			return "", errors.New("client certificate has no common name")
+		}
+		return cn, nil
+	}
+	identity, ok := a.identities[cn]
+	if !ok {
+		return "", fmt.Errorf("client certificate CN %q has no identity", cn)
+	}
+	return identity, nil
+}
+
+// identityKey is the context key of the identity of the calling service.
+type identityKey struct{}
+
+// IdentityFromContext returns the identity of the service calling with ctx, or "" when it sent no certificate.
+func IdentityFromContext(ctx context.Context) string {
+	identity, _ := ctx.Value(identityKey{}).(string)
+	return identity
+}
+
+// identify returns ctx with the identity of the client of the call, when it is known.
+func (a *ClientAuth) identify(ctx context.Context) context.Context {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ctx
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return ctx
+	}
+	identity, err := a.Identity(info.State)
+	if err != nil {
+		return ctx
+	}
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// UnaryServerInterceptor adds the identity of the calling service to the context of each call.
+func (a *ClientAuth) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(a.identify(ctx), req)
+	}
+}
+
+// StreamServerInterceptor is the streaming counterpart of UnaryServerInterceptor.
+func (a *ClientAuth) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &identifiedStream{ServerStream: ss, ctx: a.identify(ss.Context())})
+	}
+}
+
+// identifiedStream is a server stream whose context carries the identity of the client.
+type identifiedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context with the identity.
+func (s *identifiedStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,118 @@
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"testing"
+
+	"github.com/apetsko/shortugo/internal/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// identityServer is a health server remembering the identity of its last caller.
+type identityServer struct {
+	*health.Server
+	identity string
+}
+
+func (s *identityServer) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	s.identity = IdentityFromContext(ctx)
+	return s.Server.Check(ctx, req)
+}
+
+func TestClientAuth(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+	ca := newCA(t)
+	dir := t.TempDir()
+
+	serverCert, serverKey := ca.issue(t, "localhost", true)
+	m, err := NewManager(writeFile(t, dir, "server.crt", serverCert), writeFile(t, dir, "server.key", serverKey), logger)
+	require.NoError(t, err)
+	caPath := writeFile(t, dir, "ca.crt", ca.pem)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	clientCert := func(ca *testCA, cn string) []tls.Certificate {
+		certPEM, keyPEM := ca.issue(t, cn, false)
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		require.NoError(t, err)
+		return []tls.Certificate{cert}
+	}
+
+	tests := []struct {
+		name         string
+		identities   map[string]string
+		certificates []tls.Certificate
+		wantIdentity string
+		wantErr      bool
+	}{
+		{name: "CN as identity", certificates: clientCert(ca, "billing"), wantIdentity: "billing"},
+		{name: "mapped CN", identities: map[string]string{"billing.prod": "billing"}, certificates: clientCert(ca, "billing.prod"), wantIdentity: "billing"},
+		{name: "unmapped CN", identities: map[string]string{"billing.prod": "billing"}, certificates: clientCert(ca, "reports.prod"), wantErr: true},
+		{name: "no certificate", wantErr: true},
+		{name: "certificate of another CA", certificates: clientCert(newCA(t), "billing"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientAuth, err := NewClientAuth(caPath, tt.identities)
+			require.NoError(t, err)
+			tlsConfig := m.TLSConfig()
+			clientAuth.Apply(tlsConfig)
+
+			lis := bufconn.Listen(1024 * 1024)
+			srv := grpc.NewServer(
+				grpc.Creds(credentials.NewTLS(tlsConfig)),
+				grpc.UnaryInterceptor(clientAuth.UnaryServerInterceptor()),
+			)
+			hs := &identityServer{Server: health.NewServer()}
+			healthpb.RegisterHealthServer(srv, hs)
+			go func() {
+				_ = srv.Serve(lis)
+			}()
+			t.Cleanup(srv.Stop)
+
+			conn, err := grpc.NewClient("passthrough:///localhost",
+				grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+				grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
+					MinVersion:   tls.VersionTLS12,
+					RootCAs:      roots,
+					ServerName:   "localhost",
+					Certificates: tt.certificates,
+				})),
+			)
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = conn.Close() })
+
+			_, err = healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantIdentity, hs.identity)
+		})
+	}
+}
+
+func TestNewClientAuth_Errors(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := NewClientAuth(dir+"/missing.crt", nil)
+	assert.Error(t, err)
+	_, err = NewClientAuth(writeFile(t, dir, "empty.crt", []byte("not a certificate")), nil)
+	assert.ErrorContains(t, err, "no certificate found")
+}
+
+func TestIdentityFromContext(t *testing.T) {
+	assert.Empty(t, IdentityFromContext(context.Background()))
+}
@@ -1,6 +1,7 @@
 // Package config provides functionality for managing application configuration.
-// It supports loading configuration values from environment variables and command-line flags,
-// ensuring flexibility and ease of use in different deployment environments.
+// Settings are read from a JSON, YAML or TOML file, environment variables and command-line flags,
+// ensuring flexibility and ease of use in different deployment environments. Print writes the result
+// with its secrets redacted.
 package config
 
 import (
@@ -8,15 +9,20 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"time"
 
+	"github.com/apetsko/shortugo/internal/logging"
 	"github.com/apetsko/shortugo/internal/utils"
 	"github.com/caarlos0/env/v11"
+	"go.uber.org/zap/zapcore"
 )
 
 // Config holds the configuration values for the application.
-// These values can be populated from environment variables or command-line flags.
+// These values can be populated from a configuration file, environment variables or command-line flags;
+// the flag, file key and default of each setting are listed in options.
 type Config struct {
-	Config string `env:"CONFIG" envDefault:""`
+	// Config is the configuration file the settings were read from, if any.
+	Config string `env:"CONFIG"`
 
 	// TrustedSubnet indicates trusted subnet for secure access /stats endpoint
 	TrustedSubnet string `env:"TRUSTED_SUBNET" validate:"required"`
@@ -46,53 +52,203 @@ type Config struct {
 	TLSKeyPath string `env:"KEY_FILE" validate:"required_if=EnableHTTPS true"`
 
 	// Https indicates whether the application should use HTTPS for secure communication.
+	// It enables TLS on the gRPC server as well, with the same certificate unless GRPCTLSCertPath is set.
 	EnableHTTPS bool `env:"ENABLE_HTTPS"`
+
+	// GRPCEnableTLS enables TLS on the gRPC server alone.
+	GRPCEnableTLS bool `env:"GRPC_ENABLE_TLS"`
+
+	// GRPCTLSCertPath is the certificate of the gRPC server. Empty uses TLSCertPath.
+	GRPCTLSCertPath string `env:"GRPC_CERT_FILE" validate:"required_with=GRPCTLSKeyPath"`
+
+	// GRPCTLSKeyPath is the private key of the gRPC server. Empty uses TLSKeyPath.
+	GRPCTLSKeyPath string `env:"GRPC_KEY_FILE" validate:"required_with=GRPCTLSCertPath"`
+
+	// GRPCClientCAPath is the PEM CA bundle gRPC clients must present a certificate of. Empty accepts any client.
+	GRPCClientCAPath string `env:"GRPC_CLIENT_CA_FILE"`
+
+	// GRPCClientIdentities maps the CN of gRPC client certificates to service identities, as "cn=identity" pairs.
+	// Empty takes the CN as the identity; otherwise clients with another CN are refused.
+	GRPCClientIdentities map[string]string `env:"GRPC_CLIENT_IDENTITIES" envSeparator:"," envKeyValSeparator:"="`
+
+	// TLSReloadInterval is how often certificate files are checked for changes and reloaded.
+	TLSReloadInterval time.Duration `env:"TLS_RELOAD_INTERVAL" validate:"gt=0"`
+
+	// RedirectType is the default HTTP status used to redirect short links: 301, 302, 307 or 308.
+	RedirectType int `env:"REDIRECT_TYPE" validate:"oneof=301 302 307 308"`
+
+	// RedirectMode is the default way redirects are delivered: "header" (3xx with Location) or "html" (meta refresh page).
+	RedirectMode string `env:"REDIRECT_MODE" validate:"oneof=header html"`
+
+	// QRCacheSize is the number of rendered QR code images kept in memory. Zero disables the cache.
+	QRCacheSize int `env:"QR_CACHE_SIZE" validate:"gte=0"`
+
+	// PasswordAttempts is the number of wrong passwords a client may enter for a link within PasswordWindow.
+	// Zero disables the limit.
+	PasswordAttempts int `env:"PASSWORD_ATTEMPTS" validate:"gte=0"`
+
+	// PasswordWindow is the period failed password attempts are counted in.
+	PasswordWindow time.Duration `env:"PASSWORD_WINDOW" validate:"gt=0"`
+
+	// GeoIPPath is the MaxMind country database used by routing rules with a country. Empty disables them.
+	GeoIPPath string `env:"GEOIP_DB"`
+
+	// BlocklistPath is the file keeping the domains links may not lead to, one per line.
+	// Empty keeps the blocklist in memory only.
+	BlocklistPath string `env:"BLOCKLIST_FILE"`
+
+	// AdminUsers are the user IDs allowed to use the admin API with their cookie.
+	AdminUsers []string `env:"ADMIN_USERS" envSeparator:","`
+
+	// AdminKeys are the API keys allowed to use the admin API, sent as "Authorization: Bearer <key>".
+	AdminKeys []string `env:"ADMIN_API_KEYS" envSeparator:","`
+
+	// AuditFile is the JSONL file the audit log is appended to.
+	// Empty keeps the audit log in the database, or in the application log with file storage.
+	AuditFile string `env:"AUDIT_FILE"`
+
+	// TraceExporter is where spans are sent: "otlp" to a collector, "stdout" or "file". Empty disables tracing.
+	TraceExporter string `env:"TRACE_EXPORTER" validate:"omitempty,oneof=otlp stdout file"`
+
+	// TraceEndpoint is the URL of the OTLP/HTTP collector. Empty uses OTEL_EXPORTER_OTLP_ENDPOINT or localhost:4318.
+	TraceEndpoint string `env:"TRACE_ENDPOINT"`
+
+	// TraceFile is the file spans are appended to with the "file" exporter.
+	TraceFile string `env:"TRACE_FILE" validate:"required_if=TraceExporter file"`
+
+	// TraceSampleRatio is the share of new traces that are recorded; traces continued from a caller follow its decision.
+	TraceSampleRatio float64 `env:"TRACE_SAMPLE_RATIO" validate:"gte=0,lte=1"`
+
+	// HealthInterval is how often the storage is pinged to report the readiness of the service.
+	HealthInterval time.Duration `env:"HEALTH_INTERVAL" validate:"gt=0"`
+
+	// ShutdownDrain is how long the service reports it is not ready before its servers stop on shutdown.
+	ShutdownDrain time.Duration `env:"SHUTDOWN_DRAIN" validate:"gte=0"`
+
+	// LogLevel is the minimum level of the entries logged: debug, info, warn or error.
+	LogLevel string `env:"LOG_LEVEL" validate:"oneof=debug info warn error"`
+
+	// LogFormat is how entries are written: "console" lines or "json" objects for log shippers.
+	LogFormat string `env:"LOG_FORMAT" validate:"oneof=console json"`
+
+	// LogFile is the file entries are written to, rotated as it grows. Empty writes to stderr.
+	LogFile string `env:"LOG_FILE"`
+
+	// LogMaxSize is the size in megabytes LogFile grows to before it is rotated.
+	LogMaxSize int `env:"LOG_MAX_SIZE" validate:"gt=0"`
+
+	// LogMaxAge is how long rotated log files are kept, in whole days. Zero keeps them whatever their age.
+	LogMaxAge time.Duration `env:"LOG_MAX_AGE" validate:"gte=0"`
+
+	// LogMaxBackups is the number of rotated log files kept. Zero keeps them all.
+	LogMaxBackups int `env:"LOG_MAX_BACKUPS" validate:"gte=0"`
+
+	// LogSampleInitial is the number of access entries with the same message logged every second
+	// before they are sampled. Zero logs every request.
+	LogSampleInitial int `env:"LOG_SAMPLE_INITIAL" validate:"gte=0"`
+
+	// LogSampleThereafter is one out of how many further access entries are logged within the second.
+	// Zero drops them.
+	LogSampleThereafter int `env:"LOG_SAMPLE_THEREAFTER" validate:"gte=0"`
+
+	// ReloadInterval is how often the configuration file is checked for changes, which are then applied
+	// as on SIGHUP. Zero only reloads on SIGHUP.
+	ReloadInterval time.Duration `env:"CONFIG_RELOAD_INTERVAL" validate:"gte=0"`
 }
 
-// New creates a new Config instance, populating it with values from command-line flags and environment variables.
-// Returns a pointer to the Config instance or an error if the configuration is invalid.
+// New loads the configuration of the process from its command line, see Load, and validates it.
+// The flags are registered on flag.CommandLine.
 func New() (*Config, error) {
+	c, err := Load(flag.CommandLine, os.Args[1:])
+	if err != nil {
+		return nil, err
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Load registers the flags of the settings on fs, parses args and builds the configuration in layers,
+// each overriding the previous one:
+//
+//  1. the defaults;
+//  2. the configuration file of the -config flag or of the CONFIG environment variable;
+//  3. the environment variables;
+//  4. the flags given in args.
+//
+// The result is not validated, see Validate.
+func Load(fs *flag.FlagSet, args []string) (*Config, error) {
+	given := registerFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
 	var c Config
+	setDefaults(&c)
 
-	// Parse command-line flags
-	flag.BoolVar(&c.EnableHTTPS, "s", false, "enable https")
-	flag.StringVar(&c.TLSCertPath, "cert", "certs/cert.crt", "certificate filepath")
-	flag.StringVar(&c.TLSKeyPath, "key", "certs/cert.key", "private key filepath")
-	flag.StringVar(&c.Config, "config", "", "config filepath")
-	flag.StringVar(&c.Host, "a", "localhost:8080", "network address with port")
-	flag.StringVar(&c.GRPCHost, "g", "localhost:9090", "network grpc address with port")
-	flag.StringVar(&c.BaseURL, "b", "http://localhost:8080", "base url address")
-	flag.StringVar(&c.FileStoragePath, "f", "db.json", "file storages name")
-	flag.StringVar(&c.DatabaseDSN, "d", "", "database DSN")
-	flag.StringVar(&c.Secret, "secret", "fortytwo", "HMAC secret")
-	flag.StringVar(&c.TrustedSubnet, "t", "127.0.0.0/24", "trusted subnet")
-
-	// Parse config.json
-	if c.Config != "" {
-		if err := LoadJSONConfig(c.Config, &c); err != nil {
-			return nil, fmt.Errorf("failed to load config from json file: %w", err)
+	// The file is the one of the flag, or else of the environment
+	path, ok := given["Config"]
+	if !ok {
+		path = os.Getenv("CONFIG")
+	}
+	if path != "" {
+		doc, err := readFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := applyFile(doc, &c); err != nil {
+			return nil, err
 		}
 	}
 
-	// Parse the flags
-	flag.Parse()
-
-	// Load environment variables into the Config struct
 	if err := env.Parse(&c); err != nil {
 		return nil, fmt.Errorf("failed to load environment: %w", err)
 	}
 
-	// Validate the loaded configuration
-	if err := utils.ValidateStruct(c); err != nil {
+	if err := applyFlags(given, &c); err != nil {
 		return nil, err
 	}
-	fmt.Println("2 ", c.TrustedSubnet)
+	c.Config = path
 
-	// Return the populated and validated Config
 	return &c, nil
 }
 
-// LoadJSONConfig reads config.json file
+// Validate checks the settings of c.
+func (c *Config) Validate() error {
+	return utils.ValidateStruct(c)
+}
+
+// LogOptions returns the options the logger is built with.
+func (c *Config) LogOptions() (logging.Options, error) {
+	level, err := zapcore.ParseLevel(c.LogLevel)
+	if err != nil {
+		return logging.Options{}, err
+	}
+	return logging.Options{
+		Format:           c.LogFormat,
+		File:             c.LogFile,
+		MaxSize:          c.LogMaxSize,
+		MaxAge:           c.LogMaxAge,
+		MaxBackups:       c.LogMaxBackups,
+		SampleInitial:    c.LogSampleInitial,
+		SampleThereafter: c.LogSampleThereafter,
+		Level:            level,
+	}, nil
+}
+
+// GRPCTLS reports whether the gRPC server uses TLS and with which certificate and key files.
+func (c *Config) GRPCTLS() (enabled bool, certPath, keyPath string) {
+	enabled = c.GRPCEnableTLS || c.EnableHTTPS
+	if c.GRPCTLSCertPath != "" {
+		return enabled, c.GRPCTLSCertPath, c.GRPCTLSKeyPath
+	}
+	return enabled, c.TLSCertPath, c.TLSKeyPath
+}
+
+// LoadJSONConfig decodes the JSON file at path into out.
+//
+// Deprecated: Load reads configuration files of every format, with their sections.
 func LoadJSONConfig(path string, out interface{}) error {
 	file, err := os.Open(path)
 	if err != nil {
@@ -1,12 +1,19 @@
 package config
 
 import (
+	"bytes"
+	"flag"
+	"io"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
+	"time"
 
+	"github.com/apetsko/shortugo/internal/logging"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
 )
 
 type mockConfig struct {
@@ -77,7 +84,7 @@ func TestParse(t *testing.T) {
 	}{
 		{
 			name:    "OK",
-			wantC:   &Config{EnableHTTPS: false, TLSCertPath: "certs/cert.crt", TLSKeyPath: "certs/cert.key", Config: "", Host: "localhost:8080", GRPCHost: "localhost:9090", BaseURL: "http://localhost:8080", FileStoragePath: "db.json", DatabaseDSN: "", Secret: "fortytwo", TrustedSubnet: "127.0.0.0/24"},
+			wantC:   &Config{EnableHTTPS: false, TLSCertPath: "certs/cert.crt", TLSKeyPath: "certs/cert.key", Config: "", Host: "localhost:8080", GRPCHost: "localhost:9090", BaseURL: "http://localhost:8080", FileStoragePath: "db.json", DatabaseDSN: "", Secret: "fortytwo", TrustedSubnet: "127.0.0.0/24", RedirectType: 307, RedirectMode: "header", QRCacheSize: 256, PasswordAttempts: 5, PasswordWindow: 15 * time.Minute, TraceFile: "traces.jsonl", TraceSampleRatio: 1, HealthInterval: 5 * time.Second, ShutdownDrain: 5 * time.Second, TLSReloadInterval: 30 * time.Second, LogLevel: "info", LogFormat: "console", LogMaxSize: 100, LogSampleThereafter: 100, ReloadInterval: 10 * time.Second},
 			wantErr: false,
 		},
 	}
@@ -91,3 +98,312 @@ func TestParse(t *testing.T) {
 		})
 	}
 }
+
+// load loads the configuration of args with a flag set of its own.
+func load(t *testing.T, args ...string) (*Config, error) {
+	t.Helper()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return Load(fs, args)
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+http:
+  address: ":8001"
+  base_url: "http://file"
+  redirect_type: 301
+auth:
+  secret: file-secret
+  admin_users: [alice, bob]
+cache:
+  qr_size: 10
+`), 0o600))
+
+	// The file of the flag overrides the defaults
+	c, err := load(t, "-config", path)
+	require.NoError(t, err)
+	assert.Equal(t, path, c.Config)
+	assert.Equal(t, ":8001", c.Host)
+	assert.Equal(t, "http://file", c.BaseURL)
+	assert.Equal(t, 301, c.RedirectType)
+	assert.Equal(t, "file-secret", c.Secret)
+	assert.Equal(t, []string{"alice", "bob"}, c.AdminUsers)
+	assert.Equal(t, 10, c.QRCacheSize)
+	assert.Equal(t, "localhost:9090", c.GRPCHost, "defaults are kept")
+
+	// The environment overrides the file, the flags override the environment
+	t.Setenv("SERVER_ADDRESS", ":8002")
+	t.Setenv("BASE_URL", "http://env")
+	t.Setenv("QR_CACHE_SIZE", "20")
+	c, err = load(t, "-config", path, "-b", "http://flag", "-qr-cache-size", "0")
+	require.NoError(t, err)
+	assert.Equal(t, ":8002", c.Host)
+	assert.Equal(t, "http://flag", c.BaseURL)
+	assert.Equal(t, 0, c.QRCacheSize, "a flag set to the zero value still overrides")
+	assert.Equal(t, 301, c.RedirectType)
+	require.NoError(t, c.Validate())
+
+	// Without the flag, the file of the environment is read
+	t.Setenv("CONFIG", path)
+	c, err = load(t)
+	require.NoError(t, err)
+	assert.Equal(t, path, c.Config)
+	assert.Equal(t, "file-secret", c.Secret)
+}
+
+func TestLoad_Formats(t *testing.T) {
+	want := func(c *Config) {
+		assert.Equal(t, ":8001", c.Host)
+		assert.True(t, c.EnableHTTPS)
+		assert.Equal(t, 0.5, c.TraceSampleRatio)
+		assert.Equal(t, 10*time.Minute, c.PasswordWindow)
+		assert.Equal(t, []string{"k1", "k2"}, c.AdminKeys)
+		assert.Equal(t, map[string]string{"billing.prod": "billing"}, c.GRPCClientIdentities)
+	}
+
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{
+			name: "json",
+			file: "config.json",
+			content: `{
+				"http": {"address": ":8001", "tls": true},
+				"tracing": {"sample_ratio": 0.5},
+				"auth": {"password_window": "10m", "admin_keys": ["k1", "k2"]},
+				"grpc": {"client_identities": {"billing.prod": "billing"}}
+			}`,
+		},
+		{
+			name: "yaml",
+			file: "config.yml",
+			content: `
+http: {address: ":8001", tls: true}
+tracing: {sample_ratio: 0.5}
+auth: {password_window: 10m, admin_keys: [k1, k2]}
+grpc:
+  client_identities:
+    billing.prod: billing
+`,
+		},
+		{
+			name: "toml",
+			file: "config.toml",
+			content: `
+[http]
+address = ":8001"
+tls = true
+
+[tracing]
+sample_ratio = 0.5
+
+[auth]
+password_window = "10m"
+admin_keys = ["k1", "k2"]
+
+[grpc.client_identities]
+"billing.prod" = "billing"
+`,
+		},
+		{
+			name: "flat JSON of older versions",
+			file: "config.json",
+			content: `{
+				"Host": ":8001", "EnableHTTPS": true, "TraceSampleRatio": 0.5, "PasswordWindow": "10m",
+				"AdminKeys": ["k1", "k2"], "GRPCClientIdentities": {"billing.prod": "billing"}
+			}`,
+		},
+		{
+			name: "strings as on the command line",
+			file: "config.yaml",
+			content: `
+http: {address: ":8001", tls: "true"}
+tracing: {sample_ratio: "0.5"}
+auth: {password_window: 10m, admin_keys: "k1,k2"}
+grpc: {client_identities: "billing.prod=billing"}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+
+			c, err := load(t, "-config", path)
+			require.NoError(t, err)
+			want(c)
+		})
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		args    []string
+		wantErr string
+	}{
+		{name: "missing file", file: "", args: []string{"-config", "missing.yaml"}, wantErr: "open config file"},
+		{name: "unknown format", file: "config.ini", content: "a=b", wantErr: "unsupported config file"},
+		{name: "invalid YAML", file: "config.yaml", content: "http: [", wantErr: "decode config"},
+		{name: "unknown section", file: "config.yaml", content: "server: {address: ':1'}", wantErr: "unknown setting server"},
+		{name: "unknown setting", file: "config.yaml", content: "http: {adress: ':1'}", wantErr: "unknown setting http.adress"},
+		{name: "wrong type", file: "config.yaml", content: "http: {redirect_type: [301]}", wantErr: "config http.redirect_type: want an integer"},
+		{name: "duration as a number", file: "config.json", content: `{"auth": {"password_window": 600}}`, wantErr: "want a duration"},
+		{name: "fractional integer", file: "config.json", content: `{"cache": {"qr_size": 1.5}}`, wantErr: "want an integer"},
+		{name: "invalid flag", args: []string{"-password-window", "soon"}, wantErr: `invalid duration "soon"`},
+		{name: "invalid pairs", args: []string{"-grpc-client-identities", "billing"}, wantErr: "invalid pair"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := tt.args
+			if tt.file != "" {
+				path := filepath.Join(t.TempDir(), tt.file)
+				require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+				args = append([]string{"-config", path}, args...)
+			}
+
+			_, err := load(t, args...)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	c, err := load(t, "-redirect-type", "300")
+	require.NoError(t, err)
+	assert.ErrorContains(t, c.Validate(), "RedirectType")
+}
+
+func TestPrint(t *testing.T) {
+	c, err := load(t,
+		"-secret", "s3cr3t",
+		"-admin-keys", "key1,key2",
+		"-d", "postgres://app:hunter2@db:5432/short",
+		"-password-window", "10m",
+	)
+	require.NoError(t, err)
+
+	for _, format := range []string{FormatJSON, FormatYAML, FormatTOML} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, Print(&buf, c, format))
+			out := buf.String()
+			assert.NotContains(t, out, "s3cr3t")
+			assert.NotContains(t, out, "key1")
+			assert.NotContains(t, out, "hunter2")
+			assert.Contains(t, out, "postgres://app:REDACTED@db:5432/short")
+			assert.Contains(t, out, "10m0s")
+
+			// The printed configuration is read back, secrets aside
+			path := filepath.Join(t.TempDir(), "config."+format)
+			require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o600))
+			back, err := load(t, "-config", path)
+			require.NoError(t, err)
+			assert.Equal(t, c.PasswordWindow, back.PasswordWindow)
+			assert.Equal(t, c.Host, back.Host)
+			assert.Equal(t, redacted, back.Secret)
+		})
+	}
+
+	var buf bytes.Buffer
+	assert.Error(t, Print(&buf, c, "ini"))
+}
+
+func TestRedactString(t *testing.T) {
+	tests := []struct {
+		in     string
+		secret redact
+		want   string
+	}{
+		{in: "plain", secret: redactNone, want: "plain"},
+		{in: "", secret: redactAll, want: ""},
+		{in: "s3cr3t", secret: redactAll, want: redacted},
+		{in: "postgres://app:pw@db/short?sslmode=disable", secret: redactDSN, want: "postgres://app:REDACTED@db/short?sslmode=disable"},
+		{in: "postgres://app@db/short", secret: redactDSN, want: "postgres://app@db/short"},
+		{in: "host=db user=app password=pw dbname=short", secret: redactDSN, want: "host=db user=app password=REDACTED dbname=short"},
+		{in: "host=db password='p w' dbname=short", secret: redactDSN, want: "host=db password=REDACTED dbname=short"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, redactString(tt.in, tt.secret), tt.in)
+	}
+}
+
+func TestOptions(t *testing.T) {
+	// Every field of Config is a setting, once
+	typ := reflect.TypeOf(Config{})
+	fields := make(map[string]bool)
+	flags := make(map[string]bool)
+	for _, o := range options {
+		_, ok := typ.FieldByName(o.field)
+		assert.True(t, ok, o.field)
+		assert.False(t, fields[o.field], o.field)
+		assert.False(t, flags[o.flag], o.flag)
+		fields[o.field] = true
+		flags[o.flag] = true
+	}
+	assert.Len(t, fields, typ.NumField())
+}
+
+func TestUpdate(t *testing.T) {
+	running, err := load(t)
+	require.NoError(t, err)
+
+	next, err := load(t,
+		"-t", "10.0.0.0/8",
+		"-log-level", "warn",
+		"-qr-cache-size", "10",
+		"-a", ":9000",
+		"-d", "postgres://db/short",
+		"-admin-users", "alice",
+	)
+	require.NoError(t, err)
+
+	updated, applied, restart := running.Update(next)
+	assert.Equal(t, []string{"auth.trusted_subnet", "cache.qr_size", "log.level"}, applied)
+	assert.Equal(t, []string{"http.address", "storage.database_dsn", "auth.admin_users"}, restart)
+
+	assert.Equal(t, "10.0.0.0/8", updated.TrustedSubnet)
+	assert.Equal(t, "warn", updated.LogLevel)
+	assert.Equal(t, 10, updated.QRCacheSize)
+	assert.Equal(t, running.Host, updated.Host, "settings needing a restart keep their running value")
+	assert.Empty(t, updated.DatabaseDSN)
+	assert.Nil(t, updated.AdminUsers)
+	assert.Equal(t, "127.0.0.0/24", running.TrustedSubnet, "the running configuration is left as is")
+
+	// Nothing changed
+	_, applied, restart = updated.Update(updated)
+	assert.Empty(t, applied)
+	assert.Empty(t, restart)
+}
+
+func TestLogOptions(t *testing.T) {
+	c, err := load(t, "-log-level", "warn", "-log-format", "json", "-log-file", "shortugo.log", "-log-max-age", "168h", "-log-sample-initial", "10")
+	require.NoError(t, err)
+	require.NoError(t, c.Validate())
+
+	o, err := c.LogOptions()
+	require.NoError(t, err)
+	assert.Equal(t, logging.Options{
+		Format:           logging.FormatJSON,
+		File:             "shortugo.log",
+		MaxSize:          logging.DefaultMaxSize,
+		MaxAge:           7 * 24 * time.Hour,
+		SampleInitial:    10,
+		SampleThereafter: 100,
+		Level:            zapcore.WarnLevel,
+	}, o)
+
+	c, err = load(t, "-log-format", "text")
+	require.NoError(t, err)
+	assert.ErrorContains(t, c.Validate(), "LogFormat")
+}
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// readFile decodes the configuration file at path, JSON, YAML or TOML after its extension.
+func readFile(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("open config file: %w", err)
+	}
+
+	doc := make(map[string]any)
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		err = json.Unmarshal(data, &doc)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &doc)
+	case ".toml":
+		err = toml.Unmarshal(data, &doc)
+	default:
+		return nil, fmt.Errorf("unsupported config file %s: want .json, .yaml, .yml or .toml", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decode config %s: %w", path, err)
+	}
+	return doc, nil
+}
+
+// applyFile sets the settings of the configuration file doc on c.
+//
+// Settings are grouped in sections, as in {"http": {"address": ":8080"}}. Top-level settings named after
+// the fields of Config, as in {"Host": ":8080"}, are read as well, like older JSON files wrote them.
+// Unknown settings are reported, so typos are not silently ignored.
+func applyFile(doc map[string]any, c *Config) error {
+	v := reflect.ValueOf(c).Elem()
+
+	set := func(o option, x any) error {
+		if err := setValue(v.FieldByName(o.field), x); err != nil {
+			return fmt.Errorf("config %s: %w", o.key, err)
+		}
+		return nil
+	}
+
+	for _, name := range sortedKeys(doc) {
+		x := doc[name]
+		if section, ok := x.(map[string]any); ok && isSection(name) {
+			for _, key := range sortedKeys(section) {
+				o, ok := optionByKey(name + "." + key)
+				if !ok {
+					return fmt.Errorf("config: unknown setting %s.%s", name, key)
+				}
+				if err := set(o, section[key]); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		o, ok := optionByField(name)
+		if !ok {
+			return fmt.Errorf("config: unknown setting %s", name)
+		}
+		if err := set(o, x); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isSection reports whether name is the section of some settings.
+func isSection(name string) bool {
+	return slices.ContainsFunc(options, func(o option) bool {
+		return strings.HasPrefix(o.key, name+".")
+	})
+}
+
+// optionByKey returns the setting with the key section.name in configuration files.
+func optionByKey(key string) (option, bool) {
+	i := slices.IndexFunc(options, func(o option) bool { return o.key == key })
+	if i < 0 {
+		return option{}, false
+	}
+	return options[i], true
+}
+
+// optionByField returns the setting of the field name of Config, whatever its case.
+// The path of the configuration file itself cannot be set in a file.
+func optionByField(name string) (option, bool) {
+	i := slices.IndexFunc(options, func(o option) bool { return o.key != "" && strings.EqualFold(o.field, name) })
+	if i < 0 {
+		return option{}, false
+	}
+	return options[i], true
+}
+
+// sortedKeys returns the keys of m in order, so errors are reported the same way every time.
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+)
+
+// flagValue is the flag of a setting. Parsing only checks and records the value given,
+// which is set once the lower layers are loaded, so flags override them.
+type flagValue struct {
+	opt   option
+	typ   reflect.Type
+	given map[string]string
+}
+
+// registerFlags registers the flags of the settings on fs and returns the values they will be given
+// by the field of the setting.
+func registerFlags(fs *flag.FlagSet) map[string]string {
+	given := make(map[string]string)
+	typ := reflect.TypeOf(Config{})
+	for _, o := range options {
+		f, _ := typ.FieldByName(o.field)
+		fs.Var(&flagValue{opt: o, typ: f.Type, given: given}, o.flag, o.usage)
+	}
+	return given
+}
+
+// applyFlags sets the values given to flags on c.
+func applyFlags(given map[string]string, c *Config) error {
+	v := reflect.ValueOf(c).Elem()
+	for _, o := range options {
+		s, ok := given[o.field]
+		if !ok {
+			continue
+		}
+		if err := setString(v.FieldByName(o.field), s); err != nil {
+			return fmt.Errorf("flag -%s: %w", o.flag, err)
+		}
+	}
+	return nil
+}
+
+// String returns the default of the setting, shown by the usage of the flags.
+func (v *flagValue) String() string {
+	if v.opt.def == nil {
+		return ""
+	}
+	return fmt.Sprint(v.opt.def)
+}
+
+// Set checks s and records it as the value of the setting.
+func (v *flagValue) Set(s string) error {
+	if err := setString(reflect.New(v.typ).Elem(), s); err != nil {
+		return err
+	}
+	v.given[v.opt.field] = s
+	return nil
+}
+
+// IsBoolFlag lets boolean flags be given without a value.
+func (v *flagValue) IsBoolFlag() bool {
+	return v.typ != nil && v.typ.Kind() == reflect.Bool
+}
@@ -0,0 +1,259 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apetsko/shortugo/internal/certs"
+	"github.com/apetsko/shortugo/internal/health"
+	"github.com/apetsko/shortugo/internal/logging"
+	"github.com/apetsko/shortugo/internal/qrcode"
+	"github.com/apetsko/shortugo/internal/ratelimit"
+)
+
+// redact tells how the value of a setting is hidden when the configuration is printed.
+type redact int
+
+const (
+	redactNone redact = iota // Printed as is.
+	redactAll                // Replaced as a whole.
+	redactDSN                // Only the password is replaced.
+)
+
+// redacted replaces the secrets in printed configurations.
+const redacted = "REDACTED"
+
+// option is a setting of Config: its flag, its key in configuration files and its default value.
+type option struct {
+	field  string // Name of the Config field.
+	flag   string // Command-line flag, without its dash.
+	key    string // Key in configuration files, as section.name; empty when it cannot be set in a file.
+	def    any    // Default value of the type of the field; nil is the zero value.
+	usage  string // Description of the flag.
+	secret redact // How the value is hidden when printed.
+	live   bool   // Changes are applied while the server runs, see Update; others need a restart.
+}
+
+// options are the settings of Config, in the order they are printed.
+var options = []option{
+	{field: "Config", flag: "config", usage: "configuration file: .json, .yaml, .yml or .toml"},
+
+	{field: "Host", flag: "a", key: "http.address", def: "localhost:8080", usage: "network address with port"},
+	{field: "BaseURL", flag: "b", key: "http.base_url", def: "http://localhost:8080", usage: "base url address"},
+	{field: "EnableHTTPS", flag: "s", key: "http.tls", usage: "enable https"},
+	{field: "TLSCertPath", flag: "cert", key: "http.cert", def: "certs/cert.crt", usage: "certificate filepath"},
+	{field: "TLSKeyPath", flag: "key", key: "http.key", def: "certs/cert.key", usage: "private key filepath"},
+	{field: "RedirectType", flag: "redirect-type", key: "http.redirect_type", def: 307, usage: "default redirect status: 301, 302, 307 or 308"},
+	{field: "RedirectMode", flag: "redirect-mode", key: "http.redirect_mode", def: "header", usage: "default redirect mode: header or html"},
+
+	{field: "GRPCHost", flag: "g", key: "grpc.address", def: "localhost:9090", usage: "network grpc address with port"},
+	{field: "GRPCEnableTLS", flag: "grpc-tls", key: "grpc.tls", usage: "enable TLS on the gRPC server"},
+	{field: "GRPCTLSCertPath", flag: "grpc-cert", key: "grpc.cert", usage: "gRPC certificate filepath, -cert when empty"},
+	{field: "GRPCTLSKeyPath", flag: "grpc-key", key: "grpc.key", usage: "gRPC private key filepath, -key when empty"},
+	{field: "GRPCClientCAPath", flag: "grpc-client-ca", key: "grpc.client_ca", usage: "CA bundle filepath gRPC client certificates are verified against"},
+	{field: "GRPCClientIdentities", flag: "grpc-client-identities", key: "grpc.client_identities", usage: "comma-separated cn=identity pairs of gRPC client certificates"},
+
+	{field: "TLSReloadInterval", flag: "tls-reload-interval", key: "tls.reload_interval", def: certs.DefaultInterval, usage: "period of the checks of certificate files for changes"},
+
+	{field: "FileStoragePath", flag: "f", key: "storage.file", def: "db.json", usage: "file storages name"},
+	{field: "DatabaseDSN", flag: "d", key: "storage.database_dsn", usage: "database DSN", secret: redactDSN},
+	{field: "AuditFile", flag: "audit-file", key: "storage.audit_file", usage: "audit log filepath"},
+	{field: "BlocklistPath", flag: "blocklist", key: "storage.blocklist", usage: "blocked domains filepath"},
+	{field: "GeoIPPath", flag: "geoip", key: "storage.geoip", usage: "MaxMind country database filepath for routing rules"},
+
+	{field: "Secret", flag: "secret", key: "auth.secret", def: "fortytwo", usage: "HMAC secret", secret: redactAll},
+	{field: "TrustedSubnet", flag: "t", key: "auth.trusted_subnet", def: "127.0.0.0/24", usage: "trusted subnet", live: true},
+	{field: "AdminUsers", flag: "admin-users", key: "auth.admin_users", usage: "comma-separated user IDs allowed to use the admin API"},
+	{field: "AdminKeys", flag: "admin-keys", key: "auth.admin_keys", usage: "comma-separated API keys allowed to use the admin API", secret: redactAll},
+	{field: "PasswordAttempts", flag: "password-attempts", key: "auth.password_attempts", def: ratelimit.DefaultMaxFailures, usage: "wrong link passwords allowed per client within the window", live: true},
+	{field: "PasswordWindow", flag: "password-window", key: "auth.password_window", def: ratelimit.DefaultWindow, usage: "period failed link password attempts are counted in", live: true},
+
+	{field: "QRCacheSize", flag: "qr-cache-size", key: "cache.qr_size", def: qrcode.DefaultCacheSize, usage: "number of cached QR code images", live: true},
+
+	{field: "TraceExporter", flag: "trace-exporter", key: "tracing.exporter", usage: "trace exporter: otlp, stdout or file; empty disables tracing"},
+	{field: "TraceEndpoint", flag: "trace-endpoint", key: "tracing.endpoint", usage: "OTLP/HTTP collector URL"},
+	{field: "TraceFile", flag: "trace-file", key: "tracing.file", def: "traces.jsonl", usage: "trace filepath of the file exporter"},
+	{field: "TraceSampleRatio", flag: "trace-sample-ratio", key: "tracing.sample_ratio", def: 1.0, usage: "share of new traces that are recorded, from 0 to 1"},
+
+	{field: "HealthInterval", flag: "health-interval", key: "health.interval", def: health.DefaultInterval, usage: "period of the storage checks of the readiness probes"},
+	{field: "ShutdownDrain", flag: "shutdown-drain", key: "health.shutdown_drain", def: health.DefaultDrain, usage: "time the service reports it is not ready before stopping"},
+
+	{field: "LogLevel", flag: "log-level", key: "log.level", def: "info", usage: "minimum level of logged entries: debug, info, warn or error", live: true},
+	{field: "LogFormat", flag: "log-format", key: "log.format", def: logging.FormatConsole, usage: "log encoding: console or json"},
+	{field: "LogFile", flag: "log-file", key: "log.file", usage: "log filepath, rotated as it grows; empty writes to stderr"},
+	{field: "LogMaxSize", flag: "log-max-size", key: "log.max_size", def: logging.DefaultMaxSize, usage: "megabytes the log file grows to before it is rotated"},
+	{field: "LogMaxAge", flag: "log-max-age", key: "log.max_age", usage: "age rotated log files are removed after, in whole days; 0 keeps them"},
+	{field: "LogMaxBackups", flag: "log-max-backups", key: "log.max_backups", usage: "number of rotated log files kept; 0 keeps them all"},
+	{field: "LogSampleInitial", flag: "log-sample-initial", key: "log.sample_initial", usage: "access entries with the same message logged every second before sampling; 0 logs them all"},
+	{field: "LogSampleThereafter", flag: "log-sample-thereafter", key: "log.sample_thereafter", def: 100, usage: "one out of how many further access entries are logged within the second"},
+
+	{field: "ReloadInterval", flag: "config-reload-interval", key: "reload.interval", def: DefaultReloadInterval, usage: "period of the checks of the configuration file for changes; 0 reloads on SIGHUP only"},
+}
+
+// durationType is the type of the duration settings, set from strings such as "15m".
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// setDefaults sets every setting of c to its default.
+func setDefaults(c *Config) {
+	v := reflect.ValueOf(c).Elem()
+	for _, o := range options {
+		f := v.FieldByName(o.field)
+		if o.def == nil {
+			f.Set(reflect.Zero(f.Type()))
+			continue
+		}
+		f.Set(reflect.ValueOf(o.def).Convert(f.Type()))
+	}
+}
+
+// setString sets f from s, written as on the command line: lists and maps are comma-separated,
+// map entries are key=value pairs.
+func setString(f reflect.Value, s string) error {
+	switch {
+	case f.Type() == durationType:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", s)
+		}
+		f.SetInt(int64(d))
+	case f.Kind() == reflect.String:
+		f.SetString(s)
+	case f.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", s)
+		}
+		f.SetBool(b)
+	case f.Kind() == reflect.Int:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", s)
+		}
+		f.SetInt(int64(n))
+	case f.Kind() == reflect.Float64:
+		x, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", s)
+		}
+		f.SetFloat(x)
+	case f.Kind() == reflect.Slice:
+		var list []string
+		if s != "" {
+			list = strings.Split(s, ",")
+		}
+		f.Set(reflect.ValueOf(list))
+	case f.Kind() == reflect.Map:
+		pairs, err := parsePairs(s)
+		if err != nil {
+			return err
+		}
+		f.Set(reflect.ValueOf(pairs))
+	default:
+		return fmt.Errorf("unsupported setting type %s", f.Type())
+	}
+	return nil
+}
+
+// setValue sets f from x, a value decoded from a configuration file. Strings are accepted for every type,
+// as written on the command line, and durations must be strings.
+func setValue(f reflect.Value, x any) error {
+	if s, ok := x.(string); ok {
+		return setString(f, s)
+	}
+
+	switch {
+	case f.Type() == durationType:
+		return fmt.Errorf("want a duration such as \"15m\", got %v", x)
+	case f.Kind() == reflect.Bool:
+		b, ok := x.(bool)
+		if !ok {
+			return fmt.Errorf("want a boolean, got %v", x)
+		}
+		f.SetBool(b)
+	case f.Kind() == reflect.Int:
+		n, ok := integer(x)
+		if !ok {
+			return fmt.Errorf("want an integer, got %v", x)
+		}
+		f.SetInt(n)
+	case f.Kind() == reflect.Float64:
+		switch x := x.(type) {
+		case float64:
+			f.SetFloat(x)
+		case int:
+			f.SetFloat(float64(x))
+		case int64:
+			f.SetFloat(float64(x))
+		default:
+			return fmt.Errorf("want a number, got %v", x)
+		}
+	case f.Kind() == reflect.Slice:
+		items, ok := x.([]any)
+		if !ok {
+			return fmt.Errorf("want a list, got %v", x)
+		}
+		list := make([]string, 0, len(items))
+		for _, item := range items {
+			s, ok := item.(string)
+			if !ok {
+				return fmt.Errorf("want a list of strings, got %v", item)
+			}
+			list = append(list, s)
+		}
+		f.Set(reflect.ValueOf(list))
+	case f.Kind() == reflect.Map:
+		entries, ok := x.(map[string]any)
+		if !ok {
+			return fmt.Errorf("want a table of strings, got %v", x)
+		}
+		pairs := make(map[string]string, len(entries))
+		for k, v := range entries {
+			s, ok := v.(string)
+			if !ok {
+				return fmt.Errorf("want a string for %q, got %v", k, v)
+			}
+			pairs[k] = s
+		}
+		f.Set(reflect.ValueOf(pairs))
+	default:
+		return fmt.Errorf("want a string, got %v", x)
+	}
+	return nil
+}
+
+// integer returns x as an integer when it is one; JSON numbers are float64.
+func integer(x any) (int64, bool) {
+	switch x := x.(type) {
+	case int:
+		return int64(x), true
+	case int64:
+		return x, true
+	case uint64:
+		return int64(x), true //nolint:gosec // Settings are small numbers.
+	case float64:
+		if x != float64(int64(x)) {
+			return 0, false
+		}
+		return int64(x), true
+	}
+	return 0, false
+}
+
+// parsePairs parses comma-separated "key=value" pairs.
+func parsePairs(s string) (map[string]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+	pairs := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok || k == "" || v == "" {
+			return nil, fmt.Errorf("invalid pair %q, want key=value", pair)
+		}
+		pairs[k] = v
+	}
+	return pairs, nil
+}
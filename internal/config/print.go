@@ -0,0 +1,118 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Formats of printed configurations, the same as of configuration files.
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+	FormatTOML = "toml"
+)
+
+// Defaults returns the configuration of the defaults alone. It is not validated.
+func Defaults() *Config {
+	var c Config
+	setDefaults(&c)
+	return &c
+}
+
+// Print writes c in format as a configuration file with its sections, the secrets redacted.
+func Print(w io.Writer, c *Config, format string) error {
+	doc := document(c)
+
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(doc)
+	case FormatYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(doc); err != nil {
+			return err
+		}
+		return enc.Close()
+	case FormatTOML:
+		return toml.NewEncoder(w).Encode(doc)
+	default:
+		return fmt.Errorf("unsupported format %q: want json, yaml or toml", format)
+	}
+}
+
+// document returns the settings of c by section and key, as written in configuration files.
+func document(c *Config) map[string]map[string]any {
+	v := reflect.ValueOf(c).Elem()
+	doc := make(map[string]map[string]any)
+	for _, o := range options {
+		section, key, ok := strings.Cut(o.key, ".")
+		if !ok {
+			continue
+		}
+		if doc[section] == nil {
+			doc[section] = make(map[string]any)
+		}
+		doc[section][key] = printed(v.FieldByName(o.field).Interface(), o.secret)
+	}
+	return doc
+}
+
+// printed returns the value x of a setting as it is printed: durations as strings such as "15m0s",
+// empty lists and maps rather than nil ones, and secrets redacted.
+func printed(x any, secret redact) any {
+	switch x := x.(type) {
+	case time.Duration:
+		return x.String()
+	case string:
+		return redactString(x, secret)
+	case []string:
+		list := make([]string, 0, len(x))
+		for _, s := range x {
+			list = append(list, redactString(s, secret))
+		}
+		return list
+	case map[string]string:
+		pairs := make(map[string]string, len(x))
+		for k, s := range x {
+			pairs[k] = redactString(s, secret)
+		}
+		return pairs
+	}
+	return x
+}
+
+// dsnPassword matches the password of a key/value DSN such as "host=db password=secret".
+var dsnPassword = regexp.MustCompile(`(password=)(?:'[^']*'|\S+)`)
+
+// redactString hides s as secret tells; empty values are left as they are.
+func redactString(s string, secret redact) string {
+	if s == "" {
+		return s
+	}
+
+	switch secret {
+	case redactAll:
+		return redacted
+	case redactDSN:
+		if u, err := url.Parse(s); err == nil && u.User != nil {
+			if _, ok := u.User.Password(); ok {
+				u.User = url.UserPassword(u.User.Username(), redacted)
+				return u.String()
+			}
+			return s
+		}
+		return dsnPassword.ReplaceAllString(s, "${1}"+redacted)
+	}
+	return s
+}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"reflect"
+	"time"
+)
+
+// DefaultReloadInterval is how often the configuration file is checked for changes.
+const DefaultReloadInterval = 10 * time.Second
+
+// Update returns the configuration to run with once next is loaded while c runs: c with the settings
+// of next that are applied at runtime. It also returns the keys of the settings of next that changed:
+// applied ones, and the ones that only change with a restart and keep their running value.
+func (c *Config) Update(next *Config) (updated *Config, applied, restart []string) {
+	u := *c
+	from := reflect.ValueOf(next).Elem()
+	to := reflect.ValueOf(&u).Elem()
+
+	for _, o := range options {
+		if o.key == "" {
+			continue
+		}
+		f := to.FieldByName(o.field)
+		x := from.FieldByName(o.field)
+		if reflect.DeepEqual(f.Interface(), x.Interface()) {
+			continue
+		}
+		if !o.live {
+			restart = append(restart, o.key)
+			continue
+		}
+		f.Set(x)
+		applied = append(applied, o.key)
+	}
+	return &u, applied, restart
+}
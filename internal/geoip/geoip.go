@@ -0,0 +1,53 @@
+// Package geoip resolves client IP addresses to countries using a local MaxMind database file
+// (GeoLite2-Country, GeoIP2-Country or any database with country.iso_code records).
+package geoip
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/oschwald/maxminddb-golang"
+)
+
+// DB looks up countries in a MaxMind database. A nil DB knows no countries.
+type DB struct {
+	reader *maxminddb.Reader
+}
+
+// record is the part of a country record DB reads.
+type record struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+}
+
+// Open opens the MaxMind database at path.
+func Open(path string) (*DB, error) {
+	reader, err := maxminddb.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open GeoIP database: %w", err)
+	}
+	return &DB{reader: reader}, nil
+}
+
+// Country returns the ISO 3166-1 alpha-2 code of the country ip is located in,
+// or an empty string when the database does not know the address.
+func (db *DB) Country(ip net.IP) string {
+	if db == nil || ip == nil {
+		return ""
+	}
+
+	var r record
+	if err := db.reader.Lookup(ip, &r); err != nil {
+		return ""
+	}
+	return r.Country.ISOCode
+}
+
+// Close releases the database file.
+func (db *DB) Close() error {
+	if db == nil {
+		return nil
+	}
+	return db.reader.Close()
+}
@@ -0,0 +1,48 @@
+package geoip
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testdata/country.mmdb maps 192.0.2.0/24 to DE, 198.51.100.0/24 to US and 2001:db8::/32 to FR.
+func TestDB_Country(t *testing.T) {
+	db, err := Open("testdata/country.mmdb")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, db.Close())
+	}()
+
+	tests := []struct {
+		name string
+		ip   string
+		want string
+	}{
+		{name: "IPv4", ip: "192.0.2.10", want: "DE"},
+		{name: "other network", ip: "198.51.100.1", want: "US"},
+		{name: "IPv6", ip: "2001:db8::1", want: "FR"},
+		{name: "unknown address", ip: "203.0.113.5", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, db.Country(net.ParseIP(tt.ip)))
+		})
+	}
+
+	assert.Empty(t, db.Country(nil))
+}
+
+func TestDB_Nil(t *testing.T) {
+	var db *DB
+	assert.Empty(t, db.Country(net.ParseIP("192.0.2.10")))
+	assert.NoError(t, db.Close())
+}
+
+func TestOpen_Missing(t *testing.T) {
+	_, err := Open("testdata/missing.mmdb")
+	assert.Error(t, err)
+}
@@ -0,0 +1,272 @@
+// Package health reports whether the service is alive and ready to take traffic, over the standard
+// gRPC health checking protocol and the HTTP /livez and /readyz probes.
+//
+// The service is ready once the storage is initialized and answers Ping, and until shutdown begins.
+// While it starts, and while the database is migrated, requests other than probes are turned away.
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/apetsko/shortugo/internal/logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// Paths of the HTTP probes.
+const (
+	LivezPath  = "/livez"
+	ReadyzPath = "/readyz"
+)
+
+// DefaultInterval is how often the storage is pinged; DefaultDrain is how long readiness is reported
+// as NOT_SERVING before the servers stop, so load balancers stop sending requests first.
+const (
+	DefaultInterval = 5 * time.Second
+	DefaultDrain    = 5 * time.Second
+)
+
+// Reasons the service is not ready.
+var (
+	ErrStarting  = errors.New("starting")
+	ErrMigrating = errors.New("migrating the database")
+	ErrDraining  = errors.New("shutting down")
+)
+
+// phase is the stage of the life of the service.
+type phase int
+
+const (
+	phaseStarting phase = iota
+	phaseMigrating
+	phaseStarted
+	phaseDraining
+)
+
+// Checker keeps the readiness of the service and reports it to gRPC health clients and HTTP probes.
+// Its zero value is not usable; create one with New.
+type Checker struct {
+	logger   *logging.Logger
+	server   *grpchealth.Server
+	started  atomic.Bool // Set once the storage is ready; requests are turned away before.
+	mu       sync.Mutex
+	phase    phase
+	ping     func() error
+	pingErr  error
+	services []string
+}
+
+// New returns a Checker of a starting service: not ready until Start is called.
+func New(logger *logging.Logger) *Checker {
+	c := &Checker{logger: logger, server: grpchealth.NewServer()}
+	c.update()
+	return c
+}
+
+// Register adds the grpc.health.v1.Health service to s. The services already registered on s get
+// their own status, which follows the readiness of the service; register them first.
+func (c *Checker) Register(s *grpc.Server) {
+	c.mu.Lock()
+	for name := range s.GetServiceInfo() {
+		if !internal(name) {
+			c.services = append(c.services, name)
+		}
+	}
+	c.mu.Unlock()
+
+	healthpb.RegisterHealthServer(s, c.server)
+	c.update()
+}
+
+// Migrating reports that database migrations started or ended. It is passed to the storage,
+// which calls it around the migrations it runs.
+func (c *Checker) Migrating(running bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch {
+	case running && c.phase == phaseStarting:
+		c.phase = phaseMigrating
+	case !running && c.phase == phaseMigrating:
+		c.phase = phaseStarting
+	default:
+		return
+	}
+	c.updateLocked()
+}
+
+// Start reports that the storage is ready: requests are let through and the service is ready as long
+// as ping succeeds. ping is called now and then every interval until ctx is done.
+func (c *Checker) Start(ctx context.Context, ping func() error, interval time.Duration) {
+	c.mu.Lock()
+	c.ping = ping
+	if c.phase != phaseDraining {
+		c.phase = phaseStarted
+	}
+	c.mu.Unlock()
+	c.started.Store(true)
+
+	c.Check()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				c.Check()
+			}
+		}
+	}()
+}
+
+// Check pings the storage and updates the readiness. It does nothing before Start.
+func (c *Checker) Check() {
+	c.mu.Lock()
+	ping := c.ping
+	c.mu.Unlock()
+	if ping == nil {
+		return
+	}
+
+	err := ping()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch {
+	case err != nil && c.pingErr == nil:
+		c.logger.Error("Storage is unavailable, the service is not ready", "error", err.Error())
+	case err == nil && c.pingErr != nil:
+		c.logger.Info("Storage is available again, the service is ready")
+	}
+	c.pingErr = err
+	c.updateLocked()
+}
+
+// Drain reports that shutdown began: the service is not ready from now on, while requests are still
+// served until the servers stop.
+func (c *Checker) Drain() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.phase = phaseDraining
+	c.updateLocked()
+	// Later updates are ignored by the server
+	c.server.Shutdown()
+}
+
+// Ready returns nil when the service is ready, and the reason otherwise.
+func (c *Checker) Ready() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.readyLocked()
+}
+
+func (c *Checker) readyLocked() error {
+	switch c.phase {
+	case phaseStarting:
+		return ErrStarting
+	case phaseMigrating:
+		return ErrMigrating
+	case phaseDraining:
+		return ErrDraining
+	}
+	if c.pingErr != nil {
+		return fmt.Errorf("storage unavailable: %w", c.pingErr)
+	}
+	return nil
+}
+
+func (c *Checker) update() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.updateLocked()
+}
+
+// updateLocked sets the status of the whole server and of each service from the readiness.
+// Every service needs the storage, so they share its status.
+func (c *Checker) updateLocked() {
+	status := healthpb.HealthCheckResponse_SERVING
+	if c.readyLocked() != nil {
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+
+	c.server.SetServingStatus("", status)
+	for _, name := range c.services {
+		c.server.SetServingStatus(name, status)
+	}
+}
+
+// Livez answers 200 as long as the process serves HTTP requests.
+func (c *Checker) Livez(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = w.Write([]byte("ok\n"))
+}
+
+// Readyz answers 200 when the service is ready and 503 with the reason otherwise.
+func (c *Checker) Readyz(w http.ResponseWriter, _ *http.Request) {
+	if err := c.Ready(); err != nil {
+		http.Error(w, "not ready: "+err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = w.Write([]byte("ok\n"))
+}
+
+// Middleware answers 503 to the requests other than probes until Start is called.
+func (c *Checker) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !c.started.Load() && r.URL.Path != LivezPath && r.URL.Path != ReadyzPath {
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "Service Unavailable: starting", http.StatusServiceUnavailable)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// UnaryServerInterceptor answers codes.Unavailable to the calls of the services of the application
+// until Start is called. Health checks and reflection are always served.
+func (c *Checker) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := c.gate(info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor is the streaming counterpart of UnaryServerInterceptor.
+func (c *Checker) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := c.gate(info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// gate returns the error of a call of method made before Start, nil when the call may go on.
+func (c *Checker) gate(method string) error {
+	if c.started.Load() || internal(strings.TrimPrefix(method, "/")) {
+		return nil
+	}
+	return status.Error(codes.Unavailable, "service is starting")
+}
+
+// internal reports whether a gRPC service or method name belongs to gRPC itself,
+// such as grpc.health.v1.Health and grpc.reflection.v1.ServerReflection.
+func internal(name string) bool {
+	return strings.HasPrefix(name, "grpc.")
+}
@@ -0,0 +1,214 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/apetsko/shortugo/internal/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// testService is the name of an application service registered next to the health service.
+const testService = "shortugo.Test"
+
+// newChecker returns a Checker serving grpc.health.v1.Health and testService,
+// and a client of its health service.
+func newChecker(t *testing.T) (*Checker, healthpb.HealthClient) {
+	t.Helper()
+
+	logger, _ := logging.New(zapcore.DebugLevel)
+	c := New(logger)
+
+	lis := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer()
+	srv.RegisterService(&grpc.ServiceDesc{ServiceName: testService, HandlerType: (*any)(nil)}, struct{}{})
+	c.Register(srv)
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return c, healthpb.NewHealthClient(conn)
+}
+
+// statuses returns the status of the whole server and of testService.
+func statuses(t *testing.T, client healthpb.HealthClient) [2]healthpb.HealthCheckResponse_ServingStatus {
+	t.Helper()
+
+	var got [2]healthpb.HealthCheckResponse_ServingStatus
+	for i, service := range []string{"", testService} {
+		resp, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		require.NoError(t, err)
+		got[i] = resp.GetStatus()
+	}
+	return got
+}
+
+// probe returns the status and body of a GET of path through the middleware and the probes.
+func probe(c *Checker, path string) (int, string) {
+	mux := http.NewServeMux()
+	mux.HandleFunc(LivezPath, c.Livez)
+	mux.HandleFunc(ReadyzPath, c.Readyz)
+	mux.HandleFunc("/", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("app\n"))
+	})
+
+	w := httptest.NewRecorder()
+	c.Middleware(mux).ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w.Code, w.Body.String()
+}
+
+func TestChecker_Lifecycle(t *testing.T) {
+	c, client := newChecker(t)
+	serving := [2]healthpb.HealthCheckResponse_ServingStatus{healthpb.HealthCheckResponse_SERVING, healthpb.HealthCheckResponse_SERVING}
+	notServing := [2]healthpb.HealthCheckResponse_ServingStatus{healthpb.HealthCheckResponse_NOT_SERVING, healthpb.HealthCheckResponse_NOT_SERVING}
+
+	// Starting: alive but not ready, and requests are turned away
+	assert.ErrorIs(t, c.Ready(), ErrStarting)
+	assert.Equal(t, notServing, statuses(t, client))
+	code, _ := probe(c, LivezPath)
+	assert.Equal(t, http.StatusOK, code)
+	code, body := probe(c, ReadyzPath)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "not ready: starting\n", body)
+	code, _ = probe(c, "/abc123")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+
+	// Migrating
+	c.Migrating(true)
+	assert.ErrorIs(t, c.Ready(), ErrMigrating)
+	assert.Equal(t, notServing, statuses(t, client))
+	_, body = probe(c, ReadyzPath)
+	assert.Equal(t, "not ready: migrating the database\n", body)
+	c.Migrating(false)
+	assert.ErrorIs(t, c.Ready(), ErrStarting)
+
+	// Started: ready while the storage answers; checks are only made by Start and Check here
+	var pingErr error
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c.Start(ctx, func() error { return pingErr }, time.Hour)
+	require.NoError(t, c.Ready())
+	assert.Equal(t, serving, statuses(t, client))
+	code, body = probe(c, ReadyzPath)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "ok\n", body)
+	code, body = probe(c, "/abc123")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "app\n", body)
+
+	// Storage down and up again
+	pingErr = errors.New("connection refused")
+	c.Check()
+	assert.EqualError(t, c.Ready(), "storage unavailable: connection refused")
+	assert.Equal(t, notServing, statuses(t, client))
+	code, _ = probe(c, "/abc123")
+	assert.Equal(t, http.StatusOK, code, "requests still reach the handlers")
+
+	pingErr = nil
+	c.Check()
+	require.NoError(t, c.Ready())
+	assert.Equal(t, serving, statuses(t, client))
+
+	// Draining: not ready for good, requests are still served
+	c.Drain()
+	assert.ErrorIs(t, c.Ready(), ErrDraining)
+	assert.Equal(t, notServing, statuses(t, client))
+	c.Check()
+	assert.Equal(t, notServing, statuses(t, client))
+	code, _ = probe(c, ReadyzPath)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	code, _ = probe(c, "/abc123")
+	assert.Equal(t, http.StatusOK, code)
+}
+
+func TestChecker_Periodic(t *testing.T) {
+	c, client := newChecker(t)
+
+	var down atomic.Bool
+	ping := func() error {
+		if down.Load() {
+			return errors.New("connection refused")
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c.Start(ctx, ping, 10*time.Millisecond)
+	require.NoError(t, c.Ready())
+
+	// Watchers are told of the change without further calls
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{Service: testService})
+	require.NoError(t, err)
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+
+	down.Store(true)
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.GetStatus())
+}
+
+func TestChecker_UnknownService(t *testing.T) {
+	_, client := newChecker(t)
+
+	_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "shortugo.Unknown"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestChecker_Interceptors(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+	c := New(logger)
+
+	unary := c.UnaryServerInterceptor()
+	stream := c.StreamServerInterceptor()
+	call := func(method string) (error, error) {
+		_, unaryErr := unary(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: method},
+			func(context.Context, any) (any, error) { return nil, nil })
+		streamErr := stream(nil, nil, &grpc.StreamServerInfo{FullMethod: method},
+			func(any, grpc.ServerStream) error { return nil })
+		return unaryErr, streamErr
+	}
+
+	// Before Start only gRPC's own services are served
+	unaryErr, streamErr := call("/shortugo.URLShortener/Ping")
+	assert.Equal(t, codes.Unavailable, status.Code(unaryErr))
+	assert.Equal(t, codes.Unavailable, status.Code(streamErr))
+	unaryErr, streamErr = call("/grpc.health.v1.Health/Check")
+	assert.NoError(t, unaryErr)
+	assert.NoError(t, streamErr)
+	unaryErr, streamErr = call("/grpc.reflection.v1.ServerReflection/ServerReflectionInfo")
+	assert.NoError(t, unaryErr)
+	assert.NoError(t, streamErr)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c.Start(ctx, func() error { return nil }, time.Hour)
+
+	unaryErr, streamErr = call("/shortugo.URLShortener/Ping")
+	assert.NoError(t, unaryErr)
+	assert.NoError(t, streamErr)
+}
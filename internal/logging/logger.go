@@ -1,17 +1,22 @@
 // Package logging provides structured logging functionality for the application.
 // It wraps the zap.SugaredLogger to enable easy and efficient logging with support
-// for different log levels and structured log entries.
+// for different log levels and structured log entries, written as console lines or JSON
+// to stderr or to a rotated file. Access entries may be sampled apart from the others.
 package logging
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"os"
 	"time"
 
 	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/logging"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 // LogEntry defines the interface for log entries.
@@ -22,31 +27,120 @@ type LogEntry interface {
 	Panic(v interface{}, stack []byte)
 }
 
+// Encodings of log entries.
+const (
+	FormatConsole = "console" // Colored lines for people, or plain ones in a file.
+	FormatJSON    = "json"    // One JSON object per line, for log shippers.
+)
+
+// DefaultMaxSize is the size in megabytes a log file grows to before it is rotated.
+const DefaultMaxSize = 100
+
+// Options configure a Logger built by NewWithOptions.
+type Options struct {
+	Format           string        // FormatConsole or FormatJSON; empty is FormatConsole.
+	File             string        // File entries are written to, rotated as it grows; empty writes to stderr.
+	MaxSize          int           // Megabytes File grows to before it is rotated; 0 is DefaultMaxSize.
+	MaxAge           time.Duration // Age rotated files are removed after, in whole days; 0 keeps them.
+	MaxBackups       int           // Number of rotated files kept; 0 keeps them all.
+	SampleInitial    int           // Access entries with the same message logged every second; 0 logs them all.
+	SampleThereafter int           // Then one out of SampleThereafter is logged within the second; 0 drops the rest.
+	Level            zapcore.Level // Minimum level of the entries logged.
+}
+
 // Logger wraps the zap.SugaredLogger to provide structured logging.
 type Logger struct {
 	*zap.SugaredLogger
+	level  *zap.AtomicLevel // Level of the logger, changed while it runs; nil when it was not built by New or NewWithOptions.
+	access *Logger          // Sampled logger of access entries; nil logs them all.
+	file   io.Closer        // Rotated log file; nil when entries go to stderr.
 }
 
-// New creates a new Logger instance with the specified log level.
+// New creates a new Logger instance with the specified log level, writing colored lines to stderr.
 func New(level zapcore.Level) (*Logger, error) {
-	// Configure the logger with development settings.
-	config := zap.NewDevelopmentConfig()
-	config.EncoderConfig.TimeKey = "timestamp"
-	config.EncoderConfig.StacktraceKey = ""
-	config.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
-	config.Level.SetLevel(level)
+	return NewWithOptions(Options{Level: level})
+}
+
+// NewWithOptions creates a Logger configured by o.
+func NewWithOptions(o Options) (*Logger, error) {
+	level := zap.NewAtomicLevelAt(o.Level)
+
+	var encoder zapcore.Encoder
+	switch o.Format {
+	case FormatConsole, "":
+		config := zap.NewDevelopmentEncoderConfig()
+		config.TimeKey = "timestamp"
+		config.StacktraceKey = ""
+		config.EncodeLevel = zapcore.CapitalColorLevelEncoder
+		if o.File != "" {
+			config.EncodeLevel = zapcore.CapitalLevelEncoder
+		}
+		encoder = zapcore.NewConsoleEncoder(config)
+	case FormatJSON:
+		config := zap.NewProductionEncoderConfig()
+		config.TimeKey = "timestamp"
+		config.StacktraceKey = ""
+		config.EncodeTime = zapcore.ISO8601TimeEncoder
+		config.EncodeDuration = zapcore.StringDurationEncoder
+		encoder = zapcore.NewJSONEncoder(config)
+	default:
+		return nil, fmt.Errorf("unsupported log format %q: want console or json", o.Format)
+	}
+
+	l := &Logger{level: &level}
+	output := zapcore.Lock(os.Stderr)
+	if o.File != "" {
+		maxSize := o.MaxSize
+		if maxSize <= 0 {
+			maxSize = DefaultMaxSize
+		}
+		file := &lumberjack.Logger{
+			Filename:   o.File,
+			MaxSize:    maxSize,
+			MaxAge:     int((o.MaxAge + 24*time.Hour - 1) / (24 * time.Hour)),
+			MaxBackups: o.MaxBackups,
+		}
+		l.file = file
+		output = zapcore.AddSync(file)
+	}
 
 	// Build the logger with caller information.
-	logger, err := config.Build(zap.AddCaller(), zap.AddCallerSkip(1))
-	if err != nil {
-		return nil, err
+	core := zapcore.NewCore(encoder, output, level)
+	opts := []zap.Option{zap.AddCaller(), zap.AddCallerSkip(1), zap.ErrorOutput(zapcore.Lock(os.Stderr))}
+	l.SugaredLogger = zap.New(core, opts...).Sugar()
+
+	// Access entries of the same message are sampled per second; the level is shared
+	if o.SampleInitial > 0 {
+		sampled := zapcore.NewSamplerWithOptions(core, time.Second, o.SampleInitial, o.SampleThereafter)
+		l.access = &Logger{SugaredLogger: zap.New(sampled, opts...).Sugar(), level: &level}
+	}
+	return l, nil
+}
+
+// Access returns the logger of access entries, one per request or call, sampled as the Options tell.
+func (l *Logger) Access() *Logger {
+	if l == nil || l.access == nil {
+		return l
 	}
-	return &Logger{logger.Sugar()}, nil
+	return l.access
 }
 
-// Close syncs the logger, flushing any buffered log entries.
+// SetLevel changes the minimum level of the entries logged, at once for every use of the logger.
+// Loggers not built by New or NewWithOptions keep their level.
+func (l *Logger) SetLevel(level zapcore.Level) {
+	if l.level == nil {
+		return
+	}
+	l.level.SetLevel(level)
+}
+
+// Close syncs the logger, flushing any buffered log entries, and closes its log file.
 func (l *Logger) Close() error {
-	return l.Sync()
+	err := l.Sync()
+	if l.file != nil {
+		err = errors.Join(err, l.file.Close())
+	}
+	return err
 }
 
 // Debug logs a debug message with additional context.
@@ -74,7 +168,9 @@ func (l *Logger) Printf(format string, v ...interface{}) {
 	l.Infof(format, v...)
 }
 
-func InterceptorLogger(l *zap.Logger) logging.Logger {
+// InterceptorLogger adapts l to the logging interceptors of go-grpc-middleware.
+func InterceptorLogger(l *Logger) logging.Logger {
+	zl := l.Desugar()
 	return logging.LoggerFunc(func(ctx context.Context, lvl logging.Level, msg string, fields ...any) {
 		f := make([]zap.Field, 0, len(fields)/2)
 
@@ -94,7 +190,7 @@ func InterceptorLogger(l *zap.Logger) logging.Logger {
 			}
 		}
 
-		logger := l.WithOptions(zap.AddCallerSkip(1)).With(f...)
+		logger := zl.WithOptions(zap.AddCallerSkip(1)).With(f...)
 
 		switch lvl {
 		case logging.LevelDebug:
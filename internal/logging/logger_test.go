@@ -1,6 +1,10 @@
 package logging
 
 import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"go.uber.org/zap/zapcore"
@@ -25,3 +29,94 @@ func TestLogger_Methods(t *testing.T) {
 		})
 	}
 }
+
+func TestLogger_SetLevel(t *testing.T) {
+	logger, err := New(zapcore.DebugLevel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !logger.Desugar().Core().Enabled(zapcore.DebugLevel) {
+		t.Fatal("debug entries are not logged at the debug level")
+	}
+
+	logger.SetLevel(zapcore.WarnLevel)
+	if logger.Desugar().Core().Enabled(zapcore.InfoLevel) {
+		t.Error("info entries are logged at the warn level")
+	}
+	if !logger.Desugar().Core().Enabled(zapcore.ErrorLevel) {
+		t.Error("error entries are not logged at the warn level")
+	}
+
+	// Loggers built otherwise are left as they are
+	(&Logger{SugaredLogger: logger.SugaredLogger}).SetLevel(zapcore.DebugLevel)
+}
+
+func TestNewWithOptions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shortugo.log")
+	logger, err := NewWithOptions(Options{
+		Format:           FormatJSON,
+		File:             path,
+		Level:            zapcore.InfoLevel,
+		SampleInitial:    2,
+		SampleThereafter: 0,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger.Debug("hidden")
+	logger.Info("started", "port", 8080)
+	for i := 0; i < 5; i++ {
+		logger.Access().Info("GET", "uri", "/abc")
+		logger.Info("kept")
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = file.Close() }()
+
+	counts := make(map[string]int)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("line %q is not JSON: %v", scanner.Text(), err)
+		}
+		if entry["level"] != "info" || entry["timestamp"] == nil || entry["caller"] == nil {
+			t.Errorf("unexpected entry %v", entry)
+		}
+		counts[entry["msg"].(string)]++
+	}
+
+	want := map[string]int{"started": 1, "GET": 2, "kept": 5}
+	for msg, n := range want {
+		if counts[msg] != n {
+			t.Errorf("%q logged %d times, want %d", msg, counts[msg], n)
+		}
+	}
+	if counts["hidden"] != 0 {
+		t.Error("debug entry logged at the info level")
+	}
+}
+
+func TestNewWithOptions_Format(t *testing.T) {
+	if _, err := NewWithOptions(Options{Format: "xml"}); err == nil {
+		t.Error("unsupported format accepted")
+	}
+
+	logger, err := NewWithOptions(Options{Format: FormatConsole, Level: zapcore.WarnLevel})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if logger.Access() != logger {
+		t.Error("access entries are sampled without SampleInitial")
+	}
+	if logger.Desugar().Core().Enabled(zapcore.InfoLevel) {
+		t.Error("info entries are logged at the warn level")
+	}
+}
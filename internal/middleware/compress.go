@@ -21,27 +21,58 @@ var gzipReaderPool = sync.Pool{
 }
 
 // bufferedResponseWriter buffers the response.
+// Responses of a type that is not compressed are passed through as they are written,
+// so streamed downloads are never held in memory.
 type bufferedResponseWriter struct {
 	http.ResponseWriter
-	buffer     *bytes.Buffer // Buffer to store the response body.
-	statusCode int           // HTTP status code.
+	buffer      *bytes.Buffer // Buffer to store the response body.
+	statusCode  int           // HTTP status code.
+	passthrough bool          // The response is written directly to the ResponseWriter.
 }
 
 // WriteHeader sets the status code if it has not been set yet.
 func (w *bufferedResponseWriter) WriteHeader(code int) {
-	if w.statusCode == 0 {
-		w.statusCode = code
+	if w.statusCode != 0 {
+		return
+	}
+	w.statusCode = code
+	if !compressible(w.Header().Get("Content-Type")) {
+		w.passthrough = true
+		w.ResponseWriter.WriteHeader(code)
 	}
 }
 
 // Write sets the status code to http.StatusOK if it has not been set yet and writes the data to the buffer.
 func (w *bufferedResponseWriter) Write(b []byte) (int, error) {
 	if w.statusCode == 0 {
-		w.statusCode = http.StatusOK
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.passthrough {
+		return w.ResponseWriter.Write(b)
 	}
 	return w.buffer.Write(b)
 }
 
+// Flush sends the data written so far to the client when the response is passed through.
+func (w *bufferedResponseWriter) Flush() {
+	if !w.passthrough {
+		return
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap returns the wrapped ResponseWriter.
+func (w *bufferedResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// compressible reports whether responses of the content type are gzipped.
+func compressible(contentType string) bool {
+	return strings.Contains(contentType, "application/json") || strings.Contains(contentType, "text/html")
+}
+
 // compressReader wraps the gzip.Reader to decompress the request body.
 type compressReader struct {
 	io.ReadCloser
@@ -107,10 +138,16 @@ func GzipMiddleware(logger *logging.Logger) func(http.Handler) http.Handler {
 
 			// Execute the request
 			next.ServeHTTP(bufferedWriter, r)
+			if bufferedWriter.passthrough {
+				return
+			}
+			if bufferedWriter.statusCode == 0 {
+				bufferedWriter.statusCode = http.StatusOK
+			}
 
 			// Determine if compression is needed
 			contentType := bufferedWriter.ResponseWriter.Header().Get("Content-Type")
-			if strings.Contains(contentType, "application/json") || strings.Contains(contentType, "text/html") {
+			if compressible(contentType) {
 				bufferedWriter.ResponseWriter.Header().Set("Content-Encoding", "gzip")
 				bufferedWriter.ResponseWriter.WriteHeader(bufferedWriter.statusCode)
 
@@ -106,3 +106,31 @@ func TestGzipMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestGzipMiddleware_StreamsUncompressed(t *testing.T) {
+	logger, err := logging.New(zapcore.DebugLevel)
+	require.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/csv")
+		_, err := w.Write([]byte("a,b\n"))
+		require.NoError(t, err)
+		require.NoError(t, http.NewResponseController(w).Flush())
+
+		// The first rows reach the client before the handler is done
+		assert.Equal(t, "a,b\n", rr.Body.String())
+		assert.True(t, rr.Flushed)
+
+		_, err = w.Write([]byte("c,d\n"))
+		require.NoError(t, err)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	GzipMiddleware(logger)(handler).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, rr.Header().Get("Content-Encoding"))
+	assert.Equal(t, "a,b\nc,d\n", rr.Body.String())
+}
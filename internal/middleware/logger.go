@@ -2,8 +2,11 @@
 package middleware
 
 import (
+	"net"
 	"net/http"
 	"time"
+
+	"github.com/apetsko/shortugo/internal/requestid"
 )
 
 // logger defines the interface for logging.
@@ -45,8 +48,14 @@ func (r *logResponseWriter) WriteHeader(statusCode int) {
 	r.responseData.status = statusCode
 }
 
-// LogMiddleware logs the details of each HTTP request and response.
-func LogMiddleware(logger logger) func(http.Handler) http.Handler {
+// Unwrap returns the wrapped ResponseWriter, so http.ResponseController can flush streamed responses.
+func (r *logResponseWriter) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// LogMiddleware logs the details of each HTTP request and response, with the same fields for every request.
+// userID returns the ID of the user who made the request, or "" for anonymous requests; nil logs no user.
+func LogMiddleware(logger logger, userID func(r *http.Request) string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			start := time.Now()
@@ -55,16 +64,30 @@ func LogMiddleware(logger logger) func(http.Handler) http.Handler {
 			lw := newLogResponseWriter(w)
 			next.ServeHTTP(lw, r)
 
+			var user string
+			if userID != nil {
+				user = userID(r)
+			}
+
 			// Log the request and response details.
 			logger.Info(
 				r.Method,
 				"uri", r.RequestURI,
-				"UserID", r.Header.Get("UserID"),
-				"duration_ms", time.Since(start).Milliseconds(),
-				"size", lw.responseData.size,
 				"status", lw.responseData.status,
-				"IP", r.RemoteAddr,
+				"size", lw.responseData.size,
+				"duration_ms", time.Since(start).Milliseconds(),
+				"ip", clientIP(r),
+				"user_id", user,
+				"request_id", requestid.FromContext(r.Context()),
 			)
 		})
 	}
 }
+
+// clientIP returns the IP address of the client of r, without the port of its remote address.
+func clientIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
@@ -28,9 +28,21 @@ func TestLogMiddleware(t *testing.T) {
 		assert.Contains(t, fields, "/test")
 		assert.Contains(t, fields, "status")
 		assert.Contains(t, fields, 200)
+		assert.Contains(t, fields, "request_id")
+		assert.Contains(t, fields, "user_id")
+		assert.Contains(t, fields, "12345")
+		assert.Contains(t, fields, "ip")
+		assert.Contains(t, fields, "192.0.2.1")
 	}).Once()
 
-	middleware := LogMiddleware(mockLogger)
+	userID := func(r *http.Request) string {
+		c, err := r.Cookie("shortugo")
+		if err != nil {
+			return ""
+		}
+		return c.Value
+	}
+	middleware := LogMiddleware(mockLogger, userID)
 
 	handler := middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -38,7 +50,7 @@ func TestLogMiddleware(t *testing.T) {
 	}))
 
 	req := httptest.NewRequest("GET", "/test", nil)
-	req.Header.Set("UserID", "12345")
+	req.AddCookie(&http.Cookie{Name: "shortugo", Value: "12345"})
 	w := httptest.NewRecorder()
 
 	handler.ServeHTTP(w, req)
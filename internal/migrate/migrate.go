@@ -0,0 +1,234 @@
+// Package migrate copies the links of one storage to another, e.g. from the file storage to PostgreSQL.
+// Records are walked in ID order and written in batches, keeping their owner, deleted flag, click counter,
+// creation time, labels and options. A Checkpoint remembers the last copied ID, so an interrupted
+// migration resumes where it stopped. Summarize describes a storage by counts and a checksum of its records,
+// so the source and the destination of a migration can be compared.
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/apetsko/shortugo/internal/models"
+	"github.com/apetsko/shortugo/internal/storages/shared"
+)
+
+// DefaultBatchSize is the number of records written by one PutBatch call when Options.BatchSize is not set.
+const DefaultBatchSize = 500
+
+// Storage is a storage links are migrated from or to. Every storage of the application implements it.
+type Storage interface {
+	// ForEachRecord calls fn for every stored link, deleted ones included, in ID order starting after the ID after.
+	ForEachRecord(ctx context.Context, after string, fn func(r models.URLRecord) error) error
+	// PutBatch stores a batch of URL records.
+	PutBatch(ctx context.Context, rr []models.URLRecord) error
+	// GetUTMTemplate retrieves the default UTM tags of a user, or shared.ErrNotFound when none are set.
+	GetUTMTemplate(ctx context.Context, userID string) (*models.UTM, error)
+	// PutUTMTemplate replaces the default UTM tags of a user.
+	PutUTMTemplate(ctx context.Context, userID string, t models.UTM) error
+}
+
+// Options tune Copy.
+type Options struct {
+	BatchSize  int                             // Records per PutBatch call; DefaultBatchSize when zero.
+	Checkpoint *Checkpoint                     // Where the progress is kept; nil always starts from the first record.
+	Progress   func(copied int, lastID string) // Called after every stored batch, if set.
+}
+
+// Copy copies the records of src to dst, starting after the ID saved in the checkpoint, and then the
+// UTM templates of their owners. It returns the number of records copied. The checkpoint is saved after
+// every batch and removed when the copy is complete, so after an error Copy can be run again to resume.
+func Copy(ctx context.Context, src, dst Storage, opts Options) (int, error) {
+	size := opts.BatchSize
+	if size <= 0 {
+		size = DefaultBatchSize
+	}
+
+	after := ""
+	if opts.Checkpoint != nil {
+		var err error
+		if after, err = opts.Checkpoint.Load(); err != nil {
+			return 0, err
+		}
+	}
+
+	copied := 0
+	batch := make([]models.URLRecord, 0, size)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := dst.PutBatch(ctx, batch); err != nil {
+			return fmt.Errorf("failed to store records after %q: %w", after, err)
+		}
+
+		copied += len(batch)
+		after = batch[len(batch)-1].ID
+		batch = batch[:0]
+		if opts.Checkpoint != nil {
+			if err := opts.Checkpoint.Save(after); err != nil {
+				return err
+			}
+		}
+		if opts.Progress != nil {
+			opts.Progress(copied, after)
+		}
+		return nil
+	}
+
+	err := src.ForEachRecord(ctx, after, func(r models.URLRecord) error {
+		batch = append(batch, normalize(r))
+		if len(batch) < size {
+			return nil
+		}
+		return flush()
+	})
+	if err == nil {
+		err = flush()
+	}
+	if err != nil {
+		return copied, err
+	}
+
+	if err := copyTemplates(ctx, src, dst); err != nil {
+		return copied, err
+	}
+
+	if opts.Checkpoint != nil {
+		return copied, opts.Checkpoint.Remove()
+	}
+	return copied, nil
+}
+
+// copyTemplates copies the UTM templates of the owners of the links in src.
+func copyTemplates(ctx context.Context, src, dst Storage) error {
+	users := make(map[string]bool)
+	err := src.ForEachRecord(ctx, "", func(r models.URLRecord) error {
+		users[r.UserID] = true
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for userID := range users {
+		t, err := src.GetUTMTemplate(ctx, userID)
+		if errors.Is(err, shared.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get UTM template of user %q: %w", userID, err)
+		}
+		if err := dst.PutUTMTemplate(ctx, userID, *t); err != nil {
+			return fmt.Errorf("failed to store UTM template of user %q: %w", userID, err)
+		}
+	}
+	return nil
+}
+
+// Summary describes the links of a storage.
+type Summary struct {
+	Links    int    // Stored links, deleted ones included.
+	Deleted  int    // Deleted links.
+	Users    int    // Owners of the links.
+	Checksum string // Hex SHA-256 of the normalized records in ID order.
+}
+
+// Summarize walks the records of s and describes them. Storages holding the same links have equal summaries.
+func Summarize(ctx context.Context, s Storage) (Summary, error) {
+	var sum Summary
+	users := make(map[string]bool)
+	hash := sha256.New()
+
+	err := s.ForEachRecord(ctx, "", func(r models.URLRecord) error {
+		line, err := json.Marshal(normalize(r))
+		if err != nil {
+			return fmt.Errorf("failed to marshal record %q: %w", r.ID, err)
+		}
+		hash.Write(append(line, '\n'))
+
+		sum.Links++
+		if r.Deleted {
+			sum.Deleted++
+		}
+		users[r.UserID] = true
+		return nil
+	})
+	if err != nil {
+		return sum, err
+	}
+
+	sum.Users = len(users)
+	sum.Checksum = hex.EncodeToString(hash.Sum(nil))
+	return sum, nil
+}
+
+// unknownCreatedAt is the creation time of links stored before the time was kept.
+var unknownCreatedAt = time.Unix(0, 0).UTC()
+
+// normalize returns r the way every storage keeps it: times in UTC to the microsecond, sorted tags,
+// and no click counter for links without a click limit. Links without a creation time get unknownCreatedAt,
+// as the destination would otherwise stamp them with the time of the migration.
+func normalize(r models.URLRecord) models.URLRecord {
+	if r.CreatedAt.IsZero() {
+		r.CreatedAt = unknownCreatedAt
+	}
+	r.CreatedAt = r.CreatedAt.UTC().Truncate(time.Microsecond)
+
+	if r.MaxClicks == 0 {
+		r.ClicksLeft = 0
+	}
+
+	if len(r.Tags) == 0 {
+		r.Tags = nil
+	} else {
+		r.Tags = slices.Sorted(slices.Values(r.Tags))
+	}
+	return r
+}
+
+// Checkpoint keeps the ID of the last record copied by a migration in a file.
+type Checkpoint struct {
+	Path string // File holding the ID.
+}
+
+// Load returns the saved ID, or an empty string when nothing was saved.
+func (c *Checkpoint) Load() (string, error) {
+	data, err := os.ReadFile(c.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// Save replaces the saved ID. The file is written next to the checkpoint and renamed,
+// so an interrupted save keeps the previous ID.
+func (c *Checkpoint) Save(id string) error {
+	tmp := c.Path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(id+"\n"), 0o600); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, c.Path); err != nil {
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	return nil
+}
+
+// Remove deletes the checkpoint, so the next migration starts from the first record.
+func (c *Checkpoint) Remove() error {
+	if err := os.Remove(c.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove checkpoint: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/apetsko/shortugo/internal/models"
+	"github.com/apetsko/shortugo/internal/storages/infile"
+	"github.com/apetsko/shortugo/internal/storages/inmem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingStorage fails the PutBatch call number failAt.
+type failingStorage struct {
+	Storage
+	calls  int
+	failAt int
+}
+
+func (s *failingStorage) PutBatch(ctx context.Context, rr []models.URLRecord) error {
+	s.calls++
+	if s.calls == s.failAt {
+		return errors.New("connection lost")
+	}
+	return s.Storage.PutBatch(ctx, rr)
+}
+
+// sourceStorage returns a storage with ten links of two users, one of them deleted, and a UTM template.
+func sourceStorage(t *testing.T) *inmem.Storage {
+	t.Helper()
+
+	ctx := context.Background()
+	created := time.Date(2026, 10, 1, 12, 30, 15, 0, time.UTC)
+	src := inmem.New()
+
+	var rr []models.URLRecord
+	for i := range 10 {
+		rr = append(rr, models.URLRecord{
+			ID:        fmt.Sprintf("id%02d", i),
+			URL:       fmt.Sprintf("https://example.com/%d", i),
+			UserID:    fmt.Sprintf("user%d", i%2),
+			CreatedAt: created.Add(time.Duration(i) * time.Hour),
+		})
+	}
+	rr[3].Deleted = true
+	rr[4].Tags, rr[4].Folder, rr[4].Title = []string{"promo", "go"}, "work", "Docs"
+	rr[5].LinkOptions = models.LinkOptions{MaxClicks: 5, RedirectType: 307, UTM: models.UTM{Source: "news"}}
+	rr[5].ClicksLeft = 2
+	require.NoError(t, src.PutBatch(ctx, rr))
+	require.NoError(t, src.PutUTMTemplate(ctx, "user1", models.UTM{Medium: "email"}))
+	return src
+}
+
+// fileStorage opens a file storage in a temporary directory.
+func fileStorage(t *testing.T) *infile.Storage {
+	t.Helper()
+
+	s, err := infile.New(filepath.Join(t.TempDir(), "db.json"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, s.Close())
+	})
+	return s
+}
+
+func TestCopy(t *testing.T) {
+	ctx := context.Background()
+	src := sourceStorage(t)
+	dst := fileStorage(t)
+
+	var progress []string
+	copied, err := Copy(ctx, src, dst, Options{
+		BatchSize: 4,
+		Progress: func(copied int, lastID string) {
+			progress = append(progress, fmt.Sprintf("%d:%s", copied, lastID))
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 10, copied)
+	assert.Equal(t, []string{"4:id03", "8:id07", "10:id09"}, progress)
+
+	want, err := Summarize(ctx, src)
+	require.NoError(t, err)
+	got, err := Summarize(ctx, dst)
+	require.NoError(t, err)
+	assert.Equal(t, Summary{Links: 10, Deleted: 1, Users: 2, Checksum: want.Checksum}, got)
+	assert.Equal(t, want, got)
+
+	// Deleted links stay deleted, the rest keep their owner, labels and click counter
+	_, err = dst.GetRecord(ctx, "id03")
+	assert.Error(t, err)
+
+	rec, err := dst.GetRecord(ctx, "id04")
+	require.NoError(t, err)
+	assert.Equal(t, "user0", rec.UserID)
+	assert.Equal(t, []string{"go", "promo"}, rec.Tags)
+
+	rec, err = dst.GetRecord(ctx, "id05")
+	require.NoError(t, err)
+	assert.Equal(t, 2, rec.ClicksLeft)
+	assert.Equal(t, 307, rec.RedirectType)
+
+	template, err := dst.GetUTMTemplate(ctx, "user1")
+	require.NoError(t, err)
+	assert.Equal(t, "email", template.Medium)
+}
+
+func TestCopy_Resume(t *testing.T) {
+	ctx := context.Background()
+	src := sourceStorage(t)
+	dst := fileStorage(t)
+	checkpoint := &Checkpoint{Path: filepath.Join(t.TempDir(), "migrate.state")}
+
+	copied, err := Copy(ctx, src, &failingStorage{Storage: dst, failAt: 2}, Options{BatchSize: 4, Checkpoint: checkpoint})
+	require.Error(t, err)
+	assert.Equal(t, 4, copied)
+
+	last, err := checkpoint.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "id03", last)
+
+	// The second run starts after the last stored batch
+	copied, err = Copy(ctx, src, dst, Options{BatchSize: 4, Checkpoint: checkpoint})
+	require.NoError(t, err)
+	assert.Equal(t, 6, copied)
+
+	last, err = checkpoint.Load()
+	require.NoError(t, err)
+	assert.Empty(t, last, "the checkpoint is removed when the copy is complete")
+
+	want, err := Summarize(ctx, src)
+	require.NoError(t, err)
+	got, err := Summarize(ctx, dst)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestCopy_UnknownCreatedAt(t *testing.T) {
+	ctx := context.Background()
+
+	// Files written before the creation time was kept have records without it
+	path := filepath.Join(t.TempDir(), "db.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"id":"old","url":"https://example.com","userid":"user0","deleted":false}`+"\n"), 0o600))
+	src, err := infile.New(path)
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, src.Close())
+	}()
+
+	dst := inmem.New()
+	_, err = Copy(ctx, src, dst, Options{})
+	require.NoError(t, err)
+
+	rec, err := dst.GetRecord(ctx, "old")
+	require.NoError(t, err)
+	assert.True(t, unknownCreatedAt.Equal(rec.CreatedAt))
+
+	want, err := Summarize(ctx, src)
+	require.NoError(t, err)
+	got, err := Summarize(ctx, dst)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestSummarize_Differences(t *testing.T) {
+	ctx := context.Background()
+	src := sourceStorage(t)
+	want, err := Summarize(ctx, src)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		change models.URLRecord
+	}{
+		{name: "other owner", change: models.URLRecord{ID: "id00", URL: "https://example.com/0", UserID: "user9"}},
+		{name: "undeleted", change: models.URLRecord{ID: "id03", URL: "https://example.com/3", UserID: "user1"}},
+		{name: "extra link", change: models.URLRecord{ID: "id10", URL: "https://example.com/10", UserID: "user0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst := inmem.New()
+			_, err := Copy(ctx, src, dst, Options{})
+			require.NoError(t, err)
+
+			change := tt.change
+			change.CreatedAt = time.Date(2026, 10, 1, 12, 30, 15, 0, time.UTC)
+			require.NoError(t, dst.Put(ctx, change))
+
+			got, err := Summarize(ctx, dst)
+			require.NoError(t, err)
+			assert.NotEqual(t, want.Checksum, got.Checksum)
+		})
+	}
+}
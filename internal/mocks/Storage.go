@@ -23,17 +23,806 @@ func (_m *Storage) EXPECT() *Storage_Expecter {
 	return &Storage_Expecter{mock: &_m.Mock}
 }
 
+// AddLinkTags provides a mock function with given fields: ctx, id, userID, tags
+func (_m *Storage) AddLinkTags(ctx context.Context, id string, userID string, tags []string) error {
+	ret := _m.Called(ctx, id, userID, tags)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddLinkTags")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []string) error); ok {
+		r0 = rf(ctx, id, userID, tags)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Storage_AddLinkTags_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddLinkTags'
+type Storage_AddLinkTags_Call struct {
+	*mock.Call
+}
+
+// AddLinkTags is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - userID string
+//   - tags []string
+func (_e *Storage_Expecter) AddLinkTags(ctx interface{}, id interface{}, userID interface{}, tags interface{}) *Storage_AddLinkTags_Call {
+	return &Storage_AddLinkTags_Call{Call: _e.mock.On("AddLinkTags", ctx, id, userID, tags)}
+}
+
+func (_c *Storage_AddLinkTags_Call) Run(run func(ctx context.Context, id string, userID string, tags []string)) *Storage_AddLinkTags_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].([]string))
+	})
+	return _c
+}
+
+func (_c *Storage_AddLinkTags_Call) Return(_a0 error) *Storage_AddLinkTags_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Storage_AddLinkTags_Call) RunAndReturn(run func(context.Context, string, string, []string) error) *Storage_AddLinkTags_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Close provides a mock function with no fields
 func (_m *Storage) Close() error {
 	ret := _m.Called()
 
 	if len(ret) == 0 {
-		panic("no return value specified for Close")
+		panic("no return value specified for Close")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Storage_Close_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Close'
+type Storage_Close_Call struct {
+	*mock.Call
+}
+
+// Close is a helper method to define mock.On call
+func (_e *Storage_Expecter) Close() *Storage_Close_Call {
+	return &Storage_Close_Call{Call: _e.mock.On("Close")}
+}
+
+func (_c *Storage_Close_Call) Run(run func()) *Storage_Close_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Storage_Close_Call) Return(_a0 error) *Storage_Close_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Storage_Close_Call) RunAndReturn(run func() error) *Storage_Close_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ConsumeClick provides a mock function with given fields: ctx, id
+func (_m *Storage) ConsumeClick(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConsumeClick")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Storage_ConsumeClick_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ConsumeClick'
+type Storage_ConsumeClick_Call struct {
+	*mock.Call
+}
+
+// ConsumeClick is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *Storage_Expecter) ConsumeClick(ctx interface{}, id interface{}) *Storage_ConsumeClick_Call {
+	return &Storage_ConsumeClick_Call{Call: _e.mock.On("ConsumeClick", ctx, id)}
+}
+
+func (_c *Storage_ConsumeClick_Call) Run(run func(ctx context.Context, id string)) *Storage_ConsumeClick_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Storage_ConsumeClick_Call) Return(_a0 error) *Storage_ConsumeClick_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Storage_ConsumeClick_Call) RunAndReturn(run func(context.Context, string) error) *Storage_ConsumeClick_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CountVariantClick provides a mock function with given fields: ctx, id, variant
+func (_m *Storage) CountVariantClick(ctx context.Context, id string, variant string) error {
+	ret := _m.Called(ctx, id, variant)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountVariantClick")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, id, variant)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Storage_CountVariantClick_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountVariantClick'
+type Storage_CountVariantClick_Call struct {
+	*mock.Call
+}
+
+// CountVariantClick is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - variant string
+func (_e *Storage_Expecter) CountVariantClick(ctx interface{}, id interface{}, variant interface{}) *Storage_CountVariantClick_Call {
+	return &Storage_CountVariantClick_Call{Call: _e.mock.On("CountVariantClick", ctx, id, variant)}
+}
+
+func (_c *Storage_CountVariantClick_Call) Run(run func(ctx context.Context, id string, variant string)) *Storage_CountVariantClick_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *Storage_CountVariantClick_Call) Return(_a0 error) *Storage_CountVariantClick_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Storage_CountVariantClick_Call) RunAndReturn(run func(context.Context, string, string) error) *Storage_CountVariantClick_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteUserURLs provides a mock function with given fields: ctx, IDs, userID
+func (_m *Storage) DeleteUserURLs(ctx context.Context, IDs []string, userID string) error {
+	ret := _m.Called(ctx, IDs, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUserURLs")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string, string) error); ok {
+		r0 = rf(ctx, IDs, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Storage_DeleteUserURLs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteUserURLs'
+type Storage_DeleteUserURLs_Call struct {
+	*mock.Call
+}
+
+// DeleteUserURLs is a helper method to define mock.On call
+//   - ctx context.Context
+//   - IDs []string
+//   - userID string
+func (_e *Storage_Expecter) DeleteUserURLs(ctx interface{}, IDs interface{}, userID interface{}) *Storage_DeleteUserURLs_Call {
+	return &Storage_DeleteUserURLs_Call{Call: _e.mock.On("DeleteUserURLs", ctx, IDs, userID)}
+}
+
+func (_c *Storage_DeleteUserURLs_Call) Run(run func(ctx context.Context, IDs []string, userID string)) *Storage_DeleteUserURLs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *Storage_DeleteUserURLs_Call) Return(err error) *Storage_DeleteUserURLs_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *Storage_DeleteUserURLs_Call) RunAndReturn(run func(context.Context, []string, string) error) *Storage_DeleteUserURLs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ForEachLinkByUserID provides a mock function with given fields: ctx, baseURL, userID, fn
+func (_m *Storage) ForEachLinkByUserID(ctx context.Context, baseURL string, userID string, fn func(models.URLRecord) error) error {
+	ret := _m.Called(ctx, baseURL, userID, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ForEachLinkByUserID")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, func(models.URLRecord) error) error); ok {
+		r0 = rf(ctx, baseURL, userID, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Storage_ForEachLinkByUserID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ForEachLinkByUserID'
+type Storage_ForEachLinkByUserID_Call struct {
+	*mock.Call
+}
+
+// ForEachLinkByUserID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - baseURL string
+//   - userID string
+//   - fn func(models.URLRecord) error
+func (_e *Storage_Expecter) ForEachLinkByUserID(ctx interface{}, baseURL interface{}, userID interface{}, fn interface{}) *Storage_ForEachLinkByUserID_Call {
+	return &Storage_ForEachLinkByUserID_Call{Call: _e.mock.On("ForEachLinkByUserID", ctx, baseURL, userID, fn)}
+}
+
+func (_c *Storage_ForEachLinkByUserID_Call) Run(run func(ctx context.Context, baseURL string, userID string, fn func(models.URLRecord) error)) *Storage_ForEachLinkByUserID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(func(models.URLRecord) error))
+	})
+	return _c
+}
+
+func (_c *Storage_ForEachLinkByUserID_Call) Return(_a0 error) *Storage_ForEachLinkByUserID_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Storage_ForEachLinkByUserID_Call) RunAndReturn(run func(context.Context, string, string, func(models.URLRecord) error) error) *Storage_ForEachLinkByUserID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Get provides a mock function with given fields: ctx, id
+func (_m *Storage) Get(ctx context.Context, id string) (string, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Storage_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
+type Storage_Get_Call struct {
+	*mock.Call
+}
+
+// Get is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *Storage_Expecter) Get(ctx interface{}, id interface{}) *Storage_Get_Call {
+	return &Storage_Get_Call{Call: _e.mock.On("Get", ctx, id)}
+}
+
+func (_c *Storage_Get_Call) Run(run func(ctx context.Context, id string)) *Storage_Get_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Storage_Get_Call) Return(url string, err error) *Storage_Get_Call {
+	_c.Call.Return(url, err)
+	return _c
+}
+
+func (_c *Storage_Get_Call) RunAndReturn(run func(context.Context, string) (string, error)) *Storage_Get_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRecord provides a mock function with given fields: ctx, id
+func (_m *Storage) GetRecord(ctx context.Context, id string) (*models.URLRecord, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRecord")
+	}
+
+	var r0 *models.URLRecord
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.URLRecord, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.URLRecord); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.URLRecord)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Storage_GetRecord_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRecord'
+type Storage_GetRecord_Call struct {
+	*mock.Call
+}
+
+// GetRecord is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *Storage_Expecter) GetRecord(ctx interface{}, id interface{}) *Storage_GetRecord_Call {
+	return &Storage_GetRecord_Call{Call: _e.mock.On("GetRecord", ctx, id)}
+}
+
+func (_c *Storage_GetRecord_Call) Run(run func(ctx context.Context, id string)) *Storage_GetRecord_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Storage_GetRecord_Call) Return(_a0 *models.URLRecord, _a1 error) *Storage_GetRecord_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Storage_GetRecord_Call) RunAndReturn(run func(context.Context, string) (*models.URLRecord, error)) *Storage_GetRecord_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUTMTemplate provides a mock function with given fields: ctx, userID
+func (_m *Storage) GetUTMTemplate(ctx context.Context, userID string) (*models.UTM, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUTMTemplate")
+	}
+
+	var r0 *models.UTM
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.UTM, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.UTM); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.UTM)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Storage_GetUTMTemplate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUTMTemplate'
+type Storage_GetUTMTemplate_Call struct {
+	*mock.Call
+}
+
+// GetUTMTemplate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID string
+func (_e *Storage_Expecter) GetUTMTemplate(ctx interface{}, userID interface{}) *Storage_GetUTMTemplate_Call {
+	return &Storage_GetUTMTemplate_Call{Call: _e.mock.On("GetUTMTemplate", ctx, userID)}
+}
+
+func (_c *Storage_GetUTMTemplate_Call) Run(run func(ctx context.Context, userID string)) *Storage_GetUTMTemplate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Storage_GetUTMTemplate_Call) Return(_a0 *models.UTM, _a1 error) *Storage_GetUTMTemplate_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Storage_GetUTMTemplate_Call) RunAndReturn(run func(context.Context, string) (*models.UTM, error)) *Storage_GetUTMTemplate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListLinksByFilter provides a mock function with given fields: ctx, baseURL, userID, filter
+func (_m *Storage) ListLinksByFilter(ctx context.Context, baseURL string, userID string, filter models.LinkFilter) ([]models.URLRecord, error) {
+	ret := _m.Called(ctx, baseURL, userID, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListLinksByFilter")
+	}
+
+	var r0 []models.URLRecord
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, models.LinkFilter) ([]models.URLRecord, error)); ok {
+		return rf(ctx, baseURL, userID, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, models.LinkFilter) []models.URLRecord); ok {
+		r0 = rf(ctx, baseURL, userID, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.URLRecord)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, models.LinkFilter) error); ok {
+		r1 = rf(ctx, baseURL, userID, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Storage_ListLinksByFilter_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListLinksByFilter'
+type Storage_ListLinksByFilter_Call struct {
+	*mock.Call
+}
+
+// ListLinksByFilter is a helper method to define mock.On call
+//   - ctx context.Context
+//   - baseURL string
+//   - userID string
+//   - filter models.LinkFilter
+func (_e *Storage_Expecter) ListLinksByFilter(ctx interface{}, baseURL interface{}, userID interface{}, filter interface{}) *Storage_ListLinksByFilter_Call {
+	return &Storage_ListLinksByFilter_Call{Call: _e.mock.On("ListLinksByFilter", ctx, baseURL, userID, filter)}
+}
+
+func (_c *Storage_ListLinksByFilter_Call) Run(run func(ctx context.Context, baseURL string, userID string, filter models.LinkFilter)) *Storage_ListLinksByFilter_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(models.LinkFilter))
+	})
+	return _c
+}
+
+func (_c *Storage_ListLinksByFilter_Call) Return(_a0 []models.URLRecord, _a1 error) *Storage_ListLinksByFilter_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Storage_ListLinksByFilter_Call) RunAndReturn(run func(context.Context, string, string, models.LinkFilter) ([]models.URLRecord, error)) *Storage_ListLinksByFilter_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListLinksByUserID provides a mock function with given fields: ctx, baseURL, userID
+func (_m *Storage) ListLinksByUserID(ctx context.Context, baseURL string, userID string) ([]models.URLRecord, error) {
+	ret := _m.Called(ctx, baseURL, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListLinksByUserID")
+	}
+
+	var r0 []models.URLRecord
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]models.URLRecord, error)); ok {
+		return rf(ctx, baseURL, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []models.URLRecord); ok {
+		r0 = rf(ctx, baseURL, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.URLRecord)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, baseURL, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Storage_ListLinksByUserID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListLinksByUserID'
+type Storage_ListLinksByUserID_Call struct {
+	*mock.Call
+}
+
+// ListLinksByUserID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - baseURL string
+//   - userID string
+func (_e *Storage_Expecter) ListLinksByUserID(ctx interface{}, baseURL interface{}, userID interface{}) *Storage_ListLinksByUserID_Call {
+	return &Storage_ListLinksByUserID_Call{Call: _e.mock.On("ListLinksByUserID", ctx, baseURL, userID)}
+}
+
+func (_c *Storage_ListLinksByUserID_Call) Run(run func(ctx context.Context, baseURL string, userID string)) *Storage_ListLinksByUserID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *Storage_ListLinksByUserID_Call) Return(rr []models.URLRecord, err error) *Storage_ListLinksByUserID_Call {
+	_c.Call.Return(rr, err)
+	return _c
+}
+
+func (_c *Storage_ListLinksByUserID_Call) RunAndReturn(run func(context.Context, string, string) ([]models.URLRecord, error)) *Storage_ListLinksByUserID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LookupRecord provides a mock function with given fields: ctx, id
+func (_m *Storage) LookupRecord(ctx context.Context, id string) (*models.URLRecord, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LookupRecord")
+	}
+
+	var r0 *models.URLRecord
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.URLRecord, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.URLRecord); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.URLRecord)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Storage_LookupRecord_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LookupRecord'
+type Storage_LookupRecord_Call struct {
+	*mock.Call
+}
+
+// LookupRecord is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *Storage_Expecter) LookupRecord(ctx interface{}, id interface{}) *Storage_LookupRecord_Call {
+	return &Storage_LookupRecord_Call{Call: _e.mock.On("LookupRecord", ctx, id)}
+}
+
+func (_c *Storage_LookupRecord_Call) Run(run func(ctx context.Context, id string)) *Storage_LookupRecord_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Storage_LookupRecord_Call) Return(_a0 *models.URLRecord, _a1 error) *Storage_LookupRecord_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Storage_LookupRecord_Call) RunAndReturn(run func(context.Context, string) (*models.URLRecord, error)) *Storage_LookupRecord_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Ping provides a mock function with no fields
+func (_m *Storage) Ping() error {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Ping")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Storage_Ping_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Ping'
+type Storage_Ping_Call struct {
+	*mock.Call
+}
+
+// Ping is a helper method to define mock.On call
+func (_e *Storage_Expecter) Ping() *Storage_Ping_Call {
+	return &Storage_Ping_Call{Call: _e.mock.On("Ping")}
+}
+
+func (_c *Storage_Ping_Call) Run(run func()) *Storage_Ping_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Storage_Ping_Call) Return(_a0 error) *Storage_Ping_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Storage_Ping_Call) RunAndReturn(run func() error) *Storage_Ping_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PurgeDeleted provides a mock function with given fields: ctx
+func (_m *Storage) PurgeDeleted(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeDeleted")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int64, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Storage_PurgeDeleted_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PurgeDeleted'
+type Storage_PurgeDeleted_Call struct {
+	*mock.Call
+}
+
+// PurgeDeleted is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *Storage_Expecter) PurgeDeleted(ctx interface{}) *Storage_PurgeDeleted_Call {
+	return &Storage_PurgeDeleted_Call{Call: _e.mock.On("PurgeDeleted", ctx)}
+}
+
+func (_c *Storage_PurgeDeleted_Call) Run(run func(ctx context.Context)) *Storage_PurgeDeleted_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *Storage_PurgeDeleted_Call) Return(_a0 int64, _a1 error) *Storage_PurgeDeleted_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Storage_PurgeDeleted_Call) RunAndReturn(run func(context.Context) (int64, error)) *Storage_PurgeDeleted_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Put provides a mock function with given fields: ctx, r
+func (_m *Storage) Put(ctx context.Context, r models.URLRecord) error {
+	ret := _m.Called(ctx, r)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Put")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.URLRecord) error); ok {
+		r0 = rf(ctx, r)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Storage_Put_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Put'
+type Storage_Put_Call struct {
+	*mock.Call
+}
+
+// Put is a helper method to define mock.On call
+//   - ctx context.Context
+//   - r models.URLRecord
+func (_e *Storage_Expecter) Put(ctx interface{}, r interface{}) *Storage_Put_Call {
+	return &Storage_Put_Call{Call: _e.mock.On("Put", ctx, r)}
+}
+
+func (_c *Storage_Put_Call) Run(run func(ctx context.Context, r models.URLRecord)) *Storage_Put_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.URLRecord))
+	})
+	return _c
+}
+
+func (_c *Storage_Put_Call) Return(_a0 error) *Storage_Put_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Storage_Put_Call) RunAndReturn(run func(context.Context, models.URLRecord) error) *Storage_Put_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PutBatch provides a mock function with given fields: ctx, rr
+func (_m *Storage) PutBatch(ctx context.Context, rr []models.URLRecord) error {
+	ret := _m.Called(ctx, rr)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PutBatch")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func() error); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context, []models.URLRecord) error); ok {
+		r0 = rf(ctx, rr)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -41,44 +830,46 @@ func (_m *Storage) Close() error {
 	return r0
 }
 
-// Storage_Close_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Close'
-type Storage_Close_Call struct {
+// Storage_PutBatch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PutBatch'
+type Storage_PutBatch_Call struct {
 	*mock.Call
 }
 
-// Close is a helper method to define mock.On call
-func (_e *Storage_Expecter) Close() *Storage_Close_Call {
-	return &Storage_Close_Call{Call: _e.mock.On("Close")}
+// PutBatch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - rr []models.URLRecord
+func (_e *Storage_Expecter) PutBatch(ctx interface{}, rr interface{}) *Storage_PutBatch_Call {
+	return &Storage_PutBatch_Call{Call: _e.mock.On("PutBatch", ctx, rr)}
 }
 
-func (_c *Storage_Close_Call) Run(run func()) *Storage_Close_Call {
+func (_c *Storage_PutBatch_Call) Run(run func(ctx context.Context, rr []models.URLRecord)) *Storage_PutBatch_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		run(args[0].(context.Context), args[1].([]models.URLRecord))
 	})
 	return _c
 }
 
-func (_c *Storage_Close_Call) Return(_a0 error) *Storage_Close_Call {
+func (_c *Storage_PutBatch_Call) Return(_a0 error) *Storage_PutBatch_Call {
 	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *Storage_Close_Call) RunAndReturn(run func() error) *Storage_Close_Call {
+func (_c *Storage_PutBatch_Call) RunAndReturn(run func(context.Context, []models.URLRecord) error) *Storage_PutBatch_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// DeleteUserURLs provides a mock function with given fields: ctx, IDs, userID
-func (_m *Storage) DeleteUserURLs(ctx context.Context, IDs []string, userID string) error {
-	ret := _m.Called(ctx, IDs, userID)
+// PutUTMTemplate provides a mock function with given fields: ctx, userID, t
+func (_m *Storage) PutUTMTemplate(ctx context.Context, userID string, t models.UTM) error {
+	ret := _m.Called(ctx, userID, t)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteUserURLs")
+		panic("no return value specified for PutUTMTemplate")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, []string, string) error); ok {
-		r0 = rf(ctx, IDs, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.UTM) error); ok {
+		r0 = rf(ctx, userID, t)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -86,116 +877,108 @@ func (_m *Storage) DeleteUserURLs(ctx context.Context, IDs []string, userID stri
 	return r0
 }
 
-// Storage_DeleteUserURLs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteUserURLs'
-type Storage_DeleteUserURLs_Call struct {
+// Storage_PutUTMTemplate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PutUTMTemplate'
+type Storage_PutUTMTemplate_Call struct {
 	*mock.Call
 }
 
-// DeleteUserURLs is a helper method to define mock.On call
+// PutUTMTemplate is a helper method to define mock.On call
 //   - ctx context.Context
-//   - IDs []string
 //   - userID string
-func (_e *Storage_Expecter) DeleteUserURLs(ctx interface{}, IDs interface{}, userID interface{}) *Storage_DeleteUserURLs_Call {
-	return &Storage_DeleteUserURLs_Call{Call: _e.mock.On("DeleteUserURLs", ctx, IDs, userID)}
+//   - t models.UTM
+func (_e *Storage_Expecter) PutUTMTemplate(ctx interface{}, userID interface{}, t interface{}) *Storage_PutUTMTemplate_Call {
+	return &Storage_PutUTMTemplate_Call{Call: _e.mock.On("PutUTMTemplate", ctx, userID, t)}
 }
 
-func (_c *Storage_DeleteUserURLs_Call) Run(run func(ctx context.Context, IDs []string, userID string)) *Storage_DeleteUserURLs_Call {
+func (_c *Storage_PutUTMTemplate_Call) Run(run func(ctx context.Context, userID string, t models.UTM)) *Storage_PutUTMTemplate_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].([]string), args[2].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].(models.UTM))
 	})
 	return _c
 }
 
-func (_c *Storage_DeleteUserURLs_Call) Return(err error) *Storage_DeleteUserURLs_Call {
-	_c.Call.Return(err)
+func (_c *Storage_PutUTMTemplate_Call) Return(_a0 error) *Storage_PutUTMTemplate_Call {
+	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *Storage_DeleteUserURLs_Call) RunAndReturn(run func(context.Context, []string, string) error) *Storage_DeleteUserURLs_Call {
+func (_c *Storage_PutUTMTemplate_Call) RunAndReturn(run func(context.Context, string, models.UTM) error) *Storage_PutUTMTemplate_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Get provides a mock function with given fields: ctx, id
-func (_m *Storage) Get(ctx context.Context, id string) (string, error) {
-	ret := _m.Called(ctx, id)
+// RemoveLinkTags provides a mock function with given fields: ctx, id, userID, tags
+func (_m *Storage) RemoveLinkTags(ctx context.Context, id string, userID string, tags []string) error {
+	ret := _m.Called(ctx, id, userID, tags)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Get")
-	}
-
-	var r0 string
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
-		return rf(ctx, id)
-	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
-		r0 = rf(ctx, id)
-	} else {
-		r0 = ret.Get(0).(string)
+		panic("no return value specified for RemoveLinkTags")
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, id)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []string) error); ok {
+		r0 = rf(ctx, id, userID, tags)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
 
-	return r0, r1
+	return r0
 }
 
-// Storage_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
-type Storage_Get_Call struct {
+// Storage_RemoveLinkTags_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RemoveLinkTags'
+type Storage_RemoveLinkTags_Call struct {
 	*mock.Call
 }
 
-// Get is a helper method to define mock.On call
+// RemoveLinkTags is a helper method to define mock.On call
 //   - ctx context.Context
 //   - id string
-func (_e *Storage_Expecter) Get(ctx interface{}, id interface{}) *Storage_Get_Call {
-	return &Storage_Get_Call{Call: _e.mock.On("Get", ctx, id)}
+//   - userID string
+//   - tags []string
+func (_e *Storage_Expecter) RemoveLinkTags(ctx interface{}, id interface{}, userID interface{}, tags interface{}) *Storage_RemoveLinkTags_Call {
+	return &Storage_RemoveLinkTags_Call{Call: _e.mock.On("RemoveLinkTags", ctx, id, userID, tags)}
 }
 
-func (_c *Storage_Get_Call) Run(run func(ctx context.Context, id string)) *Storage_Get_Call {
+func (_c *Storage_RemoveLinkTags_Call) Run(run func(ctx context.Context, id string, userID string, tags []string)) *Storage_RemoveLinkTags_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].([]string))
 	})
 	return _c
 }
 
-func (_c *Storage_Get_Call) Return(url string, err error) *Storage_Get_Call {
-	_c.Call.Return(url, err)
+func (_c *Storage_RemoveLinkTags_Call) Return(_a0 error) *Storage_RemoveLinkTags_Call {
+	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *Storage_Get_Call) RunAndReturn(run func(context.Context, string) (string, error)) *Storage_Get_Call {
+func (_c *Storage_RemoveLinkTags_Call) RunAndReturn(run func(context.Context, string, string, []string) error) *Storage_RemoveLinkTags_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListLinksByUserID provides a mock function with given fields: ctx, baseURL, userID
-func (_m *Storage) ListLinksByUserID(ctx context.Context, baseURL string, userID string) ([]models.URLRecord, error) {
-	ret := _m.Called(ctx, baseURL, userID)
+// SearchLinks provides a mock function with given fields: ctx, baseURL, userID, query
+func (_m *Storage) SearchLinks(ctx context.Context, baseURL string, userID string, query string) ([]models.URLRecord, error) {
+	ret := _m.Called(ctx, baseURL, userID, query)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListLinksByUserID")
+		panic("no return value specified for SearchLinks")
 	}
 
 	var r0 []models.URLRecord
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]models.URLRecord, error)); ok {
-		return rf(ctx, baseURL, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) ([]models.URLRecord, error)); ok {
+		return rf(ctx, baseURL, userID, query)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) []models.URLRecord); ok {
-		r0 = rf(ctx, baseURL, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) []models.URLRecord); ok {
+		r0 = rf(ctx, baseURL, userID, query)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]models.URLRecord)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
-		r1 = rf(ctx, baseURL, userID)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, baseURL, userID, query)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -203,47 +986,48 @@ func (_m *Storage) ListLinksByUserID(ctx context.Context, baseURL string, userID
 	return r0, r1
 }
 
-// Storage_ListLinksByUserID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListLinksByUserID'
-type Storage_ListLinksByUserID_Call struct {
+// Storage_SearchLinks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SearchLinks'
+type Storage_SearchLinks_Call struct {
 	*mock.Call
 }
 
-// ListLinksByUserID is a helper method to define mock.On call
+// SearchLinks is a helper method to define mock.On call
 //   - ctx context.Context
 //   - baseURL string
 //   - userID string
-func (_e *Storage_Expecter) ListLinksByUserID(ctx interface{}, baseURL interface{}, userID interface{}) *Storage_ListLinksByUserID_Call {
-	return &Storage_ListLinksByUserID_Call{Call: _e.mock.On("ListLinksByUserID", ctx, baseURL, userID)}
+//   - query string
+func (_e *Storage_Expecter) SearchLinks(ctx interface{}, baseURL interface{}, userID interface{}, query interface{}) *Storage_SearchLinks_Call {
+	return &Storage_SearchLinks_Call{Call: _e.mock.On("SearchLinks", ctx, baseURL, userID, query)}
 }
 
-func (_c *Storage_ListLinksByUserID_Call) Run(run func(ctx context.Context, baseURL string, userID string)) *Storage_ListLinksByUserID_Call {
+func (_c *Storage_SearchLinks_Call) Run(run func(ctx context.Context, baseURL string, userID string, query string)) *Storage_SearchLinks_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
 	})
 	return _c
 }
 
-func (_c *Storage_ListLinksByUserID_Call) Return(rr []models.URLRecord, err error) *Storage_ListLinksByUserID_Call {
-	_c.Call.Return(rr, err)
+func (_c *Storage_SearchLinks_Call) Return(_a0 []models.URLRecord, _a1 error) *Storage_SearchLinks_Call {
+	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *Storage_ListLinksByUserID_Call) RunAndReturn(run func(context.Context, string, string) ([]models.URLRecord, error)) *Storage_ListLinksByUserID_Call {
+func (_c *Storage_SearchLinks_Call) RunAndReturn(run func(context.Context, string, string, string) ([]models.URLRecord, error)) *Storage_SearchLinks_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Ping provides a mock function with no fields
-func (_m *Storage) Ping() error {
-	ret := _m.Called()
+// SetLinkDisabled provides a mock function with given fields: ctx, id, disabled
+func (_m *Storage) SetLinkDisabled(ctx context.Context, id string, disabled bool) error {
+	ret := _m.Called(ctx, id, disabled)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Ping")
+		panic("no return value specified for SetLinkDisabled")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func() error); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool) error); ok {
+		r0 = rf(ctx, id, disabled)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -251,44 +1035,47 @@ func (_m *Storage) Ping() error {
 	return r0
 }
 
-// Storage_Ping_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Ping'
-type Storage_Ping_Call struct {
+// Storage_SetLinkDisabled_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetLinkDisabled'
+type Storage_SetLinkDisabled_Call struct {
 	*mock.Call
 }
 
-// Ping is a helper method to define mock.On call
-func (_e *Storage_Expecter) Ping() *Storage_Ping_Call {
-	return &Storage_Ping_Call{Call: _e.mock.On("Ping")}
+// SetLinkDisabled is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - disabled bool
+func (_e *Storage_Expecter) SetLinkDisabled(ctx interface{}, id interface{}, disabled interface{}) *Storage_SetLinkDisabled_Call {
+	return &Storage_SetLinkDisabled_Call{Call: _e.mock.On("SetLinkDisabled", ctx, id, disabled)}
 }
 
-func (_c *Storage_Ping_Call) Run(run func()) *Storage_Ping_Call {
+func (_c *Storage_SetLinkDisabled_Call) Run(run func(ctx context.Context, id string, disabled bool)) *Storage_SetLinkDisabled_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		run(args[0].(context.Context), args[1].(string), args[2].(bool))
 	})
 	return _c
 }
 
-func (_c *Storage_Ping_Call) Return(_a0 error) *Storage_Ping_Call {
+func (_c *Storage_SetLinkDisabled_Call) Return(_a0 error) *Storage_SetLinkDisabled_Call {
 	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *Storage_Ping_Call) RunAndReturn(run func() error) *Storage_Ping_Call {
+func (_c *Storage_SetLinkDisabled_Call) RunAndReturn(run func(context.Context, string, bool) error) *Storage_SetLinkDisabled_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Put provides a mock function with given fields: ctx, r
-func (_m *Storage) Put(ctx context.Context, r models.URLRecord) error {
-	ret := _m.Called(ctx, r)
+// SetLinkFolder provides a mock function with given fields: ctx, id, userID, folder
+func (_m *Storage) SetLinkFolder(ctx context.Context, id string, userID string, folder string) error {
+	ret := _m.Called(ctx, id, userID, folder)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Put")
+		panic("no return value specified for SetLinkFolder")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, models.URLRecord) error); ok {
-		r0 = rf(ctx, r)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, id, userID, folder)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -296,46 +1083,106 @@ func (_m *Storage) Put(ctx context.Context, r models.URLRecord) error {
 	return r0
 }
 
-// Storage_Put_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Put'
-type Storage_Put_Call struct {
+// Storage_SetLinkFolder_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetLinkFolder'
+type Storage_SetLinkFolder_Call struct {
 	*mock.Call
 }
 
-// Put is a helper method to define mock.On call
+// SetLinkFolder is a helper method to define mock.On call
 //   - ctx context.Context
-//   - r models.URLRecord
-func (_e *Storage_Expecter) Put(ctx interface{}, r interface{}) *Storage_Put_Call {
-	return &Storage_Put_Call{Call: _e.mock.On("Put", ctx, r)}
+//   - id string
+//   - userID string
+//   - folder string
+func (_e *Storage_Expecter) SetLinkFolder(ctx interface{}, id interface{}, userID interface{}, folder interface{}) *Storage_SetLinkFolder_Call {
+	return &Storage_SetLinkFolder_Call{Call: _e.mock.On("SetLinkFolder", ctx, id, userID, folder)}
 }
 
-func (_c *Storage_Put_Call) Run(run func(ctx context.Context, r models.URLRecord)) *Storage_Put_Call {
+func (_c *Storage_SetLinkFolder_Call) Run(run func(ctx context.Context, id string, userID string, folder string)) *Storage_SetLinkFolder_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(models.URLRecord))
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
 	})
 	return _c
 }
 
-func (_c *Storage_Put_Call) Return(_a0 error) *Storage_Put_Call {
+func (_c *Storage_SetLinkFolder_Call) Return(_a0 error) *Storage_SetLinkFolder_Call {
 	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *Storage_Put_Call) RunAndReturn(run func(context.Context, models.URLRecord) error) *Storage_Put_Call {
+func (_c *Storage_SetLinkFolder_Call) RunAndReturn(run func(context.Context, string, string, string) error) *Storage_SetLinkFolder_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// PutBatch provides a mock function with given fields: ctx, rr
-func (_m *Storage) PutBatch(ctx context.Context, rr []models.URLRecord) error {
-	ret := _m.Called(ctx, rr)
+// Stats provides a mock function with given fields: ctx
+func (_m *Storage) Stats(ctx context.Context) (*models.Stats, error) {
+	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for PutBatch")
+		panic("no return value specified for Stats")
+	}
+
+	var r0 *models.Stats
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*models.Stats, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *models.Stats); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Stats)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Storage_Stats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Stats'
+type Storage_Stats_Call struct {
+	*mock.Call
+}
+
+// Stats is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *Storage_Expecter) Stats(ctx interface{}) *Storage_Stats_Call {
+	return &Storage_Stats_Call{Call: _e.mock.On("Stats", ctx)}
+}
+
+func (_c *Storage_Stats_Call) Run(run func(ctx context.Context)) *Storage_Stats_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *Storage_Stats_Call) Return(_a0 *models.Stats, _a1 error) *Storage_Stats_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Storage_Stats_Call) RunAndReturn(run func(context.Context) (*models.Stats, error)) *Storage_Stats_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateLinkOptions provides a mock function with given fields: ctx, id, userID, update
+func (_m *Storage) UpdateLinkOptions(ctx context.Context, id string, userID string, update func(*models.LinkOptions) error) error {
+	ret := _m.Called(ctx, id, userID, update)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateLinkOptions")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, []models.URLRecord) error); ok {
-		r0 = rf(ctx, rr)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, func(*models.LinkOptions) error) error); ok {
+		r0 = rf(ctx, id, userID, update)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -343,58 +1190,60 @@ func (_m *Storage) PutBatch(ctx context.Context, rr []models.URLRecord) error {
 	return r0
 }
 
-// Storage_PutBatch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PutBatch'
-type Storage_PutBatch_Call struct {
+// Storage_UpdateLinkOptions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateLinkOptions'
+type Storage_UpdateLinkOptions_Call struct {
 	*mock.Call
 }
 
-// PutBatch is a helper method to define mock.On call
+// UpdateLinkOptions is a helper method to define mock.On call
 //   - ctx context.Context
-//   - rr []models.URLRecord
-func (_e *Storage_Expecter) PutBatch(ctx interface{}, rr interface{}) *Storage_PutBatch_Call {
-	return &Storage_PutBatch_Call{Call: _e.mock.On("PutBatch", ctx, rr)}
+//   - id string
+//   - userID string
+//   - update func(*models.LinkOptions) error
+func (_e *Storage_Expecter) UpdateLinkOptions(ctx interface{}, id interface{}, userID interface{}, update interface{}) *Storage_UpdateLinkOptions_Call {
+	return &Storage_UpdateLinkOptions_Call{Call: _e.mock.On("UpdateLinkOptions", ctx, id, userID, update)}
 }
 
-func (_c *Storage_PutBatch_Call) Run(run func(ctx context.Context, rr []models.URLRecord)) *Storage_PutBatch_Call {
+func (_c *Storage_UpdateLinkOptions_Call) Run(run func(ctx context.Context, id string, userID string, update func(*models.LinkOptions) error)) *Storage_UpdateLinkOptions_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].([]models.URLRecord))
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(func(*models.LinkOptions) error))
 	})
 	return _c
 }
 
-func (_c *Storage_PutBatch_Call) Return(_a0 error) *Storage_PutBatch_Call {
+func (_c *Storage_UpdateLinkOptions_Call) Return(_a0 error) *Storage_UpdateLinkOptions_Call {
 	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *Storage_PutBatch_Call) RunAndReturn(run func(context.Context, []models.URLRecord) error) *Storage_PutBatch_Call {
+func (_c *Storage_UpdateLinkOptions_Call) RunAndReturn(run func(context.Context, string, string, func(*models.LinkOptions) error) error) *Storage_UpdateLinkOptions_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Stats provides a mock function with given fields: ctx
-func (_m *Storage) Stats(ctx context.Context) (*models.Stats, error) {
-	ret := _m.Called(ctx)
+// VariantClicks provides a mock function with given fields: ctx, id
+func (_m *Storage) VariantClicks(ctx context.Context, id string) (map[string]int64, error) {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Stats")
+		panic("no return value specified for VariantClicks")
 	}
 
-	var r0 *models.Stats
+	var r0 map[string]int64
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) (*models.Stats, error)); ok {
-		return rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (map[string]int64, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) *models.Stats); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, string) map[string]int64); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*models.Stats)
+			r0 = ret.Get(0).(map[string]int64)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -402,30 +1251,31 @@ func (_m *Storage) Stats(ctx context.Context) (*models.Stats, error) {
 	return r0, r1
 }
 
-// Storage_Stats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Stats'
-type Storage_Stats_Call struct {
+// Storage_VariantClicks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'VariantClicks'
+type Storage_VariantClicks_Call struct {
 	*mock.Call
 }
 
-// Stats is a helper method to define mock.On call
+// VariantClicks is a helper method to define mock.On call
 //   - ctx context.Context
-func (_e *Storage_Expecter) Stats(ctx interface{}) *Storage_Stats_Call {
-	return &Storage_Stats_Call{Call: _e.mock.On("Stats", ctx)}
+//   - id string
+func (_e *Storage_Expecter) VariantClicks(ctx interface{}, id interface{}) *Storage_VariantClicks_Call {
+	return &Storage_VariantClicks_Call{Call: _e.mock.On("VariantClicks", ctx, id)}
 }
 
-func (_c *Storage_Stats_Call) Run(run func(ctx context.Context)) *Storage_Stats_Call {
+func (_c *Storage_VariantClicks_Call) Run(run func(ctx context.Context, id string)) *Storage_VariantClicks_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context))
+		run(args[0].(context.Context), args[1].(string))
 	})
 	return _c
 }
 
-func (_c *Storage_Stats_Call) Return(_a0 *models.Stats, _a1 error) *Storage_Stats_Call {
+func (_c *Storage_VariantClicks_Call) Return(_a0 map[string]int64, _a1 error) *Storage_VariantClicks_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *Storage_Stats_Call) RunAndReturn(run func(context.Context) (*models.Stats, error)) *Storage_Stats_Call {
+func (_c *Storage_VariantClicks_Call) RunAndReturn(run func(context.Context, string) (map[string]int64, error)) *Storage_VariantClicks_Call {
 	_c.Call.Return(run)
 	return _c
 }
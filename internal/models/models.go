@@ -2,12 +2,174 @@
 // It includes models for URL records, batch operations, and user-specific URL data.
 package models
 
+import (
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Redirect modes supported by LinkOptions.RedirectMode.
+const (
+	RedirectModeHeader = "header" // Redirect with a 3xx status and a Location header.
+	RedirectModeHTML   = "html"   // Serve an HTML page redirecting via meta refresh and JavaScript.
+)
+
+// Query forwarding policies supported by LinkOptions.QueryPolicy.
+// They decide which value wins when the request and the destination share a query key.
+const (
+	QueryPolicyDestination = "destination" // Keep the destination values, drop conflicting request values.
+	QueryPolicyRequest     = "request"     // Replace conflicting destination values with the request values.
+	QueryPolicyAppend      = "append"      // Keep the values from both sides.
+)
+
+// LinkOptions holds per-link settings chosen when the link is created.
+// Zero values fall back to the server defaults.
+type LinkOptions struct {
+	RedirectType int           `json:"redirect_type,omitempty" validate:"omitempty,oneof=301 302 307 308"`           // HTTP status used for the redirect.
+	RedirectMode string        `json:"redirect_mode,omitempty" validate:"omitempty,oneof=header html"`               // How the redirect is delivered to the client.
+	QueryPolicy  string        `json:"query_policy,omitempty" validate:"omitempty,oneof=destination request append"` // Merge request query parameters into the destination; empty drops them.
+	ForwardPath  bool          `json:"forward_path,omitempty"`                                                       // Append path segments following the ID to the destination path.
+	PasswordHash string        `json:"password_hash,omitempty"`                                                      // Bcrypt hash of the password gating the link; empty when the link is open.
+	MaxClicks    int           `json:"max_clicks,omitempty" validate:"gte=0"`                                        // Number of redirects after which the link is gone; zero means unlimited.
+	Rules        []RoutingRule `json:"rules,omitempty" validate:"max=32,dive"`                                       // Conditional destinations, tried in order before falling back to the link URL.
+	Variants     []Variant     `json:"variants,omitempty" validate:"omitempty,min=2,max=16,unique=Name,dive"`        // Weighted destinations of an A/B split; each visitor sticks to one.
+	Interstitial bool          `json:"interstitial,omitempty"`                                                       // Always show the preview page of the destination before redirecting.
+	UTM                        // Campaign tags appended to the destination on redirect.
+}
+
+// Protected reports whether the link requires a password.
+func (o LinkOptions) Protected() bool {
+	return o.PasswordHash != ""
+}
+
+// Platform classes a RoutingRule can match, derived from the User-Agent header.
+const (
+	PlatformIOS     = "ios"
+	PlatformAndroid = "android"
+	PlatformWindows = "windows"
+	PlatformMacOS   = "macos"
+	PlatformLinux   = "linux"
+	PlatformMobile  = "mobile"  // iOS or Android.
+	PlatformDesktop = "desktop" // Windows, macOS or Linux.
+)
+
+// RoutingRule sends visitors matching all of its conditions to URL. Empty conditions match every visitor.
+type RoutingRule struct {
+	Platform string `json:"platform,omitempty" validate:"omitempty,oneof=ios android windows macos linux mobile desktop"` // Platform class of the visitor.
+	Language string `json:"language,omitempty" validate:"omitempty,bcp47_language_tag"`                                   // Preferred language; "de" also matches "de-AT".
+	Country  string `json:"country,omitempty" validate:"omitempty,iso3166_1_alpha2"`                                      // ISO 3166-1 alpha-2 country from the GeoIP database.
+	URL      string `json:"url" validate:"required,url"`                                                                  // Destination for matching visitors.
+}
+
+// Variant is one destination of an A/B split, chosen for a share of the visitors proportional to its weight.
+type Variant struct {
+	Name   string `json:"name" validate:"required,max=64"`           // Identifies the variant in cookies and click counters.
+	URL    string `json:"url" validate:"required,url"`               // Destination of the variant.
+	Weight int    `json:"weight" validate:"required,min=1,max=1000"` // Relative share of visitors.
+}
+
+// UTM holds the standard campaign tags of a link.
+// The tags are kept apart from the destination URL, so they never change the short link ID.
+type UTM struct {
+	Source   string `json:"utm_source,omitempty" validate:"max=256"`   // Referrer, e.g. newsletter.
+	Medium   string `json:"utm_medium,omitempty" validate:"max=256"`   // Marketing medium, e.g. email.
+	Campaign string `json:"utm_campaign,omitempty" validate:"max=256"` // Campaign name.
+	Term     string `json:"utm_term,omitempty" validate:"max=256"`     // Paid search keywords.
+	Content  string `json:"utm_content,omitempty" validate:"max=256"`  // Variant of the ad or link.
+}
+
+// IsZero reports whether no tag is set.
+func (u UTM) IsZero() bool {
+	return u == UTM{}
+}
+
+// WithDefaults returns u with its empty tags taken from d.
+func (u UTM) WithDefaults(d UTM) UTM {
+	fill := func(v *string, def string) {
+		if *v == "" {
+			*v = def
+		}
+	}
+	fill(&u.Source, d.Source)
+	fill(&u.Medium, d.Medium)
+	fill(&u.Campaign, d.Campaign)
+	fill(&u.Term, d.Term)
+	fill(&u.Content, d.Content)
+	return u
+}
+
+// Encode returns the set tags as an encoded query string sorted by key.
+func (u UTM) Encode() string {
+	v := url.Values{}
+	add := func(key, value string) {
+		if value != "" {
+			v.Set(key, value)
+		}
+	}
+	add("utm_source", u.Source)
+	add("utm_medium", u.Medium)
+	add("utm_campaign", u.Campaign)
+	add("utm_term", u.Term)
+	add("utm_content", u.Content)
+	return v.Encode()
+}
+
 // URLRecord represents a record of a shortened URL.
 type URLRecord struct {
-	ID      string `json:"id"`      // Unique identifier for the URL record.
-	URL     string `json:"url"`     // Original URL.
-	UserID  string `json:"userid"`  // ID of the user who created the URL.
-	Deleted bool   `json:"deleted"` // Flag indicating if the URL is deleted.
+	CreatedAt   time.Time `json:"created_at"`                                            // When the link was stored; set by the storage when zero.
+	ID          string    `json:"id"`                                                    // Unique identifier for the URL record.
+	URL         string    `json:"url"`                                                   // Original URL.
+	UserID      string    `json:"userid"`                                                // ID of the user who created the URL.
+	Deleted     bool      `json:"deleted"`                                               // Flag indicating if the URL is deleted.
+	Disabled    bool      `json:"disabled,omitempty"`                                    // Set by an admin to stop redirects of an abusive link.
+	ClicksLeft  int       `json:"clicks_left,omitempty"`                                 // Remaining redirects of a link with MaxClicks.
+	Title       string    `json:"title,omitempty" validate:"max=256"`                    // Title of the link given by its owner, searched along with the URL.
+	Folder      string    `json:"folder,omitempty" validate:"max=128"`                   // Folder the link is filed in; empty for none.
+	Tags        []string  `json:"tags,omitempty" validate:"max=32,dive,required,max=64"` // Labels of the link, see NormalizeTags.
+	LinkOptions           // Per-link settings.
+}
+
+// MaxTags is the number of tags a link can have.
+const MaxTags = 32
+
+// NormalizeTags trims and lowercases tags, drops empty ones and duplicates and sorts the rest,
+// so tags compare case-insensitively. No tags yield nil.
+func NormalizeTags(tags []string) []string {
+	var out []string
+	for _, tag := range tags {
+		if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" {
+			out = append(out, tag)
+		}
+	}
+	slices.Sort(out)
+	return slices.Compact(out)
+}
+
+// LinkFilter selects the links of a user by tag and folder. Empty fields match every link.
+type LinkFilter struct {
+	Tag    string // Normalized tag the link must have.
+	Folder string // Folder the link must be filed in.
+}
+
+// IsZero reports whether the filter matches every link.
+func (f LinkFilter) IsZero() bool {
+	return f == LinkFilter{}
+}
+
+// Matches reports whether r passes the filter.
+func (f LinkFilter) Matches(r URLRecord) bool {
+	return (f.Folder == "" || r.Folder == f.Folder) && (f.Tag == "" || slices.Contains(r.Tags, f.Tag))
+}
+
+// Unavailable reports whether the link is no longer followed: deleted by its owner, disabled by an admin or exhausted.
+func (r URLRecord) Unavailable() bool {
+	return r.Deleted || r.Disabled || r.Exhausted()
+}
+
+// Exhausted reports whether a link limited by MaxClicks has no redirects left.
+func (r URLRecord) Exhausted() bool {
+	return r.MaxClicks > 0 && r.ClicksLeft <= 0
 }
 
 // Result represents a generic result message.
@@ -23,8 +185,13 @@ type BatchDeleteRequest struct {
 
 // BatchRequest represents a request to shorten multiple URLs.
 type BatchRequest struct {
-	ID          string `json:"correlation_id"` // Correlation ID for the batch request.
-	OriginalURL string `json:"original_url"`   // Original URL to be shortened.
+	ID          string   `json:"correlation_id"`     // Correlation ID for the batch request.
+	OriginalURL string   `json:"original_url"`       // Original URL to be shortened.
+	Password    string   `json:"password,omitempty"` // Password gating the link, stored as a hash.
+	Title       string   `json:"title,omitempty"`    // Title of the link.
+	Folder      string   `json:"folder,omitempty"`   // Folder the link is filed in.
+	Tags        []string `json:"tags,omitempty"`     // Labels of the link.
+	LinkOptions          // Per-link settings.
 }
 
 // BatchResponse represents a response for a batch URL shortening request.
@@ -35,8 +202,24 @@ type BatchResponse struct {
 
 // UserURL represents a user's URL with both short and original versions.
 type UserURL struct {
-	ShortURL    string `json:"short_url"`    // Shortened URL.
-	OriginalURL string `json:"original_url"` // Original URL.
+	ShortURL    string   `json:"short_url"`        // Shortened URL.
+	OriginalURL string   `json:"original_url"`     // Original URL.
+	Title       string   `json:"title,omitempty"`  // Title of the link.
+	Folder      string   `json:"folder,omitempty"` // Folder of the link.
+	Tags        []string `json:"tags,omitempty"`   // Tags of the link.
+}
+
+// AdminLink is a link as shown by the admin API, whoever owns it and whatever its state.
+type AdminLink struct {
+	CreatedAt   time.Time `json:"created_at"`      // When the link was stored.
+	ID          string    `json:"id"`              // ID of the link.
+	ShortURL    string    `json:"short_url"`       // Shortened URL.
+	OriginalURL string    `json:"original_url"`    // Original URL.
+	UserID      string    `json:"user_id"`         // ID of the user who created the link.
+	Title       string    `json:"title,omitempty"` // Title of the link.
+	Deleted     bool      `json:"deleted"`         // Deleted by its owner.
+	Disabled    bool      `json:"disabled"`        // Disabled by an admin.
+	Exhausted   bool      `json:"exhausted"`       // No redirects left, see URLRecord.Exhausted.
 }
 
 // Stats presents count of users and urls
@@ -0,0 +1,100 @@
+package qrcode
+
+import (
+	"container/list"
+	"sync"
+)
+
+// DefaultCacheSize is the default number of rendered images kept in a Cache.
+const DefaultCacheSize = 256
+
+// cacheKey identifies a rendered image.
+type cacheKey struct {
+	content string
+	Options
+}
+
+// cacheEntry is an element of the LRU list.
+type cacheEntry struct {
+	image []byte
+	key   cacheKey
+}
+
+// Cache renders QR codes and keeps the most recently used images in memory.
+// It is safe for concurrent use. A Cache with a capacity of zero renders every request.
+type Cache struct {
+	items    map[cacheKey]*list.Element
+	lru      *list.List
+	mu       sync.Mutex
+	capacity int
+}
+
+// NewCache creates a cache holding at most capacity rendered images.
+func NewCache(capacity int) *Cache {
+	return &Cache{
+		items:    make(map[cacheKey]*list.Element),
+		lru:      list.New(),
+		capacity: max(capacity, 0),
+	}
+}
+
+// Encode returns the cached image for content and o, rendering and caching it on a miss.
+func (c *Cache) Encode(content string, o Options) ([]byte, error) {
+	key := cacheKey{content: content, Options: o}
+
+	c.mu.Lock()
+	if el, ok := c.items[key]; ok {
+		c.lru.MoveToFront(el)
+		img := el.Value.(*cacheEntry).image
+		c.mu.Unlock()
+		return img, nil
+	}
+	c.mu.Unlock()
+
+	// Rendering happens outside the lock; concurrent misses for the same key are harmless.
+	img, err := Encode(content, o)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.capacity == 0 {
+		return img, nil
+	}
+	if el, ok := c.items[key]; ok {
+		c.lru.MoveToFront(el)
+		return img, nil
+	}
+	c.items[key] = c.lru.PushFront(&cacheEntry{key: key, image: img})
+	c.evict()
+
+	return img, nil
+}
+
+// Len returns the number of cached images.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+// Resize changes the number of images the cache holds at most, dropping the least recently used ones
+// that no longer fit. A capacity of zero empties the cache and disables it.
+func (c *Cache) Resize(capacity int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.capacity = max(capacity, 0)
+	c.evict()
+}
+
+// evict drops the least recently used images until the cache fits its capacity.
+func (c *Cache) evict() {
+	for c.lru.Len() > c.capacity {
+		el := c.lru.Back()
+		c.lru.Remove(el)
+		delete(c.items, el.Value.(*cacheEntry).key)
+	}
+}
@@ -0,0 +1,137 @@
+// Package qrcode renders QR codes for shortened links as PNG or SVG images.
+// Encoding is done in pure Go and rendered images can be kept in a bounded LRU cache.
+package qrcode
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	qr "github.com/skip2/go-qrcode"
+)
+
+// Format is the image format of a rendered QR code.
+type Format string
+
+// Supported image formats.
+const (
+	FormatPNG Format = "png"
+	FormatSVG Format = "svg"
+)
+
+// Image size limits in pixels.
+const (
+	DefaultSize = 256
+	MinSize     = 64
+	MaxSize     = 2048
+)
+
+// DefaultLevel is the error-correction level used when none is requested.
+const DefaultLevel = "M"
+
+// ErrInvalidOptions is returned when the requested format, size or level is not supported.
+var ErrInvalidOptions = errors.New("invalid QR code options")
+
+// levels maps error-correction level names to the encoder recovery levels.
+var levels = map[string]qr.RecoveryLevel{
+	"L": qr.Low,     // ~7% recovery
+	"M": qr.Medium,  // ~15% recovery
+	"Q": qr.High,    // ~25% recovery
+	"H": qr.Highest, // ~30% recovery
+}
+
+// Options describes how a QR code is rendered.
+type Options struct {
+	Format Format // Image format: png or svg.
+	Level  string // Error-correction level: L, M, Q or H.
+	Size   int    // Width and height of the image in pixels.
+}
+
+// ParseOptions validates the requested rendering options and fills in defaults for empty values.
+// A zero size selects DefaultSize.
+func ParseOptions(format, level string, size int) (Options, error) {
+	o := Options{
+		Format: Format(strings.ToLower(format)),
+		Level:  strings.ToUpper(level),
+		Size:   size,
+	}
+
+	if o.Format == "" {
+		o.Format = FormatPNG
+	}
+	if o.Format != FormatPNG && o.Format != FormatSVG {
+		return Options{}, fmt.Errorf("unsupported format %q: %w", format, ErrInvalidOptions)
+	}
+
+	if o.Level == "" {
+		o.Level = DefaultLevel
+	}
+	if _, ok := levels[o.Level]; !ok {
+		return Options{}, fmt.Errorf("unsupported error-correction level %q: %w", level, ErrInvalidOptions)
+	}
+
+	if o.Size == 0 {
+		o.Size = DefaultSize
+	}
+	if o.Size < MinSize || o.Size > MaxSize {
+		return Options{}, fmt.Errorf("size %d is out of range [%d, %d]: %w", size, MinSize, MaxSize, ErrInvalidOptions)
+	}
+
+	return o, nil
+}
+
+// ContentType returns the MIME type of images rendered with these options.
+func (o Options) ContentType() string {
+	if o.Format == FormatSVG {
+		return "image/svg+xml"
+	}
+	return "image/png"
+}
+
+// Encode renders content as a QR code image using the given options.
+func Encode(content string, o Options) ([]byte, error) {
+	level, ok := levels[o.Level]
+	if !ok {
+		return nil, fmt.Errorf("unsupported error-correction level %q: %w", o.Level, ErrInvalidOptions)
+	}
+
+	code, err := qr.New(content, level)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode QR code: %w", err)
+	}
+
+	switch o.Format {
+	case FormatPNG:
+		return code.PNG(o.Size)
+	case FormatSVG:
+		return renderSVG(code.Bitmap(), o.Size), nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q: %w", o.Format, ErrInvalidOptions)
+	}
+}
+
+// renderSVG draws the bitmap as a single path scaled to size pixels.
+// Each module is one unit of the view box, so the image stays sharp at any size.
+func renderSVG(bitmap [][]bool, size int) []byte {
+	n := strconv.Itoa(len(bitmap))
+	s := strconv.Itoa(size)
+
+	var buf bytes.Buffer
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	buf.WriteString(`<svg xmlns="http://www.w3.org/2000/svg" width="` + s + `" height="` + s +
+		`" viewBox="0 0 ` + n + ` ` + n + `" shape-rendering="crispEdges">`)
+	buf.WriteString(`<rect width="100%" height="100%" fill="#ffffff"/>`)
+	buf.WriteString(`<path fill="#000000" d="`)
+	for y, row := range bitmap {
+		for x, set := range row {
+			if set {
+				buf.WriteString("M" + strconv.Itoa(x) + " " + strconv.Itoa(y) + "h1v1h-1z")
+			}
+		}
+	}
+	buf.WriteString(`"/></svg>`)
+
+	return buf.Bytes()
+}
@@ -0,0 +1,131 @@
+package qrcode
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		level   string
+		want    Options
+		size    int
+		wantErr bool
+	}{
+		{name: "defaults", want: Options{Format: FormatPNG, Level: "M", Size: DefaultSize}},
+		{name: "svg high", format: "SVG", level: "h", size: 512, want: Options{Format: FormatSVG, Level: "H", Size: 512}},
+		{name: "unknown format", format: "gif", wantErr: true},
+		{name: "unknown level", level: "X", wantErr: true},
+		{name: "too small", size: MinSize - 1, wantErr: true},
+		{name: "too large", size: MaxSize + 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseOptions(tt.format, tt.level, tt.size)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidOptions)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	t.Run("png", func(t *testing.T) {
+		o := Options{Format: FormatPNG, Level: "M", Size: 300}
+		data, err := Encode("http://localhost:8080/abc123", o)
+		require.NoError(t, err)
+		assert.Equal(t, "image/png", o.ContentType())
+
+		img, err := png.Decode(bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Equal(t, 300, img.Bounds().Dx())
+		assert.Equal(t, 300, img.Bounds().Dy())
+	})
+
+	t.Run("svg", func(t *testing.T) {
+		o := Options{Format: FormatSVG, Level: "Q", Size: 128}
+		data, err := Encode("http://localhost:8080/abc123", o)
+		require.NoError(t, err)
+		assert.Equal(t, "image/svg+xml", o.ContentType())
+
+		svg := string(data)
+		assert.True(t, strings.HasPrefix(svg, "<?xml"))
+		assert.Contains(t, svg, `width="128" height="128"`)
+		assert.Contains(t, svg, "h1v1h-1z")
+		assert.True(t, strings.HasSuffix(svg, "</svg>"))
+	})
+
+	t.Run("higher level yields larger symbol", func(t *testing.T) {
+		low, err := Encode("http://localhost:8080/abc123", Options{Format: FormatSVG, Level: "L", Size: 128})
+		require.NoError(t, err)
+		high, err := Encode("http://localhost:8080/abc123", Options{Format: FormatSVG, Level: "H", Size: 128})
+		require.NoError(t, err)
+		assert.NotEqual(t, low, high)
+	})
+
+	t.Run("invalid level", func(t *testing.T) {
+		_, err := Encode("http://localhost:8080/abc123", Options{Format: FormatPNG, Level: "Z", Size: 128})
+		assert.ErrorIs(t, err, ErrInvalidOptions)
+	})
+}
+
+func TestCache(t *testing.T) {
+	o := Options{Format: FormatSVG, Level: "M", Size: 128}
+
+	t.Run("hit returns same image", func(t *testing.T) {
+		c := NewCache(2)
+		first, err := c.Encode("http://a", o)
+		require.NoError(t, err)
+		second, err := c.Encode("http://a", o)
+		require.NoError(t, err)
+		assert.Equal(t, first, second)
+		assert.Equal(t, 1, c.Len())
+	})
+
+	t.Run("evicts least recently used", func(t *testing.T) {
+		c := NewCache(2)
+		for _, content := range []string{"http://a", "http://b", "http://a", "http://c"} {
+			_, err := c.Encode(content, o)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 2, c.Len())
+		assert.Contains(t, c.items, cacheKey{content: "http://a", Options: o})
+		assert.NotContains(t, c.items, cacheKey{content: "http://b", Options: o})
+	})
+
+	t.Run("resize drops least recently used", func(t *testing.T) {
+		c := NewCache(3)
+		for _, content := range []string{"http://a", "http://b", "http://c", "http://a"} {
+			_, err := c.Encode(content, o)
+			require.NoError(t, err)
+		}
+
+		c.Resize(2)
+		assert.Equal(t, 2, c.Len())
+		assert.NotContains(t, c.items, cacheKey{content: "http://b", Options: o})
+
+		c.Resize(0)
+		assert.Equal(t, 0, c.Len())
+		_, err := c.Encode("http://a", o)
+		require.NoError(t, err)
+		assert.Equal(t, 0, c.Len())
+	})
+
+	t.Run("zero capacity disables caching", func(t *testing.T) {
+		c := NewCache(0)
+		_, err := c.Encode("http://a", o)
+		require.NoError(t, err)
+		assert.Equal(t, 0, c.Len())
+	})
+}
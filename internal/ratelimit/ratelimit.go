@@ -0,0 +1,135 @@
+// Package ratelimit provides a limiter of failed attempts, used to slow down password guessing.
+// Every key may fail a fixed number of times within a window; further attempts are refused
+// until the window that started with the first failure has passed.
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Default limits for failed password attempts.
+const (
+	DefaultMaxFailures = 5
+	DefaultWindow      = 15 * time.Minute
+)
+
+// attempts tracks the failures of a key within its window.
+type attempts struct {
+	start    time.Time
+	failures int
+}
+
+// Limiter counts failed attempts per key. A nil Limiter never blocks.
+type Limiter struct {
+	lastCleanup time.Time
+	now         func() time.Time
+	windows     map[string]*attempts
+	window      time.Duration
+	maxFailures int
+	mu          sync.Mutex
+}
+
+// New creates a Limiter that allows maxFailures failed attempts per key within the window.
+// A non-positive maxFailures disables limiting.
+func New(maxFailures int, window time.Duration) *Limiter {
+	return &Limiter{
+		now:         time.Now,
+		windows:     make(map[string]*attempts),
+		window:      window,
+		maxFailures: maxFailures,
+	}
+}
+
+// SetLimits changes the failures allowed per key and the window they are counted in, while l is in use.
+// Failures already counted are kept and judged by the new limits.
+func (l *Limiter) SetLimits(maxFailures int, window time.Duration) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.maxFailures = maxFailures
+	l.window = window
+}
+
+// Allow reports whether another attempt is allowed for key, and if not, how long the caller has to wait.
+func (l *Limiter) Allow(key string) (bool, time.Duration) {
+	if l == nil {
+		return true, 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.maxFailures <= 0 {
+		return true, 0
+	}
+
+	w, ok := l.windows[key]
+	if !ok {
+		return true, 0
+	}
+
+	elapsed := l.now().Sub(w.start)
+	if elapsed >= l.window {
+		delete(l.windows, key)
+		return true, 0
+	}
+	if w.failures < l.maxFailures {
+		return true, 0
+	}
+	return false, l.window - elapsed
+}
+
+// Fail records a failed attempt for key.
+func (l *Limiter) Fail(key string) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.maxFailures <= 0 {
+		return
+	}
+
+	now := l.now()
+	l.cleanup(now)
+
+	w, ok := l.windows[key]
+	if !ok || now.Sub(w.start) >= l.window {
+		w = &attempts{start: now}
+		l.windows[key] = w
+	}
+	w.failures++
+}
+
+// Reset forgets the failures of key, e.g. after a successful attempt.
+func (l *Limiter) Reset(key string) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.windows, key)
+}
+
+// cleanup drops expired windows at most once per window, so keys that stopped failing do not accumulate.
+func (l *Limiter) cleanup(now time.Time) {
+	if now.Sub(l.lastCleanup) < l.window {
+		return
+	}
+	l.lastCleanup = now
+
+	for key, w := range l.windows {
+		if now.Sub(w.start) >= l.window {
+			delete(l.windows, key)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiter(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	l := New(3, time.Minute)
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		ok, _ := l.Allow("a")
+		assert.True(t, ok, "attempt %d", i+1)
+		l.Fail("a")
+	}
+
+	ok, wait := l.Allow("a")
+	assert.False(t, ok)
+	assert.Equal(t, time.Minute, wait)
+
+	// Other keys are not affected.
+	ok, _ = l.Allow("b")
+	assert.True(t, ok)
+
+	now = now.Add(40 * time.Second)
+	ok, wait = l.Allow("a")
+	assert.False(t, ok)
+	assert.Equal(t, 20*time.Second, wait)
+
+	now = now.Add(20 * time.Second)
+	ok, _ = l.Allow("a")
+	assert.True(t, ok)
+}
+
+func TestLimiter_Reset(t *testing.T) {
+	l := New(1, time.Minute)
+
+	l.Fail("a")
+	ok, _ := l.Allow("a")
+	assert.False(t, ok)
+
+	l.Reset("a")
+	ok, _ = l.Allow("a")
+	assert.True(t, ok)
+}
+
+func TestLimiter_Cleanup(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	l := New(1, time.Minute)
+	l.now = func() time.Time { return now }
+
+	l.Fail("a")
+	now = now.Add(2 * time.Minute)
+	l.Fail("b")
+
+	assert.Len(t, l.windows, 1)
+	assert.Contains(t, l.windows, "b")
+}
+
+func TestLimiter_Disabled(t *testing.T) {
+	var nilLimiter *Limiter
+	nilLimiter.Fail("a")
+	ok, _ := nilLimiter.Allow("a")
+	assert.True(t, ok)
+
+	l := New(0, time.Minute)
+	l.Fail("a")
+	ok, _ = l.Allow("a")
+	assert.True(t, ok)
+}
+
+func TestLimiter_SetLimits(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	l := New(3, time.Minute)
+	l.now = func() time.Time { return now }
+
+	l.Fail("a")
+	l.Fail("a")
+	ok, _ := l.Allow("a")
+	assert.True(t, ok)
+
+	// Failures counted before are judged by the new limits
+	l.SetLimits(2, 10*time.Minute)
+	now = now.Add(2 * time.Minute)
+	ok, wait := l.Allow("a")
+	assert.False(t, ok)
+	assert.Equal(t, 8*time.Minute, wait)
+
+	l.SetLimits(0, time.Minute)
+	ok, _ = l.Allow("a")
+	assert.True(t, ok)
+
+	var nilLimiter *Limiter
+	nilLimiter.SetLimits(1, time.Minute)
+}
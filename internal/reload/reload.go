@@ -0,0 +1,163 @@
+// Package reload applies configuration changes while the service runs, on SIGHUP and when the
+// configuration file changes.
+//
+// Only the settings that are safe to change at runtime are applied: the trusted subnet, the log level,
+// the limit of link password attempts, the size of the QR code cache, and the blocklist, which is read
+// again from its file. Each of them is swapped atomically, so requests see either the old or the new value.
+// Changes of other settings, such as listen addresses or the storage DSN, are logged and ignored until a restart.
+package reload
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/apetsko/shortugo/internal/config"
+	"github.com/apetsko/shortugo/internal/logging"
+	"github.com/apetsko/shortugo/internal/server/http/handlers"
+	"go.uber.org/zap/zapcore"
+)
+
+// Reloader keeps the running configuration and applies the changes of the one loaded again.
+type Reloader struct {
+	load    func() (*config.Config, error)
+	running *config.Config
+	handler *handlers.URLHandler
+	logger  *logging.Logger
+	stamp   fileStamp  // Stamp of the configuration file when it was last read.
+	mu      sync.Mutex // Serializes reloads.
+}
+
+// fileStamp tells whether a file changed since it was read.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// New creates a Reloader of the running configuration, which handler and logger were set up with.
+// load builds the configuration again, the way running was built.
+func New(running *config.Config, load func() (*config.Config, error), handler *handlers.URLHandler, logger *logging.Logger) *Reloader {
+	r := &Reloader{load: load, running: running, handler: handler, logger: logger}
+	r.stamp, _ = r.stat()
+	return r
+}
+
+// Config returns the running configuration.
+func (r *Reloader) Config() *config.Config {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.running
+}
+
+// Reload loads the configuration again and applies the settings that changed and can change at runtime.
+// Changed settings that need a restart are logged and keep their running value. Nothing is applied
+// when the configuration is invalid.
+func (r *Reloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.stamp, _ = r.stat()
+
+	next, err := r.load()
+	if err != nil {
+		return err
+	}
+	if err := next.Validate(); err != nil {
+		return err
+	}
+
+	updated, applied, restart := r.running.Update(next)
+	for _, key := range restart {
+		r.logger.Error("Setting changed but needs a restart, keeping the running value", "setting", key)
+	}
+	if err := r.apply(updated); err != nil {
+		return err
+	}
+	r.running = updated
+
+	r.logger.Info("Reloaded configuration", "applied", applied, "restart_required", restart)
+	return nil
+}
+
+// apply sets the settings of c that can change at runtime. Every setting is checked before one is set.
+func (r *Reloader) apply(c *config.Config) error {
+	_, subnet, err := net.ParseCIDR(c.TrustedSubnet)
+	if err != nil {
+		return fmt.Errorf("invalid trusted subnet %q: %w", c.TrustedSubnet, err)
+	}
+	level, err := zapcore.ParseLevel(c.LogLevel)
+	if err != nil {
+		return err
+	}
+	// The blocklist is left as it was when its file is invalid
+	if err := r.handler.Blocklist.Reload(); err != nil {
+		return err
+	}
+
+	r.handler.TrustedSubnet.Store(subnet)
+	r.logger.SetLevel(level)
+	r.handler.PasswordAttempts.SetLimits(c.PasswordAttempts, c.PasswordWindow)
+	if r.handler.QRCodes != nil {
+		r.handler.QRCodes.Resize(c.QRCacheSize)
+	}
+	return nil
+}
+
+// Watch reloads the configuration on SIGHUP, and every interval when its file changed, until ctx is done.
+// Failed reloads are logged and keep the running configuration. A zero interval only reloads on SIGHUP.
+func (r *Reloader) Watch(ctx context.Context, interval time.Duration) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	var tick <-chan time.Time
+	if interval > 0 && r.Config().Config != "" {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			r.logger.Info("Received SIGHUP, reloading configuration")
+		case <-tick:
+			if !r.changed() {
+				continue
+			}
+			r.logger.Info("Configuration file changed, reloading configuration", "file", r.Config().Config)
+		}
+		if err := r.Reload(); err != nil {
+			r.logger.Error("Failed to reload configuration, keeping the running one", "error", err.Error())
+		}
+	}
+}
+
+// changed reports whether the configuration file differs from the one last read. A file that cannot be read
+// has no stamp, so its removal is reported once, by the reload it leads to.
+func (r *Reloader) changed() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stamp, _ := r.stat()
+	return stamp != r.stamp
+}
+
+// stat returns the stamp of the configuration file. The caller must hold r.mu or own r.
+func (r *Reloader) stat() (fileStamp, error) {
+	if r.running.Config == "" {
+		return fileStamp{}, nil
+	}
+	info, err := os.Stat(r.running.Config)
+	if err != nil {
+		return fileStamp{}, err
+	}
+	return fileStamp{modTime: info.ModTime(), size: info.Size()}, nil
+}
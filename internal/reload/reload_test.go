@@ -0,0 +1,142 @@
+package reload
+
+import (
+	"context"
+	"flag"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/apetsko/shortugo/internal/blocklist"
+	"github.com/apetsko/shortugo/internal/config"
+	"github.com/apetsko/shortugo/internal/logging"
+	"github.com/apetsko/shortugo/internal/qrcode"
+	"github.com/apetsko/shortugo/internal/ratelimit"
+	"github.com/apetsko/shortugo/internal/server/http/handlers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// setup writes the configuration file and the blocklist and returns a Reloader of the handler set up with it.
+func setup(t *testing.T, file string) (r *Reloader, h *handlers.URLHandler, path, list string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	path = filepath.Join(dir, "config.yaml")
+	list = filepath.Join(dir, "blocklist.txt")
+	require.NoError(t, os.WriteFile(path, []byte(file), 0o600))
+	require.NoError(t, os.WriteFile(list, []byte("evil.com\n"), 0o600))
+
+	load := func() (*config.Config, error) {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		return config.Load(fs, []string{"-config", path, "-blocklist", list})
+	}
+	cfg, err := load()
+	require.NoError(t, err)
+	require.NoError(t, cfg.Validate())
+
+	logger, err := logging.New(zapcore.DebugLevel)
+	require.NoError(t, err)
+	h = handlers.NewURLHandler(cfg.BaseURL, nil, logger, cfg.Secret, cfg.TrustedSubnet)
+	h.QRCodes = qrcode.NewCache(cfg.QRCacheSize)
+	h.PasswordAttempts = ratelimit.New(cfg.PasswordAttempts, cfg.PasswordWindow)
+	h.Blocklist, err = blocklist.Load(list)
+	require.NoError(t, err)
+
+	return New(cfg, load, h, logger), h, path, list
+}
+
+// subnet parses the CIDR s.
+func subnet(t *testing.T, s string) *net.IPNet {
+	t.Helper()
+	_, n, err := net.ParseCIDR(s)
+	require.NoError(t, err)
+	return n
+}
+
+func TestReloader_Reload(t *testing.T) {
+	r, h, path, list := setup(t, `
+http:
+  address: ":8001"
+auth:
+  trusted_subnet: 10.0.0.0/8
+  password_attempts: 1
+log:
+  level: debug
+`)
+	assert.Equal(t, subnet(t, "10.0.0.0/8"), h.TrustedSubnet.Load())
+
+	require.NoError(t, os.WriteFile(path, []byte(`
+http:
+  address: ":9001"
+storage:
+  database_dsn: postgres://db/short
+auth:
+  trusted_subnet: 192.168.0.0/16
+  password_attempts: 3
+log:
+  level: warn
+`), 0o600))
+	require.NoError(t, os.WriteFile(list, []byte("evil.com\nmalware.example\n"), 0o600))
+	require.NoError(t, r.Reload())
+
+	// Applied at runtime
+	assert.Equal(t, subnet(t, "192.168.0.0/16"), h.TrustedSubnet.Load())
+	assert.False(t, h.Logger.Desugar().Core().Enabled(zapcore.InfoLevel))
+	assert.Equal(t, []string{"evil.com", "malware.example"}, h.Blocklist.Domains())
+	h.PasswordAttempts.Fail("client")
+	ok, _ := h.PasswordAttempts.Allow("client")
+	assert.True(t, ok, "3 attempts are allowed")
+
+	// Needing a restart
+	assert.Equal(t, ":8001", r.Config().Host)
+	assert.Empty(t, r.Config().DatabaseDSN)
+	assert.Equal(t, "warn", r.Config().LogLevel)
+
+	// An invalid configuration changes nothing
+	require.NoError(t, os.WriteFile(path, []byte(`
+auth:
+  trusted_subnet: 172.16.0.0/12
+  password_attempts: -1
+`), 0o600))
+	assert.ErrorContains(t, r.Reload(), "PasswordAttempts")
+	assert.Equal(t, subnet(t, "192.168.0.0/16"), h.TrustedSubnet.Load())
+
+	require.NoError(t, os.WriteFile(path, []byte("auth:\n  trusted_subnet: not-a-subnet\n"), 0o600))
+	assert.ErrorContains(t, r.Reload(), "trusted subnet")
+	assert.Equal(t, subnet(t, "192.168.0.0/16"), h.TrustedSubnet.Load())
+	assert.Equal(t, "warn", r.Config().LogLevel)
+}
+
+func TestReloader_Watch(t *testing.T) {
+	r, h, path, list := setup(t, "auth:\n  trusted_subnet: 10.0.0.0/8\n")
+
+	// SIGHUP is caught by the test as well, so it never stops the process
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go r.Watch(ctx, 10*time.Millisecond)
+
+	// Changed file
+	require.NoError(t, os.WriteFile(path, []byte("auth:\n  trusted_subnet: 192.168.0.0/16\n"), 0o600))
+	assert.Eventually(t, func() bool {
+		return h.TrustedSubnet.Load().String() == "192.168.0.0/16"
+	}, time.Second, 5*time.Millisecond)
+
+	// SIGHUP rereads the blocklist, whose file is not watched
+	require.NoError(t, os.WriteFile(list, []byte("malware.example\n"), 0o600))
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+	assert.Eventually(t, func() bool {
+		return h.Blocklist.Blocked("malware.example")
+	}, time.Second, 5*time.Millisecond)
+}
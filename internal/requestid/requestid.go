@@ -0,0 +1,124 @@
+// Package requestid gives each HTTP request and gRPC call an ID to correlate its log lines by.
+// A client may send its own ID in the X-Request-ID header or x-request-id metadata; otherwise one is generated.
+// The ID is sent back under the same name, and calls of the REST gateway keep the ID of their HTTP request.
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Names of the request ID in HTTP headers and gRPC metadata.
+const (
+	Header      = "X-Request-ID"
+	MetadataKey = "x-request-id"
+)
+
+// maxLen is the length of the longest ID accepted from a client.
+const maxLen = 128
+
+// ctxKey is the context key of the request ID.
+type ctxKey struct{}
+
+// New returns a random request ID.
+func New() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// NewContext returns a copy of ctx carrying the request ID id.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext returns the request ID of ctx, or "" when it has none.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKey{}).(string)
+	return id
+}
+
+// valid reports whether a client sent ID can be used: IDs end up in logs, so only short
+// IDs made of letters, digits and the characters "-_.:" are accepted.
+func valid(id string) bool {
+	if id == "" || len(id) > maxLen {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// orNew returns id when it is valid and a new ID otherwise.
+func orNew(id string) string {
+	if valid(id) {
+		return id
+	}
+	return New()
+}
+
+// Middleware puts the request ID of the X-Request-ID header, or a new one, in the request context
+// and the response headers.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := orNew(r.Header.Get(Header))
+		w.Header().Set(Header, id)
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), id)))
+	})
+}
+
+// UnaryServerInterceptor puts the request ID of the x-request-id metadata, or a new one,
+// in the call context and the response header metadata.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, id := fromIncoming(ctx)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(MetadataKey, id))
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor does what UnaryServerInterceptor does for streaming calls.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, id := fromIncoming(ss.Context())
+		_ = ss.SetHeader(metadata.Pairs(MetadataKey, id))
+		return handler(srv, &stream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// fromIncoming returns ctx with the request ID of its incoming metadata, the one it already carries
+// or a new one, in that order.
+func fromIncoming(ctx context.Context) (context.Context, string) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(MetadataKey); len(values) > 0 && valid(values[0]) {
+			return NewContext(ctx, values[0]), values[0]
+		}
+	}
+	if id := FromContext(ctx); id != "" {
+		return ctx, id
+	}
+	id := New()
+	return NewContext(ctx, id), id
+}
+
+// stream is a server stream whose context carries the request ID.
+type stream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context of the call with its request ID.
+func (s *stream) Context() context.Context {
+	return s.ctx
+}
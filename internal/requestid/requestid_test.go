@@ -0,0 +1,74 @@
+package requestid
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		keep   bool
+	}{
+		{name: "client ID", header: "req-42.a_b:c", keep: true},
+		{name: "no ID", header: ""},
+		{name: "ID with spaces", header: "req 42"},
+		{name: "ID with a newline", header: "req\nlevel=error"},
+		{name: "long ID", header: strings.Repeat("a", maxLen+1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			h := Middleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				got = FromContext(r.Context())
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				r.Header.Set(Header, tt.header)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			require.NotEmpty(t, got)
+			assert.Equal(t, got, w.Header().Get(Header))
+			if tt.keep {
+				assert.Equal(t, tt.header, got)
+			} else {
+				assert.Len(t, got, 32)
+			}
+		})
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	interceptor := UnaryServerInterceptor()
+	call := func(ctx context.Context) string {
+		var got string
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, _ any) (any, error) {
+			got = FromContext(ctx)
+			return nil, nil
+		})
+		require.NoError(t, err)
+		return got
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(MetadataKey, "req-42"))
+	assert.Equal(t, "req-42", call(ctx))
+
+	// Calls of the gateway keep the ID of their HTTP request
+	assert.Equal(t, "http-7", call(NewContext(context.Background(), "http-7")))
+
+	assert.Len(t, call(context.Background()), 32)
+	assert.NotEqual(t, call(context.Background()), call(context.Background()))
+}
@@ -0,0 +1,149 @@
+// Package routing picks the destination of a link with conditional redirect rules.
+// Visitors are described by their platform class from the User-Agent header, their preferred
+// language from Accept-Language and their country from a GeoIP database.
+package routing
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/apetsko/shortugo/internal/models"
+)
+
+// Visitor holds the properties of a client that rules match on.
+type Visitor struct {
+	Platform string // One of the models.Platform* classes except mobile and desktop, or empty.
+	Language string // Most preferred language tag in lower case, or empty.
+	Country  string // ISO 3166-1 alpha-2 country code, or empty.
+}
+
+// Destination returns the URL of the first rule matching v, or fallback when no rule does.
+func Destination(rules []models.RoutingRule, v Visitor, fallback string) string {
+	if url, ok := Match(rules, v); ok {
+		return url
+	}
+	return fallback
+}
+
+// Match returns the URL of the first rule matching v and reports whether any rule matched.
+func Match(rules []models.RoutingRule, v Visitor) (string, bool) {
+	for _, rule := range rules {
+		if Matches(rule, v) {
+			return rule.URL, true
+		}
+	}
+	return "", false
+}
+
+// PickVariant selects the variant that n falls on when the weights are laid out in order.
+// n must be in [0, total weight); out of range values select the last variant.
+func PickVariant(variants []models.Variant, n int) models.Variant {
+	for _, v := range variants {
+		if n < v.Weight {
+			return v
+		}
+		n -= v.Weight
+	}
+	return variants[len(variants)-1]
+}
+
+// TotalWeight returns the sum of the weights of the variants.
+func TotalWeight(variants []models.Variant) int {
+	total := 0
+	for _, v := range variants {
+		total += v.Weight
+	}
+	return total
+}
+
+// Matches reports whether v satisfies every condition of rule.
+func Matches(rule models.RoutingRule, v Visitor) bool {
+	return matchPlatform(rule.Platform, v.Platform) &&
+		matchLanguage(rule.Language, v.Language) &&
+		(rule.Country == "" || strings.EqualFold(rule.Country, v.Country))
+}
+
+// matchPlatform reports whether platform belongs to the class wanted by a rule.
+func matchPlatform(want, platform string) bool {
+	switch want {
+	case "":
+		return true
+	case models.PlatformMobile:
+		return platform == models.PlatformIOS || platform == models.PlatformAndroid
+	case models.PlatformDesktop:
+		return platform == models.PlatformWindows || platform == models.PlatformMacOS || platform == models.PlatformLinux
+	default:
+		return platform == want
+	}
+}
+
+// matchLanguage reports whether lang is the language wanted by a rule or one of its regional variants.
+func matchLanguage(want, lang string) bool {
+	if want == "" {
+		return true
+	}
+	want = strings.ToLower(want)
+	return lang == want || strings.HasPrefix(lang, want+"-")
+}
+
+// Platform classifies a User-Agent header. Unknown clients, such as bots, get an empty platform.
+// iOS and Android are checked first, because their browsers also mention desktop systems.
+func Platform(userAgent string) string {
+	ua := strings.ToLower(userAgent)
+	switch {
+	case strings.Contains(ua, "iphone"), strings.Contains(ua, "ipad"), strings.Contains(ua, "ipod"):
+		return models.PlatformIOS
+	case strings.Contains(ua, "android"):
+		return models.PlatformAndroid
+	case strings.Contains(ua, "windows"):
+		return models.PlatformWindows
+	case strings.Contains(ua, "macintosh"), strings.Contains(ua, "mac os x"):
+		return models.PlatformMacOS
+	case strings.Contains(ua, "linux"), strings.Contains(ua, "x11"):
+		return models.PlatformLinux
+	default:
+		return ""
+	}
+}
+
+// Language returns the most preferred language of an Accept-Language header in lower case.
+// Languages with equal weight keep their order; "*" and languages with q=0 are skipped.
+func Language(acceptLanguage string) string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+
+	var langs []weighted
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || strings.TrimSpace(name) != "q" {
+				continue
+			}
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				parsed = 0
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+		langs = append(langs, weighted{tag: tag, q: q})
+	}
+
+	if len(langs) == 0 {
+		return ""
+	}
+	sort.SliceStable(langs, func(i, j int) bool { return langs[i].q > langs[j].q })
+	return langs[0].tag
+}
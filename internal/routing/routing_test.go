@@ -0,0 +1,134 @@
+package routing
+
+import (
+	"testing"
+
+	"github.com/apetsko/shortugo/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlatform(t *testing.T) {
+	tests := []struct {
+		name string
+		ua   string
+		want string
+	}{
+		{
+			name: "iPhone",
+			ua:   "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/15E148",
+			want: models.PlatformIOS,
+		},
+		{
+			name: "iPad",
+			ua:   "Mozilla/5.0 (iPad; CPU OS 16_6 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.6 Safari/604.1",
+			want: models.PlatformIOS,
+		},
+		{
+			name: "Android",
+			ua:   "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0 Mobile Safari/537.36",
+			want: models.PlatformAndroid,
+		},
+		{
+			name: "Windows",
+			ua:   "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0 Safari/537.36",
+			want: models.PlatformWindows,
+		},
+		{
+			name: "macOS",
+			ua:   "Mozilla/5.0 (Macintosh; Intel Mac OS X 14_1) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Safari/605.1.15",
+			want: models.PlatformMacOS,
+		},
+		{
+			name: "Linux",
+			ua:   "Mozilla/5.0 (X11; Linux x86_64; rv:120.0) Gecko/20100101 Firefox/120.0",
+			want: models.PlatformLinux,
+		},
+		{
+			name: "unknown client",
+			ua:   "curl/8.4.0",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Platform(tt.ua))
+		})
+	}
+}
+
+func TestLanguage(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{name: "single", header: "de-AT", want: "de-at"},
+		{name: "first of equal weights", header: "fr-CH, fr, en", want: "fr-ch"},
+		{name: "highest weight", header: "en;q=0.5, de;q=0.9, fr;q=0.7", want: "de"},
+		{name: "wildcard and zero weight skipped", header: "*, es;q=0, it;q=0.1", want: "it"},
+		{name: "malformed weight", header: "ru;q=abc, pl;q=0.3", want: "pl"},
+		{name: "empty", header: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Language(tt.header))
+		})
+	}
+}
+
+func TestDestination(t *testing.T) {
+	rules := []models.RoutingRule{
+		{Platform: models.PlatformIOS, URL: "https://apps.apple.com/app/id1"},
+		{Platform: models.PlatformAndroid, URL: "https://play.google.com/store/apps/details?id=app"},
+		{Language: "de", Country: "AT", URL: "https://example.com/at"},
+		{Language: "de", URL: "https://example.com/de"},
+		{Platform: models.PlatformDesktop, Country: "US", URL: "https://example.com/us"},
+	}
+	fallback := "https://example.com"
+
+	tests := []struct {
+		name    string
+		visitor Visitor
+		want    string
+	}{
+		{name: "iOS", visitor: Visitor{Platform: models.PlatformIOS, Language: "de"}, want: "https://apps.apple.com/app/id1"},
+		{name: "Android", visitor: Visitor{Platform: models.PlatformAndroid}, want: "https://play.google.com/store/apps/details?id=app"},
+		{name: "all conditions of a rule", visitor: Visitor{Language: "de-at", Country: "AT"}, want: "https://example.com/at"},
+		{name: "regional language variant", visitor: Visitor{Language: "de-ch", Country: "CH"}, want: "https://example.com/de"},
+		{name: "language prefix is not a variant", visitor: Visitor{Language: "dea"}, want: fallback},
+		{name: "platform class", visitor: Visitor{Platform: models.PlatformLinux, Country: "US"}, want: "https://example.com/us"},
+		{name: "country without GeoIP", visitor: Visitor{Platform: models.PlatformLinux}, want: fallback},
+		{name: "fallback", visitor: Visitor{}, want: fallback},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Destination(rules, tt.visitor, fallback))
+		})
+	}
+}
+
+func TestPickVariant(t *testing.T) {
+	variants := []models.Variant{
+		{Name: "a", URL: "https://example.com/a", Weight: 1},
+		{Name: "b", URL: "https://example.com/b", Weight: 3},
+	}
+
+	assert.Equal(t, 4, TotalWeight(variants))
+
+	tests := []struct {
+		want string
+		n    int
+	}{
+		{n: 0, want: "a"},
+		{n: 1, want: "b"},
+		{n: 3, want: "b"},
+		{n: 4, want: "b"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, PickVariant(variants, tt.n).Name, "n=%d", tt.n)
+	}
+}
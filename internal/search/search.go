@@ -0,0 +1,194 @@
+// Package search finds the links of a user by the words of their destination URL, alias, title and tags.
+// A query matches a link when every word of the query is a prefix of one of the words of the link,
+// so "exa doc" finds https://example.com/docs. Words are runs of letters and digits in lower case.
+package search
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/apetsko/shortugo/internal/models"
+)
+
+// Limits of a search.
+const (
+	MaxTerms   = 8   // Words of a query beyond this are ignored.
+	MaxResults = 100 // Links returned by a search at most.
+)
+
+// Words splits s into lower-case runs of letters and digits.
+func Words(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Terms returns the distinct words of a query, at most MaxTerms of them. An empty result matches nothing.
+func Terms(query string) []string {
+	var terms []string
+	for _, w := range Words(query) {
+		if !slices.Contains(terms, w) {
+			terms = append(terms, w)
+		}
+	}
+	if len(terms) > MaxTerms {
+		terms = terms[:MaxTerms]
+	}
+	return terms
+}
+
+// Document returns the searchable text of r: its alias, destination URL, title and tags.
+func Document(r models.URLRecord) string {
+	return strings.Join(append([]string{r.ID, r.URL, r.Title}, r.Tags...), " ")
+}
+
+// Index is an in-memory inverted index from words to the links of each user.
+// The zero value is not usable, create indexes with NewIndex.
+type Index struct {
+	postings map[string]map[string]map[string]int // user ID -> word -> link ID -> occurrences
+	words    map[string][]string                  // link ID -> distinct words, to unindex the link
+	owners   map[string]string                    // link ID -> user ID
+	mu       sync.RWMutex
+}
+
+// NewIndex creates an empty Index.
+func NewIndex() *Index {
+	return &Index{
+		postings: make(map[string]map[string]map[string]int),
+		words:    make(map[string][]string),
+		owners:   make(map[string]string),
+	}
+}
+
+// Add indexes r, replacing what was indexed for its ID before.
+func (x *Index) Add(r models.URLRecord) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	x.remove(r.ID)
+
+	user := x.postings[r.UserID]
+	if user == nil {
+		user = make(map[string]map[string]int)
+		x.postings[r.UserID] = user
+	}
+
+	counts := make(map[string]int)
+	for _, w := range Words(Document(r)) {
+		counts[w]++
+	}
+
+	words := make([]string, 0, len(counts))
+	for w, n := range counts {
+		if user[w] == nil {
+			user[w] = make(map[string]int)
+		}
+		user[w][r.ID] = n
+		words = append(words, w)
+	}
+	x.words[r.ID] = words
+	x.owners[r.ID] = r.UserID
+}
+
+// Has reports whether a link with the ID is indexed.
+func (x *Index) Has(id string) bool {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	_, ok := x.owners[id]
+	return ok
+}
+
+// Remove unindexes the links with the given IDs owned by userID; links of other users are kept.
+func (x *Index) Remove(userID string, ids ...string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	for _, id := range ids {
+		if owner, ok := x.owners[id]; ok && owner == userID {
+			x.remove(id)
+		}
+	}
+}
+
+// remove unindexes the link with the ID. The caller must hold x.mu.
+func (x *Index) remove(id string) {
+	owner, ok := x.owners[id]
+	if !ok {
+		return
+	}
+
+	user := x.postings[owner]
+	for _, w := range x.words[id] {
+		delete(user[w], id)
+		if len(user[w]) == 0 {
+			delete(user, w)
+		}
+	}
+	if len(user) == 0 {
+		delete(x.postings, owner)
+	}
+	delete(x.words, id)
+	delete(x.owners, id)
+}
+
+// Search returns the IDs of the links of userID matching all terms, best matches first, at most MaxResults.
+// Links where a term is a whole word rank above those where it only starts one; ties are ordered by ID.
+func (x *Index) Search(userID string, terms []string) []string {
+	if len(terms) == 0 {
+		return nil
+	}
+
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	user := x.postings[userID]
+
+	var scores map[string]int
+	for _, term := range terms {
+		// Every word starting with the term counts, whole words twice as much
+		hits := make(map[string]int)
+		for w, ids := range user {
+			if !strings.HasPrefix(w, term) {
+				continue
+			}
+			weight := 1
+			if w == term {
+				weight = 2
+			}
+			for id, n := range ids {
+				hits[id] += weight * n
+			}
+		}
+
+		if scores == nil {
+			scores = hits
+			continue
+		}
+		for id := range scores {
+			if hits[id] == 0 {
+				delete(scores, id)
+			} else {
+				scores[id] += hits[id]
+			}
+		}
+	}
+
+	ids := make([]string, 0, len(scores))
+	for id := range scores {
+		ids = append(ids, id)
+	}
+	slices.SortFunc(ids, func(a, b string) int {
+		if scores[a] != scores[b] {
+			return scores[b] - scores[a]
+		}
+		return strings.Compare(a, b)
+	})
+
+	if len(ids) > MaxResults {
+		ids = ids[:MaxResults]
+	}
+	return ids
+}
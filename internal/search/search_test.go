@@ -0,0 +1,91 @@
+package search
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/apetsko/shortugo/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTerms(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{name: "words", query: "Example  Docs", want: []string{"example", "docs"}},
+		{name: "URL", query: "https://Example.com/go-intro", want: []string{"https", "example", "com", "go", "intro"}},
+		{name: "duplicates", query: "go go GO", want: []string{"go"}},
+		{name: "unicode", query: "Straße über", want: []string{"straße", "über"}},
+		{name: "punctuation only", query: " -/- ", want: nil},
+		{name: "too many", query: "a b c d e f g h i j", want: []string{"a", "b", "c", "d", "e", "f", "g", "h"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Terms(tt.query))
+		})
+	}
+}
+
+func TestIndex_Search(t *testing.T) {
+	x := NewIndex()
+	x.Add(models.URLRecord{ID: "docs1", URL: "https://example.com/docs", UserID: "1", Title: "Go documentation"})
+	x.Add(models.URLRecord{ID: "blog1", URL: "https://blog.example.org/go-generics", UserID: "1", Tags: []string{"golang"}})
+	x.Add(models.URLRecord{ID: "news1", URL: "https://news.example.net", UserID: "1", Tags: []string{"black-friday"}})
+	x.Add(models.URLRecord{ID: "other", URL: "https://example.com/docs", UserID: "2"})
+
+	tests := []struct {
+		name   string
+		userID string
+		query  string
+		want   []string
+	}{
+		{name: "prefix of URL word", userID: "1", query: "exam", want: []string{"blog1", "docs1", "news1"}},
+		{name: "all terms must match", userID: "1", query: "example docs", want: []string{"docs1"}},
+		{name: "title", userID: "1", query: "documentation", want: []string{"docs1"}},
+		{name: "alias", userID: "1", query: "BLOG1", want: []string{"blog1"}},
+		{name: "tag", userID: "1", query: "friday", want: []string{"news1"}},
+		{name: "whole word ranks first", userID: "1", query: "go", want: []string{"blog1", "docs1"}},
+		{name: "no match", userID: "1", query: "example missing", want: []string{}},
+		{name: "other user", userID: "2", query: "docs", want: []string{"other"}},
+		{name: "unknown user", userID: "3", query: "docs", want: []string{}},
+		{name: "empty query", userID: "1", query: "", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, x.Search(tt.userID, Terms(tt.query)))
+		})
+	}
+}
+
+func TestIndex_AddRemove(t *testing.T) {
+	x := NewIndex()
+	x.Add(models.URLRecord{ID: "a", URL: "https://example.com/old", UserID: "1"})
+	x.Add(models.URLRecord{ID: "a", URL: "https://example.com/new", UserID: "1"})
+
+	assert.Empty(t, x.Search("1", Terms("old")))
+	assert.Equal(t, []string{"a"}, x.Search("1", Terms("new")))
+
+	// Links of other users are not removed
+	x.Remove("2", "a")
+	assert.True(t, x.Has("a"))
+
+	x.Remove("1", "a", "missing")
+	assert.False(t, x.Has("a"))
+	assert.Empty(t, x.Search("1", Terms("example")))
+	assert.Empty(t, x.postings)
+}
+
+func TestIndex_MaxResults(t *testing.T) {
+	x := NewIndex()
+	for i := range MaxResults + 10 {
+		x.Add(models.URLRecord{ID: fmt.Sprintf("id%03d", i), URL: "https://example.com", UserID: "1"})
+	}
+
+	ids := x.Search("1", Terms("example"))
+	assert.Len(t, ids, MaxResults)
+	assert.Equal(t, "id000", ids[0])
+}
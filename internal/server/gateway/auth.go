@@ -0,0 +1,155 @@
+package gateway
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+
+	grpch "github.com/apetsko/shortugo/internal/server/grpc/handlers"
+	httph "github.com/apetsko/shortugo/internal/server/http/handlers"
+	pb "github.com/apetsko/shortugo/proto"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// userKey is the metadata key the user of the v1 cookie is passed to the gRPC handlers under.
+// It is set by the gateway alone: the same key sent by a client is dropped.
+const userKey = "x-shortugo-user"
+
+// annotator returns the metadata of the user authenticated by the cookie of r, empty for anonymous requests,
+// and of the client IP, which the handlers rate-limit and audit REST calls by.
+func annotator(h *httph.URLHandler) func(context.Context, *http.Request) metadata.MD {
+	return func(_ context.Context, r *http.Request) metadata.MD {
+		return metadata.Pairs(userKey, h.UserID(r), grpch.ClientIPKey, clientIP(r))
+	}
+}
+
+// clientIP returns the IP address of the client of r, without the port of its remote address.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// headerMatcher forwards the headers the default matcher does, except the metadata only the gateway sets.
+func headerMatcher(header string) (string, bool) {
+	key, ok := runtime.DefaultHeaderMatcher(header)
+	if !ok || strings.EqualFold(key, userKey) || strings.EqualFold(key, grpch.ClientIPKey) {
+		return "", false
+	}
+	return key, true
+}
+
+// authorize checks that a call acting for userID was made by that user, as authenticated by the v1 cookie.
+func authorize(ctx context.Context, userID string) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	users := md.Get(userKey)
+	if len(users) != 1 || users[0] == "" {
+		return status.Error(codes.Unauthenticated, "authentication cookie required")
+	}
+	if users[0] != userID {
+		return status.Error(codes.PermissionDenied, "user_id is not the authenticated user")
+	}
+	return nil
+}
+
+// userRequest is a request acting for a user.
+type userRequest interface {
+	GetUserId() string
+}
+
+// forUser makes the call rpc(ctx, req) once req is authorized.
+func forUser[Req userRequest, Resp any](ctx context.Context, req Req, rpc func(context.Context, Req) (Resp, error)) (Resp, error) {
+	if err := authorize(ctx, req.GetUserId()); err != nil {
+		var zero Resp
+		return zero, err
+	}
+	return rpc(ctx, req)
+}
+
+// authorized is the URLShortener service of the gateway. gRPC clients are trusted with the user ID they send,
+// REST clients are not: every method taking a user ID is made for the user of the cookie only.
+// Methods added to the gateway that act for a user must be guarded here.
+type authorized struct {
+	pb.URLShortenerServer
+}
+
+// Shorten shortens a URL for the authenticated user.
+func (a authorized) Shorten(ctx context.Context, req *pb.ShortenRequest) (*pb.ShortenResponse, error) {
+	return forUser(ctx, req, a.URLShortenerServer.Shorten)
+}
+
+// ShortenBatch shortens URLs for the authenticated user.
+func (a authorized) ShortenBatch(ctx context.Context, req *pb.ShortenBatchRequest) (*pb.ShortenBatchResponse, error) {
+	return forUser(ctx, req, a.URLShortenerServer.ShortenBatch)
+}
+
+// ListUserURLs lists the links of the authenticated user.
+func (a authorized) ListUserURLs(ctx context.Context, req *pb.ListUserURLsRequest) (*pb.ListUserURLsResponse, error) {
+	return forUser(ctx, req, a.URLShortenerServer.ListUserURLs)
+}
+
+// SearchUserURLs searches the links of the authenticated user.
+func (a authorized) SearchUserURLs(ctx context.Context, req *pb.SearchUserURLsRequest) (*pb.ListUserURLsResponse, error) {
+	return forUser(ctx, req, a.URLShortenerServer.SearchUserURLs)
+}
+
+// DeleteUserURLs deletes links of the authenticated user.
+func (a authorized) DeleteUserURLs(ctx context.Context, req *pb.DeleteUserURLsRequest) (*pb.DeleteUserURLsResponse, error) {
+	return forUser(ctx, req, a.URLShortenerServer.DeleteUserURLs)
+}
+
+// GetUTMTemplate returns the default UTM tags of the authenticated user.
+func (a authorized) GetUTMTemplate(ctx context.Context, req *pb.GetUTMTemplateRequest) (*pb.UTMTemplateResponse, error) {
+	return forUser(ctx, req, a.URLShortenerServer.GetUTMTemplate)
+}
+
+// SetUTMTemplate replaces the default UTM tags of the authenticated user.
+func (a authorized) SetUTMTemplate(ctx context.Context, req *pb.SetUTMTemplateRequest) (*pb.UTMTemplateResponse, error) {
+	return forUser(ctx, req, a.URLShortenerServer.SetUTMTemplate)
+}
+
+// SetLinkPassword sets the password of a link of the authenticated user.
+func (a authorized) SetLinkPassword(ctx context.Context, req *pb.SetLinkPasswordRequest) (*pb.SetLinkPasswordResponse, error) {
+	return forUser(ctx, req, a.URLShortenerServer.SetLinkPassword)
+}
+
+// GetLinkRules returns the routing rules of a link of the authenticated user.
+func (a authorized) GetLinkRules(ctx context.Context, req *pb.GetLinkRulesRequest) (*pb.LinkRulesResponse, error) {
+	return forUser(ctx, req, a.URLShortenerServer.GetLinkRules)
+}
+
+// SetLinkRules replaces the routing rules of a link of the authenticated user.
+func (a authorized) SetLinkRules(ctx context.Context, req *pb.SetLinkRulesRequest) (*pb.LinkRulesResponse, error) {
+	return forUser(ctx, req, a.URLShortenerServer.SetLinkRules)
+}
+
+// GetLinkVariants returns the A/B variants of a link of the authenticated user.
+func (a authorized) GetLinkVariants(ctx context.Context, req *pb.GetLinkVariantsRequest) (*pb.LinkVariantsResponse, error) {
+	return forUser(ctx, req, a.URLShortenerServer.GetLinkVariants)
+}
+
+// SetLinkVariants replaces the A/B variants of a link of the authenticated user.
+func (a authorized) SetLinkVariants(ctx context.Context, req *pb.SetLinkVariantsRequest) (*pb.LinkVariantsResponse, error) {
+	return forUser(ctx, req, a.URLShortenerServer.SetLinkVariants)
+}
+
+// AddLinkTags tags a link of the authenticated user.
+func (a authorized) AddLinkTags(ctx context.Context, req *pb.LinkTagsRequest) (*pb.LinkLabelsResponse, error) {
+	return forUser(ctx, req, a.URLShortenerServer.AddLinkTags)
+}
+
+// RemoveLinkTags untags a link of the authenticated user.
+func (a authorized) RemoveLinkTags(ctx context.Context, req *pb.LinkTagsRequest) (*pb.LinkLabelsResponse, error) {
+	return forUser(ctx, req, a.URLShortenerServer.RemoveLinkTags)
+}
+
+// SetLinkFolder moves a link of the authenticated user to a folder.
+func (a authorized) SetLinkFolder(ctx context.Context, req *pb.SetLinkFolderRequest) (*pb.LinkLabelsResponse, error) {
+	return forUser(ctx, req, a.URLShortenerServer.SetLinkFolder)
+}
@@ -0,0 +1,74 @@
+// Package gateway exposes the gRPC URLShortener service as a JSON REST API.
+// Routes are generated from the google.api.http annotations in proto/shortugo.proto
+// and are served under Prefix together with the OpenAPI document describing them.
+package gateway
+
+import (
+	"context"
+	"net/http"
+
+	grpch "github.com/apetsko/shortugo/internal/server/grpc/handlers"
+	httph "github.com/apetsko/shortugo/internal/server/http/handlers"
+	pb "github.com/apetsko/shortugo/proto"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Prefix is the path prefix under which all gateway routes are served.
+const Prefix = "/api/v2"
+
+// OpenAPIPath is the route serving the OpenAPI document of the gateway.
+const OpenAPIPath = Prefix + "/openapi.json"
+
+// New creates an HTTP handler that translates REST calls into calls of the gRPC handlers.
+// Requests are dispatched in-process, so streaming RPCs are not exposed through the gateway.
+// Calls acting for a user are made for the user of the v1 cookie only, see authorized.
+// Passwords are only taken from request bodies, see noQueryPasswords.
+func New(ctx context.Context, h *httph.URLHandler) (http.Handler, error) {
+	mux := runtime.NewServeMux(
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+			MarshalOptions: protojson.MarshalOptions{
+				UseProtoNames:   true,
+				EmitUnpopulated: true,
+			},
+			UnmarshalOptions: protojson.UnmarshalOptions{
+				DiscardUnknown: true,
+			},
+		}),
+		runtime.WithIncomingHeaderMatcher(headerMatcher),
+		runtime.WithMetadata(annotator(h)),
+	)
+
+	if err := pb.RegisterURLShortenerHandlerServer(ctx, mux, authorized{grpch.NewHandler(h)}); err != nil {
+		return nil, err
+	}
+
+	if err := mux.HandlePath(http.MethodGet, OpenAPIPath, serveOpenAPI); err != nil {
+		return nil, err
+	}
+
+	return noQueryPasswords(mux), nil
+}
+
+// noQueryPasswords refuses requests with a password query parameter, which the gateway would otherwise
+// map into the request message: query strings end up in access logs, traces and browser histories.
+func noQueryPasswords(mux *runtime.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Has("password") {
+			_, marshaler := runtime.MarshalerForRequest(mux, r)
+			err := status.Error(codes.InvalidArgument, "password must be sent in the request body")
+			runtime.HTTPError(r.Context(), mux, marshaler, w, r, err)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// serveOpenAPI writes the embedded OpenAPI document.
+func serveOpenAPI(w http.ResponseWriter, _ *http.Request, _ map[string]string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(pb.OpenAPI)
+}
@@ -0,0 +1,291 @@
+package gateway_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apetsko/shortugo/internal/auth"
+	"github.com/apetsko/shortugo/internal/logging"
+	"github.com/apetsko/shortugo/internal/mocks"
+	"github.com/apetsko/shortugo/internal/models"
+	"github.com/apetsko/shortugo/internal/server/gateway"
+	"github.com/apetsko/shortugo/internal/server/http/handlers"
+	"github.com/apetsko/shortugo/internal/storages/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestGateway(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+
+	tests := []struct {
+		mockStorageSetup func(mockStorage *mocks.Storage)
+		headers          map[string]string
+		name             string
+		user             string
+		method           string
+		path             string
+		body             string
+		expectedBody     string
+		expectedStatus   int
+	}{
+		{
+			name:   "shorten",
+			user:   "user123",
+			method: http.MethodPost,
+			path:   "/api/v2/shorten",
+			body:   `{"original_url":"https://example.com","user_id":"user123"}`,
+			mockStorageSetup: func(mockStorage *mocks.Storage) {
+				mockStorage.On("Get", mock.Anything, mock.Anything).Return("", shared.ErrNotFound)
+				mockStorage.On("Put", mock.Anything, mock.Anything).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"short_url":"http://short.ly/`,
+		},
+		{
+			name:   "shorten conflict",
+			user:   "user123",
+			method: http.MethodPost,
+			path:   "/api/v2/shorten",
+			body:   `{"original_url":"https://example.com","user_id":"user123"}`,
+			mockStorageSetup: func(mockStorage *mocks.Storage) {
+				mockStorage.On("Get", mock.Anything, mock.Anything).Return("https://example.com", nil)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:             "shorten invalid body",
+			method:           http.MethodPost,
+			path:             "/api/v2/shorten",
+			body:             `{"original_url":`,
+			mockStorageSetup: func(mockStorage *mocks.Storage) {},
+			expectedStatus:   http.StatusBadRequest,
+		},
+		{
+			name:   "expand",
+			method: http.MethodGet,
+			path:   "/api/v2/urls/abc123",
+			mockStorageSetup: func(mockStorage *mocks.Storage) {
+				mockStorage.On("GetRecord", mock.Anything, "abc123").
+					Return(&models.URLRecord{ID: "abc123", URL: "https://example.com"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"original_url":"https://example.com"`,
+		},
+		{
+			name:   "expand not found",
+			method: http.MethodGet,
+			path:   "/api/v2/urls/missing",
+			mockStorageSetup: func(mockStorage *mocks.Storage) {
+				mockStorage.On("GetRecord", mock.Anything, "missing").Return(nil, shared.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "list user urls",
+			user:   "user123",
+			method: http.MethodGet,
+			path:   "/api/v2/users/user123/urls",
+			mockStorageSetup: func(mockStorage *mocks.Storage) {
+				mockStorage.On("ListLinksByUserID", mock.Anything, "http://short.ly", "user123").
+					Return([]models.URLRecord{{ID: "http://short.ly/abc123", URL: "https://example.com", UserID: "user123"}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"short_url":"http://short.ly/abc123"`,
+		},
+		{
+			name:   "list user urls storage error",
+			user:   "user123",
+			method: http.MethodGet,
+			path:   "/api/v2/users/user123/urls",
+			mockStorageSetup: func(mockStorage *mocks.Storage) {
+				mockStorage.On("ListLinksByUserID", mock.Anything, "http://short.ly", "user123").
+					Return(nil, errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:             "delete user urls",
+			user:             "user123",
+			method:           http.MethodDelete,
+			path:             "/api/v2/users/user123/urls",
+			body:             `{"short_url_ids":["abc123"]}`,
+			mockStorageSetup: func(mockStorage *mocks.Storage) {},
+			expectedStatus:   http.StatusOK,
+			expectedBody:     `"success":true`,
+		},
+		{
+			name:             "list urls of another user",
+			user:             "intruder",
+			method:           http.MethodGet,
+			path:             "/api/v2/users/user123/urls",
+			mockStorageSetup: func(mockStorage *mocks.Storage) {},
+			expectedStatus:   http.StatusForbidden,
+		},
+		{
+			name:             "delete urls without cookie",
+			method:           http.MethodDelete,
+			path:             "/api/v2/users/user123/urls",
+			body:             `{"short_url_ids":["abc123"]}`,
+			mockStorageSetup: func(mockStorage *mocks.Storage) {},
+			expectedStatus:   http.StatusUnauthorized,
+		},
+		{
+			name:             "shorten for another user",
+			user:             "intruder",
+			method:           http.MethodPost,
+			path:             "/api/v2/shorten",
+			body:             `{"original_url":"https://example.com","user_id":"user123"}`,
+			mockStorageSetup: func(mockStorage *mocks.Storage) {},
+			expectedStatus:   http.StatusForbidden,
+		},
+		{
+			name:             "set password of another user",
+			user:             "intruder",
+			method:           http.MethodPut,
+			path:             "/api/v2/users/user123/urls/abc123/password",
+			body:             `{"password":"hunter2"}`,
+			mockStorageSetup: func(mockStorage *mocks.Storage) {},
+			expectedStatus:   http.StatusForbidden,
+		},
+		{
+			name:             "user metadata sent by the client",
+			headers:          map[string]string{"Grpc-Metadata-X-Shortugo-User": "user123"},
+			method:           http.MethodGet,
+			path:             "/api/v2/users/user123/urls",
+			mockStorageSetup: func(mockStorage *mocks.Storage) {},
+			expectedStatus:   http.StatusUnauthorized,
+		},
+		{
+			name:   "ping",
+			method: http.MethodGet,
+			path:   "/api/v2/ping",
+			mockStorageSetup: func(mockStorage *mocks.Storage) {
+				mockStorage.On("Ping").Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:             "unknown route",
+			method:           http.MethodGet,
+			path:             "/api/v2/unknown",
+			mockStorageSetup: func(mockStorage *mocks.Storage) {},
+			expectedStatus:   http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := new(mocks.Storage)
+			mockStorage.On("GetUTMTemplate", mock.Anything, mock.Anything).Return(nil, shared.ErrNotFound).Maybe()
+			tt.mockStorageSetup(mockStorage)
+
+			authenticator := new(mocks.Authenticator)
+			if tt.user != "" {
+				authenticator.On("CookieGetUserID", mock.Anything, "secret").Return(tt.user, nil)
+			} else {
+				authenticator.On("CookieGetUserID", mock.Anything, "secret").Return("", http.ErrNoCookie)
+			}
+
+			h := handlers.NewURLHandler("http://short.ly", mockStorage, logger, "secret", "127.0.0.0/8")
+			h.Auth = authenticator
+			gw, err := gateway.New(context.Background(), h)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+
+			gw.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, rec.Body.String(), tt.expectedBody)
+			}
+			mockStorage.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGateway_ExpandPassword(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+	hash, err := auth.HashPassword("hunter2")
+	require.NoError(t, err)
+
+	mockStorage := new(mocks.Storage)
+	mockStorage.On("GetRecord", mock.Anything, "abc123").
+		Return(&models.URLRecord{ID: "abc123", URL: "https://example.com", LinkOptions: models.LinkOptions{PasswordHash: hash}}, nil)
+
+	h := handlers.NewURLHandler("http://short.ly", mockStorage, logger, "secret", "127.0.0.0/8")
+	h.PasswordAttempts.SetLimits(1, time.Minute)
+	gw, err := gateway.New(context.Background(), h)
+	require.NoError(t, err)
+
+	expand := func(method, path, body, remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		gw.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Passwords in query strings are refused before they reach the handlers
+	rec := expand(http.MethodGet, "/api/v2/urls/abc123?password=hunter2", "", "198.51.100.1:1234")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = expand(http.MethodPost, "/api/v2/urls/abc123/expand?password=hunter2", "{}", "198.51.100.1:1234")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// Failed attempts are counted per client
+	rec = expand(http.MethodPost, "/api/v2/urls/abc123/expand", `{"password":"wrong"}`, "198.51.100.1:1234")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = expand(http.MethodPost, "/api/v2/urls/abc123/expand", `{"password":"hunter2"}`, "198.51.100.1:5678")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+
+	rec = expand(http.MethodPost, "/api/v2/urls/abc123/expand", `{"password":"hunter2"}`, "198.51.100.2:1234")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"original_url":"https://example.com"`)
+
+	// The client IP cannot be chosen by the client
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/urls/abc123/expand", strings.NewReader(`{"password":"hunter2"}`))
+	req.Header.Set("Grpc-Metadata-X-Shortugo-Client-Ip", "198.51.100.3")
+	req.RemoteAddr = "198.51.100.1:1234"
+	rec = httptest.NewRecorder()
+	gw.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+}
+
+func TestGateway_OpenAPI(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+
+	h := handlers.NewURLHandler("http://short.ly", new(mocks.Storage), logger, "secret", "127.0.0.0/8")
+	gw, err := gateway.New(context.Background(), h)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	gw.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, gateway.OpenAPIPath, nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var doc struct {
+		Swagger string                    `json:"swagger"`
+		Paths   map[string]map[string]any `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+	assert.Equal(t, "2.0", doc.Swagger)
+	assert.Contains(t, doc.Paths, "/api/v2/shorten")
+	assert.Contains(t, doc.Paths["/api/v2/users/{user_id}/urls"], "delete")
+	assert.Contains(t, doc.Paths["/api/v2/urls/{short_url_id}/expand"], "post")
+}
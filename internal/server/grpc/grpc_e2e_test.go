@@ -25,7 +25,6 @@ import (
 )
 
 func TestGRPC_E2E(t *testing.T) {
-	testIP := "127.0.0.1"
 	testUser := "test-user"
 	one := "1"
 
@@ -34,6 +33,7 @@ func TestGRPC_E2E(t *testing.T) {
 	toDelete := make(chan models.BatchDeleteRequest, 1)
 
 	// Setup common mocks
+	mockStorage.On("GetUTMTemplate", mock.Anything, "test-user").Return(nil, shared.ErrNotFound)
 	mockStorage.On("Ping").Return(nil)
 	mockStorage.On("PutBatch", mock.Anything, mock.Anything).Return(nil)
 	mockStorage.On("DeleteUserURLs", mock.Anything, []string{"abc123"}, "test-user").Return(nil)
@@ -85,8 +85,9 @@ func TestGRPC_E2E(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "http://short.ly/"+shortenedID, shortenResp.GetShortUrl())
 
-	// Step 3: Expand — Get returns actual URL
-	mockStorage.On("Get", mock.Anything, shortenedID).Return(uniqueURL, nil).Once()
+	// Step 3: Expand — GetRecord returns actual URL
+	mockStorage.On("GetRecord", mock.Anything, shortenedID).
+		Return(&models.URLRecord{ID: shortenedID, URL: uniqueURL}, nil).Once()
 
 	expandResp, err := client.Expand(ctx, &pb.ExpandRequest{
 		ShortUrlId: &shortenedID,
@@ -134,7 +135,7 @@ func TestGRPC_E2E(t *testing.T) {
 	}()
 
 	// Step 7: Stats
-	statsResp, err := client.Stats(ctx, &pb.StatsRequest{Ip: &testIP})
+	statsResp, err := client.Stats(ctx, &pb.StatsRequest{})
 	require.NoError(t, err)
 	assert.Equal(t, int64(2), statsResp.GetUrlCount())
 	assert.Equal(t, int64(1), statsResp.GetUserCount())
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/apetsko/shortugo/internal/audit"
+	"github.com/apetsko/shortugo/internal/blocklist"
+	pb "github.com/apetsko/shortugo/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PurgeDeleted removes the deleted links from the storage for good and returns how many were removed.
+// Like Stats, it is only answered for trusted callers.
+//
+// Errors:
+//   - PermissionDenied if the caller is not trusted
+//   - Internal if the storage fails
+func (h *Handler) PurgeDeleted(ctx context.Context, req *pb.PurgeDeletedRequest) (*pb.PurgeDeletedResponse, error) {
+	actor, err := h.trusted(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	purged, err := h.URLHandler.Storage.PurgeDeleted(ctx)
+	if err != nil {
+		h.URLHandler.Logger.Error("Failed to purge deleted links: " + err.Error())
+		return nil, status.Error(codes.Internal, "failed to purge deleted links")
+	}
+
+	h.URLHandler.Logger.Infof("Purged %d deleted links", purged)
+	h.auditTrusted(ctx, actor, audit.ActionPurgeDeleted, "", map[string]string{"purged": strconv.FormatInt(purged, 10)})
+	return &pb.PurgeDeletedResponse{Purged: &purged}, nil
+}
+
+// BlockDomain blocks a domain and its subdomains: new links to them are refused
+// and existing ones are no longer followed. It returns all blocked domains.
+// Like Stats, it is only answered for trusted callers.
+//
+// Errors:
+//   - PermissionDenied if the caller is not trusted
+//   - InvalidArgument if the domain is not a host name or IP address
+//   - FailedPrecondition if the server runs without a blocklist
+//   - Internal if the blocklist cannot be saved
+func (h *Handler) BlockDomain(ctx context.Context, req *pb.BlockDomainRequest) (*pb.BlockedDomainsResponse, error) {
+	return h.changeBlocklist(ctx, req, audit.ActionBlockDomain, (*blocklist.List).Block)
+}
+
+// UnblockDomain unblocks a domain blocked by BlockDomain and returns all blocked domains.
+// Like Stats, it is only answered for trusted callers; errors are those of BlockDomain.
+func (h *Handler) UnblockDomain(ctx context.Context, req *pb.BlockDomainRequest) (*pb.BlockedDomainsResponse, error) {
+	return h.changeBlocklist(ctx, req, audit.ActionUnblockDomain, (*blocklist.List).Unblock)
+}
+
+// ListBlockedDomains returns all blocked domains in alphabetical order.
+// Like Stats, it is only answered for trusted callers.
+func (h *Handler) ListBlockedDomains(ctx context.Context, req *pb.ListBlockedDomainsRequest) (*pb.BlockedDomainsResponse, error) {
+	actor, err := h.trusted(ctx)
+	if err != nil {
+		return nil, err
+	}
+	h.auditTrusted(ctx, actor, audit.ActionListBlocked, "", nil)
+	return &pb.BlockedDomainsResponse{Domains: h.URLHandler.Blocklist.Domains()}, nil
+}
+
+// changeBlocklist applies change to the blocklist for the domain of req, records it as action
+// and returns all blocked domains.
+func (h *Handler) changeBlocklist(
+	ctx context.Context, req *pb.BlockDomainRequest, action string,
+	change func(l *blocklist.List, domain string) error,
+) (*pb.BlockedDomainsResponse, error) {
+	actor, err := h.trusted(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if h.URLHandler.Blocklist == nil {
+		return nil, status.Error(codes.FailedPrecondition, "blocklist is not configured")
+	}
+
+	if err := change(h.URLHandler.Blocklist, req.GetDomain()); err != nil {
+		if errors.Is(err, blocklist.ErrInvalidDomain) {
+			return nil, status.Error(codes.InvalidArgument, "invalid domain")
+		}
+		h.URLHandler.Logger.Error("Failed to change blocklist: " + err.Error())
+		return nil, status.Error(codes.Internal, "failed to save blocklist")
+	}
+
+	h.URLHandler.Logger.Info("Blocklist changed", "domain", req.GetDomain())
+	h.auditTrusted(ctx, actor, action, req.GetDomain(), nil)
+	return &pb.BlockedDomainsResponse{Domains: h.URLHandler.Blocklist.Domains()}, nil
+}
+
+// GetLinkOwner looks up who created a link, e.g. to follow up an abuse report.
+// Like Stats, it is only answered for trusted callers.
+//
+// Errors:
+//   - PermissionDenied if the caller is not trusted
+//   - InvalidArgument if short_url_id is missing
+//   - NotFound if the link does not exist, FailedPrecondition if it is gone
+//   - Internal if the storage fails
+func (h *Handler) GetLinkOwner(ctx context.Context, req *pb.GetLinkOwnerRequest) (*pb.GetLinkOwnerResponse, error) {
+	actor, err := h.trusted(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.GetShortUrlId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "short_url_id is required")
+	}
+
+	rec, err := h.URLHandler.Storage.GetRecord(ctx, req.GetShortUrlId())
+	if err != nil {
+		return nil, h.linkError("failed to get link", err)
+	}
+	h.auditTrusted(ctx, actor, audit.ActionLookupLink, rec.ID, nil)
+
+	resp := &pb.GetLinkOwnerResponse{
+		ShortUrlId:  &rec.ID,
+		UserId:      &rec.UserID,
+		OriginalUrl: &rec.URL,
+	}
+	if !rec.CreatedAt.IsZero() {
+		created := rec.CreatedAt.UTC().Format(time.RFC3339)
+		resp.CreatedAt = &created
+	}
+	return resp, nil
+}
@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/apetsko/shortugo/internal/audit"
+	"github.com/apetsko/shortugo/internal/auth"
+	"github.com/apetsko/shortugo/internal/models"
+	httph "github.com/apetsko/shortugo/internal/server/http/handlers"
+	pb "github.com/apetsko/shortugo/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// AdminHandler implements the Admin gRPC service for support staff.
+// Like Handler, it delegates to the shared HTTP URLHandler.
+type AdminHandler struct {
+	pb.UnimplementedAdminServer                   // Embeds the unimplemented server for forward compatibility.
+	URLHandler                  *httph.URLHandler // Reference to the shared HTTP handler logic.
+}
+
+// NewAdminHandler creates the Admin service over the HTTP handler h.
+func NewAdminHandler(h *httph.URLHandler) *AdminHandler {
+	return &AdminHandler{URLHandler: h}
+}
+
+// GetLink returns any link with its owner and state, deleted and disabled links included.
+//
+// Errors:
+//   - Unauthenticated if no API key is sent, PermissionDenied if it is not an admin key
+//   - InvalidArgument if short_url_id is missing
+//   - NotFound if the link does not exist
+//   - Internal if the storage fails
+func (a *AdminHandler) GetLink(ctx context.Context, req *pb.AdminLinkRequest) (*pb.AdminLink, error) {
+	actor, err := a.admin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.GetShortUrlId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "short_url_id is required")
+	}
+
+	rec, err := a.URLHandler.Storage.LookupRecord(ctx, req.GetShortUrlId())
+	if err != nil {
+		return nil, a.linkError("failed to get link", err)
+	}
+
+	a.audit(ctx, actor, audit.ActionLookupLink, req.GetShortUrlId(), nil)
+	return a.link(*rec), nil
+}
+
+// DisableLink stops the redirects of any link until EnableLink is called; the owner keeps it in their list.
+// The reason, if any, is recorded in the audit entry. Errors are those of GetLink.
+func (a *AdminHandler) DisableLink(ctx context.Context, req *pb.DisableLinkRequest) (*pb.AdminLink, error) {
+	var details map[string]string
+	if req.GetReason() != "" {
+		details = map[string]string{"reason": req.GetReason()}
+	}
+	return a.setDisabled(ctx, req.GetShortUrlId(), true, audit.ActionDisableLink, details)
+}
+
+// EnableLink lets a link disabled by DisableLink redirect again. Errors are those of GetLink.
+func (a *AdminHandler) EnableLink(ctx context.Context, req *pb.AdminLinkRequest) (*pb.AdminLink, error) {
+	return a.setDisabled(ctx, req.GetShortUrlId(), false, audit.ActionEnableLink, nil)
+}
+
+// setDisabled disables or enables the link id, records the action and returns the link.
+func (a *AdminHandler) setDisabled(ctx context.Context, id string, disabled bool, action string, details map[string]string) (*pb.AdminLink, error) {
+	actor, err := a.admin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if id == "" {
+		return nil, status.Error(codes.InvalidArgument, "short_url_id is required")
+	}
+
+	if err := a.URLHandler.Storage.SetLinkDisabled(ctx, id, disabled); err != nil {
+		return nil, a.linkError("failed to change link", err)
+	}
+	a.audit(ctx, actor, action, id, details)
+
+	rec, err := a.URLHandler.Storage.LookupRecord(ctx, id)
+	if err != nil {
+		return nil, a.linkError("failed to get link", err)
+	}
+	return a.link(*rec), nil
+}
+
+// ListUserLinks lists the links of any user that are not deleted, disabled ones included.
+// A user without links gets an empty list.
+//
+// Errors:
+//   - Unauthenticated if no API key is sent, PermissionDenied if it is not an admin key
+//   - InvalidArgument if user_id is missing
+//   - Internal if the storage fails
+func (a *AdminHandler) ListUserLinks(ctx context.Context, req *pb.AdminUserLinksRequest) (*pb.AdminLinksResponse, error) {
+	actor, err := a.admin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.GetUserId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	links, err := a.URLHandler.AdminUserLinks(ctx, req.GetUserId())
+	if err != nil {
+		a.URLHandler.Logger.Error("Failed to list user links", "error", err.Error())
+		return nil, status.Error(codes.Internal, "failed to list user links")
+	}
+	a.audit(ctx, actor, audit.ActionListUserLinks, req.GetUserId(), nil)
+
+	resp := &pb.AdminLinksResponse{Links: make([]*pb.AdminLink, 0, len(links))}
+	for _, l := range links {
+		resp.Links = append(resp.Links, toPBAdminLink(l))
+	}
+	return resp, nil
+}
+
+// admin checks the API key sent in the "authorization: Bearer <key>" metadata
+// and returns the actor audit entries name the caller by.
+func (a *AdminHandler) admin(ctx context.Context) (string, error) {
+	var token string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			token = auth.BearerToken(values[0])
+		}
+	}
+	if token == "" {
+		return "", status.Error(codes.Unauthenticated, "admin API key required")
+	}
+
+	actor, ok := a.URLHandler.Admins.Key(token)
+	if !ok {
+		a.URLHandler.Logger.Error("Forbidden: invalid admin API key")
+		return "", status.Error(codes.PermissionDenied, "invalid admin API key")
+	}
+	return actor, nil
+}
+
+// audit records an admin action of the caller of ctx on target.
+func (a *AdminHandler) audit(ctx context.Context, actor, action, target string, details map[string]string) {
+	recordAudit(ctx, a.URLHandler, actor, action, []string{target}, details)
+}
+
+// linkError maps a storage error of a link to a gRPC status like Handler does.
+func (a *AdminHandler) linkError(msg string, err error) error {
+	return NewHandler(a.URLHandler).linkError(msg, err)
+}
+
+// link converts a record to an Admin service link.
+func (a *AdminHandler) link(r models.URLRecord) *pb.AdminLink {
+	return toPBAdminLink(a.URLHandler.AdminLink(r))
+}
+
+// toPBAdminLink converts a link of the admin API to its protobuf message.
+func toPBAdminLink(l models.AdminLink) *pb.AdminLink {
+	link := &pb.AdminLink{
+		ShortUrlId:  &l.ID,
+		ShortUrl:    &l.ShortURL,
+		OriginalUrl: &l.OriginalURL,
+		UserId:      &l.UserID,
+		Title:       &l.Title,
+		Deleted:     &l.Deleted,
+		Disabled:    &l.Disabled,
+		Exhausted:   &l.Exhausted,
+	}
+	if !l.CreatedAt.IsZero() {
+		created := l.CreatedAt.UTC().Format(time.RFC3339)
+		link.CreatedAt = &created
+	}
+	return link
+}
+
+// peerAddr returns the host of the caller of ctx, or "" when it is unknown.
+func peerAddr(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/apetsko/shortugo/internal/audit"
+	"github.com/apetsko/shortugo/internal/auth"
+	"github.com/apetsko/shortugo/internal/logging"
+	"github.com/apetsko/shortugo/internal/mocks"
+	"github.com/apetsko/shortugo/internal/models"
+	httph "github.com/apetsko/shortugo/internal/server/http/handlers"
+	"github.com/apetsko/shortugo/internal/storages/inmem"
+	pb "github.com/apetsko/shortugo/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// auditRecorder keeps the recorded audit entries.
+type auditRecorder struct {
+	entries []audit.Entry
+}
+
+func (r *auditRecorder) Record(_ context.Context, e audit.Entry) error {
+	r.entries = append(r.entries, e)
+	return nil
+}
+
+// newAdminServiceClient serves the Admin service over storage with "admin-key" as admin API key.
+func newAdminServiceClient(t *testing.T, storage httph.Storage) (pb.AdminClient, *auditRecorder) {
+	t.Helper()
+
+	logger, _ := logging.New(zapcore.DebugLevel)
+	recorder := new(auditRecorder)
+	h := &httph.URLHandler{
+		Storage:  storage,
+		Logger:   logger,
+		BaseURL:  "http://localhost:8080",
+		Admins:   auth.NewAdmins(nil, []string{"admin-key"}),
+		AuditLog: recorder,
+	}
+
+	conn, cleanup, err := startServer(func(s *grpc.Server) {
+		pb.RegisterAdminServer(s, NewAdminHandler(h))
+	})
+	require.NoError(t, err)
+	t.Cleanup(cleanup)
+	return pb.NewAdminClient(conn), recorder
+}
+
+// withKey returns ctx sending key as the admin API key.
+func withKey(ctx context.Context, key string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+key)
+}
+
+func TestAdminService_Auth(t *testing.T) {
+	client, recorder := newAdminServiceClient(t, new(mocks.Storage))
+	id, userID := "abc123", "user1"
+
+	calls := map[string]func(ctx context.Context) error{
+		"GetLink": func(ctx context.Context) error {
+			_, err := client.GetLink(ctx, &pb.AdminLinkRequest{ShortUrlId: &id})
+			return err
+		},
+		"DisableLink": func(ctx context.Context) error {
+			_, err := client.DisableLink(ctx, &pb.DisableLinkRequest{ShortUrlId: &id})
+			return err
+		},
+		"EnableLink": func(ctx context.Context) error {
+			_, err := client.EnableLink(ctx, &pb.AdminLinkRequest{ShortUrlId: &id})
+			return err
+		},
+		"ListUserLinks": func(ctx context.Context) error {
+			_, err := client.ListUserLinks(ctx, &pb.AdminUserLinksRequest{UserId: &userID})
+			return err
+		},
+	}
+
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, codes.Unauthenticated, status.Code(call(context.Background())))
+			assert.Equal(t, codes.PermissionDenied, status.Code(call(withKey(context.Background(), "guess"))))
+		})
+	}
+	assert.Empty(t, recorder.entries)
+}
+
+func TestAdminService_Links(t *testing.T) {
+	created := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	storage := inmem.New()
+	require.NoError(t, storage.PutBatch(context.Background(), []models.URLRecord{
+		{ID: "abc123", URL: "https://phishing.example", UserID: "user1", CreatedAt: created},
+		{ID: "def456", URL: "https://example.com", UserID: "user1", CreatedAt: created},
+	}))
+	client, recorder := newAdminServiceClient(t, storage)
+	ctx := withKey(context.Background(), "admin-key")
+	id, userID, reason := "abc123", "user1", "phishing"
+
+	link, err := client.DisableLink(ctx, &pb.DisableLinkRequest{ShortUrlId: &id, Reason: &reason})
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(&pb.AdminLink{
+		ShortUrlId:  proto.String("abc123"),
+		ShortUrl:    proto.String("http://localhost:8080/abc123"),
+		OriginalUrl: proto.String("https://phishing.example"),
+		UserId:      proto.String("user1"),
+		CreatedAt:   proto.String("2026-10-01T12:00:00Z"),
+		Title:       proto.String(""),
+		Deleted:     proto.Bool(false),
+		Disabled:    proto.Bool(true),
+		Exhausted:   proto.Bool(false),
+	}, link), "got %v", link)
+
+	_, err = storage.Get(context.Background(), id)
+	require.Error(t, err)
+
+	link, err = client.GetLink(ctx, &pb.AdminLinkRequest{ShortUrlId: &id})
+	require.NoError(t, err)
+	assert.True(t, link.GetDisabled())
+
+	resp, err := client.ListUserLinks(ctx, &pb.AdminUserLinksRequest{UserId: &userID})
+	require.NoError(t, err)
+	assert.Len(t, resp.GetLinks(), 2)
+
+	link, err = client.EnableLink(ctx, &pb.AdminLinkRequest{ShortUrlId: &id})
+	require.NoError(t, err)
+	assert.False(t, link.GetDisabled())
+
+	unknown := "unknown"
+	resp, err = client.ListUserLinks(ctx, &pb.AdminUserLinksRequest{UserId: &unknown})
+	require.NoError(t, err)
+	assert.Empty(t, resp.GetLinks())
+
+	actions := make([]string, 0, len(recorder.entries))
+	for _, e := range recorder.entries {
+		assert.Regexp(t, `^key:[0-9a-f]{8}$`, e.Actor)
+		actions = append(actions, e.Action)
+	}
+	assert.Equal(t, []string{
+		audit.ActionDisableLink, audit.ActionLookupLink, audit.ActionListUserLinks, audit.ActionEnableLink, audit.ActionListUserLinks,
+	}, actions)
+	assert.Equal(t, map[string]string{"reason": "phishing"}, recorder.entries[0].Details)
+}
+
+func TestAdminService_Errors(t *testing.T) {
+	ctx := withKey(context.Background(), "admin-key")
+	empty, id := "", "abc123"
+
+	t.Run("missing arguments", func(t *testing.T) {
+		client, _ := newAdminServiceClient(t, new(mocks.Storage))
+		_, err := client.GetLink(ctx, &pb.AdminLinkRequest{ShortUrlId: &empty})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		_, err = client.DisableLink(ctx, &pb.DisableLinkRequest{})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		_, err = client.ListUserLinks(ctx, &pb.AdminUserLinksRequest{})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("unknown link", func(t *testing.T) {
+		client, recorder := newAdminServiceClient(t, inmem.New())
+		_, err := client.GetLink(ctx, &pb.AdminLinkRequest{ShortUrlId: &id})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+		_, err = client.EnableLink(ctx, &pb.AdminLinkRequest{ShortUrlId: &id})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+		assert.Empty(t, recorder.entries)
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		mockStorage := new(mocks.Storage)
+		mockStorage.On("SetLinkDisabled", mock.Anything, id, true).Return(errors.New("database error"))
+		client, _ := newAdminServiceClient(t, mockStorage)
+		_, err := client.DisableLink(ctx, &pb.DisableLinkRequest{ShortUrlId: &id})
+		assert.Equal(t, codes.Internal, status.Code(err))
+		mockStorage.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,292 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/apetsko/shortugo/internal/audit"
+	"github.com/apetsko/shortugo/internal/auth"
+	"github.com/apetsko/shortugo/internal/blocklist"
+	"github.com/apetsko/shortugo/internal/logging"
+	"github.com/apetsko/shortugo/internal/mocks"
+	"github.com/apetsko/shortugo/internal/models"
+	httph "github.com/apetsko/shortugo/internal/server/http/handlers"
+	"github.com/apetsko/shortugo/internal/storages/inmem"
+	pb "github.com/apetsko/shortugo/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// newAdminClient serves storage with 192.168.0.0/24 as the trusted subnet and the blocklist list
+// to a client connecting from 192.168.0.42.
+func newAdminClient(t *testing.T, storage httph.Storage, list *blocklist.List) pb.URLShortenerClient {
+	t.Helper()
+	return newAdminClientFrom(t, "192.168.0.42", storage, list)
+}
+
+// newAdminClientFrom is newAdminClient for a client connecting from ip; the server also accepts the admin API key "admin-key".
+func newAdminClientFrom(t *testing.T, ip string, storage httph.Storage, list *blocklist.List) pb.URLShortenerClient {
+	t.Helper()
+
+	_, trustedNet, _ := net.ParseCIDR("192.168.0.0/24")
+	logger, _ := logging.New(zapcore.DebugLevel)
+	h := &httph.URLHandler{
+		Storage:   storage,
+		Logger:    logger,
+		BaseURL:   "http://localhost:8080",
+		Blocklist: list,
+		Admins:    auth.NewAdmins(nil, []string{"admin-key"}),
+	}
+	h.TrustedSubnet.Store(trustedNet)
+
+	conn, cleanup, err := startGRPCServer(NewHandler(h), fromIP(ip))
+	require.NoError(t, err)
+	t.Cleanup(cleanup)
+	return pb.NewURLShortenerClient(conn)
+}
+
+func TestAdmin_TrustedSubnet(t *testing.T) {
+	client := newAdminClientFrom(t, "10.0.0.1", new(mocks.Storage), blocklist.New())
+	domain := "evil.com"
+	id := "abc123"
+
+	calls := map[string]func(ctx context.Context) error{
+		"PurgeDeleted": func(ctx context.Context) error {
+			_, err := client.PurgeDeleted(ctx, &pb.PurgeDeletedRequest{})
+			return err
+		},
+		"BlockDomain": func(ctx context.Context) error {
+			_, err := client.BlockDomain(ctx, &pb.BlockDomainRequest{Domain: &domain})
+			return err
+		},
+		"UnblockDomain": func(ctx context.Context) error {
+			_, err := client.UnblockDomain(ctx, &pb.BlockDomainRequest{Domain: &domain})
+			return err
+		},
+		"ListBlockedDomains": func(ctx context.Context) error {
+			_, err := client.ListBlockedDomains(ctx, &pb.ListBlockedDomainsRequest{})
+			return err
+		},
+		"GetLinkOwner": func(ctx context.Context) error {
+			_, err := client.GetLinkOwner(ctx, &pb.GetLinkOwnerRequest{ShortUrlId: &id})
+			return err
+		},
+	}
+
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			assert.Equal(t, codes.PermissionDenied, status.Code(call(ctx)))
+			assert.Equal(t, codes.PermissionDenied, status.Code(call(withKey(ctx, "wrong-key"))))
+		})
+	}
+}
+
+func TestAdmin_TrustedCaller(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("admin API key outside the trusted subnet", func(t *testing.T) {
+		client := newAdminClientFrom(t, "10.0.0.1", inmem.New(), blocklist.New())
+		resp, err := client.ListBlockedDomains(withKey(ctx, "admin-key"), &pb.ListBlockedDomainsRequest{})
+		require.NoError(t, err)
+		assert.Empty(t, resp.GetDomains())
+	})
+
+	t.Run("wrong admin API key inside the trusted subnet", func(t *testing.T) {
+		client := newAdminClient(t, inmem.New(), blocklist.New())
+		_, err := client.ListBlockedDomains(withKey(ctx, "wrong-key"), &pb.ListBlockedDomainsRequest{})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("unknown client address", func(t *testing.T) {
+		logger, _ := logging.New(zapcore.DebugLevel)
+		h := &httph.URLHandler{Storage: inmem.New(), Logger: logger, Blocklist: blocklist.New()}
+		_, trustedNet, _ := net.ParseCIDR("0.0.0.0/0")
+		h.TrustedSubnet.Store(trustedNet)
+		conn, cleanup, err := startGRPCServer(NewHandler(h))
+		require.NoError(t, err)
+		t.Cleanup(cleanup)
+
+		_, err = pb.NewURLShortenerClient(conn).ListBlockedDomains(ctx, &pb.ListBlockedDomainsRequest{})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+}
+
+func TestPurgeDeleted_GRPC(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		storage := inmem.New()
+		require.NoError(t, storage.PutBatch(ctx, []models.URLRecord{
+			{ID: "a", URL: "https://a.com", UserID: "user1"},
+			{ID: "b", URL: "https://b.com", UserID: "user1"},
+		}))
+		require.NoError(t, storage.DeleteUserURLs(ctx, []string{"b"}, "user1"))
+
+		resp, err := newAdminClient(t, storage, nil).PurgeDeleted(ctx, &pb.PurgeDeletedRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), resp.GetPurged())
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		mockStorage := new(mocks.Storage)
+		mockStorage.On("PurgeDeleted", mock.Anything).Return(int64(0), errors.New("database error"))
+
+		_, err := newAdminClient(t, mockStorage, nil).PurgeDeleted(ctx, &pb.PurgeDeletedRequest{})
+		assert.Equal(t, codes.Internal, status.Code(err))
+		mockStorage.AssertExpectations(t)
+	})
+}
+
+func TestBlockDomain_GRPC(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "blocklist.txt")
+	list, err := blocklist.Load(path)
+	require.NoError(t, err)
+
+	storage := inmem.New()
+	client := newAdminClient(t, storage, list)
+
+	block := func(domain string) (*pb.BlockedDomainsResponse, error) {
+		return client.BlockDomain(ctx, &pb.BlockDomainRequest{Domain: &domain})
+	}
+
+	resp, err := block("Evil.com")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"evil.com"}, resp.GetDomains())
+
+	resp, err = block("malware.example")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"evil.com", "malware.example"}, resp.GetDomains())
+
+	_, err = block("https://evil.com/")
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// New links to blocked domains are refused, existing ones are no longer followed
+	userID, target := "user1", "https://login.evil.com/account"
+	_, err = client.Shorten(ctx, &pb.ShortenRequest{UserId: &userID, OriginalUrl: &target})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	require.NoError(t, storage.Put(ctx, models.URLRecord{ID: "old", URL: "https://evil.com", UserID: userID}))
+	oldID := "old"
+	_, err = client.Expand(ctx, &pb.ExpandRequest{ShortUrlId: &oldID})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// The blocklist survives a restart
+	reloaded, err := blocklist.Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"evil.com", "malware.example"}, reloaded.Domains())
+
+	domain := "evil.com"
+	resp, err = client.UnblockDomain(ctx, &pb.BlockDomainRequest{Domain: &domain})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"malware.example"}, resp.GetDomains())
+
+	resp, err = client.ListBlockedDomains(ctx, &pb.ListBlockedDomainsRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"malware.example"}, resp.GetDomains())
+
+	_, err = client.Expand(ctx, &pb.ExpandRequest{ShortUrlId: &oldID})
+	require.NoError(t, err)
+}
+
+func TestBlockDomain_NoBlocklist(t *testing.T) {
+	domain := "evil.com"
+	_, err := newAdminClient(t, new(mocks.Storage), nil).BlockDomain(context.Background(), &pb.BlockDomainRequest{Domain: &domain})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestGetLinkOwner_GRPC(t *testing.T) {
+	ctx := context.Background()
+	created := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	storage := inmem.New()
+	require.NoError(t, storage.PutBatch(ctx, []models.URLRecord{
+		{ID: "abc123", URL: "https://example.com", UserID: "user1", CreatedAt: created},
+		{ID: "def456", URL: "https://example.org", UserID: "user1", CreatedAt: created},
+	}))
+	require.NoError(t, storage.DeleteUserURLs(ctx, []string{"def456"}, "user1"))
+	client := newAdminClient(t, storage, nil)
+
+	tests := []struct {
+		name         string
+		id           string
+		expected     *pb.GetLinkOwnerResponse
+		expectedCode codes.Code
+	}{
+		{
+			name: "success",
+			id:   "abc123",
+			expected: &pb.GetLinkOwnerResponse{
+				ShortUrlId:  proto.String("abc123"),
+				UserId:      proto.String("user1"),
+				OriginalUrl: proto.String("https://example.com"),
+				CreatedAt:   proto.String("2026-10-01T12:00:00Z"),
+			},
+			expectedCode: codes.OK,
+		},
+		{name: "missing ID", id: "", expectedCode: codes.InvalidArgument},
+		{name: "unknown link", id: "unknown", expectedCode: codes.NotFound},
+		{name: "deleted link", id: "def456", expectedCode: codes.FailedPrecondition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := client.GetLinkOwner(ctx, &pb.GetLinkOwnerRequest{ShortUrlId: &tt.id})
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+			if tt.expectedCode == codes.OK {
+				assert.True(t, proto.Equal(tt.expected, resp), "got %v", resp)
+			}
+		})
+	}
+}
+
+func TestAdmin_TrustedSubnetAudit(t *testing.T) {
+	_, trustedNet, _ := net.ParseCIDR("192.168.0.0/24")
+	logger, _ := logging.New(zapcore.DebugLevel)
+	recorder := new(auditRecorder)
+	h := &httph.URLHandler{
+		Storage:   inmem.New(),
+		Logger:    logger,
+		Blocklist: blocklist.New(),
+		AuditLog:  recorder,
+	}
+	h.TrustedSubnet.Store(trustedNet)
+	serve := func(ip string) pb.URLShortenerClient {
+		conn, cleanup, err := startGRPCServer(NewHandler(h), fromIP(ip))
+		require.NoError(t, err)
+		t.Cleanup(cleanup)
+		return pb.NewURLShortenerClient(conn)
+	}
+	client := serve("192.168.0.42")
+
+	ctx := context.Background()
+	domain := "evil.com"
+	var err error
+	_, err = client.BlockDomain(ctx, &pb.BlockDomainRequest{Domain: &domain})
+	require.NoError(t, err)
+	_, err = client.PurgeDeleted(ctx, &pb.PurgeDeletedRequest{})
+	require.NoError(t, err)
+
+	// Refused calls are not audited
+	_, err = serve("10.0.0.1").UnblockDomain(ctx, &pb.BlockDomainRequest{Domain: &domain})
+	require.Error(t, err)
+
+	require.Len(t, recorder.entries, 2)
+	assert.Equal(t, "ip:192.168.0.42", recorder.entries[0].Actor)
+	assert.Equal(t, "192.168.0.42", recorder.entries[0].Remote)
+	assert.Equal(t, audit.ActionBlockDomain, recorder.entries[0].Action)
+	assert.Equal(t, []string{"evil.com"}, recorder.entries[0].Targets)
+	assert.Equal(t, audit.ProtocolGRPC, recorder.entries[0].Protocol)
+	assert.Equal(t, audit.ActionPurgeDeleted, recorder.entries[1].Action)
+	assert.Equal(t, map[string]string{"purged": "0"}, recorder.entries[1].Details)
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"context"
+	"maps"
+
+	"github.com/apetsko/shortugo/internal/audit"
+	"github.com/apetsko/shortugo/internal/certs"
+	httph "github.com/apetsko/shortugo/internal/server/http/handlers"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// audit records an action of the user userID on targets.
+func (h *Handler) audit(ctx context.Context, userID, action string, targets []string, details map[string]string) {
+	recordAudit(ctx, h.URLHandler, userID, action, targets, details)
+}
+
+// auditUpdate records the change of field of the link id by the user userID.
+func (h *Handler) auditUpdate(ctx context.Context, userID, id, field string) {
+	h.audit(ctx, userID, audit.ActionUpdateLink, []string{id}, map[string]string{"field": field})
+}
+
+// auditTrusted records an administration call of a trusted caller, named by the actor trusted returned.
+func (h *Handler) auditTrusted(ctx context.Context, actor, action, target string, details map[string]string) {
+	var targets []string
+	if target != "" {
+		targets = []string{target}
+	}
+	recordAudit(ctx, h.URLHandler, actor, action, targets, details)
+}
+
+// recordAudit records an action of actor taken through a call with ctx.
+// Calls of the in-process HTTP gateway have no peer; they are recorded as HTTP
+// with the client address the gateway passes. The identity of a client service
+// authenticated by its certificate is kept in the "client" detail.
+func recordAudit(ctx context.Context, h *httph.URLHandler, actor, action string, targets []string, details map[string]string) {
+	protocol, remote := audit.ProtocolGRPC, peerAddr(ctx)
+	if _, ok := peer.FromContext(ctx); !ok {
+		protocol, remote = audit.ProtocolHTTP, gatewayClient(ctx)
+	}
+	if client := certs.IdentityFromContext(ctx); client != "" {
+		details = maps.Clone(details)
+		if details == nil {
+			details = make(map[string]string, 1)
+		}
+		details["client"] = client
+	}
+
+	h.RecordAudit(ctx, audit.Entry{
+		Actor:    actor,
+		Remote:   remote,
+		Protocol: protocol,
+		Action:   action,
+		Targets:  targets,
+		Details:  details,
+	})
+}
+
+// ClientIPKey is the metadata key the in-process HTTP gateway passes the IP address of its client under,
+// since its calls have no peer. It is set by the gateway alone: the same key sent by a client is dropped.
+const ClientIPKey = "x-shortugo-client-ip"
+
+// gatewayClient returns the client IP passed by the gateway, or "" when it is missing.
+func gatewayClient(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(ClientIPKey); len(values) == 1 {
+		return values[0]
+	}
+	return ""
+}
+
+// remoteAddr returns the host of the client of ctx: the peer of a gRPC call, or the client IP passed
+// by the gateway for REST calls. It is "" when neither is known.
+func remoteAddr(ctx context.Context) string {
+	if _, ok := peer.FromContext(ctx); ok {
+		return peerAddr(ctx)
+	}
+	return gatewayClient(ctx)
+}
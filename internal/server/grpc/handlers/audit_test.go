@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apetsko/shortugo/internal/audit"
+	"github.com/apetsko/shortugo/internal/logging"
+	"github.com/apetsko/shortugo/internal/models"
+	httph "github.com/apetsko/shortugo/internal/server/http/handlers"
+	"github.com/apetsko/shortugo/internal/storages/inmem"
+	pb "github.com/apetsko/shortugo/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestAudit_GRPC(t *testing.T) {
+	ctx := context.Background()
+	logger, _ := logging.New(zapcore.DebugLevel)
+	recorder := new(auditRecorder)
+	h := NewHandler(&httph.URLHandler{
+		Storage:  inmem.New(),
+		Logger:   logger,
+		BaseURL:  "http://localhost:8080",
+		ToDelete: make(chan models.BatchDeleteRequest, 1),
+		AuditLog: recorder,
+	})
+	conn, cleanup, err := startGRPCServer(h)
+	require.NoError(t, err)
+	defer cleanup()
+	client := pb.NewURLShortenerClient(conn)
+
+	resp, err := client.ShortenJSON(ctx, &pb.ShortenRequest{UserId: proto.String("user1"), OriginalUrl: proto.String("https://example.com")})
+	require.NoError(t, err)
+	_, err = client.SetLinkFolder(ctx, &pb.SetLinkFolderRequest{UserId: proto.String("user1"), ShortUrlId: proto.String("unknown"), Folder: proto.String("work")})
+	require.Error(t, err)
+
+	require.Len(t, recorder.entries, 1)
+	e := recorder.entries[0]
+	assert.Equal(t, "user1", e.Actor)
+	assert.Equal(t, audit.ProtocolGRPC, e.Protocol)
+	assert.Equal(t, audit.ActionCreateLinks, e.Action)
+	assert.Equal(t, []string{resp.GetShortUrl()[len("http://localhost:8080/"):]}, e.Targets)
+
+	// Calls of the HTTP gateway have no peer and pass the client address
+	gwCtx := metadata.NewIncomingContext(ctx, metadata.Pairs(ClientIPKey, "203.0.113.7"))
+	_, err = h.DeleteUserURLs(gwCtx, &pb.DeleteUserURLsRequest{UserId: proto.String("user1"), ShortUrlIds: []string{"abc123"}})
+	require.NoError(t, err)
+
+	require.Len(t, recorder.entries, 2)
+	assert.Equal(t, audit.Entry{
+		Actor: "user1", Remote: "203.0.113.7", Protocol: audit.ProtocolHTTP, Action: audit.ActionDeleteLinks, Targets: []string{"abc123"},
+	}, recorder.entries[1])
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 
+	"github.com/apetsko/shortugo/internal/audit"
 	"github.com/apetsko/shortugo/internal/models"
 	pb "github.com/apetsko/shortugo/proto"
 )
@@ -25,6 +26,7 @@ func (h *Handler) DeleteUserURLs(ctx context.Context, req *pb.DeleteUserURLsRequ
 			UserID: *req.UserId,
 		}
 	}()
+	h.audit(ctx, req.GetUserId(), audit.ActionDeleteLinks, req.ShortUrlIds, nil)
 	success := true
 	return &pb.DeleteUserURLsResponse{Success: &success}, nil
 }
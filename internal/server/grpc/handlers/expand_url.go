@@ -4,16 +4,22 @@ import (
 	"context"
 	"errors"
 
+	"github.com/apetsko/shortugo/internal/auth"
+	httph "github.com/apetsko/shortugo/internal/server/http/handlers"
 	"github.com/apetsko/shortugo/internal/storages/shared"
 	pb "github.com/apetsko/shortugo/proto"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
-// Expand resolves a short URL ID to its original URL.
+// Expand resolves a short URL ID to its original URL, with the UTM tags of the link added like a redirect adds them.
+// Password-protected links require the password; failed attempts are rate-limited per client and link,
+// the client of a REST call being the one the gateway passes.
+// Every call uses up one click of a link limited by max_clicks.
+// Links leading to a blocked domain are refused with PermissionDenied.
 // Returns gRPC status codes based on the error encountered.
 func (h *Handler) Expand(ctx context.Context, req *pb.ExpandRequest) (*pb.ExpandResponse, error) {
-	originalURL, err := h.URLHandler.Storage.Get(ctx, req.GetShortUrlId())
+	rec, err := h.URLHandler.Storage.GetRecord(ctx, req.GetShortUrlId())
 	if err != nil {
 		switch {
 		case errors.Is(err, shared.ErrGone):
@@ -30,5 +36,45 @@ func (h *Handler) Expand(ctx context.Context, req *pb.ExpandRequest) (*pb.Expand
 		}
 	}
 
-	return &pb.ExpandResponse{OriginalUrl: &originalURL}, nil
+	if h.URLHandler.Blocked(rec.URL, rec.LinkOptions) {
+		h.URLHandler.Logger.Info("Link leads to a blocked domain", "id", rec.ID)
+		return nil, status.Error(codes.PermissionDenied, "blocked domain")
+	}
+
+	if rec.Protected() {
+		remote := remoteAddr(ctx)
+		key := httph.PasswordAttemptKey(remote, rec.ID)
+		if ok, _ := h.URLHandler.PasswordAttempts.Allow(key); !ok {
+			h.URLHandler.Logger.Info("Too many password attempts", "id", rec.ID, "remote", remote)
+			return nil, status.Error(codes.ResourceExhausted, "too many password attempts")
+		}
+		if !auth.CheckPassword(rec.PasswordHash, req.GetPassword()) {
+			h.URLHandler.PasswordAttempts.Fail(key)
+			return nil, status.Error(codes.PermissionDenied, "wrong password")
+		}
+		h.URLHandler.PasswordAttempts.Reset(key)
+	}
+
+	// The destination is reported with the UTM tags a redirect adds
+	target, err := httph.TaggedDestination(rec.URL, rec.LinkOptions)
+	if err != nil {
+		h.URLHandler.Logger.Error("failed to build destination: " + err.Error())
+		return nil, status.Error(codes.Internal, "Internal server error")
+	}
+
+	if rec.MaxClicks > 0 {
+		if err := h.URLHandler.Storage.ConsumeClick(ctx, rec.ID); err != nil {
+			switch {
+			case errors.Is(err, shared.ErrGone):
+				return nil, status.Error(codes.FailedPrecondition, "URL is gone")
+			case errors.Is(err, shared.ErrNotFound):
+				return nil, status.Error(codes.NotFound, "URL not found")
+			default:
+				h.URLHandler.Logger.Error("failed to consume click: " + err.Error())
+				return nil, status.Error(codes.Internal, "Internal server error")
+			}
+		}
+	}
+
+	return &pb.ExpandResponse{OriginalUrl: &target}, nil
 }
@@ -3,11 +3,18 @@ package handlers
 import (
 	"context"
 	"errors"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/apetsko/shortugo/internal/auth"
 	"github.com/apetsko/shortugo/internal/logging"
 	"github.com/apetsko/shortugo/internal/mocks"
+	"github.com/apetsko/shortugo/internal/models"
+	"github.com/apetsko/shortugo/internal/ratelimit"
 	httph "github.com/apetsko/shortugo/internal/server/http/handlers"
+	"github.com/apetsko/shortugo/internal/storages/inmem"
 	"github.com/apetsko/shortugo/internal/storages/shared"
 	pb "github.com/apetsko/shortugo/proto"
 	"github.com/stretchr/testify/assert"
@@ -91,7 +98,11 @@ func TestExpand_GRPC(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mockStorage.On("Get", mock.Anything, tt.id).Return(tt.mockReturn, tt.mockError)
+			var rec *models.URLRecord
+			if tt.mockError == nil {
+				rec = &models.URLRecord{ID: tt.id, URL: tt.mockReturn}
+			}
+			mockStorage.On("GetRecord", mock.Anything, tt.id).Return(rec, tt.mockError)
 
 			resp, err := client.Expand(ctx, &pb.ExpandRequest{ShortUrlId: &tt.id})
 
@@ -105,3 +116,104 @@ func TestExpand_GRPC(t *testing.T) {
 		})
 	}
 }
+
+func TestExpand_GRPC_Password(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+	hash, err := auth.HashPassword("s3cret")
+	require.NoError(t, err)
+
+	mockStorage := new(mocks.Storage)
+	mockStorage.On("GetRecord", mock.Anything, "abc123").Return(&models.URLRecord{
+		ID:          "abc123",
+		URL:         "http://example.com",
+		LinkOptions: models.LinkOptions{PasswordHash: hash},
+	}, nil)
+
+	conn, cleanup, err := startGRPCServer(NewHandler(&httph.URLHandler{
+		Storage:          mockStorage,
+		Logger:           logger,
+		PasswordAttempts: ratelimit.New(2, time.Minute),
+	}))
+	require.NoError(t, err)
+	defer cleanup()
+
+	client := pb.NewURLShortenerClient(conn)
+	id := "abc123"
+	expand := func(password string) (*pb.ExpandResponse, codes.Code) {
+		resp, err := client.Expand(context.Background(), &pb.ExpandRequest{ShortUrlId: &id, Password: &password})
+		return resp, status.Code(err)
+	}
+
+	_, code := expand("")
+	assert.Equal(t, codes.PermissionDenied, code)
+
+	resp, code := expand("s3cret")
+	require.Equal(t, codes.OK, code)
+	assert.Equal(t, "http://example.com", resp.GetOriginalUrl())
+
+	// The successful attempt reset the counter, so two more failures are needed to block the client.
+	_, code = expand("wrong")
+	assert.Equal(t, codes.PermissionDenied, code)
+	_, code = expand("wrong")
+	assert.Equal(t, codes.PermissionDenied, code)
+	_, code = expand("s3cret")
+	assert.Equal(t, codes.ResourceExhausted, code)
+}
+
+func TestExpand_GRPC_MaxClicks(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+
+	mockStorage := new(mocks.Storage)
+	mockStorage.On("GetRecord", mock.Anything, "invite").Return(&models.URLRecord{
+		ID:          "invite",
+		URL:         "http://example.com",
+		ClicksLeft:  1,
+		LinkOptions: models.LinkOptions{MaxClicks: 1},
+	}, nil)
+	// The first call takes the last click, the second one lost the race for it
+	mockStorage.On("ConsumeClick", mock.Anything, "invite").Return(nil).Once()
+	mockStorage.On("ConsumeClick", mock.Anything, "invite").Return(shared.ErrGone).Once()
+
+	conn, cleanup, err := startGRPCServer(NewHandler(&httph.URLHandler{Storage: mockStorage, Logger: logger}))
+	require.NoError(t, err)
+	defer cleanup()
+
+	client := pb.NewURLShortenerClient(conn)
+	id := "invite"
+
+	resp, err := client.Expand(context.Background(), &pb.ExpandRequest{ShortUrlId: &id})
+	require.NoError(t, err)
+	assert.Equal(t, "http://example.com", resp.GetOriginalUrl())
+
+	_, err = client.Expand(context.Background(), &pb.ExpandRequest{ShortUrlId: &id})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	mockStorage.AssertExpectations(t)
+}
+
+func TestExpand_GRPC_UTM(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+	ctx := context.Background()
+
+	storage := inmem.New()
+	require.NoError(t, storage.Put(ctx, models.URLRecord{
+		ID:          "tagged",
+		URL:         "https://example.com/sale?ref=home&utm_source=old#top",
+		UserID:      "user123",
+		LinkOptions: models.LinkOptions{UTM: models.UTM{Source: "newsletter", Campaign: "spring"}},
+	}))
+	h := &httph.URLHandler{Storage: storage, Logger: logger}
+
+	conn, cleanup, err := startGRPCServer(NewHandler(h))
+	require.NoError(t, err)
+	defer cleanup()
+
+	id := "tagged"
+	resp, err := pb.NewURLShortenerClient(conn).Expand(ctx, &pb.ExpandRequest{ShortUrlId: &id})
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/sale?ref=home&utm_campaign=spring&utm_source=newsletter#top", resp.GetOriginalUrl())
+
+	// A redirect sends visitors to the same destination
+	w := httptest.NewRecorder()
+	h.ExpandURL(w, httptest.NewRequest(http.MethodGet, "/tagged", nil))
+	assert.Equal(t, resp.GetOriginalUrl(), w.Header().Get("Location"))
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/apetsko/shortugo/internal/qrcode"
+	"github.com/apetsko/shortugo/internal/storages/shared"
+	pb "github.com/apetsko/shortugo/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GetQRCode renders a QR code image encoding the short link of the given ID.
+// Missing and deleted links are reported with the same status codes as Expand.
+//
+// Request:
+//   - short_url_id: string
+//   - format: png (default) or svg
+//   - size: image width and height in pixels
+//   - level: error-correction level L, M, Q or H
+//
+// Response:
+//   - image: rendered image bytes
+//   - content_type: MIME type of the image
+func (h *Handler) GetQRCode(ctx context.Context, req *pb.GetQRCodeRequest) (*pb.GetQRCodeResponse, error) {
+	if req.GetShortUrlId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "short_url_id is required")
+	}
+
+	opts, err := qrcode.ParseOptions(req.GetFormat(), req.GetLevel(), int(req.GetSize()))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	img, err := h.URLHandler.QRCodeImage(ctx, req.GetShortUrlId(), opts)
+	if err != nil {
+		switch {
+		case errors.Is(err, shared.ErrGone):
+			h.URLHandler.Logger.Error("URL is gone: " + req.GetShortUrlId())
+			return nil, status.Error(codes.FailedPrecondition, "URL is gone")
+
+		case errors.Is(err, shared.ErrNotFound):
+			h.URLHandler.Logger.Error("URL not found: " + req.GetShortUrlId())
+			return nil, status.Error(codes.NotFound, "URL not found")
+
+		default:
+			h.URLHandler.Logger.Error("QR code error: " + err.Error())
+			return nil, status.Error(codes.Internal, "Internal server error")
+		}
+	}
+
+	contentType := opts.ContentType()
+	return &pb.GetQRCodeResponse{
+		Image:       img,
+		ContentType: &contentType,
+	}, nil
+}
@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/apetsko/shortugo/internal/logging"
+	"github.com/apetsko/shortugo/internal/mocks"
+	"github.com/apetsko/shortugo/internal/models"
+	"github.com/apetsko/shortugo/internal/qrcode"
+	httph "github.com/apetsko/shortugo/internal/server/http/handlers"
+	"github.com/apetsko/shortugo/internal/storages/infile"
+	"github.com/apetsko/shortugo/internal/storages/shared"
+	pb "github.com/apetsko/shortugo/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetQRCode_GRPC(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+
+	tests := []struct {
+		mockStorageSetup    func(mockStorage *mocks.Storage)
+		name                string
+		id                  string
+		format              string
+		level               string
+		expectedContentType string
+		size                int32
+		expectedStatus      codes.Code
+	}{
+		{
+			name: "png",
+			id:   "abc123",
+			mockStorageSetup: func(mockStorage *mocks.Storage) {
+				mockStorage.On("Get", mock.Anything, "abc123").Return("http://example.com", nil)
+			},
+			expectedStatus:      codes.OK,
+			expectedContentType: "image/png",
+		},
+		{
+			name:   "svg",
+			id:     "abc123",
+			format: "svg",
+			level:  "Q",
+			size:   300,
+			mockStorageSetup: func(mockStorage *mocks.Storage) {
+				mockStorage.On("Get", mock.Anything, "abc123").Return("http://example.com", nil)
+			},
+			expectedStatus:      codes.OK,
+			expectedContentType: "image/svg+xml",
+		},
+		{
+			name:             "invalid level",
+			id:               "abc123",
+			level:            "Z",
+			mockStorageSetup: func(mockStorage *mocks.Storage) {},
+			expectedStatus:   codes.InvalidArgument,
+		},
+		{
+			name:             "missing id",
+			mockStorageSetup: func(mockStorage *mocks.Storage) {},
+			expectedStatus:   codes.InvalidArgument,
+		},
+		{
+			name: "url gone",
+			id:   "expired123",
+			mockStorageSetup: func(mockStorage *mocks.Storage) {
+				mockStorage.On("Get", mock.Anything, "expired123").Return("", shared.ErrGone)
+			},
+			expectedStatus: codes.FailedPrecondition,
+		},
+		{
+			name: "url not found",
+			id:   "missing123",
+			mockStorageSetup: func(mockStorage *mocks.Storage) {
+				mockStorage.On("Get", mock.Anything, "missing123").Return("", shared.ErrNotFound)
+			},
+			expectedStatus: codes.NotFound,
+		},
+		{
+			name: "internal error",
+			id:   "error123",
+			mockStorageSetup: func(mockStorage *mocks.Storage) {
+				mockStorage.On("Get", mock.Anything, "error123").Return("", errors.New("db fail"))
+			},
+			expectedStatus: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := new(mocks.Storage)
+			tt.mockStorageSetup(mockStorage)
+
+			urlHandler := &httph.URLHandler{
+				Storage: mockStorage,
+				Logger:  logger,
+				BaseURL: "http://short.ly",
+				QRCodes: qrcode.NewCache(qrcode.DefaultCacheSize),
+			}
+
+			conn, cleanup, err := startGRPCServer(NewHandler(urlHandler))
+			require.NoError(t, err)
+			defer cleanup()
+
+			client := pb.NewURLShortenerClient(conn)
+
+			resp, err := client.GetQRCode(context.Background(), &pb.GetQRCodeRequest{
+				ShortUrlId: &tt.id,
+				Format:     &tt.format,
+				Level:      &tt.level,
+				Size:       &tt.size,
+			})
+
+			if tt.expectedStatus != codes.OK {
+				require.Nil(t, resp)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				assert.Equal(t, tt.expectedStatus, st.Code())
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedContentType, resp.GetContentType())
+			assert.NotEmpty(t, resp.GetImage())
+			mockStorage.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetQRCode_GRPC_FileStorage(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+	ctx := context.Background()
+
+	storage, err := infile.New(filepath.Join(t.TempDir(), "urls.json"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = storage.Close() })
+
+	require.NoError(t, storage.PutBatch(ctx, []models.URLRecord{
+		{ID: "live", URL: "https://live.com", UserID: "u1"},
+		{ID: "deleted", URL: "https://deleted.com", UserID: "u1"},
+	}))
+	require.NoError(t, storage.DeleteUserURLs(ctx, []string{"deleted"}, "u1"))
+
+	conn, cleanup, err := startGRPCServer(NewHandler(&httph.URLHandler{Storage: storage, Logger: logger, BaseURL: "http://short.ly"}))
+	require.NoError(t, err)
+	defer cleanup()
+	client := pb.NewURLShortenerClient(conn)
+
+	tests := []struct {
+		id   string
+		want codes.Code
+	}{
+		{id: "live", want: codes.OK},
+		{id: "deleted", want: codes.FailedPrecondition},
+		{id: "missing", want: codes.NotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			_, err := client.GetQRCode(ctx, &pb.GetQRCodeRequest{ShortUrlId: &tt.id})
+			assert.Equal(t, tt.want, status.Code(err))
+		})
+	}
+}
@@ -8,16 +8,32 @@ import (
 	pb "github.com/apetsko/shortugo/proto"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/test/bufconn"
 )
 
 const bufSize = 1024 * 1024
 
-func startGRPCServer(handler pb.URLShortenerServer) (*grpc.ClientConn, func(), error) {
+func startGRPCServer(handler pb.URLShortenerServer, opts ...grpc.ServerOption) (*grpc.ClientConn, func(), error) {
+	return startServer(func(s *grpc.Server) {
+		pb.RegisterURLShortenerServer(s, handler)
+	}, opts...)
+}
+
+// fromIP makes the server see the unary calls of its clients as coming from ip,
+// which in-memory connections have no address for.
+func fromIP(ip string) grpc.ServerOption {
+	return grpc.UnaryInterceptor(func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 50000}}), req)
+	})
+}
+
+// startServer serves the services registered by register over an in-memory listener.
+func startServer(register func(s *grpc.Server), opts ...grpc.ServerOption) (*grpc.ClientConn, func(), error) {
 	lis := bufconn.Listen(bufSize)
 
-	s := grpc.NewServer()
-	pb.RegisterURLShortenerServer(s, handler)
+	s := grpc.NewServer(opts...)
+	register(s)
 
 	go func() {
 		_ = s.Serve(lis)
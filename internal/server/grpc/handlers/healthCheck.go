@@ -7,7 +7,8 @@ import (
 )
 
 // HealthCheck handles a health check request.
-// It responds with a static "OK" status to confirm the service is alive.
+// It responds with a static "OK" status to confirm the service is alive;
+// readiness is reported by the grpc.health.v1.Health service.
 //
 // This method corresponds to a simple HTTP healthcheck endpoint.
 func (h *Handler) HealthCheck(ctx context.Context, req *pb.HealthCheckRequest) (*pb.HealthCheckResponse, error) {
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/apetsko/shortugo/internal/models"
+	httph "github.com/apetsko/shortugo/internal/server/http/handlers"
+	"github.com/apetsko/shortugo/internal/utils"
+	pb "github.com/apetsko/shortugo/proto"
+)
+
+// invalidOptions is reported for batch and stream items with invalid link options, mirroring ShortenBatchJSON.
+const invalidOptions = "Bad Request: Invalid link options"
+
+// blockedDomain is reported for batch and stream items leading to a blocked domain, mirroring ShortenBatchJSON.
+const blockedDomain = "Forbidden: Blocked domain"
+
+// linkOptionsFromProto converts the link options of a request and validates them.
+// A nil message yields zero options, so the server defaults apply. A password is stored as a hash.
+func linkOptionsFromProto(o *pb.LinkOptions) (models.LinkOptions, error) {
+	opts := models.LinkOptions{
+		RedirectType: int(o.GetRedirectType()),
+		RedirectMode: o.GetRedirectMode(),
+		QueryPolicy:  o.GetQueryPolicy(),
+		ForwardPath:  o.GetForwardPath(),
+		MaxClicks:    int(o.GetMaxClicks()),
+		Rules:        rulesFromProto(o.GetRules()),
+		Variants:     variantsFromProto(o.GetVariants()),
+		Interstitial: o.GetInterstitial(),
+		UTM:          utmFromProto(o.GetUtm()),
+	}
+	if err := utils.ValidateStruct(opts); err != nil {
+		return models.LinkOptions{}, err
+	}
+	if err := httph.SetPassword(&opts, o.GetPassword()); err != nil {
+		return models.LinkOptions{}, err
+	}
+	return opts, nil
+}
+
+// utmFromProto converts UTM tags of a request. A nil message yields no tags.
+func utmFromProto(u *pb.UTM) models.UTM {
+	return models.UTM{
+		Source:   u.GetSource(),
+		Medium:   u.GetMedium(),
+		Campaign: u.GetCampaign(),
+		Term:     u.GetTerm(),
+		Content:  u.GetContent(),
+	}
+}
+
+// utmToProto converts UTM tags for a response.
+func utmToProto(u models.UTM) *pb.UTM {
+	return &pb.UTM{
+		Source:   &u.Source,
+		Medium:   &u.Medium,
+		Campaign: &u.Campaign,
+		Term:     &u.Term,
+		Content:  &u.Content,
+	}
+}
+
+// rulesFromProto converts the routing rules of a request. No rules yield nil.
+func rulesFromProto(rr []*pb.RoutingRule) []models.RoutingRule {
+	if len(rr) == 0 {
+		return nil
+	}
+
+	rules := make([]models.RoutingRule, 0, len(rr))
+	for _, r := range rr {
+		rules = append(rules, models.RoutingRule{
+			Platform: r.GetPlatform(),
+			Language: r.GetLanguage(),
+			Country:  r.GetCountry(),
+			URL:      r.GetUrl(),
+		})
+	}
+	return rules
+}
+
+// rulesToProto converts routing rules for a response.
+func rulesToProto(rules []models.RoutingRule) []*pb.RoutingRule {
+	rr := make([]*pb.RoutingRule, 0, len(rules))
+	for _, r := range rules {
+		rr = append(rr, &pb.RoutingRule{
+			Platform: &r.Platform,
+			Language: &r.Language,
+			Country:  &r.Country,
+			Url:      &r.URL,
+		})
+	}
+	return rr
+}
+
+// variantsFromProto converts the A/B variants of a request; click counters are ignored. No variants yield nil.
+func variantsFromProto(vv []*pb.Variant) []models.Variant {
+	if len(vv) == 0 {
+		return nil
+	}
+
+	variants := make([]models.Variant, 0, len(vv))
+	for _, v := range vv {
+		variants = append(variants, models.Variant{
+			Name:   v.GetName(),
+			URL:    v.GetUrl(),
+			Weight: int(v.GetWeight()),
+		})
+	}
+	return variants
+}
+
+// variantsToProto converts A/B variants with their click counters for a response.
+func variantsToProto(variants []models.Variant, clicks map[string]int64) []*pb.Variant {
+	vv := make([]*pb.Variant, 0, len(variants))
+	for _, v := range variants {
+		weight := int32(v.Weight)
+		n := clicks[v.Name]
+		vv = append(vv, &pb.Variant{
+			Name:   &v.Name,
+			Url:    &v.URL,
+			Weight: &weight,
+			Clicks: &n,
+		})
+	}
+	return vv
+}
+
+// titleFromProto trims the title of a new link and checks its length like the HTTP API does.
+func titleFromProto(title string) (string, error) {
+	t := struct {
+		Title string `validate:"max=256"`
+	}{Title: strings.TrimSpace(title)}
+	if err := utils.ValidateStruct(t); err != nil {
+		return "", err
+	}
+	return t.Title, nil
+}
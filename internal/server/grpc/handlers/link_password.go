@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/apetsko/shortugo/internal/models"
+	httph "github.com/apetsko/shortugo/internal/server/http/handlers"
+	pb "github.com/apetsko/shortugo/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SetLinkPassword sets or removes the password of a link owned by the user.
+//
+// Request:
+//   - user_id: string
+//   - short_url_id: string
+//   - password: string; empty removes the protection
+//
+// Response:
+//   - protected: whether the link now requires a password
+func (h *Handler) SetLinkPassword(ctx context.Context, req *pb.SetLinkPasswordRequest) (*pb.SetLinkPasswordResponse, error) {
+	if req.GetUserId() == "" || req.GetShortUrlId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id and short_url_id are required")
+	}
+
+	var options models.LinkOptions
+	if err := httph.SetPassword(&options, req.GetPassword()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid password")
+	}
+
+	err := h.URLHandler.Storage.UpdateLinkOptions(ctx, req.GetShortUrlId(), req.GetUserId(), func(o *models.LinkOptions) error {
+		o.PasswordHash = options.PasswordHash
+		return nil
+	})
+	if err != nil {
+		return nil, h.linkError("failed to update link password", err)
+	}
+	h.auditUpdate(ctx, req.GetUserId(), req.GetShortUrlId(), "password")
+
+	protected := options.Protected()
+	return &pb.SetLinkPasswordResponse{Protected: &protected}, nil
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/apetsko/shortugo/internal/auth"
+	"github.com/apetsko/shortugo/internal/logging"
+	"github.com/apetsko/shortugo/internal/mocks"
+	"github.com/apetsko/shortugo/internal/models"
+	httph "github.com/apetsko/shortugo/internal/server/http/handlers"
+	"github.com/apetsko/shortugo/internal/storages/shared"
+	pb "github.com/apetsko/shortugo/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// applyUpdate runs the update passed to UpdateLinkOptions against o, so tests can check its effect.
+func applyUpdate(o *models.LinkOptions) func(args mock.Arguments) {
+	return func(args mock.Arguments) {
+		update := args.Get(3).(func(o *models.LinkOptions) error)
+		_ = update(o)
+	}
+}
+
+func TestSetLinkPassword_GRPC(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+
+	tests := []struct {
+		name           string
+		userID         string
+		id             string
+		password       string
+		storageErr     error
+		expectedStatus codes.Code
+		callsStorage   bool
+	}{
+		{
+			name:           "password set",
+			userID:         "user123",
+			id:             "abc123",
+			password:       "s3cret",
+			callsStorage:   true,
+			expectedStatus: codes.OK,
+		},
+		{
+			name:           "password removed",
+			userID:         "user123",
+			id:             "abc123",
+			callsStorage:   true,
+			expectedStatus: codes.OK,
+		},
+		{
+			name:           "missing user ID",
+			id:             "abc123",
+			expectedStatus: codes.InvalidArgument,
+		},
+		{
+			name:           "password too long",
+			userID:         "user123",
+			id:             "abc123",
+			password:       strings.Repeat("a", 73),
+			expectedStatus: codes.InvalidArgument,
+		},
+		{
+			name:           "foreign link",
+			userID:         "user123",
+			id:             "abc123",
+			password:       "s3cret",
+			storageErr:     shared.ErrNotFound,
+			callsStorage:   true,
+			expectedStatus: codes.NotFound,
+		},
+		{
+			name:           "deleted link",
+			userID:         "user123",
+			id:             "abc123",
+			password:       "s3cret",
+			storageErr:     shared.ErrGone,
+			callsStorage:   true,
+			expectedStatus: codes.FailedPrecondition,
+		},
+		{
+			name:           "internal error",
+			userID:         "user123",
+			id:             "abc123",
+			password:       "s3cret",
+			storageErr:     errors.New("db fail"),
+			callsStorage:   true,
+			expectedStatus: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The link starts with a password, so removing it is visible.
+			opts := models.LinkOptions{PasswordHash: "old"}
+			mockStorage := new(mocks.Storage)
+			if tt.callsStorage {
+				mockStorage.On("UpdateLinkOptions", mock.Anything, tt.id, tt.userID, mock.Anything).
+					Run(applyUpdate(&opts)).Return(tt.storageErr)
+			}
+
+			conn, cleanup, err := startGRPCServer(NewHandler(&httph.URLHandler{Storage: mockStorage, Logger: logger}))
+			require.NoError(t, err)
+			defer cleanup()
+
+			client := pb.NewURLShortenerClient(conn)
+			resp, err := client.SetLinkPassword(context.Background(), &pb.SetLinkPasswordRequest{
+				UserId:     &tt.userID,
+				ShortUrlId: &tt.id,
+				Password:   &tt.password,
+			})
+			mockStorage.AssertExpectations(t)
+
+			if tt.expectedStatus != codes.OK {
+				require.Nil(t, resp)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				assert.Equal(t, tt.expectedStatus, st.Code())
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.password != "", resp.GetProtected())
+			assert.Equal(t, tt.password != "", opts.Protected())
+			if tt.password != "" {
+				assert.True(t, auth.CheckPassword(opts.PasswordHash, tt.password))
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/apetsko/shortugo/internal/models"
+	"github.com/apetsko/shortugo/internal/storages/shared"
+	"github.com/apetsko/shortugo/internal/utils"
+	pb "github.com/apetsko/shortugo/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GetLinkRules returns the routing rules of a link owned by the user.
+//
+// Request:
+//   - user_id: string
+//   - short_url_id: string
+//
+// Response:
+//   - rules: routing rules in the order they are tried
+func (h *Handler) GetLinkRules(ctx context.Context, req *pb.GetLinkRulesRequest) (*pb.LinkRulesResponse, error) {
+	if req.GetUserId() == "" || req.GetShortUrlId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id and short_url_id are required")
+	}
+
+	rec, err := h.URLHandler.Storage.GetRecord(ctx, req.GetShortUrlId())
+	if err == nil && rec.UserID != req.GetUserId() {
+		err = shared.ErrNotFound
+	}
+	if err != nil {
+		return nil, h.linkError("failed to get link rules", err)
+	}
+
+	return &pb.LinkRulesResponse{Rules: rulesToProto(rec.Rules)}, nil
+}
+
+// SetLinkRules replaces the routing rules of a link owned by the user.
+// Redirects try the rules in order and fall back to the link URL; empty rules remove them.
+//
+// Request:
+//   - user_id: string
+//   - short_url_id: string
+//   - rules: routing rules
+//
+// Response:
+//   - rules: the stored routing rules
+func (h *Handler) SetLinkRules(ctx context.Context, req *pb.SetLinkRulesRequest) (*pb.LinkRulesResponse, error) {
+	if req.GetUserId() == "" || req.GetShortUrlId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id and short_url_id are required")
+	}
+
+	options := models.LinkOptions{Rules: rulesFromProto(req.GetRules())}
+	if err := utils.ValidateStruct(options); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid routing rules")
+	}
+
+	err := h.URLHandler.Storage.UpdateLinkOptions(ctx, req.GetShortUrlId(), req.GetUserId(), func(o *models.LinkOptions) error {
+		o.Rules = options.Rules
+		return nil
+	})
+	if err != nil {
+		return nil, h.linkError("failed to update link rules", err)
+	}
+	h.auditUpdate(ctx, req.GetUserId(), req.GetShortUrlId(), "rules")
+
+	return &pb.LinkRulesResponse{Rules: rulesToProto(options.Rules)}, nil
+}
+
+// linkError converts a failed lookup or update of a user's link to a gRPC status.
+func (h *Handler) linkError(msg string, err error) error {
+	switch {
+	case errors.Is(err, shared.ErrNotFound):
+		return status.Error(codes.NotFound, "URL not found")
+	case errors.Is(err, shared.ErrGone):
+		return status.Error(codes.FailedPrecondition, "URL is gone")
+	default:
+		h.URLHandler.Logger.Error(msg, "error", err.Error())
+		return status.Error(codes.Internal, msg)
+	}
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/apetsko/shortugo/internal/logging"
+	"github.com/apetsko/shortugo/internal/mocks"
+	"github.com/apetsko/shortugo/internal/models"
+	httph "github.com/apetsko/shortugo/internal/server/http/handlers"
+	"github.com/apetsko/shortugo/internal/storages/shared"
+	pb "github.com/apetsko/shortugo/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetLinkRules_GRPC(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+	rules := []models.RoutingRule{{Platform: models.PlatformIOS, URL: "https://apps.apple.com/app/id1"}}
+
+	tests := []struct {
+		record         *models.URLRecord
+		storageErr     error
+		name           string
+		userID         string
+		expected       []models.RoutingRule
+		expectedStatus codes.Code
+	}{
+		{
+			name:           "rules set",
+			userID:         "user123",
+			record:         &models.URLRecord{ID: "abc123", UserID: "user123", LinkOptions: models.LinkOptions{Rules: rules}},
+			expected:       rules,
+			expectedStatus: codes.OK,
+		},
+		{
+			name:           "foreign link",
+			userID:         "user123",
+			record:         &models.URLRecord{ID: "abc123", UserID: "other", LinkOptions: models.LinkOptions{Rules: rules}},
+			expectedStatus: codes.NotFound,
+		},
+		{
+			name:           "deleted link",
+			userID:         "user123",
+			storageErr:     shared.ErrGone,
+			expectedStatus: codes.FailedPrecondition,
+		},
+		{
+			name:           "missing user ID",
+			expectedStatus: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := new(mocks.Storage)
+			mockStorage.On("GetRecord", mock.Anything, "abc123").Return(tt.record, tt.storageErr).Maybe()
+
+			conn, cleanup, err := startGRPCServer(NewHandler(&httph.URLHandler{Storage: mockStorage, Logger: logger}))
+			require.NoError(t, err)
+			defer cleanup()
+
+			client := pb.NewURLShortenerClient(conn)
+			id := "abc123"
+			resp, err := client.GetLinkRules(context.Background(), &pb.GetLinkRulesRequest{UserId: &tt.userID, ShortUrlId: &id})
+
+			if tt.expectedStatus != codes.OK {
+				require.Nil(t, resp)
+				assert.Equal(t, tt.expectedStatus, status.Code(err))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, rulesFromProto(resp.GetRules()))
+		})
+	}
+}
+
+func TestSetLinkRules_GRPC(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+	android := []models.RoutingRule{{Platform: models.PlatformAndroid, URL: "https://play.google.com/store"}}
+
+	tests := []struct {
+		storageErr     error
+		name           string
+		rules          []models.RoutingRule
+		expectedStatus codes.Code
+		callsStorage   bool
+	}{
+		{
+			name:           "rules stored",
+			rules:          android,
+			callsStorage:   true,
+			expectedStatus: codes.OK,
+		},
+		{
+			name:           "rules removed",
+			callsStorage:   true,
+			expectedStatus: codes.OK,
+		},
+		{
+			name:           "invalid rule",
+			rules:          []models.RoutingRule{{Language: "not a language", URL: "https://example.com"}},
+			expectedStatus: codes.InvalidArgument,
+		},
+		{
+			name:           "foreign link",
+			rules:          android,
+			storageErr:     shared.ErrNotFound,
+			callsStorage:   true,
+			expectedStatus: codes.NotFound,
+		},
+		{
+			name:           "internal error",
+			rules:          android,
+			storageErr:     errors.New("db fail"),
+			callsStorage:   true,
+			expectedStatus: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The link starts with rules, so removing them is visible.
+			opts := models.LinkOptions{Rules: []models.RoutingRule{{Country: "DE", URL: "https://example.com/de"}}}
+			mockStorage := new(mocks.Storage)
+			if tt.callsStorage {
+				mockStorage.On("UpdateLinkOptions", mock.Anything, "abc123", "user123", mock.Anything).
+					Run(applyUpdate(&opts)).Return(tt.storageErr)
+			}
+
+			conn, cleanup, err := startGRPCServer(NewHandler(&httph.URLHandler{Storage: mockStorage, Logger: logger}))
+			require.NoError(t, err)
+			defer cleanup()
+
+			client := pb.NewURLShortenerClient(conn)
+			userID, id := "user123", "abc123"
+			resp, err := client.SetLinkRules(context.Background(), &pb.SetLinkRulesRequest{
+				UserId:     &userID,
+				ShortUrlId: &id,
+				Rules:      rulesToProto(tt.rules),
+			})
+			mockStorage.AssertExpectations(t)
+
+			if tt.expectedStatus != codes.OK {
+				require.Nil(t, resp)
+				assert.Equal(t, tt.expectedStatus, status.Code(err))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.rules, opts.Rules)
+			assert.Equal(t, tt.rules, rulesFromProto(resp.GetRules()))
+		})
+	}
+}
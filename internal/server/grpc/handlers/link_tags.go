@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/apetsko/shortugo/internal/models"
+	"github.com/apetsko/shortugo/internal/storages/shared"
+	"github.com/apetsko/shortugo/internal/utils"
+	pb "github.com/apetsko/shortugo/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// tagsRequest validates the tags of AddLinkTags and RemoveLinkTags like the HTTP endpoints do.
+type tagsRequest struct {
+	Tags []string `validate:"required,min=1,max=32,dive,required,max=64"`
+}
+
+// folderRequest validates the folder of SetLinkFolder like the HTTP endpoint does.
+type folderRequest struct {
+	Folder string `validate:"max=128"`
+}
+
+// AddLinkTags adds tags to a link owned by the user.
+// Tags are trimmed and lowercased; a link has at most 32 tags.
+//
+// Request:
+//   - user_id: string
+//   - short_url_id: string
+//   - tags: tags to add
+//
+// Response:
+//   - tags, folder: the stored labels of the link
+func (h *Handler) AddLinkTags(ctx context.Context, req *pb.LinkTagsRequest) (*pb.LinkLabelsResponse, error) {
+	return h.updateLinkTags(ctx, req, h.URLHandler.Storage.AddLinkTags)
+}
+
+// RemoveLinkTags removes tags from a link owned by the user. Tags the link does not have are ignored.
+//
+// Request:
+//   - user_id: string
+//   - short_url_id: string
+//   - tags: tags to remove
+//
+// Response:
+//   - tags, folder: the stored labels of the link
+func (h *Handler) RemoveLinkTags(ctx context.Context, req *pb.LinkTagsRequest) (*pb.LinkLabelsResponse, error) {
+	return h.updateLinkTags(ctx, req, h.URLHandler.Storage.RemoveLinkTags)
+}
+
+// updateLinkTags validates the tags of req and passes them to update.
+func (h *Handler) updateLinkTags(ctx context.Context, req *pb.LinkTagsRequest, update func(ctx context.Context, id, userID string, tags []string) error) (*pb.LinkLabelsResponse, error) {
+	if req.GetUserId() == "" || req.GetShortUrlId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id and short_url_id are required")
+	}
+
+	t := tagsRequest{Tags: req.GetTags()}
+	if err := utils.ValidateStruct(t); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid tags")
+	}
+	tags := models.NormalizeTags(t.Tags)
+	if len(tags) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid tags")
+	}
+
+	if err := update(ctx, req.GetShortUrlId(), req.GetUserId(), tags); err != nil {
+		if errors.Is(err, shared.ErrTooManyTags) {
+			return nil, status.Error(codes.InvalidArgument, "too many tags")
+		}
+		return nil, h.linkError("failed to update link tags", err)
+	}
+	h.auditUpdate(ctx, req.GetUserId(), req.GetShortUrlId(), "tags")
+
+	return h.labelsResponse(ctx, req.GetShortUrlId())
+}
+
+// SetLinkFolder files a link owned by the user in a folder.
+//
+// Request:
+//   - user_id: string
+//   - short_url_id: string
+//   - folder: up to 128 characters; empty takes the link out of its folder
+//
+// Response:
+//   - tags, folder: the stored labels of the link
+func (h *Handler) SetLinkFolder(ctx context.Context, req *pb.SetLinkFolderRequest) (*pb.LinkLabelsResponse, error) {
+	if req.GetUserId() == "" || req.GetShortUrlId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id and short_url_id are required")
+	}
+
+	f := folderRequest{Folder: strings.TrimSpace(req.GetFolder())}
+	if err := utils.ValidateStruct(f); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid folder")
+	}
+
+	if err := h.URLHandler.Storage.SetLinkFolder(ctx, req.GetShortUrlId(), req.GetUserId(), f.Folder); err != nil {
+		return nil, h.linkError("failed to update link folder", err)
+	}
+	h.auditUpdate(ctx, req.GetUserId(), req.GetShortUrlId(), "folder")
+
+	return h.labelsResponse(ctx, req.GetShortUrlId())
+}
+
+// labelsResponse reads the stored tags and folder of a link.
+func (h *Handler) labelsResponse(ctx context.Context, id string) (*pb.LinkLabelsResponse, error) {
+	rec, err := h.URLHandler.Storage.GetRecord(ctx, id)
+	if err != nil {
+		return nil, h.linkError("failed to get link labels", err)
+	}
+
+	tags := rec.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return &pb.LinkLabelsResponse{Tags: tags, Folder: &rec.Folder}, nil
+}
@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/apetsko/shortugo/internal/logging"
+	"github.com/apetsko/shortugo/internal/mocks"
+	"github.com/apetsko/shortugo/internal/models"
+	httph "github.com/apetsko/shortugo/internal/server/http/handlers"
+	"github.com/apetsko/shortugo/internal/storages/inmem"
+	"github.com/apetsko/shortugo/internal/storages/shared"
+	pb "github.com/apetsko/shortugo/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestLinkTags_GRPC(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+	ctx := context.Background()
+
+	storage := inmem.New()
+	require.NoError(t, storage.Put(ctx, models.URLRecord{ID: "abc123", URL: "https://example.com", UserID: "user123"}))
+	require.NoError(t, storage.Put(ctx, models.URLRecord{ID: "def456", URL: "https://example.org", UserID: "user123"}))
+
+	conn, cleanup, err := startGRPCServer(NewHandler(&httph.URLHandler{Storage: storage, Logger: logger, BaseURL: "http://short.ly"}))
+	require.NoError(t, err)
+	defer cleanup()
+
+	client := pb.NewURLShortenerClient(conn)
+	userID, id, folder := "user123", "abc123", " Campaigns "
+
+	resp, err := client.AddLinkTags(ctx, &pb.LinkTagsRequest{UserId: &userID, ShortUrlId: &id, Tags: []string{"Promo", "2026"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2026", "promo"}, resp.GetTags())
+
+	resp, err = client.SetLinkFolder(ctx, &pb.SetLinkFolderRequest{UserId: &userID, ShortUrlId: &id, Folder: &folder})
+	require.NoError(t, err)
+	assert.Equal(t, "Campaigns", resp.GetFolder())
+	assert.Equal(t, []string{"2026", "promo"}, resp.GetTags())
+
+	tag := "PROMO"
+	list, err := client.ListUserURLs(ctx, &pb.ListUserURLsRequest{UserId: &userID, Tag: &tag})
+	require.NoError(t, err)
+	require.Len(t, list.GetUrls(), 1)
+	assert.Equal(t, "http://short.ly/abc123", list.GetUrls()[0].GetShortUrl())
+	assert.Equal(t, "Campaigns", list.GetUrls()[0].GetFolder())
+	assert.Equal(t, []string{"2026", "promo"}, list.GetUrls()[0].GetTags())
+
+	stream, err := client.StreamUserURLs(ctx, &pb.ListUserURLsRequest{UserId: &userID, Tag: &tag})
+	require.NoError(t, err)
+	pair, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "http://short.ly/abc123", pair.GetShortUrl())
+	_, err = stream.Recv()
+	assert.Error(t, err)
+
+	resp, err = client.RemoveLinkTags(ctx, &pb.LinkTagsRequest{UserId: &userID, ShortUrlId: &id, Tags: []string{"promo"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2026"}, resp.GetTags())
+
+	_, err = client.ListUserURLs(ctx, &pb.ListUserURLsRequest{UserId: &userID, Tag: &tag})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestAddLinkTags_GRPC(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+
+	tests := []struct {
+		storageErr     error
+		name           string
+		userID         string
+		tags           []string
+		callsStorage   bool
+		expectedStatus codes.Code
+	}{
+		{
+			name:           "deleted link",
+			userID:         "user123",
+			tags:           []string{"promo"},
+			storageErr:     shared.ErrGone,
+			callsStorage:   true,
+			expectedStatus: codes.FailedPrecondition,
+		},
+		{
+			name:           "foreign link",
+			userID:         "user123",
+			tags:           []string{"promo"},
+			storageErr:     shared.ErrNotFound,
+			callsStorage:   true,
+			expectedStatus: codes.NotFound,
+		},
+		{
+			name:           "too many tags",
+			userID:         "user123",
+			tags:           []string{"promo"},
+			storageErr:     shared.ErrTooManyTags,
+			callsStorage:   true,
+			expectedStatus: codes.InvalidArgument,
+		},
+		{
+			name:           "storage failure",
+			userID:         "user123",
+			tags:           []string{"promo"},
+			storageErr:     errors.New("db fail"),
+			callsStorage:   true,
+			expectedStatus: codes.Internal,
+		},
+		{
+			name:           "no tags",
+			userID:         "user123",
+			expectedStatus: codes.InvalidArgument,
+		},
+		{
+			name:           "blank tag",
+			userID:         "user123",
+			tags:           []string{" "},
+			expectedStatus: codes.InvalidArgument,
+		},
+		{
+			name:           "tag too long",
+			userID:         "user123",
+			tags:           []string{strings.Repeat("a", 65)},
+			expectedStatus: codes.InvalidArgument,
+		},
+		{
+			name:           "missing user ID",
+			tags:           []string{"promo"},
+			expectedStatus: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := new(mocks.Storage)
+			if tt.callsStorage {
+				mockStorage.On("AddLinkTags", mock.Anything, "abc123", tt.userID, tt.tags).Return(tt.storageErr)
+			}
+
+			conn, cleanup, err := startGRPCServer(NewHandler(&httph.URLHandler{Storage: mockStorage, Logger: logger}))
+			require.NoError(t, err)
+			defer cleanup()
+
+			client := pb.NewURLShortenerClient(conn)
+			id := "abc123"
+			_, err = client.AddLinkTags(context.Background(), &pb.LinkTagsRequest{UserId: &tt.userID, ShortUrlId: &id, Tags: tt.tags})
+
+			assert.Equal(t, tt.expectedStatus, status.Code(err))
+			mockStorage.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSetLinkFolder_GRPC(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+
+	mockStorage := new(mocks.Storage)
+	conn, cleanup, err := startGRPCServer(NewHandler(&httph.URLHandler{Storage: mockStorage, Logger: logger}))
+	require.NoError(t, err)
+	defer cleanup()
+
+	client := pb.NewURLShortenerClient(conn)
+	userID, id, folder := "user123", "abc123", strings.Repeat("a", 129)
+	_, err = client.SetLinkFolder(context.Background(), &pb.SetLinkFolderRequest{UserId: &userID, ShortUrlId: &id, Folder: &folder})
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	mockStorage.AssertExpectations(t)
+}
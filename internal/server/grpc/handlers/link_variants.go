@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/apetsko/shortugo/internal/models"
+	"github.com/apetsko/shortugo/internal/storages/shared"
+	"github.com/apetsko/shortugo/internal/utils"
+	pb "github.com/apetsko/shortugo/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// variantsRequest validates the A/B variants of SetLinkVariants like the HTTP endpoint does.
+type variantsRequest struct {
+	Variants []models.Variant `validate:"omitempty,min=2,max=16,unique=Name,dive"`
+}
+
+// GetLinkVariants returns the A/B variants of a link owned by the user with their click counters.
+//
+// Request:
+//   - user_id: string
+//   - short_url_id: string
+//
+// Response:
+//   - variants: A/B variants with their click counters
+func (h *Handler) GetLinkVariants(ctx context.Context, req *pb.GetLinkVariantsRequest) (*pb.LinkVariantsResponse, error) {
+	if req.GetUserId() == "" || req.GetShortUrlId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id and short_url_id are required")
+	}
+
+	rec, err := h.URLHandler.Storage.GetRecord(ctx, req.GetShortUrlId())
+	if err == nil && rec.UserID != req.GetUserId() {
+		err = shared.ErrNotFound
+	}
+	if err != nil {
+		return nil, h.linkError("failed to get link variants", err)
+	}
+
+	return h.variantsResponse(ctx, rec.ID, rec.Variants)
+}
+
+// SetLinkVariants replaces the A/B variants of a link owned by the user.
+// Counters are kept by variant name; empty variants end the A/B test.
+//
+// Request:
+//   - user_id: string
+//   - short_url_id: string
+//   - variants: 2 to 16 variants with unique names and weights from 1 to 1000
+//
+// Response:
+//   - variants: the stored variants with their click counters
+func (h *Handler) SetLinkVariants(ctx context.Context, req *pb.SetLinkVariantsRequest) (*pb.LinkVariantsResponse, error) {
+	if req.GetUserId() == "" || req.GetShortUrlId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id and short_url_id are required")
+	}
+
+	v := variantsRequest{Variants: variantsFromProto(req.GetVariants())}
+	if err := utils.ValidateStruct(v); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid variants")
+	}
+
+	err := h.URLHandler.Storage.UpdateLinkOptions(ctx, req.GetShortUrlId(), req.GetUserId(), func(o *models.LinkOptions) error {
+		o.Variants = v.Variants
+		return nil
+	})
+	if err != nil {
+		return nil, h.linkError("failed to update link variants", err)
+	}
+	h.auditUpdate(ctx, req.GetUserId(), req.GetShortUrlId(), "variants")
+
+	return h.variantsResponse(ctx, req.GetShortUrlId(), v.Variants)
+}
+
+// variantsResponse adds the click counters of the link to its variants.
+func (h *Handler) variantsResponse(ctx context.Context, id string, variants []models.Variant) (*pb.LinkVariantsResponse, error) {
+	if len(variants) == 0 {
+		return &pb.LinkVariantsResponse{Variants: []*pb.Variant{}}, nil
+	}
+
+	clicks, err := h.URLHandler.Storage.VariantClicks(ctx, id)
+	if err != nil {
+		return nil, h.linkError("failed to get variant clicks", err)
+	}
+
+	return &pb.LinkVariantsResponse{Variants: variantsToProto(variants, clicks)}, nil
+}
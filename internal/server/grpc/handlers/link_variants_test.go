@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/apetsko/shortugo/internal/logging"
+	"github.com/apetsko/shortugo/internal/mocks"
+	"github.com/apetsko/shortugo/internal/models"
+	httph "github.com/apetsko/shortugo/internal/server/http/handlers"
+	"github.com/apetsko/shortugo/internal/storages/shared"
+	pb "github.com/apetsko/shortugo/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetLinkVariants_GRPC(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+	variants := []models.Variant{
+		{Name: "a", URL: "https://example.com/a", Weight: 70},
+		{Name: "b", URL: "https://example.com/b", Weight: 30},
+	}
+
+	tests := []struct {
+		record         *models.URLRecord
+		storageErr     error
+		name           string
+		userID         string
+		expectedClicks []int64
+		expectedStatus codes.Code
+	}{
+		{
+			name:           "variants set",
+			userID:         "user123",
+			record:         &models.URLRecord{ID: "abc123", UserID: "user123", LinkOptions: models.LinkOptions{Variants: variants}},
+			expectedClicks: []int64{12, 0},
+			expectedStatus: codes.OK,
+		},
+		{
+			name:           "no variants",
+			userID:         "user123",
+			record:         &models.URLRecord{ID: "abc123", UserID: "user123"},
+			expectedStatus: codes.OK,
+		},
+		{
+			name:           "foreign link",
+			userID:         "user123",
+			record:         &models.URLRecord{ID: "abc123", UserID: "other", LinkOptions: models.LinkOptions{Variants: variants}},
+			expectedStatus: codes.NotFound,
+		},
+		{
+			name:           "deleted link",
+			userID:         "user123",
+			storageErr:     shared.ErrGone,
+			expectedStatus: codes.FailedPrecondition,
+		},
+		{
+			name:           "missing user ID",
+			expectedStatus: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := new(mocks.Storage)
+			mockStorage.On("GetRecord", mock.Anything, "abc123").Return(tt.record, tt.storageErr).Maybe()
+			mockStorage.On("VariantClicks", mock.Anything, "abc123").Return(map[string]int64{"a": 12}, nil).Maybe()
+
+			conn, cleanup, err := startGRPCServer(NewHandler(&httph.URLHandler{Storage: mockStorage, Logger: logger}))
+			require.NoError(t, err)
+			defer cleanup()
+
+			client := pb.NewURLShortenerClient(conn)
+			id := "abc123"
+			resp, err := client.GetLinkVariants(context.Background(), &pb.GetLinkVariantsRequest{UserId: &tt.userID, ShortUrlId: &id})
+
+			if tt.expectedStatus != codes.OK {
+				require.Nil(t, resp)
+				assert.Equal(t, tt.expectedStatus, status.Code(err))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.record.Variants, variantsFromProto(resp.GetVariants()))
+			var clicks []int64
+			for _, v := range resp.GetVariants() {
+				clicks = append(clicks, v.GetClicks())
+			}
+			assert.Equal(t, tt.expectedClicks, clicks)
+		})
+	}
+}
+
+func TestSetLinkVariants_GRPC(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+	variants := []models.Variant{
+		{Name: "a", URL: "https://example.com/a", Weight: 1},
+		{Name: "b", URL: "https://example.com/b", Weight: 3},
+	}
+
+	tests := []struct {
+		storageErr     error
+		name           string
+		variants       []models.Variant
+		expectedStatus codes.Code
+		callsStorage   bool
+	}{
+		{
+			name:           "variants stored",
+			variants:       variants,
+			callsStorage:   true,
+			expectedStatus: codes.OK,
+		},
+		{
+			name:           "experiment ended",
+			callsStorage:   true,
+			expectedStatus: codes.OK,
+		},
+		{
+			name:           "single variant",
+			variants:       variants[:1],
+			expectedStatus: codes.InvalidArgument,
+		},
+		{
+			name: "duplicate names",
+			variants: []models.Variant{
+				{Name: "a", URL: "https://example.com/a", Weight: 1},
+				{Name: "a", URL: "https://example.com/b", Weight: 1},
+			},
+			expectedStatus: codes.InvalidArgument,
+		},
+		{
+			name:           "foreign link",
+			variants:       variants,
+			storageErr:     shared.ErrNotFound,
+			callsStorage:   true,
+			expectedStatus: codes.NotFound,
+		},
+		{
+			name:           "internal error",
+			variants:       variants,
+			storageErr:     errors.New("db fail"),
+			callsStorage:   true,
+			expectedStatus: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The link starts with variants, so ending the experiment is visible.
+			opts := models.LinkOptions{Variants: []models.Variant{
+				{Name: "x", URL: "https://example.com/x", Weight: 1},
+				{Name: "y", URL: "https://example.com/y", Weight: 1},
+			}}
+			mockStorage := new(mocks.Storage)
+			if tt.callsStorage {
+				mockStorage.On("UpdateLinkOptions", mock.Anything, "abc123", "user123", mock.Anything).
+					Run(applyUpdate(&opts)).Return(tt.storageErr)
+			}
+			mockStorage.On("VariantClicks", mock.Anything, "abc123").Return(map[string]int64{}, nil).Maybe()
+
+			conn, cleanup, err := startGRPCServer(NewHandler(&httph.URLHandler{Storage: mockStorage, Logger: logger}))
+			require.NoError(t, err)
+			defer cleanup()
+
+			client := pb.NewURLShortenerClient(conn)
+			userID, id := "user123", "abc123"
+			resp, err := client.SetLinkVariants(context.Background(), &pb.SetLinkVariantsRequest{
+				UserId:     &userID,
+				ShortUrlId: &id,
+				Variants:   variantsToProto(tt.variants, nil),
+			})
+			mockStorage.AssertExpectations(t)
+
+			if tt.expectedStatus != codes.OK {
+				require.Nil(t, resp)
+				assert.Equal(t, tt.expectedStatus, status.Code(err))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.variants, opts.Variants)
+			assert.Equal(t, tt.variants, variantsFromProto(resp.GetVariants()))
+		})
+	}
+}
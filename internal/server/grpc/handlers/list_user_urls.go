@@ -3,7 +3,9 @@ package handlers
 import (
 	"context"
 	"errors"
+	"strings"
 
+	"github.com/apetsko/shortugo/internal/models"
 	"github.com/apetsko/shortugo/internal/storages/shared"
 	pb "github.com/apetsko/shortugo/proto"
 	"google.golang.org/grpc/codes"
@@ -14,11 +16,20 @@ import (
 //
 // Request:
 //   - user_id: string
+//   - tag, folder: optional, only list the links with the tag and in the folder
 //
 // Response:
-//   - repeated URLPair (short + original URLs)
+//   - repeated URLPair (short + original URLs with their tags and folder)
 func (h *Handler) ListUserURLs(ctx context.Context, req *pb.ListUserURLsRequest) (*pb.ListUserURLsResponse, error) {
-	records, err := h.URLHandler.Storage.ListLinksByUserID(ctx, h.URLHandler.BaseURL, req.GetUserId())
+	var (
+		records []models.URLRecord
+		err     error
+	)
+	if filter := listFilter(req); filter.IsZero() {
+		records, err = h.URLHandler.Storage.ListLinksByUserID(ctx, h.URLHandler.BaseURL, req.GetUserId())
+	} else {
+		records, err = h.URLHandler.Storage.ListLinksByFilter(ctx, h.URLHandler.BaseURL, req.GetUserId(), filter)
+	}
 	if err != nil {
 		if errors.Is(err, shared.ErrNotFound) {
 			h.URLHandler.Logger.Error("no URLs for user: " + req.GetUserId())
@@ -30,13 +41,34 @@ func (h *Handler) ListUserURLs(ctx context.Context, req *pb.ListUserURLsRequest)
 
 	resp := &pb.ListUserURLsResponse{}
 	for _, record := range records {
-		empty := ""
-		resp.Urls = append(resp.Urls, &pb.URLPair{
-			CorrelationId: &empty, // not used here
-			OriginalUrl:   &record.URL,
-			ShortUrl:      &record.ID,
-		})
+		resp.Urls = append(resp.Urls, userURLToProto(record))
 	}
 
 	return resp, nil
 }
+
+// userURLToProto converts a listed link to a URLPair. The folder and title are only set when the link has them.
+func userURLToProto(r models.URLRecord) *pb.URLPair {
+	empty := ""
+	pair := &pb.URLPair{
+		CorrelationId: &empty, // not used here
+		OriginalUrl:   &r.URL,
+		ShortUrl:      &r.ID,
+		Tags:          r.Tags,
+	}
+	if r.Folder != "" {
+		pair.Folder = &r.Folder
+	}
+	if r.Title != "" {
+		pair.Title = &r.Title
+	}
+	return pair
+}
+
+// listFilter reads the optional tag and folder of a listing request.
+func listFilter(req *pb.ListUserURLsRequest) models.LinkFilter {
+	return models.LinkFilter{
+		Tag:    strings.ToLower(strings.TrimSpace(req.GetTag())),
+		Folder: strings.TrimSpace(req.GetFolder()),
+	}
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/apetsko/shortugo/internal/search"
+	pb "github.com/apetsko/shortugo/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SearchUserURLs finds the URLs of the given user by the words of their destination URL, alias, title and tags.
+//
+// Request:
+//   - user_id: string
+//   - q: every word has to start a word of the link
+//
+// Response:
+//   - repeated URLPair, best matches first, at most 100; empty when nothing matches
+func (h *Handler) SearchUserURLs(ctx context.Context, req *pb.SearchUserURLsRequest) (*pb.ListUserURLsResponse, error) {
+	if req.GetUserId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	if len(search.Terms(req.GetQ())) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "q has no words")
+	}
+
+	records, err := h.URLHandler.Storage.SearchLinks(ctx, h.URLHandler.BaseURL, req.GetUserId(), req.GetQ())
+	if err != nil {
+		h.URLHandler.Logger.Error("storage error: " + err.Error())
+		return nil, status.Error(codes.Internal, "failed to search URLs")
+	}
+
+	resp := &pb.ListUserURLsResponse{Urls: make([]*pb.URLPair, 0, len(records))}
+	for _, record := range records {
+		resp.Urls = append(resp.Urls, userURLToProto(record))
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/apetsko/shortugo/internal/logging"
+	"github.com/apetsko/shortugo/internal/mocks"
+	"github.com/apetsko/shortugo/internal/models"
+	httph "github.com/apetsko/shortugo/internal/server/http/handlers"
+	pb "github.com/apetsko/shortugo/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSearchUserURLs_GRPC(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+
+	tests := []struct {
+		storageErr     error
+		name           string
+		userID         string
+		query          string
+		records        []models.URLRecord
+		expectedURLs   []string
+		callsStorage   bool
+		expectedStatus codes.Code
+	}{
+		{
+			name:   "matches",
+			userID: "user123",
+			query:  "docs",
+			records: []models.URLRecord{
+				{ID: "http://short.ly/abc123", URL: "https://example.com/docs", Title: "Docs"},
+				{ID: "http://short.ly/def456", URL: "https://example.org/docs"},
+			},
+			callsStorage:   true,
+			expectedURLs:   []string{"http://short.ly/abc123", "http://short.ly/def456"},
+			expectedStatus: codes.OK,
+		},
+		{
+			name:           "no match",
+			userID:         "user123",
+			query:          "docs",
+			records:        []models.URLRecord{},
+			callsStorage:   true,
+			expectedURLs:   []string{},
+			expectedStatus: codes.OK,
+		},
+		{
+			name:           "storage failure",
+			userID:         "user123",
+			query:          "docs",
+			storageErr:     errors.New("db fail"),
+			callsStorage:   true,
+			expectedStatus: codes.Internal,
+		},
+		{
+			name:           "empty query",
+			userID:         "user123",
+			query:          " / ",
+			expectedStatus: codes.InvalidArgument,
+		},
+		{
+			name:           "missing user ID",
+			query:          "docs",
+			expectedStatus: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := new(mocks.Storage)
+			if tt.callsStorage {
+				mockStorage.On("SearchLinks", mock.Anything, "http://short.ly", tt.userID, tt.query).Return(tt.records, tt.storageErr)
+			}
+
+			conn, cleanup, err := startGRPCServer(NewHandler(&httph.URLHandler{Storage: mockStorage, Logger: logger, BaseURL: "http://short.ly"}))
+			require.NoError(t, err)
+			defer cleanup()
+
+			client := pb.NewURLShortenerClient(conn)
+			resp, err := client.SearchUserURLs(context.Background(), &pb.SearchUserURLsRequest{UserId: &tt.userID, Q: &tt.query})
+
+			assert.Equal(t, tt.expectedStatus, status.Code(err))
+			mockStorage.AssertExpectations(t)
+			if tt.expectedStatus != codes.OK {
+				return
+			}
+
+			urls := make([]string, 0, len(resp.GetUrls()))
+			for _, u := range resp.GetUrls() {
+				urls = append(urls, u.GetShortUrl())
+			}
+			assert.Equal(t, tt.expectedURLs, urls)
+			if len(tt.records) > 0 {
+				assert.Equal(t, "Docs", resp.GetUrls()[0].GetTitle())
+			}
+		})
+	}
+}
@@ -3,8 +3,9 @@ package handlers
 import (
 	"context"
 
+	"github.com/apetsko/shortugo/internal/audit"
 	"github.com/apetsko/shortugo/internal/models"
-	"github.com/apetsko/shortugo/internal/utils"
+	httph "github.com/apetsko/shortugo/internal/server/http/handlers"
 	pb "github.com/apetsko/shortugo/proto"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -17,6 +18,12 @@ func (h *Handler) ShortenBatch(ctx context.Context, req *pb.ShortenBatchRequest)
 		return nil, status.Error(codes.InvalidArgument, "user_id is required")
 	}
 
+	template, err := h.URLHandler.UTMTemplate(ctx, req.GetUserId())
+	if err != nil {
+		h.URLHandler.Logger.Error("failed to get UTM template", "error", err.Error())
+		return nil, status.Error(codes.Internal, "failed to get UTM template")
+	}
+
 	var records []models.URLRecord
 	var results []*pb.URLPair
 
@@ -29,13 +36,33 @@ func (h *Handler) ShortenBatch(ctx context.Context, req *pb.ShortenBatchRequest)
 			})
 			continue
 		}
+		options, err := linkOptionsFromProto(item.GetOptions())
+		if err != nil {
+			badreq := invalidOptions
+			results = append(results, &pb.URLPair{
+				CorrelationId: item.CorrelationId,
+				ShortUrl:      &badreq,
+			})
+			continue
+		}
+		if h.URLHandler.Blocked(item.GetOriginalUrl(), options) {
+			forbidden := blockedDomain
+			results = append(results, &pb.URLPair{
+				CorrelationId: item.CorrelationId,
+				ShortUrl:      &forbidden,
+			})
+			continue
+		}
+		options.UTM = options.UTM.WithDefaults(template)
 		idLen := 8
-		id := utils.GenerateID(item.GetOriginalUrl(), idLen)
+		id := httph.LinkID(item.GetOriginalUrl(), options.UTM, idLen)
 
 		record := models.URLRecord{
-			URL:    item.GetOriginalUrl(),
-			ID:     id,
-			UserID: req.GetUserId(),
+			URL:         item.GetOriginalUrl(),
+			ID:          id,
+			UserID:      req.GetUserId(),
+			ClicksLeft:  options.MaxClicks,
+			LinkOptions: options,
 		}
 		records = append(records, record)
 
@@ -51,6 +78,9 @@ func (h *Handler) ShortenBatch(ctx context.Context, req *pb.ShortenBatchRequest)
 		h.URLHandler.Logger.Error("failed to store batch", "error", err.Error())
 		return nil, status.Error(codes.Internal, "failed to store URLs")
 	}
+	if len(records) > 0 {
+		h.audit(ctx, req.GetUserId(), audit.ActionCreateLinks, httph.LinkIDs(records), nil)
+	}
 
 	return &pb.ShortenBatchResponse{
 		Results: results,
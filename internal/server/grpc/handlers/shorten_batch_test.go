@@ -8,6 +8,7 @@ import (
 	"github.com/apetsko/shortugo/internal/logging"
 	"github.com/apetsko/shortugo/internal/mocks"
 	httph "github.com/apetsko/shortugo/internal/server/http/handlers"
+	"github.com/apetsko/shortugo/internal/storages/shared"
 	pb "github.com/apetsko/shortugo/proto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -25,6 +26,8 @@ func TestShortenBatch_GRPC(t *testing.T) {
 	example := "http://example.com"
 	test := "http://test.com"
 	badreq := "Bad Request: Empty URL"
+	badOptions := invalidOptions
+	badMode := "frame"
 
 	logger, _ := logging.New(zapcore.DebugLevel)
 
@@ -71,6 +74,25 @@ func TestShortenBatch_GRPC(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:   "invalid link options",
+			userID: "user123",
+			mockStorageSetup: func(s *mocks.Storage) {
+				s.On("PutBatch", mock.Anything, mock.Anything).Return(nil)
+			},
+			request: &pb.ShortenBatchRequest{
+				UserId: &userID,
+				Urls: []*pb.URLPair{
+					{CorrelationId: &one, OriginalUrl: &example, Options: &pb.LinkOptions{RedirectMode: &badMode}},
+				},
+			},
+			expectedStatus: codes.OK,
+			expectedBody: &pb.ShortenBatchResponse{
+				Results: []*pb.URLPair{
+					{CorrelationId: &one, ShortUrl: &badOptions},
+				},
+			},
+		},
 		{
 			name:   "storage error",
 			userID: "user123",
@@ -104,6 +126,7 @@ func TestShortenBatch_GRPC(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockStorage := new(mocks.Storage)
+			mockStorage.On("GetUTMTemplate", mock.Anything, mock.Anything).Return(nil, shared.ErrNotFound).Maybe()
 			if tt.mockStorageSetup != nil {
 				tt.mockStorageSetup(mockStorage)
 			}
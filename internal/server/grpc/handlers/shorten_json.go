@@ -4,9 +4,10 @@ import (
 	"context"
 	"errors"
 
+	"github.com/apetsko/shortugo/internal/audit"
 	"github.com/apetsko/shortugo/internal/models"
+	httph "github.com/apetsko/shortugo/internal/server/http/handlers"
 	"github.com/apetsko/shortugo/internal/storages/shared"
-	"github.com/apetsko/shortugo/internal/utils"
 	pb "github.com/apetsko/shortugo/proto"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -21,14 +22,34 @@ func (h *Handler) ShortenJSON(ctx context.Context, req *pb.ShortenRequest) (*pb.
 	if req.GetOriginalUrl() == "" {
 		return nil, status.Error(codes.InvalidArgument, "original_url is required")
 	}
+	options, err := linkOptionsFromProto(req.GetOptions())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid link options")
+	}
+	if h.URLHandler.Blocked(req.GetOriginalUrl(), options) {
+		return nil, status.Error(codes.PermissionDenied, "blocked domain")
+	}
+	title, err := titleFromProto(req.GetTitle())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid title")
+	}
+	template, err := h.URLHandler.UTMTemplate(ctx, req.GetUserId())
+	if err != nil {
+		h.URLHandler.Logger.Error("failed to get UTM template", "error", err.Error())
+		return nil, status.Error(codes.Internal, "failed to get UTM template")
+	}
+	options.UTM = options.UTM.WithDefaults(template)
 	idLen := 8
-	id := utils.GenerateID(req.GetOriginalUrl(), idLen)
+	id := httph.LinkID(req.GetOriginalUrl(), options.UTM, idLen)
 	shortURL := h.URLHandler.BaseURL + "/" + id
 
 	record := models.URLRecord{
-		ID:     id,
-		URL:    req.GetOriginalUrl(),
-		UserID: req.GetUserId(),
+		ID:          id,
+		URL:         req.GetOriginalUrl(),
+		UserID:      req.GetUserId(),
+		Title:       title,
+		ClicksLeft:  options.MaxClicks,
+		LinkOptions: options,
 	}
 
 	// Check if it already exists
@@ -49,6 +70,7 @@ func (h *Handler) ShortenJSON(ctx context.Context, req *pb.ShortenRequest) (*pb.
 		h.URLHandler.Logger.Error("failed to store URL", "error", err.Error())
 		return nil, status.Error(codes.Internal, "failed to store URL")
 	}
+	h.audit(ctx, req.GetUserId(), audit.ActionCreateLinks, []string{id}, nil)
 
 	return &pb.ShortenResponse{
 		ShortUrl: &shortURL,
@@ -113,6 +113,7 @@ func TestShortenJSON_GRPC(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockStorage := new(mocks.Storage)
+			mockStorage.On("GetUTMTemplate", mock.Anything, mock.Anything).Return(nil, shared.ErrNotFound).Maybe()
 			if tt.mockStorageSetup != nil {
 				tt.mockStorageSetup(mockStorage)
 			}
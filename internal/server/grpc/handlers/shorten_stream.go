@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+
+	"github.com/apetsko/shortugo/internal/audit"
+	"github.com/apetsko/shortugo/internal/models"
+	httph "github.com/apetsko/shortugo/internal/server/http/handlers"
+	pb "github.com/apetsko/shortugo/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ShortenStream handles bidirectional streaming URL shortening.
+// The client sends original URLs one message at a time and receives the short URL
+// for each of them as soon as it is persisted, so arbitrarily large batches never
+// have to fit into a single message.
+//
+// Messages are processed strictly one by one: the next request is not read until
+// the previous response has been sent, so a client that stops reading also stops
+// the server from consuming its input.
+//
+// Request (stream):
+//   - user_id, correlation_id, original_url, options
+//
+// Response (stream):
+//   - URLPair with the correlation ID and the short URL, or an error text in short_url
+//     for an invalid item, mirroring ShortenBatch
+func (h *Handler) ShortenStream(stream grpc.BidiStreamingServer[pb.ShortenStreamRequest, pb.URLPair]) error {
+	ctx := stream.Context()
+	// UTM templates are looked up once per user for the lifetime of the stream.
+	templates := make(map[string]models.UTM)
+
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return status.FromContextError(ctxErr).Err()
+			}
+			return err
+		}
+
+		if req.GetUserId() == "" {
+			return status.Error(codes.InvalidArgument, "user_id is required")
+		}
+
+		template, ok := templates[req.GetUserId()]
+		if !ok {
+			template, err = h.URLHandler.UTMTemplate(ctx, req.GetUserId())
+			if err != nil {
+				h.URLHandler.Logger.Error("failed to get UTM template", "error", err.Error())
+				return status.Error(codes.Internal, "failed to get UTM template")
+			}
+			templates[req.GetUserId()] = template
+		}
+
+		result := &pb.URLPair{CorrelationId: req.CorrelationId}
+		options, optionsErr := linkOptionsFromProto(req.GetOptions())
+
+		switch {
+		case req.GetOriginalUrl() == "":
+			badreq := "Bad Request: Empty URL"
+			result.ShortUrl = &badreq
+		case optionsErr != nil:
+			badreq := invalidOptions
+			result.ShortUrl = &badreq
+		case h.URLHandler.Blocked(req.GetOriginalUrl(), options):
+			forbidden := blockedDomain
+			result.ShortUrl = &forbidden
+		default:
+			options.UTM = options.UTM.WithDefaults(template)
+			idLen := 8
+			id := httph.LinkID(req.GetOriginalUrl(), options.UTM, idLen)
+
+			record := models.URLRecord{
+				URL:         req.GetOriginalUrl(),
+				ID:          id,
+				UserID:      req.GetUserId(),
+				ClicksLeft:  options.MaxClicks,
+				LinkOptions: options,
+			}
+			if err := h.URLHandler.Storage.Put(ctx, record); err != nil {
+				if ctxErr := ctx.Err(); ctxErr != nil {
+					return status.FromContextError(ctxErr).Err()
+				}
+				h.URLHandler.Logger.Error("failed to store URL", "error", err.Error())
+				return status.Error(codes.Internal, "failed to store URL")
+			}
+			h.audit(ctx, req.GetUserId(), audit.ActionCreateLinks, []string{id}, nil)
+
+			shortURL := h.URLHandler.BaseURL + "/" + id
+			result.ShortUrl = &shortURL
+			result.OriginalUrl = req.OriginalUrl
+		}
+
+		if err := stream.Send(result); err != nil {
+			return err
+		}
+	}
+}
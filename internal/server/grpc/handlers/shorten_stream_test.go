@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/apetsko/shortugo/internal/logging"
+	"github.com/apetsko/shortugo/internal/mocks"
+	httph "github.com/apetsko/shortugo/internal/server/http/handlers"
+	"github.com/apetsko/shortugo/internal/storages/shared"
+	pb "github.com/apetsko/shortugo/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestShortenStream_GRPC(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+	userID := "user123"
+	empty := ""
+	one := "1"
+	two := "2"
+	three := "3"
+	example := "http://example.com"
+	test := "http://test.com"
+
+	tests := []struct {
+		mockStorageSetup func(mockStorage *mocks.Storage)
+		name             string
+		requests         []*pb.ShortenStreamRequest
+		expectedShort    []string
+		expectedStatus   codes.Code
+	}{
+		{
+			name: "successful stream",
+			mockStorageSetup: func(s *mocks.Storage) {
+				s.On("Put", mock.Anything, mock.Anything).Return(nil).Twice()
+			},
+			requests: []*pb.ShortenStreamRequest{
+				{UserId: &userID, CorrelationId: &one, OriginalUrl: &example},
+				{UserId: &userID, CorrelationId: &two, OriginalUrl: &empty},
+				{UserId: &userID, CorrelationId: &three, OriginalUrl: &test},
+			},
+			expectedStatus: codes.OK,
+			expectedShort:  []string{"http://short.ly/", "Bad Request: Empty URL", "http://short.ly/"},
+		},
+		{
+			name: "storage error",
+			mockStorageSetup: func(s *mocks.Storage) {
+				s.On("Put", mock.Anything, mock.Anything).Return(errors.New("fail"))
+			},
+			requests: []*pb.ShortenStreamRequest{
+				{UserId: &userID, CorrelationId: &one, OriginalUrl: &example},
+			},
+			expectedStatus: codes.Internal,
+		},
+		{
+			name:             "missing user id",
+			mockStorageSetup: func(s *mocks.Storage) {},
+			requests: []*pb.ShortenStreamRequest{
+				{UserId: &empty, CorrelationId: &one, OriginalUrl: &example},
+			},
+			expectedStatus: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := new(mocks.Storage)
+			mockStorage.On("GetUTMTemplate", mock.Anything, mock.Anything).Return(nil, shared.ErrNotFound).Maybe()
+			tt.mockStorageSetup(mockStorage)
+
+			urlHandler := &httph.URLHandler{
+				Storage: mockStorage,
+				Logger:  logger,
+				BaseURL: "http://short.ly",
+			}
+
+			conn, cleanup, err := startGRPCServer(NewHandler(urlHandler))
+			require.NoError(t, err)
+			defer cleanup()
+
+			client := pb.NewURLShortenerClient(conn)
+
+			stream, err := client.ShortenStream(context.Background())
+			require.NoError(t, err)
+
+			var got []*pb.URLPair
+			var recvErr error
+			for _, req := range tt.requests {
+				require.NoError(t, stream.Send(req))
+
+				// Each response arrives before the next request is sent.
+				pair, err := stream.Recv()
+				if err != nil {
+					recvErr = err
+					break
+				}
+				got = append(got, pair)
+			}
+
+			if recvErr == nil {
+				require.NoError(t, stream.CloseSend())
+				_, recvErr = stream.Recv()
+			}
+
+			if tt.expectedStatus != codes.OK {
+				assert.Equal(t, tt.expectedStatus, status.Code(recvErr))
+				return
+			}
+
+			assert.ErrorIs(t, recvErr, io.EOF)
+			require.Len(t, got, len(tt.expectedShort))
+			for i, pair := range got {
+				assert.Equal(t, tt.requests[i].GetCorrelationId(), pair.GetCorrelationId())
+				assert.Contains(t, pair.GetShortUrl(), tt.expectedShort[i])
+			}
+			mockStorage.AssertExpectations(t)
+		})
+	}
+}
+
+func TestShortenStream_GRPC_ClientCancel(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+
+	mockStorage := new(mocks.Storage)
+	mockStorage.On("GetUTMTemplate", mock.Anything, mock.Anything).Return(nil, shared.ErrNotFound).Maybe()
+	mockStorage.On("Put", mock.Anything, mock.Anything).Return(nil)
+
+	urlHandler := &httph.URLHandler{
+		Storage: mockStorage,
+		Logger:  logger,
+		BaseURL: "http://short.ly",
+	}
+
+	conn, cleanup, err := startGRPCServer(NewHandler(urlHandler))
+	require.NoError(t, err)
+	defer cleanup()
+
+	client := pb.NewURLShortenerClient(conn)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := client.ShortenStream(ctx)
+	require.NoError(t, err)
+
+	userID := "user123"
+	one := "1"
+	example := "http://example.com"
+	require.NoError(t, stream.Send(&pb.ShortenStreamRequest{UserId: &userID, CorrelationId: &one, OriginalUrl: &example}))
+
+	_, err = stream.Recv()
+	require.NoError(t, err)
+
+	cancel()
+
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Canceled, status.Code(err))
+	mockStorage.AssertNumberOfCalls(t, "Put", 1)
+}
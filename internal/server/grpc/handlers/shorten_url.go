@@ -3,11 +3,11 @@ package handlers
 import (
 	"context"
 	"errors"
-	"fmt"
 
+	"github.com/apetsko/shortugo/internal/audit"
 	"github.com/apetsko/shortugo/internal/models"
+	httph "github.com/apetsko/shortugo/internal/server/http/handlers"
 	"github.com/apetsko/shortugo/internal/storages/shared"
-	"github.com/apetsko/shortugo/internal/utils"
 	pb "github.com/apetsko/shortugo/proto"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -22,19 +22,39 @@ func (h *Handler) Shorten(ctx context.Context, req *pb.ShortenRequest) (*pb.Shor
 	if req.GetOriginalUrl() == "" {
 		return nil, status.Error(codes.InvalidArgument, "original_url is required")
 	}
+	options, err := linkOptionsFromProto(req.GetOptions())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid link options")
+	}
+	if h.URLHandler.Blocked(req.GetOriginalUrl(), options) {
+		return nil, status.Error(codes.PermissionDenied, "blocked domain")
+	}
+	title, err := titleFromProto(req.GetTitle())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid title")
+	}
+	template, err := h.URLHandler.UTMTemplate(ctx, req.GetUserId())
+	if err != nil {
+		h.URLHandler.Logger.Error("failed to get UTM template", "error", err.Error())
+		return nil, status.Error(codes.Internal, "failed to get UTM template")
+	}
+	options.UTM = options.UTM.WithDefaults(template)
 	idLen := 8
-	id := utils.GenerateID(req.GetOriginalUrl(), idLen)
+	id := httph.LinkID(req.GetOriginalUrl(), options.UTM, idLen)
 	record := models.URLRecord{
-		ID:     id,
-		URL:    req.GetOriginalUrl(),
-		UserID: req.GetUserId(),
+		ID:          id,
+		URL:         req.GetOriginalUrl(),
+		UserID:      req.GetUserId(),
+		Title:       title,
+		ClicksLeft:  options.MaxClicks,
+		LinkOptions: options,
 	}
 	shortURL := h.URLHandler.BaseURL + "/" + id
 
 	existing, err := h.URLHandler.Storage.Get(ctx, id)
 	if err == nil && existing != "" {
 		// Already exists
-		fmt.Println("Already exists", existing)
+		h.URLHandler.Logger.Debug("URL already exists", "id", id)
 		return &pb.ShortenResponse{
 			ShortUrl: &shortURL,
 		}, status.Error(codes.AlreadyExists, "URL already exists")
@@ -49,6 +69,7 @@ func (h *Handler) Shorten(ctx context.Context, req *pb.ShortenRequest) (*pb.Shor
 		h.URLHandler.Logger.Error("Put failed", "error", err.Error())
 		return nil, status.Error(codes.Internal, "failed to store URL")
 	}
+	h.audit(ctx, req.GetUserId(), audit.ActionCreateLinks, []string{id}, nil)
 
 	return &pb.ShortenResponse{
 		ShortUrl: &shortURL,
@@ -3,11 +3,16 @@ package handlers
 import (
 	"context"
 	"errors"
+	"net/http"
+	"strings"
 	"testing"
 
+	"github.com/apetsko/shortugo/internal/auth"
 	"github.com/apetsko/shortugo/internal/logging"
 	"github.com/apetsko/shortugo/internal/mocks"
+	"github.com/apetsko/shortugo/internal/models"
 	httph "github.com/apetsko/shortugo/internal/server/http/handlers"
+	"github.com/apetsko/shortugo/internal/storages/inmem"
 	"github.com/apetsko/shortugo/internal/storages/shared"
 	"github.com/apetsko/shortugo/internal/utils"
 	pb "github.com/apetsko/shortugo/proto"
@@ -17,6 +22,7 @@ import (
 	"go.uber.org/zap/zapcore"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 func TestShorten_GRPC(t *testing.T) {
@@ -27,6 +33,20 @@ func TestShorten_GRPC(t *testing.T) {
 
 	id := utils.GenerateID(example, 8)
 	shortURL := baseURL + "/" + id
+	// The tags of the link with the template applied pick the suffix of its ID
+	campaign := models.UTM{Source: "ads", Medium: "email"}
+	campaignID := httph.LinkID(example, campaign, 8)
+
+	permanent := int32(http.StatusMovedPermanently)
+	seeOther := int32(http.StatusSeeOther)
+	html := models.RedirectModeHTML
+	appendQuery := models.QueryPolicyAppend
+	unknownPolicy := "merge"
+	forward := true
+	ads := "ads"
+	password := "s3cret"
+	title := " Example site "
+	longTitle := strings.Repeat("t", 257)
 
 	tests := []struct {
 		mockStorageSetup func(s *mocks.Storage)
@@ -50,6 +70,34 @@ func TestShorten_GRPC(t *testing.T) {
 			expectedCode:  codes.OK,
 			expectedShort: shortURL,
 		},
+		{
+			name:   "shortening with title",
+			userID: "user123",
+			mockStorageSetup: func(s *mocks.Storage) {
+				s.On("Get", mock.Anything, id).Return("", shared.ErrNotFound)
+				s.On("Put", mock.Anything, mock.MatchedBy(func(r models.URLRecord) bool {
+					return r.Title == "Example site"
+				})).Return(nil)
+			},
+			req: &pb.ShortenRequest{
+				UserId:      &userID,
+				OriginalUrl: &example,
+				Title:       &title,
+			},
+			expectedCode:  codes.OK,
+			expectedShort: shortURL,
+		},
+		{
+			name:             "title too long",
+			userID:           "user123",
+			mockStorageSetup: func(s *mocks.Storage) {},
+			req: &pb.ShortenRequest{
+				UserId:      &userID,
+				OriginalUrl: &example,
+				Title:       &longTitle,
+			},
+			expectedCode: codes.InvalidArgument,
+		},
 		{
 			name:   "duplicate URL",
 			userID: "user123",
@@ -63,6 +111,106 @@ func TestShorten_GRPC(t *testing.T) {
 			expectedCode:  codes.AlreadyExists,
 			expectedShort: shortURL,
 		},
+		{
+			name:   "shortening with link options",
+			userID: "user123",
+			mockStorageSetup: func(s *mocks.Storage) {
+				s.On("Get", mock.Anything, id).Return("", shared.ErrNotFound)
+				s.On("Put", mock.Anything, models.URLRecord{
+					ID:          id,
+					URL:         example,
+					UserID:      userID,
+					LinkOptions: models.LinkOptions{RedirectType: http.StatusMovedPermanently, RedirectMode: html},
+				}).Return(nil)
+			},
+			req: &pb.ShortenRequest{
+				UserId:      &userID,
+				OriginalUrl: &example,
+				Options:     &pb.LinkOptions{RedirectType: &permanent, RedirectMode: &html},
+			},
+			expectedCode:  codes.OK,
+			expectedShort: shortURL,
+		},
+		{
+			name:   "shortening with passthrough options",
+			userID: "user123",
+			mockStorageSetup: func(s *mocks.Storage) {
+				s.On("Get", mock.Anything, id).Return("", shared.ErrNotFound)
+				s.On("Put", mock.Anything, models.URLRecord{
+					ID:          id,
+					URL:         example,
+					UserID:      userID,
+					LinkOptions: models.LinkOptions{QueryPolicy: appendQuery, ForwardPath: true},
+				}).Return(nil)
+			},
+			req: &pb.ShortenRequest{
+				UserId:      &userID,
+				OriginalUrl: &example,
+				Options:     &pb.LinkOptions{QueryPolicy: &appendQuery, ForwardPath: &forward},
+			},
+			expectedCode:  codes.OK,
+			expectedShort: shortURL,
+		},
+		{
+			name:   "shortening with UTM template",
+			userID: "user123",
+			mockStorageSetup: func(s *mocks.Storage) {
+				s.On("GetUTMTemplate", mock.Anything, userID).Return(&models.UTM{Source: "newsletter", Medium: "email"}, nil)
+				s.On("Get", mock.Anything, campaignID).Return("", shared.ErrNotFound)
+				s.On("Put", mock.Anything, models.URLRecord{
+					ID:          campaignID,
+					URL:         example,
+					UserID:      userID,
+					LinkOptions: models.LinkOptions{UTM: campaign},
+				}).Return(nil)
+			},
+			req: &pb.ShortenRequest{
+				UserId:      &userID,
+				OriginalUrl: &example,
+				Options:     &pb.LinkOptions{Utm: &pb.UTM{Source: &ads}},
+			},
+			expectedCode:  codes.OK,
+			expectedShort: baseURL + "/" + campaignID,
+		},
+		{
+			name:   "shortening with password",
+			userID: "user123",
+			mockStorageSetup: func(s *mocks.Storage) {
+				s.On("Get", mock.Anything, id).Return("", shared.ErrNotFound)
+				s.On("Put", mock.Anything, mock.MatchedBy(func(rec models.URLRecord) bool {
+					return auth.CheckPassword(rec.PasswordHash, password)
+				})).Return(nil)
+			},
+			req: &pb.ShortenRequest{
+				UserId:      &userID,
+				OriginalUrl: &example,
+				Options:     &pb.LinkOptions{Password: &password},
+			},
+			expectedCode:  codes.OK,
+			expectedShort: shortURL,
+		},
+		{
+			name:             "invalid query policy",
+			userID:           "user123",
+			mockStorageSetup: func(s *mocks.Storage) {},
+			req: &pb.ShortenRequest{
+				UserId:      &userID,
+				OriginalUrl: &example,
+				Options:     &pb.LinkOptions{QueryPolicy: &unknownPolicy},
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:             "invalid redirect type",
+			userID:           "user123",
+			mockStorageSetup: func(s *mocks.Storage) {},
+			req: &pb.ShortenRequest{
+				UserId:      &userID,
+				OriginalUrl: &example,
+				Options:     &pb.LinkOptions{RedirectType: &seeOther},
+			},
+			expectedCode: codes.InvalidArgument,
+		},
 		{
 			name:             "missing user ID",
 			userID:           "",
@@ -116,6 +264,7 @@ func TestShorten_GRPC(t *testing.T) {
 			if tt.mockStorageSetup != nil {
 				tt.mockStorageSetup(mockStorage)
 			}
+			mockStorage.On("GetUTMTemplate", mock.Anything, mock.Anything).Return(nil, shared.ErrNotFound).Maybe()
 
 			logger, _ := logging.New(zapcore.DebugLevel)
 			urlHandler := &httph.URLHandler{
@@ -144,3 +293,47 @@ func TestShorten_GRPC(t *testing.T) {
 		})
 	}
 }
+
+func TestShorten_GRPC_Campaigns(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+	h := &httph.URLHandler{Storage: inmem.New(), Logger: logger, BaseURL: "http://short.ly"}
+	conn, cleanup, err := startGRPCServer(NewHandler(h))
+	require.NoError(t, err)
+	defer cleanup()
+	client := pb.NewURLShortenerClient(conn)
+
+	ctx := context.Background()
+	userID, url := "user123", "https://example.com/sale"
+	shorten := func(campaign string) (string, error) {
+		req := &pb.ShortenRequest{UserId: &userID, OriginalUrl: &url}
+		if campaign != "" {
+			req.Options = &pb.LinkOptions{Utm: &pb.UTM{Source: proto.String("newsletter"), Campaign: &campaign}}
+		}
+		resp, err := client.Shorten(ctx, req)
+		return resp.GetShortUrl(), err
+	}
+
+	spring, err := shorten("spring")
+	require.NoError(t, err)
+	autumn, err := shorten("autumn")
+	require.NoError(t, err, "a second campaign on the same URL gets its own link")
+	assert.NotEqual(t, spring, autumn)
+
+	plain, err := shorten("")
+	require.NoError(t, err)
+	assert.NotContains(t, []string{spring, autumn}, plain)
+
+	// Every link of the URL shares the hash of the URL alone; the tags only pick the suffix
+	assert.Equal(t, "http://short.ly/"+utils.GenerateID(url, 8), plain)
+	assert.True(t, strings.HasPrefix(spring, plain+"-"), spring)
+	assert.True(t, strings.HasPrefix(autumn, plain+"-"), autumn)
+
+	_, err = shorten("spring")
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	for short, campaign := range map[string]string{spring: "spring", autumn: "autumn"} {
+		rec, err := h.Storage.GetRecord(ctx, strings.TrimPrefix(short, "http://short.ly/"))
+		require.NoError(t, err)
+		assert.Equal(t, campaign, rec.UTM.Campaign)
+	}
+}
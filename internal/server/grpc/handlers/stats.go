@@ -3,43 +3,28 @@ package handlers
 import (
 	"context"
 	"net"
-	"strings"
 
 	pb "github.com/apetsko/shortugo/proto"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
 // Stats handles the gRPC request to retrieve internal usage statistics.
-// Access is restricted to clients within a configured trusted subnet.
-// It validates the caller's IP (provided in the request) and,
-// if authorized, returns the total number of shortened URLs and unique users.
-//
-// Request:
-//   - pb.StatsRequest with IP address in string format
+// Access is restricted to clients connecting from a configured trusted subnet
+// or sending an admin API key. If authorized, it returns the total number of
+// shortened URLs and unique users.
 //
 // Response:
 //   - pb.StatsResponse with UrlCount and UserCount
 //
 // Errors:
-//   - PermissionDenied if TrustedSubnet is not configured, IP is invalid,
-//     or the IP is outside the allowed subnet
+//   - PermissionDenied if TrustedSubnet is not configured, the client address is unknown
+//     or outside the allowed subnet, or the API key is not an admin key
 //   - Internal if fetching stats from storage fails
 func (h *Handler) Stats(ctx context.Context, req *pb.StatsRequest) (*pb.StatsResponse, error) {
-	if h.URLHandler.TrustedSubnet == nil {
-		h.URLHandler.Logger.Error("Forbidden. TrustedSubnet is not configured")
-		return nil, status.Error(codes.PermissionDenied, "trusted subnet required")
-	}
-
-	ip := net.ParseIP(strings.TrimSpace(req.GetIp()))
-	if ip == nil {
-		h.URLHandler.Logger.Error("Forbidden: Invalid IP in request")
-		return nil, status.Error(codes.PermissionDenied, "invalid IP")
-	}
-
-	if !h.URLHandler.TrustedSubnet.Contains(ip) {
-		h.URLHandler.Logger.Errorf("Forbidden: IP %s not in trusted subnet", ip)
-		return nil, status.Error(codes.PermissionDenied, "IP not allowed")
+	if _, err := h.trusted(ctx); err != nil {
+		return nil, err
 	}
 
 	stats, err := h.URLHandler.Storage.Stats(ctx)
@@ -54,3 +39,31 @@ func (h *Handler) Stats(ctx context.Context, req *pb.StatsRequest) (*pb.StatsRes
 		UserCount: &usercount,
 	}, nil
 }
+
+// trusted checks that the caller of ctx may use Stats and the administration methods and returns
+// the actor audit entries name it by. The caller is trusted when it connects from the trusted subnet,
+// named by its address, or when it sends an admin API key like the Admin service does, named by the key.
+// The address is that of the connection, never one the caller sends.
+func (h *Handler) trusted(ctx context.Context) (string, error) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get("authorization")) > 0 {
+		return NewAdminHandler(h.URLHandler).admin(ctx)
+	}
+
+	trusted := h.URLHandler.TrustedSubnet.Load()
+	if trusted == nil {
+		h.URLHandler.Logger.Error("Forbidden. TrustedSubnet is not configured")
+		return "", status.Error(codes.PermissionDenied, "trusted subnet required")
+	}
+
+	addr := net.ParseIP(peerAddr(ctx))
+	if addr == nil {
+		h.URLHandler.Logger.Error("Forbidden: unknown client address")
+		return "", status.Error(codes.PermissionDenied, "client address unknown")
+	}
+
+	if !trusted.Contains(addr) {
+		h.URLHandler.Logger.Errorf("Forbidden: IP %s not in trusted subnet", addr)
+		return "", status.Error(codes.PermissionDenied, "IP not allowed")
+	}
+	return "ip:" + addr.String(), nil
+}
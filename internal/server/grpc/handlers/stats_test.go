@@ -15,6 +15,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
@@ -51,8 +52,7 @@ func TestStats_GRPC(t *testing.T) {
 			expectedCode:  codes.PermissionDenied,
 		},
 		{
-			name:          "invalid IP",
-			ip:            "invalid-ip",
+			name:          "unknown client address",
 			trustedSubnet: trustedNet,
 			expectedCode:  codes.PermissionDenied,
 		},
@@ -81,19 +81,22 @@ func TestStats_GRPC(t *testing.T) {
 			}
 
 			h := &httph.URLHandler{
-				Storage:       mockStorage,
-				Logger:        logger,
-				TrustedSubnet: tt.trustedSubnet,
+				Storage: mockStorage,
+				Logger:  logger,
 			}
+			h.TrustedSubnet.Store(tt.trustedSubnet)
 
-			grpcHandler := NewHandler(h)
-			conn, cleanup, err := startGRPCServer(grpcHandler)
+			var opts []grpc.ServerOption
+			if tt.ip != "" {
+				opts = append(opts, fromIP(tt.ip))
+			}
+			conn, cleanup, err := startGRPCServer(NewHandler(h), opts...)
 			require.NoError(t, err)
 			defer cleanup()
 
 			client := pb.NewURLShortenerClient(conn)
 
-			resp, err := client.Stats(context.Background(), &pb.StatsRequest{Ip: &tt.ip})
+			resp, err := client.Stats(context.Background(), &pb.StatsRequest{})
 
 			if tt.expectedCode == codes.OK {
 				require.NoError(t, err)
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"github.com/apetsko/shortugo/internal/models"
+	pb "github.com/apetsko/shortugo/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// StreamUserURLs streams all shortened URLs associated with the given user ID.
+// Unlike ListUserURLs it walks the storage cursor and sends one URLPair per record,
+// so the listing never has to fit into a single message. Send blocks while the
+// client's flow-control window is full, which in turn pauses reading from storage.
+//
+// Request:
+//   - user_id: string
+//   - tag, folder: optional, only stream the links with the tag and in the folder
+//
+// Response:
+//   - stream of URLPair (short + original URLs with their tags and folder)
+func (h *Handler) StreamUserURLs(req *pb.ListUserURLsRequest, stream grpc.ServerStreamingServer[pb.URLPair]) error {
+	if req.GetUserId() == "" {
+		return status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	ctx := stream.Context()
+
+	filter := listFilter(req)
+
+	var sent int
+	var sendErr error
+	err := h.URLHandler.Storage.ForEachLinkByUserID(ctx, h.URLHandler.BaseURL, req.GetUserId(), func(r models.URLRecord) error {
+		if !filter.Matches(r) {
+			return nil
+		}
+		if sendErr = stream.Send(userURLToProto(r)); sendErr != nil {
+			return sendErr
+		}
+		sent++
+		return nil
+	})
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return status.FromContextError(ctxErr).Err()
+		}
+		if sendErr != nil {
+			return sendErr
+		}
+		h.URLHandler.Logger.Error("storage error: " + err.Error())
+		return status.Error(codes.Internal, "failed to stream URLs")
+	}
+
+	if sent == 0 {
+		h.URLHandler.Logger.Error("no URLs for user: " + req.GetUserId())
+		return status.Error(codes.NotFound, "no URLs found for user")
+	}
+
+	return nil
+}
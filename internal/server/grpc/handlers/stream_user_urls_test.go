@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"testing"
+
+	"github.com/apetsko/shortugo/internal/logging"
+	"github.com/apetsko/shortugo/internal/mocks"
+	"github.com/apetsko/shortugo/internal/models"
+	httph "github.com/apetsko/shortugo/internal/server/http/handlers"
+	pb "github.com/apetsko/shortugo/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type forEachFunc = func(ctx context.Context, baseURL, userID string, fn func(models.URLRecord) error) error
+
+// walkRecords returns a ForEachLinkByUserID implementation that feeds rr to the callback.
+func walkRecords(rr []models.URLRecord) forEachFunc {
+	return func(ctx context.Context, baseURL, userID string, fn func(models.URLRecord) error) error {
+		for _, r := range rr {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			r.ID = baseURL + "/" + r.ID
+			if err := fn(r); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func TestStreamUserURLs_GRPC(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+
+	tests := []struct {
+		mockStorageSetup func(mockStorage *mocks.Storage)
+		name             string
+		reqUserID        string
+		expectedURLs     []string
+		expectedStatus   codes.Code
+	}{
+		{
+			name:      "successful stream",
+			reqUserID: "user123",
+			mockStorageSetup: func(mockStorage *mocks.Storage) {
+				mockStorage.On("ForEachLinkByUserID", mock.Anything, "http://short.ly", "user123", mock.Anything).
+					Return(walkRecords([]models.URLRecord{
+						{ID: "short1", URL: "http://example.com", UserID: "user123"},
+						{ID: "short2", URL: "http://test.com", UserID: "user123"},
+					}))
+			},
+			expectedStatus: codes.OK,
+			expectedURLs:   []string{"http://short.ly/short1", "http://short.ly/short2"},
+		},
+		{
+			name:      "no content",
+			reqUserID: "user123",
+			mockStorageSetup: func(mockStorage *mocks.Storage) {
+				mockStorage.On("ForEachLinkByUserID", mock.Anything, "http://short.ly", "user123", mock.Anything).
+					Return(nil)
+			},
+			expectedStatus: codes.NotFound,
+		},
+		{
+			name:      "internal error",
+			reqUserID: "user123",
+			mockStorageSetup: func(mockStorage *mocks.Storage) {
+				mockStorage.On("ForEachLinkByUserID", mock.Anything, "http://short.ly", "user123", mock.Anything).
+					Return(errors.New("db error"))
+			},
+			expectedStatus: codes.Internal,
+		},
+		{
+			name:             "missing user id",
+			reqUserID:        "",
+			mockStorageSetup: func(mockStorage *mocks.Storage) {},
+			expectedStatus:   codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := new(mocks.Storage)
+			tt.mockStorageSetup(mockStorage)
+
+			urlHandler := &httph.URLHandler{
+				Storage: mockStorage,
+				Logger:  logger,
+				BaseURL: "http://short.ly",
+			}
+
+			conn, cleanup, err := startGRPCServer(NewHandler(urlHandler))
+			require.NoError(t, err)
+			defer cleanup()
+
+			client := pb.NewURLShortenerClient(conn)
+
+			stream, err := client.StreamUserURLs(context.Background(), &pb.ListUserURLsRequest{
+				UserId: &tt.reqUserID,
+			})
+			require.NoError(t, err)
+
+			var got []string
+			for {
+				pair, err := stream.Recv()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if err != nil {
+					st, ok := status.FromError(err)
+					require.True(t, ok)
+					assert.Equal(t, tt.expectedStatus, st.Code())
+					return
+				}
+				got = append(got, pair.GetShortUrl())
+			}
+
+			assert.Equal(t, codes.OK, tt.expectedStatus)
+			assert.Equal(t, tt.expectedURLs, got)
+		})
+	}
+}
+
+func TestStreamUserURLs_GRPC_ClientCancel(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+
+	records := make([]models.URLRecord, 10000)
+	for i := range records {
+		records[i] = models.URLRecord{ID: strconv.Itoa(i), URL: "http://example.com/" + strconv.Itoa(i), UserID: "user123"}
+	}
+
+	walkDone := make(chan error, 1)
+	mockStorage := new(mocks.Storage)
+	mockStorage.On("ForEachLinkByUserID", mock.Anything, "http://short.ly", "user123", mock.Anything).
+		Return(func(ctx context.Context, baseURL, userID string, fn func(models.URLRecord) error) error {
+			err := walkRecords(records)(ctx, baseURL, userID, fn)
+			walkDone <- err
+			return err
+		})
+
+	urlHandler := &httph.URLHandler{
+		Storage: mockStorage,
+		Logger:  logger,
+		BaseURL: "http://short.ly",
+	}
+
+	conn, cleanup, err := startGRPCServer(NewHandler(urlHandler))
+	require.NoError(t, err)
+	defer cleanup()
+
+	client := pb.NewURLShortenerClient(conn)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	userID := "user123"
+	stream, err := client.StreamUserURLs(ctx, &pb.ListUserURLsRequest{UserId: &userID})
+	require.NoError(t, err)
+
+	_, err = stream.Recv()
+	require.NoError(t, err)
+	cancel()
+
+	// The storage walk must be aborted instead of running to completion.
+	walkErr := <-walkDone
+	require.Error(t, walkErr)
+
+	// Messages already buffered by the client may still be delivered before the cancellation.
+	for err == nil {
+		_, err = stream.Recv()
+	}
+	assert.Equal(t, codes.Canceled, status.Code(err))
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/apetsko/shortugo/internal/audit"
+	"github.com/apetsko/shortugo/internal/utils"
+	pb "github.com/apetsko/shortugo/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GetUTMTemplate returns the default UTM tags of a user.
+// A user without a template gets an empty one.
+//
+// Request:
+//   - user_id: string
+//
+// Response:
+//   - template: UTM tags
+func (h *Handler) GetUTMTemplate(ctx context.Context, req *pb.GetUTMTemplateRequest) (*pb.UTMTemplateResponse, error) {
+	if req.GetUserId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	t, err := h.URLHandler.UTMTemplate(ctx, req.GetUserId())
+	if err != nil {
+		h.URLHandler.Logger.Error("failed to get UTM template", "error", err.Error())
+		return nil, status.Error(codes.Internal, "failed to get UTM template")
+	}
+
+	return &pb.UTMTemplateResponse{Template: utmToProto(t)}, nil
+}
+
+// SetUTMTemplate replaces the default UTM tags of a user.
+// Links created afterwards get the tags they do not set themselves; an empty template removes it.
+//
+// Request:
+//   - user_id: string
+//   - template: UTM tags
+//
+// Response:
+//   - template: the stored UTM tags
+func (h *Handler) SetUTMTemplate(ctx context.Context, req *pb.SetUTMTemplateRequest) (*pb.UTMTemplateResponse, error) {
+	if req.GetUserId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	t := utmFromProto(req.GetTemplate())
+	if err := utils.ValidateStruct(t); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid UTM template")
+	}
+
+	if err := h.URLHandler.Storage.PutUTMTemplate(ctx, req.GetUserId(), t); err != nil {
+		h.URLHandler.Logger.Error("failed to store UTM template", "error", err.Error())
+		return nil, status.Error(codes.Internal, "failed to store UTM template")
+	}
+	h.audit(ctx, req.GetUserId(), audit.ActionUpdateUTM, []string{req.GetUserId()}, nil)
+
+	return &pb.UTMTemplateResponse{Template: utmToProto(t)}, nil
+}
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/apetsko/shortugo/internal/logging"
+	"github.com/apetsko/shortugo/internal/mocks"
+	"github.com/apetsko/shortugo/internal/models"
+	httph "github.com/apetsko/shortugo/internal/server/http/handlers"
+	"github.com/apetsko/shortugo/internal/storages/shared"
+	pb "github.com/apetsko/shortugo/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetUTMTemplate_GRPC(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+
+	tests := []struct {
+		mockStorageSetup func(mockStorage *mocks.Storage)
+		expected         models.UTM
+		name             string
+		userID           string
+		expectedStatus   codes.Code
+	}{
+		{
+			name:   "template set",
+			userID: "user123",
+			mockStorageSetup: func(mockStorage *mocks.Storage) {
+				mockStorage.On("GetUTMTemplate", mock.Anything, "user123").
+					Return(&models.UTM{Source: "newsletter", Term: "shoes"}, nil)
+			},
+			expectedStatus: codes.OK,
+			expected:       models.UTM{Source: "newsletter", Term: "shoes"},
+		},
+		{
+			name:   "no template",
+			userID: "user123",
+			mockStorageSetup: func(mockStorage *mocks.Storage) {
+				mockStorage.On("GetUTMTemplate", mock.Anything, "user123").Return(nil, shared.ErrNotFound)
+			},
+			expectedStatus: codes.OK,
+		},
+		{
+			name:             "missing user ID",
+			mockStorageSetup: func(mockStorage *mocks.Storage) {},
+			expectedStatus:   codes.InvalidArgument,
+		},
+		{
+			name:   "internal error",
+			userID: "user123",
+			mockStorageSetup: func(mockStorage *mocks.Storage) {
+				mockStorage.On("GetUTMTemplate", mock.Anything, "user123").Return(nil, errors.New("db fail"))
+			},
+			expectedStatus: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := new(mocks.Storage)
+			tt.mockStorageSetup(mockStorage)
+
+			conn, cleanup, err := startGRPCServer(NewHandler(&httph.URLHandler{Storage: mockStorage, Logger: logger}))
+			require.NoError(t, err)
+			defer cleanup()
+
+			client := pb.NewURLShortenerClient(conn)
+			resp, err := client.GetUTMTemplate(context.Background(), &pb.GetUTMTemplateRequest{UserId: &tt.userID})
+
+			if tt.expectedStatus != codes.OK {
+				require.Nil(t, resp)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				assert.Equal(t, tt.expectedStatus, st.Code())
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, utmFromProto(resp.GetTemplate()))
+			mockStorage.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSetUTMTemplate_GRPC(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+	long := strings.Repeat("a", 257)
+
+	tests := []struct {
+		mockStorageSetup func(mockStorage *mocks.Storage)
+		template         *pb.UTM
+		name             string
+		userID           string
+		expectedStatus   codes.Code
+	}{
+		{
+			name:     "template stored",
+			userID:   "user123",
+			template: utmToProto(models.UTM{Source: "newsletter", Medium: "email"}),
+			mockStorageSetup: func(mockStorage *mocks.Storage) {
+				mockStorage.On("PutUTMTemplate", mock.Anything, "user123",
+					models.UTM{Source: "newsletter", Medium: "email"}).Return(nil)
+			},
+			expectedStatus: codes.OK,
+		},
+		{
+			name:   "template removed",
+			userID: "user123",
+			mockStorageSetup: func(mockStorage *mocks.Storage) {
+				mockStorage.On("PutUTMTemplate", mock.Anything, "user123", models.UTM{}).Return(nil)
+			},
+			expectedStatus: codes.OK,
+		},
+		{
+			name:             "tag too long",
+			userID:           "user123",
+			template:         &pb.UTM{Campaign: &long},
+			mockStorageSetup: func(mockStorage *mocks.Storage) {},
+			expectedStatus:   codes.InvalidArgument,
+		},
+		{
+			name:             "missing user ID",
+			mockStorageSetup: func(mockStorage *mocks.Storage) {},
+			expectedStatus:   codes.InvalidArgument,
+		},
+		{
+			name:     "internal error",
+			userID:   "user123",
+			template: utmToProto(models.UTM{Source: "newsletter"}),
+			mockStorageSetup: func(mockStorage *mocks.Storage) {
+				mockStorage.On("PutUTMTemplate", mock.Anything, "user123", mock.Anything).Return(errors.New("db fail"))
+			},
+			expectedStatus: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := new(mocks.Storage)
+			tt.mockStorageSetup(mockStorage)
+
+			conn, cleanup, err := startGRPCServer(NewHandler(&httph.URLHandler{Storage: mockStorage, Logger: logger}))
+			require.NoError(t, err)
+			defer cleanup()
+
+			client := pb.NewURLShortenerClient(conn)
+			resp, err := client.SetUTMTemplate(context.Background(), &pb.SetUTMTemplateRequest{
+				UserId:   &tt.userID,
+				Template: tt.template,
+			})
+
+			if tt.expectedStatus != codes.OK {
+				require.Nil(t, resp)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				assert.Equal(t, tt.expectedStatus, st.Code())
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, utmFromProto(tt.template), utmFromProto(resp.GetTemplate()))
+			mockStorage.AssertExpectations(t)
+		})
+	}
+}
@@ -1,48 +1,103 @@
 package grpc
 
 import (
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/apetsko/shortugo/internal/certs"
+	"github.com/apetsko/shortugo/internal/health"
 	logger "github.com/apetsko/shortugo/internal/logging"
+	"github.com/apetsko/shortugo/internal/requestid"
 	grpch "github.com/apetsko/shortugo/internal/server/grpc/handlers"
 	httph "github.com/apetsko/shortugo/internal/server/http/handlers"
+	"github.com/apetsko/shortugo/internal/tracing"
 	pb "github.com/apetsko/shortugo/proto"
 	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/logging"
-	"go.uber.org/zap"
+	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/recovery"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	_ "google.golang.org/grpc/encoding/gzip"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
 )
 
 // RouterGRPC sets up and returns a new gRPC server instance.
-// It registers the URLShortener service implementation and enables server reflection
-// for easier testing and introspection (e.g., via grpcurl).
+// It registers the URLShortener and Admin service implementations behind the interceptor chain,
+// the grpc.health.v1.Health service when h has a health checker,
+// and enables server reflection for easier testing and introspection (e.g., via grpcurl).
 //
 // Parameters:
 //   - h: pointer to the shared HTTP URLHandler, reused for business logic.
+//   - l: logger of the calls and of recovered panics.
+//   - opts: further server options, such as TLS credentials.
 //
 // Returns:
 //   - *grpc.Server: the fully initialized gRPC server.
-func RouterGRPC(h *httph.URLHandler) *grpc.Server {
-	zaplogger := zap.NewExample()
-
-	opts := []logging.Option{
-		logging.WithLogOnEvents(logging.StartCall, logging.FinishCall),
-		// Add any other option (check functions starting with logging.With).
-	}
-
-	// You can now create a server with logging instrumentation that e.g. logs when the unary or stream call is started or finished.
-	_ = grpc.NewServer(
-		grpc.ChainUnaryInterceptor(
-			logging.UnaryServerInterceptor(logger.InterceptorLogger(zaplogger), opts...),
-			// Add any other interceptor you want.
-		),
-		grpc.ChainStreamInterceptor(
-			logging.StreamServerInterceptor(logger.InterceptorLogger(zaplogger), opts...),
-			// Add any other interceptor you want.
-		),
-	)
-	server := grpc.NewServer()
+func RouterGRPC(h *httph.URLHandler, l *logger.Logger, opts ...grpc.ServerOption) *grpc.Server {
+	var checker *health.Checker
+	if h != nil {
+		checker = h.Health
+	}
+
+	server := grpc.NewServer(append(opts, interceptors(l, checker)...)...)
 	pb.RegisterURLShortenerServer(server, grpch.NewHandler(h))
+	pb.RegisterAdminServer(server, grpch.NewAdminHandler(h))
+	if checker != nil {
+		checker.Register(server)
+	}
 	reflection.Register(server)
 
 	return server
 }
+
+// interceptors returns the interceptor chain of every call, outermost first:
+//   - tracing, so the span covers the whole call;
+//   - request IDs, taken from the x-request-id metadata or generated;
+//   - logging of each finished call with its code, duration, request ID and client service identity;
+//   - panic recovery, answering codes.Internal so the call is logged as failed;
+//   - with a health checker, codes.Unavailable until the storage is ready.
+func interceptors(l *logger.Logger, checker *health.Checker) []grpc.ServerOption {
+	logOpts := []logging.Option{
+		logging.WithLogOnEvents(logging.FinishCall),
+		logging.WithFieldsFromContext(func(ctx context.Context) logging.Fields {
+			fields := logging.Fields{"request_id", requestid.FromContext(ctx)}
+			if client := certs.IdentityFromContext(ctx); client != "" {
+				fields = append(fields, "client", client)
+			}
+			return fields
+		}),
+	}
+	recoveryOpts := []recovery.Option{
+		recovery.WithRecoveryHandlerContext(func(ctx context.Context, p any) error {
+			l.Error("gRPC handler panicked",
+				"panic", fmt.Sprint(p),
+				"request_id", requestid.FromContext(ctx),
+				"stack", string(debug.Stack()),
+			)
+			return status.Error(codes.Internal, "internal error")
+		}),
+	}
+
+	unary := []grpc.UnaryServerInterceptor{
+		tracing.UnaryServerInterceptor(),
+		requestid.UnaryServerInterceptor(),
+		logging.UnaryServerInterceptor(logger.InterceptorLogger(l.Access()), logOpts...),
+		recovery.UnaryServerInterceptor(recoveryOpts...),
+	}
+	stream := []grpc.StreamServerInterceptor{
+		tracing.StreamServerInterceptor(),
+		requestid.StreamServerInterceptor(),
+		logging.StreamServerInterceptor(logger.InterceptorLogger(l.Access()), logOpts...),
+		recovery.StreamServerInterceptor(recoveryOpts...),
+	}
+	if checker != nil {
+		unary = append(unary, checker.UnaryServerInterceptor())
+		stream = append(stream, checker.StreamServerInterceptor())
+	}
+
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	}
+}
@@ -1,14 +1,140 @@
 package grpc
 
 import (
+	"context"
+	"net"
 	"testing"
+	"time"
 
+	"github.com/apetsko/shortugo/internal/health"
+	"github.com/apetsko/shortugo/internal/logging"
+	"github.com/apetsko/shortugo/internal/mocks"
+	"github.com/apetsko/shortugo/internal/requestid"
+	"github.com/apetsko/shortugo/internal/server/http/handlers"
+	pb "github.com/apetsko/shortugo/proto"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
 )
 
 func TestRouterGRPC(t *testing.T) {
 	// Можно передать nil, потому что NewHandler допускает nil
-	srv := RouterGRPC(nil)
+	logger, _ := logging.New(zapcore.DebugLevel)
+	srv := RouterGRPC(nil, logger)
 
 	require.NotNil(t, srv)
 }
+
+func TestRouterGRPC_Interceptors(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger := &logging.Logger{SugaredLogger: zap.New(core).Sugar()}
+
+	mockStorage := new(mocks.Storage)
+	mockStorage.On("Ping").Return(nil).Once()
+	mockStorage.On("Ping").Panic("storage exploded")
+	h := handlers.NewURLHandler("http://localhost", mockStorage, logger, "secret", "127.0.0.0/8")
+
+	lis := bufconn.Listen(1024 * 1024)
+	srv := RouterGRPC(h, logger)
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	client := pb.NewURLShortenerClient(conn)
+
+	// The request ID of the client is kept and sent back
+	ctx := metadata.AppendToOutgoingContext(context.Background(), requestid.MetadataKey, "req-42")
+	var header metadata.MD
+	_, err = client.Ping(ctx, &pb.PingRequest{}, grpc.Header(&header))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"req-42"}, header.Get(requestid.MetadataKey))
+
+	finished := logs.FilterMessage("finished call").FilterField(zap.String("request_id", "req-42")).All()
+	require.Len(t, finished, 1)
+	assert.Equal(t, "OK", finished[0].ContextMap()["grpc.code"])
+
+	// A panic is answered with Internal and the server keeps serving
+	_, err = client.Ping(context.Background(), &pb.PingRequest{}, grpc.Header(&header))
+	require.Error(t, err)
+	assert.Equal(t, codes.Internal, status.Code(err))
+	require.Len(t, header.Get(requestid.MetadataKey), 1)
+	generated := header.Get(requestid.MetadataKey)[0]
+
+	panics := logs.FilterMessage("gRPC handler panicked").All()
+	require.Len(t, panics, 1)
+	assert.Equal(t, "storage exploded", panics[0].ContextMap()["panic"])
+	assert.Equal(t, generated, panics[0].ContextMap()["request_id"])
+
+	_, err = client.Ping(context.Background(), &pb.PingRequest{})
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
+
+func TestRouterGRPC_Health(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+	mockStorage := new(mocks.Storage)
+	mockStorage.On("Ping").Return(nil)
+	h := handlers.NewURLHandler("http://localhost", mockStorage, logger, "secret", "127.0.0.0/8")
+	h.Health = health.New(logger)
+
+	lis := bufconn.Listen(1024 * 1024)
+	srv := RouterGRPC(h, logger)
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	client := pb.NewURLShortenerClient(conn)
+	healthClient := healthpb.NewHealthClient(conn)
+
+	check := func(service string) healthpb.HealthCheckResponse_ServingStatus {
+		resp, err := healthClient.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		require.NoError(t, err)
+		return resp.GetStatus()
+	}
+	services := []string{"", pb.URLShortener_ServiceDesc.ServiceName, pb.Admin_ServiceDesc.ServiceName}
+
+	// Until the storage is ready the services are not serving and turn calls away
+	for _, service := range services {
+		assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, check(service), service)
+	}
+	_, err = client.Ping(context.Background(), &pb.PingRequest{})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	h.Health.Start(ctx, mockStorage.Ping, time.Hour)
+
+	for _, service := range services {
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, check(service), service)
+	}
+	_, err = client.Ping(context.Background(), &pb.PingRequest{})
+	assert.NoError(t, err)
+
+	// Shutdown began
+	h.Health.Drain()
+	for _, service := range services {
+		assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, check(service), service)
+	}
+}
@@ -2,23 +2,23 @@ package grpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 
+	"github.com/apetsko/shortugo/internal/certs"
 	"github.com/apetsko/shortugo/internal/config"
 	"github.com/apetsko/shortugo/internal/logging"
-	grpch "github.com/apetsko/shortugo/internal/server/grpc/handlers"
 	"github.com/apetsko/shortugo/internal/server/http/handlers"
-	pb "github.com/apetsko/shortugo/proto"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/reflection"
 )
 
 // Run starts a gRPC server on the address specified in cfg.GRPCHost.
-// If cfg.EnableHTTPS is true and TLSCertPath/TLSKeyPath are provided,
-// the server will use TLS credentials.
+// The server uses TLS when cfg.GRPCTLS reports it, with a certificate reloaded when its files change.
+// With cfg.GRPCClientCAPath clients must present a certificate signed by that CA, and calls carry the
+// service identity its CN maps to (see certs.IdentityFromContext). Calls go through the interceptor chain of RouterGRPC.
 //
 // The function performs graceful shutdown on context cancellation,
 // and logs startup and shutdown events asynchronously.
@@ -32,24 +32,43 @@ import (
 //   - *grpc.Server: the running gRPC server instance
 //   - error: non-nil if the server fails to start
 func Run(cfg *config.Config, h *handlers.URLHandler, logger *logging.Logger) (*grpc.Server, error) {
-	lis, err := net.Listen("tcp", cfg.GRPCHost)
-	if err != nil {
-		return nil, fmt.Errorf("listen error: %w", err)
+	enableTLS, certPath, keyPath := cfg.GRPCTLS()
+	if cfg.GRPCClientCAPath != "" && !enableTLS {
+		return nil, errors.New("client certificates need TLS on the gRPC server")
 	}
 
-	var opts []grpc.ServerOption
+	var (
+		opts    []grpc.ServerOption
+		manager *certs.Manager
+	)
 
-	if cfg.EnableHTTPS {
-		creds, err := credentials.NewServerTLSFromFile(cfg.TLSCertPath, cfg.TLSKeyPath)
-		if err != nil {
+	if enableTLS {
+		var err error
+		if manager, err = certs.NewManager(certPath, keyPath, logger); err != nil {
 			return nil, fmt.Errorf("failed to load TLS credentials: %w", err)
 		}
-		opts = append(opts, grpc.Creds(creds))
+		tlsConfig := manager.TLSConfig()
+
+		if cfg.GRPCClientCAPath != "" {
+			clientAuth, err := certs.NewClientAuth(cfg.GRPCClientCAPath, cfg.GRPCClientIdentities)
+			if err != nil {
+				return nil, err
+			}
+			clientAuth.Apply(tlsConfig)
+			opts = append(opts,
+				grpc.ChainUnaryInterceptor(clientAuth.UnaryServerInterceptor()),
+				grpc.ChainStreamInterceptor(clientAuth.StreamServerInterceptor()),
+			)
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+
+	lis, err := net.Listen("tcp", cfg.GRPCHost)
+	if err != nil {
+		return nil, fmt.Errorf("listen error: %w", err)
 	}
 
-	srv := grpc.NewServer(opts...)
-	reflection.Register(srv)
-	pb.RegisterURLShortenerServer(srv, grpch.NewHandler(h))
+	srv := RouterGRPC(h, logger, opts...)
 
 	g, ctx := errgroup.WithContext(context.Background())
 
@@ -60,7 +79,14 @@ func Run(cfg *config.Config, h *handlers.URLHandler, logger *logging.Logger) (*g
 	})
 
 	g.Go(func() error {
-		logger.Info(fmt.Sprintf("Starting gRPC server at %s, TLS: %t", cfg.GRPCHost, cfg.EnableHTTPS))
+		logger.Info(fmt.Sprintf("Starting gRPC server at %s, TLS: %t, client certificates: %t", cfg.GRPCHost, enableTLS, cfg.GRPCClientCAPath != ""))
+		if manager == nil {
+			return srv.Serve(lis)
+		}
+
+		watchCtx, stopWatch := context.WithCancel(context.Background())
+		defer stopWatch()
+		go manager.Watch(watchCtx, cfg.TLSReloadInterval)
 		return srv.Serve(lis)
 	})
 
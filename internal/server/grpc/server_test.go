@@ -2,6 +2,7 @@ package grpc
 
 import (
 	"context"
+	"crypto/tls"
 	"testing"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap/zapcore"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
@@ -78,3 +80,47 @@ func TestGRPCServer_TLSConfigError(t *testing.T) {
 	assert.Nil(t, srv)
 	require.ErrorContains(t, err, "failed to load TLS credentials")
 }
+
+func TestGRPCServer_ClientCAWithoutTLS(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+	cfg := &config.Config{
+		GRPCHost:         "127.0.0.1:0",
+		GRPCClientCAPath: "../../../certs/cert.crt",
+	}
+
+	srv, err := Run(cfg, handlers.NewURLHandler("http://localhost", new(mocks.Storage), logger, "secret", "127.0.0.0/8"), logger)
+	assert.Nil(t, srv)
+	assert.EqualError(t, err, "client certificates need TLS on the gRPC server")
+}
+
+func TestGRPCServer_TLS(t *testing.T) {
+	mockStorage := new(mocks.Storage)
+	mockStorage.On("Ping").Return(nil)
+	logger, _ := logging.New(zapcore.DebugLevel)
+
+	addr := "127.0.0.1:19093"
+	cfg := &config.Config{
+		GRPCHost:        addr,
+		GRPCEnableTLS:   true,
+		GRPCTLSCertPath: "../../../certs/cert.crt",
+		GRPCTLSKeyPath:  "../../../certs/cert.key",
+	}
+
+	srv, err := Run(cfg, handlers.NewURLHandler("http://localhost", mockStorage, logger, "secret", "127.0.0.0/8"), logger)
+	require.NoError(t, err)
+	defer srv.GracefulStop()
+
+	conn, err := grpc.NewClient("passthrough:///"+addr,
+		grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{InsecureSkipVerify: true})), //nolint:gosec
+	)
+	require.NoError(t, err)
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	resp, err := pb.NewURLShortenerClient(conn).Ping(ctx, &pb.PingRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "OK", resp.GetStatus())
+}
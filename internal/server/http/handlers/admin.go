@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/apetsko/shortugo/internal/audit"
+	"github.com/apetsko/shortugo/internal/auth"
+	"github.com/apetsko/shortugo/internal/models"
+	"github.com/apetsko/shortugo/internal/storages/shared"
+	"github.com/apetsko/shortugo/internal/utils"
+)
+
+// adminActorKey is the context key of the admin a request to the admin API comes from.
+type adminActorKey struct{}
+
+// disableRequest is the optional body of AdminDisableLink.
+type disableRequest struct {
+	Reason string `json:"reason" validate:"max=512"`
+}
+
+// AdminOnly lets requests from admins through to next and answers the others with 403 Forbidden.
+// Admins authenticate with an admin API key in an "Authorization: Bearer <key>" header,
+// or with the cookie of a user configured as an admin.
+func (h *URLHandler) AdminOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actor, ok := "", false
+		if token := auth.BearerToken(r.Header.Get("Authorization")); token != "" {
+			actor, ok = h.Admins.Key(token)
+		} else if userID, err := h.Auth.CookieGetUserID(r, h.Secret); err == nil && h.Admins.User(userID) {
+			actor, ok = userID, true
+		}
+
+		if !ok {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), adminActorKey{}, actor)))
+	})
+}
+
+// auditAdmin records an admin action of the request r on target.
+func (h *URLHandler) auditAdmin(r *http.Request, action, target string, details map[string]string) {
+	actor, _ := r.Context().Value(adminActorKey{}).(string)
+	h.audit(r, actor, action, []string{target}, details)
+}
+
+// AdminLink converts a record to a link of the admin API.
+func (h *URLHandler) AdminLink(r models.URLRecord) models.AdminLink {
+	return models.AdminLink{
+		CreatedAt:   r.CreatedAt,
+		ID:          r.ID,
+		ShortURL:    h.BaseURL + "/" + r.ID,
+		OriginalURL: r.URL,
+		UserID:      r.UserID,
+		Title:       r.Title,
+		Deleted:     r.Deleted,
+		Disabled:    r.Disabled,
+		Exhausted:   r.Exhausted(),
+	}
+}
+
+// AdminGetLink returns any link with its owner and state.
+//
+// Request:
+//   - Method: GET
+//   - URL: /api/admin/urls/{id}
+//
+// Response:
+//   - 200 OK: {"id": "abc123", "short_url": "...", "original_url": "...", "user_id": "...", "created_at": "...",
+//     "deleted": false, "disabled": false, "exhausted": false}
+//   - 403 Forbidden: The client is not an admin.
+//   - 404 Not Found: The link does not exist.
+//   - 500 Internal Server Error: Server error.
+func (h *URLHandler) AdminGetLink(w http.ResponseWriter, r *http.Request) {
+	ID := strings.TrimPrefix(r.URL.Path, "/api/admin/urls/")
+
+	rec, err := h.Storage.LookupRecord(r.Context(), ID)
+	if err != nil {
+		h.writeLinkError(w, "Failed to look up link", err)
+		return
+	}
+
+	h.auditAdmin(r, audit.ActionLookupLink, ID, nil)
+	h.writeAdminJSON(w, h.AdminLink(*rec))
+}
+
+// AdminDisableLink stops the redirects of any link: it is answered with 410 Gone, like a deleted link,
+// until it is enabled again. The owner keeps seeing the link in their list.
+//
+// Request:
+//   - Method: POST
+//   - URL: /api/admin/urls/{id}/disable
+//   - Body (optional): {"reason": "phishing"}, recorded in the audit entry.
+//
+// Response:
+//   - 200 OK: The link after the change.
+//   - 400 Bad Request: Invalid request body.
+//   - 403 Forbidden: The client is not an admin.
+//   - 404 Not Found: The link does not exist.
+//   - 500 Internal Server Error: Server error.
+func (h *URLHandler) AdminDisableLink(w http.ResponseWriter, r *http.Request) {
+	ID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/admin/urls/"), "/disable")
+
+	// Ensure the request body is closed after reading
+	defer func() {
+		if err := r.Body.Close(); err != nil {
+			h.Logger.Error("Failed to close request body", "error", err.Error())
+		}
+	}()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	var req disableRequest
+	if len(bytes.TrimSpace(body)) > 0 {
+		if err = json.Unmarshal(body, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err = utils.ValidateStruct(req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	var details map[string]string
+	if req.Reason != "" {
+		details = map[string]string{"reason": req.Reason}
+	}
+	h.setLinkDisabled(w, r, ID, true, audit.ActionDisableLink, details)
+}
+
+// AdminEnableLink lets a disabled link redirect again.
+//
+// Request:
+//   - Method: POST
+//   - URL: /api/admin/urls/{id}/enable
+//
+// Response:
+//   - 200 OK: The link after the change.
+//   - 403 Forbidden: The client is not an admin.
+//   - 404 Not Found: The link does not exist.
+//   - 500 Internal Server Error: Server error.
+func (h *URLHandler) AdminEnableLink(w http.ResponseWriter, r *http.Request) {
+	ID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/admin/urls/"), "/enable")
+	h.setLinkDisabled(w, r, ID, false, audit.ActionEnableLink, nil)
+}
+
+// setLinkDisabled disables or enables the link ID, records the action and answers with the link.
+func (h *URLHandler) setLinkDisabled(w http.ResponseWriter, r *http.Request, ID string, disabled bool, action string, details map[string]string) {
+	ctx := r.Context()
+	if err := h.Storage.SetLinkDisabled(ctx, ID, disabled); err != nil {
+		h.writeLinkError(w, "Failed to change link", err)
+		return
+	}
+	h.auditAdmin(r, action, ID, details)
+
+	rec, err := h.Storage.LookupRecord(ctx, ID)
+	if err != nil {
+		h.writeLinkError(w, "Failed to look up link", err)
+		return
+	}
+	h.writeAdminJSON(w, h.AdminLink(*rec))
+}
+
+// AdminListUserLinks lists the links of any user, with their state.
+//
+// Request:
+//   - Method: GET
+//   - URL: /api/admin/users/{user_id}/urls
+//
+// Response:
+//   - 200 OK: A JSON array of links as returned by AdminGetLink; empty for a user without links.
+//   - 403 Forbidden: The client is not an admin.
+//   - 500 Internal Server Error: Server error.
+func (h *URLHandler) AdminListUserLinks(w http.ResponseWriter, r *http.Request) {
+	userID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/admin/users/"), "/urls")
+
+	links, err := h.AdminUserLinks(r.Context(), userID)
+	if err != nil {
+		h.Logger.Error("Failed to list user links", "error", err.Error())
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	h.auditAdmin(r, audit.ActionListUserLinks, userID, nil)
+	h.writeAdminJSON(w, links)
+}
+
+// AdminUserLinks returns the links of a user that are not deleted, as links of the admin API.
+func (h *URLHandler) AdminUserLinks(ctx context.Context, userID string) ([]models.AdminLink, error) {
+	// Listed IDs come prefixed with the base URL passed in, so none is
+	records, err := h.Storage.ListLinksByUserID(ctx, "", userID)
+	if err != nil && !errors.Is(err, shared.ErrNotFound) {
+		return nil, err
+	}
+
+	links := make([]models.AdminLink, 0, len(records))
+	for _, rec := range records {
+		rec.ID = strings.TrimPrefix(rec.ID, "/")
+		links = append(links, h.AdminLink(rec))
+	}
+	return links, nil
+}
+
+// writeAdminJSON writes v as the JSON response of the admin endpoints.
+func (h *URLHandler) writeAdminJSON(w http.ResponseWriter, v any) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(v); err != nil {
+		h.Logger.Error("Error marshaling admin response", "error", err.Error())
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if _, err := buf.WriteTo(w); err != nil {
+		h.Logger.Error(err.Error())
+	}
+}
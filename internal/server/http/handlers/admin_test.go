@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/apetsko/shortugo/internal/audit"
+	"github.com/apetsko/shortugo/internal/auth"
+	"github.com/apetsko/shortugo/internal/logging"
+	"github.com/apetsko/shortugo/internal/mocks"
+	"github.com/apetsko/shortugo/internal/models"
+	"github.com/apetsko/shortugo/internal/storages/inmem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// auditRecorder keeps the recorded audit entries.
+type auditRecorder struct {
+	entries []audit.Entry
+}
+
+func (r *auditRecorder) Record(_ context.Context, e audit.Entry) error {
+	r.entries = append(r.entries, e)
+	return nil
+}
+
+// newAdminHandler returns a handler whose cookie user is userID, with admin1 as admin user
+// and "admin-key" as admin API key.
+func newAdminHandler(t *testing.T, storage Storage, userID string) (*URLHandler, *auditRecorder) {
+	t.Helper()
+
+	logger, _ := logging.New(zapcore.DebugLevel)
+	mockAuth := new(mocks.Authenticator)
+	if userID == "" {
+		mockAuth.On("CookieGetUserID", mock.Anything, mock.Anything).Return("", http.ErrNoCookie)
+	} else {
+		mockAuth.On("CookieGetUserID", mock.Anything, mock.Anything).Return(userID, nil)
+	}
+
+	recorder := new(auditRecorder)
+	return &URLHandler{
+		Auth:         mockAuth,
+		Storage:      storage,
+		Logger:       logger,
+		BaseURL:      "http://short.ly",
+		RedirectType: http.StatusTemporaryRedirect,
+		Admins:       auth.NewAdmins([]string{"admin1"}, []string{"admin-key"}),
+		AuditLog:     recorder,
+	}, recorder
+}
+
+// serveAdmin serves r through AdminOnly and handler.
+func serveAdmin(h *URLHandler, handler http.HandlerFunc, r *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.AdminOnly(handler).ServeHTTP(w, r)
+	return w
+}
+
+func TestAdminOnly(t *testing.T) {
+	storage := inmem.New()
+	require.NoError(t, storage.Put(context.Background(), models.URLRecord{ID: "abc123", URL: "https://example.com", UserID: "user456"}))
+
+	tests := []struct {
+		name           string
+		cookieUser     string
+		authorization  string
+		expectedStatus int
+		expectedActor  string
+	}{
+		{name: "admin user", cookieUser: "admin1", expectedStatus: http.StatusOK, expectedActor: "admin1"},
+		{name: "admin key", authorization: "Bearer admin-key", expectedStatus: http.StatusOK, expectedActor: "key:"},
+		{name: "other user", cookieUser: "user456", expectedStatus: http.StatusForbidden},
+		{name: "no credentials", expectedStatus: http.StatusForbidden},
+		{name: "wrong key", authorization: "Bearer guess", expectedStatus: http.StatusForbidden},
+		{name: "wrong key of an admin user", cookieUser: "admin1", authorization: "Bearer guess", expectedStatus: http.StatusForbidden},
+		{name: "other scheme", authorization: "Basic admin-key", cookieUser: "user456", expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, recorder := newAdminHandler(t, storage, tt.cookieUser)
+			r := httptest.NewRequest(http.MethodGet, "/api/admin/urls/abc123", nil)
+			if tt.authorization != "" {
+				r.Header.Set("Authorization", tt.authorization)
+			}
+
+			w := serveAdmin(h, h.AdminGetLink, r)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusOK {
+				assert.Empty(t, recorder.entries)
+				return
+			}
+			require.Len(t, recorder.entries, 1)
+			assert.True(t, strings.HasPrefix(recorder.entries[0].Actor, tt.expectedActor), recorder.entries[0].Actor)
+			assert.NotContains(t, recorder.entries[0].Actor, "admin-key")
+		})
+	}
+
+	t.Run("no admins configured", func(t *testing.T) {
+		h, _ := newAdminHandler(t, storage, "admin1")
+		h.Admins = nil
+		w := serveAdmin(h, h.AdminGetLink, httptest.NewRequest(http.MethodGet, "/api/admin/urls/abc123", nil))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestAdmin_DisableLink(t *testing.T) {
+	ctx := context.Background()
+	storage := inmem.New()
+	require.NoError(t, storage.PutBatch(ctx, []models.URLRecord{
+		{ID: "abc123", URL: "https://phishing.example", UserID: "user456"},
+		{ID: "def456", URL: "https://example.com", UserID: "user456"},
+	}))
+	h, recorder := newAdminHandler(t, storage, "admin1")
+
+	// Disable with a reason
+	r := httptest.NewRequest(http.MethodPost, "/api/admin/urls/abc123/disable", strings.NewReader(`{"reason":"phishing"}`))
+	w := serveAdmin(h, h.AdminDisableLink, r)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var link models.AdminLink
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &link))
+	assert.Equal(t, "abc123", link.ID)
+	assert.Equal(t, "http://short.ly/abc123", link.ShortURL)
+	assert.Equal(t, "user456", link.UserID)
+	assert.True(t, link.Disabled)
+
+	// The link is gone for visitors
+	w = httptest.NewRecorder()
+	h.ExpandURL(w, httptest.NewRequest(http.MethodGet, "/abc123", nil))
+	assert.Equal(t, http.StatusGone, w.Code)
+
+	// The owner's links are listed with their state
+	w = serveAdmin(h, h.AdminListUserLinks, httptest.NewRequest(http.MethodGet, "/api/admin/users/user456/urls", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var links []models.AdminLink
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &links))
+	disabled := map[string]bool{}
+	for _, l := range links {
+		disabled[l.ID] = l.Disabled
+	}
+	assert.Equal(t, map[string]bool{"abc123": true, "def456": false}, disabled)
+
+	// Enable again, without a body
+	w = serveAdmin(h, h.AdminEnableLink, httptest.NewRequest(http.MethodPost, "/api/admin/urls/abc123/enable", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &link))
+	assert.False(t, link.Disabled)
+
+	w = httptest.NewRecorder()
+	h.ExpandURL(w, httptest.NewRequest(http.MethodGet, "/abc123", nil))
+	assert.Equal(t, http.StatusTemporaryRedirect, w.Code)
+
+	// Every action is audited, in order
+	require.Len(t, recorder.entries, 3)
+	assert.Equal(t, audit.Entry{
+		Actor: "admin1", Remote: "192.0.2.1", Protocol: audit.ProtocolHTTP, Action: audit.ActionDisableLink,
+		Targets: []string{"abc123"}, Details: map[string]string{"reason": "phishing"},
+	}, recorder.entries[0])
+	assert.Equal(t, audit.ActionListUserLinks, recorder.entries[1].Action)
+	assert.Equal(t, []string{"user456"}, recorder.entries[1].Targets)
+	assert.Equal(t, audit.ActionEnableLink, recorder.entries[2].Action)
+}
+
+func TestAdmin_Errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		handler        func(h *URLHandler) http.HandlerFunc
+		target         string
+		body           string
+		storageErr     error
+		expectedStatus int
+	}{
+		{
+			name:           "lookup of an unknown link",
+			handler:        func(h *URLHandler) http.HandlerFunc { return h.AdminGetLink },
+			target:         "/api/admin/urls/unknown",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "disable of an unknown link",
+			handler:        func(h *URLHandler) http.HandlerFunc { return h.AdminDisableLink },
+			target:         "/api/admin/urls/unknown/disable",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "invalid body",
+			handler:        func(h *URLHandler) http.HandlerFunc { return h.AdminDisableLink },
+			target:         "/api/admin/urls/abc123/disable",
+			body:           `{"reason":`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "reason too long",
+			handler:        func(h *URLHandler) http.HandlerFunc { return h.AdminDisableLink },
+			target:         "/api/admin/urls/abc123/disable",
+			body:           `{"reason":"` + strings.Repeat("x", 513) + `"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "storage error",
+			handler:        func(h *URLHandler) http.HandlerFunc { return h.AdminListUserLinks },
+			target:         "/api/admin/users/user456/urls",
+			storageErr:     errors.New("database error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var storage Storage = inmem.New()
+			if tt.storageErr != nil {
+				mockStorage := new(mocks.Storage)
+				mockStorage.On("ListLinksByUserID", mock.Anything, mock.Anything, mock.Anything).Return(nil, tt.storageErr)
+				storage = mockStorage
+			}
+			h, recorder := newAdminHandler(t, storage, "admin1")
+
+			w := serveAdmin(h, tt.handler(h), httptest.NewRequest(http.MethodPost, tt.target, strings.NewReader(tt.body)))
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Empty(t, recorder.entries)
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/apetsko/shortugo/internal/audit"
+	"github.com/apetsko/shortugo/internal/models"
+)
+
+// RecordAudit records an action in the audit log. A failure to record is logged;
+// the action has already been taken.
+func (h *URLHandler) RecordAudit(ctx context.Context, e audit.Entry) {
+	recorder := h.AuditLog
+	if recorder == nil {
+		recorder = audit.NewLog(h.Logger)
+	}
+	if err := recorder.Record(ctx, e); err != nil {
+		h.Logger.Error("Failed to record audit entry", "action", e.Action, "actor", e.Actor, "targets", e.Targets, "error", err.Error())
+	}
+}
+
+// audit records an action of the request r taken by actor on targets.
+func (h *URLHandler) audit(r *http.Request, actor, action string, targets []string, details map[string]string) {
+	remote, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		remote = r.RemoteAddr
+	}
+
+	h.RecordAudit(r.Context(), audit.Entry{
+		Actor:    actor,
+		Remote:   remote,
+		Protocol: audit.ProtocolHTTP,
+		Action:   action,
+		Targets:  targets,
+		Details:  details,
+	})
+}
+
+// auditUpdate records the change of field of the link ID by the user userID.
+func (h *URLHandler) auditUpdate(r *http.Request, userID, ID, field string) {
+	h.audit(r, userID, audit.ActionUpdateLink, []string{ID}, map[string]string{"field": field})
+}
+
+// LinkIDs returns the IDs of records, the targets of an audit entry of their creation.
+func LinkIDs(records []models.URLRecord) []string {
+	ids := make([]string, 0, len(records))
+	for _, r := range records {
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
+
+// AdminAudit returns the audit log, newest entries first.
+//
+// Request:
+//   - Method: GET
+//   - URL: /api/admin/audit
+//   - Query parameters, all optional:
+//     actor, action, target, protocol: entries with this actor, action, target among others or protocol;
+//     since, until: RFC 3339 times bounding the entries, until excluded;
+//     limit: the number of entries, 100 by default and 1000 at most.
+//
+// Response:
+//   - 200 OK: [{"time": "...", "actor": "...", "remote": "...", "protocol": "http", "action": "link.create",
+//     "targets": ["abc123"], "details": {...}}]
+//   - 400 Bad Request: A time or the limit is invalid.
+//   - 403 Forbidden: The client is not an admin.
+//   - 500 Internal Server Error: Server error.
+//   - 501 Not Implemented: The audit log is only written to the application log and cannot be queried.
+func (h *URLHandler) AdminAudit(w http.ResponseWriter, r *http.Request) {
+	querier, ok := h.AuditLog.(audit.Querier)
+	if !ok {
+		http.Error(w, "Audit log cannot be queried", http.StatusNotImplemented)
+		return
+	}
+
+	filter, err := auditFilter(r)
+	if err != nil {
+		http.Error(w, "Bad Request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	entries, err := querier.Query(r.Context(), filter)
+	if err != nil {
+		h.Logger.Error("Failed to query audit log", "error", err.Error())
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	var details map[string]string
+	if r.URL.RawQuery != "" {
+		details = map[string]string{"query": r.URL.RawQuery}
+	}
+	actor, _ := r.Context().Value(adminActorKey{}).(string)
+	h.audit(r, actor, audit.ActionQueryAudit, nil, details)
+	h.writeAdminJSON(w, entries)
+}
+
+// auditFilter reads the filter of AdminAudit from the query of r.
+func auditFilter(r *http.Request) (audit.Filter, error) {
+	q := r.URL.Query()
+	f := audit.Filter{
+		Actor:    q.Get("actor"),
+		Action:   q.Get("action"),
+		Target:   q.Get("target"),
+		Protocol: q.Get("protocol"),
+	}
+
+	var err error
+	if s := q.Get("since"); s != "" {
+		if f.Since, err = time.Parse(time.RFC3339, s); err != nil {
+			return f, errors.New("since is not an RFC 3339 time")
+		}
+	}
+	if s := q.Get("until"); s != "" {
+		if f.Until, err = time.Parse(time.RFC3339, s); err != nil {
+			return f, errors.New("until is not an RFC 3339 time")
+		}
+	}
+	if s := q.Get("limit"); s != "" {
+		if f.Limit, err = strconv.Atoi(s); err != nil || f.Limit <= 0 || f.Limit > audit.MaxLimit {
+			return f, fmt.Errorf("limit is not a number from 1 to %d", audit.MaxLimit)
+		}
+	}
+	return f, nil
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/apetsko/shortugo/internal/audit"
+	"github.com/apetsko/shortugo/internal/models"
+	"github.com/apetsko/shortugo/internal/storages/inmem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAudit_Mutations(t *testing.T) {
+	storage := inmem.New()
+	h, recorder := newAdminHandler(t, storage, "user456")
+	h.ToDelete = make(chan models.BatchDeleteRequest, 1)
+
+	w := httptest.NewRecorder()
+	h.ShortenURL(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("https://example.com")))
+	require.Equal(t, http.StatusCreated, w.Code)
+	ID := strings.TrimPrefix(w.Body.String(), h.BaseURL+"/")
+
+	w = httptest.NewRecorder()
+	h.SetLinkFolder(w, httptest.NewRequest(http.MethodPut, "/api/user/urls/"+ID+"/folder", strings.NewReader(`{"folder":"work"}`)))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	h.DeleteUserURLs(w, httptest.NewRequest(http.MethodDelete, "/api/user/urls", strings.NewReader(`["`+ID+`"]`)))
+	require.Equal(t, http.StatusAccepted, w.Code)
+
+	// A failed change is not audited
+	w = httptest.NewRecorder()
+	h.SetLinkFolder(w, httptest.NewRequest(http.MethodPut, "/api/user/urls/unknown/folder", strings.NewReader(`{"folder":"work"}`)))
+	require.Equal(t, http.StatusNotFound, w.Code)
+
+	require.Len(t, recorder.entries, 3)
+	assert.Equal(t, audit.Entry{
+		Actor: "user456", Remote: "192.0.2.1", Protocol: audit.ProtocolHTTP, Action: audit.ActionCreateLinks, Targets: []string{ID},
+	}, recorder.entries[0])
+	assert.Equal(t, audit.ActionUpdateLink, recorder.entries[1].Action)
+	assert.Equal(t, map[string]string{"field": "folder"}, recorder.entries[1].Details)
+	assert.Equal(t, audit.ActionDeleteLinks, recorder.entries[2].Action)
+	assert.Equal(t, []string{ID}, recorder.entries[2].Targets)
+}
+
+func TestAdminAudit(t *testing.T) {
+	ctx := context.Background()
+	h, _ := newAdminHandler(t, inmem.New(), "admin1")
+
+	log, err := audit.OpenFile(filepath.Join(t.TempDir(), "audit.jsonl"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = log.Close() })
+	h.AuditLog = log
+
+	require.NoError(t, log.Record(ctx, audit.Entry{Actor: "user1", Protocol: audit.ProtocolHTTP, Action: audit.ActionCreateLinks, Targets: []string{"abc123"}}))
+	require.NoError(t, log.Record(ctx, audit.Entry{Actor: "user2", Protocol: audit.ProtocolGRPC, Action: audit.ActionDeleteLinks, Targets: []string{"def456"}}))
+
+	query := func(target string) []audit.Entry {
+		t.Helper()
+		w := serveAdmin(h, h.AdminAudit, httptest.NewRequest(http.MethodGet, target, nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		var entries []audit.Entry
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &entries))
+		return entries
+	}
+
+	entries := query("/api/admin/audit")
+	require.Len(t, entries, 2)
+	assert.Equal(t, "user2", entries[0].Actor)
+
+	entries = query("/api/admin/audit?target=abc123&since=2000-01-01T00:00:00Z")
+	require.Len(t, entries, 1)
+	assert.Equal(t, "user1", entries[0].Actor)
+
+	// Queries are audited too
+	entries = query("/api/admin/audit?action=audit.query&limit=1")
+	require.Len(t, entries, 1)
+	assert.Equal(t, "admin1", entries[0].Actor)
+	assert.Equal(t, map[string]string{"query": "target=abc123&since=2000-01-01T00:00:00Z"}, entries[0].Details)
+
+	for _, target := range []string{
+		"/api/admin/audit?since=yesterday",
+		"/api/admin/audit?until=2026-13-01T00:00:00Z",
+		"/api/admin/audit?limit=0",
+		"/api/admin/audit?limit=1001",
+	} {
+		w := serveAdmin(h, h.AdminAudit, httptest.NewRequest(http.MethodGet, target, nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, target)
+	}
+
+	t.Run("log only", func(t *testing.T) {
+		h, _ := newAdminHandler(t, inmem.New(), "admin1")
+		h.AuditLog = audit.NewLog(h.Logger)
+		w := serveAdmin(h, h.AdminAudit, httptest.NewRequest(http.MethodGet, "/api/admin/audit", nil))
+		assert.Equal(t, http.StatusNotImplemented, w.Code)
+	})
+
+	t.Run("not an admin", func(t *testing.T) {
+		h, _ := newAdminHandler(t, inmem.New(), "user456")
+		h.AuditLog = log
+		w := serveAdmin(h, h.AdminAudit, httptest.NewRequest(http.MethodGet, "/api/admin/audit", nil))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
@@ -0,0 +1,22 @@
+package handlers
+
+import "github.com/apetsko/shortugo/internal/models"
+
+// Blocked reports whether a link to url with the options o leads to a blocked domain,
+// by its URL, a routing rule or an A/B variant. Without a blocklist nothing is blocked.
+func (h *URLHandler) Blocked(url string, o models.LinkOptions) bool {
+	if h.Blocklist.BlockedURL(url) {
+		return true
+	}
+	for _, r := range o.Rules {
+		if h.Blocklist.BlockedURL(r.URL) {
+			return true
+		}
+	}
+	for _, v := range o.Variants {
+		if h.Blocklist.BlockedURL(v.URL) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/apetsko/shortugo/internal/blocklist"
+	"github.com/apetsko/shortugo/internal/models"
+	"github.com/apetsko/shortugo/internal/storages/inmem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockedHandler returns a handler blocking evil.com with a storage holding links created before the block.
+func blockedHandler(t *testing.T) *URLHandler {
+	t.Helper()
+
+	storage := inmem.New()
+	require.NoError(t, storage.PutBatch(context.Background(), []models.URLRecord{
+		{ID: "direct", URL: "https://www.evil.com/login", UserID: "user456"},
+		{ID: "variant", URL: "https://example.com", UserID: "user456", LinkOptions: models.LinkOptions{
+			Variants: []models.Variant{{Name: "a", URL: "https://example.com/a", Weight: 1}, {Name: "b", URL: "https://evil.com/b", Weight: 1}},
+		}},
+		{ID: "fine", URL: "https://example.com/fine", UserID: "user456"},
+	}))
+
+	h := newImportHandler(t, storage)
+	h.Blocklist = blocklist.New()
+	require.NoError(t, h.Blocklist.Block("evil.com"))
+	return h
+}
+
+func TestBlocked_Shorten(t *testing.T) {
+	h := blockedHandler(t)
+
+	t.Run("plain text", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.ShortenURL(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("https://evil.com/x")))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("JSON with a blocked rule", func(t *testing.T) {
+		body := `{"url":"https://example.com","rules":[{"platform":"ios","url":"https://app.evil.com"}]}`
+		w := httptest.NewRecorder()
+		h.ShortenJSON(w, httptest.NewRequest(http.MethodPost, "/api/shorten", strings.NewReader(body)))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("batch", func(t *testing.T) {
+		body := `[{"correlation_id":"1","original_url":"https://evil.com"},{"correlation_id":"2","original_url":"https://example.com/ok"}]`
+		w := httptest.NewRecorder()
+		h.ShortenBatchJSON(w, httptest.NewRequest(http.MethodPost, "/api/shorten/batch", strings.NewReader(body)))
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		var resp []models.BatchResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp, 2)
+		assert.Equal(t, "Forbidden: Blocked domain", resp[0].ShortURL)
+		assert.True(t, strings.HasPrefix(resp[1].ShortURL, "http://short.ly/"))
+	})
+}
+
+func TestBlocked_Redirect(t *testing.T) {
+	h := blockedHandler(t)
+
+	tests := []struct {
+		name           string
+		target         string
+		expectedStatus int
+	}{
+		{name: "blocked URL", target: "/direct", expectedStatus: http.StatusForbidden},
+		{name: "blocked variant", target: "/variant", expectedStatus: http.StatusForbidden},
+		{name: "other link", target: "/fine", expectedStatus: http.StatusTemporaryRedirect},
+		{name: "preview of blocked URL", target: "/direct+", expectedStatus: http.StatusForbidden},
+		{name: "preview of other link", target: "/fine+", expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h.RedirectType = http.StatusTemporaryRedirect
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if strings.HasSuffix(tt.target, "+") {
+				h.PreviewURL(w, r)
+			} else {
+				h.ExpandURL(w, r)
+			}
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
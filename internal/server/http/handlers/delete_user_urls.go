@@ -5,6 +5,7 @@ import (
 	"io"
 	"net/http"
 
+	"github.com/apetsko/shortugo/internal/audit"
 	"github.com/apetsko/shortugo/internal/models"
 )
 
@@ -55,6 +56,7 @@ func (h *URLHandler) DeleteUserURLs(w http.ResponseWriter, r *http.Request) {
 	go func() {
 		h.ToDelete <- models.BatchDeleteRequest{Ids: ids, UserID: userID}
 	}()
+	h.audit(r, userID, audit.ActionDeleteLinks, ids, nil)
 
 	// Respond with 202 Accepted and return the requested IDs
 	w.WriteHeader(http.StatusAccepted)
@@ -3,22 +3,50 @@ package handlers
 import (
 	"errors"
 	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/apetsko/shortugo/internal/storages/shared"
 )
 
 // ExpandURL handles requests for expanding a shortened URL.
-// It retrieves the original URL from the storage and redirects the client to it.
+// It retrieves the original URL from the storage and redirects the client to it
+// using the redirect type and mode of the link, or the server defaults.
+//
+// Links created with forward_path also accept /{id}/rest/of/path and append the rest to the destination path;
+// links created with a query_policy merge the request query into the destination query.
+// UTM tags of the link are added to the destination query on every redirect.
+//
+// Password-protected links answer GET with a password form and redirect the POSTed form
+// with 303 See Other when the password matches; failed attempts are rate-limited per client and link.
+//
+// Links with routing rules send the client to the destination of the first rule matching its platform,
+// preferred language and country, or to the link URL when none matches.
+//
+// Links with A/B variants split the visitors no rule matches between the variant URLs by weight.
+// The chosen variant is remembered in a cookie scoped to the link and its click counter grows on every redirect.
+//
+// Links created with max_clicks count every redirect and answer 410 Gone once the clicks are used up.
+// Showing the password form does not count.
+//
+// Links leading to a blocked domain, by their URL, a routing rule or a variant, answer 403 Forbidden.
+//
+// Links created with interstitial answer GET with a preview page of the destination, see PreviewURL;
+// its continue button POSTs back and is redirected with 303 See Other.
 func (h *URLHandler) ExpandURL(w http.ResponseWriter, r *http.Request) {
-	// Extract the ID from the URL path (remove the leading "/")
-	ID := strings.TrimPrefix(r.URL.Path, "/")
+	// Split the escaped path into the ID and the extra path following it
+	ID, extraPath, hasExtraPath := strings.Cut(strings.TrimPrefix(r.URL.EscapedPath(), "/"), "/")
+	ID, err := url.PathUnescape(ID)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
 
 	// Get the context from the request
 	ctx := r.Context()
 
-	// Retrieve the original URL from the storage using the ID
-	URL, err := h.Storage.Get(ctx, ID)
+	// Retrieve the URL record from the storage using the ID
+	rec, err := h.Storage.GetRecord(ctx, ID)
 	if err != nil {
 		// Handle the case where the URL is no longer available (gone)
 		if errors.Is(err, shared.ErrGone) {
@@ -40,16 +68,77 @@ func (h *URLHandler) ExpandURL(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Set the "Location" header for the redirect response
-	w.Header().Set("Location", URL)
-	// Add the "Content-Type" header for the response
-	w.Header().Add("Content-Type", "text/html")
+	// Links leading to a blocked domain are not followed, whatever destination the visitor would get
+	if h.Blocked(rec.URL, rec.LinkOptions) {
+		h.Logger.Info("Link leads to a blocked domain", "id", ID)
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	// Extra path segments are only served by links that forward them
+	if hasExtraPath && !rec.ForwardPath {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Password-protected links are only followed after the password form is submitted;
+	// other links do not accept form submissions
+	if rec.Protected() {
+		if !h.unlock(w, r, rec) {
+			return
+		}
+		// Answer the submission with 303, so the browser follows it with a GET
+		rec.RedirectType = http.StatusSeeOther
+	} else if r.Method == http.MethodPost {
+		// Only the continue button of an interstitial page submits to an open link
+		if !rec.Interstitial {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		rec.RedirectType = http.StatusSeeOther
+	}
+
+	// Pick the destination from the routing rules and the A/B variants of the link
+	destination, variant := h.destination(w, r, rec)
 
-	// Respond with a temporary redirect (HTTP 307) and the URL in the body
-	w.WriteHeader(http.StatusTemporaryRedirect)
-	_, err = w.Write([]byte(URL))
+	// Add the UTM tags and forward the extra path and the query string as allowed by the link
+	target, err := applyPassthrough(destination, extraPath, r.URL.RawQuery, rec.LinkOptions)
 	if err != nil {
-		// Log any error that occurs while writing the response
-		h.Logger.Error(err.Error())
+		h.Logger.Error("failed to build redirect target", "id", ID, "error", err.Error())
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	// Links with an interstitial page show where they lead and wait for the visitor to continue;
+	// showing the page neither uses up a click nor counts for a variant
+	if rec.Interstitial && r.Method == http.MethodGet {
+		h.preview(w, r, rec, target, r.URL.RequestURI())
+		return
+	}
+
+	// Links limited by max_clicks use up one redirect; the last one makes the link gone
+	if rec.MaxClicks > 0 {
+		if err = h.Storage.ConsumeClick(ctx, ID); err != nil {
+			switch {
+			case errors.Is(err, shared.ErrGone):
+				w.WriteHeader(http.StatusGone)
+			case errors.Is(err, shared.ErrNotFound):
+				w.WriteHeader(http.StatusNotFound)
+			default:
+				h.Logger.Error("failed to consume click", "id", ID, "error", err.Error())
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			return
+		}
 	}
+
+	// A failed counter update must not keep the visitor from the destination
+	if variant != "" {
+		if err = h.Storage.CountVariantClick(ctx, ID, variant); err != nil {
+			h.Logger.Error("failed to count variant click", "id", ID, "variant", variant, "error", err.Error())
+		}
+	}
+
+	// Redirect using the status and mode configured for the link
+	h.redirect(w, target, rec)
 }
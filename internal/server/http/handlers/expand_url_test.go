@@ -8,6 +8,7 @@ import (
 
 	"github.com/apetsko/shortugo/internal/logging"
 	"github.com/apetsko/shortugo/internal/mocks"
+	"github.com/apetsko/shortugo/internal/models"
 	"github.com/apetsko/shortugo/internal/storages/shared"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -26,7 +27,7 @@ func BenchmarkExpandURL(b *testing.B) {
 	testID := "abc123"
 	mockURL := "https://example.com"
 
-	mockStorage.On("Get", mock.Anything, testID).Return(mockURL, nil)
+	mockStorage.On("GetRecord", mock.Anything, testID).Return(&models.URLRecord{ID: testID, URL: mockURL}, nil)
 
 	for i := 0; i < b.N; i++ {
 		req := httptest.NewRequest("GET", "/"+testID, nil)
@@ -93,7 +94,11 @@ func TestExpandURL(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mockStorage.On("Get", mock.Anything, tt.urlID).Return(tt.mockReturn, tt.mockError)
+			var rec *models.URLRecord
+			if tt.mockError == nil {
+				rec = &models.URLRecord{ID: tt.urlID, URL: tt.mockReturn}
+			}
+			mockStorage.On("GetRecord", mock.Anything, tt.urlID).Return(rec, tt.mockError)
 
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest(http.MethodGet, "/"+tt.urlID, nil)
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apetsko/shortugo/internal/models"
+)
+
+// exportColumns is the header of the CSV export. ImportUserURLs reads the columns it knows and skips the others.
+var exportColumns = []string{
+	"short_url", "original_url", "title", "folder", "tags", "created_at",
+	"redirect_type", "redirect_mode", "query_policy", "forward_path", "max_clicks", "clicks_left",
+	"utm_source", "utm_medium", "utm_campaign", "utm_term", "utm_content",
+}
+
+// exportedLink is a line of the JSONL export, in the format of an item of ShortenBatchJSON.
+type exportedLink struct {
+	ShortURL    string    `json:"short_url"`             // Shortened URL.
+	OriginalURL string    `json:"original_url"`          // Original URL.
+	Title       string    `json:"title,omitempty"`       // Title of the link.
+	Folder      string    `json:"folder,omitempty"`      // Folder of the link.
+	Tags        []string  `json:"tags,omitempty"`        // Tags of the link.
+	CreatedAt   time.Time `json:"created_at"`            // When the link was stored.
+	ClicksLeft  int       `json:"clicks_left,omitempty"` // Remaining redirects of a link with max_clicks.
+	Protected   bool      `json:"protected,omitempty"`   // The link requires a password, which is not exported.
+	models.LinkOptions
+}
+
+// ExportUserURLs streams all links of the user as CSV or JSONL, one link at a time,
+// so the export never holds the whole list in memory. Both formats can be imported with ImportUserURLs;
+// routing rules and A/B variants are only part of JSONL, and passwords are never exported.
+//
+// Request:
+//   - Method: GET
+//   - URL: /api/user/urls/export?format=csv|jsonl, csv when format is not given.
+//
+// Response:
+//   - 200 OK: The links as an attachment; a CSV export always starts with its header row.
+//   - 400 Bad Request: Unknown format.
+//   - 500 Internal Server Error: User authentication failed or other server error.
+func (h *URLHandler) ExportUserURLs(w http.ResponseWriter, r *http.Request) {
+	// Retrieve the user ID from the cookie
+	userID, err := h.Auth.CookieGetUserID(r, h.Secret)
+	if err != nil {
+		// If the user ID is not found, set a new one
+		userID, err = h.Auth.CookieSetUserID(w, h.Secret)
+		if err != nil {
+			h.Logger.Error(err.Error())
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = FormatCSV
+	}
+
+	var (
+		contentType string
+		write       func(rec models.URLRecord) error
+		csvWriter   *csv.Writer
+	)
+	switch format {
+	case FormatCSV:
+		contentType = "text/csv; charset=utf-8"
+		csvWriter = csv.NewWriter(w)
+		write = func(rec models.URLRecord) error {
+			return csvWriter.Write(exportRow(rec))
+		}
+	case FormatJSONL:
+		contentType = "application/x-ndjson"
+		encoder := json.NewEncoder(w)
+		write = func(rec models.URLRecord) error {
+			return encoder.Encode(exportLink(rec))
+		}
+	default:
+		http.Error(w, "Unknown export format", http.StatusBadRequest)
+		return
+	}
+
+	// The response starts with the first link, so a storage failure before it still gets an error status
+	started := false
+	start := func() error {
+		started = true
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition", `attachment; filename="links.`+format+`"`)
+		w.WriteHeader(http.StatusOK)
+		if csvWriter != nil {
+			return csvWriter.Write(exportColumns)
+		}
+		return nil
+	}
+
+	err = h.Storage.ForEachLinkByUserID(r.Context(), h.BaseURL, userID, func(rec models.URLRecord) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		return write(rec)
+	})
+	if err == nil && !started {
+		err = start()
+	}
+	if csvWriter != nil && started {
+		csvWriter.Flush()
+		if err == nil {
+			err = csvWriter.Error()
+		}
+	}
+
+	if err != nil {
+		h.Logger.Error("Failed to export user URLs", "error", err.Error())
+		if !started {
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		}
+	}
+}
+
+// exportRow returns the fields of a link in the order of exportColumns.
+func exportRow(rec models.URLRecord) []string {
+	optionalInt := func(n int) string {
+		if n == 0 {
+			return ""
+		}
+		return strconv.Itoa(n)
+	}
+
+	forwardPath := ""
+	if rec.ForwardPath {
+		forwardPath = "true"
+	}
+
+	return []string{
+		rec.ID, rec.URL, rec.Title, rec.Folder, strings.Join(rec.Tags, "|"), rec.CreatedAt.UTC().Format(time.RFC3339),
+		optionalInt(rec.RedirectType), rec.RedirectMode, rec.QueryPolicy, forwardPath, optionalInt(rec.MaxClicks), optionalInt(rec.ClicksLeft),
+		rec.Source, rec.Medium, rec.Campaign, rec.Term, rec.Content,
+	}
+}
+
+// exportLink returns the JSONL line of a link, leaving out its password hash.
+func exportLink(rec models.URLRecord) exportedLink {
+	link := exportedLink{
+		ShortURL:    rec.ID,
+		OriginalURL: rec.URL,
+		Title:       rec.Title,
+		Folder:      rec.Folder,
+		Tags:        rec.Tags,
+		CreatedAt:   rec.CreatedAt.UTC(),
+		ClicksLeft:  rec.ClicksLeft,
+		Protected:   rec.Protected(),
+		LinkOptions: rec.LinkOptions,
+	}
+	link.PasswordHash = ""
+	return link
+}
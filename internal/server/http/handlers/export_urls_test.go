@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apetsko/shortugo/internal/mocks"
+	"github.com/apetsko/shortugo/internal/models"
+	"github.com/apetsko/shortugo/internal/storages/inmem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// exportStorage returns a storage with two links of user123, one of them protected, and a link of another user.
+func exportStorage(t *testing.T) *inmem.Storage {
+	t.Helper()
+
+	ctx := context.Background()
+	created := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	storage := inmem.New()
+
+	opts := models.LinkOptions{
+		RedirectType: 307,
+		MaxClicks:    5,
+		PasswordHash: "hash",
+		Rules:        []models.RoutingRule{{Platform: models.PlatformIOS, URL: "https://apps.apple.com"}},
+		UTM:          models.UTM{Source: "news"},
+	}
+	require.NoError(t, storage.Put(ctx, models.URLRecord{
+		ID: "abc123", URL: "https://example.com/a", UserID: "user123", CreatedAt: created,
+		Title: "Docs, part 1", Folder: "work", Tags: []string{"go", "promo"}, ClicksLeft: 4, LinkOptions: opts,
+	}))
+	require.NoError(t, storage.Put(ctx, models.URLRecord{ID: "def456", URL: "https://example.com/b", UserID: "user123", CreatedAt: created}))
+	require.NoError(t, storage.Put(ctx, models.URLRecord{ID: "ghi789", URL: "https://example.com/c", UserID: "user456", CreatedAt: created}))
+	return storage
+}
+
+func TestExportUserURLs_CSV(t *testing.T) {
+	h := newImportHandler(t, exportStorage(t))
+
+	w := httptest.NewRecorder()
+	h.ExportUserURLs(w, httptest.NewRequest(http.MethodGet, "/api/user/urls/export", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="links.csv"`, w.Header().Get("Content-Disposition"))
+	assert.Equal(t, strings.Join([]string{
+		"short_url,original_url,title,folder,tags,created_at,redirect_type,redirect_mode,query_policy,forward_path,max_clicks,clicks_left,utm_source,utm_medium,utm_campaign,utm_term,utm_content",
+		`http://short.ly/abc123,https://example.com/a,"Docs, part 1",work,go|promo,2026-10-01T12:00:00Z,307,,,,5,4,news,,,,`,
+		"http://short.ly/def456,https://example.com/b,,,,2026-10-01T12:00:00Z,,,,,,,,,,,",
+		"",
+	}, "\n"), w.Body.String())
+}
+
+func TestExportUserURLs_JSONL(t *testing.T) {
+	h := newImportHandler(t, exportStorage(t))
+
+	w := httptest.NewRecorder()
+	h.ExportUserURLs(w, httptest.NewRequest(http.MethodGet, "/api/user/urls/export?format=jsonl", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.JSONEq(t, `{
+		"short_url": "http://short.ly/abc123", "original_url": "https://example.com/a",
+		"title": "Docs, part 1", "folder": "work", "tags": ["go", "promo"],
+		"created_at": "2026-10-01T12:00:00Z", "clicks_left": 4, "protected": true,
+		"redirect_type": 307, "max_clicks": 5, "utm_source": "news",
+		"rules": [{"platform": "ios", "url": "https://apps.apple.com"}]
+	}`, lines[0])
+	assert.JSONEq(t, `{"short_url": "http://short.ly/def456", "original_url": "https://example.com/b", "created_at": "2026-10-01T12:00:00Z"}`, lines[1])
+}
+
+func TestExportUserURLs_RoundTrip(t *testing.T) {
+	source := newImportHandler(t, exportStorage(t))
+	w := httptest.NewRecorder()
+	source.ExportUserURLs(w, httptest.NewRequest(http.MethodGet, "/api/user/urls/export?format=jsonl", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	target := inmem.New()
+	h := newImportHandler(t, target)
+	imported := httptest.NewRecorder()
+	h.ImportUserURLs(imported, httptest.NewRequest(http.MethodPost, "/api/user/urls/import?format=jsonl", w.Body))
+	require.Equal(t, http.StatusOK, imported.Code)
+
+	for _, res := range importReport(t, imported.Body.String()) {
+		assert.Equal(t, ImportCreated, res.Status)
+	}
+
+	links, err := target.ListLinksByUserID(context.Background(), "http://short.ly", "user123")
+	require.NoError(t, err)
+	require.Len(t, links, 2)
+}
+
+func TestExportUserURLs_Errors(t *testing.T) {
+	t.Run("unknown format", func(t *testing.T) {
+		h := newImportHandler(t, new(mocks.Storage))
+
+		w := httptest.NewRecorder()
+		h.ExportUserURLs(w, httptest.NewRequest(http.MethodGet, "/api/user/urls/export?format=xml", nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		mockStorage := new(mocks.Storage)
+		mockStorage.On("ForEachLinkByUserID", mock.Anything, "http://short.ly", "user123", mock.Anything).Return(errors.New("database error"))
+		h := newImportHandler(t, mockStorage)
+
+		w := httptest.NewRecorder()
+		h.ExportUserURLs(w, httptest.NewRequest(http.MethodGet, "/api/user/urls/export", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		mockStorage.AssertExpectations(t)
+	})
+
+	t.Run("no links", func(t *testing.T) {
+		h := newImportHandler(t, inmem.New())
+
+		w := httptest.NewRecorder()
+		h.ExportUserURLs(w, httptest.NewRequest(http.MethodGet, "/api/user/urls/export?format=csv", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, strings.Join(exportColumns, ",")+"\n", w.Body.String())
+	})
+}
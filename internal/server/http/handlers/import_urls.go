@@ -0,0 +1,345 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/apetsko/shortugo/internal/audit"
+	"github.com/apetsko/shortugo/internal/models"
+	"github.com/apetsko/shortugo/internal/storages/shared"
+)
+
+// Formats of imported and exported links.
+const (
+	FormatCSV   = "csv"   // Comma-separated values with a header row.
+	FormatJSONL = "jsonl" // One JSON object per line.
+)
+
+// Statuses of an imported row in the ImportUserURLs report.
+const (
+	ImportCreated = "created" // The link was stored.
+	ImportExists  = "exists"  // A link with the same ID is already stored or came earlier in the upload.
+	ImportInvalid = "invalid" // The row failed validation and was skipped.
+	ImportFailed  = "failed"  // Storing the row failed; the import stops after it.
+)
+
+const (
+	importChunk   = 500     // Imported links stored by one PutBatch call.
+	maxImportLine = 1 << 20 // Longest line of a JSONL upload in bytes.
+)
+
+// importResult is a line of the ImportUserURLs report.
+type importResult struct {
+	Line     int    `json:"line"`                     // Line of the row in the upload, starting at 1.
+	ID       string `json:"correlation_id,omitempty"` // Correlation ID of the row.
+	ShortURL string `json:"short_url,omitempty"`      // Short URL of a created or existing link.
+	Status   string `json:"status"`                   // One of the Import statuses.
+	Error    string `json:"error,omitempty"`          // Why the row is invalid or failed.
+}
+
+// importRow is a row read from an upload.
+type importRow struct {
+	line int                 // Line of the row in the upload.
+	req  models.BatchRequest // The link to create.
+	err  error               // Why the row could not be parsed; the row is reported invalid.
+}
+
+// ImportUserURLs creates links for the user from a CSV or JSONL upload, validating each row like ShortenBatchJSON.
+// The upload is read row by row and stored in chunks, so it may hold any number of links.
+//
+// A CSV upload starts with a header naming its columns; original_url (or url) is required and
+// correlation_id, password, title, folder, tags (separated by |), redirect_type, redirect_mode,
+// query_policy, forward_path, max_clicks and utm_* are optional. Unknown columns are ignored,
+// so the CSV of ExportUserURLs can be imported as it is. A JSONL upload holds one item of
+// ShortenBatchJSON per line, rules and variants included.
+//
+// Request:
+//   - Method: POST
+//   - URL: /api/user/urls/import?format=csv|jsonl
+//   - Headers: Content-Type: text/csv or application/x-ndjson, read when format is not given.
+//   - Body: the rows to import.
+//
+// Response:
+//   - 200 OK: A JSONL report with a line per row:
+//     {"line": 2, "correlation_id": "1", "short_url": "http://localhost:8080/abc", "status": "created"}
+//     status is created, exists, invalid or failed, with the reason in error. A failed row stops the import.
+//   - 400 Bad Request: Unknown format or invalid CSV header.
+//   - 500 Internal Server Error: User authentication failed or other server error.
+func (h *URLHandler) ImportUserURLs(w http.ResponseWriter, r *http.Request) {
+	// Retrieve the user ID from the cookie
+	userID, err := h.Auth.CookieGetUserID(r, h.Secret)
+	if err != nil {
+		// If the user ID is not found, set a new one
+		userID, err = h.Auth.CookieSetUserID(w, h.Secret)
+		if err != nil {
+			h.Logger.Error(err.Error())
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	// Ensure the request body is closed after reading
+	defer func() {
+		if err := r.Body.Close(); err != nil {
+			h.Logger.Error("Failed to close request body", "error", err.Error())
+		}
+	}()
+
+	var next func() (importRow, error)
+	switch importFormat(r) {
+	case FormatCSV:
+		if next, err = csvImport(r.Body); err != nil {
+			h.Logger.Info("Invalid CSV upload", "error", err.Error())
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	case FormatJSONL:
+		next = jsonlImport(r.Body)
+	default:
+		http.Error(w, "Unknown import format", http.StatusBadRequest)
+		return
+	}
+
+	// The user's UTM template fills the tags missing from every row
+	ctx := r.Context()
+	template, err := h.UTMTemplate(ctx, userID)
+	if err != nil {
+		h.Logger.Error("Failed to get UTM template", "error", err.Error())
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+
+	encoder := json.NewEncoder(w)
+	var (
+		results []importResult
+		records []models.URLRecord
+		seen    = make(map[string]bool)
+	)
+
+	// store saves the pending links and reports the pending rows in order
+	store := func() bool {
+		var err error
+		if len(records) > 0 {
+			if err = h.Storage.PutBatch(ctx, records); err != nil {
+				h.Logger.Error("Failed to store imported links", "error", err.Error())
+			} else {
+				h.audit(r, userID, audit.ActionCreateLinks, LinkIDs(records), map[string]string{"source": "import"})
+			}
+		}
+		for _, res := range results {
+			if err != nil && res.Status == ImportCreated {
+				res.Status, res.ShortURL, res.Error = ImportFailed, "", "Failed to store link"
+			}
+			if encErr := encoder.Encode(res); encErr != nil {
+				h.Logger.Error(encErr.Error())
+				return false
+			}
+		}
+		results, records = results[:0], records[:0]
+		// Errors only mean the writer does not support flushing, then the report is sent at the end
+		_ = http.NewResponseController(w).Flush()
+		return err == nil
+	}
+
+	for {
+		row, err := next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			h.Logger.Info("Failed to read upload", "error", err.Error())
+			results = append(results, importResult{Line: row.line, Status: ImportFailed, Error: "Failed to read upload: " + err.Error()})
+			break
+		}
+
+		res := importResult{Line: row.line, ID: row.req.ID}
+		if row.err != nil {
+			res.Status, res.Error = ImportInvalid, http.StatusText(http.StatusBadRequest)+": "+row.err.Error()
+			results = append(results, res)
+			continue
+		}
+
+		record, errStr := h.batchRecord(row.req, userID, template)
+		if errStr != "" {
+			res.Status, res.Error = ImportInvalid, errStr
+			results = append(results, res)
+			continue
+		}
+		res.ShortURL = h.BaseURL + "/" + record.ID
+
+		if seen[record.ID] {
+			res.Status = ImportExists
+			results = append(results, res)
+			continue
+		}
+		seen[record.ID] = true
+
+		// Links already stored, deleted ones included, are never overwritten
+		if _, err = h.Storage.Get(ctx, record.ID); !errors.Is(err, shared.ErrNotFound) {
+			res.Status = ImportExists
+			if err != nil && !errors.Is(err, shared.ErrGone) {
+				h.Logger.Error("Failed to look up imported link", "error", err.Error())
+				res.Status, res.ShortURL, res.Error = ImportFailed, "", "Failed to look up link"
+				results = append(results, res)
+				break
+			}
+			results = append(results, res)
+			continue
+		}
+
+		res.Status = ImportCreated
+		results = append(results, res)
+		records = append(records, record)
+		if len(records) == importChunk && !store() {
+			return
+		}
+	}
+
+	store()
+}
+
+// importFormat returns the format of an upload from the format query parameter or else the Content-Type header.
+func importFormat(r *http.Request) string {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return format
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "text/csv":
+		return FormatCSV
+	case "application/x-ndjson", "application/jsonl", "application/x-jsonlines":
+		return FormatJSONL
+	}
+	return ""
+}
+
+// csvColumns set the field of an imported link named by a CSV column.
+var csvColumns = map[string]func(req *models.BatchRequest, v string) error{
+	"correlation_id": func(req *models.BatchRequest, v string) error { req.ID = v; return nil },
+	"original_url":   func(req *models.BatchRequest, v string) error { req.OriginalURL = strings.TrimSpace(v); return nil },
+	"url":            func(req *models.BatchRequest, v string) error { req.OriginalURL = strings.TrimSpace(v); return nil },
+	"password":       func(req *models.BatchRequest, v string) error { req.Password = v; return nil },
+	"title":          func(req *models.BatchRequest, v string) error { req.Title = v; return nil },
+	"folder":         func(req *models.BatchRequest, v string) error { req.Folder = v; return nil },
+	"tags": func(req *models.BatchRequest, v string) error {
+		if strings.TrimSpace(v) != "" {
+			req.Tags = strings.Split(v, "|")
+		}
+		return nil
+	},
+	"redirect_type": func(req *models.BatchRequest, v string) error { return parseCSVInt(v, &req.RedirectType) },
+	"redirect_mode": func(req *models.BatchRequest, v string) error { req.RedirectMode = strings.TrimSpace(v); return nil },
+	"query_policy":  func(req *models.BatchRequest, v string) error { req.QueryPolicy = strings.TrimSpace(v); return nil },
+	"forward_path": func(req *models.BatchRequest, v string) (err error) {
+		if v = strings.TrimSpace(v); v != "" {
+			req.ForwardPath, err = strconv.ParseBool(v)
+		}
+		return err
+	},
+	"max_clicks":   func(req *models.BatchRequest, v string) error { return parseCSVInt(v, &req.MaxClicks) },
+	"utm_source":   func(req *models.BatchRequest, v string) error { req.Source = v; return nil },
+	"utm_medium":   func(req *models.BatchRequest, v string) error { req.Medium = v; return nil },
+	"utm_campaign": func(req *models.BatchRequest, v string) error { req.Campaign = v; return nil },
+	"utm_term":     func(req *models.BatchRequest, v string) error { req.Term = v; return nil },
+	"utm_content":  func(req *models.BatchRequest, v string) error { req.Content = v; return nil },
+}
+
+// parseCSVInt parses a number of a CSV row into n; an empty value leaves n zero.
+func parseCSVInt(v string, n *int) (err error) {
+	if v = strings.TrimSpace(v); v != "" {
+		*n, err = strconv.Atoi(v)
+	}
+	return err
+}
+
+// csvImport reads the header of a CSV upload and returns a function reading its rows one at a time.
+func csvImport(body io.Reader) (func() (importRow, error), error) {
+	cr := csv.NewReader(body)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columns := make([]func(req *models.BatchRequest, v string) error, len(header))
+	hasURL := false
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		header[i] = name
+		columns[i] = csvColumns[name]
+		hasURL = hasURL || name == "original_url" || name == "url"
+	}
+	if !hasURL {
+		return nil, errors.New("CSV header has no original_url column")
+	}
+
+	return func() (importRow, error) {
+		fields, err := cr.Read()
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				return importRow{line: parseErr.Line}, err
+			}
+			return importRow{}, err
+		}
+
+		line, _ := cr.FieldPos(0)
+		row := importRow{line: line}
+
+		if len(fields) != len(header) {
+			row.err = fmt.Errorf("expected %d fields, got %d", len(header), len(fields))
+			return row, nil
+		}
+		for i, v := range fields {
+			if columns[i] == nil {
+				continue
+			}
+			if err := columns[i](&row.req, v); err != nil {
+				row.err = fmt.Errorf("invalid %s", header[i])
+				return row, nil
+			}
+		}
+		return row, nil
+	}, nil
+}
+
+// jsonlImport returns a function reading the rows of a JSONL upload one at a time, skipping blank lines.
+func jsonlImport(body io.Reader) func() (importRow, error) {
+	sc := bufio.NewScanner(body)
+	sc.Buffer(make([]byte, 0, 64*1024), maxImportLine)
+	line := 0
+
+	return func() (importRow, error) {
+		for sc.Scan() {
+			line++
+			b := bytes.TrimSpace(sc.Bytes())
+			if len(b) == 0 {
+				continue
+			}
+
+			row := importRow{line: line}
+			if err := json.Unmarshal(b, &row.req); err != nil {
+				row.err = errors.New("invalid JSON")
+			}
+			return row, nil
+		}
+		if err := sc.Err(); err != nil {
+			return importRow{line: line + 1}, err
+		}
+		return importRow{}, io.EOF
+	}
+}
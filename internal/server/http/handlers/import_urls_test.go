@@ -0,0 +1,250 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/apetsko/shortugo/internal/logging"
+	"github.com/apetsko/shortugo/internal/mocks"
+	"github.com/apetsko/shortugo/internal/models"
+	"github.com/apetsko/shortugo/internal/storages/infile"
+	"github.com/apetsko/shortugo/internal/storages/inmem"
+	"github.com/apetsko/shortugo/internal/storages/shared"
+	"github.com/apetsko/shortugo/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// importReport decodes the JSONL report of ImportUserURLs.
+func importReport(t *testing.T, body string) []importResult {
+	t.Helper()
+
+	var report []importResult
+	sc := bufio.NewScanner(strings.NewReader(body))
+	for sc.Scan() {
+		var res importResult
+		require.NoError(t, json.Unmarshal(sc.Bytes(), &res))
+		report = append(report, res)
+	}
+	require.NoError(t, sc.Err())
+	return report
+}
+
+// newImportHandler returns a handler authenticating every request as user123.
+func newImportHandler(t *testing.T, storage Storage) *URLHandler {
+	t.Helper()
+
+	logger, _ := logging.New(zapcore.DebugLevel)
+	mockAuth := new(mocks.Authenticator)
+	mockAuth.On("CookieGetUserID", mock.Anything, mock.Anything).Return("user123", nil)
+
+	return &URLHandler{
+		Auth:    mockAuth,
+		Storage: storage,
+		Logger:  logger,
+		BaseURL: "http://short.ly",
+	}
+}
+
+func TestImportUserURLs_CSV(t *testing.T) {
+	ctx := context.Background()
+	storage := inmem.New()
+	stored := models.URLRecord{ID: utils.GenerateID("https://example.com/c", 8), URL: "https://example.com/c", UserID: "user456"}
+	require.NoError(t, storage.Put(ctx, stored))
+
+	body := "original_url,correlation_id,tags,max_clicks,short_url\n" +
+		"https://example.com/a,1,promo|Go,3,ignored\n" +
+		",2,,,\n" +
+		"https://example.com/b,3,,abc,\n" +
+		"https://example.com/a,4,,,\n" +
+		"https://example.com/c,5,,,\n" +
+		"https://example.com/d,6\n"
+
+	h := newImportHandler(t, storage)
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/api/user/urls/import", strings.NewReader(body))
+	r.Header.Set("Content-Type", "text/csv")
+	h.ImportUserURLs(w, r)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+
+	idA := utils.GenerateID("https://example.com/a", 8)
+	assert.Equal(t, []importResult{
+		{Line: 2, ID: "1", ShortURL: "http://short.ly/" + idA, Status: ImportCreated},
+		{Line: 3, ID: "2", Status: ImportInvalid, Error: "Bad Request: Empty URL"},
+		{Line: 4, ID: "3", Status: ImportInvalid, Error: "Bad Request: invalid max_clicks"},
+		{Line: 5, ID: "4", ShortURL: "http://short.ly/" + idA, Status: ImportExists},
+		{Line: 6, ID: "5", ShortURL: "http://short.ly/" + stored.ID, Status: ImportExists},
+		{Line: 7, Status: ImportInvalid, Error: "Bad Request: expected 5 fields, got 2"},
+	}, importReport(t, w.Body.String()))
+
+	got, err := storage.GetRecord(ctx, idA)
+	require.NoError(t, err)
+	assert.Equal(t, "user123", got.UserID)
+	assert.Equal(t, []string{"go", "promo"}, got.Tags)
+	assert.Equal(t, 3, got.MaxClicks)
+	assert.Equal(t, 3, got.ClicksLeft)
+
+	// Existing links are never taken over
+	got, err = storage.GetRecord(ctx, stored.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "user456", got.UserID)
+}
+
+func TestImportUserURLs_FileStorage(t *testing.T) {
+	ctx := context.Background()
+	storage, err := infile.New(filepath.Join(t.TempDir(), "urls.json"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = storage.Close() })
+
+	idDeleted := utils.GenerateID("https://example.com/deleted", 8)
+	idExhausted := utils.GenerateID("https://example.com/exhausted", 8)
+	require.NoError(t, storage.PutBatch(ctx, []models.URLRecord{
+		{ID: idDeleted, URL: "https://example.com/deleted", UserID: "user456"},
+		{ID: idExhausted, URL: "https://example.com/exhausted", UserID: "user456", LinkOptions: models.LinkOptions{MaxClicks: 1}, ClicksLeft: 1},
+	}))
+	require.NoError(t, storage.DeleteUserURLs(ctx, []string{idDeleted}, "user456"))
+	require.NoError(t, storage.ConsumeClick(ctx, idExhausted))
+
+	body := "original_url,correlation_id,tags,max_clicks,short_url\n" +
+		"https://example.com/deleted,1,,,\n" +
+		"https://example.com/exhausted,2,,,\n" +
+		"https://example.com/after,3,,,\n"
+
+	h := newImportHandler(t, storage)
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/api/user/urls/import", strings.NewReader(body))
+	r.Header.Set("Content-Type", "text/csv")
+	h.ImportUserURLs(w, r)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	idAfter := utils.GenerateID("https://example.com/after", 8)
+	assert.Equal(t, []importResult{
+		{Line: 2, ID: "1", ShortURL: "http://short.ly/" + idDeleted, Status: ImportExists},
+		{Line: 3, ID: "2", ShortURL: "http://short.ly/" + idExhausted, Status: ImportExists},
+		{Line: 4, ID: "3", ShortURL: "http://short.ly/" + idAfter, Status: ImportCreated},
+	}, importReport(t, w.Body.String()))
+
+	// Rows after a gone link are stored, and the gone link is not taken over
+	got, err := storage.GetRecord(ctx, idAfter)
+	require.NoError(t, err)
+	assert.Equal(t, "user123", got.UserID)
+
+	_, err = storage.GetRecord(ctx, idDeleted)
+	assert.ErrorIs(t, err, shared.ErrGone)
+}
+
+func TestImportUserURLs_JSONL(t *testing.T) {
+	ctx := context.Background()
+	storage := inmem.New()
+
+	body := `{"correlation_id":"1","original_url":"https://example.com/a","password":"secret","rules":[{"platform":"ios","url":"https://apps.apple.com"}]}` + "\n" +
+		"\n" +
+		`{"correlation_id":"2","original_url":"https://example.com/b","redirect_type":200}` + "\n" +
+		`not json` + "\n" +
+		`{"correlation_id":"3","original_url":"https://example.com/c","password_hash":"forged"}`
+
+	h := newImportHandler(t, storage)
+	w := httptest.NewRecorder()
+	h.ImportUserURLs(w, httptest.NewRequest(http.MethodPost, "/api/user/urls/import?format=jsonl", strings.NewReader(body)))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	idA := utils.GenerateID("https://example.com/a", 8)
+	idC := utils.GenerateID("https://example.com/c", 8)
+	assert.Equal(t, []importResult{
+		{Line: 1, ID: "1", ShortURL: "http://short.ly/" + idA, Status: ImportCreated},
+		{Line: 3, ID: "2", Status: ImportInvalid, Error: "Bad Request: Invalid link options"},
+		{Line: 4, Status: ImportInvalid, Error: "Bad Request: invalid JSON"},
+		{Line: 5, ID: "3", ShortURL: "http://short.ly/" + idC, Status: ImportCreated},
+	}, importReport(t, w.Body.String()))
+
+	got, err := storage.GetRecord(ctx, idA)
+	require.NoError(t, err)
+	assert.True(t, got.Protected())
+	assert.Len(t, got.Rules, 1)
+
+	got, err = storage.GetRecord(ctx, idC)
+	require.NoError(t, err)
+	assert.False(t, got.Protected())
+}
+
+func TestImportUserURLs_Chunks(t *testing.T) {
+	ctx := context.Background()
+	storage := inmem.New()
+
+	var body strings.Builder
+	body.WriteString("url\n")
+	for i := range importChunk + 1 {
+		fmt.Fprintf(&body, "https://example.com/%d\n", i)
+	}
+
+	h := newImportHandler(t, storage)
+	w := httptest.NewRecorder()
+	h.ImportUserURLs(w, httptest.NewRequest(http.MethodPost, "/api/user/urls/import?format=csv", strings.NewReader(body.String())))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	report := importReport(t, w.Body.String())
+	require.Len(t, report, importChunk+1)
+	assert.Equal(t, importChunk+2, report[importChunk].Line)
+
+	links, err := storage.ListLinksByUserID(ctx, "http://short.ly", "user123")
+	require.NoError(t, err)
+	assert.Len(t, links, importChunk+1)
+}
+
+func TestImportUserURLs_Errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		target         string
+		contentType    string
+		body           string
+		expectedStatus int
+	}{
+		{name: "unknown format", target: "/api/user/urls/import?format=xml", body: "<links/>", expectedStatus: http.StatusBadRequest},
+		{name: "no format", target: "/api/user/urls/import", contentType: "application/json", body: "[]", expectedStatus: http.StatusBadRequest},
+		{name: "CSV without URL column", target: "/api/user/urls/import?format=csv", body: "title,folder\n", expectedStatus: http.StatusBadRequest},
+		{name: "empty CSV", target: "/api/user/urls/import?format=csv", body: "", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newImportHandler(t, new(mocks.Storage))
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, tt.target, strings.NewReader(tt.body))
+			r.Header.Set("Content-Type", tt.contentType)
+			h.ImportUserURLs(w, r)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestImportUserURLs_StorageFailure(t *testing.T) {
+	mockStorage := new(mocks.Storage)
+	mockStorage.On("GetUTMTemplate", mock.Anything, "user123").Return(nil, shared.ErrNotFound)
+	mockStorage.On("Get", mock.Anything, mock.Anything).Return("", shared.ErrNotFound)
+	mockStorage.On("PutBatch", mock.Anything, mock.Anything).Return(errors.New("database error"))
+
+	h := newImportHandler(t, mockStorage)
+	w := httptest.NewRecorder()
+	body := "url,title\n,Empty\nhttps://example.com/a,\n"
+	h.ImportUserURLs(w, httptest.NewRequest(http.MethodPost, "/api/user/urls/import?format=csv", strings.NewReader(body)))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []importResult{
+		{Line: 2, Status: ImportInvalid, Error: "Bad Request: Empty URL"},
+		{Line: 3, Status: ImportFailed, Error: "Failed to store link"},
+	}, importReport(t, w.Body.String()))
+	mockStorage.AssertExpectations(t)
+}
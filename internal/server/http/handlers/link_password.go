@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/apetsko/shortugo/internal/auth"
+	"github.com/apetsko/shortugo/internal/models"
+)
+
+// maxPasswordFormSize bounds the body of a password form submission.
+const maxPasswordFormSize = 4 << 10
+
+// passwordRequest is the body of SetLinkPassword.
+type passwordRequest struct {
+	Password string `json:"password"`
+}
+
+// SetPassword replaces the password of o with the hash of password. An empty password removes it.
+// A password hash sent by the client is never kept, so links can only be protected through this function.
+func SetPassword(o *models.LinkOptions, password string) error {
+	o.PasswordHash = ""
+	if password == "" {
+		return nil
+	}
+
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		return err
+	}
+	o.PasswordHash = hash
+	return nil
+}
+
+// PasswordAttemptKey identifies the client and the link whose failed password attempts are counted together.
+func PasswordAttemptKey(remoteAddr, id string) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	return host + "/" + id
+}
+
+// unlock checks the password of a protected link and reports whether the client may be redirected.
+// GET requests are answered with the password form; failed attempts are rate-limited per client and link.
+func (h *URLHandler) unlock(w http.ResponseWriter, r *http.Request, rec *models.URLRecord) bool {
+	if r.Method != http.MethodPost {
+		h.passwordForm(w, http.StatusOK, "")
+		return false
+	}
+
+	key := PasswordAttemptKey(r.RemoteAddr, rec.ID)
+	if ok, wait := h.PasswordAttempts.Allow(key); !ok {
+		h.Logger.Info("Too many password attempts", "id", rec.ID, "remote", r.RemoteAddr)
+		w.Header().Set("Retry-After", strconv.Itoa(int(wait.Seconds())+1))
+		h.passwordForm(w, http.StatusTooManyRequests, "Too many attempts. Try again later.")
+		return false
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxPasswordFormSize)
+	if !auth.CheckPassword(rec.PasswordHash, r.PostFormValue("password")) {
+		h.PasswordAttempts.Fail(key)
+		h.passwordForm(w, http.StatusUnauthorized, "Wrong password.")
+		return false
+	}
+
+	h.PasswordAttempts.Reset(key)
+	return true
+}
+
+// passwordForm renders the password form of a protected link.
+func (h *URLHandler) passwordForm(w http.ResponseWriter, code int, message string) {
+	var buf bytes.Buffer
+	if err := templates.ExecuteTemplate(&buf, "password.html", struct{ Error string }{Error: message}); err != nil {
+		h.Logger.Error("failed to render password form", "error", err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(code)
+	if _, err := buf.WriteTo(w); err != nil {
+		h.Logger.Error(err.Error())
+	}
+}
+
+// SetLinkPassword sets or removes the password of a link owned by the user.
+//
+// Request:
+//   - Method: PUT
+//   - URL: /api/user/urls/{id}/password
+//   - Headers: Content-Type: application/json
+//   - Body: {"password": "s3cret"}; an empty password removes the protection.
+//
+// Response:
+//   - 204 No Content: The password is updated.
+//   - 400 Bad Request: Invalid request body or a password longer than 72 bytes.
+//   - 404 Not Found: The link does not exist or belongs to another user.
+//   - 410 Gone: The link is deleted.
+//   - 500 Internal Server Error: User authentication failed or other server error.
+func (h *URLHandler) SetLinkPassword(w http.ResponseWriter, r *http.Request) {
+	// Retrieve the user ID from the cookie
+	userID, err := h.Auth.CookieGetUserID(r, h.Secret)
+	if err != nil {
+		// If the user ID is not found, set a new one
+		userID, err = h.Auth.CookieSetUserID(w, h.Secret)
+		if err != nil {
+			h.Logger.Error(err.Error())
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	// Extract the ID from the URL path
+	ID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/user/urls/"), "/password")
+
+	// Ensure the request body is closed after reading
+	defer func() {
+		if err2 := r.Body.Close(); err2 != nil {
+			h.Logger.Error("Failed to close request body", "error", err2.Error())
+		}
+	}()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	var req passwordRequest
+	if err = json.Unmarshal(body, &req); err != nil {
+		h.Logger.Info("Error unmarshaling request body", "error", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Hash before touching the storage, so the link is not locked for the duration of bcrypt
+	var options models.LinkOptions
+	if err = SetPassword(&options, req.Password); err != nil {
+		h.Logger.Info("Invalid link password", "error", err.Error())
+		http.Error(w, "Invalid password", http.StatusBadRequest)
+		return
+	}
+
+	err = h.Storage.UpdateLinkOptions(r.Context(), ID, userID, func(o *models.LinkOptions) error {
+		o.PasswordHash = options.PasswordHash
+		return nil
+	})
+	if err != nil {
+		h.writeLinkError(w, "Failed to update link password", err)
+		return
+	}
+	h.auditUpdate(r, userID, ID, "password")
+
+	w.WriteHeader(http.StatusNoContent)
+}
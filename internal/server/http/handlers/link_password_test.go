@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apetsko/shortugo/internal/auth"
+	"github.com/apetsko/shortugo/internal/logging"
+	"github.com/apetsko/shortugo/internal/mocks"
+	"github.com/apetsko/shortugo/internal/models"
+	"github.com/apetsko/shortugo/internal/ratelimit"
+	"github.com/apetsko/shortugo/internal/storages/shared"
+	"github.com/apetsko/shortugo/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestExpandURL_Password(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+	hash, err := auth.HashPassword("s3cret")
+	require.NoError(t, err)
+
+	mockStorage := new(mocks.Storage)
+	mockStorage.On("GetRecord", mock.Anything, "locked").Return(&models.URLRecord{
+		ID:          "locked",
+		URL:         "https://example.com/docs",
+		LinkOptions: models.LinkOptions{PasswordHash: hash, RedirectType: http.StatusMovedPermanently},
+	}, nil)
+	mockStorage.On("GetRecord", mock.Anything, "open").
+		Return(&models.URLRecord{ID: "open", URL: "https://example.com"}, nil)
+
+	h := &URLHandler{
+		Storage:          mockStorage,
+		Logger:           logger,
+		PasswordAttempts: ratelimit.New(2, time.Minute),
+	}
+
+	submit := func(id, password string) *httptest.ResponseRecorder {
+		form := url.Values{"password": {password}}
+		r := httptest.NewRequest(http.MethodPost, "/"+id, strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		h.ExpandURL(w, r)
+		return w
+	}
+
+	t.Run("form served", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.ExpandURL(w, httptest.NewRequest(http.MethodGet, "/locked", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("Location"))
+		assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+		assert.Contains(t, w.Body.String(), `name="password"`)
+		assert.NotContains(t, w.Body.String(), "example.com")
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		w := submit("locked", "guess")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Contains(t, w.Body.String(), "Wrong password")
+	})
+
+	t.Run("right password", func(t *testing.T) {
+		w := submit("locked", "s3cret")
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, "https://example.com/docs", w.Header().Get("Location"))
+	})
+
+	t.Run("rate limited", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, submit("locked", "guess").Code)
+		assert.Equal(t, http.StatusUnauthorized, submit("locked", "guess").Code)
+
+		// Even the right password is refused until the window passes
+		w := submit("locked", "s3cret")
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.NotEmpty(t, w.Header().Get("Retry-After"))
+		assert.Empty(t, w.Header().Get("Location"))
+	})
+
+	t.Run("unprotected link rejects submissions", func(t *testing.T) {
+		assert.Equal(t, http.StatusMethodNotAllowed, submit("open", "s3cret").Code)
+	})
+}
+
+func TestSetLinkPassword(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+
+	tests := []struct {
+		storageErr     error
+		name           string
+		body           string
+		expectedStatus int
+		callsStorage   bool
+		protected      bool
+	}{
+		{
+			name:           "password set",
+			body:           `{"password":"s3cret"}`,
+			callsStorage:   true,
+			protected:      true,
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "password removed",
+			body:           `{"password":""}`,
+			callsStorage:   true,
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "invalid JSON",
+			body:           `{"password":`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "password too long",
+			body:           `{"password":"` + strings.Repeat("a", 73) + `"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "foreign link",
+			body:           `{"password":"s3cret"}`,
+			storageErr:     shared.ErrNotFound,
+			callsStorage:   true,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "deleted link",
+			body:           `{"password":"s3cret"}`,
+			storageErr:     shared.ErrGone,
+			callsStorage:   true,
+			expectedStatus: http.StatusGone,
+		},
+		{
+			name:           "storage error",
+			body:           `{"password":"s3cret"}`,
+			storageErr:     errors.New("database error"),
+			callsStorage:   true,
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAuth := new(mocks.Authenticator)
+			mockAuth.On("CookieGetUserID", mock.Anything, mock.Anything).Return("user123", nil)
+
+			// The link starts with a password, so removing it is visible.
+			opts := models.LinkOptions{PasswordHash: "old"}
+			mockStorage := new(mocks.Storage)
+			if tt.callsStorage {
+				mockStorage.On("UpdateLinkOptions", mock.Anything, "abc123", "user123", mock.Anything).
+					Run(func(args mock.Arguments) {
+						_ = args.Get(3).(func(o *models.LinkOptions) error)(&opts)
+					}).Return(tt.storageErr)
+			}
+
+			h := &URLHandler{
+				Auth:    mockAuth,
+				Storage: mockStorage,
+				Logger:  logger,
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPut, "/api/user/urls/abc123/password", strings.NewReader(tt.body))
+			h.SetLinkPassword(w, r)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockStorage.AssertExpectations(t)
+			if tt.expectedStatus == http.StatusNoContent {
+				assert.Equal(t, tt.protected, opts.Protected())
+				if tt.protected {
+					assert.True(t, auth.CheckPassword(opts.PasswordHash, "s3cret"))
+				}
+			}
+		})
+	}
+}
+
+func TestShortenJSON_Password(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+	url := "https://example.com"
+	id := utils.GenerateID(url, 8)
+
+	mockAuth := new(mocks.Authenticator)
+	mockAuth.On("CookieGetUserID", mock.Anything, mock.Anything).Return("user123", nil)
+	mockStorage := new(mocks.Storage)
+	mockStorage.On("GetUTMTemplate", mock.Anything, "user123").Return(nil, shared.ErrNotFound)
+	mockStorage.On("Get", mock.Anything, id).Return("", shared.ErrNotFound)
+	var stored models.URLRecord
+	mockStorage.On("Put", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(models.URLRecord) }).
+		Return(nil)
+
+	h := &URLHandler{
+		Auth:    mockAuth,
+		Storage: mockStorage,
+		Logger:  logger,
+		BaseURL: "http://short.ly",
+	}
+
+	// A hash sent by the client is replaced by the hash of the password
+	body := `{"url":"https://example.com","password":"s3cret","password_hash":"forged"}`
+	w := httptest.NewRecorder()
+	h.ShortenJSON(w, httptest.NewRequest(http.MethodPost, "/api/shorten", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	require.True(t, stored.Protected())
+	assert.NotEqual(t, "forged", stored.PasswordHash)
+	assert.True(t, auth.CheckPassword(stored.PasswordHash, "s3cret"))
+}
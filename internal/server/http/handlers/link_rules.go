@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/apetsko/shortugo/internal/models"
+	"github.com/apetsko/shortugo/internal/routing"
+	"github.com/apetsko/shortugo/internal/storages/shared"
+	"github.com/apetsko/shortugo/internal/utils"
+)
+
+// rulesRequest is the body of PutLinkRules and the response of GetLinkRules.
+type rulesRequest struct {
+	Rules []models.RoutingRule `json:"rules" validate:"max=32,dive"`
+}
+
+// visitor describes the client of r for routing rules.
+func (h *URLHandler) visitor(r *http.Request) routing.Visitor {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	return routing.Visitor{
+		Platform: routing.Platform(r.UserAgent()),
+		Language: routing.Language(r.Header.Get("Accept-Language")),
+		Country:  h.GeoIP.Country(net.ParseIP(host)),
+	}
+}
+
+// GetLinkRules returns the routing rules of a link owned by the user.
+//
+// Request:
+//   - Method: GET
+//   - URL: /api/user/urls/{id}/rules
+//
+// Response:
+//   - 200 OK: {"rules": [{"platform": "ios", "url": "https://apps.apple.com/..."}]}
+//   - 404 Not Found: The link does not exist or belongs to another user.
+//   - 410 Gone: The link is deleted.
+//   - 500 Internal Server Error: User authentication failed or other server error.
+func (h *URLHandler) GetLinkRules(w http.ResponseWriter, r *http.Request) {
+	// Retrieve the user ID from the cookie
+	userID, err := h.Auth.CookieGetUserID(r, h.Secret)
+	if err != nil {
+		// If the user ID is not found, set a new one
+		userID, err = h.Auth.CookieSetUserID(w, h.Secret)
+		if err != nil {
+			h.Logger.Error(err.Error())
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	// Extract the ID from the URL path
+	ID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/user/urls/"), "/rules")
+
+	rec, err := h.Storage.GetRecord(r.Context(), ID)
+	if err == nil && rec.UserID != userID {
+		err = shared.ErrNotFound
+	}
+	if err != nil {
+		h.writeLinkError(w, "Failed to get link rules", err)
+		return
+	}
+
+	h.writeRules(w, rec.Rules)
+}
+
+// PutLinkRules replaces the routing rules of a link owned by the user.
+// Rules are tried in order on every redirect; visitors matching none of them go to the link URL.
+//
+// Request:
+//   - Method: PUT
+//   - URL: /api/user/urls/{id}/rules
+//   - Headers: Content-Type: application/json
+//   - Body: {"rules": [{"platform": "ios", "url": "https://apps.apple.com/..."},
+//     {"platform": "android", "url": "https://play.google.com/..."}, {"language": "de", "country": "AT", "url": "..."}]}
+//     platform (ios, android, windows, macos, linux, mobile or desktop), language (BCP 47 tag)
+//     and country (ISO 3166-1 alpha-2) are optional; an empty list removes the rules.
+//
+// Response:
+//   - 200 OK: The stored rules.
+//   - 400 Bad Request: Invalid request body or rules.
+//   - 404 Not Found: The link does not exist or belongs to another user.
+//   - 410 Gone: The link is deleted.
+//   - 500 Internal Server Error: User authentication failed or other server error.
+func (h *URLHandler) PutLinkRules(w http.ResponseWriter, r *http.Request) {
+	// Retrieve the user ID from the cookie
+	userID, err := h.Auth.CookieGetUserID(r, h.Secret)
+	if err != nil {
+		// If the user ID is not found, set a new one
+		userID, err = h.Auth.CookieSetUserID(w, h.Secret)
+		if err != nil {
+			h.Logger.Error(err.Error())
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	// Extract the ID from the URL path
+	ID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/user/urls/"), "/rules")
+
+	// Ensure the request body is closed after reading
+	defer func() {
+		if err2 := r.Body.Close(); err2 != nil {
+			h.Logger.Error("Failed to close request body", "error", err2.Error())
+		}
+	}()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	var req rulesRequest
+	if err = json.Unmarshal(body, &req); err != nil {
+		h.Logger.Info("Error unmarshaling request body", "error", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = utils.ValidateStruct(req); err != nil {
+		h.Logger.Info("Invalid routing rules", "error", err.Error())
+		http.Error(w, "Invalid routing rules", http.StatusBadRequest)
+		return
+	}
+
+	err = h.Storage.UpdateLinkOptions(r.Context(), ID, userID, func(o *models.LinkOptions) error {
+		o.Rules = req.Rules
+		return nil
+	})
+	if err != nil {
+		h.writeLinkError(w, "Failed to update link rules", err)
+		return
+	}
+	h.auditUpdate(r, userID, ID, "rules")
+
+	h.writeRules(w, req.Rules)
+}
+
+// writeRules writes rules as the JSON response of the rules endpoints.
+func (h *URLHandler) writeRules(w http.ResponseWriter, rules []models.RoutingRule) {
+	if rules == nil {
+		rules = []models.RoutingRule{}
+	}
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(rulesRequest{Rules: rules}); err != nil {
+		h.Logger.Error("Error marshaling link rules", "error", err.Error())
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if _, err := buf.WriteTo(w); err != nil {
+		h.Logger.Error(err.Error())
+	}
+}
+
+// writeLinkError answers a failed lookup or update of a user's link.
+func (h *URLHandler) writeLinkError(w http.ResponseWriter, msg string, err error) {
+	switch {
+	case errors.Is(err, shared.ErrNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	case errors.Is(err, shared.ErrGone):
+		w.WriteHeader(http.StatusGone)
+	default:
+		h.Logger.Error(msg, "error", err.Error())
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
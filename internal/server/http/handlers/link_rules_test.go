@@ -0,0 +1,274 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/apetsko/shortugo/internal/geoip"
+	"github.com/apetsko/shortugo/internal/logging"
+	"github.com/apetsko/shortugo/internal/mocks"
+	"github.com/apetsko/shortugo/internal/models"
+	"github.com/apetsko/shortugo/internal/storages/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	iPhoneUA  = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 Mobile/15E148"
+	androidUA = "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 Chrome/120.0 Mobile Safari/537.36"
+	windowsUA = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 Chrome/120.0 Safari/537.36"
+)
+
+func TestExpandURL_RoutingRules(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+	// The test database maps 192.0.2.0/24 to DE and does not know 203.0.113.0/24
+	db, err := geoip.Open("../../../geoip/testdata/country.mmdb")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, db.Close())
+	}()
+
+	rules := []models.RoutingRule{
+		{Platform: models.PlatformIOS, URL: "https://apps.apple.com/app/id1"},
+		{Platform: models.PlatformAndroid, URL: "https://play.google.com/store/apps/details?id=app"},
+		{Country: "DE", URL: "https://example.com/de"},
+		{Language: "fr", URL: "https://example.com/fr"},
+	}
+
+	tests := []struct {
+		name             string
+		userAgent        string
+		acceptLanguage   string
+		remoteAddr       string
+		expectedLocation string
+	}{
+		{
+			name:             "iOS",
+			userAgent:        iPhoneUA,
+			remoteAddr:       "192.0.2.10:1234",
+			expectedLocation: "https://apps.apple.com/app/id1?utm_source=print",
+		},
+		{
+			name:             "Android",
+			userAgent:        androidUA,
+			remoteAddr:       "192.0.2.10:1234",
+			expectedLocation: "https://play.google.com/store/apps/details?id=app&utm_source=print",
+		},
+		{
+			name:             "country",
+			userAgent:        windowsUA,
+			remoteAddr:       "192.0.2.10:1234",
+			expectedLocation: "https://example.com/de?utm_source=print",
+		},
+		{
+			name:             "preferred language",
+			userAgent:        windowsUA,
+			remoteAddr:       "203.0.113.5:1234",
+			acceptLanguage:   "fr-CA, en;q=0.8",
+			expectedLocation: "https://example.com/fr?utm_source=print",
+		},
+		{
+			name:             "fallback",
+			userAgent:        windowsUA,
+			remoteAddr:       "203.0.113.5:1234",
+			acceptLanguage:   "en, fr;q=0.5",
+			expectedLocation: "https://example.com?utm_source=print",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := new(mocks.Storage)
+			mockStorage.On("GetRecord", mock.Anything, "abc123").Return(&models.URLRecord{
+				ID:          "abc123",
+				URL:         "https://example.com",
+				LinkOptions: models.LinkOptions{Rules: rules, UTM: models.UTM{Source: "print"}},
+			}, nil)
+
+			h := &URLHandler{
+				Storage: mockStorage,
+				Logger:  logger,
+				GeoIP:   db,
+			}
+
+			r := httptest.NewRequest(http.MethodGet, "/abc123", nil)
+			r.Header.Set("User-Agent", tt.userAgent)
+			r.Header.Set("Accept-Language", tt.acceptLanguage)
+			r.RemoteAddr = tt.remoteAddr
+			w := httptest.NewRecorder()
+			h.ExpandURL(w, r)
+
+			assert.Equal(t, http.StatusTemporaryRedirect, w.Code)
+			assert.Equal(t, tt.expectedLocation, w.Header().Get("Location"))
+			assert.Equal(t, "User-Agent, Accept-Language", w.Header().Get("Vary"))
+		})
+	}
+}
+
+func TestGetLinkRules(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+
+	tests := []struct {
+		record         *models.URLRecord
+		storageErr     error
+		name           string
+		expectedBody   string
+		expectedStatus int
+	}{
+		{
+			name: "rules set",
+			record: &models.URLRecord{ID: "abc123", UserID: "user123", LinkOptions: models.LinkOptions{
+				Rules: []models.RoutingRule{{Platform: "ios", URL: "https://apps.apple.com/app/id1"}},
+			}},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"rules":[{"platform":"ios","url":"https://apps.apple.com/app/id1"}]}`,
+		},
+		{
+			name:           "no rules",
+			record:         &models.URLRecord{ID: "abc123", UserID: "user123"},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"rules":[]}`,
+		},
+		{
+			name:           "foreign link",
+			record:         &models.URLRecord{ID: "abc123", UserID: "other"},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "deleted link",
+			storageErr:     shared.ErrGone,
+			expectedStatus: http.StatusGone,
+		},
+		{
+			name:           "storage error",
+			storageErr:     errors.New("database error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAuth := new(mocks.Authenticator)
+			mockAuth.On("CookieGetUserID", mock.Anything, mock.Anything).Return("user123", nil)
+			mockStorage := new(mocks.Storage)
+			mockStorage.On("GetRecord", mock.Anything, "abc123").Return(tt.record, tt.storageErr)
+
+			h := &URLHandler{
+				Auth:    mockAuth,
+				Storage: mockStorage,
+				Logger:  logger,
+			}
+
+			w := httptest.NewRecorder()
+			h.GetLinkRules(w, httptest.NewRequest(http.MethodGet, "/api/user/urls/abc123/rules", nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestPutLinkRules(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+
+	tests := []struct {
+		storageErr     error
+		name           string
+		body           string
+		expectedRules  []models.RoutingRule
+		expectedStatus int
+		callsStorage   bool
+	}{
+		{
+			name:         "rules stored",
+			body:         `{"rules":[{"platform":"android","url":"https://play.google.com/store"},{"language":"de-AT","country":"AT","url":"https://example.com/at"}]}`,
+			callsStorage: true,
+			expectedRules: []models.RoutingRule{
+				{Platform: "android", URL: "https://play.google.com/store"},
+				{Language: "de-AT", Country: "AT", URL: "https://example.com/at"},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "rules removed",
+			body:           `{"rules":[]}`,
+			callsStorage:   true,
+			expectedRules:  []models.RoutingRule{},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid JSON",
+			body:           `{"rules":`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown platform",
+			body:           `{"rules":[{"platform":"palm","url":"https://example.com"}]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid country",
+			body:           `{"rules":[{"country":"germany","url":"https://example.com"}]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing destination",
+			body:           `{"rules":[{"platform":"ios"}]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "foreign link",
+			body:           `{"rules":[]}`,
+			storageErr:     shared.ErrNotFound,
+			callsStorage:   true,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "storage error",
+			body:           `{"rules":[]}`,
+			storageErr:     errors.New("database error"),
+			callsStorage:   true,
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAuth := new(mocks.Authenticator)
+			mockAuth.On("CookieGetUserID", mock.Anything, mock.Anything).Return("user123", nil)
+
+			var opts models.LinkOptions
+			mockStorage := new(mocks.Storage)
+			if tt.callsStorage {
+				mockStorage.On("UpdateLinkOptions", mock.Anything, "abc123", "user123", mock.Anything).
+					Run(func(args mock.Arguments) {
+						_ = args.Get(3).(func(o *models.LinkOptions) error)(&opts)
+					}).Return(tt.storageErr)
+			}
+
+			h := &URLHandler{
+				Auth:    mockAuth,
+				Storage: mockStorage,
+				Logger:  logger,
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPut, "/api/user/urls/abc123/rules", strings.NewReader(tt.body))
+			h.PutLinkRules(w, r)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockStorage.AssertExpectations(t)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, tt.expectedRules, opts.Rules)
+				assert.JSONEq(t, tt.body, w.Body.String())
+			}
+		})
+	}
+}
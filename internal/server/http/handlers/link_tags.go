@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/apetsko/shortugo/internal/models"
+	"github.com/apetsko/shortugo/internal/storages/shared"
+	"github.com/apetsko/shortugo/internal/utils"
+)
+
+// tagsRequest is the body of AddLinkTags and RemoveLinkTags.
+type tagsRequest struct {
+	Tags []string `json:"tags" validate:"required,min=1,max=32,dive,required,max=64"`
+}
+
+// folderRequest is the body of SetLinkFolder.
+type folderRequest struct {
+	Folder string `json:"folder" validate:"max=128"`
+}
+
+// labelsResponse is the response of the tag and folder endpoints.
+type labelsResponse struct {
+	Folder string   `json:"folder"`
+	Tags   []string `json:"tags"`
+}
+
+// AddLinkTags adds tags to a link owned by the user.
+// Tags are trimmed and lowercased, so "Promo" and "promo" are the same tag; adding a tag twice is a no-op.
+//
+// Request:
+//   - Method: POST
+//   - URL: /api/user/urls/{id}/tags
+//   - Headers: Content-Type: application/json
+//   - Body: {"tags": ["promo", "2026"]}
+//
+// Response:
+//   - 200 OK: {"folder": "campaigns", "tags": ["2026", "promo"]}
+//   - 400 Bad Request: Invalid request body or tags, or the link would get more than 32 tags.
+//   - 404 Not Found: The link does not exist or belongs to another user.
+//   - 410 Gone: The link is deleted.
+//   - 500 Internal Server Error: User authentication failed or other server error.
+func (h *URLHandler) AddLinkTags(w http.ResponseWriter, r *http.Request) {
+	h.updateLinkTags(w, r, h.Storage.AddLinkTags)
+}
+
+// RemoveLinkTags removes tags from a link owned by the user. Tags the link does not have are ignored.
+//
+// Request:
+//   - Method: DELETE
+//   - URL: /api/user/urls/{id}/tags
+//   - Headers: Content-Type: application/json
+//   - Body: {"tags": ["promo"]}
+//
+// Response:
+//   - 200 OK: {"folder": "campaigns", "tags": ["2026"]}
+//   - 400 Bad Request: Invalid request body or tags.
+//   - 404 Not Found: The link does not exist or belongs to another user.
+//   - 410 Gone: The link is deleted.
+//   - 500 Internal Server Error: User authentication failed or other server error.
+func (h *URLHandler) RemoveLinkTags(w http.ResponseWriter, r *http.Request) {
+	h.updateLinkTags(w, r, h.Storage.RemoveLinkTags)
+}
+
+// updateLinkTags reads the tags of a tag request and passes them to update.
+func (h *URLHandler) updateLinkTags(w http.ResponseWriter, r *http.Request, update func(ctx context.Context, id, userID string, tags []string) error) {
+	userID, ok := h.labelsUserID(w, r)
+	if !ok {
+		return
+	}
+
+	// Extract the ID from the URL path
+	ID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/user/urls/"), "/tags")
+
+	var req tagsRequest
+	if !h.readLabelsRequest(w, r, &req) {
+		return
+	}
+
+	tags := models.NormalizeTags(req.Tags)
+	if len(tags) == 0 {
+		http.Error(w, "Invalid tags", http.StatusBadRequest)
+		return
+	}
+
+	if err := update(r.Context(), ID, userID, tags); err != nil {
+		if errors.Is(err, shared.ErrTooManyTags) {
+			http.Error(w, "Too many tags", http.StatusBadRequest)
+			return
+		}
+		h.writeLinkError(w, "Failed to update link tags", err)
+		return
+	}
+	h.auditUpdate(r, userID, ID, "tags")
+
+	h.writeLabels(w, r, ID)
+}
+
+// SetLinkFolder files a link owned by the user in a folder. An empty folder takes the link out of its folder.
+//
+// Request:
+//   - Method: PUT
+//   - URL: /api/user/urls/{id}/folder
+//   - Headers: Content-Type: application/json
+//   - Body: {"folder": "campaigns"}
+//
+// Response:
+//   - 200 OK: {"folder": "campaigns", "tags": ["promo"]}
+//   - 400 Bad Request: Invalid request body or folder.
+//   - 404 Not Found: The link does not exist or belongs to another user.
+//   - 410 Gone: The link is deleted.
+//   - 500 Internal Server Error: User authentication failed or other server error.
+func (h *URLHandler) SetLinkFolder(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.labelsUserID(w, r)
+	if !ok {
+		return
+	}
+
+	// Extract the ID from the URL path
+	ID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/user/urls/"), "/folder")
+
+	var req folderRequest
+	if !h.readLabelsRequest(w, r, &req) {
+		return
+	}
+
+	if err := h.Storage.SetLinkFolder(r.Context(), ID, userID, strings.TrimSpace(req.Folder)); err != nil {
+		h.writeLinkError(w, "Failed to update link folder", err)
+		return
+	}
+	h.auditUpdate(r, userID, ID, "folder")
+
+	h.writeLabels(w, r, ID)
+}
+
+// labelsUserID returns the user ID from the cookie, setting a new one if not found.
+func (h *URLHandler) labelsUserID(w http.ResponseWriter, r *http.Request) (string, bool) {
+	// Retrieve the user ID from the cookie
+	userID, err := h.Auth.CookieGetUserID(r, h.Secret)
+	if err != nil {
+		// If the user ID is not found, set a new one
+		userID, err = h.Auth.CookieSetUserID(w, h.Secret)
+		if err != nil {
+			h.Logger.Error(err.Error())
+			w.WriteHeader(http.StatusInternalServerError)
+			return "", false
+		}
+	}
+	return userID, true
+}
+
+// readLabelsRequest reads and validates the JSON body of a tag or folder request into req.
+func (h *URLHandler) readLabelsRequest(w http.ResponseWriter, r *http.Request, req any) bool {
+	// Ensure the request body is closed after reading
+	defer func() {
+		if err := r.Body.Close(); err != nil {
+			h.Logger.Error("Failed to close request body", "error", err.Error())
+		}
+	}()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		return false
+	}
+
+	if err = json.Unmarshal(body, req); err != nil {
+		h.Logger.Info("Error unmarshaling request body", "error", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return false
+	}
+
+	if err = utils.ValidateStruct(req); err != nil {
+		h.Logger.Info("Invalid link labels", "error", err.Error())
+		http.Error(w, "Invalid labels", http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// writeLabels writes the stored folder and tags of a link as the JSON response of the tag and folder endpoints.
+func (h *URLHandler) writeLabels(w http.ResponseWriter, r *http.Request, id string) {
+	rec, err := h.Storage.GetRecord(r.Context(), id)
+	if err != nil {
+		h.writeLinkError(w, "Failed to get link labels", err)
+		return
+	}
+
+	resp := labelsResponse{Folder: rec.Folder, Tags: rec.Tags}
+	if resp.Tags == nil {
+		resp.Tags = []string{}
+	}
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(resp); err != nil {
+		h.Logger.Error("Error marshaling link labels", "error", err.Error())
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if _, err := buf.WriteTo(w); err != nil {
+		h.Logger.Error(err.Error())
+	}
+}
@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/apetsko/shortugo/internal/logging"
+	"github.com/apetsko/shortugo/internal/mocks"
+	"github.com/apetsko/shortugo/internal/models"
+	"github.com/apetsko/shortugo/internal/storages/inmem"
+	"github.com/apetsko/shortugo/internal/storages/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLinkTags(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+	ctx := context.Background()
+
+	storage := inmem.New()
+	require.NoError(t, storage.Put(ctx, models.URLRecord{ID: "abc123", URL: "https://example.com", UserID: "user123"}))
+	require.NoError(t, storage.Put(ctx, models.URLRecord{ID: "def456", URL: "https://example.org", UserID: "user123"}))
+	require.NoError(t, storage.Put(ctx, models.URLRecord{ID: "foreign", URL: "https://example.net", UserID: "user456"}))
+
+	mockAuth := new(mocks.Authenticator)
+	mockAuth.On("CookieGetUserID", mock.Anything, mock.Anything).Return("user123", nil)
+
+	h := &URLHandler{
+		Auth:    mockAuth,
+		Storage: storage,
+		Logger:  logger,
+		BaseURL: "http://short.ly",
+	}
+
+	do := func(handler http.HandlerFunc, method, target, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler(w, httptest.NewRequest(method, target, strings.NewReader(body)))
+		return w
+	}
+
+	w := do(h.AddLinkTags, http.MethodPost, "/api/user/urls/abc123/tags", `{"tags":["Promo"," 2026 ","promo"]}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"folder":"","tags":["2026","promo"]}`, w.Body.String())
+
+	w = do(h.AddLinkTags, http.MethodPost, "/api/user/urls/def456/tags", `{"tags":["promo"]}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = do(h.SetLinkFolder, http.MethodPut, "/api/user/urls/abc123/folder", `{"folder":" Campaigns "}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"folder":"Campaigns","tags":["2026","promo"]}`, w.Body.String())
+
+	w = do(h.ListUserURLs, http.MethodGet, "/api/user/urls?tag=promo&folder=Campaigns", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[{"short_url":"http://short.ly/abc123","original_url":"https://example.com","folder":"Campaigns","tags":["2026","promo"]}]`,
+		w.Body.String())
+
+	w = do(h.RemoveLinkTags, http.MethodDelete, "/api/user/urls/abc123/tags", `{"tags":["PROMO"]}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"folder":"Campaigns","tags":["2026"]}`, w.Body.String())
+
+	w = do(h.ListUserURLs, http.MethodGet, "/api/user/urls?tag=promo", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[{"short_url":"http://short.ly/def456","original_url":"https://example.org","tags":["promo"]}]`, w.Body.String())
+
+	w = do(h.ListUserURLs, http.MethodGet, "/api/user/urls?tag=unknown", "")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = do(h.SetLinkFolder, http.MethodPut, "/api/user/urls/abc123/folder", `{"folder":""}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"folder":"","tags":["2026"]}`, w.Body.String())
+
+	w = do(h.AddLinkTags, http.MethodPost, "/api/user/urls/foreign/tags", `{"tags":["promo"]}`)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	many := make([]string, models.MaxTags)
+	for i := range many {
+		many[i] = fmt.Sprintf("%q", fmt.Sprintf("tag%02d", i))
+	}
+	w = do(h.AddLinkTags, http.MethodPost, "/api/user/urls/abc123/tags", `{"tags":[`+strings.Join(many, ",")+`]}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestLinkTags_BadRequest(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+
+	tests := []struct {
+		handler func(h *URLHandler) http.HandlerFunc
+		name    string
+		path    string
+		body    string
+	}{
+		{
+			name:    "invalid JSON",
+			handler: func(h *URLHandler) http.HandlerFunc { return h.AddLinkTags },
+			path:    "/api/user/urls/abc123/tags",
+			body:    `{"tags":`,
+		},
+		{
+			name:    "no tags",
+			handler: func(h *URLHandler) http.HandlerFunc { return h.AddLinkTags },
+			path:    "/api/user/urls/abc123/tags",
+			body:    `{"tags":[]}`,
+		},
+		{
+			name:    "blank tags",
+			handler: func(h *URLHandler) http.HandlerFunc { return h.RemoveLinkTags },
+			path:    "/api/user/urls/abc123/tags",
+			body:    `{"tags":["  "]}`,
+		},
+		{
+			name:    "tag too long",
+			handler: func(h *URLHandler) http.HandlerFunc { return h.AddLinkTags },
+			path:    "/api/user/urls/abc123/tags",
+			body:    `{"tags":["` + strings.Repeat("a", 65) + `"]}`,
+		},
+		{
+			name:    "folder too long",
+			handler: func(h *URLHandler) http.HandlerFunc { return h.SetLinkFolder },
+			path:    "/api/user/urls/abc123/folder",
+			body:    `{"folder":"` + strings.Repeat("a", 129) + `"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAuth := new(mocks.Authenticator)
+			mockAuth.On("CookieGetUserID", mock.Anything, mock.Anything).Return("user123", nil)
+			mockStorage := new(mocks.Storage)
+
+			h := &URLHandler{
+				Auth:    mockAuth,
+				Storage: mockStorage,
+				Logger:  logger,
+			}
+
+			w := httptest.NewRecorder()
+			tt.handler(h)(w, httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body)))
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			mockStorage.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLinkTags_StorageErrors(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+
+	tests := []struct {
+		storageErr     error
+		name           string
+		expectedStatus int
+	}{
+		{name: "gone", storageErr: shared.ErrGone, expectedStatus: http.StatusGone},
+		{name: "too many tags", storageErr: shared.ErrTooManyTags, expectedStatus: http.StatusBadRequest},
+		{name: "database error", storageErr: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAuth := new(mocks.Authenticator)
+			mockAuth.On("CookieGetUserID", mock.Anything, mock.Anything).Return("user123", nil)
+			mockStorage := new(mocks.Storage)
+			mockStorage.On("AddLinkTags", mock.Anything, "abc123", "user123", []string{"promo"}).Return(tt.storageErr)
+
+			h := &URLHandler{
+				Auth:    mockAuth,
+				Storage: mockStorage,
+				Logger:  logger,
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/api/user/urls/abc123/tags", strings.NewReader(`{"tags":["promo"]}`))
+			h.AddLinkTags(w, r)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockStorage.AssertExpectations(t)
+		})
+	}
+}
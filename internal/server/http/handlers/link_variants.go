@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+
+	"github.com/apetsko/shortugo/internal/auth"
+	"github.com/apetsko/shortugo/internal/models"
+	"github.com/apetsko/shortugo/internal/routing"
+	"github.com/apetsko/shortugo/internal/storages/shared"
+	"github.com/apetsko/shortugo/internal/utils"
+)
+
+// variantsRequest is the body of PutLinkVariants.
+type variantsRequest struct {
+	Variants []models.Variant `json:"variants" validate:"omitempty,min=2,max=16,unique=Name,dive"`
+}
+
+// variantStats is a variant of a link together with its click counter.
+type variantStats struct {
+	models.Variant
+	Clicks int64 `json:"clicks"`
+}
+
+// variantsResponse is the response of the variants endpoints.
+type variantsResponse struct {
+	Variants []variantStats `json:"variants"`
+}
+
+// destination returns the destination of rec for the client of r and the name of the A/B variant it belongs to, if any.
+// Routing rules are tried first; visitors no rule matches take part in the A/B test of the link.
+func (h *URLHandler) destination(w http.ResponseWriter, r *http.Request, rec *models.URLRecord) (string, string) {
+	if len(rec.Rules) > 0 {
+		// The answer depends on the client
+		w.Header().Add("Vary", "User-Agent, Accept-Language")
+		if dest, ok := routing.Match(rec.Rules, h.visitor(r)); ok {
+			return dest, ""
+		}
+	}
+
+	if len(rec.Variants) > 0 {
+		v := h.chooseVariant(w, r, rec)
+		return v.URL, v.Name
+	}
+
+	return rec.URL, ""
+}
+
+// chooseVariant returns the A/B variant the visitor is sent to.
+// A visitor keeps the variant remembered in the cookie of the link as long as the link still has it;
+// everybody else gets a variant chosen by weight, which is then remembered.
+func (h *URLHandler) chooseVariant(w http.ResponseWriter, r *http.Request, rec *models.URLRecord) models.Variant {
+	if name, ok := auth.VariantCookie(r, h.Secret, rec.ID); ok {
+		for _, v := range rec.Variants {
+			if v.Name == name {
+				return v
+			}
+		}
+	}
+
+	v := routing.PickVariant(rec.Variants, rand.IntN(routing.TotalWeight(rec.Variants)))
+	if err := auth.SetVariantCookie(w, h.Secret, rec.ID, v.Name); err != nil {
+		// The visitor is still redirected, it just may get another variant next time
+		h.Logger.Error("failed to set variant cookie", "id", rec.ID, "error", err.Error())
+	}
+	return v
+}
+
+// GetLinkVariants returns the A/B variants of a link owned by the user with their click counters.
+//
+// Request:
+//   - Method: GET
+//   - URL: /api/user/urls/{id}/variants
+//
+// Response:
+//   - 200 OK: {"variants": [{"name": "a", "url": "https://example.com/a", "weight": 50, "clicks": 12}]}
+//   - 404 Not Found: The link does not exist or belongs to another user.
+//   - 410 Gone: The link is deleted.
+//   - 500 Internal Server Error: User authentication failed or other server error.
+func (h *URLHandler) GetLinkVariants(w http.ResponseWriter, r *http.Request) {
+	// Retrieve the user ID from the cookie
+	userID, err := h.Auth.CookieGetUserID(r, h.Secret)
+	if err != nil {
+		// If the user ID is not found, set a new one
+		userID, err = h.Auth.CookieSetUserID(w, h.Secret)
+		if err != nil {
+			h.Logger.Error(err.Error())
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	// Extract the ID from the URL path
+	ID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/user/urls/"), "/variants")
+
+	rec, err := h.Storage.GetRecord(r.Context(), ID)
+	if err == nil && rec.UserID != userID {
+		err = shared.ErrNotFound
+	}
+	if err != nil {
+		h.writeLinkError(w, "Failed to get link variants", err)
+		return
+	}
+
+	h.writeVariants(w, r, ID, rec.Variants)
+}
+
+// PutLinkVariants replaces the A/B variants of a link owned by the user.
+// Visitors no routing rule matches are split between the variants by weight and keep their variant
+// through a cookie. Counters are kept by variant name, so renaming a variant starts a new counter.
+//
+// Request:
+//   - Method: PUT
+//   - URL: /api/user/urls/{id}/variants
+//   - Headers: Content-Type: application/json
+//   - Body: {"variants": [{"name": "a", "url": "https://example.com/a", "weight": 50},
+//     {"name": "b", "url": "https://example.com/b", "weight": 50}]}
+//     2 to 16 variants with unique names and weights from 1 to 1000; an empty list ends the experiment.
+//
+// Response:
+//   - 200 OK: The stored variants with their click counters.
+//   - 400 Bad Request: Invalid request body or variants.
+//   - 404 Not Found: The link does not exist or belongs to another user.
+//   - 410 Gone: The link is deleted.
+//   - 500 Internal Server Error: User authentication failed or other server error.
+func (h *URLHandler) PutLinkVariants(w http.ResponseWriter, r *http.Request) {
+	// Retrieve the user ID from the cookie
+	userID, err := h.Auth.CookieGetUserID(r, h.Secret)
+	if err != nil {
+		// If the user ID is not found, set a new one
+		userID, err = h.Auth.CookieSetUserID(w, h.Secret)
+		if err != nil {
+			h.Logger.Error(err.Error())
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	// Extract the ID from the URL path
+	ID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/user/urls/"), "/variants")
+
+	// Ensure the request body is closed after reading
+	defer func() {
+		if err2 := r.Body.Close(); err2 != nil {
+			h.Logger.Error("Failed to close request body", "error", err2.Error())
+		}
+	}()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	var req variantsRequest
+	if err = json.Unmarshal(body, &req); err != nil {
+		h.Logger.Info("Error unmarshaling request body", "error", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// An empty list ends the experiment; the validator only skips nil slices
+	if len(req.Variants) == 0 {
+		req.Variants = nil
+	}
+
+	if err = utils.ValidateStruct(req); err != nil {
+		h.Logger.Info("Invalid link variants", "error", err.Error())
+		http.Error(w, "Invalid variants", http.StatusBadRequest)
+		return
+	}
+
+	err = h.Storage.UpdateLinkOptions(r.Context(), ID, userID, func(o *models.LinkOptions) error {
+		o.Variants = req.Variants
+		return nil
+	})
+	if err != nil {
+		h.writeLinkError(w, "Failed to update link variants", err)
+		return
+	}
+	h.auditUpdate(r, userID, ID, "variants")
+
+	h.writeVariants(w, r, ID, req.Variants)
+}
+
+// writeVariants writes variants with their click counters as the JSON response of the variants endpoints.
+func (h *URLHandler) writeVariants(w http.ResponseWriter, r *http.Request, id string, variants []models.Variant) {
+	resp := variantsResponse{Variants: make([]variantStats, 0, len(variants))}
+	if len(variants) > 0 {
+		clicks, err := h.Storage.VariantClicks(r.Context(), id)
+		if err != nil {
+			h.writeLinkError(w, "Failed to get variant clicks", err)
+			return
+		}
+		for _, v := range variants {
+			resp.Variants = append(resp.Variants, variantStats{Variant: v, Clicks: clicks[v.Name]})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(resp); err != nil {
+		h.Logger.Error("Error marshaling link variants", "error", err.Error())
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if _, err := buf.WriteTo(w); err != nil {
+		h.Logger.Error(err.Error())
+	}
+}
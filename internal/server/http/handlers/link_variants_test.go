@@ -0,0 +1,318 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/apetsko/shortugo/internal/auth"
+	"github.com/apetsko/shortugo/internal/logging"
+	"github.com/apetsko/shortugo/internal/mocks"
+	"github.com/apetsko/shortugo/internal/models"
+	"github.com/apetsko/shortugo/internal/storages/inmem"
+	"github.com/apetsko/shortugo/internal/storages/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// variantCookie returns the cookie that sends a visitor of abc123 to variant.
+func variantCookie(t *testing.T, secret, variant string) *http.Cookie {
+	w := httptest.NewRecorder()
+	require.NoError(t, auth.SetVariantCookie(w, secret, "abc123", variant))
+
+	resp := w.Result()
+	require.NoError(t, resp.Body.Close())
+	return resp.Cookies()[0]
+}
+
+func TestExpandURL_Variants(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+	ctx := context.Background()
+
+	storage := inmem.New()
+	require.NoError(t, storage.Put(ctx, models.URLRecord{
+		ID:     "abc123",
+		URL:    "https://example.com",
+		UserID: "user123",
+		LinkOptions: models.LinkOptions{
+			Rules: []models.RoutingRule{{Platform: models.PlatformIOS, URL: "https://apps.apple.com/app/id1"}},
+			Variants: []models.Variant{
+				{Name: "a", URL: "https://example.com/a", Weight: 1},
+				{Name: "b", URL: "https://example.com/b", Weight: 3},
+			},
+		},
+	}))
+
+	h := &URLHandler{
+		Storage: storage,
+		Logger:  logger,
+		Secret:  "secret",
+	}
+
+	expand := func(r *http.Request) *http.Response {
+		w := httptest.NewRecorder()
+		h.ExpandURL(w, r)
+		resp := w.Result()
+		require.NoError(t, resp.Body.Close())
+		return resp
+	}
+
+	t.Run("weighted split", func(t *testing.T) {
+		const visitors = 400
+		seen := map[string]int{}
+		for range visitors {
+			resp := expand(httptest.NewRequest(http.MethodGet, "/abc123", nil))
+			require.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode)
+			assert.Equal(t, "no-store", resp.Header.Get("Cache-Control"))
+			require.Len(t, resp.Cookies(), 1)
+			seen[resp.Header.Get("Location")]++
+		}
+
+		assert.Len(t, seen, 2)
+		assert.InDelta(t, visitors/4, seen["https://example.com/a"], visitors/8)
+
+		clicks, err := storage.VariantClicks(ctx, "abc123")
+		require.NoError(t, err)
+		assert.Equal(t, int64(seen["https://example.com/a"]), clicks["a"])
+		assert.Equal(t, int64(seen["https://example.com/b"]), clicks["b"])
+	})
+
+	t.Run("sticky cookie", func(t *testing.T) {
+		cookie := variantCookie(t, h.Secret, "a")
+
+		for range 20 {
+			r := httptest.NewRequest(http.MethodGet, "/abc123", nil)
+			r.AddCookie(cookie)
+			resp := expand(r)
+			assert.Equal(t, "https://example.com/a", resp.Header.Get("Location"))
+			assert.Empty(t, resp.Cookies())
+		}
+	})
+
+	t.Run("cookie of removed variant", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/abc123", nil)
+		r.AddCookie(variantCookie(t, h.Secret, "c"))
+		resp := expand(r)
+		assert.Contains(t, []string{"https://example.com/a", "https://example.com/b"}, resp.Header.Get("Location"))
+		assert.Len(t, resp.Cookies(), 1)
+	})
+
+	t.Run("matching rule wins", func(t *testing.T) {
+		before, err := storage.VariantClicks(ctx, "abc123")
+		require.NoError(t, err)
+
+		r := httptest.NewRequest(http.MethodGet, "/abc123", nil)
+		r.Header.Set("User-Agent", iPhoneUA)
+		resp := expand(r)
+		assert.Equal(t, "https://apps.apple.com/app/id1", resp.Header.Get("Location"))
+		assert.Empty(t, resp.Cookies())
+
+		after, err := storage.VariantClicks(ctx, "abc123")
+		require.NoError(t, err)
+		assert.Equal(t, before, after)
+	})
+}
+
+func TestExpandURL_VariantCountError(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+
+	mockStorage := new(mocks.Storage)
+	mockStorage.On("GetRecord", mock.Anything, "abc123").Return(&models.URLRecord{
+		ID:  "abc123",
+		URL: "https://example.com",
+		LinkOptions: models.LinkOptions{Variants: []models.Variant{
+			{Name: "a", URL: "https://example.com/a", Weight: 1},
+			{Name: "b", URL: "https://example.com/a", Weight: 1},
+		}},
+	}, nil)
+	mockStorage.On("CountVariantClick", mock.Anything, "abc123", mock.Anything).Return(errors.New("database error"))
+
+	h := &URLHandler{
+		Storage: mockStorage,
+		Logger:  logger,
+	}
+
+	w := httptest.NewRecorder()
+	h.ExpandURL(w, httptest.NewRequest(http.MethodGet, "/abc123", nil))
+
+	// The visitor is redirected even if the click is not counted
+	assert.Equal(t, http.StatusTemporaryRedirect, w.Code)
+	assert.Equal(t, "https://example.com/a", w.Header().Get("Location"))
+	mockStorage.AssertExpectations(t)
+}
+
+func TestGetLinkVariants(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+	variants := []models.Variant{
+		{Name: "a", URL: "https://example.com/a", Weight: 70},
+		{Name: "b", URL: "https://example.com/b", Weight: 30},
+	}
+
+	tests := []struct {
+		record         *models.URLRecord
+		storageErr     error
+		clicksErr      error
+		name           string
+		expectedBody   string
+		expectedStatus int
+	}{
+		{
+			name:           "variants set",
+			record:         &models.URLRecord{ID: "abc123", UserID: "user123", LinkOptions: models.LinkOptions{Variants: variants}},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"variants":[{"name":"a","url":"https://example.com/a","weight":70,"clicks":12},` +
+				`{"name":"b","url":"https://example.com/b","weight":30,"clicks":0}]}`,
+		},
+		{
+			name:           "no variants",
+			record:         &models.URLRecord{ID: "abc123", UserID: "user123"},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"variants":[]}`,
+		},
+		{
+			name:           "foreign link",
+			record:         &models.URLRecord{ID: "abc123", UserID: "other", LinkOptions: models.LinkOptions{Variants: variants}},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "deleted link",
+			storageErr:     shared.ErrGone,
+			expectedStatus: http.StatusGone,
+		},
+		{
+			name:           "counter error",
+			record:         &models.URLRecord{ID: "abc123", UserID: "user123", LinkOptions: models.LinkOptions{Variants: variants}},
+			clicksErr:      errors.New("database error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAuth := new(mocks.Authenticator)
+			mockAuth.On("CookieGetUserID", mock.Anything, mock.Anything).Return("user123", nil)
+			mockStorage := new(mocks.Storage)
+			mockStorage.On("GetRecord", mock.Anything, "abc123").Return(tt.record, tt.storageErr)
+			mockStorage.On("VariantClicks", mock.Anything, "abc123").Return(map[string]int64{"a": 12}, tt.clicksErr).Maybe()
+
+			h := &URLHandler{
+				Auth:    mockAuth,
+				Storage: mockStorage,
+				Logger:  logger,
+			}
+
+			w := httptest.NewRecorder()
+			h.GetLinkVariants(w, httptest.NewRequest(http.MethodGet, "/api/user/urls/abc123/variants", nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestPutLinkVariants(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+
+	tests := []struct {
+		storageErr       error
+		name             string
+		body             string
+		expectedBody     string
+		expectedVariants []models.Variant
+		expectedStatus   int
+		callsStorage     bool
+	}{
+		{
+			name:         "variants stored",
+			body:         `{"variants":[{"name":"a","url":"https://example.com/a","weight":1},{"name":"b","url":"https://example.com/b","weight":3}]}`,
+			callsStorage: true,
+			expectedVariants: []models.Variant{
+				{Name: "a", URL: "https://example.com/a", Weight: 1},
+				{Name: "b", URL: "https://example.com/b", Weight: 3},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"variants":[{"name":"a","url":"https://example.com/a","weight":1,"clicks":5},` +
+				`{"name":"b","url":"https://example.com/b","weight":3,"clicks":0}]}`,
+		},
+		{
+			name:           "experiment ended",
+			body:           `{"variants":[]}`,
+			callsStorage:   true,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"variants":[]}`,
+		},
+		{
+			name:           "invalid JSON",
+			body:           `{"variants":`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "single variant",
+			body:           `{"variants":[{"name":"a","url":"https://example.com/a","weight":1}]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "duplicate names",
+			body:           `{"variants":[{"name":"a","url":"https://example.com/a","weight":1},{"name":"a","url":"https://example.com/b","weight":1}]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "zero weight",
+			body:           `{"variants":[{"name":"a","url":"https://example.com/a","weight":1},{"name":"b","url":"https://example.com/b","weight":0}]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid URL",
+			body:           `{"variants":[{"name":"a","url":"https://example.com/a","weight":1},{"name":"b","url":"not a url","weight":1}]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "foreign link",
+			body:           `{"variants":[]}`,
+			storageErr:     shared.ErrNotFound,
+			callsStorage:   true,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAuth := new(mocks.Authenticator)
+			mockAuth.On("CookieGetUserID", mock.Anything, mock.Anything).Return("user123", nil)
+
+			var opts models.LinkOptions
+			mockStorage := new(mocks.Storage)
+			if tt.callsStorage {
+				mockStorage.On("UpdateLinkOptions", mock.Anything, "abc123", "user123", mock.Anything).
+					Run(func(args mock.Arguments) {
+						_ = args.Get(3).(func(o *models.LinkOptions) error)(&opts)
+					}).Return(tt.storageErr)
+			}
+			mockStorage.On("VariantClicks", mock.Anything, "abc123").Return(map[string]int64{"a": 5}, nil).Maybe()
+
+			h := &URLHandler{
+				Auth:    mockAuth,
+				Storage: mockStorage,
+				Logger:  logger,
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPut, "/api/user/urls/abc123/variants", strings.NewReader(tt.body))
+			h.PutLinkVariants(w, r)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockStorage.AssertExpectations(t)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, tt.expectedVariants, opts.Variants)
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
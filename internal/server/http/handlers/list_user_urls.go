@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/apetsko/shortugo/internal/models"
 	"github.com/apetsko/shortugo/internal/storages/shared"
@@ -13,6 +14,7 @@ import (
 // ListUserURLs handles the request to list all URLs associated with a user.
 // It retrieves the user ID from the request's cookie or sets a new one if not found.
 // Then, it fetches the list of URLs associated with the user ID from the storage and returns them in the response.
+// The optional tag and folder query parameters narrow the list to the links with that tag and in that folder.
 func (h *URLHandler) ListUserURLs(w http.ResponseWriter, r *http.Request) {
 	// Retrieve the user ID from the cookie
 	userID, err := h.Auth.CookieGetUserID(r, h.Secret)
@@ -28,7 +30,12 @@ func (h *URLHandler) ListUserURLs(w http.ResponseWriter, r *http.Request) {
 	// Get the context from the request
 	ctx := r.Context()
 	// Fetch the list of URLs associated with the user ID from the storage
-	records, err := h.Storage.ListLinksByUserID(ctx, h.BaseURL, userID)
+	var records []models.URLRecord
+	if filter := listFilter(r); filter.IsZero() {
+		records, err = h.Storage.ListLinksByUserID(ctx, h.BaseURL, userID)
+	} else {
+		records, err = h.Storage.ListLinksByFilter(ctx, h.BaseURL, userID, filter)
+	}
 	if err != nil {
 		// Handle the case where no URLs are found for the user
 		if errors.Is(err, shared.ErrNotFound) {
@@ -43,13 +50,7 @@ func (h *URLHandler) ListUserURLs(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Prepare the list of user URLs for the response
-	var userURLs = make([]models.UserURL, 0, len(records))
-	for _, record := range records {
-		userURLs = append(userURLs, models.UserURL{
-			ShortURL:    record.ID,
-			OriginalURL: record.URL,
-		})
-	}
+	userURLs := toUserURLs(records)
 
 	// Marshal the list of user URLs into JSON
 	var buf bytes.Buffer
@@ -68,3 +69,27 @@ func (h *URLHandler) ListUserURLs(w http.ResponseWriter, r *http.Request) {
 		h.Logger.Error(err.Error())
 	}
 }
+
+// listFilter reads the tag and folder query parameters of ListUserURLs.
+func listFilter(r *http.Request) models.LinkFilter {
+	q := r.URL.Query()
+	return models.LinkFilter{
+		Tag:    strings.ToLower(strings.TrimSpace(q.Get("tag"))),
+		Folder: strings.TrimSpace(q.Get("folder")),
+	}
+}
+
+// toUserURLs converts listed records to the user URLs of a listing response.
+func toUserURLs(records []models.URLRecord) []models.UserURL {
+	userURLs := make([]models.UserURL, 0, len(records))
+	for _, record := range records {
+		userURLs = append(userURLs, models.UserURL{
+			ShortURL:    record.ID,
+			OriginalURL: record.URL,
+			Title:       record.Title,
+			Folder:      record.Folder,
+			Tags:        record.Tags,
+		})
+	}
+	return userURLs
+}
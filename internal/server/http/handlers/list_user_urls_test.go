@@ -151,3 +151,31 @@ func TestListUserURLs(t *testing.T) {
 		})
 	}
 }
+
+func TestListUserURLs_Filter(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+
+	mockAuth := new(mocks.Authenticator)
+	mockAuth.On("CookieGetUserID", mock.Anything, mock.Anything).Return("user123", nil)
+
+	mockStorage := new(mocks.Storage)
+	mockStorage.On("ListLinksByFilter", mock.Anything, "http://short.ly", "user123", models.LinkFilter{Tag: "promo", Folder: "Campaigns"}).
+		Return([]models.URLRecord{
+			{ID: "short1", URL: "http://example.com", UserID: "user123", Folder: "Campaigns", Tags: []string{"2026", "promo"}},
+		}, nil)
+
+	h := &URLHandler{
+		Auth:    mockAuth,
+		Storage: mockStorage,
+		Logger:  logger,
+		BaseURL: "http://short.ly",
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/api/user/urls?tag=%20Promo&folder=Campaigns", nil)
+	h.ListUserURLs(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[{"short_url":"short1","original_url":"http://example.com","folder":"Campaigns","tags":["2026","promo"]}]`, w.Body.String())
+	mockStorage.AssertExpectations(t)
+}
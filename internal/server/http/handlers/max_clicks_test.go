@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/apetsko/shortugo/internal/logging"
+	"github.com/apetsko/shortugo/internal/mocks"
+	"github.com/apetsko/shortugo/internal/models"
+	"github.com/apetsko/shortugo/internal/storages/inmem"
+	"github.com/apetsko/shortugo/internal/storages/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestExpandURL_MaxClicks(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+
+	tests := []struct {
+		consumeErr     error
+		name           string
+		expectedStatus int
+	}{
+		{
+			name:           "click left",
+			expectedStatus: http.StatusTemporaryRedirect,
+		},
+		{
+			name:           "clicks used up meanwhile",
+			consumeErr:     shared.ErrGone,
+			expectedStatus: http.StatusGone,
+		},
+		{
+			name:           "storage error",
+			consumeErr:     errors.New("database error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := new(mocks.Storage)
+			mockStorage.On("GetRecord", mock.Anything, "abc123").Return(&models.URLRecord{
+				ID:          "abc123",
+				URL:         "https://example.com",
+				ClicksLeft:  1,
+				LinkOptions: models.LinkOptions{MaxClicks: 3},
+			}, nil)
+			mockStorage.On("ConsumeClick", mock.Anything, "abc123").Return(tt.consumeErr).Once()
+
+			h := &URLHandler{
+				Storage: mockStorage,
+				Logger:  logger,
+			}
+
+			w := httptest.NewRecorder()
+			h.ExpandURL(w, httptest.NewRequest(http.MethodGet, "/abc123", nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockStorage.AssertExpectations(t)
+		})
+	}
+}
+
+func TestExpandURL_MaxClicks_Concurrent(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+	storage := inmem.New()
+
+	const maxClicks, visitors = 3, 50
+	require.NoError(t, storage.Put(context.Background(), models.URLRecord{
+		ID:          "invite",
+		URL:         "https://example.com/join",
+		ClicksLeft:  maxClicks,
+		LinkOptions: models.LinkOptions{MaxClicks: maxClicks},
+	}))
+
+	h := &URLHandler{
+		Storage: storage,
+		Logger:  logger,
+	}
+
+	var (
+		mu    sync.Mutex
+		codes = make(map[int]int)
+		wg    sync.WaitGroup
+	)
+	for range visitors {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w := httptest.NewRecorder()
+			h.ExpandURL(w, httptest.NewRequest(http.MethodGet, "/invite", nil))
+
+			mu.Lock()
+			codes[w.Code]++
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, map[int]int{
+		http.StatusTemporaryRedirect: maxClicks,
+		http.StatusGone:              visitors - maxClicks,
+	}, codes)
+}
+
+func TestShortenJSON_MaxClicks(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+
+	mockAuth := new(mocks.Authenticator)
+	mockAuth.On("CookieGetUserID", mock.Anything, mock.Anything).Return("user123", nil)
+	mockStorage := new(mocks.Storage)
+	mockStorage.On("GetUTMTemplate", mock.Anything, "user123").Return(nil, shared.ErrNotFound)
+	mockStorage.On("Get", mock.Anything, mock.Anything).Return("", shared.ErrNotFound)
+	// Remaining clicks sent by the client are replaced by max_clicks
+	mockStorage.On("Put", mock.Anything, mock.MatchedBy(func(r models.URLRecord) bool {
+		return r.MaxClicks == 5 && r.ClicksLeft == 5
+	})).Return(nil)
+
+	h := &URLHandler{
+		Auth:    mockAuth,
+		Storage: mockStorage,
+		Logger:  logger,
+		BaseURL: "http://short.ly",
+	}
+
+	body := `{"url":"https://example.com","max_clicks":5,"clicks_left":1000}`
+	w := httptest.NewRecorder()
+	h.ShortenJSON(w, httptest.NewRequest(http.MethodPost, "/api/shorten", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockStorage.AssertExpectations(t)
+
+	w = httptest.NewRecorder()
+	h.ShortenJSON(w, httptest.NewRequest(http.MethodPost, "/api/shorten", strings.NewReader(`{"url":"https://example.com","max_clicks":-1}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/apetsko/shortugo/internal/models"
+)
+
+// errInvalidExtraPath is returned when the forwarded path tries to leave the destination path.
+var errInvalidExtraPath = errors.New("extra path must not contain dot segments")
+
+// queryPair is a single key=value pair of a query string.
+// The raw form is kept so destination parameters are forwarded exactly as they were stored.
+type queryPair struct {
+	key string // Decoded key, used to detect conflicts.
+	raw string // Encoded "key=value" form.
+}
+
+// TaggedDestination returns dest with the UTM tags of the link, as a redirect without extra path
+// or query sends visitors there. Other transports report it as the destination of the link.
+func TaggedDestination(dest string, opts models.LinkOptions) (string, error) {
+	return applyPassthrough(dest, "", "", opts)
+}
+
+// applyPassthrough extends the destination with the UTM tags of the link, then with the extra path
+// and the query of the incoming request, as allowed by the link options.
+// UTM tags replace same-named destination parameters; the request query is merged after them.
+// extraPath and rawQuery must be in their escaped form.
+// The destination fragment is preserved and stays at the end of the resulting URL.
+func applyPassthrough(dest, extraPath, rawQuery string, opts models.LinkOptions) (string, error) {
+	tagged := !opts.UTM.IsZero()
+	forwardPath := opts.ForwardPath && extraPath != ""
+	forwardQuery := opts.QueryPolicy != "" && rawQuery != ""
+	if !tagged && !forwardPath && !forwardQuery {
+		return dest, nil
+	}
+
+	u, err := url.Parse(dest)
+	if err != nil {
+		return "", err
+	}
+
+	if forwardPath && u.Opaque == "" {
+		if err := joinPath(u, extraPath); err != nil {
+			return "", err
+		}
+	}
+
+	if tagged {
+		u.RawQuery = mergeQuery(u.RawQuery, opts.UTM.Encode(), models.QueryPolicyRequest)
+		u.ForceQuery = false
+	}
+
+	if forwardQuery {
+		u.RawQuery = mergeQuery(u.RawQuery, rawQuery, opts.QueryPolicy)
+		u.ForceQuery = false
+	}
+
+	return u.String(), nil
+}
+
+// joinPath appends the escaped extra path to the path of u, keeping the existing escaping intact.
+func joinPath(u *url.URL, extraPath string) error {
+	for _, segment := range strings.Split(extraPath, "/") {
+		decoded, err := url.PathUnescape(segment)
+		if err != nil {
+			return err
+		}
+		if decoded == "." || decoded == ".." {
+			return errInvalidExtraPath
+		}
+	}
+
+	escaped := strings.TrimSuffix(u.EscapedPath(), "/") + "/" + extraPath
+	decoded, err := url.PathUnescape(escaped)
+	if err != nil {
+		return err
+	}
+
+	u.Path = decoded
+	u.RawPath = escaped
+	return nil
+}
+
+// mergeQuery merges the request query into the destination query according to policy.
+// Parameter order is preserved: destination parameters first, then the request parameters.
+func mergeQuery(destQuery, reqQuery, policy string) string {
+	dest := parseQuery(destQuery, false)
+	req := parseQuery(reqQuery, true)
+
+	var merged []queryPair
+	switch policy {
+	case models.QueryPolicyDestination:
+		keys := pairKeys(dest)
+		merged = dest
+		for _, p := range req {
+			if _, ok := keys[p.key]; !ok {
+				merged = append(merged, p)
+			}
+		}
+	case models.QueryPolicyRequest:
+		keys := pairKeys(req)
+		for _, p := range dest {
+			if _, ok := keys[p.key]; !ok {
+				merged = append(merged, p)
+			}
+		}
+		merged = append(merged, req...)
+	default:
+		merged = append(dest, req...)
+	}
+
+	raw := make([]string, len(merged))
+	for i, p := range merged {
+		raw[i] = p.raw
+	}
+	return strings.Join(raw, "&")
+}
+
+// parseQuery splits a raw query into pairs, skipping empty ones.
+// When normalize is set, pairs are re-encoded and pairs with invalid escapes are dropped,
+// so untrusted input always ends up correctly encoded.
+func parseQuery(rawQuery string, normalize bool) []queryPair {
+	var pairs []queryPair
+	for _, part := range strings.Split(rawQuery, "&") {
+		if part == "" {
+			continue
+		}
+
+		rawKey, rawValue, hasValue := strings.Cut(part, "=")
+		key, err := url.QueryUnescape(rawKey)
+		if err != nil {
+			if normalize {
+				continue
+			}
+			key = rawKey
+		}
+
+		if !normalize {
+			pairs = append(pairs, queryPair{key: key, raw: part})
+			continue
+		}
+
+		value, err := url.QueryUnescape(rawValue)
+		if err != nil {
+			continue
+		}
+		raw := url.QueryEscape(key)
+		if hasValue {
+			raw += "=" + url.QueryEscape(value)
+		}
+		pairs = append(pairs, queryPair{key: key, raw: raw})
+	}
+	return pairs
+}
+
+// pairKeys returns the set of keys present in pairs.
+func pairKeys(pairs []queryPair) map[string]struct{} {
+	keys := make(map[string]struct{}, len(pairs))
+	for _, p := range pairs {
+		keys[p.key] = struct{}{}
+	}
+	return keys
+}
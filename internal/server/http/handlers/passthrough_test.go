@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/apetsko/shortugo/internal/logging"
+	"github.com/apetsko/shortugo/internal/mocks"
+	"github.com/apetsko/shortugo/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestApplyPassthrough(t *testing.T) {
+	tests := []struct {
+		name      string
+		dest      string
+		extraPath string
+		rawQuery  string
+		want      string
+		opts      models.LinkOptions
+		wantErr   bool
+	}{
+		{
+			name:     "query dropped without policy",
+			dest:     "https://example.com/page?a=1",
+			rawQuery: "utm_source=mail",
+			want:     "https://example.com/page?a=1",
+		},
+		{
+			name:     "query added to destination without query",
+			dest:     "https://example.com/page",
+			rawQuery: "utm_source=mail&utm_medium=email",
+			opts:     models.LinkOptions{QueryPolicy: models.QueryPolicyDestination},
+			want:     "https://example.com/page?utm_source=mail&utm_medium=email",
+		},
+		{
+			name:     "fragment stays after merged query",
+			dest:     "https://example.com/page?a=1#section-2",
+			rawQuery: "b=2",
+			opts:     models.LinkOptions{QueryPolicy: models.QueryPolicyAppend},
+			want:     "https://example.com/page?a=1&b=2#section-2",
+		},
+		{
+			name:     "fragment without query",
+			dest:     "https://example.com/app#/route?x=1",
+			rawQuery: "b=2",
+			opts:     models.LinkOptions{QueryPolicy: models.QueryPolicyAppend},
+			want:     "https://example.com/app?b=2#/route?x=1",
+		},
+		{
+			name:     "conflict keeps destination values",
+			dest:     "https://example.com/?a=1&a=2&c=3",
+			rawQuery: "a=9&b=8&a=7",
+			opts:     models.LinkOptions{QueryPolicy: models.QueryPolicyDestination},
+			want:     "https://example.com/?a=1&a=2&c=3&b=8",
+		},
+		{
+			name:     "conflict replaces destination values",
+			dest:     "https://example.com/?a=1&a=2&c=3",
+			rawQuery: "a=9&b=8&a=7",
+			opts:     models.LinkOptions{QueryPolicy: models.QueryPolicyRequest},
+			want:     "https://example.com/?c=3&a=9&b=8&a=7",
+		},
+		{
+			name:     "conflict keeps both values",
+			dest:     "https://example.com/?a=1",
+			rawQuery: "a=1&a=2",
+			opts:     models.LinkOptions{QueryPolicy: models.QueryPolicyAppend},
+			want:     "https://example.com/?a=1&a=1&a=2",
+		},
+		{
+			name:     "conflict detected on decoded keys",
+			dest:     "https://example.com/?a%20b=1",
+			rawQuery: "a+b=2",
+			opts:     models.LinkOptions{QueryPolicy: models.QueryPolicyRequest},
+			want:     "https://example.com/?a+b=2",
+		},
+		{
+			name:     "request values are re-encoded",
+			dest:     "https://example.com/",
+			rawQuery: "q=a b&next=/x?y=1&tag=%23go&name=%D0%B8%D0%BC%D1%8F",
+			opts:     models.LinkOptions{QueryPolicy: models.QueryPolicyAppend},
+			want:     "https://example.com/?q=a+b&next=%2Fx%3Fy%3D1&tag=%23go&name=%D0%B8%D0%BC%D1%8F",
+		},
+		{
+			name:     "destination encoding kept verbatim",
+			dest:     "https://example.com/?redirect=https%3A%2F%2Fa.com%2F&flag",
+			rawQuery: "b=2",
+			opts:     models.LinkOptions{QueryPolicy: models.QueryPolicyDestination},
+			want:     "https://example.com/?redirect=https%3A%2F%2Fa.com%2F&flag&b=2",
+		},
+		{
+			name:     "keys without values and empty pairs",
+			dest:     "https://example.com/",
+			rawQuery: "debug&&x=",
+			opts:     models.LinkOptions{QueryPolicy: models.QueryPolicyAppend},
+			want:     "https://example.com/?debug&x=",
+		},
+		{
+			name:     "invalid escapes are dropped",
+			dest:     "https://example.com/",
+			rawQuery: "a=%zz&b=2",
+			opts:     models.LinkOptions{QueryPolicy: models.QueryPolicyAppend},
+			want:     "https://example.com/?b=2",
+		},
+		{
+			name: "UTM tags added",
+			dest: "https://example.com/page?a=1#top",
+			opts: models.LinkOptions{UTM: models.UTM{Source: "newsletter", Medium: "email", Campaign: "spring sale"}},
+			want: "https://example.com/page?a=1&utm_campaign=spring+sale&utm_medium=email&utm_source=newsletter#top",
+		},
+		{
+			name: "UTM tags replace destination tags",
+			dest: "https://example.com/?utm_source=old&utm_source=older&b=2",
+			opts: models.LinkOptions{UTM: models.UTM{Source: "newsletter"}},
+			want: "https://example.com/?b=2&utm_source=newsletter",
+		},
+		{
+			name:     "UTM tags before request query",
+			dest:     "https://example.com/",
+			rawQuery: "utm_source=visitor&ref=x",
+			opts:     models.LinkOptions{QueryPolicy: models.QueryPolicyDestination, UTM: models.UTM{Source: "newsletter"}},
+			want:     "https://example.com/?utm_source=newsletter&ref=x",
+		},
+		{
+			name:      "path ignored without forwarding",
+			dest:      "https://example.com/docs",
+			extraPath: "guide",
+			want:      "https://example.com/docs",
+		},
+		{
+			name:      "path appended",
+			dest:      "https://example.com/docs",
+			extraPath: "guide/intro",
+			opts:      models.LinkOptions{ForwardPath: true},
+			want:      "https://example.com/docs/guide/intro",
+		},
+		{
+			name:      "path appended after trailing slash",
+			dest:      "https://example.com/docs/",
+			extraPath: "guide",
+			opts:      models.LinkOptions{ForwardPath: true},
+			want:      "https://example.com/docs/guide",
+		},
+		{
+			name:      "path appended to bare host",
+			dest:      "https://example.com",
+			extraPath: "guide",
+			opts:      models.LinkOptions{ForwardPath: true},
+			want:      "https://example.com/guide",
+		},
+		{
+			name:      "escaped segments preserved",
+			dest:      "https://example.com/files/a%2Fb",
+			extraPath: "c%2Fd/e%20f",
+			opts:      models.LinkOptions{ForwardPath: true},
+			want:      "https://example.com/files/a%2Fb/c%2Fd/e%20f",
+		},
+		{
+			name:      "path and query with fragment",
+			dest:      "https://example.com/docs?lang=en#top",
+			extraPath: "guide",
+			rawQuery:  "lang=de&v=2",
+			opts:      models.LinkOptions{ForwardPath: true, QueryPolicy: models.QueryPolicyRequest},
+			want:      "https://example.com/docs/guide?lang=de&v=2#top",
+		},
+		{
+			name:      "dot segments rejected",
+			dest:      "https://example.com/docs",
+			extraPath: "../admin",
+			opts:      models.LinkOptions{ForwardPath: true},
+			wantErr:   true,
+		},
+		{
+			name:      "encoded dot segments rejected",
+			dest:      "https://example.com/docs",
+			extraPath: "%2e%2e/admin",
+			opts:      models.LinkOptions{ForwardPath: true},
+			wantErr:   true,
+		},
+		{
+			name:     "unparsable destination",
+			dest:     "https://exa mple.com/%zz",
+			rawQuery: "a=1",
+			opts:     models.LinkOptions{QueryPolicy: models.QueryPolicyAppend},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := applyPassthrough(tt.dest, tt.extraPath, tt.rawQuery, tt.opts)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestExpandURL_Passthrough(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+
+	tests := []struct {
+		name             string
+		target           string
+		expectedLocation string
+		options          models.LinkOptions
+		expectedStatus   int
+	}{
+		{
+			name:             "query dropped by default",
+			target:           "/abc123?utm_source=mail",
+			expectedStatus:   http.StatusTemporaryRedirect,
+			expectedLocation: "https://example.com/docs?lang=en",
+		},
+		{
+			name:             "query merged",
+			target:           "/abc123?utm_source=mail&lang=de",
+			options:          models.LinkOptions{QueryPolicy: models.QueryPolicyDestination},
+			expectedStatus:   http.StatusTemporaryRedirect,
+			expectedLocation: "https://example.com/docs?lang=en&utm_source=mail",
+		},
+		{
+			name:           "extra path rejected without forwarding",
+			target:         "/abc123/guide",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:             "extra path forwarded with escaping",
+			target:           "/abc123/guide/a%2Fb?x=1",
+			options:          models.LinkOptions{ForwardPath: true, QueryPolicy: models.QueryPolicyAppend},
+			expectedStatus:   http.StatusTemporaryRedirect,
+			expectedLocation: "https://example.com/docs/guide/a%2Fb?lang=en&x=1",
+		},
+		{
+			name:           "dot segments rejected",
+			target:         "/abc123/%2E%2E/admin",
+			options:        models.LinkOptions{ForwardPath: true},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := new(mocks.Storage)
+			mockStorage.On("GetRecord", mock.Anything, "abc123").
+				Return(&models.URLRecord{ID: "abc123", URL: "https://example.com/docs?lang=en", LinkOptions: tt.options}, nil)
+
+			h := &URLHandler{
+				Storage: mockStorage,
+				Logger:  logger,
+			}
+
+			w := httptest.NewRecorder()
+			h.ExpandURL(w, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedLocation, w.Header().Get("Location"))
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apetsko/shortugo/internal/models"
+	"github.com/apetsko/shortugo/internal/storages/shared"
+)
+
+// previewPage describes the destination of a link on the preview page and in its JSON form.
+type previewPage struct {
+	CreatedAt    *time.Time `json:"created_at,omitempty"` // Missing for links stored before creation times were kept.
+	ID           string     `json:"id"`
+	ShortURL     string     `json:"short_url"`
+	URL          string     `json:"url,omitempty"`  // Hidden for password-protected links.
+	Host         string     `json:"host,omitempty"` // Hidden for password-protected links.
+	Action       string     `json:"-"`              // Where the continue button leads.
+	Method       string     `json:"-"`              // How the continue button submits.
+	Protected    bool       `json:"protected"`
+	Interstitial bool       `json:"interstitial"`
+}
+
+// PreviewURL handles requests for the preview of a shortened URL.
+// It shows the destination host, the full destination URL and the creation date of the link
+// with a button continuing to the link, so recipients can check where a link goes before following it.
+// The destination is the one the client would be redirected to, including UTM tags.
+// Password-protected links do not reveal their destination.
+// Showing the preview neither uses up a click of a link limited by max_clicks nor counts for an A/B variant.
+//
+// Request:
+//   - Method: GET
+//   - URL: /{id}+
+//   - Headers: Accept: application/json for the JSON form of the page.
+//
+// Response:
+//   - 200 OK: The preview page, or {"id": "abc123", "short_url": "http://localhost:8080/abc123",
+//     "url": "https://example.com/docs", "host": "example.com", "created_at": "2026-10-19T12:00:00Z",
+//     "protected": false, "interstitial": false}.
+//   - 403 Forbidden: The link leads to a blocked domain.
+//   - 404 Not Found: The link does not exist.
+//   - 410 Gone: The link is deleted or its clicks are used up.
+//   - 500 Internal Server Error: Other server error.
+func (h *URLHandler) PreviewURL(w http.ResponseWriter, r *http.Request) {
+	// Extract the ID from the URL path
+	ID, err := url.PathUnescape(strings.TrimSuffix(strings.TrimPrefix(r.URL.EscapedPath(), "/"), "+"))
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	rec, err := h.Storage.GetRecord(r.Context(), ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, shared.ErrGone):
+			w.WriteHeader(http.StatusGone)
+		case errors.Is(err, shared.ErrNotFound):
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			h.Logger.Error(err.Error())
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Links leading to a blocked domain are not shown either
+	if h.Blocked(rec.URL, rec.LinkOptions) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	// The destination of a protected link is only revealed by its password form
+	target := ""
+	if !rec.Protected() {
+		destination, _ := h.destination(w, r, rec)
+		if target, err = TaggedDestination(destination, rec.LinkOptions); err != nil {
+			h.Logger.Error("failed to build redirect target", "id", ID, "error", err.Error())
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	h.preview(w, r, rec, target, "/"+url.PathEscape(ID))
+}
+
+// preview answers with the preview page of rec leading to target, or with its JSON form if the client prefers JSON.
+// The continue button of an interstitial link POSTs to action, so the visitor is redirected instead of seeing the page again.
+func (h *URLHandler) preview(w http.ResponseWriter, r *http.Request, rec *models.URLRecord, target, action string) {
+	page := previewPage{
+		ID:           rec.ID,
+		ShortURL:     h.BaseURL + "/" + url.PathEscape(rec.ID),
+		URL:          target,
+		Action:       action,
+		Method:       http.MethodGet,
+		Protected:    rec.Protected(),
+		Interstitial: rec.Interstitial,
+	}
+	if u, err := url.Parse(target); err == nil {
+		page.Host = u.Hostname()
+	}
+	if !rec.CreatedAt.IsZero() {
+		page.CreatedAt = &rec.CreatedAt
+	}
+	if rec.Interstitial && !rec.Protected() {
+		page.Method = http.MethodPost
+	}
+
+	var (
+		buf         bytes.Buffer
+		contentType string
+		err         error
+	)
+	w.Header().Add("Vary", "Accept")
+	if prefersJSON(r.Header.Get("Accept")) {
+		contentType = "application/json"
+		err = json.NewEncoder(&buf).Encode(page)
+	} else {
+		contentType = "text/html; charset=utf-8"
+		err = templates.ExecuteTemplate(&buf, "preview.html", page)
+	}
+	if err != nil {
+		h.Logger.Error("failed to render preview", "id", rec.ID, "error", err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	if _, err = buf.WriteTo(w); err != nil {
+		h.Logger.Error(err.Error())
+	}
+}
+
+// prefersJSON reports whether an Accept header ranks application/json above text/html.
+// Browsers ask for text/html first; API clients ask for JSON only.
+func prefersJSON(accept string) bool {
+	var jsonQ, htmlQ float64
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+
+		switch mediaType {
+		case "application/json":
+			jsonQ = max(jsonQ, q)
+		case "text/html":
+			htmlQ = max(htmlQ, q)
+		}
+	}
+	return jsonQ > htmlQ
+}
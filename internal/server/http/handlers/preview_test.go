@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/apetsko/shortugo/internal/logging"
+	"github.com/apetsko/shortugo/internal/mocks"
+	"github.com/apetsko/shortugo/internal/models"
+	"github.com/apetsko/shortugo/internal/storages/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestPreviewURL(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+	created := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		record         *models.URLRecord
+		storageErr     error
+		name           string
+		accept         string
+		expectedBody   string
+		expectedHTML   []string
+		absentHTML     []string
+		expectedStatus int
+	}{
+		{
+			name: "HTML page",
+			record: &models.URLRecord{ID: "abc123", URL: "https://example.com/docs?lang=en", CreatedAt: created,
+				LinkOptions: models.LinkOptions{UTM: models.UTM{Source: "mail"}, MaxClicks: 1}},
+			accept:         "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
+			expectedStatus: http.StatusOK,
+			expectedHTML: []string{
+				"<strong>example.com</strong>",
+				"<code>https://example.com/docs?lang=en&amp;utm_source=mail</code>",
+				`<time datetime="2026-10-19">October 19, 2026</time>`,
+				`<form method="GET" action="/abc123">`,
+			},
+		},
+		{
+			name:           "JSON",
+			record:         &models.URLRecord{ID: "abc123", URL: "https://example.com/docs", CreatedAt: created},
+			accept:         "application/json",
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"id":"abc123","short_url":"http://short.ly/abc123","url":"https://example.com/docs",` +
+				`"host":"example.com","created_at":"2026-10-19T12:00:00Z","protected":false,"interstitial":false}`,
+		},
+		{
+			name:           "JSON without creation time",
+			record:         &models.URLRecord{ID: "abc123", URL: "https://example.com/docs", LinkOptions: models.LinkOptions{Interstitial: true}},
+			accept:         "text/html;q=0.5, application/json",
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"id":"abc123","short_url":"http://short.ly/abc123","url":"https://example.com/docs",` +
+				`"host":"example.com","protected":false,"interstitial":true}`,
+		},
+		{
+			name:           "protected link hides destination",
+			record:         &models.URLRecord{ID: "abc123", URL: "https://secret.example.com", LinkOptions: models.LinkOptions{PasswordHash: "hash"}},
+			expectedStatus: http.StatusOK,
+			expectedHTML:   []string{"protected by a password", `<form method="GET" action="/abc123">`},
+			absentHTML:     []string{"secret.example.com", "Created on"},
+		},
+		{
+			name:           "protected link JSON",
+			record:         &models.URLRecord{ID: "abc123", URL: "https://secret.example.com", LinkOptions: models.LinkOptions{PasswordHash: "hash"}},
+			accept:         "application/json",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"id":"abc123","short_url":"http://short.ly/abc123","protected":true,"interstitial":false}`,
+		},
+		{
+			name:           "not found",
+			storageErr:     shared.ErrNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "gone",
+			storageErr:     shared.ErrGone,
+			expectedStatus: http.StatusGone,
+		},
+		{
+			name:           "storage error",
+			storageErr:     errors.New("database error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// No ConsumeClick or CountVariantClick expectations: the preview never counts
+			mockStorage := new(mocks.Storage)
+			mockStorage.On("GetRecord", mock.Anything, "abc123").Return(tt.record, tt.storageErr)
+
+			h := &URLHandler{
+				Storage: mockStorage,
+				Logger:  logger,
+				BaseURL: "http://short.ly",
+			}
+
+			r := httptest.NewRequest(http.MethodGet, "/abc123+", nil)
+			r.Header.Set("Accept", tt.accept)
+			w := httptest.NewRecorder()
+			h.PreviewURL(w, r)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockStorage.AssertExpectations(t)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+			if tt.expectedBody != "" {
+				assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+				return
+			}
+			assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+			for _, s := range tt.expectedHTML {
+				assert.Contains(t, w.Body.String(), s)
+			}
+			for _, s := range tt.absentHTML {
+				assert.NotContains(t, w.Body.String(), s)
+			}
+		})
+	}
+}
+
+func TestExpandURL_Interstitial(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+
+	record := func() *models.URLRecord {
+		return &models.URLRecord{
+			ID:          "abc123",
+			URL:         "https://example.com/docs",
+			LinkOptions: models.LinkOptions{Interstitial: true, MaxClicks: 3, ForwardPath: true, QueryPolicy: models.QueryPolicyAppend},
+			ClicksLeft:  3,
+		}
+	}
+
+	t.Run("GET shows the page", func(t *testing.T) {
+		mockStorage := new(mocks.Storage)
+		mockStorage.On("GetRecord", mock.Anything, "abc123").Return(record(), nil)
+		h := &URLHandler{Storage: mockStorage, Logger: logger}
+
+		w := httptest.NewRecorder()
+		h.ExpandURL(w, httptest.NewRequest(http.MethodGet, "/abc123/guide?x=1", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("Location"))
+		assert.Contains(t, w.Body.String(), "<code>https://example.com/docs/guide?x=1</code>")
+		assert.Contains(t, w.Body.String(), `<form method="POST" action="/abc123/guide?x=1">`)
+		mockStorage.AssertExpectations(t)
+	})
+
+	t.Run("continue redirects", func(t *testing.T) {
+		mockStorage := new(mocks.Storage)
+		mockStorage.On("GetRecord", mock.Anything, "abc123").Return(record(), nil)
+		mockStorage.On("ConsumeClick", mock.Anything, "abc123").Return(nil)
+		h := &URLHandler{Storage: mockStorage, Logger: logger}
+
+		w := httptest.NewRecorder()
+		h.ExpandURL(w, httptest.NewRequest(http.MethodPost, "/abc123/guide?x=1", nil))
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, "https://example.com/docs/guide?x=1", w.Header().Get("Location"))
+		mockStorage.AssertExpectations(t)
+	})
+
+	t.Run("POST to a link without interstitial", func(t *testing.T) {
+		mockStorage := new(mocks.Storage)
+		mockStorage.On("GetRecord", mock.Anything, "abc123").
+			Return(&models.URLRecord{ID: "abc123", URL: "https://example.com/docs"}, nil)
+		h := &URLHandler{Storage: mockStorage, Logger: logger}
+
+		w := httptest.NewRecorder()
+		h.ExpandURL(w, httptest.NewRequest(http.MethodPost, "/abc123", nil))
+
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	})
+}
+
+func TestPrefersJSON(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   bool
+	}{
+		{accept: "", want: false},
+		{accept: "*/*", want: false},
+		{accept: "application/json", want: true},
+		{accept: "application/json, text/html", want: false},
+		{accept: "text/html;q=0.9, application/json", want: true},
+		{accept: "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", want: false},
+		{accept: "application/json;q=bad", want: false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, prefersJSON(tt.accept), tt.accept)
+	}
+}
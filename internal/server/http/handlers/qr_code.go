@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/apetsko/shortugo/internal/qrcode"
+	"github.com/apetsko/shortugo/internal/storages/shared"
+)
+
+// QRCodeImage renders a QR code encoding the short link BaseURL + "/" + id.
+// Storage errors are returned unchanged, so callers can map shared.ErrNotFound and shared.ErrGone.
+func (h *URLHandler) QRCodeImage(ctx context.Context, id string, o qrcode.Options) ([]byte, error) {
+	if _, err := h.Storage.Get(ctx, id); err != nil {
+		return nil, err
+	}
+
+	content := h.BaseURL + "/" + id
+	if h.QRCodes == nil {
+		return qrcode.Encode(content, o)
+	}
+	return h.QRCodes.Encode(content, o)
+}
+
+// QRCode handles requests for a QR code image of a shortened URL.
+//
+//   - Method: GET
+//   - Endpoint: /{id}/qr
+//   - Query: format=png|svg (default png), size=<pixels> (default 256), level=L|M|Q|H (default M)
+//   - Success: 200 OK with an image/png or image/svg+xml body
+//   - Errors:
+//     400 Bad Request – if the format, size or level is invalid
+//     404 Not Found – if the short URL does not exist
+//     410 Gone – if the short URL was deleted
+func (h *URLHandler) QRCode(w http.ResponseWriter, r *http.Request) {
+	// Extract the ID from the URL path (remove the leading "/" and the trailing "/qr")
+	ID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/"), "/qr")
+
+	query := r.URL.Query()
+
+	size := 0
+	if s := query.Get("size"); s != "" {
+		var err error
+		if size, err = strconv.Atoi(s); err != nil {
+			h.Logger.Error("Invalid QR code size: " + s)
+			http.Error(w, "Bad Request: invalid size", http.StatusBadRequest)
+			return
+		}
+	}
+
+	opts, err := qrcode.ParseOptions(query.Get("format"), query.Get("level"), size)
+	if err != nil {
+		h.Logger.Error(err.Error())
+		http.Error(w, "Bad Request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	img, err := h.QRCodeImage(r.Context(), ID, opts)
+	if err != nil {
+		h.Logger.Error(err.Error())
+		switch {
+		case errors.Is(err, shared.ErrGone):
+			w.WriteHeader(http.StatusGone)
+		case errors.Is(err, shared.ErrNotFound):
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", opts.ContentType())
+	w.Header().Set("Content-Length", strconv.Itoa(len(img)))
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(img); err != nil {
+		h.Logger.Error(err.Error())
+	}
+}
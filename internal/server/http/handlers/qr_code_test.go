@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/apetsko/shortugo/internal/logging"
+	"github.com/apetsko/shortugo/internal/mocks"
+	"github.com/apetsko/shortugo/internal/models"
+	"github.com/apetsko/shortugo/internal/qrcode"
+	"github.com/apetsko/shortugo/internal/storages/infile"
+	"github.com/apetsko/shortugo/internal/storages/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestQRCode(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+
+	tests := []struct {
+		mockStorageSetup    func(mockStorage *mocks.Storage)
+		validate            func(t *testing.T, w *httptest.ResponseRecorder)
+		name                string
+		target              string
+		expectedContentType string
+		expectedStatus      int
+	}{
+		{
+			name:   "png by default",
+			target: "/abc123/qr",
+			mockStorageSetup: func(mockStorage *mocks.Storage) {
+				mockStorage.On("Get", mock.Anything, "abc123").Return("https://example.com", nil)
+			},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "image/png",
+			validate: func(t *testing.T, w *httptest.ResponseRecorder) {
+				img, err := png.Decode(bytes.NewReader(w.Body.Bytes()))
+				require.NoError(t, err)
+				assert.Equal(t, qrcode.DefaultSize, img.Bounds().Dx())
+			},
+		},
+		{
+			name:   "svg with size and level",
+			target: "/abc123/qr?format=svg&size=512&level=H",
+			mockStorageSetup: func(mockStorage *mocks.Storage) {
+				mockStorage.On("Get", mock.Anything, "abc123").Return("https://example.com", nil)
+			},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "image/svg+xml",
+			validate: func(t *testing.T, w *httptest.ResponseRecorder) {
+				assert.Contains(t, w.Body.String(), `width="512" height="512"`)
+			},
+		},
+		{
+			name:             "invalid format",
+			target:           "/abc123/qr?format=gif",
+			mockStorageSetup: func(mockStorage *mocks.Storage) {},
+			expectedStatus:   http.StatusBadRequest,
+		},
+		{
+			name:             "invalid size",
+			target:           "/abc123/qr?size=big",
+			mockStorageSetup: func(mockStorage *mocks.Storage) {},
+			expectedStatus:   http.StatusBadRequest,
+		},
+		{
+			name:             "size out of range",
+			target:           "/abc123/qr?size=10000",
+			mockStorageSetup: func(mockStorage *mocks.Storage) {},
+			expectedStatus:   http.StatusBadRequest,
+		},
+		{
+			name:   "URL gone",
+			target: "/deleted/qr",
+			mockStorageSetup: func(mockStorage *mocks.Storage) {
+				mockStorage.On("Get", mock.Anything, "deleted").Return("", shared.ErrGone)
+			},
+			expectedStatus: http.StatusGone,
+		},
+		{
+			name:   "URL not found",
+			target: "/missing/qr",
+			mockStorageSetup: func(mockStorage *mocks.Storage) {
+				mockStorage.On("Get", mock.Anything, "missing").Return("", shared.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "internal error",
+			target: "/error/qr",
+			mockStorageSetup: func(mockStorage *mocks.Storage) {
+				mockStorage.On("Get", mock.Anything, "error").Return("", errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := new(mocks.Storage)
+			tt.mockStorageSetup(mockStorage)
+
+			h := &URLHandler{
+				Storage: mockStorage,
+				Logger:  logger,
+				BaseURL: "http://localhost:8080",
+				QRCodes: qrcode.NewCache(qrcode.DefaultCacheSize),
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, tt.target, nil)
+
+			h.QRCode(w, r)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedContentType != "" {
+				assert.Equal(t, tt.expectedContentType, w.Header().Get("Content-Type"))
+			}
+			if tt.validate != nil {
+				tt.validate(t, w)
+			}
+			mockStorage.AssertExpectations(t)
+		})
+	}
+}
+
+func TestQRCodeImage_EncodesShortURL(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+	mockStorage := new(mocks.Storage)
+	mockStorage.On("Get", mock.Anything, "abc123").Return("https://example.com", nil)
+
+	h := &URLHandler{
+		Storage: mockStorage,
+		Logger:  logger,
+		BaseURL: "http://localhost:8080",
+	}
+
+	opts := qrcode.Options{Format: qrcode.FormatSVG, Level: "M", Size: 128}
+	got, err := h.QRCodeImage(context.Background(), "abc123", opts)
+	require.NoError(t, err)
+
+	want, err := qrcode.Encode("http://localhost:8080/abc123", opts)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestQRCode_FileStorage(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+	ctx := context.Background()
+
+	storage, err := infile.New(filepath.Join(t.TempDir(), "urls.json"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = storage.Close() })
+
+	require.NoError(t, storage.PutBatch(ctx, []models.URLRecord{
+		{ID: "live", URL: "https://live.com", UserID: "u1"},
+		{ID: "deleted", URL: "https://deleted.com", UserID: "u1"},
+		{ID: "exhausted", URL: "https://exhausted.com", UserID: "u1", LinkOptions: models.LinkOptions{MaxClicks: 1}, ClicksLeft: 1},
+	}))
+	require.NoError(t, storage.DeleteUserURLs(ctx, []string{"deleted"}, "u1"))
+	require.NoError(t, storage.ConsumeClick(ctx, "exhausted"))
+
+	h := &URLHandler{Storage: storage, Logger: logger, BaseURL: "http://localhost:8080"}
+
+	tests := []struct {
+		id   string
+		want int
+	}{
+		{id: "live", want: http.StatusOK},
+		{id: "deleted", want: http.StatusGone},
+		{id: "exhausted", want: http.StatusGone},
+		{id: "missing", want: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.QRCode(w, httptest.NewRequest(http.MethodGet, "/"+tt.id+"/qr", nil))
+			assert.Equal(t, tt.want, w.Code)
+		})
+	}
+}
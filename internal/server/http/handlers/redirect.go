@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"bytes"
+	"embed"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/apetsko/shortugo/internal/models"
+)
+
+// DefaultRedirectType is the redirect status used when neither the link nor the server configures one.
+const DefaultRedirectType = http.StatusTemporaryRedirect
+
+// permanentRedirectMaxAge bounds how long clients may cache a permanent redirect.
+const permanentRedirectMaxAge = 24 * time.Hour
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+// templates holds the HTML pages served by the handlers.
+var templates = template.Must(template.ParseFS(templateFS, "templates/*.html"))
+
+// redirectType returns the redirect status for rec, falling back to the server default.
+func (h *URLHandler) redirectType(rec *models.URLRecord) int {
+	switch {
+	case rec.RedirectType != 0:
+		return rec.RedirectType
+	case h.RedirectType != 0:
+		return h.RedirectType
+	default:
+		return DefaultRedirectType
+	}
+}
+
+// redirectMode returns the redirect mode for rec, falling back to the server default.
+func (h *URLHandler) redirectMode(rec *models.URLRecord) string {
+	switch {
+	case rec.RedirectMode != "":
+		return rec.RedirectMode
+	case h.RedirectMode != "":
+		return h.RedirectMode
+	default:
+		return models.RedirectModeHeader
+	}
+}
+
+// cacheControl returns the Cache-Control value of a redirect of rec with the status code.
+// Permanent redirects may be cached for a bounded time, temporary ones must reach the server on every visit.
+// Redirects of links that are counted, checked or computed per visit are never cached, whatever their status:
+// a cached one would skip the A/B variant counters, the routing rules, the click limit or the password,
+// or replay the query and path forwarded for another visit.
+func cacheControl(code int, rec *models.URLRecord) string {
+	perVisit := len(rec.Variants) > 0 || len(rec.Rules) > 0 || rec.MaxClicks > 0 || rec.PasswordHash != "" ||
+		rec.QueryPolicy != "" || rec.ForwardPath
+	switch {
+	case perVisit:
+		return "no-store"
+	case code == http.StatusMovedPermanently || code == http.StatusPermanentRedirect:
+		return "public, max-age=" + strconv.Itoa(int(permanentRedirectMaxAge.Seconds()))
+	default:
+		return "no-store"
+	}
+}
+
+// isWebURL reports whether target is an absolute http or https URL.
+func isWebURL(target string) bool {
+	u, err := url.Parse(target)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https")
+}
+
+// redirect sends the client to target according to the redirect settings of rec.
+// The HTML mode is only used for http and https targets, so the page can never run a script URL.
+func (h *URLHandler) redirect(w http.ResponseWriter, target string, rec *models.URLRecord) {
+	code := h.redirectType(rec)
+	w.Header().Set("Cache-Control", cacheControl(code, rec))
+
+	if h.redirectMode(rec) == models.RedirectModeHTML && isWebURL(target) {
+		var buf bytes.Buffer
+		if err := templates.ExecuteTemplate(&buf, "redirect.html", struct{ URL string }{URL: target}); err != nil {
+			h.Logger.Error("failed to render redirect page", "error", err.Error())
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		if _, err := buf.WriteTo(w); err != nil {
+			h.Logger.Error(err.Error())
+		}
+		return
+	}
+
+	// Set the "Location" header for the redirect response
+	w.Header().Set("Location", target)
+	// Add the "Content-Type" header for the response
+	w.Header().Add("Content-Type", "text/html")
+
+	// Respond with the redirect status and the URL in the body
+	w.WriteHeader(code)
+	if _, err := w.Write([]byte(target)); err != nil {
+		// Log any error that occurs while writing the response
+		h.Logger.Error(err.Error())
+	}
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/apetsko/shortugo/internal/logging"
+	"github.com/apetsko/shortugo/internal/mocks"
+	"github.com/apetsko/shortugo/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestExpandURL_RedirectType(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+
+	tests := []struct {
+		validate             func(t *testing.T, w *httptest.ResponseRecorder)
+		name                 string
+		url                  string
+		serverMode           string
+		options              models.LinkOptions
+		serverType           int
+		expectedStatus       int
+		expectedCacheControl string
+	}{
+		{
+			name:                 "built-in default",
+			url:                  "https://example.com",
+			expectedStatus:       http.StatusTemporaryRedirect,
+			expectedCacheControl: "no-store",
+		},
+		{
+			name:                 "server default permanent",
+			url:                  "https://example.com",
+			serverType:           http.StatusMovedPermanently,
+			expectedStatus:       http.StatusMovedPermanently,
+			expectedCacheControl: "public, max-age=86400",
+		},
+		{
+			name:                 "link overrides server default",
+			url:                  "https://example.com",
+			serverType:           http.StatusFound,
+			options:              models.LinkOptions{RedirectType: http.StatusPermanentRedirect},
+			expectedStatus:       http.StatusPermanentRedirect,
+			expectedCacheControl: "public, max-age=86400",
+		},
+		{
+			name:                 "permanent with forwarded query",
+			url:                  "https://example.com",
+			options:              models.LinkOptions{RedirectType: http.StatusMovedPermanently, QueryPolicy: models.QueryPolicyAppend},
+			expectedStatus:       http.StatusMovedPermanently,
+			expectedCacheControl: "no-store",
+		},
+		{
+			name:                 "link found",
+			url:                  "https://example.com",
+			options:              models.LinkOptions{RedirectType: http.StatusFound},
+			expectedStatus:       http.StatusFound,
+			expectedCacheControl: "no-store",
+		},
+		{
+			name:                 "html mode from link",
+			url:                  "https://example.com/path?q=1&x=2",
+			options:              models.LinkOptions{RedirectMode: models.RedirectModeHTML, RedirectType: http.StatusMovedPermanently},
+			expectedStatus:       http.StatusOK,
+			expectedCacheControl: "public, max-age=86400",
+			validate: func(t *testing.T, w *httptest.ResponseRecorder) {
+				assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+				assert.Empty(t, w.Header().Get("Location"))
+				body := w.Body.String()
+				assert.Contains(t, body, `<meta http-equiv="refresh" content="0; url=https://example.com/path?q=1&amp;x=2">`)
+				assert.Contains(t, body, `window.location.replace("https://example.com/path?q=1\u0026x=2")`)
+				assert.Contains(t, body, `<a href="https://example.com/path?q=1&amp;x=2">`)
+			},
+		},
+		{
+			name:                 "html mode from server default escapes markup",
+			url:                  `https://example.com/"><script>alert(1)</script>`,
+			serverMode:           models.RedirectModeHTML,
+			expectedStatus:       http.StatusOK,
+			expectedCacheControl: "no-store",
+			validate: func(t *testing.T, w *httptest.ResponseRecorder) {
+				assert.NotContains(t, w.Body.String(), "<script>alert(1)</script>")
+			},
+		},
+		{
+			name:                 "html mode ignored for non-web URLs",
+			url:                  "javascript:alert(1)",
+			options:              models.LinkOptions{RedirectMode: models.RedirectModeHTML},
+			expectedStatus:       http.StatusTemporaryRedirect,
+			expectedCacheControl: "no-store",
+			validate: func(t *testing.T, w *httptest.ResponseRecorder) {
+				assert.Equal(t, "javascript:alert(1)", w.Header().Get("Location"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := new(mocks.Storage)
+			mockStorage.On("GetRecord", mock.Anything, "abc123").
+				Return(&models.URLRecord{ID: "abc123", URL: tt.url, LinkOptions: tt.options}, nil)
+
+			h := &URLHandler{
+				Storage:      mockStorage,
+				Logger:       logger,
+				RedirectType: tt.serverType,
+				RedirectMode: tt.serverMode,
+			}
+
+			w := httptest.NewRecorder()
+			h.ExpandURL(w, httptest.NewRequest(http.MethodGet, "/abc123", nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedCacheControl, w.Header().Get("Cache-Control"))
+			if tt.expectedStatus != http.StatusOK {
+				assert.Equal(t, tt.url, w.Header().Get("Location"))
+			}
+			if tt.validate != nil {
+				tt.validate(t, w)
+			}
+		})
+	}
+}
+
+func TestCacheControl(t *testing.T) {
+	permanent := "public, max-age=86400"
+	tests := []struct {
+		name     string
+		options  models.LinkOptions
+		code     int
+		expected string
+	}{
+		{name: "moved permanently", code: http.StatusMovedPermanently, expected: permanent},
+		{name: "permanent redirect", code: http.StatusPermanentRedirect, expected: permanent},
+		{name: "temporary redirect", code: http.StatusTemporaryRedirect, expected: "no-store"},
+		{name: "found", code: http.StatusFound, expected: "no-store"},
+		{
+			name:     "variants",
+			code:     http.StatusMovedPermanently,
+			options:  models.LinkOptions{Variants: []models.Variant{{Name: "a", URL: "https://a.example", Weight: 1}}},
+			expected: "no-store",
+		},
+		{
+			name:     "routing rules",
+			code:     http.StatusMovedPermanently,
+			options:  models.LinkOptions{Rules: []models.RoutingRule{{Platform: "ios", URL: "https://apps.apple.com"}}},
+			expected: "no-store",
+		},
+		{name: "max clicks", code: http.StatusMovedPermanently, options: models.LinkOptions{MaxClicks: 1}, expected: "no-store"},
+		{name: "password", code: http.StatusMovedPermanently, options: models.LinkOptions{PasswordHash: "hash"}, expected: "no-store"},
+		{
+			name:     "query policy",
+			code:     http.StatusPermanentRedirect,
+			options:  models.LinkOptions{QueryPolicy: models.QueryPolicyDestination},
+			expected: "no-store",
+		},
+		{name: "forward path", code: http.StatusPermanentRedirect, options: models.LinkOptions{ForwardPath: true}, expected: "no-store"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, cacheControl(tt.code, &models.URLRecord{LinkOptions: tt.options}))
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"github.com/apetsko/shortugo/internal/search"
+)
+
+// SearchUserURLs finds the user's URLs by the words of their destination URL, alias, title and tags.
+// Every word of the query has to start a word of the link, so "exa doc" finds https://example.com/docs.
+//
+// Request:
+//   - Method: GET
+//   - URL: /api/user/urls/search?q=example docs
+//
+// Response:
+//   - 200 OK: The matching URLs, best matches first, in the format of ListUserURLs; at most 100 of them.
+//     An empty list when nothing matches.
+//   - 400 Bad Request: The query has no words.
+//   - 500 Internal Server Error: User authentication failed or other server error.
+func (h *URLHandler) SearchUserURLs(w http.ResponseWriter, r *http.Request) {
+	// Retrieve the user ID from the cookie
+	userID, err := h.Auth.CookieGetUserID(r, h.Secret)
+	if err != nil {
+		// If the user ID is not found, set a new one
+		userID, err = h.Auth.CookieSetUserID(w, h.Secret)
+		if err != nil {
+			h.Logger.Error(err.Error())
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	query := r.URL.Query().Get("q")
+	if len(search.Terms(query)) == 0 {
+		http.Error(w, "Empty search query", http.StatusBadRequest)
+		return
+	}
+
+	records, err := h.Storage.SearchLinks(r.Context(), h.BaseURL, userID, query)
+	if err != nil {
+		h.Logger.Error("Failed to search user URLs", "error", err.Error())
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err = json.NewEncoder(&buf).Encode(toUserURLs(records)); err != nil {
+		h.Logger.Error("Error marshaling user URLs", "error", err.Error())
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if _, err = buf.WriteTo(w); err != nil {
+		h.Logger.Error(err.Error())
+	}
+}
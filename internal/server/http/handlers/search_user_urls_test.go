@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/apetsko/shortugo/internal/logging"
+	"github.com/apetsko/shortugo/internal/mocks"
+	"github.com/apetsko/shortugo/internal/models"
+	"github.com/apetsko/shortugo/internal/storages/inmem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestSearchUserURLs(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+	ctx := context.Background()
+
+	storage := inmem.New()
+	require.NoError(t, storage.Put(ctx, models.URLRecord{ID: "abc123", URL: "https://example.com/docs", UserID: "user123", Title: "Go docs"}))
+	require.NoError(t, storage.Put(ctx, models.URLRecord{ID: "def456", URL: "https://example.org/blog", UserID: "user123", Tags: []string{"golang"}}))
+	require.NoError(t, storage.Put(ctx, models.URLRecord{ID: "ghi789", URL: "https://example.com/docs", UserID: "user456"}))
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedBody   string
+		expectedStatus int
+	}{
+		{
+			name:           "all words match",
+			query:          "?q=example+docs",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[{"short_url":"http://short.ly/abc123","original_url":"https://example.com/docs","title":"Go docs"}]`,
+		},
+		{
+			name:           "whole word first",
+			query:          "?q=GO",
+			expectedStatus: http.StatusOK,
+			expectedBody: `[{"short_url":"http://short.ly/abc123","original_url":"https://example.com/docs","title":"Go docs"},` +
+				`{"short_url":"http://short.ly/def456","original_url":"https://example.org/blog","tags":["golang"]}]`,
+		},
+		{
+			name:           "alias",
+			query:          "?q=def4",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[{"short_url":"http://short.ly/def456","original_url":"https://example.org/blog","tags":["golang"]}]`,
+		},
+		{
+			name:           "no match",
+			query:          "?q=missing",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[]`,
+		},
+		{
+			name:           "empty query",
+			query:          "?q=+-+",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAuth := new(mocks.Authenticator)
+			mockAuth.On("CookieGetUserID", mock.Anything, mock.Anything).Return("user123", nil)
+
+			h := &URLHandler{
+				Auth:    mockAuth,
+				Storage: storage,
+				Logger:  logger,
+				BaseURL: "http://short.ly",
+			}
+
+			w := httptest.NewRecorder()
+			h.SearchUserURLs(w, httptest.NewRequest(http.MethodGet, "/api/user/urls/search"+tt.query, nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestSearchUserURLs_StorageError(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+
+	mockAuth := new(mocks.Authenticator)
+	mockAuth.On("CookieGetUserID", mock.Anything, mock.Anything).Return("user123", nil)
+	mockStorage := new(mocks.Storage)
+	mockStorage.On("SearchLinks", mock.Anything, "http://short.ly", "user123", "docs").Return(nil, errors.New("database error"))
+
+	h := &URLHandler{
+		Auth:    mockAuth,
+		Storage: mockStorage,
+		Logger:  logger,
+		BaseURL: "http://short.ly",
+	}
+
+	w := httptest.NewRecorder()
+	h.SearchUserURLs(w, httptest.NewRequest(http.MethodGet, "/api/user/urls/search?q=docs", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	mockStorage.AssertExpectations(t)
+}
@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"strings"
 
+	"github.com/apetsko/shortugo/internal/audit"
 	"github.com/apetsko/shortugo/internal/models"
 	"github.com/apetsko/shortugo/internal/utils"
 )
@@ -16,7 +18,8 @@ import (
 //   - Method: POST
 //   - URL: /api/shorten/batch
 //   - Headers: Content-Type: application/json
-//   - Body: [{"id": "1", "original_url": "http://example.com"}, ...]
+//   - Body: [{"correlation_id": "1", "original_url": "http://example.com", "redirect_type": 301}, ...]
+//     redirect_type, redirect_mode, the UTM tags, password, max_clicks, title, folder and tags are optional per item.
 //
 // Response:
 //   - 201 Created: The batch shortening request is successful.
@@ -60,37 +63,30 @@ func (h *URLHandler) ShortenBatchJSON(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The user's UTM template fills the tags missing from every item
+	template, err := h.UTMTemplate(r.Context(), userID)
+	if err != nil {
+		h.Logger.Error("Failed to get UTM template", "error", err.Error())
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
 	var resps []models.BatchResponse
 	var records []models.URLRecord
 
 	// Process each batch request
 	for _, req := range reqs {
-		var resp models.BatchResponse
-
-		// Validate the original URL
-		if req.OriginalURL == "" {
-			errStr := http.StatusText(http.StatusBadRequest) + ": Empty URL"
-			h.Logger.Error(errStr, "id", req.ID)
-			resp.ID = req.ID
-			resp.ShortURL = errStr
-			resps = append(resps, resp)
+		record, errStr := h.batchRecord(req, userID, template)
+		if errStr != "" {
+			resps = append(resps, models.BatchResponse{ID: req.ID, ShortURL: errStr})
 			continue
 		}
 
-		// Generate a unique ID for the URL
-		IDlen := 8
-		var record = models.URLRecord{
-			URL:    req.OriginalURL,
-			ID:     utils.GenerateID(req.OriginalURL, IDlen),
-			UserID: userID,
-		}
-
 		records = append(records, record)
 
 		// Create the shortened URL
 		shortURL := h.BaseURL + "/" + record.ID
-		resp = models.BatchResponse{ID: req.ID, ShortURL: shortURL}
-		resps = append(resps, resp)
+		resps = append(resps, models.BatchResponse{ID: req.ID, ShortURL: shortURL})
 	}
 
 	// Store the batch of URL records
@@ -99,6 +95,9 @@ func (h *URLHandler) ShortenBatchJSON(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	if len(records) > 0 {
+		h.audit(r, userID, audit.ActionCreateLinks, LinkIDs(records), nil)
+	}
 
 	// Set the response headers and write the JSON response
 	w.Header().Add("Content-Type", "application/json")
@@ -108,3 +107,59 @@ func (h *URLHandler) ShortenBatchJSON(w http.ResponseWriter, r *http.Request) {
 		h.Logger.Error(err.Error())
 	}
 }
+
+// batchRecord validates an item of a batch and builds the record storing it, filling the missing UTM tags from template.
+// An invalid item yields the error reported in place of its short URL.
+func (h *URLHandler) batchRecord(req models.BatchRequest, userID string, template models.UTM) (models.URLRecord, string) {
+	// Validate the original URL
+	if req.OriginalURL == "" {
+		errStr := http.StatusText(http.StatusBadRequest) + ": Empty URL"
+		h.Logger.Error(errStr, "id", req.ID)
+		return models.URLRecord{}, errStr
+	}
+
+	// Validate the per-link options
+	if err := utils.ValidateStruct(req.LinkOptions); err != nil {
+		errStr := http.StatusText(http.StatusBadRequest) + ": Invalid link options"
+		h.Logger.Error(errStr, "id", req.ID, "error", err.Error())
+		return models.URLRecord{}, errStr
+	}
+
+	// Refuse links to blocked domains
+	if h.Blocked(req.OriginalURL, req.LinkOptions) {
+		errStr := http.StatusText(http.StatusForbidden) + ": Blocked domain"
+		h.Logger.Error(errStr, "id", req.ID)
+		return models.URLRecord{}, errStr
+	}
+
+	var record = models.URLRecord{
+		URL:         req.OriginalURL,
+		UserID:      userID,
+		ClicksLeft:  req.LinkOptions.MaxClicks,
+		Title:       strings.TrimSpace(req.Title),
+		Folder:      strings.TrimSpace(req.Folder),
+		Tags:        models.NormalizeTags(req.Tags),
+		LinkOptions: req.LinkOptions,
+	}
+
+	// Validate the title, tags and folder, compared in their normalized form
+	if err := utils.ValidateStruct(record); err != nil {
+		errStr := http.StatusText(http.StatusBadRequest) + ": Invalid labels"
+		h.Logger.Error(errStr, "id", req.ID, "error", err.Error())
+		return models.URLRecord{}, errStr
+	}
+
+	// Store the password as a hash
+	if err := SetPassword(&record.LinkOptions, req.Password); err != nil {
+		errStr := http.StatusText(http.StatusBadRequest) + ": Invalid password"
+		h.Logger.Error(errStr, "id", req.ID, "error", err.Error())
+		return models.URLRecord{}, errStr
+	}
+	record.UTM = record.UTM.WithDefaults(template)
+
+	// Generate a unique ID for the URL and its UTM tags
+	IDlen := 8
+	record.ID = LinkID(record.URL, record.UTM, IDlen)
+
+	return record, ""
+}
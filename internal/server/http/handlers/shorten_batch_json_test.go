@@ -13,6 +13,7 @@ import (
 	"github.com/apetsko/shortugo/internal/logging"
 	"github.com/apetsko/shortugo/internal/mocks"
 	"github.com/apetsko/shortugo/internal/models"
+	"github.com/apetsko/shortugo/internal/storages/shared"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -22,6 +23,7 @@ import (
 func BenchmarkShortenBatchJSON(b *testing.B) {
 	logger, _ := logging.New(zapcore.DebugLevel)
 	mockStorage := new(mocks.Storage)
+	mockStorage.On("GetUTMTemplate", mock.Anything, mock.Anything).Return(nil, shared.ErrNotFound).Maybe()
 	mockAuth := new(mocks.Authenticator)
 	h := &URLHandler{
 		Storage: mockStorage,
@@ -114,6 +116,47 @@ func TestShortenBatchJSON(t *testing.T) {
 			expectedStatus: http.StatusCreated,
 			expectedBody:   `[{"correlation_id":"1", "short_url":"Bad Request: Empty URL"}]`,
 		},
+		{
+			name: "bad request on invalid redirect type",
+			mockAuthSetup: func(mockAuth *mocks.Authenticator) {
+				mockAuth.On("CookieGetUserID", mock.Anything, mock.Anything).Return("user123", nil)
+			},
+			mockStorageSetup: func(mockStorage *mocks.Storage) {
+				mockStorage.On("PutBatch", mock.Anything, mock.MatchedBy(func(rr []models.URLRecord) bool {
+					return len(rr) == 1 && rr[0].RedirectType == http.StatusPermanentRedirect
+				})).Return(nil)
+			},
+			requestBody: `[
+				{"correlation_id":"1", "original_url":"http://example.com", "redirect_type":308},
+				{"correlation_id":"2", "original_url":"http://test.com", "redirect_type":200}
+			]`,
+			expectedStatus: http.StatusCreated,
+			expectedBody: `[
+				{"correlation_id":"1", "short_url":"http://short.ly/"},
+				{"correlation_id":"2", "short_url":"Bad Request: Invalid link options"}
+			]`,
+		},
+		{
+			name: "labels are normalized and validated",
+			mockAuthSetup: func(mockAuth *mocks.Authenticator) {
+				mockAuth.On("CookieGetUserID", mock.Anything, mock.Anything).Return("user123", nil)
+			},
+			mockStorageSetup: func(mockStorage *mocks.Storage) {
+				mockStorage.On("PutBatch", mock.Anything, mock.MatchedBy(func(rr []models.URLRecord) bool {
+					return len(rr) == 1 && rr[0].Title == "Docs" && rr[0].Folder == "work" &&
+						len(rr[0].Tags) == 2 && rr[0].Tags[0] == "go" && rr[0].Tags[1] == "promo"
+				})).Return(nil)
+			},
+			requestBody: `[
+				{"correlation_id":"1", "original_url":"http://example.com", "title":" Docs ", "folder":"work", "tags":["Promo", "go", "promo"]},
+				{"correlation_id":"2", "original_url":"http://test.com", "folder":"` + strings.Repeat("f", 129) + `"}
+			]`,
+			expectedStatus: http.StatusCreated,
+			expectedBody: `[
+				{"correlation_id":"1", "short_url":"http://short.ly/"},
+				{"correlation_id":"2", "short_url":"Bad Request: Invalid labels"}
+			]`,
+		},
 		{
 			name: "internal server error on Auth failure",
 			mockAuthSetup: func(mockAuth *mocks.Authenticator) {
@@ -141,6 +184,7 @@ func TestShortenBatchJSON(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockAuth := new(mocks.Authenticator)
 			mockStorage := new(mocks.Storage)
+			mockStorage.On("GetUTMTemplate", mock.Anything, mock.Anything).Return(nil, shared.ErrNotFound).Maybe()
 			logger, _ := logging.New(zapcore.DebugLevel)
 
 			h := &URLHandler{
@@ -5,7 +5,10 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"strings"
+	"time"
 
+	"github.com/apetsko/shortugo/internal/audit"
 	"github.com/apetsko/shortugo/internal/models"
 	"github.com/apetsko/shortugo/internal/storages/shared"
 	"github.com/apetsko/shortugo/internal/utils"
@@ -18,11 +21,18 @@ import (
 //   - Method: POST
 //   - URL: /api/shorten
 //   - Headers: Content-Type: application/json
-//   - Body: {"url": "http://example.com"}
+//   - Body: {"url": "http://example.com", "redirect_type": 301, "redirect_mode": "header", "utm_source": "newsletter"}
+//     redirect_type (301, 302, 307 or 308), redirect_mode (header or html) and the UTM tags
+//     (utm_source, utm_medium, utm_campaign, utm_term, utm_content) are optional.
+//     password (up to 72 bytes) puts the link behind a password form.
+//     max_clicks makes the link gone after that many redirects.
+//     tags (up to 32) and folder label the link, see AddLinkTags and SetLinkFolder.
+//     title (up to 256 characters) describes the link and is found by SearchUserURLs.
 //
 // Response:
 //   - 201 Created: The URL shortening request is successful.
 //   - 400 Bad Request: Invalid request body or JSON format.
+//   - 403 Forbidden: The URL, a routing rule or a variant leads to a blocked domain.
 //   - 409 Conflict: The URL already exists.
 //   - 500 Internal Server Error: User authentication failed or other server error.
 func (h *URLHandler) ShortenJSON(w http.ResponseWriter, r *http.Request) {
@@ -52,15 +62,21 @@ func (h *URLHandler) ShortenJSON(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var record models.URLRecord
+	var req struct {
+		models.URLRecord
+		Password string `json:"password"`
+	}
 
 	// Unmarshal the JSON object into a URLRecord
-	err = json.Unmarshal(body, &record)
+	err = json.Unmarshal(body, &req)
 	if err != nil {
 		h.Logger.Info("Error unmarshaling request body", "error", err.Error())
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	record := req.URLRecord
+	// The creation time is set by the storage
+	record.CreatedAt = time.Time{}
 
 	// Validate the original URL
 	if record.URL == "" {
@@ -68,9 +84,51 @@ func (h *URLHandler) ShortenJSON(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Generate a unique ID for the URL
+	// Validate the per-link options
+	if err = utils.ValidateStruct(record.LinkOptions); err != nil {
+		h.Logger.Info("Invalid link options", "error", err.Error())
+		http.Error(w, "Invalid link options", http.StatusBadRequest)
+		return
+	}
+
+	// Refuse links to blocked domains
+	if h.Blocked(record.URL, record.LinkOptions) {
+		http.Error(w, "Blocked domain", http.StatusForbidden)
+		return
+	}
+
+	// Validate the title, tags and folder, compared in their normalized form
+	record.Tags = models.NormalizeTags(record.Tags)
+	record.Folder = strings.TrimSpace(record.Folder)
+	record.Title = strings.TrimSpace(record.Title)
+	if err = utils.ValidateStruct(record); err != nil {
+		h.Logger.Info("Invalid link labels", "error", err.Error())
+		http.Error(w, "Invalid labels", http.StatusBadRequest)
+		return
+	}
+
+	// Store the password as a hash
+	if err = SetPassword(&record.LinkOptions, req.Password); err != nil {
+		h.Logger.Info("Invalid link password", "error", err.Error())
+		http.Error(w, "Invalid password", http.StatusBadRequest)
+		return
+	}
+
+	// Fill the missing UTM tags from the user's template
+	template, err := h.UTMTemplate(r.Context(), userID)
+	if err != nil {
+		h.Logger.Error("Failed to get UTM template", "error", err.Error())
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	record.UTM = record.UTM.WithDefaults(template)
+
+	// A new link starts with all of its clicks
+	record.ClicksLeft = record.MaxClicks
+
+	// Generate a unique ID for the URL and its UTM tags
 	IDlen := 8
-	record.ID = utils.GenerateID(record.URL, IDlen)
+	record.ID = LinkID(record.URL, record.UTM, IDlen)
 	record.UserID = userID
 
 	var resp models.Result
@@ -91,6 +149,7 @@ func (h *URLHandler) ShortenJSON(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
+		h.audit(r, userID, audit.ActionCreateLinks, []string{record.ID}, nil)
 
 		// Prepare the response with the shortened URL
 		resp.Result = h.BaseURL + "/" + record.ID
@@ -8,6 +8,8 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/apetsko/shortugo/internal/logging"
@@ -24,6 +26,7 @@ import (
 func BenchmarkShortenJSON(b *testing.B) {
 	logger, _ := logging.New(zapcore.DebugLevel)
 	mockStorage := new(mocks.Storage)
+	mockStorage.On("GetUTMTemplate", mock.Anything, mock.Anything).Return(nil, shared.ErrNotFound).Maybe()
 	mockAuth := new(mocks.Authenticator)
 	h := &URLHandler{
 		Storage: mockStorage,
@@ -101,6 +104,63 @@ func TestShortenJSON(t *testing.T) {
 			expectedStatus: http.StatusConflict,
 			expectedBody:   shortenURL,
 		},
+		{
+			name: "successful URL shortening with redirect options",
+			mockAuthSetup: func(mockAuth *mocks.Authenticator) {
+				mockAuth.On("CookieGetUserID", mock.Anything, mock.Anything).Return("user123", nil)
+			},
+			mockStorageSetup: func(mockStorage *mocks.Storage) {
+				mockStorage.On("Get", mock.Anything, shortenID).Return("", shared.ErrNotFound)
+				mockStorage.On("Put", mock.Anything, mock.MatchedBy(func(r models.URLRecord) bool {
+					return r.RedirectType == http.StatusMovedPermanently && r.RedirectMode == models.RedirectModeHTML
+				})).Return(nil)
+			},
+			requestBody:    `{"url":"http://example.com","redirect_type":301,"redirect_mode":"html"}`,
+			expectedStatus: http.StatusCreated,
+			expectedBody:   shortenURL,
+		},
+		{
+			name: "successful URL shortening with tags and folder",
+			mockAuthSetup: func(mockAuth *mocks.Authenticator) {
+				mockAuth.On("CookieGetUserID", mock.Anything, mock.Anything).Return("user123", nil)
+			},
+			mockStorageSetup: func(mockStorage *mocks.Storage) {
+				mockStorage.On("Get", mock.Anything, shortenID).Return("", shared.ErrNotFound)
+				mockStorage.On("Put", mock.Anything, mock.MatchedBy(func(r models.URLRecord) bool {
+					return slices.Equal(r.Tags, []string{"2026", "promo"}) && r.Folder == "Campaigns"
+				})).Return(nil)
+			},
+			requestBody:    `{"url":"http://example.com","tags":["Promo","2026",""],"folder":" Campaigns "}`,
+			expectedStatus: http.StatusCreated,
+			expectedBody:   shortenURL,
+		},
+		{
+			name: "bad request on too long folder",
+			mockAuthSetup: func(mockAuth *mocks.Authenticator) {
+				mockAuth.On("CookieGetUserID", mock.Anything, mock.Anything).Return("user123", nil)
+			},
+			mockStorageSetup: func(mockStorage *mocks.Storage) {},
+			requestBody:      `{"url":"http://example.com","folder":"` + strings.Repeat("a", 129) + `"}`,
+			expectedStatus:   http.StatusBadRequest,
+		},
+		{
+			name: "bad request on invalid redirect type",
+			mockAuthSetup: func(mockAuth *mocks.Authenticator) {
+				mockAuth.On("CookieGetUserID", mock.Anything, mock.Anything).Return("user123", nil)
+			},
+			mockStorageSetup: func(mockStorage *mocks.Storage) {},
+			requestBody:      `{"url":"http://example.com","redirect_type":303}`,
+			expectedStatus:   http.StatusBadRequest,
+		},
+		{
+			name: "bad request on invalid redirect mode",
+			mockAuthSetup: func(mockAuth *mocks.Authenticator) {
+				mockAuth.On("CookieGetUserID", mock.Anything, mock.Anything).Return("user123", nil)
+			},
+			mockStorageSetup: func(mockStorage *mocks.Storage) {},
+			requestBody:      `{"url":"http://example.com","redirect_mode":"frame"}`,
+			expectedStatus:   http.StatusBadRequest,
+		},
 		{
 			name: "Auth error",
 			mockAuthSetup: func(mockAuth *mocks.Authenticator) {
@@ -167,6 +227,7 @@ func TestShortenJSON(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockAuth := new(mocks.Authenticator)
 			mockStorage := new(mocks.Storage)
+			mockStorage.On("GetUTMTemplate", mock.Anything, mock.Anything).Return(nil, shared.ErrNotFound).Maybe()
 			logger, _ := logging.New(zapcore.DebugLevel)
 
 			h := &URLHandler{
@@ -5,9 +5,9 @@ import (
 	"io"
 	"net/http"
 
+	"github.com/apetsko/shortugo/internal/audit"
 	"github.com/apetsko/shortugo/internal/models"
 	"github.com/apetsko/shortugo/internal/storages/shared"
-	"github.com/apetsko/shortugo/internal/utils"
 )
 
 // ShortenURL handles the shortening of a single URL.
@@ -22,6 +22,7 @@ import (
 // Response:
 //   - 201 Created: The URL shortening request is successful.
 //   - 400 Bad Request: Invalid request body or empty URL.
+//   - 403 Forbidden: The URL leads to a blocked domain.
 //   - 409 Conflict: The URL already exists.
 //   - 500 Internal Server Error: User authentication failed or other server error.
 func (h *URLHandler) ShortenURL(w http.ResponseWriter, r *http.Request) {
@@ -58,16 +59,31 @@ func (h *URLHandler) ShortenURL(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Refuse links to blocked domains
+	if h.Blocked(url, models.LinkOptions{}) {
+		http.Error(w, "Blocked domain", http.StatusForbidden)
+		return
+	}
+
+	ctx := r.Context()
+
+	// Tag the link with the user's UTM template
+	template, err := h.UTMTemplate(ctx, userID)
+	if err != nil {
+		h.Logger.Error("Failed to get UTM template", "error", err.Error())
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
 	// Generate a unique ID for the URL
 	IDlen := 8
 	record := models.URLRecord{
-		URL:    url,
-		ID:     utils.GenerateID(url, IDlen),
-		UserID: userID,
+		URL:         url,
+		ID:          LinkID(url, template, IDlen),
+		UserID:      userID,
+		LinkOptions: models.LinkOptions{UTM: template},
 	}
 
-	ctx := r.Context()
-
 	// Check if the URL already exists in the storage
 	shortenURL, err := h.Storage.Get(ctx, record.ID)
 	if err != nil {
@@ -82,6 +98,7 @@ func (h *URLHandler) ShortenURL(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
+		h.audit(r, userID, audit.ActionCreateLinks, []string{record.ID}, nil)
 
 		// Prepare the response with the shortened URL
 		newShortenURL := h.BaseURL + "/" + record.ID
@@ -24,6 +24,7 @@ import (
 func BenchmarkShortenURL(b *testing.B) {
 	logger, _ := logging.New(zapcore.DebugLevel)
 	mockStorage := new(mocks.Storage)
+	mockStorage.On("GetUTMTemplate", mock.Anything, mock.Anything).Return(nil, shared.ErrNotFound).Maybe()
 	mockAuth := new(mocks.Authenticator)
 	h := &URLHandler{
 		Storage: mockStorage,
@@ -196,6 +197,7 @@ func TestShortenURL(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockAuth := new(mocks.Authenticator)
 			mockStorage := new(mocks.Storage)
+			mockStorage.On("GetUTMTemplate", mock.Anything, mock.Anything).Return(nil, shared.ErrNotFound).Maybe()
 			logger, _ := logging.New(zapcore.DebugLevel)
 
 			h := &URLHandler{
@@ -20,7 +20,8 @@ import (
 //     403 Forbidden – if TrustedSubnet is not configured or IP is outside the allowed range
 //     500 Internal Server Error – if JSON encoding fails
 func (h *URLHandler) Stats(w http.ResponseWriter, r *http.Request) {
-	if h.TrustedSubnet == nil {
+	trusted := h.TrustedSubnet.Load()
+	if trusted == nil {
 		h.Logger.Error("Forbidden. TrustedSubnet is required")
 		w.WriteHeader(http.StatusForbidden)
 		return
@@ -34,7 +35,7 @@ func (h *URLHandler) Stats(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !h.TrustedSubnet.Contains(ip) {
+	if !trusted.Contains(ip) {
 		h.Logger.Errorf("Forbidden: IP %s not in trusted subnet", ip)
 		w.WriteHeader(http.StatusForbidden)
 		return
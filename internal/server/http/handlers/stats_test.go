@@ -87,10 +87,10 @@ func TestURLHandler_Stats(t *testing.T) {
 			}
 
 			h := &URLHandler{
-				Storage:       mockStorage,
-				Logger:        logger,
-				TrustedSubnet: tt.trustedSubnet,
+				Storage: mockStorage,
+				Logger:  logger,
 			}
+			h.TrustedSubnet.Store(tt.trustedSubnet)
 
 			req := httptest.NewRequest(http.MethodGet, "/api/internal/stats", nil)
 			req.Header.Set("X-Real-IP", tt.ipHeader)
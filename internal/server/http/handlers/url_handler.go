@@ -3,10 +3,18 @@ package handlers
 import (
 	"context"
 	"net"
+	"net/http"
+	"sync/atomic"
 
+	"github.com/apetsko/shortugo/internal/audit"
 	"github.com/apetsko/shortugo/internal/auth"
+	"github.com/apetsko/shortugo/internal/blocklist"
+	"github.com/apetsko/shortugo/internal/geoip"
+	"github.com/apetsko/shortugo/internal/health"
 	"github.com/apetsko/shortugo/internal/logging"
 	"github.com/apetsko/shortugo/internal/models"
+	"github.com/apetsko/shortugo/internal/qrcode"
+	"github.com/apetsko/shortugo/internal/ratelimit"
 )
 
 // Storage defines the interface for URL storage operations.
@@ -17,10 +25,54 @@ type Storage interface {
 	PutBatch(ctx context.Context, rr []models.URLRecord) error
 	// Get retrieves a URL by its ID.
 	Get(ctx context.Context, id string) (url string, err error)
+	// GetRecord retrieves the full URL record by its ID, including per-link options.
+	// It reports missing and deleted records with the same errors as Get.
+	GetRecord(ctx context.Context, id string) (*models.URLRecord, error)
+	// LookupRecord retrieves the URL record by its ID whatever its state: deleted, disabled and
+	// exhausted links are returned as well. Only a missing link is reported, with shared.ErrNotFound.
+	LookupRecord(ctx context.Context, id string) (*models.URLRecord, error)
 	// ListLinksByUserID lists all URLs associated with a user ID.
 	ListLinksByUserID(ctx context.Context, baseURL, userID string) (rr []models.URLRecord, err error)
+	// ForEachLinkByUserID walks the user's URLs one record at a time without loading them all into memory.
+	// Iteration stops at the first error returned by fn, which is then returned to the caller.
+	ForEachLinkByUserID(ctx context.Context, baseURL, userID string, fn func(r models.URLRecord) error) error
+	// ListLinksByFilter lists the user's URLs matching filter, or reports shared.ErrNotFound when none does.
+	ListLinksByFilter(ctx context.Context, baseURL, userID string, filter models.LinkFilter) ([]models.URLRecord, error)
+	// SearchLinks returns the user's URLs matching query, best matches first, at most search.MaxResults.
+	// No match is an empty result, not an error.
+	SearchLinks(ctx context.Context, baseURL, userID, query string) ([]models.URLRecord, error)
 	// DeleteUserURLs deletes URLs associated with a user ID.
 	DeleteUserURLs(ctx context.Context, IDs []string, userID string) (err error)
+	// PurgeDeleted removes the deleted links for good and returns how many were removed.
+	PurgeDeleted(ctx context.Context) (int64, error)
+	// UpdateLinkOptions changes the options of a link owned by userID through update.
+	// It reports a missing or foreign link with shared.ErrNotFound and a deleted one with shared.ErrGone;
+	// an error returned by update aborts the change and is returned as is.
+	UpdateLinkOptions(ctx context.Context, id, userID string, update func(o *models.LinkOptions) error) error
+	// AddLinkTags adds normalized tags to a link owned by userID; tags the link already has are kept once.
+	// It reports missing, foreign and deleted links like UpdateLinkOptions and shared.ErrTooManyTags
+	// when the link would get more than models.MaxTags tags.
+	AddLinkTags(ctx context.Context, id, userID string, tags []string) error
+	// RemoveLinkTags removes normalized tags from a link owned by userID; tags the link does not have are ignored.
+	RemoveLinkTags(ctx context.Context, id, userID string, tags []string) error
+	// SetLinkFolder files a link owned by userID in folder. An empty folder takes the link out of its folder.
+	SetLinkFolder(ctx context.Context, id, userID, folder string) error
+	// SetLinkDisabled disables or re-enables a link whoever owns it. Disabled links are reported
+	// like deleted ones by Get and GetRecord. A missing link is reported with shared.ErrNotFound.
+	SetLinkDisabled(ctx context.Context, id string, disabled bool) error
+	// ConsumeClick takes one of the remaining redirects of a link limited by MaxClicks, atomically.
+	// It reports a missing link with shared.ErrNotFound and a deleted or exhausted one with shared.ErrGone;
+	// links without a limit are left unchanged.
+	ConsumeClick(ctx context.Context, id string) error
+	// CountVariantClick adds one redirect to the click counter of an A/B variant of a link.
+	CountVariantClick(ctx context.Context, id, variant string) error
+	// VariantClicks returns the click counters of the A/B variants of a link by variant name.
+	// Variants that were never followed are missing from the map.
+	VariantClicks(ctx context.Context, id string) (map[string]int64, error)
+	// GetUTMTemplate retrieves the default UTM tags of a user, or shared.ErrNotFound when none are set.
+	GetUTMTemplate(ctx context.Context, userID string) (*models.UTM, error)
+	// PutUTMTemplate replaces the default UTM tags of a user. An empty template removes it.
+	PutUTMTemplate(ctx context.Context, userID string, t models.UTM) error
 	// Stats retrieves counts of url and users.
 	Stats(ctx context.Context) (*models.Stats, error)
 	// Ping checks the connection to the storage.
@@ -31,13 +83,34 @@ type Storage interface {
 
 // URLHandler handles URL shortening and related operations.
 type URLHandler struct {
-	Auth          auth.Authenticator             // Authenticator for user authentication.
-	Storage       Storage                        // Storage interface for URL operations.
-	ToDelete      chan models.BatchDeleteRequest // Channel for batch delete requests.
-	Logger        *logging.Logger                // Logger for logging operations.
-	TrustedSubnet *net.IPNet                     // indicates trusted subnet
-	Secret        string                         // Secret key for authentication.
-	BaseURL       string                         // Base URL for shortened links.
+	Auth             auth.Authenticator             // Authenticator for user authentication.
+	Storage          Storage                        // Storage interface for URL operations.
+	ToDelete         chan models.BatchDeleteRequest // Channel for batch delete requests.
+	Logger           *logging.Logger                // Logger for logging operations.
+	TrustedSubnet    atomic.Pointer[net.IPNet]      // Trusted subnet of /stats and the admin methods; nil trusts nobody. Replaced on reload.
+	QRCodes          *qrcode.Cache                  // Cache of rendered QR code images.
+	PasswordAttempts *ratelimit.Limiter             // Failed password attempts per client and link.
+	GeoIP            *geoip.DB                      // Country database for routing rules; nil disables country rules.
+	Blocklist        *blocklist.List                // Domains links may not lead to; nil blocks nothing.
+	Admins           *auth.Admins                   // Users and API keys allowed to use the admin API; nil allows nobody.
+	AuditLog         audit.Recorder                 // Records mutations and admin actions; nil writes them to Logger.
+	Health           *health.Checker                // Readiness of the service for probes; nil serves no probes.
+	Secret           string                         // Secret key for authentication.
+	BaseURL          string                         // Base URL for shortened links.
+	RedirectMode     string                         // Default redirect mode for links without one.
+	RedirectType     int                            // Default redirect status for links without one.
+}
+
+// UserID returns the ID of the user authenticated by the cookie of r, or "" for anonymous requests.
+func (h *URLHandler) UserID(r *http.Request) string {
+	if h.Auth == nil {
+		return ""
+	}
+	userID, err := h.Auth.CookieGetUserID(r, h.Secret)
+	if err != nil {
+		return ""
+	}
+	return userID
 }
 
 // NewURLHandler creates a new URLHandler instance.
@@ -47,13 +120,17 @@ func NewURLHandler(baseURL string, s Storage, l *logging.Logger, secret, trusted
 		l.Error("Invalid trusted subnet: " + trustedSubnet)
 		network = nil
 	}
-	return &URLHandler{
-		Auth:          new(auth.Auth),                       // Initialize the authenticator.
-		BaseURL:       baseURL,                              // Set the base URL.
-		Storage:       s,                                    // Set the storage interface.
-		Logger:        l,                                    // Set the logger.
-		Secret:        secret,                               // Set the secret key.
-		ToDelete:      make(chan models.BatchDeleteRequest), // Initialize the delete request channel.
-		TrustedSubnet: network,                              // indicates trusted subnet
+	h := &URLHandler{
+		Auth:             new(auth.Auth),                                                       // Initialize the authenticator.
+		BaseURL:          baseURL,                                                              // Set the base URL.
+		Storage:          s,                                                                    // Set the storage interface.
+		Logger:           l,                                                                    // Set the logger.
+		Secret:           secret,                                                               // Set the secret key.
+		ToDelete:         make(chan models.BatchDeleteRequest),                                 // Initialize the delete request channel.
+		QRCodes:          qrcode.NewCache(qrcode.DefaultCacheSize),                             // Initialize the QR code cache.
+		PasswordAttempts: ratelimit.New(ratelimit.DefaultMaxFailures, ratelimit.DefaultWindow), // Limit password guessing.
+		AuditLog:         audit.NewLog(l),                                                      // Record audit entries in the log.
 	}
+	h.TrustedSubnet.Store(network) // indicates trusted subnet
+	return h
 }
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/apetsko/shortugo/internal/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestURLHandler_UserID(t *testing.T) {
+	h := &URLHandler{Auth: new(auth.Auth), Secret: "secret"}
+
+	w := httptest.NewRecorder()
+	userID, err := h.Auth.CookieSetUserID(w, h.Secret)
+	require.NoError(t, err)
+
+	res := w.Result()
+	defer func() { _ = res.Body.Close() }()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	for _, c := range res.Cookies() {
+		req.AddCookie(c)
+	}
+	assert.Equal(t, userID, h.UserID(req))
+
+	// Anonymous, forged and unauthenticated requests
+	assert.Empty(t, h.UserID(httptest.NewRequest(http.MethodGet, "/", nil)))
+	forged := httptest.NewRequest(http.MethodGet, "/", nil)
+	forged.AddCookie(&http.Cookie{Name: "shortugo", Value: "admin"})
+	assert.Empty(t, h.UserID(forged))
+	assert.Empty(t, (&URLHandler{}).UserID(req))
+}
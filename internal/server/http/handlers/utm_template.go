@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/apetsko/shortugo/internal/audit"
+	"github.com/apetsko/shortugo/internal/models"
+	"github.com/apetsko/shortugo/internal/storages/shared"
+	"github.com/apetsko/shortugo/internal/utils"
+)
+
+// UTMTemplate returns the default UTM tags of a user, or no tags when the user has not set a template.
+// Shorten handlers fill the tags missing from a new link with it.
+func (h *URLHandler) UTMTemplate(ctx context.Context, userID string) (models.UTM, error) {
+	t, err := h.Storage.GetUTMTemplate(ctx, userID)
+	if err != nil {
+		if errors.Is(err, shared.ErrNotFound) {
+			return models.UTM{}, nil
+		}
+		return models.UTM{}, err
+	}
+	return *t, nil
+}
+
+// campaignSuffixLen is the length of the suffix telling the links of a URL apart by their UTM tags.
+const campaignSuffixLen = 4
+
+// LinkID returns the ID of a new link to url with the UTM tags utm, the user's template applied.
+// The ID is always the hash of the URL alone; tags are not part of that hash input, they are
+// appended to the destination at redirect time. So that each campaign on a URL still gets a link
+// of its own, links with tags take a separate "-" suffix derived from the tags only.
+func LinkID(url string, utm models.UTM, length int) string {
+	id := utils.GenerateID(url, length)
+	if utm.IsZero() {
+		return id
+	}
+	return id + "-" + utils.GenerateID(utm.Encode(), campaignSuffixLen)
+}
+
+// GetUTMTemplate returns the default UTM tags of the user.
+//
+// Request:
+//   - Method: GET
+//   - URL: /api/user/utm
+//
+// Response:
+//   - 200 OK: {"utm_source": "newsletter", "utm_medium": "email"}, or {} when no template is set.
+//   - 500 Internal Server Error: User authentication failed or other server error.
+func (h *URLHandler) GetUTMTemplate(w http.ResponseWriter, r *http.Request) {
+	// Retrieve the user ID from the cookie
+	userID, err := h.Auth.CookieGetUserID(r, h.Secret)
+	if err != nil {
+		// If the user ID is not found, set a new one
+		userID, err = h.Auth.CookieSetUserID(w, h.Secret)
+		if err != nil {
+			h.Logger.Error(err.Error())
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	t, err := h.UTMTemplate(r.Context(), userID)
+	if err != nil {
+		h.Logger.Error("Failed to get UTM template", "error", err.Error())
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(t); err != nil {
+		h.Logger.Error(err.Error())
+	}
+}
+
+// PutUTMTemplate replaces the default UTM tags of the user.
+// Links created afterwards get the tags they do not set themselves; existing links keep their tags.
+//
+// Request:
+//   - Method: PUT
+//   - URL: /api/user/utm
+//   - Headers: Content-Type: application/json
+//   - Body: {"utm_source": "newsletter", "utm_medium": "email", "utm_campaign": "spring"}
+//     An empty object removes the template.
+//
+// Response:
+//   - 200 OK: The stored template.
+//   - 400 Bad Request: Invalid request body or tags longer than 256 characters.
+//   - 500 Internal Server Error: User authentication failed or other server error.
+func (h *URLHandler) PutUTMTemplate(w http.ResponseWriter, r *http.Request) {
+	// Retrieve the user ID from the cookie
+	userID, err := h.Auth.CookieGetUserID(r, h.Secret)
+	if err != nil {
+		// If the user ID is not found, set a new one
+		userID, err = h.Auth.CookieSetUserID(w, h.Secret)
+		if err != nil {
+			h.Logger.Error(err.Error())
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	// Ensure the request body is closed after reading
+	defer func() {
+		if err2 := r.Body.Close(); err2 != nil {
+			h.Logger.Error("Failed to close request body", "error", err2.Error())
+		}
+	}()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	var t models.UTM
+	if err = json.Unmarshal(body, &t); err != nil {
+		h.Logger.Info("Error unmarshaling request body", "error", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = utils.ValidateStruct(t); err != nil {
+		h.Logger.Info("Invalid UTM template", "error", err.Error())
+		http.Error(w, "Invalid UTM template", http.StatusBadRequest)
+		return
+	}
+
+	if err = h.Storage.PutUTMTemplate(r.Context(), userID, t); err != nil {
+		h.Logger.Error("Failed to store UTM template", "error", err.Error())
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	h.audit(r, userID, audit.ActionUpdateUTM, []string{userID}, nil)
+
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(t); err != nil {
+		h.Logger.Error(err.Error())
+	}
+}
@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/apetsko/shortugo/internal/logging"
+	"github.com/apetsko/shortugo/internal/mocks"
+	"github.com/apetsko/shortugo/internal/models"
+	"github.com/apetsko/shortugo/internal/storages/shared"
+	"github.com/apetsko/shortugo/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestGetUTMTemplate(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+
+	tests := []struct {
+		mockStorageSetup func(mockStorage *mocks.Storage)
+		name             string
+		expectedBody     string
+		expectedStatus   int
+	}{
+		{
+			name: "template set",
+			mockStorageSetup: func(mockStorage *mocks.Storage) {
+				mockStorage.On("GetUTMTemplate", mock.Anything, "user123").
+					Return(&models.UTM{Source: "newsletter", Medium: "email"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"utm_source":"newsletter","utm_medium":"email"}`,
+		},
+		{
+			name: "no template",
+			mockStorageSetup: func(mockStorage *mocks.Storage) {
+				mockStorage.On("GetUTMTemplate", mock.Anything, "user123").Return(nil, shared.ErrNotFound)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{}`,
+		},
+		{
+			name: "storage error",
+			mockStorageSetup: func(mockStorage *mocks.Storage) {
+				mockStorage.On("GetUTMTemplate", mock.Anything, "user123").Return(nil, errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAuth := new(mocks.Authenticator)
+			mockAuth.On("CookieGetUserID", mock.Anything, mock.Anything).Return("user123", nil)
+			mockStorage := new(mocks.Storage)
+			tt.mockStorageSetup(mockStorage)
+
+			h := &URLHandler{
+				Auth:    mockAuth,
+				Storage: mockStorage,
+				Logger:  logger,
+			}
+
+			w := httptest.NewRecorder()
+			h.GetUTMTemplate(w, httptest.NewRequest(http.MethodGet, "/api/user/utm", nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+			mockStorage.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPutUTMTemplate(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+
+	tests := []struct {
+		mockStorageSetup func(mockStorage *mocks.Storage)
+		name             string
+		body             string
+		expectedStatus   int
+	}{
+		{
+			name: "template stored",
+			body: `{"utm_source":"newsletter","utm_campaign":"spring"}`,
+			mockStorageSetup: func(mockStorage *mocks.Storage) {
+				mockStorage.On("PutUTMTemplate", mock.Anything, "user123",
+					models.UTM{Source: "newsletter", Campaign: "spring"}).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "template removed",
+			body: `{}`,
+			mockStorageSetup: func(mockStorage *mocks.Storage) {
+				mockStorage.On("PutUTMTemplate", mock.Anything, "user123", models.UTM{}).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:             "invalid JSON",
+			body:             `{"utm_source":`,
+			mockStorageSetup: func(mockStorage *mocks.Storage) {},
+			expectedStatus:   http.StatusBadRequest,
+		},
+		{
+			name:             "tag too long",
+			body:             `{"utm_source":"` + strings.Repeat("a", 257) + `"}`,
+			mockStorageSetup: func(mockStorage *mocks.Storage) {},
+			expectedStatus:   http.StatusBadRequest,
+		},
+		{
+			name: "storage error",
+			body: `{"utm_source":"newsletter"}`,
+			mockStorageSetup: func(mockStorage *mocks.Storage) {
+				mockStorage.On("PutUTMTemplate", mock.Anything, "user123", mock.Anything).Return(errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAuth := new(mocks.Authenticator)
+			mockAuth.On("CookieGetUserID", mock.Anything, mock.Anything).Return("user123", nil)
+			mockStorage := new(mocks.Storage)
+			tt.mockStorageSetup(mockStorage)
+
+			h := &URLHandler{
+				Auth:    mockAuth,
+				Storage: mockStorage,
+				Logger:  logger,
+			}
+
+			w := httptest.NewRecorder()
+			h.PutUTMTemplate(w, httptest.NewRequest(http.MethodPut, "/api/user/utm", strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.JSONEq(t, tt.body, w.Body.String())
+			}
+			mockStorage.AssertExpectations(t)
+		})
+	}
+}
+
+func TestShortenJSON_UTMTemplate(t *testing.T) {
+	logger, _ := logging.New(zapcore.DebugLevel)
+	url := "https://example.com"
+	utm := models.UTM{Source: "ads", Medium: "email", Campaign: "spring"}
+	id := LinkID(url, utm, 8)
+	assert.Equal(t, utils.GenerateID(url, 8)+"-"+utils.GenerateID(utm.Encode(), 4), id, "the URL hash with a campaign suffix")
+
+	mockAuth := new(mocks.Authenticator)
+	mockAuth.On("CookieGetUserID", mock.Anything, mock.Anything).Return("user123", nil)
+	mockStorage := new(mocks.Storage)
+	mockStorage.On("GetUTMTemplate", mock.Anything, "user123").
+		Return(&models.UTM{Source: "newsletter", Medium: "email"}, nil)
+	mockStorage.On("Get", mock.Anything, id).Return("", shared.ErrNotFound)
+	// The link's own tags win over the template and are part of the ID with it.
+	mockStorage.On("Put", mock.Anything, models.URLRecord{
+		ID:          id,
+		URL:         url,
+		UserID:      "user123",
+		LinkOptions: models.LinkOptions{UTM: utm},
+	}).Return(nil)
+
+	h := &URLHandler{
+		Auth:    mockAuth,
+		Storage: mockStorage,
+		Logger:  logger,
+		BaseURL: "http://short.ly",
+	}
+
+	body := `{"url":"https://example.com","utm_source":"ads","utm_campaign":"spring"}`
+	w := httptest.NewRecorder()
+	h.ShortenJSON(w, httptest.NewRequest(http.MethodPost, "/api/shorten", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.JSONEq(t, `{"result":"http://short.ly/`+id+`"}`, w.Body.String())
+	mockStorage.AssertExpectations(t)
+}
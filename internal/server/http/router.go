@@ -1,11 +1,16 @@
 package http
 
 import (
+	"context"
 	"expvar"
 	"net/http/pprof"
 
+	"github.com/apetsko/shortugo/internal/health"
 	mw "github.com/apetsko/shortugo/internal/middleware"
+	"github.com/apetsko/shortugo/internal/requestid"
+	"github.com/apetsko/shortugo/internal/server/gateway"
 	"github.com/apetsko/shortugo/internal/server/http/handlers"
+	"github.com/apetsko/shortugo/internal/tracing"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 )
@@ -13,14 +18,24 @@ import (
 // Router initializes the router with all the necessary routes and middleware.
 func Router(handler *handlers.URLHandler) *chi.Mux {
 	r := chi.NewRouter()
+	// Middleware to trace each request, continuing the trace of the caller.
+	r.Use(tracing.Middleware)
+	// Middleware to give each request an ID, shared with the gRPC calls of the REST gateway.
+	r.Use(requestid.Middleware)
 	// Middleware to get the real IP address of the client.
 	r.Use(middleware.RealIP)
 	// Middleware to recover from panics and return a 500 error.
 	r.Use(middleware.Recoverer)
-	// Custom middleware to log the details of each request and response.
-	r.Use(mw.LogMiddleware(handler.Logger))
+	// Custom middleware to log the details of each request and response, sampled as configured.
+	r.Use(mw.LogMiddleware(handler.Logger.Access(), handler.UserID))
 	// Custom middleware to compress the response body using gzip.
 	r.Use(mw.GzipMiddleware(handler.Logger))
+	// Middleware to turn away the requests other than probes until the storage is ready.
+	if handler.Health != nil {
+		r.Use(handler.Health.Middleware)
+	}
+	// Middleware to trace the handler of each route apart from the middlewares above.
+	r.Use(tracing.Handler)
 
 	// Route to shorten a URL.
 	r.Post("/", handler.ShortenURL)
@@ -30,14 +45,67 @@ func Router(handler *handlers.URLHandler) *chi.Mux {
 	r.Post("/api/shorten/batch", handler.ShortenBatchJSON)
 	// Route to list all URLs associated with a user.
 	r.Get("/api/user/urls", handler.ListUserURLs)
+	// Route to search the URLs of a user.
+	r.Get("/api/user/urls/search", handler.SearchUserURLs)
+	// Routes to import links from CSV or JSONL and export them as such.
+	r.Post("/api/user/urls/import", handler.ImportUserURLs)
+	r.Get("/api/user/urls/export", handler.ExportUserURLs)
 	// Route to delete multiple URLs associated with a user.
 	r.Delete("/api/user/urls", handler.DeleteUserURLs)
+	// Routes to read and replace the default UTM tags of a user.
+	r.Get("/api/user/utm", handler.GetUTMTemplate)
+	r.Put("/api/user/utm", handler.PutUTMTemplate)
+	// Route to set or remove the password of a link.
+	r.Put("/api/user/urls/{id}/password", handler.SetLinkPassword)
+	// Routes to read and replace the routing rules of a link.
+	r.Get("/api/user/urls/{id}/rules", handler.GetLinkRules)
+	r.Put("/api/user/urls/{id}/rules", handler.PutLinkRules)
+	// Routes to read and replace the A/B variants of a link.
+	r.Get("/api/user/urls/{id}/variants", handler.GetLinkVariants)
+	r.Put("/api/user/urls/{id}/variants", handler.PutLinkVariants)
+	// Routes to add and remove the tags of a link.
+	r.Post("/api/user/urls/{id}/tags", handler.AddLinkTags)
+	r.Delete("/api/user/urls/{id}/tags", handler.RemoveLinkTags)
+	// Route to file a link in a folder.
+	r.Put("/api/user/urls/{id}/folder", handler.SetLinkFolder)
+	// Route to preview where a shortened URL leads.
+	r.Get("/{id}+", handler.PreviewURL)
 	// Route to expand a shortened URL.
 	r.Get("/{id}", handler.ExpandURL)
+	// Routes to submit the password form of a protected link.
+	r.Post("/{id}", handler.ExpandURL)
+	r.Post("/{id}/*", handler.ExpandURL)
+	// Route to render a QR code for a shortened URL.
+	r.Get("/{id}/qr", handler.QRCode)
+	// Route to expand a shortened URL forwarding the rest of the path.
+	r.Get("/{id}/*", handler.ExpandURL)
 	// Route to check the database connection.
 	r.Get("/ping", handler.PingDB)
 	// Route to list all URLs associated with a user.
 	r.Get("/api/internal/stats", handler.Stats)
+	// Routes of the liveness and readiness probes.
+	if handler.Health != nil {
+		r.Get(health.LivezPath, handler.Health.Livez)
+		r.Get(health.ReadyzPath, handler.Health.Readyz)
+	}
+
+	// Admin API: any link and user, for admins only.
+	r.Route("/api/admin", func(r chi.Router) {
+		r.Use(handler.AdminOnly)
+		r.Get("/urls/{id}", handler.AdminGetLink)
+		r.Post("/urls/{id}/disable", handler.AdminDisableLink)
+		r.Post("/urls/{id}/enable", handler.AdminEnableLink)
+		r.Get("/users/{user_id}/urls", handler.AdminListUserLinks)
+		r.Get("/audit", handler.AdminAudit)
+	})
+
+	// REST API generated from the gRPC service definition, see proto/shortugo.proto.
+	gw, err := gateway.New(context.Background(), handler)
+	if err != nil {
+		handler.Logger.Error("failed to initialize gRPC gateway", "error", err.Error())
+	} else {
+		r.Handle(gateway.Prefix+"/*", gw)
+	}
 
 	r.Route("/debug/pprof", func(r chi.Router) {
 		r.HandleFunc("/*", pprof.Index)
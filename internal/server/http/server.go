@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/apetsko/shortugo/internal/certs"
 	"github.com/apetsko/shortugo/internal/config"
 	"github.com/apetsko/shortugo/internal/logging"
 	"github.com/apetsko/shortugo/internal/server/http/handlers"
@@ -16,6 +17,7 @@ import (
 // Run creates and configures and run a new HTTP server.
 // a is the address the server will listen on.
 // h is the handler that will be used by router the incoming requests.
+// With HTTPS the certificate is reloaded when its files change, until the server shuts down.
 func Run(cfg *config.Config, h *handlers.URLHandler, logger *logging.Logger) (*http.Server, error) {
 	srv := &http.Server{
 		Addr:              cfg.Host,
@@ -23,6 +25,18 @@ func Run(cfg *config.Config, h *handlers.URLHandler, logger *logging.Logger) (*h
 		ReadHeaderTimeout: 3 * time.Second,
 	}
 
+	if cfg.EnableHTTPS {
+		m, err := certs.NewManager(cfg.TLSCertPath, cfg.TLSKeyPath, logger)
+		if err != nil {
+			return nil, err
+		}
+		srv.TLSConfig = m.TLSConfig()
+
+		watchCtx, stopWatch := context.WithCancel(context.Background())
+		srv.RegisterOnShutdown(stopWatch)
+		go m.Watch(watchCtx, cfg.TLSReloadInterval)
+	}
+
 	g, ctx := errgroup.WithContext(context.Background())
 
 	g.Go(func() error {
@@ -36,7 +50,7 @@ func Run(cfg *config.Config, h *handlers.URLHandler, logger *logging.Logger) (*h
 	g.Go(func() error {
 		logger.Info(fmt.Sprintf("Starting HTTP server at %s, TLS: %t", srv.Addr, cfg.EnableHTTPS))
 		if cfg.EnableHTTPS {
-			return srv.ListenAndServeTLS(cfg.TLSCertPath, cfg.TLSKeyPath)
+			return srv.ListenAndServeTLS("", "")
 		}
 		return srv.ListenAndServe()
 	})
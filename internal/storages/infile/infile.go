@@ -8,20 +8,32 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"net/http"
+	"maps"
 	"os"
+	"path/filepath"
 	"slices"
+	"strings"
 	"sync"
 
 	"github.com/apetsko/shortugo/internal/models"
+	"github.com/apetsko/shortugo/internal/search"
 	"github.com/apetsko/shortugo/internal/storages/shared"
 )
 
 // Storage represents a storage backed by a file.
+//
+// Records are appended to the file and the first record with an ID wins; changes of existing links rewrite
+// the file through a temporary file. Redirects taken from links limited by MaxClicks are appended to
+// a clicks file next to it instead, so a redirect does not rewrite the storage file: they are subtracted
+// from ClicksLeft when records are read, and written into the records at the next rewrite.
 type Storage struct {
-	file    *os.File
-	encoder *json.Encoder
-	mu      sync.Mutex
+	path     string
+	file     *os.File
+	encoder  *json.Encoder
+	clicks   *os.File       // Log of the redirects taken since the storage file was last rewritten, one link ID per line.
+	consumed map[string]int // Redirects in the clicks log by link ID.
+	index    *search.Index  // Words of the non-deleted links, built when the storage is opened.
+	mu       sync.Mutex     // Guards file, encoder, clicks and consumed.
 }
 
 // CustomBool is a custom boolean type for JSON marshaling/unmarshaling.
@@ -30,6 +42,9 @@ type CustomBool bool
 // FilePermUserRWGroupROthersR File permissions for user read/write, group read, others read.
 const FilePermUserRWGroupROthersR = 0644
 
+// clicksSuffix names the file next to the storage file that logs the redirects taken from links limited by MaxClicks.
+const clicksSuffix = ".clicks"
+
 // UnmarshalJSON unmarshals a boolean from JSON.
 func (b *CustomBool) UnmarshalJSON(data []byte) error {
 	if len(data) == 0 {
@@ -60,15 +75,81 @@ func New(filename string) (*Storage, error) {
 		return nil, err
 	}
 
-	return &Storage{
-		file:    f,
-		encoder: json.NewEncoder(f),
-	}, nil
+	clicks, err := os.OpenFile(filename+clicksSuffix, os.O_RDWR|os.O_CREATE|os.O_APPEND, FilePermUserRWGroupROthersR)
+	if err != nil {
+		return nil, errors.Join(err, f.Close())
+	}
+
+	s := &Storage{
+		path:     filename,
+		file:     f,
+		encoder:  json.NewEncoder(f),
+		clicks:   clicks,
+		consumed: make(map[string]int),
+		index:    search.NewIndex(),
+	}
+	if err := s.readClicks(); err != nil {
+		return nil, errors.Join(err, f.Close(), clicks.Close())
+	}
+	if err := s.buildIndex(); err != nil {
+		return nil, errors.Join(err, f.Close(), clicks.Close())
+	}
+	return s, nil
+}
+
+// readClicks counts the redirects in the clicks log.
+func (f *Storage) readClicks() error {
+	scanner := bufio.NewScanner(f.clicks)
+	for scanner.Scan() {
+		if id := scanner.Text(); id != "" {
+			f.consumed[id]++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading clicks file: %w", err)
+	}
+	return nil
+}
+
+// buildIndex indexes the links already in the storage file.
+// The first record with an ID decides whether the link exists, as in GetRecord.
+func (f *Storage) buildIndex() error {
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(f.file)
+	for scanner.Scan() {
+		r, err := f.parseRecord(scanner.Bytes())
+		if err != nil {
+			return err
+		}
+
+		if seen[r.ID] {
+			continue
+		}
+		seen[r.ID] = true
+		if !r.Deleted {
+			f.index.Add(*r)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading file: %w", err)
+	}
+	return nil
+}
+
+// indexNew indexes r unless it is deleted or a link with its ID is stored already, which shadows the new record.
+func (f *Storage) indexNew(r models.URLRecord) {
+	if !r.Deleted && !f.index.Has(r.ID) {
+		f.index.Add(r)
+	}
 }
 
-// Close closes the storage file.
+// Close closes the storage file and the clicks log.
 func (f *Storage) Close() error {
-	return f.file.Close()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return errors.Join(f.file.Close(), f.clicks.Close())
 }
 
 // Put stores a URLRecord in the storage.
@@ -80,9 +161,10 @@ func (f *Storage) Put(ctx context.Context, r models.URLRecord) (err error) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
-	if err := f.encoder.Encode(r); err != nil {
+	if err := f.encoder.Encode(shared.WithCreatedAt(r)); err != nil {
 		return err
 	}
+	f.indexNew(r)
 
 	if err := ctx.Err(); err != nil {
 		return err
@@ -97,14 +179,18 @@ func (f *Storage) Put(ctx context.Context, r models.URLRecord) (err error) {
 
 // PutBatch stores multiple URLRecords in the storage.
 func (f *Storage) PutBatch(ctx context.Context, rr []models.URLRecord) (err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
 	for _, r := range rr {
 		if err := ctx.Err(); err != nil {
 			return err
 		}
 
-		if err := f.encoder.Encode(r); err != nil {
+		if err := f.encoder.Encode(shared.WithCreatedAt(r)); err != nil {
 			return err
 		}
+		f.indexNew(r)
 
 		if err := f.file.Sync(); err != nil {
 			return fmt.Errorf("error sync file: %w", err)
@@ -120,33 +206,74 @@ func (f *Storage) Get(ctx context.Context, shortURL string) (string, error) {
 		return "", err
 	}
 
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	r, err := f.findRecord(shortURL)
+	if err != nil {
+		return "", err
+	}
+	if r.Unavailable() {
+		return "", shared.ErrGone
+	}
+	return r.URL, nil
+}
+
+// GetRecord retrieves the URL record for a given short URL.
+func (f *Storage) GetRecord(ctx context.Context, shortURL string) (*models.URLRecord, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	r, err := f.findRecord(shortURL)
+	if err != nil {
+		return nil, err
+	}
+	if r.Unavailable() {
+		return nil, shared.ErrGone
+	}
+	return r, nil
+}
+
+// LookupRecord retrieves the URL record for a given short URL, deleted, disabled and exhausted ones included.
+func (f *Storage) LookupRecord(ctx context.Context, shortURL string) (*models.URLRecord, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.findRecord(shortURL)
+}
+
+// findRecord returns the first record with the given ID in the storage file. The caller must hold f.mu.
+func (f *Storage) findRecord(shortURL string) (*models.URLRecord, error) {
 	if _, err := f.file.Seek(0, 0); err != nil {
-		return "", fmt.Errorf("error setting file seek: %w", err)
+		return nil, fmt.Errorf("error setting file seek: %w", err)
 	}
 
-	r := new(models.URLRecord)
 	scanner := bufio.NewScanner(f.file)
 	for scanner.Scan() {
-		data := scanner.Bytes()
-		err := json.Unmarshal(data, &r)
+		r, err := f.parseRecord(scanner.Bytes())
 		if err != nil {
-			return "", fmt.Errorf("failed unmarshal: %w", err)
+			return nil, fmt.Errorf("failed unmarshal: %w", err)
 		}
-		fmt.Println("r", r)
-		if r.ID == shortURL {
-			if r.Deleted {
-				return "", errors.New(http.StatusText(http.StatusGone))
-			}
 
-			return r.URL, nil
+		if r.ID == shortURL {
+			r.ClicksLeft -= f.consumed[r.ID]
+			return r, nil
 		}
 	}
 
 	if err := scanner.Err(); err != nil {
-		return "", fmt.Errorf("error reading file: %w", err)
+		return nil, fmt.Errorf("error reading file: %w", err)
 	}
 
-	return "", fmt.Errorf("URL not found: %s. %w", shortURL, shared.ErrNotFound)
+	return nil, fmt.Errorf("URL not found: %s. %w", shortURL, shared.ErrNotFound)
 }
 
 // ListLinksByUserID lists all URLs associated with a user ID.
@@ -155,20 +282,24 @@ func (f *Storage) ListLinksByUserID(ctx context.Context, baseURL, userID string)
 		return nil, err
 	}
 
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
 	if _, err := f.file.Seek(0, 0); err != nil {
 		return nil, fmt.Errorf("error setting file seek: %w", err)
 	}
 
 	rr := make([]models.URLRecord, 0)
-	r := new(models.URLRecord)
 	scanner := bufio.NewScanner(f.file)
 	for scanner.Scan() {
-		data := scanner.Bytes()
-		if err := json.Unmarshal(data, r); err != nil {
+		// Every line gets a fresh record, so slices and optional fields do not leak between records.
+		r, err := f.parseRecord(scanner.Bytes())
+		if err != nil {
 			return nil, err
 		}
 
 		if r.UserID == userID && !r.Deleted {
+			r.ClicksLeft -= f.consumed[r.ID]
 			r.ID = baseURL + "/" + r.ID
 			rr = append(rr, *r)
 		}
@@ -184,47 +315,446 @@ func (f *Storage) ListLinksByUserID(ctx context.Context, baseURL, userID string)
 	return rr, nil
 }
 
-// DeleteUserURLs deletes multiple URLs associated with a user ID.
-func (f *Storage) DeleteUserURLs(ctx context.Context, ids []string, userID string) error {
+// ForEachLinkByUserID calls fn for every non-deleted URL associated with a user ID.
+// The file is read through its own descriptor, so a slow consumer does not block writers.
+func (f *Storage) ForEachLinkByUserID(ctx context.Context, baseURL, userID string, fn func(r models.URLRecord) error) (err error) {
 	if err := ctx.Err(); err != nil {
 		return err
 	}
 
-	tmpFilename := f.file.Name() + ".tmp"
-	tmpFile, err := os.OpenFile(tmpFilename, os.O_CREATE|os.O_WRONLY, FilePermUserRWGroupROthersR)
+	file, consumed, err := f.snapshot()
 	if err != nil {
-		return fmt.Errorf("error creating temp file: %w", err)
+		return err
 	}
+	defer func() {
+		if closeErr := file.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("error closing storage file: %w", closeErr)
+		}
+	}()
 
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		r, err := f.parseRecord(scanner.Bytes())
+		if err != nil {
+			return err
+		}
+
+		if r.UserID != userID || r.Deleted {
+			continue
+		}
+
+		r.ClicksLeft -= consumed[r.ID]
+		r.ID = baseURL + "/" + r.ID
+		if err := fn(*r); err != nil {
+			return err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading file: %w", err)
+	}
+
+	return nil
+}
+
+// ForEachRecord calls fn for every stored link, deleted ones included, in ID order starting after the ID after.
+// IDs are not prefixed. As everywhere in the file, the first record with an ID wins;
+// the records are read into memory to be sorted, so fn may write to the storage.
+func (f *Storage) ForEachRecord(ctx context.Context, after string, fn func(r models.URLRecord) error) (err error) {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	file, consumed, err := f.snapshot()
+	if err != nil {
+		return err
+	}
 	defer func() {
-		closeErr := tmpFile.Close()
-		if closeErr != nil && err == nil {
-			err = fmt.Errorf("error closing temp file: %w", closeErr)
+		if closeErr := file.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("error closing storage file: %w", closeErr)
 		}
 	}()
 
+	var rr []models.URLRecord
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		r, err := f.parseRecord(scanner.Bytes())
+		if err != nil {
+			return err
+		}
+
+		if seen[r.ID] {
+			continue
+		}
+		seen[r.ID] = true
+		if r.ID > after {
+			r.ClicksLeft -= consumed[r.ID]
+			rr = append(rr, *r)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading file: %w", err)
+	}
+
+	slices.SortFunc(rr, func(a, b models.URLRecord) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	for _, r := range rr {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// snapshot opens the storage file through its own descriptor, so it can be read without holding f.mu,
+// and returns it with the redirects logged since it was written.
+func (f *Storage) snapshot() (*os.File, map[string]int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file, err := os.Open(f.path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error opening storage file: %w", err)
+	}
+	return file, maps.Clone(f.consumed), nil
+}
+
+// ListLinksByFilter lists the non-deleted URLs of a user matching filter.
+func (f *Storage) ListLinksByFilter(ctx context.Context, baseURL, userID string, filter models.LinkFilter) ([]models.URLRecord, error) {
+	var rr []models.URLRecord
+	err := f.ForEachLinkByUserID(ctx, baseURL, userID, func(r models.URLRecord) error {
+		if filter.Matches(r) {
+			rr = append(rr, r)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rr) == 0 {
+		return nil, fmt.Errorf("URLs not found for UserID: %s. %w", userID, shared.ErrNotFound)
+	}
+	return rr, nil
+}
+
+// SearchLinks returns the non-deleted URLs of a user matching query, best matches first.
+// The index picks the links, their records are then read in a single pass over the file.
+func (f *Storage) SearchLinks(ctx context.Context, baseURL, userID, query string) ([]models.URLRecord, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	ids := f.index.Search(userID, search.Terms(query))
+	if len(ids) == 0 {
+		return []models.URLRecord{}, nil
+	}
+
+	rank := make(map[string]int, len(ids))
+	for i, id := range ids {
+		rank[baseURL+"/"+id] = i
+	}
+
+	found := make([]*models.URLRecord, len(ids))
+	err := f.ForEachLinkByUserID(ctx, baseURL, userID, func(r models.URLRecord) error {
+		if i, ok := rank[r.ID]; ok && found[i] == nil {
+			found[i] = &r
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rr := make([]models.URLRecord, 0, len(ids))
+	for _, r := range found {
+		if r != nil {
+			rr = append(rr, *r)
+		}
+	}
+	return rr, nil
+}
+
+// DeleteUserURLs deletes multiple URLs associated with a user ID.
+// The storage file is rewritten through a temporary file.
+func (f *Storage) DeleteUserURLs(ctx context.Context, ids []string, userID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	err := f.rewrite(func(r *models.URLRecord) (*models.URLRecord, error) {
+		if shouldDelete(r, ids, userID) {
+			r.Deleted = true
+		}
+		return r, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	f.index.Remove(userID, ids...)
+	return nil
+}
+
+// PurgeDeleted removes the deleted links from the storage file for good and returns how many were removed.
+// As everywhere in the file, the first record with an ID decides whether the link is deleted;
+// all records with the ID are dropped then. The file is rewritten through a temporary file.
+func (f *Storage) PurgeDeleted(ctx context.Context) (purged int64, err error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	deleted, err := f.deletedIDs()
+	if err != nil || len(deleted) == 0 {
+		return 0, err
+	}
+
+	err = f.rewrite(func(r *models.URLRecord) (*models.URLRecord, error) {
+		if deleted[r.ID] {
+			return nil, nil
+		}
+		return r, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(deleted)), nil
+}
+
+// deletedIDs returns the IDs whose first record in the storage file is deleted.
+func (f *Storage) deletedIDs() (map[string]bool, error) {
+	if _, err := f.file.Seek(0, 0); err != nil {
+		return nil, fmt.Errorf("error setting file seek: %w", err)
+	}
+
+	deleted := make(map[string]bool)
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(f.file)
+	for scanner.Scan() {
+		r, err := f.parseRecord(scanner.Bytes())
+		if err != nil {
+			return nil, err
+		}
+
+		if seen[r.ID] {
+			continue
+		}
+		seen[r.ID] = true
+		if r.Deleted {
+			deleted[r.ID] = true
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading file: %w", err)
+	}
+	return deleted, nil
+}
+
+// UpdateLinkOptions changes the options of a link owned by userID.
+// The storage file is rewritten through a temporary file, like DeleteUserURLs does.
+func (f *Storage) UpdateLinkOptions(ctx context.Context, id, userID string, update func(o *models.LinkOptions) error) error {
+	return f.updateOwnRecord(ctx, id, userID, func(r *models.URLRecord) error {
+		return update(&r.LinkOptions)
+	})
+}
+
+// AddLinkTags adds tags to a link owned by userID.
+func (f *Storage) AddLinkTags(ctx context.Context, id, userID string, tags []string) error {
+	return f.updateOwnRecord(ctx, id, userID, func(r *models.URLRecord) error {
+		merged := models.NormalizeTags(append(slices.Clone(r.Tags), tags...))
+		if len(merged) > models.MaxTags {
+			return shared.ErrTooManyTags
+		}
+		r.Tags = merged
+		return nil
+	})
+}
+
+// RemoveLinkTags removes tags from a link owned by userID.
+func (f *Storage) RemoveLinkTags(ctx context.Context, id, userID string, tags []string) error {
+	return f.updateOwnRecord(ctx, id, userID, func(r *models.URLRecord) error {
+		r.Tags = slices.DeleteFunc(r.Tags, func(tag string) bool {
+			return slices.Contains(tags, tag)
+		})
+		if len(r.Tags) == 0 {
+			r.Tags = nil
+		}
+		return nil
+	})
+}
+
+// SetLinkFolder files a link owned by userID in folder.
+func (f *Storage) SetLinkFolder(ctx context.Context, id, userID, folder string) error {
+	return f.updateOwnRecord(ctx, id, userID, func(r *models.URLRecord) error {
+		r.Folder = folder
+		return nil
+	})
+}
+
+// updateOwnRecord rewrites a non-deleted link owned by userID through update.
+func (f *Storage) updateOwnRecord(ctx context.Context, id, userID string, update func(r *models.URLRecord) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var updated models.URLRecord
+	err := f.rewriteRecord(id, func(r *models.URLRecord) error {
+		switch {
+		case r.UserID != userID:
+			return fmt.Errorf("URL not found: %s. %w", id, shared.ErrNotFound)
+		case r.Deleted:
+			return shared.ErrGone
+		}
+		if err := update(r); err != nil {
+			return err
+		}
+		updated = *r
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	f.index.Add(updated)
+	return nil
+}
+
+// SetLinkDisabled disables or re-enables the link with the given ID, whoever owns it.
+func (f *Storage) SetLinkDisabled(ctx context.Context, id string, disabled bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.rewriteRecord(id, func(r *models.URLRecord) error {
+		r.Disabled = disabled
+		return nil
+	})
+}
+
+// ConsumeClick takes one of the remaining redirects of a link limited by MaxClicks.
+// The redirect is checked and appended to the clicks log under the storage lock, so the storage file
+// is not rewritten for every redirect.
+func (f *Storage) ConsumeClick(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	r, err := f.findRecord(id)
+	switch {
+	case err != nil:
+		return err
+	case r.Unavailable():
+		return shared.ErrGone
+	case r.MaxClicks == 0:
+		return nil
+	}
+
+	if _, err := f.clicks.WriteString(id + "\n"); err != nil {
+		return fmt.Errorf("error writing clicks file: %w", err)
+	}
+	if err := f.clicks.Sync(); err != nil {
+		return fmt.Errorf("error sync clicks file: %w", err)
+	}
+	f.consumed[id]++
+	return nil
+}
+
+// rewriteRecord replaces the record with the given ID by the result of update, rewriting the storage file.
+// The first record with the ID decides whether the link exists, as in GetRecord; later duplicates of the ID
+// owned by the same user get the same update. An error returned by update aborts the rewrite.
+// The caller must hold f.mu.
+func (f *Storage) rewriteRecord(id string, update func(r *models.URLRecord) error) error {
+	var updated *models.URLRecord
+	err := f.rewrite(func(r *models.URLRecord) (*models.URLRecord, error) {
+		if r.ID != id {
+			return r, nil
+		}
+		if updated == nil {
+			if err := update(r); err != nil {
+				return nil, err
+			}
+			updated = r
+		} else if r.UserID == updated.UserID {
+			r = updated
+		}
+		return r, nil
+	})
+	if err != nil {
+		return err
+	}
+	if updated == nil {
+		return fmt.Errorf("URL not found: %s. %w", id, shared.ErrNotFound)
+	}
+	return nil
+}
+
+// rewrite replaces the storage file by its records passed through edit, which returns the record to write
+// or nil to leave it out. The records are written to a temporary file next to the storage file, which
+// replaces it once complete; an error of edit aborts the rewrite and leaves the storage file untouched.
+// The logged redirects are written into the records and the clicks log is emptied. The caller must hold f.mu.
+func (f *Storage) rewrite(edit func(r *models.URLRecord) (*models.URLRecord, error)) (err error) {
+	tmpFile, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("error creating temp file: %w", err)
+	}
+
 	defer func() {
 		if err != nil {
-			removeErr := os.Remove(tmpFilename)
-			if removeErr != nil {
+			_ = tmpFile.Close()
+			if removeErr := os.Remove(tmpFile.Name()); removeErr != nil && !errors.Is(removeErr, os.ErrNotExist) {
 				err = fmt.Errorf("error removing temp file: %w (original error: %v)", removeErr, err)
 			}
 		}
 	}()
 
-	if err := f.copyAndMarkDeleted(tmpFile, ids, userID); err != nil {
+	if err = tmpFile.Chmod(FilePermUserRWGroupROthersR); err != nil {
+		return fmt.Errorf("error setting temp file mode: %w", err)
+	}
+	if err = f.copyRecords(tmpFile, edit); err != nil {
 		return err
 	}
+	if err = tmpFile.Sync(); err != nil {
+		return fmt.Errorf("error sync temp file: %w", err)
+	}
+	if err = tmpFile.Close(); err != nil {
+		return fmt.Errorf("error closing temp file: %w", err)
+	}
 
-	if err := f.replaceFile(tmpFilename); err != nil {
+	if err = f.replaceFile(tmpFile.Name()); err != nil {
 		return err
 	}
-
-	return nil
+	return f.resetClicks()
 }
 
-// copyAndMarkDeleted copies records to a temporary file and marks specified records as deleted.
-func (f *Storage) copyAndMarkDeleted(tmpFile *os.File, ids []string, userID string) error {
+// copyRecords writes the records of the storage file passed through edit to tmpFile, with the logged redirects applied.
+func (f *Storage) copyRecords(tmpFile *os.File, edit func(r *models.URLRecord) (*models.URLRecord, error)) error {
 	if _, err := f.file.Seek(0, 0); err != nil {
 		return fmt.Errorf("error setting file seek: %w", err)
 	}
@@ -237,9 +767,13 @@ func (f *Storage) copyAndMarkDeleted(tmpFile *os.File, ids []string, userID stri
 		if err != nil {
 			return err
 		}
+		r.ClicksLeft -= f.consumed[r.ID]
 
-		if shouldDelete(r, ids, userID) {
-			r.Deleted = true
+		if r, err = edit(r); err != nil {
+			return err
+		}
+		if r == nil {
+			continue
 		}
 
 		if err := writeRecord(writer, r); err != nil {
@@ -286,32 +820,201 @@ func writeRecord(writer *bufio.Writer, r *models.URLRecord) error {
 	return nil
 }
 
-// replaceFile replaces the original storage file with the temporary file.
+// replaceFile replaces the storage file with the temporary file and reopens it.
 func (f *Storage) replaceFile(tmpFilename string) error {
+	if err := os.Rename(tmpFilename, f.path); err != nil {
+		return fmt.Errorf("error replacing storage file: %w", err)
+	}
+
+	file, err := os.OpenFile(f.path, os.O_RDWR|os.O_CREATE|os.O_APPEND, FilePermUserRWGroupROthersR)
+	if err != nil {
+		return fmt.Errorf("error reopening storage file: %w", err)
+	}
 	if err := f.file.Close(); err != nil {
-		return fmt.Errorf("error closing temp file: %w", err)
+		_ = file.Close()
+		return fmt.Errorf("error closing replaced storage file: %w", err)
 	}
+	f.file = file
+	f.encoder = json.NewEncoder(file)
 
-	if err := os.Rename(tmpFilename, f.file.Name()); err != nil {
-		return fmt.Errorf("error replacing storage file: %w", err)
+	return nil
+}
+
+// resetClicks empties the clicks log once its redirects are written into the storage file.
+// A crash in between counts them twice after a restart, which ends limited links early rather than late.
+func (f *Storage) resetClicks() error {
+	if err := f.clicks.Truncate(0); err != nil {
+		return fmt.Errorf("error truncating clicks file: %w", err)
 	}
+	clear(f.consumed)
+	return nil
+}
+
+// utmTemplatesSuffix names the file next to the storage file that keeps the default UTM tags of users.
+const utmTemplatesSuffix = ".utm.json"
 
-	var err error
-	f.file, err = os.OpenFile(f.file.Name(), os.O_RDWR|os.O_CREATE|os.O_APPEND, FilePermUserRWGroupROthersR)
+// GetUTMTemplate retrieves the default UTM tags of a user.
+func (f *Storage) GetUTMTemplate(ctx context.Context, userID string) (*models.UTM, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	templates, err := f.readUTMTemplates()
 	if err != nil {
-		return fmt.Errorf("error reopening storage file: %w", err)
+		return nil, err
+	}
+
+	t, ok := templates[userID]
+	if !ok {
+		return nil, fmt.Errorf("UTM template not found for UserID: %s. %w", userID, shared.ErrNotFound)
+	}
+	return &t, nil
+}
+
+// PutUTMTemplate replaces the default UTM tags of a user.
+// The templates file is rewritten through a temporary file, so a failed write keeps the previous templates.
+func (f *Storage) PutUTMTemplate(ctx context.Context, userID string, t models.UTM) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	templates, err := f.readUTMTemplates()
+	if err != nil {
+		return err
+	}
+
+	if t.IsZero() {
+		delete(templates, userID)
+	} else {
+		templates[userID] = t
+	}
+
+	data, err := json.Marshal(templates)
+	if err != nil {
+		return err
+	}
+
+	filename := f.path + utmTemplatesSuffix
+	if err := os.WriteFile(filename+".tmp", data, FilePermUserRWGroupROthersR); err != nil {
+		return fmt.Errorf("error writing UTM templates: %w", err)
+	}
+	if err := os.Rename(filename+".tmp", filename); err != nil {
+		return fmt.Errorf("error replacing UTM templates file: %w", err)
+	}
+
+	return nil
+}
+
+// readUTMTemplates loads the default UTM tags of all users. A missing file means no templates.
+func (f *Storage) readUTMTemplates() (map[string]models.UTM, error) {
+	templates := make(map[string]models.UTM)
+
+	data, err := os.ReadFile(f.path + utmTemplatesSuffix)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return templates, nil
+		}
+		return nil, fmt.Errorf("error reading UTM templates: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &templates); err != nil {
+		return nil, fmt.Errorf("failed unmarshal UTM templates: %w", err)
+	}
+	return templates, nil
+}
+
+// variantClicksSuffix names the file next to the storage file that keeps the click counters of A/B variants.
+const variantClicksSuffix = ".variants.json"
+
+// CountVariantClick adds one redirect to the click counter of an A/B variant of a link.
+// The counters file is rewritten through a temporary file, so a failed write keeps the previous counts.
+func (f *Storage) CountVariantClick(ctx context.Context, id, variant string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	counters, err := f.readVariantClicks()
+	if err != nil {
+		return err
+	}
+
+	if counters[id] == nil {
+		counters[id] = make(map[string]int64)
+	}
+	counters[id][variant]++
+
+	data, err := json.Marshal(counters)
+	if err != nil {
+		return err
+	}
+
+	filename := f.path + variantClicksSuffix
+	if err := os.WriteFile(filename+".tmp", data, FilePermUserRWGroupROthersR); err != nil {
+		return fmt.Errorf("error writing variant clicks: %w", err)
+	}
+	if err := os.Rename(filename+".tmp", filename); err != nil {
+		return fmt.Errorf("error replacing variant clicks file: %w", err)
 	}
-	f.encoder = json.NewEncoder(f.file)
 
 	return nil
 }
 
+// VariantClicks returns the click counters of the A/B variants of a link by variant name.
+func (f *Storage) VariantClicks(ctx context.Context, id string) (map[string]int64, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	counters, err := f.readVariantClicks()
+	if err != nil {
+		return nil, err
+	}
+
+	if counters[id] == nil {
+		return make(map[string]int64), nil
+	}
+	return counters[id], nil
+}
+
+// readVariantClicks loads the click counters of all links. A missing file means no clicks.
+func (f *Storage) readVariantClicks() (map[string]map[string]int64, error) {
+	counters := make(map[string]map[string]int64)
+
+	data, err := os.ReadFile(f.path + variantClicksSuffix)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return counters, nil
+		}
+		return nil, fmt.Errorf("error reading variant clicks: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &counters); err != nil {
+		return nil, fmt.Errorf("failed unmarshal variant clicks: %w", err)
+	}
+	return counters, nil
+}
+
 // Stats retrieves count stats: urls and users.
 func (f *Storage) Stats(ctx context.Context) (*models.Stats, error) {
 	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
 
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
 	if _, err := f.file.Seek(0, 0); err != nil {
 		return nil, fmt.Errorf("error seeking file: %w", err)
 	}
@@ -2,8 +2,14 @@ package infile
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/apetsko/shortugo/internal/models"
 	"github.com/apetsko/shortugo/internal/storages/shared"
@@ -12,22 +18,25 @@ import (
 )
 
 func setupTempStorage(t *testing.T) (*Storage, func()) {
-	tmpFile, err := os.CreateTemp("", "test_storage")
-	require.NoError(t, err, "failed to create temp file")
-
-	store, err := New(tmpFile.Name())
+	// The storage keeps files next to its own, so it gets a directory the test removes.
+	store, err := New(filepath.Join(t.TempDir(), "test_storage"))
 	require.NoError(t, err, "failed to create storage")
 
 	return store, func() {
 		if err := store.Close(); err != nil {
 			t.Errorf("failed to close storage: %v", err)
 		}
-		if err := os.Remove(tmpFile.Name()); err != nil {
-			t.Errorf("failed to remove temp file: %v", err)
-		}
 	}
 }
 
+// tempFiles returns the temporary files left next to the storage file.
+func tempFiles(t *testing.T, store *Storage) []string {
+	t.Helper()
+	files, err := filepath.Glob(store.path + ".*.tmp")
+	require.NoError(t, err)
+	return files
+}
+
 func TestStorage_PutAndGet(t *testing.T) {
 	store, cleanup := setupTempStorage(t)
 	defer cleanup()
@@ -83,7 +92,282 @@ func TestStorage_Get_Deleted(t *testing.T) {
 	require.NoError(t, store.Put(ctx, record))
 
 	_, err := store.Get(ctx, "del123")
-	assert.ErrorContains(t, err, "Gone")
+	assert.ErrorIs(t, err, shared.ErrGone)
+}
+
+func TestStorage_GetRecord(t *testing.T) {
+	store, cleanup := setupTempStorage(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	record := models.URLRecord{
+		ID:        "short123",
+		URL:       "http://example.com",
+		UserID:    "user1",
+		CreatedAt: time.Date(2024, 1, 19, 12, 30, 0, 0, time.UTC),
+		LinkOptions: models.LinkOptions{
+			RedirectType: 308,
+			RedirectMode: models.RedirectModeHTML,
+			UTM:          models.UTM{Source: "newsletter", Content: "banner"},
+		},
+	}
+	require.NoError(t, store.Put(ctx, record))
+	require.NoError(t, store.Put(ctx, models.URLRecord{ID: "del123", URL: "http://del.com", UserID: "user1", Deleted: true}))
+
+	got, err := store.GetRecord(ctx, "short123")
+	require.NoError(t, err)
+	assert.Equal(t, record, *got)
+
+	_, err = store.GetRecord(ctx, "del123")
+	assert.ErrorIs(t, err, shared.ErrGone)
+
+	_, err = store.GetRecord(ctx, "nonexistent")
+	assert.ErrorIs(t, err, shared.ErrNotFound)
+}
+
+func TestStorage_UTMTemplate(t *testing.T) {
+	store, cleanup := setupTempStorage(t)
+	defer cleanup()
+	defer func() {
+		_ = os.Remove(store.file.Name() + utmTemplatesSuffix)
+	}()
+
+	ctx := context.Background()
+
+	_, err := store.GetUTMTemplate(ctx, "user1")
+	assert.ErrorIs(t, err, shared.ErrNotFound)
+
+	template := models.UTM{Source: "newsletter", Medium: "email"}
+	require.NoError(t, store.PutUTMTemplate(ctx, "user1", template))
+	require.NoError(t, store.PutUTMTemplate(ctx, "user2", models.UTM{Campaign: "spring"}))
+
+	got, err := store.GetUTMTemplate(ctx, "user1")
+	require.NoError(t, err)
+	assert.Equal(t, template, *got)
+
+	require.NoError(t, store.PutUTMTemplate(ctx, "user1", models.UTM{}))
+	_, err = store.GetUTMTemplate(ctx, "user1")
+	assert.ErrorIs(t, err, shared.ErrNotFound)
+
+	got, err = store.GetUTMTemplate(ctx, "user2")
+	require.NoError(t, err)
+	assert.Equal(t, models.UTM{Campaign: "spring"}, *got)
+}
+
+func TestStorage_UpdateLinkOptions(t *testing.T) {
+	store, cleanup := setupTempStorage(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	require.NoError(t, store.Put(ctx, models.URLRecord{ID: "short1", URL: "http://a.com", UserID: "user1"}))
+	require.NoError(t, store.Put(ctx, models.URLRecord{ID: "short2", URL: "http://b.com", UserID: "user1", Deleted: true}))
+
+	setHash := func(o *models.LinkOptions) error {
+		o.PasswordHash = "hash"
+		return nil
+	}
+
+	require.NoError(t, store.UpdateLinkOptions(ctx, "short1", "user1", setHash))
+	got, err := store.GetRecord(ctx, "short1")
+	require.NoError(t, err)
+	assert.Equal(t, "hash", got.PasswordHash)
+	assert.Equal(t, "http://a.com", got.URL)
+
+	assert.ErrorIs(t, store.UpdateLinkOptions(ctx, "short1", "user2", setHash), shared.ErrNotFound)
+	assert.ErrorIs(t, store.UpdateLinkOptions(ctx, "missing", "user1", setHash), shared.ErrNotFound)
+	assert.ErrorIs(t, store.UpdateLinkOptions(ctx, "short2", "user1", setHash), shared.ErrGone)
+
+	errAbort := errors.New("abort")
+	err = store.UpdateLinkOptions(ctx, "short1", "user1", func(o *models.LinkOptions) error { return errAbort })
+	assert.ErrorIs(t, err, errAbort)
+	assert.Empty(t, tempFiles(t, store))
+
+	// Records stored after the rewrite are still appended to the same file.
+	require.NoError(t, store.Put(ctx, models.URLRecord{ID: "short3", URL: "http://c.com", UserID: "user1"}))
+	_, err = store.GetRecord(ctx, "short3")
+	require.NoError(t, err)
+}
+
+func TestStorage_ConsumeClick(t *testing.T) {
+	store, cleanup := setupTempStorage(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	limited := models.LinkOptions{MaxClicks: 2}
+	require.NoError(t, store.Put(ctx, models.URLRecord{ID: "short1", URL: "http://a.com", UserID: "user1", LinkOptions: limited, ClicksLeft: 2}))
+	require.NoError(t, store.Put(ctx, models.URLRecord{ID: "short2", URL: "http://b.com", UserID: "user1"}))
+
+	require.NoError(t, store.ConsumeClick(ctx, "short1"))
+	got, err := store.GetRecord(ctx, "short1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, got.ClicksLeft)
+
+	require.NoError(t, store.ConsumeClick(ctx, "short1"))
+	assert.ErrorIs(t, store.ConsumeClick(ctx, "short1"), shared.ErrGone)
+	_, err = store.GetRecord(ctx, "short1")
+	assert.ErrorIs(t, err, shared.ErrGone)
+	_, err = store.Get(ctx, "short1")
+	assert.Error(t, err)
+
+	// Redirects are logged, the storage file is left alone
+	data, err := os.ReadFile(store.path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"clicks_left":2`)
+	require.NoError(t, store.ConsumeClick(ctx, "short2"))
+	after, err := os.ReadFile(store.path)
+	require.NoError(t, err)
+	assert.Equal(t, data, after)
+
+	assert.ErrorIs(t, store.ConsumeClick(ctx, "missing"), shared.ErrNotFound)
+}
+
+func TestStorage_ConsumeClick_Persisted(t *testing.T) {
+	store, cleanup := setupTempStorage(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	limited := models.LinkOptions{MaxClicks: 3}
+	require.NoError(t, store.Put(ctx, models.URLRecord{ID: "short1", URL: "http://a.com", UserID: "user1", LinkOptions: limited, ClicksLeft: 3}))
+	require.NoError(t, store.Put(ctx, models.URLRecord{ID: "short2", URL: "http://b.com", UserID: "user1"}))
+	require.NoError(t, store.ConsumeClick(ctx, "short1"))
+
+	// The logged redirects survive a restart
+	reopened, err := New(store.path)
+	require.NoError(t, err)
+	got, err := reopened.GetRecord(ctx, "short1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, got.ClicksLeft)
+
+	// A rewrite writes them into the storage file and empties the log
+	require.NoError(t, reopened.ConsumeClick(ctx, "short1"))
+	require.NoError(t, reopened.DeleteUserURLs(ctx, []string{"short2"}, "user1"))
+	info, err := os.Stat(store.path + clicksSuffix)
+	require.NoError(t, err)
+	assert.Zero(t, info.Size())
+	require.NoError(t, reopened.Close())
+
+	reopened, err = New(store.path)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, reopened.Close())
+	}()
+	links, err := reopened.ListLinksByUserID(ctx, "", "user1")
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+	assert.Equal(t, 1, links[0].ClicksLeft)
+}
+
+func TestStorage_Concurrent(t *testing.T) {
+	store, cleanup := setupTempStorage(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	for i := range 20 {
+		id := fmt.Sprintf("short%d", i)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, store.PutBatch(ctx, []models.URLRecord{{ID: id, URL: "http://a.com", UserID: "user1"}}))
+			_, err := store.Get(ctx, id)
+			assert.NoError(t, err)
+			assert.NoError(t, store.DeleteUserURLs(ctx, []string{id}, "user1"))
+			_, err = store.ListLinksByUserID(ctx, "", "user1")
+			assert.True(t, err == nil || errors.Is(err, shared.ErrNotFound), err)
+			_, err = store.Stats(ctx)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	stats, err := store.Stats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 20, stats.Urls)
+	assert.Empty(t, tempFiles(t, store))
+}
+
+func TestStorage_ConsumeClick_Concurrent(t *testing.T) {
+	store, cleanup := setupTempStorage(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	const maxClicks, visitors = 5, 30
+	require.NoError(t, store.Put(ctx, models.URLRecord{
+		ID: "short1", URL: "http://a.com", UserID: "user1", LinkOptions: models.LinkOptions{MaxClicks: maxClicks}, ClicksLeft: maxClicks,
+	}))
+
+	var (
+		followed atomic.Int32
+		wg       sync.WaitGroup
+	)
+	for range visitors {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := store.ConsumeClick(ctx, "short1"); err == nil {
+				followed.Add(1)
+			} else {
+				assert.ErrorIs(t, err, shared.ErrGone)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(maxClicks), followed.Load())
+}
+
+func TestStorage_RoutingRules(t *testing.T) {
+	store, cleanup := setupTempStorage(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	rules := []models.RoutingRule{
+		{Platform: models.PlatformIOS, URL: "https://apps.apple.com/app/id1"},
+		{Language: "de", Country: "AT", URL: "https://example.com/at"},
+	}
+	require.NoError(t, store.Put(ctx, models.URLRecord{ID: "short1", URL: "http://a.com", UserID: "user1", LinkOptions: models.LinkOptions{Rules: rules}}))
+
+	got, err := store.GetRecord(ctx, "short1")
+	require.NoError(t, err)
+	assert.Equal(t, rules, got.Rules)
+
+	require.NoError(t, store.UpdateLinkOptions(ctx, "short1", "user1", func(o *models.LinkOptions) error {
+		o.Rules = nil
+		return nil
+	}))
+	got, err = store.GetRecord(ctx, "short1")
+	require.NoError(t, err)
+	assert.Empty(t, got.Rules)
+}
+
+func TestStorage_VariantClicks(t *testing.T) {
+	store, cleanup := setupTempStorage(t)
+	defer cleanup()
+	defer func() {
+		_ = os.Remove(store.file.Name() + variantClicksSuffix)
+	}()
+
+	ctx := context.Background()
+
+	clicks, err := store.VariantClicks(ctx, "short1")
+	require.NoError(t, err)
+	assert.Empty(t, clicks)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, store.CountVariantClick(ctx, "short1", "blue"))
+		}()
+	}
+	wg.Wait()
+	require.NoError(t, store.CountVariantClick(ctx, "short1", "green"))
+	require.NoError(t, store.CountVariantClick(ctx, "short2", "blue"))
+
+	clicks, err = store.VariantClicks(ctx, "short1")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"blue": 10, "green": 1}, clicks)
 }
 
 func TestStorage_ListLinksByUserID(t *testing.T) {
@@ -131,7 +415,7 @@ func TestStorage_DeleteUserURLs(t *testing.T) {
 	require.NoError(t, err)
 
 	_, err = store.Get(ctx, "short1")
-	assert.ErrorContains(t, err, "Gone")
+	assert.ErrorIs(t, err, shared.ErrGone)
 
 	url, err := store.Get(ctx, "short2")
 	require.NoError(t, err)
@@ -164,6 +448,35 @@ func TestStorage_Stats(t *testing.T) {
 	assert.Equal(t, 2, stats.Users)
 }
 
+func TestStorage_ForEachLinkByUserID(t *testing.T) {
+	store, cleanup := setupTempStorage(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	created := time.Date(2024, 1, 19, 0, 0, 0, 0, time.UTC)
+	records := []models.URLRecord{
+		{ID: "a", URL: "http://a.com", UserID: "user1", CreatedAt: created},
+		{ID: "b", URL: "http://b.com", UserID: "user2", CreatedAt: created},
+		{ID: "c", URL: "http://c.com", UserID: "user1", CreatedAt: created},
+	}
+	require.NoError(t, store.PutBatch(ctx, records))
+	require.NoError(t, store.DeleteUserURLs(ctx, []string{"c"}, "user1"))
+
+	var got []models.URLRecord
+	err := store.ForEachLinkByUserID(ctx, "http://short", "user1", func(r models.URLRecord) error {
+		got = append(got, r)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []models.URLRecord{{ID: "http://short/a", URL: "http://a.com", UserID: "user1", CreatedAt: created}}, got)
+
+	stop := errors.New("stop")
+	err = store.ForEachLinkByUserID(ctx, "http://short", "user2", func(r models.URLRecord) error {
+		return stop
+	})
+	assert.ErrorIs(t, err, stop)
+}
+
 func TestCustomBool_JSON(t *testing.T) {
 	var b CustomBool
 
@@ -188,3 +501,200 @@ func TestCustomBool_JSON(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, []byte("0"), out)
 }
+
+func TestStorage_LinkTags(t *testing.T) {
+	store, cleanup := setupTempStorage(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	require.NoError(t, store.PutBatch(ctx, []models.URLRecord{
+		{ID: "short1", URL: "http://a.com", UserID: "user1", Tags: []string{"promo"}},
+		{ID: "short2", URL: "http://b.com", UserID: "user1"},
+		{ID: "short3", URL: "http://c.com", UserID: "user1"},
+	}))
+	require.NoError(t, store.DeleteUserURLs(ctx, []string{"short3"}, "user1"))
+
+	require.NoError(t, store.AddLinkTags(ctx, "short1", "user1", []string{"2026", "promo"}))
+	require.NoError(t, store.SetLinkFolder(ctx, "short1", "user1", "campaigns"))
+
+	got, err := store.GetRecord(ctx, "short1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2026", "promo"}, got.Tags)
+	assert.Equal(t, "campaigns", got.Folder)
+
+	rr, err := store.ListLinksByFilter(ctx, "http://localhost", "user1", models.LinkFilter{Folder: "campaigns"})
+	require.NoError(t, err)
+	require.Len(t, rr, 1)
+	assert.Equal(t, "http://localhost/short1", rr[0].ID)
+
+	// Fields of one record do not leak into the next one
+	rr, err = store.ListLinksByUserID(ctx, "http://localhost", "user1")
+	require.NoError(t, err)
+	require.Len(t, rr, 2)
+	assert.Empty(t, rr[1].Tags)
+	assert.Empty(t, rr[1].Folder)
+
+	require.NoError(t, store.RemoveLinkTags(ctx, "short1", "user1", []string{"promo"}))
+	_, err = store.ListLinksByFilter(ctx, "http://localhost", "user1", models.LinkFilter{Tag: "promo"})
+	assert.ErrorIs(t, err, shared.ErrNotFound)
+
+	assert.ErrorIs(t, store.AddLinkTags(ctx, "short1", "user2", []string{"x"}), shared.ErrNotFound)
+	assert.ErrorIs(t, store.SetLinkFolder(ctx, "short3", "user1", "x"), shared.ErrGone)
+
+	many := make([]string, models.MaxTags)
+	for i := range many {
+		many[i] = fmt.Sprintf("tag%02d", i)
+	}
+	assert.ErrorIs(t, store.AddLinkTags(ctx, "short1", "user1", many), shared.ErrTooManyTags)
+}
+
+func TestStorage_SearchLinks(t *testing.T) {
+	store, cleanup := setupTempStorage(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	require.NoError(t, store.Put(ctx, models.URLRecord{ID: "short1", URL: "https://example.com/docs", UserID: "user1", Title: "Go docs"}))
+	require.NoError(t, store.PutBatch(ctx, []models.URLRecord{
+		{ID: "short2", URL: "https://example.org/blog", UserID: "user1"},
+		{ID: "short3", URL: "https://example.net/news", UserID: "user1"},
+		{ID: "short4", URL: "https://example.com/docs", UserID: "user2"},
+	}))
+	require.NoError(t, store.AddLinkTags(ctx, "short2", "user1", []string{"golang"}))
+	require.NoError(t, store.DeleteUserURLs(ctx, []string{"short3"}, "user1"))
+
+	check := func(t *testing.T, s *Storage) {
+		rr, err := s.SearchLinks(ctx, "http://localhost", "user1", "go")
+		require.NoError(t, err)
+		require.Len(t, rr, 2)
+		assert.Equal(t, "http://localhost/short1", rr[0].ID)
+		assert.Equal(t, "Go docs", rr[0].Title)
+		assert.Equal(t, []string{"golang"}, rr[1].Tags)
+
+		rr, err = s.SearchLinks(ctx, "http://localhost", "user1", "news")
+		require.NoError(t, err)
+		assert.Empty(t, rr)
+	}
+
+	check(t, store)
+
+	// The index is rebuilt from the file
+	reopened, err := New(store.file.Name())
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, reopened.Close())
+	}()
+	check(t, reopened)
+}
+
+func TestStorage_ForEachRecord(t *testing.T) {
+	store, cleanup := setupTempStorage(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	created := time.Date(2024, 1, 19, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, store.PutBatch(ctx, []models.URLRecord{
+		{ID: "c", URL: "http://c.com", UserID: "user1", CreatedAt: created},
+		{ID: "a", URL: "http://a.com", UserID: "user2", CreatedAt: created},
+		{ID: "b", URL: "http://b.com", UserID: "user1", CreatedAt: created, Deleted: true},
+		{ID: "a", URL: "http://shadowed.com", UserID: "user2", CreatedAt: created},
+	}))
+
+	// Deleted links are stored as such, but never found
+	rr, err := store.SearchLinks(ctx, "", "user1", "b")
+	require.NoError(t, err)
+	assert.Empty(t, rr)
+
+	var got []models.URLRecord
+	err = store.ForEachRecord(ctx, "", func(r models.URLRecord) error {
+		got = append(got, r)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []models.URLRecord{
+		{ID: "a", URL: "http://a.com", UserID: "user2", CreatedAt: created},
+		{ID: "b", URL: "http://b.com", UserID: "user1", CreatedAt: created, Deleted: true},
+		{ID: "c", URL: "http://c.com", UserID: "user1", CreatedAt: created},
+	}, got)
+
+	var ids []string
+	err = store.ForEachRecord(ctx, "a", func(r models.URLRecord) error {
+		ids = append(ids, r.ID)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b", "c"}, ids)
+}
+
+func TestStorage_PurgeDeleted(t *testing.T) {
+	store, cleanup := setupTempStorage(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	require.NoError(t, store.PutBatch(ctx, []models.URLRecord{
+		{ID: "a", URL: "http://a.com", UserID: "user1"},
+		{ID: "b", URL: "http://b.com", UserID: "user1", Deleted: true},
+		{ID: "b", URL: "http://shadowed.com", UserID: "user1"},
+		{ID: "c", URL: "http://c.com", UserID: "user2"},
+	}))
+
+	purged, err := store.PurgeDeleted(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+
+	// The shadowed record goes with the deleted one, so the ID is free again
+	_, err = store.Get(ctx, "b")
+	assert.ErrorIs(t, err, shared.ErrNotFound)
+
+	var ids []string
+	err = store.ForEachRecord(ctx, "", func(r models.URLRecord) error {
+		ids = append(ids, r.ID)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "c"}, ids)
+
+	// The storage keeps appending to the rewritten file
+	require.NoError(t, store.Put(ctx, models.URLRecord{ID: "d", URL: "http://d.com", UserID: "user2"}))
+	url, err := store.Get(ctx, "d")
+	require.NoError(t, err)
+	assert.Equal(t, "http://d.com", url)
+
+	purged, err = store.PurgeDeleted(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+}
+
+func TestStorage_SetLinkDisabled(t *testing.T) {
+	store, cleanup := setupTempStorage(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	require.NoError(t, store.PutBatch(ctx, []models.URLRecord{
+		{ID: "a", URL: "http://a.com", UserID: "user1"},
+		{ID: "b", URL: "http://b.com", UserID: "user1", Deleted: true},
+	}))
+
+	require.NoError(t, store.SetLinkDisabled(ctx, "a", true))
+
+	_, err := store.GetRecord(ctx, "a")
+	assert.ErrorIs(t, err, shared.ErrGone)
+	assert.ErrorIs(t, store.ConsumeClick(ctx, "a"), shared.ErrGone)
+
+	rec, err := store.LookupRecord(ctx, "a")
+	require.NoError(t, err)
+	assert.True(t, rec.Disabled)
+	assert.Equal(t, "http://a.com", rec.URL)
+
+	rec, err = store.LookupRecord(ctx, "b")
+	require.NoError(t, err)
+	assert.True(t, rec.Deleted)
+
+	require.NoError(t, store.SetLinkDisabled(ctx, "a", false))
+	url, err := store.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "http://a.com", url)
+
+	assert.ErrorIs(t, store.SetLinkDisabled(ctx, "unknown", true), shared.ErrNotFound)
+	_, err = store.LookupRecord(ctx, "unknown")
+	assert.ErrorIs(t, err, shared.ErrNotFound)
+}
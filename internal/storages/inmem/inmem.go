@@ -5,15 +5,24 @@ package inmem
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
+	"sync"
 
 	"github.com/apetsko/shortugo/internal/models"
+	"github.com/apetsko/shortugo/internal/search"
 	"github.com/apetsko/shortugo/internal/storages/shared"
 )
 
 // Storage represents an in-memory storage for URL records.
+// The maps are guarded by mu, so redirects counting clicks may run concurrently with writers.
 type Storage struct {
 	byID     map[string]models.URLRecord   // Map of URL records by their ID.
 	byUserID map[string][]models.URLRecord // Map of URL records by user ID.
+	utm      map[string]models.UTM         // Default UTM tags by user ID.
+	variants map[string]map[string]int64   // Click counters of A/B variants by link ID and variant name.
+	index    *search.Index                 // Words of the non-deleted links.
+	mu       sync.Mutex
 }
 
 // New creates a new instance of in-memory storage.
@@ -21,30 +30,47 @@ func New() *Storage {
 	return &Storage{
 		byID:     make(map[string]models.URLRecord),
 		byUserID: make(map[string][]models.URLRecord),
+		utm:      make(map[string]models.UTM),
+		variants: make(map[string]map[string]int64),
+		index:    search.NewIndex(),
 	}
 }
 
 // Put stores a URL record in the in-memory storage.
 func (im *Storage) Put(ctx context.Context, r models.URLRecord) (err error) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
 	select {
 	case <-ctx.Done():
 		return ctx.Err()
 	default:
+		r = shared.WithCreatedAt(r)
 		im.byID[r.ID] = r
 		im.byUserID[r.UserID] = append(im.byUserID[r.UserID], r)
+		if !r.Deleted {
+			im.index.Add(r)
+		}
 	}
 	return nil
 }
 
 // PutBatch stores multiple URL records in the in-memory storage.
 func (im *Storage) PutBatch(ctx context.Context, rr []models.URLRecord) (err error) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
 	for _, r := range rr {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
 		default:
+			r = shared.WithCreatedAt(r)
 			im.byID[r.ID] = r
 			im.byUserID[r.UserID] = append(im.byUserID[r.UserID], r)
+			if !r.Deleted {
+				im.index.Add(r)
+			}
 		}
 	}
 	return nil
@@ -52,12 +78,15 @@ func (im *Storage) PutBatch(ctx context.Context, rr []models.URLRecord) (err err
 
 // Get retrieves the original URL for a given short URL.
 func (im *Storage) Get(ctx context.Context, shortURL string) (url string, err error) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
 	select {
 	case <-ctx.Done():
 		return "", ctx.Err()
 	default:
 		if rec, ok := im.byID[shortURL]; ok {
-			if rec.Deleted {
+			if rec.Unavailable() {
 				return "", shared.ErrGone
 			}
 			return rec.URL, nil
@@ -66,26 +95,163 @@ func (im *Storage) Get(ctx context.Context, shortURL string) (url string, err er
 	return "", fmt.Errorf("URL not found: %s. %w", shortURL, shared.ErrNotFound)
 }
 
+// GetRecord retrieves the URL record for a given short URL.
+func (im *Storage) GetRecord(ctx context.Context, shortURL string) (*models.URLRecord, error) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	rec, ok := im.byID[shortURL]
+	if !ok {
+		return nil, fmt.Errorf("URL not found: %s. %w", shortURL, shared.ErrNotFound)
+	}
+	if rec.Unavailable() {
+		return nil, shared.ErrGone
+	}
+	return &rec, nil
+}
+
+// LookupRecord retrieves the URL record for a given short URL, deleted, disabled and exhausted ones included.
+func (im *Storage) LookupRecord(ctx context.Context, shortURL string) (*models.URLRecord, error) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	rec, ok := im.byID[shortURL]
+	if !ok {
+		return nil, fmt.Errorf("URL not found: %s. %w", shortURL, shared.ErrNotFound)
+	}
+	return &rec, nil
+}
+
 // ListLinksByUserID lists all URLs associated with a user ID.
 func (im *Storage) ListLinksByUserID(ctx context.Context, baseURL, userID string) (rr []models.URLRecord, err error) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
 	select {
 	case <-ctx.Done():
 		return nil, ctx.Err()
 	default:
 		if recs, ok := im.byUserID[userID]; ok {
-			for i := range recs {
-				if !recs[i].Deleted {
-					recs[i].ID = baseURL + "/" + recs[i].ID
+			// Prefix copies, so the stored IDs stay intact.
+			rr = make([]models.URLRecord, 0, len(recs))
+			for _, r := range recs {
+				if !r.Deleted {
+					r.ID = baseURL + "/" + r.ID
 				}
+				rr = append(rr, r)
 			}
-			return recs, nil
+			return rr, nil
 		}
 	}
 	return nil, fmt.Errorf("URLs not found for UserID: %s. %w", userID, shared.ErrNotFound)
 }
 
+// ForEachLinkByUserID calls fn for every non-deleted URL associated with a user ID.
+// The records are copied first, so fn may write to the storage.
+func (im *Storage) ForEachLinkByUserID(ctx context.Context, baseURL, userID string, fn func(r models.URLRecord) error) error {
+	im.mu.Lock()
+	rr := make([]models.URLRecord, 0, len(im.byUserID[userID]))
+	for _, r := range im.byUserID[userID] {
+		// byUserID holds copies, so the deleted flag is taken from byID.
+		if rec, ok := im.byID[r.ID]; ok && rec.Deleted {
+			continue
+		}
+		rr = append(rr, r)
+	}
+	im.mu.Unlock()
+
+	for _, r := range rr {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		r.ID = baseURL + "/" + r.ID
+		if err := fn(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ForEachRecord calls fn for every stored record, deleted ones included, in ID order starting after the ID after.
+// IDs are not prefixed. The records are copied first, so fn may write to the storage.
+func (im *Storage) ForEachRecord(ctx context.Context, after string, fn func(r models.URLRecord) error) error {
+	im.mu.Lock()
+	rr := make([]models.URLRecord, 0, len(im.byID))
+	for id, r := range im.byID {
+		if id > after {
+			rr = append(rr, r)
+		}
+	}
+	im.mu.Unlock()
+
+	slices.SortFunc(rr, func(a, b models.URLRecord) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	for _, r := range rr {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListLinksByFilter lists the non-deleted URLs of a user matching filter.
+func (im *Storage) ListLinksByFilter(ctx context.Context, baseURL, userID string, filter models.LinkFilter) ([]models.URLRecord, error) {
+	var rr []models.URLRecord
+	err := im.ForEachLinkByUserID(ctx, baseURL, userID, func(r models.URLRecord) error {
+		if filter.Matches(r) {
+			rr = append(rr, r)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rr) == 0 {
+		return nil, fmt.Errorf("URLs not found for UserID: %s. %w", userID, shared.ErrNotFound)
+	}
+	return rr, nil
+}
+
+// SearchLinks returns the non-deleted URLs of a user matching query, best matches first.
+func (im *Storage) SearchLinks(ctx context.Context, baseURL, userID, query string) ([]models.URLRecord, error) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	ids := im.index.Search(userID, search.Terms(query))
+	rr := make([]models.URLRecord, 0, len(ids))
+	for _, id := range ids {
+		if r, ok := im.byID[id]; ok && !r.Deleted {
+			r.ID = baseURL + "/" + r.ID
+			rr = append(rr, r)
+		}
+	}
+	return rr, nil
+}
+
 // DeleteUserURLs deletes multiple URLs associated with a user ID.
 func (im *Storage) DeleteUserURLs(ctx context.Context, ids []string, userID string) (err error) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
 	select {
 	case <-ctx.Done():
 		return ctx.Err()
@@ -95,6 +261,7 @@ func (im *Storage) DeleteUserURLs(ctx context.Context, ids []string, userID stri
 				if rec.UserID == userID {
 					rec.Deleted = true
 					im.byID[id] = rec
+					im.index.Remove(userID, id)
 				}
 			}
 		}
@@ -102,8 +269,243 @@ func (im *Storage) DeleteUserURLs(ctx context.Context, ids []string, userID stri
 	}
 }
 
+// PurgeDeleted removes the deleted links for good, with their click counters, and returns how many were removed.
+func (im *Storage) PurgeDeleted(ctx context.Context) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	var purged int64
+	for id, rec := range im.byID {
+		if rec.Deleted {
+			delete(im.byID, id)
+			delete(im.variants, id)
+			purged++
+		}
+	}
+	if purged == 0 {
+		return 0, nil
+	}
+
+	// byUserID holds copies, so the links left are looked up in byID
+	for userID, recs := range im.byUserID {
+		recs = slices.DeleteFunc(recs, func(r models.URLRecord) bool {
+			_, ok := im.byID[r.ID]
+			return !ok
+		})
+		if len(recs) == 0 {
+			delete(im.byUserID, userID)
+		} else {
+			im.byUserID[userID] = recs
+		}
+	}
+	return purged, nil
+}
+
+// UpdateLinkOptions changes the options of a link owned by userID.
+func (im *Storage) UpdateLinkOptions(ctx context.Context, id, userID string, update func(o *models.LinkOptions) error) error {
+	return im.updateRecord(ctx, id, userID, func(r *models.URLRecord) error {
+		return update(&r.LinkOptions)
+	})
+}
+
+// AddLinkTags adds tags to a link owned by userID.
+func (im *Storage) AddLinkTags(ctx context.Context, id, userID string, tags []string) error {
+	return im.updateRecord(ctx, id, userID, func(r *models.URLRecord) error {
+		merged := models.NormalizeTags(append(slices.Clone(r.Tags), tags...))
+		if len(merged) > models.MaxTags {
+			return shared.ErrTooManyTags
+		}
+		r.Tags = merged
+		return nil
+	})
+}
+
+// RemoveLinkTags removes tags from a link owned by userID.
+func (im *Storage) RemoveLinkTags(ctx context.Context, id, userID string, tags []string) error {
+	return im.updateRecord(ctx, id, userID, func(r *models.URLRecord) error {
+		r.Tags = slices.DeleteFunc(slices.Clone(r.Tags), func(tag string) bool {
+			return slices.Contains(tags, tag)
+		})
+		if len(r.Tags) == 0 {
+			r.Tags = nil
+		}
+		return nil
+	})
+}
+
+// SetLinkFolder files a link owned by userID in folder.
+func (im *Storage) SetLinkFolder(ctx context.Context, id, userID, folder string) error {
+	return im.updateRecord(ctx, id, userID, func(r *models.URLRecord) error {
+		r.Folder = folder
+		return nil
+	})
+}
+
+// updateRecord changes a non-deleted link owned by userID through update.
+func (im *Storage) updateRecord(ctx context.Context, id, userID string, update func(r *models.URLRecord) error) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	rec, ok := im.byID[id]
+	if !ok || rec.UserID != userID {
+		return fmt.Errorf("URL not found: %s. %w", id, shared.ErrNotFound)
+	}
+	if rec.Deleted {
+		return shared.ErrGone
+	}
+
+	if err := update(&rec); err != nil {
+		return err
+	}
+	im.byID[id] = rec
+	im.index.Add(rec)
+
+	// byUserID holds copies, keep them in sync.
+	for i, r := range im.byUserID[userID] {
+		if r.ID == id {
+			im.byUserID[userID][i] = rec
+		}
+	}
+	return nil
+}
+
+// SetLinkDisabled disables or re-enables the link with the given ID, whoever owns it.
+func (im *Storage) SetLinkDisabled(ctx context.Context, id string, disabled bool) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	rec, ok := im.byID[id]
+	if !ok {
+		return fmt.Errorf("URL not found: %s. %w", id, shared.ErrNotFound)
+	}
+	rec.Disabled = disabled
+	im.byID[id] = rec
+
+	// byUserID holds copies, keep them in sync.
+	for i, r := range im.byUserID[rec.UserID] {
+		if r.ID == id {
+			im.byUserID[rec.UserID][i] = rec
+		}
+	}
+	return nil
+}
+
+// ConsumeClick takes one of the remaining redirects of a link limited by MaxClicks.
+func (im *Storage) ConsumeClick(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	rec, ok := im.byID[id]
+	if !ok {
+		return fmt.Errorf("URL not found: %s. %w", id, shared.ErrNotFound)
+	}
+	if rec.Unavailable() {
+		return shared.ErrGone
+	}
+	if rec.MaxClicks == 0 {
+		return nil
+	}
+
+	rec.ClicksLeft--
+	im.byID[id] = rec
+
+	// byUserID holds copies, keep their counters in sync for exports.
+	for i, r := range im.byUserID[rec.UserID] {
+		if r.ID == id {
+			im.byUserID[rec.UserID][i].ClicksLeft = rec.ClicksLeft
+		}
+	}
+	return nil
+}
+
+// CountVariantClick adds one redirect to the click counter of an A/B variant of a link.
+func (im *Storage) CountVariantClick(ctx context.Context, id, variant string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	counters, ok := im.variants[id]
+	if !ok {
+		counters = make(map[string]int64)
+		im.variants[id] = counters
+	}
+	counters[variant]++
+	return nil
+}
+
+// VariantClicks returns the click counters of the A/B variants of a link by variant name.
+func (im *Storage) VariantClicks(ctx context.Context, id string) (map[string]int64, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	clicks := make(map[string]int64, len(im.variants[id]))
+	for name, n := range im.variants[id] {
+		clicks[name] = n
+	}
+	return clicks, nil
+}
+
+// GetUTMTemplate retrieves the default UTM tags of a user.
+func (im *Storage) GetUTMTemplate(ctx context.Context, userID string) (*models.UTM, error) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	t, ok := im.utm[userID]
+	if !ok {
+		return nil, fmt.Errorf("UTM template not found for UserID: %s. %w", userID, shared.ErrNotFound)
+	}
+	return &t, nil
+}
+
+// PutUTMTemplate replaces the default UTM tags of a user.
+func (im *Storage) PutUTMTemplate(ctx context.Context, userID string, t models.UTM) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if t.IsZero() {
+		delete(im.utm, userID)
+		return nil
+	}
+	im.utm[userID] = t
+	return nil
+}
+
 // Stats retrieves count stats: urls and users.
 func (im *Storage) Stats(ctx context.Context) (*models.Stats, error) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
 	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
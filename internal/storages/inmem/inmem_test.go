@@ -2,8 +2,12 @@ package inmem
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/apetsko/shortugo/internal/models"
 	"github.com/apetsko/shortugo/internal/storages/shared"
@@ -38,6 +42,8 @@ func Test_Put(t *testing.T) {
 
 			v, ok := im.byID[test.ID]
 			require.Equal(t, ok, true)
+			assert.False(t, v.CreatedAt.IsZero())
+			v.CreatedAt = time.Time{}
 			assert.Equal(t, v, test)
 		})
 	}
@@ -389,3 +395,429 @@ func Test_Stats(t *testing.T) {
 		assert.Equal(t, 2, stats.Users, "User count mismatch")
 	})
 }
+
+func Test_ForEachLinkByUserID(t *testing.T) {
+	im := New()
+	ctx := context.Background()
+
+	created := time.Date(2024, 1, 19, 0, 0, 0, 0, time.UTC)
+	records := []models.URLRecord{
+		{UserID: "1", URL: "http://a.com", ID: "a", CreatedAt: created},
+		{UserID: "1", URL: "http://b.com", ID: "b", CreatedAt: created},
+		{UserID: "2", URL: "http://c.com", ID: "c", CreatedAt: created},
+	}
+	require.NoError(t, im.PutBatch(ctx, records))
+	require.NoError(t, im.DeleteUserURLs(ctx, []string{"b"}, "1"))
+
+	var got []models.URLRecord
+	err := im.ForEachLinkByUserID(ctx, "http://short", "1", func(r models.URLRecord) error {
+		got = append(got, r)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []models.URLRecord{{UserID: "1", URL: "http://a.com", ID: "http://short/a", CreatedAt: created}}, got)
+
+	// Stored records must not be rewritten by the walk.
+	assert.Equal(t, "a", im.byUserID["1"][0].ID)
+
+	// fn may write to the storage without deadlocking.
+	err = im.ForEachLinkByUserID(ctx, "", "1", func(r models.URLRecord) error {
+		return im.Put(ctx, models.URLRecord{UserID: "3", URL: r.URL, ID: "copy"})
+	})
+	require.NoError(t, err)
+	copied, err := im.Get(ctx, "copy")
+	require.NoError(t, err)
+	assert.Equal(t, "http://a.com", copied)
+
+	// Consumed clicks are seen by the walk.
+	require.NoError(t, im.Put(ctx, models.URLRecord{UserID: "2", URL: "http://d.com", ID: "d", CreatedAt: created, ClicksLeft: 2, LinkOptions: models.LinkOptions{MaxClicks: 2}}))
+	require.NoError(t, im.ConsumeClick(ctx, "d"))
+	err = im.ForEachLinkByUserID(ctx, "", "2", func(r models.URLRecord) error {
+		if r.ID == "/d" {
+			assert.Equal(t, 1, r.ClicksLeft)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	err = im.ForEachLinkByUserID(cancelled, "http://short", "1", func(r models.URLRecord) error {
+		return nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func Test_GetRecord(t *testing.T) {
+	im := New()
+	ctx := context.Background()
+
+	rec := models.URLRecord{
+		ID:          "a",
+		URL:         "http://a.com",
+		UserID:      "1",
+		CreatedAt:   time.Date(2024, 1, 19, 12, 30, 0, 0, time.UTC),
+		LinkOptions: models.LinkOptions{RedirectType: 301, RedirectMode: models.RedirectModeHTML},
+	}
+	require.NoError(t, im.Put(ctx, rec))
+
+	got, err := im.GetRecord(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, rec, *got)
+
+	_, err = im.GetRecord(ctx, "missing")
+	assert.ErrorIs(t, err, shared.ErrNotFound)
+
+	require.NoError(t, im.DeleteUserURLs(ctx, []string{"a"}, "1"))
+	_, err = im.GetRecord(ctx, "a")
+	assert.ErrorIs(t, err, shared.ErrGone)
+}
+
+func Test_UTMTemplate(t *testing.T) {
+	im := New()
+	ctx := context.Background()
+
+	_, err := im.GetUTMTemplate(ctx, "1")
+	assert.ErrorIs(t, err, shared.ErrNotFound)
+
+	template := models.UTM{Source: "newsletter", Medium: "email"}
+	require.NoError(t, im.PutUTMTemplate(ctx, "1", template))
+
+	got, err := im.GetUTMTemplate(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, template, *got)
+
+	_, err = im.GetUTMTemplate(ctx, "2")
+	assert.ErrorIs(t, err, shared.ErrNotFound)
+
+	require.NoError(t, im.PutUTMTemplate(ctx, "1", models.UTM{}))
+	_, err = im.GetUTMTemplate(ctx, "1")
+	assert.ErrorIs(t, err, shared.ErrNotFound)
+}
+
+func Test_UpdateLinkOptions(t *testing.T) {
+	im := New()
+	ctx := context.Background()
+
+	require.NoError(t, im.Put(ctx, models.URLRecord{ID: "a", URL: "http://a.com", UserID: "1"}))
+	require.NoError(t, im.Put(ctx, models.URLRecord{ID: "b", URL: "http://b.com", UserID: "1"}))
+	require.NoError(t, im.DeleteUserURLs(ctx, []string{"b"}, "1"))
+
+	setHash := func(o *models.LinkOptions) error {
+		o.PasswordHash = "hash"
+		return nil
+	}
+
+	require.NoError(t, im.UpdateLinkOptions(ctx, "a", "1", setHash))
+	got, err := im.GetRecord(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "hash", got.PasswordHash)
+	assert.Equal(t, "hash", im.byUserID["1"][0].PasswordHash)
+
+	assert.ErrorIs(t, im.UpdateLinkOptions(ctx, "a", "2", setHash), shared.ErrNotFound)
+	assert.ErrorIs(t, im.UpdateLinkOptions(ctx, "missing", "1", setHash), shared.ErrNotFound)
+	assert.ErrorIs(t, im.UpdateLinkOptions(ctx, "b", "1", setHash), shared.ErrGone)
+
+	errAbort := errors.New("abort")
+	err = im.UpdateLinkOptions(ctx, "a", "1", func(o *models.LinkOptions) error {
+		o.PasswordHash = ""
+		return errAbort
+	})
+	assert.ErrorIs(t, err, errAbort)
+	got, err = im.GetRecord(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "hash", got.PasswordHash)
+}
+
+func Test_ConsumeClick(t *testing.T) {
+	im := New()
+	ctx := context.Background()
+
+	limited := models.LinkOptions{MaxClicks: 2}
+	require.NoError(t, im.Put(ctx, models.URLRecord{ID: "a", URL: "http://a.com", UserID: "1", LinkOptions: limited, ClicksLeft: 2}))
+	require.NoError(t, im.Put(ctx, models.URLRecord{ID: "b", URL: "http://b.com", UserID: "1"}))
+
+	require.NoError(t, im.ConsumeClick(ctx, "a"))
+	require.NoError(t, im.ConsumeClick(ctx, "a"))
+	assert.ErrorIs(t, im.ConsumeClick(ctx, "a"), shared.ErrGone)
+
+	_, err := im.Get(ctx, "a")
+	assert.ErrorIs(t, err, shared.ErrGone)
+	_, err = im.GetRecord(ctx, "a")
+	assert.ErrorIs(t, err, shared.ErrGone)
+
+	// Links without a limit are not counted
+	for range 3 {
+		require.NoError(t, im.ConsumeClick(ctx, "b"))
+	}
+	got, err := im.GetRecord(ctx, "b")
+	require.NoError(t, err)
+	assert.Zero(t, got.ClicksLeft)
+
+	assert.ErrorIs(t, im.ConsumeClick(ctx, "missing"), shared.ErrNotFound)
+}
+
+func Test_ConsumeClick_Concurrent(t *testing.T) {
+	im := New()
+	ctx := context.Background()
+
+	const maxClicks, visitors = 10, 100
+	require.NoError(t, im.Put(ctx, models.URLRecord{
+		ID: "a", URL: "http://a.com", UserID: "1", LinkOptions: models.LinkOptions{MaxClicks: maxClicks}, ClicksLeft: maxClicks,
+	}))
+
+	var (
+		followed atomic.Int32
+		wg       sync.WaitGroup
+	)
+	for range visitors {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := im.ConsumeClick(ctx, "a"); err == nil {
+				followed.Add(1)
+			} else {
+				assert.ErrorIs(t, err, shared.ErrGone)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(maxClicks), followed.Load())
+}
+
+func Test_RoutingRules(t *testing.T) {
+	im := New()
+	ctx := context.Background()
+
+	rules := []models.RoutingRule{
+		{Platform: models.PlatformIOS, URL: "https://apps.apple.com/app/id1"},
+		{Language: "de", Country: "AT", URL: "https://example.com/at"},
+	}
+	require.NoError(t, im.Put(ctx, models.URLRecord{ID: "a", URL: "http://a.com", UserID: "1", LinkOptions: models.LinkOptions{Rules: rules}}))
+
+	got, err := im.GetRecord(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, rules, got.Rules)
+
+	require.NoError(t, im.UpdateLinkOptions(ctx, "a", "1", func(o *models.LinkOptions) error {
+		o.Rules = rules[:1]
+		return nil
+	}))
+	got, err = im.GetRecord(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, rules[:1], got.Rules)
+}
+
+func Test_VariantClicks(t *testing.T) {
+	im := New()
+	ctx := context.Background()
+
+	clicks, err := im.VariantClicks(ctx, "a")
+	require.NoError(t, err)
+	assert.Empty(t, clicks)
+
+	require.NoError(t, im.CountVariantClick(ctx, "a", "blue"))
+	require.NoError(t, im.CountVariantClick(ctx, "a", "blue"))
+	require.NoError(t, im.CountVariantClick(ctx, "a", "green"))
+	require.NoError(t, im.CountVariantClick(ctx, "b", "blue"))
+
+	clicks, err = im.VariantClicks(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"blue": 2, "green": 1}, clicks)
+}
+
+func Test_LinkTags(t *testing.T) {
+	im := New()
+	ctx := context.Background()
+
+	require.NoError(t, im.Put(ctx, models.URLRecord{ID: "a", URL: "http://a.com", UserID: "1", Tags: []string{"promo"}}))
+	require.NoError(t, im.Put(ctx, models.URLRecord{ID: "b", URL: "http://b.com", UserID: "1"}))
+	require.NoError(t, im.Put(ctx, models.URLRecord{ID: "c", URL: "http://c.com", UserID: "1"}))
+	require.NoError(t, im.DeleteUserURLs(ctx, []string{"c"}, "1"))
+
+	require.NoError(t, im.AddLinkTags(ctx, "a", "1", []string{"2026", "promo"}))
+	require.NoError(t, im.AddLinkTags(ctx, "b", "1", []string{"promo"}))
+	require.NoError(t, im.SetLinkFolder(ctx, "a", "1", "campaigns"))
+
+	got, err := im.GetRecord(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2026", "promo"}, got.Tags)
+	assert.Equal(t, "campaigns", got.Folder)
+
+	rr, err := im.ListLinksByFilter(ctx, "http://localhost", "1", models.LinkFilter{Tag: "promo"})
+	require.NoError(t, err)
+	assert.Len(t, rr, 2)
+
+	rr, err = im.ListLinksByFilter(ctx, "http://localhost", "1", models.LinkFilter{Tag: "promo", Folder: "campaigns"})
+	require.NoError(t, err)
+	require.Len(t, rr, 1)
+	assert.Equal(t, "http://localhost/a", rr[0].ID)
+
+	require.NoError(t, im.RemoveLinkTags(ctx, "a", "1", []string{"promo", "unknown"}))
+	_, err = im.ListLinksByFilter(ctx, "http://localhost", "1", models.LinkFilter{Tag: "promo", Folder: "campaigns"})
+	assert.ErrorIs(t, err, shared.ErrNotFound)
+
+	assert.ErrorIs(t, im.AddLinkTags(ctx, "a", "2", []string{"x"}), shared.ErrNotFound)
+	assert.ErrorIs(t, im.SetLinkFolder(ctx, "missing", "1", "x"), shared.ErrNotFound)
+	assert.ErrorIs(t, im.RemoveLinkTags(ctx, "c", "1", []string{"x"}), shared.ErrGone)
+
+	many := make([]string, models.MaxTags)
+	for i := range many {
+		many[i] = fmt.Sprintf("tag%02d", i)
+	}
+	assert.ErrorIs(t, im.AddLinkTags(ctx, "a", "1", many), shared.ErrTooManyTags)
+	got, err = im.GetRecord(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2026"}, got.Tags)
+
+	// Listing hands out copies, the stored IDs keep no base URL
+	_, err = im.ListLinksByUserID(ctx, "http://localhost", "1")
+	require.NoError(t, err)
+	assert.Equal(t, "a", im.byUserID["1"][0].ID)
+}
+
+func Test_SearchLinks(t *testing.T) {
+	im := New()
+	ctx := context.Background()
+
+	require.NoError(t, im.Put(ctx, models.URLRecord{ID: "a", URL: "https://example.com/docs", UserID: "1", Title: "Go docs"}))
+	require.NoError(t, im.PutBatch(ctx, []models.URLRecord{
+		{ID: "b", URL: "https://example.org/blog", UserID: "1"},
+		{ID: "c", URL: "https://example.com/docs", UserID: "2"},
+	}))
+
+	rr, err := im.SearchLinks(ctx, "http://localhost", "1", "example docs")
+	require.NoError(t, err)
+	require.Len(t, rr, 1)
+	assert.Equal(t, "http://localhost/a", rr[0].ID)
+	assert.Equal(t, "a", im.byID["a"].ID)
+
+	// Tag changes are searchable at once
+	require.NoError(t, im.AddLinkTags(ctx, "b", "1", []string{"golang"}))
+	rr, err = im.SearchLinks(ctx, "http://localhost", "1", "go")
+	require.NoError(t, err)
+	require.Len(t, rr, 2)
+
+	// Deleted links are not found
+	require.NoError(t, im.DeleteUserURLs(ctx, []string{"a"}, "1"))
+	rr, err = im.SearchLinks(ctx, "http://localhost", "1", "docs")
+	require.NoError(t, err)
+	assert.Empty(t, rr)
+}
+
+func Test_ForEachRecord(t *testing.T) {
+	im := New()
+	ctx := context.Background()
+
+	created := time.Date(2024, 1, 19, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, im.PutBatch(ctx, []models.URLRecord{
+		{UserID: "1", URL: "http://c.com", ID: "c", CreatedAt: created},
+		{UserID: "2", URL: "http://a.com", ID: "a", CreatedAt: created},
+		{UserID: "1", URL: "http://b.com", ID: "b", CreatedAt: created, Deleted: true},
+	}))
+
+	// Deleted links are stored as such, but never found
+	rr, err := im.SearchLinks(ctx, "", "1", "b")
+	require.NoError(t, err)
+	assert.Empty(t, rr)
+
+	var got []models.URLRecord
+	err = im.ForEachRecord(ctx, "", func(r models.URLRecord) error {
+		got = append(got, r)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []models.URLRecord{
+		{UserID: "2", URL: "http://a.com", ID: "a", CreatedAt: created},
+		{UserID: "1", URL: "http://b.com", ID: "b", CreatedAt: created, Deleted: true},
+		{UserID: "1", URL: "http://c.com", ID: "c", CreatedAt: created},
+	}, got)
+
+	var ids []string
+	err = im.ForEachRecord(ctx, "a", func(r models.URLRecord) error {
+		ids = append(ids, r.ID)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b", "c"}, ids)
+}
+
+func Test_PurgeDeleted(t *testing.T) {
+	im := New()
+	ctx := context.Background()
+
+	require.NoError(t, im.PutBatch(ctx, []models.URLRecord{
+		{UserID: "1", URL: "http://a.com", ID: "a"},
+		{UserID: "1", URL: "http://b.com", ID: "b"},
+		{UserID: "2", URL: "http://c.com", ID: "c"},
+	}))
+	require.NoError(t, im.CountVariantClick(ctx, "b", "blue"))
+	require.NoError(t, im.DeleteUserURLs(ctx, []string{"b"}, "1"))
+	require.NoError(t, im.DeleteUserURLs(ctx, []string{"c"}, "2"))
+
+	purged, err := im.PurgeDeleted(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), purged)
+
+	_, err = im.Get(ctx, "b")
+	assert.ErrorIs(t, err, shared.ErrNotFound)
+	clicks, err := im.VariantClicks(ctx, "b")
+	require.NoError(t, err)
+	assert.Empty(t, clicks)
+
+	rr, err := im.ListLinksByUserID(ctx, "", "1")
+	require.NoError(t, err)
+	require.Len(t, rr, 1)
+	assert.Equal(t, "/a", rr[0].ID)
+
+	stats, err := im.Stats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &models.Stats{Urls: 1, Users: 1}, stats)
+
+	purged, err = im.PurgeDeleted(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+}
+
+func Test_SetLinkDisabled(t *testing.T) {
+	im := New()
+	ctx := context.Background()
+
+	require.NoError(t, im.PutBatch(ctx, []models.URLRecord{
+		{UserID: "1", URL: "http://a.com", ID: "a"},
+		{UserID: "1", URL: "http://b.com", ID: "b"},
+	}))
+	require.NoError(t, im.DeleteUserURLs(ctx, []string{"b"}, "1"))
+
+	require.NoError(t, im.SetLinkDisabled(ctx, "a", true))
+
+	_, err := im.Get(ctx, "a")
+	assert.ErrorIs(t, err, shared.ErrGone)
+	_, err = im.GetRecord(ctx, "a")
+	assert.ErrorIs(t, err, shared.ErrGone)
+	assert.ErrorIs(t, im.ConsumeClick(ctx, "a"), shared.ErrGone)
+
+	// Lookups and the owner's list still show the link, with its state
+	rec, err := im.LookupRecord(ctx, "a")
+	require.NoError(t, err)
+	assert.True(t, rec.Disabled)
+	rr, err := im.ListLinksByUserID(ctx, "", "1")
+	require.NoError(t, err)
+	require.NotEmpty(t, rr)
+	assert.Equal(t, "/a", rr[0].ID)
+	assert.True(t, rr[0].Disabled)
+
+	rec, err = im.LookupRecord(ctx, "b")
+	require.NoError(t, err)
+	assert.True(t, rec.Deleted)
+
+	require.NoError(t, im.SetLinkDisabled(ctx, "a", false))
+	url, err := im.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "http://a.com", url)
+
+	assert.ErrorIs(t, im.SetLinkDisabled(ctx, "unknown", true), shared.ErrNotFound)
+	_, err = im.LookupRecord(ctx, "unknown")
+	assert.ErrorIs(t, err, shared.ErrNotFound)
+}
@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/apetsko/shortugo/internal/audit"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AuditLog is the audit log kept in the audit_log table of the database.
+// A trigger refuses updates and deletes of the table, so entries can only be appended.
+type AuditLog struct {
+	pool *pgxpool.Pool
+}
+
+// AuditLog returns the audit log kept in the database of the storage.
+func (p *Storage) AuditLog() *AuditLog {
+	return &AuditLog{pool: p.pool}
+}
+
+// Record appends e to the audit_log table.
+func (a *AuditLog) Record(ctx context.Context, e audit.Entry) error {
+	const insert = `
+			INSERT INTO audit_log (time, actor, remote, protocol, action, targets, details)
+			VALUES ($1, $2, $3, $4, $5, $6, $7);`
+
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	targets := e.Targets
+	if targets == nil {
+		targets = []string{}
+	}
+	var details []byte
+	if len(e.Details) > 0 {
+		var err error
+		if details, err = json.Marshal(e.Details); err != nil {
+			return fmt.Errorf("failed to marshal audit details: %w", err)
+		}
+	}
+
+	if _, err := a.pool.Exec(ctx, insert, e.Time, e.Actor, e.Remote, e.Protocol, e.Action, targets, details); err != nil {
+		return fmt.Errorf("failed to insert audit entry: %w", err)
+	}
+	return nil
+}
+
+// Query returns the entries matching f, newest first.
+func (a *AuditLog) Query(ctx context.Context, f audit.Filter) ([]audit.Entry, error) {
+	const query = `
+			SELECT time, actor, remote, protocol, action, targets, details
+			FROM audit_log
+			WHERE ($1::timestamptz IS NULL OR time >= $1)
+				AND ($2::timestamptz IS NULL OR time < $2)
+				AND ($3 = '' OR actor = $3)
+				AND ($4 = '' OR action = $4)
+				AND ($5 = '' OR targets @> ARRAY[$5])
+				AND ($6 = '' OR protocol = $6)
+			ORDER BY id DESC
+			LIMIT $7;`
+
+	rows, err := a.pool.Query(ctx, query, optionalTime(f.Since), optionalTime(f.Until), f.Actor, f.Action, f.Target, f.Protocol, f.Size())
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]audit.Entry, 0)
+	for rows.Next() {
+		var (
+			e       audit.Entry
+			details []byte
+		)
+		if err := rows.Scan(&e.Time, &e.Actor, &e.Remote, &e.Protocol, &e.Action, &e.Targets, &details); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		if len(e.Targets) == 0 {
+			e.Targets = nil
+		}
+		if details != nil {
+			if err := json.Unmarshal(details, &e.Details); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal audit details: %w", err)
+			}
+		}
+		e.Time = e.Time.UTC()
+		entries = append(entries, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return entries, nil
+}
+
+// optionalTime returns t as a query argument, NULL when it is zero.
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS options JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+-- +goose Down
+ALTER TABLE urls DROP COLUMN IF EXISTS options;
@@ -0,0 +1,8 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS utm_templates (
+    user_id TEXT PRIMARY KEY,
+    template JSONB NOT NULL
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS utm_templates;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS clicks_left INTEGER;
+
+-- +goose Down
+ALTER TABLE urls DROP COLUMN IF EXISTS clicks_left;
@@ -0,0 +1,10 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS variant_clicks (
+    url_id TEXT NOT NULL,
+    variant TEXT NOT NULL,
+    clicks BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (url_id, variant)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS variant_clicks;
@@ -0,0 +1,16 @@
+-- +goose Up
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS folder TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS url_tags (
+    url_id TEXT NOT NULL REFERENCES urls (id) ON DELETE CASCADE,
+    tag TEXT NOT NULL,
+    PRIMARY KEY (url_id, tag)
+);
+
+CREATE INDEX IF NOT EXISTS url_tags_tag_idx ON url_tags (tag);
+CREATE INDEX IF NOT EXISTS urls_user_id_folder_idx ON urls (user_id, folder);
+
+-- +goose Down
+DROP INDEX IF EXISTS urls_user_id_folder_idx;
+DROP TABLE IF EXISTS url_tags;
+ALTER TABLE urls DROP COLUMN IF EXISTS folder;
@@ -0,0 +1,52 @@
+-- +goose Up
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS title TEXT NOT NULL DEFAULT '';
+
+-- Tags of a link as one string, so that the search vector can be a generated column
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS tags_text TEXT NOT NULL DEFAULT '';
+
+UPDATE urls
+SET tags_text = (SELECT string_agg(tag, ' ' ORDER BY tag) FROM url_tags WHERE url_tags.url_id = urls.id)
+WHERE EXISTS (SELECT 1 FROM url_tags WHERE url_tags.url_id = urls.id);
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION sync_url_tags_text() RETURNS trigger AS $$
+DECLARE
+    link TEXT;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        link := OLD.url_id;
+    ELSE
+        link := NEW.url_id;
+    END IF;
+
+    UPDATE urls
+    SET tags_text = COALESCE((SELECT string_agg(tag, ' ' ORDER BY tag) FROM url_tags WHERE url_id = link), '')
+    WHERE id = link;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER url_tags_sync_text
+AFTER INSERT OR DELETE ON url_tags
+FOR EACH ROW EXECUTE FUNCTION sync_url_tags_text();
+
+-- Trigram indexes answer ILIKE '%...%' patterns
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Lower-case words of the alias, URL, title and tags, split where search.Words splits.
+-- Words are padded with a space on each side, so ' term' matches where a word starts
+-- and ' term ' a whole word, without two matches sharing a space.
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS search_text TEXT GENERATED ALWAYS AS (
+    ' ' || lower(regexp_replace(id || ' ' || url || ' ' || title || ' ' || tags_text, '[^[:alnum:]]+', '  ', 'g')) || ' '
+) STORED;
+
+CREATE INDEX IF NOT EXISTS urls_search_text_trgm_idx ON urls USING GIN (search_text gin_trgm_ops);
+
+-- +goose Down
+DROP INDEX IF EXISTS urls_search_text_trgm_idx;
+ALTER TABLE urls DROP COLUMN IF EXISTS search_text;
+DROP TRIGGER IF EXISTS url_tags_sync_text ON url_tags;
+DROP FUNCTION IF EXISTS sync_url_tags_text();
+ALTER TABLE urls DROP COLUMN IF EXISTS tags_text;
+ALTER TABLE urls DROP COLUMN IF EXISTS title;
@@ -0,0 +1,6 @@
+-- +goose Up
+-- Keep the time of day links were created, so migrations between storages preserve it
+ALTER TABLE urls ALTER COLUMN date TYPE TIMESTAMPTZ USING date::timestamptz;
+
+-- +goose Down
+ALTER TABLE urls ALTER COLUMN date TYPE DATE USING date::date;
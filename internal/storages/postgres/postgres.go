@@ -6,12 +6,15 @@ import (
 	"context"
 	"database/sql"
 	"embed"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/apetsko/shortugo/internal/logging"
 	"github.com/apetsko/shortugo/internal/models"
+	"github.com/apetsko/shortugo/internal/search"
 	"github.com/apetsko/shortugo/internal/storages/shared"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -49,19 +52,33 @@ func applyMigrations(conn string, logger *logging.Logger) error {
 }
 
 // New creates a new Storage instance and applies migrations.
-func New(conn string, logger *logging.Logger) (*Storage, error) {
+// migrating, when not nil, is called with true before the migrations run and with false once they are done,
+// so the service reports it is not ready meanwhile.
+func New(conn string, logger *logging.Logger, migrating func(running bool)) (*Storage, error) {
 	// Подождем, пока БД станет доступна (в CI может занять пару секунд)
 	if err := waitForDB(conn, logger); err != nil {
 		return nil, fmt.Errorf("database not ready: %w", err)
 	}
 
 	// Применяем миграции
-	if err := applyMigrations(conn, logger); err != nil {
+	if migrating != nil {
+		migrating(true)
+	}
+	err := applyMigrations(conn, logger)
+	if migrating != nil {
+		migrating(false)
+	}
+	if err != nil {
 		return nil, fmt.Errorf("failed to apply migrations: %w", err)
 	}
 
-	ctx := context.Background()
-	pool, err := pgxpool.New(ctx, conn)
+	cfg, err := pgxpool.ParseConfig(conn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid database DSN: %w", err)
+	}
+	cfg.ConnConfig.Tracer = queryTracer{}
+
+	pool, err := pgxpool.NewWithConfig(context.Background(), cfg)
 	if err != nil {
 		return nil, fmt.Errorf("unable to connect to database: %w", err)
 	}
@@ -97,14 +114,12 @@ func waitForDB(conn string, logger *logging.Logger) error {
 
 // Put stores a URLRecord in the database.
 func (p *Storage) Put(ctx context.Context, r models.URLRecord) error {
-	const insert = `
-			INSERT INTO urls (id, url, user_id, date)
-			VALUES ($1, $2, $3, $4)
-			ON CONFLICT (id)
-			DO UPDATE SET date = EXCLUDED.date;`
+	batch := new(pgx.Batch)
+	if err := queueInsert(batch, r); err != nil {
+		return err
+	}
 
-	_, err := p.pool.Exec(ctx, insert, r.ID, r.URL, r.UserID, time.Now().Format(time.RFC3339))
-	if err != nil {
+	if err := p.pool.SendBatch(ctx, batch).Close(); err != nil {
 		return fmt.Errorf("failed to insert URL: %w", err)
 	}
 
@@ -113,39 +128,78 @@ func (p *Storage) Put(ctx context.Context, r models.URLRecord) error {
 
 // PutBatch stores multiple URLRecords in the database.
 func (p *Storage) PutBatch(ctx context.Context, rr []models.URLRecord) error {
-	const insertBatch = `
-			INSERT INTO urls (id, url, user_id, date)
-			VALUES ($1, $2, $3, $4)
-			ON CONFLICT (id)
-			DO UPDATE SET date = EXCLUDED.date;`
-
 	batch := new(pgx.Batch)
 	for _, r := range rr {
-		batch.Queue(insertBatch, r.ID, r.URL, r.UserID, time.Now().Format(time.RFC3339))
+		if err := queueInsert(batch, r); err != nil {
+			return err
+		}
 	}
-	br := p.pool.SendBatch(ctx, batch)
-	defer func() {
-		_ = br.Close()
-	}()
 
-	if _, err := br.Exec(); err != nil {
+	if err := p.pool.SendBatch(ctx, batch).Close(); err != nil {
 		return fmt.Errorf("failed to batch insert: %w", err)
 	}
 
 	return nil
 }
 
+// queueInsert queues the statement storing r and its tags.
+// A conflicting id only refreshes the date, and tags are added only when this
+// statement inserted the row (xmax = 0), never to a link that already existed.
+func queueInsert(batch *pgx.Batch, r models.URLRecord) error {
+	const insert = `
+			INSERT INTO urls (id, url, user_id, date, options, clicks_left, folder, title, deleted, disabled)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			ON CONFLICT (id)
+			DO UPDATE SET date = EXCLUDED.date;`
+
+	const insertWithTags = `
+			WITH stored AS (
+				INSERT INTO urls (id, url, user_id, date, options, clicks_left, folder, title, deleted, disabled)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+				ON CONFLICT (id)
+				DO UPDATE SET date = EXCLUDED.date
+				RETURNING id, xmax = 0 AS inserted
+			)
+			INSERT INTO url_tags (url_id, tag)
+			SELECT stored.id, unnest($11::text[]) FROM stored WHERE stored.inserted
+			ON CONFLICT DO NOTHING;`
+
+	options, err := json.Marshal(r.LinkOptions)
+	if err != nil {
+		return fmt.Errorf("failed to marshal URL options: %w", err)
+	}
+
+	args := []any{r.ID, r.URL, r.UserID, shared.WithCreatedAt(r).CreatedAt, options, clicksLeft(r), r.Folder, r.Title, r.Deleted, r.Disabled}
+	if len(r.Tags) > 0 {
+		batch.Queue(insertWithTags, append(args, r.Tags)...)
+		return nil
+	}
+	batch.Queue(insert, args...)
+	return nil
+}
+
+// clicksLeft returns the clicks_left column of r: NULL for links without a click limit.
+func clicksLeft(r models.URLRecord) *int {
+	if r.MaxClicks == 0 {
+		return nil
+	}
+	return &r.ClicksLeft
+}
+
 // Get retrieves the original URL for a given short URL.
 func (p *Storage) Get(ctx context.Context, id string) (string, error) {
 	if err := ctx.Err(); err != nil {
 		return "", err
 	}
 
-	deleted := false
-	const query = "SELECT url, deleted FROM urls WHERE id = $1"
+	const query = "SELECT url, deleted, disabled, clicks_left FROM urls WHERE id = $1"
 
-	var url string
-	err := p.pool.QueryRow(ctx, query, id).Scan(&url, &deleted)
+	var (
+		url               string
+		deleted, disabled bool
+		left              *int
+	)
+	err := p.pool.QueryRow(ctx, query, id).Scan(&url, &deleted, &disabled, &left)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return "", fmt.Errorf("URL not found: %s. %w", id, shared.ErrNotFound)
@@ -157,20 +211,82 @@ func (p *Storage) Get(ctx context.Context, id string) (string, error) {
 		return "", err
 	}
 
-	if deleted {
+	if deleted || disabled || (left != nil && *left <= 0) {
 		return "", shared.ErrGone
 	}
 
 	return url, nil
 }
 
+// GetRecord retrieves the URL record for a given short URL.
+func (p *Storage) GetRecord(ctx context.Context, id string) (*models.URLRecord, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	const query = "SELECT id, url, user_id, deleted, disabled, date, options, clicks_left, title, folder, " + tagsColumn + " FROM urls WHERE id = $1"
+
+	var (
+		r       models.URLRecord
+		options []byte
+		left    *int
+	)
+	err := p.pool.QueryRow(ctx, query, id).Scan(&r.ID, &r.URL, &r.UserID, &r.Deleted, &r.Disabled, &r.CreatedAt, &options, &left, &r.Title, &r.Folder, &r.Tags)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("URL not found: %s. %w", id, shared.ErrNotFound)
+		}
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	if r.Deleted || r.Disabled {
+		return nil, shared.ErrGone
+	}
+
+	if err := json.Unmarshal(options, &r.LinkOptions); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal URL options: %w", err)
+	}
+
+	if left != nil {
+		r.ClicksLeft = *left
+	}
+	if len(r.Tags) == 0 {
+		r.Tags = nil
+	}
+	if r.Exhausted() {
+		return nil, shared.ErrGone
+	}
+
+	return &r, nil
+}
+
+// LookupRecord retrieves the URL record for a given short URL, deleted, disabled and exhausted ones included.
+func (p *Storage) LookupRecord(ctx context.Context, id string) (*models.URLRecord, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	const query = "SELECT " + listColumns + ", deleted FROM urls WHERE id = $1"
+
+	var deleted bool
+	record, err := scanLink(p.pool.QueryRow(ctx, query, id), &deleted)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("URL not found: %s. %w", id, shared.ErrNotFound)
+		}
+		return nil, err
+	}
+	record.Deleted = deleted
+	return &record, nil
+}
+
 // ListLinksByUserID lists all URLs associated with a user ID.
 func (p *Storage) ListLinksByUserID(ctx context.Context, baseURL, userID string) ([]models.URLRecord, error) {
 	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
 
-	const query = "SELECT id, url, user_id FROM urls WHERE user_id = $1 AND deleted = FALSE"
+	const query = "SELECT " + listColumns + " FROM urls WHERE user_id = $1 AND deleted = FALSE"
 
 	rows, err := p.pool.Query(ctx, query, userID)
 	if err != nil {
@@ -180,11 +296,120 @@ func (p *Storage) ListLinksByUserID(ctx context.Context, baseURL, userID string)
 
 	rr := make([]models.URLRecord, 0)
 	for rows.Next() {
-		var record models.URLRecord
-		if err := rows.Scan(&record.ID, &record.URL, &record.UserID); err != nil {
-			return nil, fmt.Errorf("failed to scan row: %w", err)
+		record, err := scanListed(rows, baseURL)
+		if err != nil {
+			return nil, err
+		}
+		rr = append(rr, record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	if len(rr) == 0 {
+		return nil, fmt.Errorf("urls not found for userID: %s. %w", userID, shared.ErrNotFound)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return rr, nil
+}
+
+// ForEachLinkByUserID calls fn for every non-deleted URL associated with a user ID.
+// Rows are consumed from the server-side cursor as fn returns, so the result set is never held in memory.
+func (p *Storage) ForEachLinkByUserID(ctx context.Context, baseURL, userID string, fn func(r models.URLRecord) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	const query = "SELECT " + listColumns + " FROM urls WHERE user_id = $1 AND deleted = FALSE"
+
+	rows, err := p.pool.Query(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		record, err := scanListed(rows, baseURL)
+		if err != nil {
+			return err
+		}
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return nil
+}
+
+// ForEachRecord calls fn for every stored link, deleted ones included, in ID order starting after the ID after.
+// IDs are not prefixed. Rows are consumed from the server-side cursor as fn returns.
+func (p *Storage) ForEachRecord(ctx context.Context, after string, fn func(r models.URLRecord) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// IDs are compared byte by byte, as strings.Compare orders them in the other storages
+	const query = "SELECT " + listColumns + `, deleted FROM urls WHERE id COLLATE "C" > $1 ORDER BY id COLLATE "C"`
+
+	rows, err := p.pool.Query(ctx, query, after)
+	if err != nil {
+		return fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var deleted bool
+		record, err := scanLink(rows, &deleted)
+		if err != nil {
+			return err
+		}
+		record.Deleted = deleted
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return nil
+}
+
+// ListLinksByFilter lists the non-deleted URLs of a user matching filter.
+func (p *Storage) ListLinksByFilter(ctx context.Context, baseURL, userID string, filter models.LinkFilter) ([]models.URLRecord, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	const query = `
+			SELECT ` + listColumns + `
+			FROM urls
+			WHERE user_id = $1 AND deleted = FALSE
+				AND ($2 = '' OR folder = $2)
+				AND ($3 = '' OR EXISTS (SELECT 1 FROM url_tags WHERE url_tags.url_id = urls.id AND tag = $3));`
+
+	rows, err := p.pool.Query(ctx, query, userID, filter.Folder, filter.Tag)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var rr []models.URLRecord
+	for rows.Next() {
+		record, err := scanListed(rows, baseURL)
+		if err != nil {
+			return nil, err
 		}
-		record.ID = baseURL + "/" + record.ID
 		rr = append(rr, record)
 	}
 
@@ -196,13 +421,105 @@ func (p *Storage) ListLinksByUserID(ctx context.Context, baseURL, userID string)
 		return nil, fmt.Errorf("urls not found for userID: %s. %w", userID, shared.ErrNotFound)
 	}
 
+	return rr, nil
+}
+
+// SearchLinks returns the non-deleted URLs of a user matching query, best matches first.
+// Every term has to start a word of the search_text column, an ILIKE the trigram index answers.
+// Links are ranked like search.Index ranks them: each word starting with a term scores one,
+// a word equal to the term two, and ties are ordered by ID byte by byte.
+func (p *Storage) SearchLinks(ctx context.Context, baseURL, userID, query string) ([]models.URLRecord, error) {
 	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
 
+	terms := search.Terms(query)
+	if len(terms) == 0 {
+		return []models.URLRecord{}, nil
+	}
+
+	// One ILIKE per term, as the trigram index cannot answer ILIKE ALL over an array.
+	// Terms are letters and digits only, so they need no escaping in LIKE patterns.
+	args := []any{userID, terms, search.MaxResults}
+	conds := make([]string, 0, len(terms))
+	for _, t := range terms {
+		args = append(args, "% "+t+"%")
+		conds = append(conds, fmt.Sprintf("search_text ILIKE $%d", len(args)))
+	}
+
+	q := `
+			SELECT ` + listColumns + `
+			FROM urls
+			WHERE user_id = $1 AND deleted = FALSE AND ` + strings.Join(conds, " AND ") + `
+			ORDER BY (
+				SELECT sum(
+					(length(search_text) - length(replace(search_text, ' ' || t, ''))) / length(' ' || t) +
+					(length(search_text) - length(replace(search_text, ' ' || t || ' ', ''))) / length(' ' || t || ' '))
+				FROM unnest($2::text[]) AS t
+			) DESC, id COLLATE "C"
+			LIMIT $3;`
+
+	rows, err := p.pool.Query(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	rr := make([]models.URLRecord, 0)
+	for rows.Next() {
+		record, err := scanListed(rows, baseURL)
+		if err != nil {
+			return nil, err
+		}
+		rr = append(rr, record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
 	return rr, nil
 }
 
+// tagsColumn selects the sorted tags of a link from the url_tags join table.
+const tagsColumn = "ARRAY(SELECT tag FROM url_tags WHERE url_tags.url_id = urls.id ORDER BY tag)"
+
+// listColumns are the columns of a listed link, read by scanListed.
+const listColumns = "id, url, user_id, date, options, clicks_left, title, folder, disabled, " + tagsColumn
+
+// scanListed scans a row of listColumns, prefixing the ID with baseURL.
+func scanListed(rows pgx.Rows, baseURL string) (models.URLRecord, error) {
+	record, err := scanLink(rows)
+	if err != nil {
+		return record, err
+	}
+	record.ID = baseURL + "/" + record.ID
+	return record, nil
+}
+
+// scanLink scans a row of listColumns followed by the columns read into extra.
+func scanLink(row pgx.Row, extra ...any) (models.URLRecord, error) {
+	var (
+		record  models.URLRecord
+		options []byte
+		left    *int
+	)
+	dest := append([]any{&record.ID, &record.URL, &record.UserID, &record.CreatedAt, &options, &left, &record.Title, &record.Folder, &record.Disabled, &record.Tags}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return record, fmt.Errorf("failed to scan row: %w", err)
+	}
+	if err := json.Unmarshal(options, &record.LinkOptions); err != nil {
+		return record, fmt.Errorf("failed to unmarshal URL options: %w", err)
+	}
+	if left != nil {
+		record.ClicksLeft = *left
+	}
+	if len(record.Tags) == 0 {
+		record.Tags = nil
+	}
+	return record, nil
+}
+
 // DeleteUserURLs deletes multiple URLs associated with a user ID.
 func (p *Storage) DeleteUserURLs(ctx context.Context, ids []string, userID string) error {
 	const setDeleteBatch = `
@@ -225,6 +542,312 @@ func (p *Storage) DeleteUserURLs(ctx context.Context, ids []string, userID strin
 	return nil
 }
 
+// PurgeDeleted removes the deleted links for good, with their tags and click counters,
+// and returns how many were removed.
+func (p *Storage) PurgeDeleted(ctx context.Context) (int64, error) {
+	const purge = `
+			WITH purged AS (
+				DELETE FROM urls WHERE deleted RETURNING id
+			), clicks AS (
+				DELETE FROM variant_clicks WHERE url_id IN (SELECT id FROM purged)
+			)
+			SELECT count(*) FROM purged;`
+
+	var purged int64
+	if err := p.pool.QueryRow(ctx, purge).Scan(&purged); err != nil {
+		return 0, fmt.Errorf("failed to purge deleted urls: %w", err)
+	}
+
+	return purged, nil
+}
+
+// UpdateLinkOptions changes the options of a link owned by userID.
+// The row is locked while update runs, so concurrent changes of the same link are applied one after another.
+func (p *Storage) UpdateLinkOptions(ctx context.Context, id, userID string, update func(o *models.LinkOptions) error) error {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	const query = "SELECT deleted, options FROM urls WHERE id = $1 AND user_id = $2 FOR UPDATE"
+
+	var (
+		deleted bool
+		data    []byte
+	)
+	err = tx.QueryRow(ctx, query, id, userID).Scan(&deleted, &data)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("URL not found: %s. %w", id, shared.ErrNotFound)
+		}
+		return fmt.Errorf("query failed: %w", err)
+	}
+
+	if deleted {
+		return shared.ErrGone
+	}
+
+	var options models.LinkOptions
+	if err := json.Unmarshal(data, &options); err != nil {
+		return fmt.Errorf("failed to unmarshal URL options: %w", err)
+	}
+
+	if err := update(&options); err != nil {
+		return err
+	}
+
+	if data, err = json.Marshal(options); err != nil {
+		return fmt.Errorf("failed to marshal URL options: %w", err)
+	}
+
+	const set = "UPDATE urls SET options = $1 WHERE id = $2"
+	if _, err := tx.Exec(ctx, set, data, id); err != nil {
+		return fmt.Errorf("failed to update URL options: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// AddLinkTags adds tags to a link owned by userID.
+// The count is checked after the insert, so the transaction is rolled back when the link ends up with too many tags.
+func (p *Storage) AddLinkTags(ctx context.Context, id, userID string, tags []string) error {
+	return p.updateOwnLink(ctx, id, userID, func(tx pgx.Tx) error {
+		const insert = `
+				INSERT INTO url_tags (url_id, tag)
+				SELECT $1, unnest($2::text[])
+				ON CONFLICT DO NOTHING;`
+
+		if _, err := tx.Exec(ctx, insert, id, tags); err != nil {
+			return fmt.Errorf("failed to add URL tags: %w", err)
+		}
+
+		var n int
+		if err := tx.QueryRow(ctx, "SELECT COUNT(*) FROM url_tags WHERE url_id = $1", id).Scan(&n); err != nil {
+			return fmt.Errorf("failed to count URL tags: %w", err)
+		}
+		if n > models.MaxTags {
+			return shared.ErrTooManyTags
+		}
+		return nil
+	})
+}
+
+// RemoveLinkTags removes tags from a link owned by userID.
+func (p *Storage) RemoveLinkTags(ctx context.Context, id, userID string, tags []string) error {
+	return p.updateOwnLink(ctx, id, userID, func(tx pgx.Tx) error {
+		const remove = "DELETE FROM url_tags WHERE url_id = $1 AND tag = ANY($2::text[])"
+		if _, err := tx.Exec(ctx, remove, id, tags); err != nil {
+			return fmt.Errorf("failed to remove URL tags: %w", err)
+		}
+		return nil
+	})
+}
+
+// SetLinkFolder files a link owned by userID in folder.
+func (p *Storage) SetLinkFolder(ctx context.Context, id, userID, folder string) error {
+	return p.updateOwnLink(ctx, id, userID, func(tx pgx.Tx) error {
+		const set = "UPDATE urls SET folder = $1 WHERE id = $2"
+		if _, err := tx.Exec(ctx, set, folder, id); err != nil {
+			return fmt.Errorf("failed to update URL folder: %w", err)
+		}
+		return nil
+	})
+}
+
+// updateOwnLink runs update in a transaction holding the row lock of a non-deleted link owned by userID.
+func (p *Storage) updateOwnLink(ctx context.Context, id, userID string, update func(tx pgx.Tx) error) error {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	const query = "SELECT deleted FROM urls WHERE id = $1 AND user_id = $2 FOR UPDATE"
+
+	var deleted bool
+	if err := tx.QueryRow(ctx, query, id, userID).Scan(&deleted); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("URL not found: %s. %w", id, shared.ErrNotFound)
+		}
+		return fmt.Errorf("query failed: %w", err)
+	}
+
+	if deleted {
+		return shared.ErrGone
+	}
+
+	if err := update(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// SetLinkDisabled disables or re-enables the link with the given ID, whoever owns it.
+func (p *Storage) SetLinkDisabled(ctx context.Context, id string, disabled bool) error {
+	const update = "UPDATE urls SET disabled = $2 WHERE id = $1"
+
+	tag, err := p.pool.Exec(ctx, update, id, disabled)
+	if err != nil {
+		return fmt.Errorf("failed to update URL: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("URL not found: %s. %w", id, shared.ErrNotFound)
+	}
+	return nil
+}
+
+// ConsumeClick takes one of the remaining redirects of a link limited by MaxClicks.
+// The conditional UPDATE only succeeds while clicks are left, so concurrent redirects never exceed the limit.
+func (p *Storage) ConsumeClick(ctx context.Context, id string) error {
+	const consume = `
+			UPDATE urls
+			SET clicks_left = clicks_left - 1
+			WHERE id = $1 AND deleted = FALSE AND disabled = FALSE AND clicks_left > 0;`
+
+	tag, err := p.pool.Exec(ctx, consume, id)
+	if err != nil {
+		return fmt.Errorf("failed to consume click: %w", err)
+	}
+	if tag.RowsAffected() == 1 {
+		return nil
+	}
+
+	// Nothing was updated: tell a missing link from a gone one and from one without a limit
+	const query = "SELECT deleted OR disabled, clicks_left FROM urls WHERE id = $1"
+
+	var (
+		deleted bool
+		left    *int
+	)
+	err = p.pool.QueryRow(ctx, query, id).Scan(&deleted, &left)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("URL not found: %s. %w", id, shared.ErrNotFound)
+		}
+		return fmt.Errorf("query failed: %w", err)
+	}
+
+	if deleted || left != nil {
+		return shared.ErrGone
+	}
+	return nil
+}
+
+// CountVariantClick adds one redirect to the click counter of an A/B variant of a link.
+func (p *Storage) CountVariantClick(ctx context.Context, id, variant string) error {
+	const upsert = `
+			INSERT INTO variant_clicks (url_id, variant, clicks)
+			VALUES ($1, $2, 1)
+			ON CONFLICT (url_id, variant)
+			DO UPDATE SET clicks = variant_clicks.clicks + 1;`
+
+	if _, err := p.pool.Exec(ctx, upsert, id, variant); err != nil {
+		return fmt.Errorf("failed to count variant click: %w", err)
+	}
+
+	return nil
+}
+
+// VariantClicks returns the click counters of the A/B variants of a link by variant name.
+func (p *Storage) VariantClicks(ctx context.Context, id string) (map[string]int64, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	const query = "SELECT variant, clicks FROM variant_clicks WHERE url_id = $1"
+
+	rows, err := p.pool.Query(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	clicks := make(map[string]int64)
+	for rows.Next() {
+		var (
+			variant string
+			n       int64
+		)
+		if err := rows.Scan(&variant, &n); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		clicks[variant] = n
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return clicks, nil
+}
+
+// GetUTMTemplate retrieves the default UTM tags of a user.
+func (p *Storage) GetUTMTemplate(ctx context.Context, userID string) (*models.UTM, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	const query = "SELECT template FROM utm_templates WHERE user_id = $1"
+
+	var data []byte
+	err := p.pool.QueryRow(ctx, query, userID).Scan(&data)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("UTM template not found for userID: %s. %w", userID, shared.ErrNotFound)
+		}
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	t := new(models.UTM)
+	if err := json.Unmarshal(data, t); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal UTM template: %w", err)
+	}
+
+	return t, nil
+}
+
+// PutUTMTemplate replaces the default UTM tags of a user.
+func (p *Storage) PutUTMTemplate(ctx context.Context, userID string, t models.UTM) error {
+	if t.IsZero() {
+		const remove = "DELETE FROM utm_templates WHERE user_id = $1"
+		if _, err := p.pool.Exec(ctx, remove, userID); err != nil {
+			return fmt.Errorf("failed to delete UTM template: %w", err)
+		}
+		return nil
+	}
+
+	const upsert = `
+			INSERT INTO utm_templates (user_id, template)
+			VALUES ($1, $2)
+			ON CONFLICT (user_id)
+			DO UPDATE SET template = EXCLUDED.template;`
+
+	data, err := json.Marshal(t)
+	if err != nil {
+		return fmt.Errorf("failed to marshal UTM template: %w", err)
+	}
+
+	if _, err := p.pool.Exec(ctx, upsert, userID, data); err != nil {
+		return fmt.Errorf("failed to store UTM template: %w", err)
+	}
+
+	return nil
+}
+
 // Stats retrieves count stats: urls and users.
 func (p *Storage) Stats(ctx context.Context) (*models.Stats, error) {
 	if err := ctx.Err(); err != nil {
@@ -2,13 +2,22 @@ package postgres
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/apetsko/shortugo/internal/audit"
 	"github.com/apetsko/shortugo/internal/logging"
 	"github.com/apetsko/shortugo/internal/models"
+	"github.com/apetsko/shortugo/internal/storages/infile"
+	"github.com/apetsko/shortugo/internal/storages/inmem"
 	"github.com/apetsko/shortugo/internal/storages/shared"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -64,7 +73,7 @@ func waitForTestDB() {
 			logger.Error("❌ Timeout while waiting for PostgreSQL")
 			os.Exit(1)
 		case <-tick:
-			storage, err := New(connStr, logger)
+			storage, err := New(connStr, logger, nil)
 			if err == nil {
 				_ = storage.Close()
 				logger.Info("✅ PostgreSQL is ready")
@@ -75,7 +84,7 @@ func waitForTestDB() {
 }
 
 func setupTestStorage(t *testing.T) *Storage {
-	storage, err := New(connStr, logger)
+	storage, err := New(connStr, logger, nil)
 	require.NoError(t, err)
 	t.Cleanup(func() { _ = storage.Close() })
 	return storage
@@ -126,6 +135,177 @@ func TestStorage_Get_Deleted(t *testing.T) {
 	assert.ErrorIs(t, err, shared.ErrGone)
 }
 
+func TestStorage_GetRecord(t *testing.T) {
+	storage := setupTestStorage(t)
+	ctx := context.Background()
+
+	rec := models.URLRecord{
+		ID:        "id-rec",
+		URL:       "https://example.com",
+		UserID:    "user-rec",
+		CreatedAt: time.Date(2024, 1, 19, 0, 0, 0, 0, time.UTC), // The date column keeps the day only.
+		LinkOptions: models.LinkOptions{
+			RedirectType: 301,
+			RedirectMode: models.RedirectModeHTML,
+			UTM:          models.UTM{Source: "newsletter", Campaign: "spring"},
+		},
+	}
+	require.NoError(t, storage.Put(ctx, rec))
+	require.NoError(t, storage.PutBatch(ctx, []models.URLRecord{{ID: "id-rec-plain", URL: "https://plain.com", UserID: "user-rec"}}))
+
+	got, err := storage.GetRecord(ctx, "id-rec")
+	require.NoError(t, err)
+	assert.Equal(t, rec, *got)
+
+	got, err = storage.GetRecord(ctx, "id-rec-plain")
+	require.NoError(t, err)
+	assert.Equal(t, models.LinkOptions{}, got.LinkOptions)
+
+	_, err = storage.GetRecord(ctx, "unknown-id")
+	assert.ErrorIs(t, err, shared.ErrNotFound)
+
+	require.NoError(t, storage.DeleteUserURLs(ctx, []string{"id-rec"}, "user-rec"))
+	_, err = storage.GetRecord(ctx, "id-rec")
+	assert.ErrorIs(t, err, shared.ErrGone)
+}
+
+func TestStorage_UpdateLinkOptions(t *testing.T) {
+	storage := setupTestStorage(t)
+	ctx := context.Background()
+
+	require.NoError(t, storage.Put(ctx, models.URLRecord{ID: "id-upd", URL: "https://upd.com", UserID: "user-upd"}))
+	require.NoError(t, storage.Put(ctx, models.URLRecord{ID: "id-upd-del", URL: "https://upd-del.com", UserID: "user-upd"}))
+	require.NoError(t, storage.DeleteUserURLs(ctx, []string{"id-upd-del"}, "user-upd"))
+
+	setHash := func(o *models.LinkOptions) error {
+		o.PasswordHash = "hash"
+		return nil
+	}
+
+	require.NoError(t, storage.UpdateLinkOptions(ctx, "id-upd", "user-upd", setHash))
+	got, err := storage.GetRecord(ctx, "id-upd")
+	require.NoError(t, err)
+	assert.Equal(t, "hash", got.PasswordHash)
+
+	assert.ErrorIs(t, storage.UpdateLinkOptions(ctx, "id-upd", "other", setHash), shared.ErrNotFound)
+	assert.ErrorIs(t, storage.UpdateLinkOptions(ctx, "id-upd-del", "user-upd", setHash), shared.ErrGone)
+}
+
+func TestStorage_ConsumeClick(t *testing.T) {
+	storage := setupTestStorage(t)
+	ctx := context.Background()
+
+	limited := models.LinkOptions{MaxClicks: 1}
+	require.NoError(t, storage.Put(ctx, models.URLRecord{ID: "id-once", URL: "https://once.com", UserID: "user-clk", LinkOptions: limited, ClicksLeft: 1}))
+	require.NoError(t, storage.Put(ctx, models.URLRecord{ID: "id-free", URL: "https://free.com", UserID: "user-clk"}))
+
+	require.NoError(t, storage.ConsumeClick(ctx, "id-once"))
+	assert.ErrorIs(t, storage.ConsumeClick(ctx, "id-once"), shared.ErrGone)
+	_, err := storage.Get(ctx, "id-once")
+	assert.ErrorIs(t, err, shared.ErrGone)
+	_, err = storage.GetRecord(ctx, "id-once")
+	assert.ErrorIs(t, err, shared.ErrGone)
+
+	require.NoError(t, storage.ConsumeClick(ctx, "id-free"))
+	assert.ErrorIs(t, storage.ConsumeClick(ctx, "id-missing-clk"), shared.ErrNotFound)
+}
+
+func TestStorage_ConsumeClick_Concurrent(t *testing.T) {
+	storage := setupTestStorage(t)
+	ctx := context.Background()
+
+	const maxClicks, visitors = 10, 50
+	require.NoError(t, storage.Put(ctx, models.URLRecord{
+		ID: "id-many", URL: "https://many.com", UserID: "user-clk", LinkOptions: models.LinkOptions{MaxClicks: maxClicks}, ClicksLeft: maxClicks,
+	}))
+
+	var (
+		followed atomic.Int32
+		wg       sync.WaitGroup
+	)
+	for range visitors {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := storage.ConsumeClick(ctx, "id-many"); err == nil {
+				followed.Add(1)
+			} else {
+				assert.ErrorIs(t, err, shared.ErrGone)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(maxClicks), followed.Load())
+}
+
+func TestStorage_RoutingRules(t *testing.T) {
+	storage := setupTestStorage(t)
+	ctx := context.Background()
+
+	rules := []models.RoutingRule{
+		{Platform: models.PlatformIOS, URL: "https://apps.apple.com/app/id1"},
+		{Language: "de", Country: "AT", URL: "https://example.com/at"},
+	}
+	require.NoError(t, storage.Put(ctx, models.URLRecord{ID: "id-rules", URL: "https://rules.com", UserID: "user-rules", LinkOptions: models.LinkOptions{Rules: rules}}))
+
+	got, err := storage.GetRecord(ctx, "id-rules")
+	require.NoError(t, err)
+	assert.Equal(t, rules, got.Rules)
+
+	require.NoError(t, storage.UpdateLinkOptions(ctx, "id-rules", "user-rules", func(o *models.LinkOptions) error {
+		o.Rules = rules[:1]
+		return nil
+	}))
+	got, err = storage.GetRecord(ctx, "id-rules")
+	require.NoError(t, err)
+	assert.Equal(t, rules[:1], got.Rules)
+}
+
+func TestStorage_VariantClicks(t *testing.T) {
+	storage := setupTestStorage(t)
+	ctx := context.Background()
+
+	clicks, err := storage.VariantClicks(ctx, "id-ab")
+	require.NoError(t, err)
+	assert.Empty(t, clicks)
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, storage.CountVariantClick(ctx, "id-ab", "blue"))
+		}()
+	}
+	wg.Wait()
+	require.NoError(t, storage.CountVariantClick(ctx, "id-ab", "green"))
+
+	clicks, err = storage.VariantClicks(ctx, "id-ab")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"blue": 20, "green": 1}, clicks)
+}
+
+func TestStorage_UTMTemplate(t *testing.T) {
+	storage := setupTestStorage(t)
+	ctx := context.Background()
+
+	_, err := storage.GetUTMTemplate(ctx, "user-utm")
+	assert.ErrorIs(t, err, shared.ErrNotFound)
+
+	template := models.UTM{Source: "newsletter", Medium: "email"}
+	require.NoError(t, storage.PutUTMTemplate(ctx, "user-utm", template))
+	require.NoError(t, storage.PutUTMTemplate(ctx, "user-utm", models.UTM{Source: "ads"}))
+
+	got, err := storage.GetUTMTemplate(ctx, "user-utm")
+	require.NoError(t, err)
+	assert.Equal(t, models.UTM{Source: "ads"}, *got)
+
+	require.NoError(t, storage.PutUTMTemplate(ctx, "user-utm", models.UTM{}))
+	_, err = storage.GetUTMTemplate(ctx, "user-utm")
+	assert.ErrorIs(t, err, shared.ErrNotFound)
+}
+
 func TestStorage_PutBatch(t *testing.T) {
 	storage := setupTestStorage(t)
 	ctx := context.Background()
@@ -171,6 +351,101 @@ func TestStorage_ListLinksByUserID(t *testing.T) {
 	assert.Contains(t, links[0].ID, "http://short/")
 }
 
+func TestStorage_ForEachLinkByUserID(t *testing.T) {
+	storage := setupTestStorage(t)
+	ctx := context.Background()
+
+	records := []models.URLRecord{
+		{ID: "f1", URL: "http://1.com", UserID: "user-f", ClicksLeft: 5, LinkOptions: models.LinkOptions{RedirectType: 307, MaxClicks: 5}},
+		{ID: "f2", URL: "http://2.com", UserID: "user-f"},
+	}
+	require.NoError(t, storage.PutBatch(ctx, records))
+
+	got := make(map[string]models.URLRecord)
+	err := storage.ForEachLinkByUserID(ctx, "http://short", "user-f", func(r models.URLRecord) error {
+		got[r.ID] = r
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Len(t, got, 2)
+
+	// Links are walked with all of their settings, so they can be exported
+	f1 := got["http://short/f1"]
+	assert.Equal(t, 307, f1.RedirectType)
+	assert.Equal(t, 5, f1.ClicksLeft)
+	assert.False(t, f1.CreatedAt.IsZero())
+}
+
+func TestStorage_ForEachRecord(t *testing.T) {
+	storage := setupTestStorage(t)
+	ctx := context.Background()
+
+	created := time.Date(2024, 1, 19, 10, 30, 0, 0, time.UTC)
+	require.NoError(t, storage.PutBatch(ctx, []models.URLRecord{
+		{ID: "zz-rec-2", URL: "http://2.com", UserID: "user-rec", CreatedAt: created, Deleted: true},
+		{ID: "zz-rec-1", URL: "http://1.com", UserID: "user-rec", CreatedAt: created, Tags: []string{"go"}},
+		// Generated IDs are mixed-case base64url, ordered byte by byte like strings.Compare does
+		{ID: "zz-rec-a", URL: "http://a.com", UserID: "user-rec"},
+		{ID: "zz-rec-B", URL: "http://b.com", UserID: "user-rec"},
+		{ID: "zz-rec-_c", URL: "http://c.com", UserID: "user-rec"},
+	}))
+
+	walk := func(after string) []models.URLRecord {
+		var got []models.URLRecord
+		err := storage.ForEachRecord(ctx, after, func(r models.URLRecord) error {
+			if strings.HasPrefix(r.ID, "zz-rec-") {
+				got = append(got, r)
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		return got
+	}
+	ids := func(rr []models.URLRecord) []string {
+		out := make([]string, 0, len(rr))
+		for _, r := range rr {
+			out = append(out, r.ID)
+		}
+		return out
+	}
+
+	got := walk("zz-rec-")
+	require.Equal(t, []string{"zz-rec-1", "zz-rec-2", "zz-rec-B", "zz-rec-_c", "zz-rec-a"}, ids(got))
+	assert.True(t, slices.IsSorted(ids(got)), "IDs in strings.Compare order")
+	assert.Equal(t, []string{"go"}, got[0].Tags)
+	assert.True(t, created.Equal(got[0].CreatedAt), "the time of day is kept")
+	assert.True(t, got[1].Deleted)
+
+	// Resuming after a mixed-case ID neither skips nor repeats records
+	assert.Equal(t, []string{"zz-rec-_c", "zz-rec-a"}, ids(walk("zz-rec-B")))
+}
+
+func TestStorage_PurgeDeleted(t *testing.T) {
+	storage := setupTestStorage(t)
+	ctx := context.Background()
+
+	require.NoError(t, storage.PutBatch(ctx, []models.URLRecord{
+		{ID: "id-purge-1", URL: "https://purge1.com", UserID: "user-purge", Tags: []string{"go"}},
+		{ID: "id-purge-2", URL: "https://purge2.com", UserID: "user-purge"},
+	}))
+	require.NoError(t, storage.CountVariantClick(ctx, "id-purge-1", "blue"))
+	require.NoError(t, storage.DeleteUserURLs(ctx, []string{"id-purge-1"}, "user-purge"))
+
+	purged, err := storage.PurgeDeleted(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+
+	_, err = storage.Get(ctx, "id-purge-1")
+	assert.ErrorIs(t, err, shared.ErrNotFound)
+	clicks, err := storage.VariantClicks(ctx, "id-purge-1")
+	require.NoError(t, err)
+	assert.Empty(t, clicks)
+
+	url, err := storage.Get(ctx, "id-purge-2")
+	require.NoError(t, err)
+	assert.Equal(t, "https://purge2.com", url)
+}
+
 func TestStorage_ListLinksByUserID_NotFound(t *testing.T) {
 	storage := setupTestStorage(t)
 	ctx := context.Background()
@@ -210,3 +485,206 @@ func TestStorage_CtxCancelled(t *testing.T) {
 	_, err := storage.Get(ctx, "any")
 	assert.ErrorIs(t, err, context.Canceled)
 }
+
+func TestStorage_LinkTags(t *testing.T) {
+	storage := setupTestStorage(t)
+	ctx := context.Background()
+
+	require.NoError(t, storage.Put(ctx, models.URLRecord{ID: "id-tags", URL: "http://tags.com", UserID: "user-tags", Tags: []string{"promo"}}))
+	require.NoError(t, storage.Put(ctx, models.URLRecord{ID: "id-tags-2", URL: "http://tags2.com", UserID: "user-tags"}))
+
+	require.NoError(t, storage.AddLinkTags(ctx, "id-tags", "user-tags", []string{"2026", "promo"}))
+	require.NoError(t, storage.SetLinkFolder(ctx, "id-tags", "user-tags", "campaigns"))
+
+	got, err := storage.GetRecord(ctx, "id-tags")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2026", "promo"}, got.Tags)
+	assert.Equal(t, "campaigns", got.Folder)
+
+	rr, err := storage.ListLinksByFilter(ctx, "http://localhost", "user-tags", models.LinkFilter{Tag: "promo", Folder: "campaigns"})
+	require.NoError(t, err)
+	require.Len(t, rr, 1)
+	assert.Equal(t, "http://localhost/id-tags", rr[0].ID)
+
+	require.NoError(t, storage.RemoveLinkTags(ctx, "id-tags", "user-tags", []string{"promo"}))
+	_, err = storage.ListLinksByFilter(ctx, "http://localhost", "user-tags", models.LinkFilter{Tag: "promo"})
+	assert.ErrorIs(t, err, shared.ErrNotFound)
+
+	assert.ErrorIs(t, storage.AddLinkTags(ctx, "id-tags", "other", []string{"x"}), shared.ErrNotFound)
+
+	many := make([]string, models.MaxTags)
+	for i := range many {
+		many[i] = fmt.Sprintf("tag%02d", i)
+	}
+	assert.ErrorIs(t, storage.AddLinkTags(ctx, "id-tags", "user-tags", many), shared.ErrTooManyTags)
+	got, err = storage.GetRecord(ctx, "id-tags")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2026"}, got.Tags)
+}
+
+func TestStorage_PutTagsOnConflict(t *testing.T) {
+	storage := setupTestStorage(t)
+	ctx := context.Background()
+
+	require.NoError(t, storage.Put(ctx, models.URLRecord{ID: "id-owned", URL: "http://owned.com", UserID: "owner", Tags: []string{"mine"}}))
+	require.NoError(t, storage.Put(ctx, models.URLRecord{ID: "id-owned", URL: "http://owned.com", UserID: "intruder", Tags: []string{"spam"}}))
+	require.NoError(t, storage.PutBatch(ctx, []models.URLRecord{
+		{ID: "id-owned", URL: "http://owned.com", UserID: "intruder", Tags: []string{"batch-spam"}},
+	}))
+
+	got, err := storage.GetRecord(ctx, "id-owned")
+	require.NoError(t, err)
+	assert.Equal(t, "owner", got.UserID)
+	assert.Equal(t, []string{"mine"}, got.Tags)
+}
+
+func TestStorage_SearchLinks(t *testing.T) {
+	storage := setupTestStorage(t)
+	ctx := context.Background()
+
+	require.NoError(t, storage.PutBatch(ctx, []models.URLRecord{
+		{ID: "id-search-1", URL: "https://example.com/docs", UserID: "user-search", Title: "Go docs"},
+		{ID: "id-search-2", URL: "https://example.org/blog", UserID: "user-search"},
+		{ID: "id-search-3", URL: "https://example.com/docs", UserID: "user-search-other"},
+	}))
+	require.NoError(t, storage.AddLinkTags(ctx, "id-search-2", "user-search", []string{"golang"}))
+
+	rr, err := storage.SearchLinks(ctx, "http://localhost", "user-search", "example docs")
+	require.NoError(t, err)
+	require.Len(t, rr, 1)
+	assert.Equal(t, "http://localhost/id-search-1", rr[0].ID)
+	assert.Equal(t, "Go docs", rr[0].Title)
+
+	rr, err = storage.SearchLinks(ctx, "http://localhost", "user-search", "golan")
+	require.NoError(t, err)
+	require.Len(t, rr, 1)
+	assert.Equal(t, "http://localhost/id-search-2", rr[0].ID)
+
+	require.NoError(t, storage.RemoveLinkTags(ctx, "id-search-2", "user-search", []string{"golang"}))
+	rr, err = storage.SearchLinks(ctx, "http://localhost", "user-search", "golang")
+	require.NoError(t, err)
+	assert.Empty(t, rr)
+}
+
+func TestStorage_SearchLinks_SameAsOtherBackends(t *testing.T) {
+	ctx := context.Background()
+	const user = "user-search-backends"
+
+	records := []models.URLRecord{
+		{ID: "sb-Docs", URL: "https://example.com/docs/go", UserID: user, Title: "Go docs", Tags: []string{"golang"}},
+		{ID: "sb-blog", URL: "https://example.org/blog/go-go", UserID: user, Title: "Blog"},
+		{ID: "sb-ample", URL: "https://ample.net", UserID: user, Title: "Sample page"},
+		{ID: "sb-Tour", URL: "https://go.dev/tour", UserID: user, Tags: []string{"docs", "tour"}},
+		{ID: "sb-other", URL: "https://example.com/docs", UserID: "user-search-backends-other"},
+	}
+
+	pg := setupTestStorage(t)
+	require.NoError(t, pg.PutBatch(ctx, records))
+
+	mem := inmem.New()
+	require.NoError(t, mem.PutBatch(ctx, records))
+
+	file, err := infile.New(filepath.Join(t.TempDir(), "urls.json"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = file.Close() })
+	require.NoError(t, file.PutBatch(ctx, records))
+
+	ids := func(rr []models.URLRecord) []string {
+		out := make([]string, 0, len(rr))
+		for _, r := range rr {
+			out = append(out, r.ID)
+		}
+		return out
+	}
+
+	for _, query := range []string{"example", "exa doc", "go", "GO blog", "docs", "ample", "tour golang", "amp", "missing"} {
+		t.Run(query, func(t *testing.T) {
+			want, err := mem.SearchLinks(ctx, "http://localhost", user, query)
+			require.NoError(t, err)
+
+			got, err := pg.SearchLinks(ctx, "http://localhost", user, query)
+			require.NoError(t, err)
+			assert.Equal(t, ids(want), ids(got), "postgres")
+
+			got, err = file.SearchLinks(ctx, "http://localhost", user, query)
+			require.NoError(t, err)
+			assert.Equal(t, ids(want), ids(got), "infile")
+		})
+	}
+}
+
+func TestStorage_SetLinkDisabled(t *testing.T) {
+	storage := setupTestStorage(t)
+	ctx := context.Background()
+
+	require.NoError(t, storage.PutBatch(ctx, []models.URLRecord{
+		{ID: "id-disable-1", URL: "https://disable1.com", UserID: "user-disable"},
+		{ID: "id-disable-2", URL: "https://disable2.com", UserID: "user-disable"},
+	}))
+	require.NoError(t, storage.DeleteUserURLs(ctx, []string{"id-disable-2"}, "user-disable"))
+
+	require.NoError(t, storage.SetLinkDisabled(ctx, "id-disable-1", true))
+
+	_, err := storage.Get(ctx, "id-disable-1")
+	assert.ErrorIs(t, err, shared.ErrGone)
+	_, err = storage.GetRecord(ctx, "id-disable-1")
+	assert.ErrorIs(t, err, shared.ErrGone)
+
+	rec, err := storage.LookupRecord(ctx, "id-disable-1")
+	require.NoError(t, err)
+	assert.True(t, rec.Disabled)
+	assert.Equal(t, "user-disable", rec.UserID)
+
+	rec, err = storage.LookupRecord(ctx, "id-disable-2")
+	require.NoError(t, err)
+	assert.True(t, rec.Deleted)
+
+	require.NoError(t, storage.SetLinkDisabled(ctx, "id-disable-1", false))
+	url, err := storage.Get(ctx, "id-disable-1")
+	require.NoError(t, err)
+	assert.Equal(t, "https://disable1.com", url)
+
+	assert.ErrorIs(t, storage.SetLinkDisabled(ctx, "id-unknown", true), shared.ErrNotFound)
+	_, err = storage.LookupRecord(ctx, "id-unknown")
+	assert.ErrorIs(t, err, shared.ErrNotFound)
+}
+
+func TestAuditLog(t *testing.T) {
+	storage := setupTestStorage(t)
+	ctx := context.Background()
+	log := storage.AuditLog()
+
+	since := time.Now().Add(-time.Second)
+	actor := fmt.Sprintf("audit-user-%d", time.Now().UnixNano())
+	require.NoError(t, log.Record(ctx, audit.Entry{
+		Actor: actor, Remote: "192.0.2.1", Protocol: audit.ProtocolHTTP, Action: audit.ActionCreateLinks,
+		Targets: []string{"id-audit-1", "id-audit-2"},
+	}))
+	require.NoError(t, log.Record(ctx, audit.Entry{
+		Actor: actor, Protocol: audit.ProtocolGRPC, Action: audit.ActionUpdateLink,
+		Targets: []string{"id-audit-2"}, Details: map[string]string{"field": "rules"},
+	}))
+
+	entries, err := log.Query(ctx, audit.Filter{Actor: actor, Since: since})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, audit.ActionUpdateLink, entries[0].Action)
+	assert.Equal(t, map[string]string{"field": "rules"}, entries[0].Details)
+	assert.Equal(t, "192.0.2.1", entries[1].Remote)
+	assert.Equal(t, []string{"id-audit-1", "id-audit-2"}, entries[1].Targets)
+
+	entries, err = log.Query(ctx, audit.Filter{Actor: actor, Target: "id-audit-1"})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, audit.ActionCreateLinks, entries[0].Action)
+
+	entries, err = log.Query(ctx, audit.Filter{Actor: actor, Protocol: audit.ProtocolGRPC, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	// Entries cannot be changed or removed
+	_, err = storage.pool.Exec(ctx, "UPDATE audit_log SET actor = 'someone' WHERE actor = $1", actor)
+	assert.Error(t, err)
+	_, err = storage.pool.Exec(ctx, "DELETE FROM audit_log WHERE actor = $1", actor)
+	assert.Error(t, err)
+}
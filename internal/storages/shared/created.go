@@ -0,0 +1,16 @@
+package shared
+
+import (
+	"time"
+
+	"github.com/apetsko/shortugo/internal/models"
+)
+
+// WithCreatedAt returns r with its creation time set to now, unless the caller already set one,
+// e.g. when records are copied between storages.
+func WithCreatedAt(r models.URLRecord) models.URLRecord {
+	if r.CreatedAt.IsZero() {
+		r.CreatedAt = time.Now().UTC()
+	}
+	return r
+}
@@ -9,3 +9,6 @@ var ErrNotFound = errors.New("not found")
 
 // ErrGone is returned when a requested resource is permanently deleted.
 var ErrGone = errors.New("Gone")
+
+// ErrTooManyTags is returned when adding tags would exceed models.MaxTags on a link.
+var ErrTooManyTags = errors.New("too many tags")
@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/apetsko/shortugo/internal/audit"
 	"github.com/apetsko/shortugo/internal/logging"
 	"github.com/apetsko/shortugo/internal/models"
 	"github.com/apetsko/shortugo/internal/server/http/handlers"
@@ -23,11 +24,12 @@ type Storage interface {
 }
 
 // Init initializes the appropriate storage based on the provided configuration.
-func Init(databaseDSN, fileStoragePath string, logger *logging.Logger) (handlers.Storage, error) {
+// migrating, when not nil, is told when database migrations start and end.
+func Init(databaseDSN, fileStoragePath string, logger *logging.Logger, migrating func(running bool)) (handlers.Storage, error) {
 	switch {
 	case databaseDSN != "":
 		// Initialize PostgreSQL storage if databaseDSN is provided.
-		s, err := postgres.New(databaseDSN, logger)
+		s, err := postgres.New(databaseDSN, logger, migrating)
 		if err != nil {
 			return nil, err
 		}
@@ -49,6 +51,27 @@ func Init(databaseDSN, fileStoragePath string, logger *logging.Logger) (handlers
 	}
 }
 
+// InitAudit initializes the audit log: the JSONL file auditFile when it is given, the audit_log table
+// when s is a database storage, and the application log, which cannot be queried, otherwise.
+func InitAudit(auditFile string, s handlers.Storage, logger *logging.Logger) (audit.Recorder, error) {
+	if auditFile != "" {
+		f, err := audit.OpenFile(auditFile)
+		if err != nil {
+			return nil, err
+		}
+		logger.Infof("Using audit log file: %s", auditFile)
+		return f, nil
+	}
+
+	if db, ok := s.(*postgres.Storage); ok {
+		logger.Info("Using database audit log")
+		return db.AuditLog(), nil
+	}
+
+	logger.Info("Writing audit entries to the log, set an audit file to keep them")
+	return audit.NewLog(logger), nil
+}
+
 // StartBatchDeleteProcessor starts a background processor to handle batch delete requests.
 func StartBatchDeleteProcessor(ctx context.Context, s Storage, input <-chan models.BatchDeleteRequest, logger *logging.Logger) {
 	const (
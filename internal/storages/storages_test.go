@@ -24,7 +24,7 @@ func setupLogger(t *testing.T) *logging.Logger {
 
 func TestInit_InMemory(t *testing.T) {
 	logger := setupLogger(t)
-	store, err := storages.Init("", "", logger)
+	store, err := storages.Init("", "", logger, nil)
 	require.NoError(t, err)
 	assert.NotNil(t, store)
 }
@@ -38,7 +38,7 @@ func TestInit_FileStorage(t *testing.T) {
 		err = os.Remove(tmp.Name())
 		require.NoError(t, err)
 	}()
-	store, err := storages.Init("", tmp.Name(), logger)
+	store, err := storages.Init("", tmp.Name(), logger, nil)
 	require.NoError(t, err)
 	assert.NotNil(t, store)
 
@@ -48,13 +48,13 @@ func TestInit_FileStorage(t *testing.T) {
 
 func TestInit_InvalidFilePath(t *testing.T) {
 	logger := setupLogger(t)
-	_, err := storages.Init("", "/invalid/path/storage.json", logger)
+	_, err := storages.Init("", "/invalid/path/storage.json", logger, nil)
 	require.Error(t, err)
 }
 
 func TestInit_PostgresFails(t *testing.T) {
 	logger := setupLogger(t)
-	_, err := storages.Init("invalid-dsn", "", logger)
+	_, err := storages.Init("invalid-dsn", "", logger, nil)
 	require.Error(t, err)
 }
 
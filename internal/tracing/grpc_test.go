@@ -0,0 +1,68 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	recorder := recordSpans(t)
+	interceptor := UnaryServerInterceptor()
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("traceparent", traceParent))
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/shortugo.URLShortener/ExpandURL"}
+	_, err := interceptor(ctx, nil, info, func(ctx context.Context, _ any) (any, error) {
+		assert.Equal(t, traceID, trace.SpanFromContext(ctx).SpanContext().TraceID().String())
+		return nil, status.Error(codes.NotFound, "URL not found")
+	})
+	require.Error(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "shortugo.URLShortener/ExpandURL", span.Name())
+	assert.Equal(t, trace.SpanKindServer, span.SpanKind())
+	assert.True(t, span.Parent().IsRemote())
+	assert.Contains(t, span.Attributes(), semconv.RPCService("shortugo.URLShortener"))
+	assert.Contains(t, span.Attributes(), semconv.RPCMethod("ExpandURL"))
+	assert.Contains(t, span.Attributes(), semconv.RPCGRPCStatusCodeKey.Int(int(codes.NotFound)))
+	assert.Equal(t, otelcodes.Error, span.Status().Code)
+}
+
+// serverStream is a server stream with a context only.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	recorder := recordSpans(t)
+	interceptor := StreamServerInterceptor()
+
+	info := &grpc.StreamServerInfo{FullMethod: "/shortugo.URLShortener/ShortenStream"}
+	err := interceptor(nil, &serverStream{ctx: context.Background()}, info, func(_ any, ss grpc.ServerStream) error {
+		assert.True(t, trace.SpanFromContext(ss.Context()).SpanContext().IsValid())
+		return nil
+	})
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "shortugo.URLShortener/ShortenStream", spans[0].Name())
+	assert.False(t, spans[0].Parent().IsValid())
+	assert.Equal(t, otelcodes.Unset, spans[0].Status().Code)
+}
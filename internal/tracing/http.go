@@ -0,0 +1,43 @@
+package tracing
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Middleware starts a server span for each request, continuing the trace of its traceparent header.
+// It goes first in the middleware chain so the span covers the other middlewares; Handler names it
+// after the matched route.
+func Middleware(next http.Handler) http.Handler {
+	return otelhttp.NewHandler(next, "HTTP",
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			return "HTTP " + r.Method
+		}),
+	)
+}
+
+// Handler wraps the handler of the matched route in a child span of the request span, so the time spent
+// in the middlewares and in the handler can be told apart. It goes last in the middleware chain.
+// Both spans are named after the route pattern once the request is routed.
+func Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := trace.SpanFromContext(r.Context())
+		ctx, span := tracer().Start(r.Context(), "handler")
+		defer span.End()
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+
+		rctx := chi.RouteContext(ctx)
+		if rctx == nil || rctx.RoutePattern() == "" {
+			return
+		}
+		name := r.Method + " " + rctx.RoutePattern()
+		span.SetName(name)
+		request.SetName(name)
+		request.SetAttributes(semconv.HTTPRoute(rctx.RoutePattern()))
+	})
+}
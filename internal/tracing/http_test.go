@@ -0,0 +1,43 @@
+package tracing
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestMiddleware(t *testing.T) {
+	recorder := recordSpans(t)
+
+	r := chi.NewRouter()
+	r.Use(Middleware)
+	r.Use(Handler)
+	r.Get("/{id}", func(w http.ResponseWriter, r *http.Request) {
+		assert.True(t, trace.SpanFromContext(r.Context()).SpanContext().IsValid())
+		w.WriteHeader(http.StatusTemporaryRedirect)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/abc123", nil)
+	req.Header.Set("traceparent", traceParent)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusTemporaryRedirect, w.Code)
+
+	// The handler span ends first, inside the request span continuing the caller's trace
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	handler, request := spans[0], spans[1]
+	assert.Equal(t, "GET /{id}", handler.Name())
+	assert.Equal(t, "GET /{id}", request.Name())
+	assert.Equal(t, request.SpanContext().SpanID(), handler.Parent().SpanID())
+	assert.Equal(t, traceID, request.SpanContext().TraceID().String())
+	assert.True(t, request.Parent().IsRemote())
+	assert.Equal(t, trace.SpanKindServer, request.SpanKind())
+	assert.Contains(t, request.Attributes(), semconv.HTTPRoute("/{id}"))
+}
@@ -0,0 +1,239 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+
+	"github.com/apetsko/shortugo/internal/models"
+	"github.com/apetsko/shortugo/internal/server/http/handlers"
+	"github.com/apetsko/shortugo/internal/storages/shared"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Storage is a handlers.Storage recording a span for each call of the storage it wraps.
+// Missing and gone links are expected answers, not failures: they are recorded as the
+// storage.result attribute and leave the span status unset.
+type Storage struct {
+	next handlers.Storage
+}
+
+// NewStorage wraps s in spans.
+func NewStorage(s handlers.Storage) *Storage {
+	return &Storage{next: s}
+}
+
+// start starts the span of the storage operation op.
+func (s *Storage) start(ctx context.Context, op string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer().Start(ctx, "storage."+op,
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+// end records the outcome err of an operation on span and ends it.
+func end(span trace.Span, err error) {
+	switch {
+	case err == nil:
+	case errors.Is(err, shared.ErrNotFound):
+		span.SetAttributes(attribute.String("storage.result", "not_found"))
+	case errors.Is(err, shared.ErrGone):
+		span.SetAttributes(attribute.String("storage.result", "gone"))
+	default:
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// linkID is the attribute of the link a storage operation works on.
+func linkID(id string) attribute.KeyValue {
+	return attribute.String("link.id", id)
+}
+
+// Put stores a single URL record.
+func (s *Storage) Put(ctx context.Context, r models.URLRecord) error {
+	ctx, span := s.start(ctx, "Put", linkID(r.ID))
+	err := s.next.Put(ctx, r)
+	end(span, err)
+	return err
+}
+
+// PutBatch stores a batch of URL records.
+func (s *Storage) PutBatch(ctx context.Context, rr []models.URLRecord) error {
+	ctx, span := s.start(ctx, "PutBatch", attribute.Int("links.count", len(rr)))
+	err := s.next.PutBatch(ctx, rr)
+	end(span, err)
+	return err
+}
+
+// Get retrieves a URL by its ID.
+func (s *Storage) Get(ctx context.Context, id string) (string, error) {
+	ctx, span := s.start(ctx, "Get", linkID(id))
+	url, err := s.next.Get(ctx, id)
+	end(span, err)
+	return url, err
+}
+
+// GetRecord retrieves the full URL record by its ID.
+func (s *Storage) GetRecord(ctx context.Context, id string) (*models.URLRecord, error) {
+	ctx, span := s.start(ctx, "GetRecord", linkID(id))
+	r, err := s.next.GetRecord(ctx, id)
+	end(span, err)
+	return r, err
+}
+
+// LookupRecord retrieves the URL record by its ID whatever its state.
+func (s *Storage) LookupRecord(ctx context.Context, id string) (*models.URLRecord, error) {
+	ctx, span := s.start(ctx, "LookupRecord", linkID(id))
+	r, err := s.next.LookupRecord(ctx, id)
+	end(span, err)
+	return r, err
+}
+
+// ListLinksByUserID lists all URLs associated with a user ID.
+func (s *Storage) ListLinksByUserID(ctx context.Context, baseURL, userID string) ([]models.URLRecord, error) {
+	ctx, span := s.start(ctx, "ListLinksByUserID")
+	rr, err := s.next.ListLinksByUserID(ctx, baseURL, userID)
+	end(span, err)
+	return rr, err
+}
+
+// ForEachLinkByUserID walks the user's URLs one record at a time.
+func (s *Storage) ForEachLinkByUserID(ctx context.Context, baseURL, userID string, fn func(r models.URLRecord) error) error {
+	ctx, span := s.start(ctx, "ForEachLinkByUserID")
+	err := s.next.ForEachLinkByUserID(ctx, baseURL, userID, fn)
+	end(span, err)
+	return err
+}
+
+// ListLinksByFilter lists the user's URLs matching filter.
+func (s *Storage) ListLinksByFilter(ctx context.Context, baseURL, userID string, filter models.LinkFilter) ([]models.URLRecord, error) {
+	ctx, span := s.start(ctx, "ListLinksByFilter")
+	rr, err := s.next.ListLinksByFilter(ctx, baseURL, userID, filter)
+	end(span, err)
+	return rr, err
+}
+
+// SearchLinks returns the user's URLs matching query.
+func (s *Storage) SearchLinks(ctx context.Context, baseURL, userID, query string) ([]models.URLRecord, error) {
+	ctx, span := s.start(ctx, "SearchLinks")
+	rr, err := s.next.SearchLinks(ctx, baseURL, userID, query)
+	end(span, err)
+	return rr, err
+}
+
+// DeleteUserURLs deletes URLs associated with a user ID.
+func (s *Storage) DeleteUserURLs(ctx context.Context, IDs []string, userID string) error {
+	ctx, span := s.start(ctx, "DeleteUserURLs", attribute.Int("links.count", len(IDs)))
+	err := s.next.DeleteUserURLs(ctx, IDs, userID)
+	end(span, err)
+	return err
+}
+
+// PurgeDeleted removes the deleted links for good.
+func (s *Storage) PurgeDeleted(ctx context.Context) (int64, error) {
+	ctx, span := s.start(ctx, "PurgeDeleted")
+	n, err := s.next.PurgeDeleted(ctx)
+	end(span, err)
+	return n, err
+}
+
+// UpdateLinkOptions changes the options of a link owned by userID through update.
+func (s *Storage) UpdateLinkOptions(ctx context.Context, id, userID string, update func(o *models.LinkOptions) error) error {
+	ctx, span := s.start(ctx, "UpdateLinkOptions", linkID(id))
+	err := s.next.UpdateLinkOptions(ctx, id, userID, update)
+	end(span, err)
+	return err
+}
+
+// AddLinkTags adds normalized tags to a link owned by userID.
+func (s *Storage) AddLinkTags(ctx context.Context, id, userID string, tags []string) error {
+	ctx, span := s.start(ctx, "AddLinkTags", linkID(id))
+	err := s.next.AddLinkTags(ctx, id, userID, tags)
+	end(span, err)
+	return err
+}
+
+// RemoveLinkTags removes normalized tags from a link owned by userID.
+func (s *Storage) RemoveLinkTags(ctx context.Context, id, userID string, tags []string) error {
+	ctx, span := s.start(ctx, "RemoveLinkTags", linkID(id))
+	err := s.next.RemoveLinkTags(ctx, id, userID, tags)
+	end(span, err)
+	return err
+}
+
+// SetLinkFolder files a link owned by userID in folder.
+func (s *Storage) SetLinkFolder(ctx context.Context, id, userID, folder string) error {
+	ctx, span := s.start(ctx, "SetLinkFolder", linkID(id))
+	err := s.next.SetLinkFolder(ctx, id, userID, folder)
+	end(span, err)
+	return err
+}
+
+// SetLinkDisabled disables or re-enables a link whoever owns it.
+func (s *Storage) SetLinkDisabled(ctx context.Context, id string, disabled bool) error {
+	ctx, span := s.start(ctx, "SetLinkDisabled", linkID(id))
+	err := s.next.SetLinkDisabled(ctx, id, disabled)
+	end(span, err)
+	return err
+}
+
+// ConsumeClick takes one of the remaining redirects of a link limited by MaxClicks.
+func (s *Storage) ConsumeClick(ctx context.Context, id string) error {
+	ctx, span := s.start(ctx, "ConsumeClick", linkID(id))
+	err := s.next.ConsumeClick(ctx, id)
+	end(span, err)
+	return err
+}
+
+// CountVariantClick adds one redirect to the click counter of an A/B variant of a link.
+func (s *Storage) CountVariantClick(ctx context.Context, id, variant string) error {
+	ctx, span := s.start(ctx, "CountVariantClick", linkID(id))
+	err := s.next.CountVariantClick(ctx, id, variant)
+	end(span, err)
+	return err
+}
+
+// VariantClicks returns the click counters of the A/B variants of a link.
+func (s *Storage) VariantClicks(ctx context.Context, id string) (map[string]int64, error) {
+	ctx, span := s.start(ctx, "VariantClicks", linkID(id))
+	clicks, err := s.next.VariantClicks(ctx, id)
+	end(span, err)
+	return clicks, err
+}
+
+// GetUTMTemplate retrieves the default UTM tags of a user.
+func (s *Storage) GetUTMTemplate(ctx context.Context, userID string) (*models.UTM, error) {
+	ctx, span := s.start(ctx, "GetUTMTemplate")
+	t, err := s.next.GetUTMTemplate(ctx, userID)
+	end(span, err)
+	return t, err
+}
+
+// PutUTMTemplate replaces the default UTM tags of a user.
+func (s *Storage) PutUTMTemplate(ctx context.Context, userID string, t models.UTM) error {
+	ctx, span := s.start(ctx, "PutUTMTemplate")
+	err := s.next.PutUTMTemplate(ctx, userID, t)
+	end(span, err)
+	return err
+}
+
+// Stats retrieves counts of url and users.
+func (s *Storage) Stats(ctx context.Context) (*models.Stats, error) {
+	ctx, span := s.start(ctx, "Stats")
+	stats, err := s.next.Stats(ctx)
+	end(span, err)
+	return stats, err
+}
+
+// Ping checks the connection to the storage. It takes no context, so it is not traced.
+func (s *Storage) Ping() error {
+	return s.next.Ping()
+}
+
+// Close closes the connection to the storage.
+func (s *Storage) Close() error {
+	return s.next.Close()
+}
@@ -0,0 +1,61 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/apetsko/shortugo/internal/mocks"
+	"github.com/apetsko/shortugo/internal/models"
+	"github.com/apetsko/shortugo/internal/storages/inmem"
+	"github.com/apetsko/shortugo/internal/storages/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+func TestStorage(t *testing.T) {
+	recorder := recordSpans(t)
+	ctx, parent := tracer().Start(context.Background(), "request")
+	s := NewStorage(inmem.New())
+
+	require.NoError(t, s.Put(ctx, models.URLRecord{ID: "abc123", URL: "https://example.com", UserID: "user1"}))
+	url, err := s.Get(ctx, "abc123")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com", url)
+	_, err = s.Get(ctx, "unknown")
+	require.ErrorIs(t, err, shared.ErrNotFound)
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 4)
+	assert.Equal(t, "storage.Put", spans[0].Name())
+	assert.Equal(t, "storage.Get", spans[1].Name())
+	assert.Contains(t, spans[1].Attributes(), attribute.String("link.id", "abc123"))
+	for _, span := range spans[:3] {
+		assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+	}
+
+	// A missing link is an answer, not a failure
+	assert.Contains(t, spans[2].Attributes(), attribute.String("storage.result", "not_found"))
+	assert.Equal(t, codes.Unset, spans[2].Status().Code)
+}
+
+func TestStorage_Error(t *testing.T) {
+	recorder := recordSpans(t)
+	mockStorage := new(mocks.Storage)
+	mockStorage.On("Stats", mock.Anything).Return(nil, errors.New("database error"))
+
+	_, err := NewStorage(mockStorage).Stats(context.Background())
+	require.Error(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "storage.Stats", spans[0].Name())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, "database error", spans[0].Status().Description)
+	require.Len(t, spans[0].Events(), 1)
+	assert.Equal(t, "exception", spans[0].Events()[0].Name)
+}
@@ -0,0 +1,117 @@
+// Package tracing sets up OpenTelemetry tracing and instruments the HTTP router, the gRPC server and the storage.
+// Trace context is propagated in the W3C traceparent and tracestate headers and metadata; spans are exported
+// over OTLP/HTTP to a collector, or written as JSON to stdout or a file for local debugging.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Exporters spans can be sent to.
+const (
+	ExporterNone   = ""       // Spans are not recorded; trace context is still propagated.
+	ExporterOTLP   = "otlp"   // OTLP/HTTP to a collector.
+	ExporterStdout = "stdout" // JSON to the standard output.
+	ExporterFile   = "file"   // JSON to a file.
+)
+
+// instrumentation is the name the tracers of the package are created with.
+const instrumentation = "github.com/apetsko/shortugo/internal/tracing"
+
+// tracer creates the spans of the package through the global tracer provider.
+func tracer() trace.Tracer {
+	return otel.Tracer(instrumentation)
+}
+
+// Options configure the tracer provider.
+type Options struct {
+	Exporter    string  // One of the Exporter constants.
+	Endpoint    string  // URL of the OTLP/HTTP collector; empty uses OTEL_EXPORTER_OTLP_ENDPOINT or localhost:4318.
+	File        string  // File the spans are appended to with ExporterFile.
+	SampleRatio float64 // Share of the traces started here that are recorded, from 0 to 1.
+	Service     string  // Service name of the spans.
+	Version     string  // Service version of the spans.
+}
+
+// Provider is the tracer provider installed by Setup.
+type Provider struct {
+	provider *sdktrace.TracerProvider
+	file     io.Closer
+}
+
+// Setup installs the W3C trace context propagator and, unless the exporter is ExporterNone,
+// a global tracer provider exporting spans as configured by opts.
+// The provider must be shut down to flush the spans still buffered.
+func Setup(ctx context.Context, opts Options) (*Provider, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	p := new(Provider)
+	var (
+		exporter sdktrace.SpanExporter
+		err      error
+	)
+	switch opts.Exporter {
+	case ExporterNone:
+		return p, nil
+	case ExporterOTLP:
+		var options []otlptracehttp.Option
+		if opts.Endpoint != "" {
+			options = append(options, otlptracehttp.WithEndpointURL(opts.Endpoint))
+		}
+		exporter, err = otlptracehttp.New(ctx, options...)
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case ExporterFile:
+		var f *os.File
+		if f, err = os.OpenFile(opts.File, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600); err != nil {
+			return nil, fmt.Errorf("failed to open trace file: %w", err)
+		}
+		p.file = f
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(f))
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", opts.Exporter)
+	}
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to create trace exporter: %w", err), p.closeFile())
+	}
+
+	p.provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(
+			semconv.ServiceName(opts.Service),
+			semconv.ServiceVersion(opts.Version),
+		)),
+	)
+	otel.SetTracerProvider(p.provider)
+	return p, nil
+}
+
+// Shutdown exports the buffered spans and stops the provider.
+func (p *Provider) Shutdown(ctx context.Context) error {
+	if p.provider == nil {
+		return nil
+	}
+	return errors.Join(p.provider.Shutdown(ctx), p.closeFile())
+}
+
+// closeFile closes the file of ExporterFile, if any.
+func (p *Provider) closeFile() error {
+	if p.file == nil {
+		return nil
+	}
+	return p.file.Close()
+}
@@ -0,0 +1,79 @@
+package tracing
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// traceParent is a W3C traceparent of a sampled remote span.
+const (
+	traceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	traceID     = "4bf92f3577b34da6a3ce929d0e0e4736"
+)
+
+// recordSpans installs a tracer provider keeping the ended spans in the returned recorder
+// and the W3C propagator, restoring the global ones when the test ends.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+
+	provider, propagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	t.Cleanup(func() {
+		otel.SetTracerProvider(provider)
+		otel.SetTextMapPropagator(propagator)
+	})
+
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return recorder
+}
+
+func TestSetup(t *testing.T) {
+	provider, propagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	t.Cleanup(func() {
+		otel.SetTracerProvider(provider)
+		otel.SetTextMapPropagator(propagator)
+	})
+	ctx := context.Background()
+
+	t.Run("file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "traces.jsonl")
+		p, err := Setup(ctx, Options{Exporter: ExporterFile, File: path, SampleRatio: 1, Service: "shortugo"})
+		require.NoError(t, err)
+
+		_, span := tracer().Start(ctx, "test span")
+		span.End()
+		require.NoError(t, p.Shutdown(ctx))
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"Name":"test span"`)
+		assert.Contains(t, string(data), "shortugo")
+	})
+
+	t.Run("none", func(t *testing.T) {
+		p, err := Setup(ctx, Options{})
+		require.NoError(t, err)
+		assert.NoError(t, p.Shutdown(ctx))
+		assert.Contains(t, otel.GetTextMapPropagator().Fields(), "traceparent")
+	})
+
+	t.Run("unknown exporter", func(t *testing.T) {
+		_, err := Setup(ctx, Options{Exporter: "zipkin"})
+		assert.Error(t, err)
+	})
+
+	t.Run("file cannot be opened", func(t *testing.T) {
+		_, err := Setup(ctx, Options{Exporter: ExporterFile, File: filepath.Join(t.TempDir(), "missing", "traces.jsonl")})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,8 @@
+package proto
+
+import _ "embed"
+
+// OpenAPI is the OpenAPI v2 document describing the REST routes of the gRPC gateway.
+//
+//go:embed shortugo.swagger.json
+var OpenAPI []byte
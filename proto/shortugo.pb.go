@@ -12,6 +12,7 @@ import (
 	reflect "reflect"
 	unsafe "unsafe"
 
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	_ "google.golang.org/protobuf/types/gofeaturespb"
@@ -29,6 +30,10 @@ type URLPair struct {
 	CorrelationId *string                `protobuf:"bytes,1,opt,name=correlation_id,json=correlationId" json:"correlation_id,omitempty"`
 	OriginalUrl   *string                `protobuf:"bytes,2,opt,name=original_url,json=originalUrl" json:"original_url,omitempty"`
 	ShortUrl      *string                `protobuf:"bytes,3,opt,name=short_url,json=shortUrl" json:"short_url,omitempty"`
+	Options       *LinkOptions           `protobuf:"bytes,4,opt,name=options" json:"options,omitempty"` // only used when shortening
+	Tags          []string               `protobuf:"bytes,5,rep,name=tags" json:"tags,omitempty"`       // only set when listing
+	Folder        *string                `protobuf:"bytes,6,opt,name=folder" json:"folder,omitempty"`   // only set when listing
+	Title         *string                `protobuf:"bytes,7,opt,name=title" json:"title,omitempty"`     // only set when listing
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -79,6 +84,34 @@ func (x *URLPair) GetShortUrl() string {
 	return ""
 }
 
+func (x *URLPair) GetOptions() *LinkOptions {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *URLPair) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *URLPair) GetFolder() string {
+	if x != nil && x.Folder != nil {
+		return *x.Folder
+	}
+	return ""
+}
+
+func (x *URLPair) GetTitle() string {
+	if x != nil && x.Title != nil {
+		return *x.Title
+	}
+	return ""
+}
+
 func (x *URLPair) SetCorrelationId(v string) {
 	x.CorrelationId = &v
 }
@@ -91,6 +124,22 @@ func (x *URLPair) SetShortUrl(v string) {
 	x.ShortUrl = &v
 }
 
+func (x *URLPair) SetOptions(v *LinkOptions) {
+	x.Options = v
+}
+
+func (x *URLPair) SetTags(v []string) {
+	x.Tags = v
+}
+
+func (x *URLPair) SetFolder(v string) {
+	x.Folder = &v
+}
+
+func (x *URLPair) SetTitle(v string) {
+	x.Title = &v
+}
+
 func (x *URLPair) HasCorrelationId() bool {
 	if x == nil {
 		return false
@@ -112,6 +161,27 @@ func (x *URLPair) HasShortUrl() bool {
 	return x.ShortUrl != nil
 }
 
+func (x *URLPair) HasOptions() bool {
+	if x == nil {
+		return false
+	}
+	return x.Options != nil
+}
+
+func (x *URLPair) HasFolder() bool {
+	if x == nil {
+		return false
+	}
+	return x.Folder != nil
+}
+
+func (x *URLPair) HasTitle() bool {
+	if x == nil {
+		return false
+	}
+	return x.Title != nil
+}
+
 func (x *URLPair) ClearCorrelationId() {
 	x.CorrelationId = nil
 }
@@ -124,12 +194,28 @@ func (x *URLPair) ClearShortUrl() {
 	x.ShortUrl = nil
 }
 
+func (x *URLPair) ClearOptions() {
+	x.Options = nil
+}
+
+func (x *URLPair) ClearFolder() {
+	x.Folder = nil
+}
+
+func (x *URLPair) ClearTitle() {
+	x.Title = nil
+}
+
 type URLPair_builder struct {
 	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
 
 	CorrelationId *string
 	OriginalUrl   *string
 	ShortUrl      *string
+	Options       *LinkOptions
+	Tags          []string
+	Folder        *string
+	Title         *string
 }
 
 func (b0 URLPair_builder) Build() *URLPair {
@@ -139,31 +225,44 @@ func (b0 URLPair_builder) Build() *URLPair {
 	x.CorrelationId = b.CorrelationId
 	x.OriginalUrl = b.OriginalUrl
 	x.ShortUrl = b.ShortUrl
+	x.Options = b.Options
+	x.Tags = b.Tags
+	x.Folder = b.Folder
+	x.Title = b.Title
 	return m0
 }
 
-type ShortenRequest struct {
+// Per-link settings chosen when the link is created; unset fields use the server defaults.
+type LinkOptions struct {
 	state         protoimpl.MessageState `protogen:"hybrid.v1"`
-	OriginalUrl   *string                `protobuf:"bytes,1,opt,name=original_url,json=originalUrl" json:"original_url,omitempty"`
-	UserId        *string                `protobuf:"bytes,2,opt,name=user_id,json=userId" json:"user_id,omitempty"`
+	RedirectType  *int32                 `protobuf:"varint,1,opt,name=redirect_type,json=redirectType" json:"redirect_type,omitempty"` // 301, 302, 307 or 308
+	RedirectMode  *string                `protobuf:"bytes,2,opt,name=redirect_mode,json=redirectMode" json:"redirect_mode,omitempty"`  // header or html
+	QueryPolicy   *string                `protobuf:"bytes,3,opt,name=query_policy,json=queryPolicy" json:"query_policy,omitempty"`     // destination, request or append; empty drops the request query
+	ForwardPath   *bool                  `protobuf:"varint,4,opt,name=forward_path,json=forwardPath" json:"forward_path,omitempty"`    // append the path after /{id}/ to the destination
+	Utm           *UTM                   `protobuf:"bytes,5,opt,name=utm" json:"utm,omitempty"`                                        // campaign tags; unset tags are taken from the user's template
+	Password      *string                `protobuf:"bytes,6,opt,name=password" json:"password,omitempty"`                              // write-only; visitors have to enter it before the redirect
+	MaxClicks     *int32                 `protobuf:"varint,7,opt,name=max_clicks,json=maxClicks" json:"max_clicks,omitempty"`          // the link is gone after this many redirects; 0 means unlimited
+	Rules         []*RoutingRule         `protobuf:"bytes,8,rep,name=rules" json:"rules,omitempty"`                                    // conditional destinations, tried in order before the link URL
+	Variants      []*Variant             `protobuf:"bytes,9,rep,name=variants" json:"variants,omitempty"`                              // A/B destinations for visitors no rule matches
+	Interstitial  *bool                  `protobuf:"varint,10,opt,name=interstitial" json:"interstitial,omitempty"`                    // always show the preview page before redirecting
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ShortenRequest) Reset() {
-	*x = ShortenRequest{}
+func (x *LinkOptions) Reset() {
+	*x = LinkOptions{}
 	mi := &file_proto_shortugo_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ShortenRequest) String() string {
+func (x *LinkOptions) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ShortenRequest) ProtoMessage() {}
+func (*LinkOptions) ProtoMessage() {}
 
-func (x *ShortenRequest) ProtoReflect() protoreflect.Message {
+func (x *LinkOptions) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_shortugo_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -175,87 +274,261 @@ func (x *ShortenRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-func (x *ShortenRequest) GetOriginalUrl() string {
-	if x != nil && x.OriginalUrl != nil {
-		return *x.OriginalUrl
+func (x *LinkOptions) GetRedirectType() int32 {
+	if x != nil && x.RedirectType != nil {
+		return *x.RedirectType
+	}
+	return 0
+}
+
+func (x *LinkOptions) GetRedirectMode() string {
+	if x != nil && x.RedirectMode != nil {
+		return *x.RedirectMode
 	}
 	return ""
 }
 
-func (x *ShortenRequest) GetUserId() string {
-	if x != nil && x.UserId != nil {
-		return *x.UserId
+func (x *LinkOptions) GetQueryPolicy() string {
+	if x != nil && x.QueryPolicy != nil {
+		return *x.QueryPolicy
 	}
 	return ""
 }
 
-func (x *ShortenRequest) SetOriginalUrl(v string) {
-	x.OriginalUrl = &v
+func (x *LinkOptions) GetForwardPath() bool {
+	if x != nil && x.ForwardPath != nil {
+		return *x.ForwardPath
+	}
+	return false
 }
 
-func (x *ShortenRequest) SetUserId(v string) {
-	x.UserId = &v
+func (x *LinkOptions) GetUtm() *UTM {
+	if x != nil {
+		return x.Utm
+	}
+	return nil
 }
 
-func (x *ShortenRequest) HasOriginalUrl() bool {
+func (x *LinkOptions) GetPassword() string {
+	if x != nil && x.Password != nil {
+		return *x.Password
+	}
+	return ""
+}
+
+func (x *LinkOptions) GetMaxClicks() int32 {
+	if x != nil && x.MaxClicks != nil {
+		return *x.MaxClicks
+	}
+	return 0
+}
+
+func (x *LinkOptions) GetRules() []*RoutingRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+func (x *LinkOptions) GetVariants() []*Variant {
+	if x != nil {
+		return x.Variants
+	}
+	return nil
+}
+
+func (x *LinkOptions) GetInterstitial() bool {
+	if x != nil && x.Interstitial != nil {
+		return *x.Interstitial
+	}
+	return false
+}
+
+func (x *LinkOptions) SetRedirectType(v int32) {
+	x.RedirectType = &v
+}
+
+func (x *LinkOptions) SetRedirectMode(v string) {
+	x.RedirectMode = &v
+}
+
+func (x *LinkOptions) SetQueryPolicy(v string) {
+	x.QueryPolicy = &v
+}
+
+func (x *LinkOptions) SetForwardPath(v bool) {
+	x.ForwardPath = &v
+}
+
+func (x *LinkOptions) SetUtm(v *UTM) {
+	x.Utm = v
+}
+
+func (x *LinkOptions) SetPassword(v string) {
+	x.Password = &v
+}
+
+func (x *LinkOptions) SetMaxClicks(v int32) {
+	x.MaxClicks = &v
+}
+
+func (x *LinkOptions) SetRules(v []*RoutingRule) {
+	x.Rules = v
+}
+
+func (x *LinkOptions) SetVariants(v []*Variant) {
+	x.Variants = v
+}
+
+func (x *LinkOptions) SetInterstitial(v bool) {
+	x.Interstitial = &v
+}
+
+func (x *LinkOptions) HasRedirectType() bool {
 	if x == nil {
 		return false
 	}
-	return x.OriginalUrl != nil
+	return x.RedirectType != nil
 }
 
-func (x *ShortenRequest) HasUserId() bool {
+func (x *LinkOptions) HasRedirectMode() bool {
 	if x == nil {
 		return false
 	}
-	return x.UserId != nil
+	return x.RedirectMode != nil
 }
 
-func (x *ShortenRequest) ClearOriginalUrl() {
-	x.OriginalUrl = nil
+func (x *LinkOptions) HasQueryPolicy() bool {
+	if x == nil {
+		return false
+	}
+	return x.QueryPolicy != nil
 }
 
-func (x *ShortenRequest) ClearUserId() {
-	x.UserId = nil
+func (x *LinkOptions) HasForwardPath() bool {
+	if x == nil {
+		return false
+	}
+	return x.ForwardPath != nil
 }
 
-type ShortenRequest_builder struct {
+func (x *LinkOptions) HasUtm() bool {
+	if x == nil {
+		return false
+	}
+	return x.Utm != nil
+}
+
+func (x *LinkOptions) HasPassword() bool {
+	if x == nil {
+		return false
+	}
+	return x.Password != nil
+}
+
+func (x *LinkOptions) HasMaxClicks() bool {
+	if x == nil {
+		return false
+	}
+	return x.MaxClicks != nil
+}
+
+func (x *LinkOptions) HasInterstitial() bool {
+	if x == nil {
+		return false
+	}
+	return x.Interstitial != nil
+}
+
+func (x *LinkOptions) ClearRedirectType() {
+	x.RedirectType = nil
+}
+
+func (x *LinkOptions) ClearRedirectMode() {
+	x.RedirectMode = nil
+}
+
+func (x *LinkOptions) ClearQueryPolicy() {
+	x.QueryPolicy = nil
+}
+
+func (x *LinkOptions) ClearForwardPath() {
+	x.ForwardPath = nil
+}
+
+func (x *LinkOptions) ClearUtm() {
+	x.Utm = nil
+}
+
+func (x *LinkOptions) ClearPassword() {
+	x.Password = nil
+}
+
+func (x *LinkOptions) ClearMaxClicks() {
+	x.MaxClicks = nil
+}
+
+func (x *LinkOptions) ClearInterstitial() {
+	x.Interstitial = nil
+}
+
+type LinkOptions_builder struct {
 	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
 
-	OriginalUrl *string
-	UserId      *string
+	RedirectType *int32
+	RedirectMode *string
+	QueryPolicy  *string
+	ForwardPath  *bool
+	Utm          *UTM
+	Password     *string
+	MaxClicks    *int32
+	Rules        []*RoutingRule
+	Variants     []*Variant
+	Interstitial *bool
 }
 
-func (b0 ShortenRequest_builder) Build() *ShortenRequest {
-	m0 := &ShortenRequest{}
+func (b0 LinkOptions_builder) Build() *LinkOptions {
+	m0 := &LinkOptions{}
 	b, x := &b0, m0
 	_, _ = b, x
-	x.OriginalUrl = b.OriginalUrl
-	x.UserId = b.UserId
+	x.RedirectType = b.RedirectType
+	x.RedirectMode = b.RedirectMode
+	x.QueryPolicy = b.QueryPolicy
+	x.ForwardPath = b.ForwardPath
+	x.Utm = b.Utm
+	x.Password = b.Password
+	x.MaxClicks = b.MaxClicks
+	x.Rules = b.Rules
+	x.Variants = b.Variants
+	x.Interstitial = b.Interstitial
 	return m0
 }
 
-type ShortenResponse struct {
+// A destination of an A/B test; visitors are split by weight and keep their variant.
+type Variant struct {
 	state         protoimpl.MessageState `protogen:"hybrid.v1"`
-	ShortUrl      *string                `protobuf:"bytes,1,opt,name=short_url,json=shortUrl" json:"short_url,omitempty"`
+	Name          *string                `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Url           *string                `protobuf:"bytes,2,opt,name=url" json:"url,omitempty"`
+	Weight        *int32                 `protobuf:"varint,3,opt,name=weight" json:"weight,omitempty"` // 1 to 1000
+	Clicks        *int64                 `protobuf:"varint,4,opt,name=clicks" json:"clicks,omitempty"` // output only
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ShortenResponse) Reset() {
-	*x = ShortenResponse{}
+func (x *Variant) Reset() {
+	*x = Variant{}
 	mi := &file_proto_shortugo_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ShortenResponse) String() string {
+func (x *Variant) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ShortenResponse) ProtoMessage() {}
+func (*Variant) ProtoMessage() {}
 
-func (x *ShortenResponse) ProtoReflect() protoreflect.Message {
+func (x *Variant) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_shortugo_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -267,63 +540,139 @@ func (x *ShortenResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-func (x *ShortenResponse) GetShortUrl() string {
-	if x != nil && x.ShortUrl != nil {
-		return *x.ShortUrl
+func (x *Variant) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
 	}
 	return ""
 }
 
-func (x *ShortenResponse) SetShortUrl(v string) {
-	x.ShortUrl = &v
+func (x *Variant) GetUrl() string {
+	if x != nil && x.Url != nil {
+		return *x.Url
+	}
+	return ""
 }
 
-func (x *ShortenResponse) HasShortUrl() bool {
+func (x *Variant) GetWeight() int32 {
+	if x != nil && x.Weight != nil {
+		return *x.Weight
+	}
+	return 0
+}
+
+func (x *Variant) GetClicks() int64 {
+	if x != nil && x.Clicks != nil {
+		return *x.Clicks
+	}
+	return 0
+}
+
+func (x *Variant) SetName(v string) {
+	x.Name = &v
+}
+
+func (x *Variant) SetUrl(v string) {
+	x.Url = &v
+}
+
+func (x *Variant) SetWeight(v int32) {
+	x.Weight = &v
+}
+
+func (x *Variant) SetClicks(v int64) {
+	x.Clicks = &v
+}
+
+func (x *Variant) HasName() bool {
 	if x == nil {
 		return false
 	}
-	return x.ShortUrl != nil
+	return x.Name != nil
 }
 
-func (x *ShortenResponse) ClearShortUrl() {
-	x.ShortUrl = nil
+func (x *Variant) HasUrl() bool {
+	if x == nil {
+		return false
+	}
+	return x.Url != nil
 }
 
-type ShortenResponse_builder struct {
+func (x *Variant) HasWeight() bool {
+	if x == nil {
+		return false
+	}
+	return x.Weight != nil
+}
+
+func (x *Variant) HasClicks() bool {
+	if x == nil {
+		return false
+	}
+	return x.Clicks != nil
+}
+
+func (x *Variant) ClearName() {
+	x.Name = nil
+}
+
+func (x *Variant) ClearUrl() {
+	x.Url = nil
+}
+
+func (x *Variant) ClearWeight() {
+	x.Weight = nil
+}
+
+func (x *Variant) ClearClicks() {
+	x.Clicks = nil
+}
+
+type Variant_builder struct {
 	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
 
-	ShortUrl *string
+	Name   *string
+	Url    *string
+	Weight *int32
+	Clicks *int64
 }
 
-func (b0 ShortenResponse_builder) Build() *ShortenResponse {
-	m0 := &ShortenResponse{}
+func (b0 Variant_builder) Build() *Variant {
+	m0 := &Variant{}
 	b, x := &b0, m0
 	_, _ = b, x
-	x.ShortUrl = b.ShortUrl
+	x.Name = b.Name
+	x.Url = b.Url
+	x.Weight = b.Weight
+	x.Clicks = b.Clicks
 	return m0
 }
 
-type ExpandRequest struct {
+// Sends visitors matching all set conditions to url.
+type RoutingRule struct {
 	state         protoimpl.MessageState `protogen:"hybrid.v1"`
-	ShortUrlId    *string                `protobuf:"bytes,1,opt,name=short_url_id,json=shortUrlId" json:"short_url_id,omitempty"`
+	Platform      *string                `protobuf:"bytes,1,opt,name=platform" json:"platform,omitempty"` // ios, android, windows, macos, linux, mobile or desktop
+	Language      *string                `protobuf:"bytes,2,opt,name=language" json:"language,omitempty"` // BCP 47 tag of the preferred language; "de" also matches "de-AT"
+	Country       *string                `protobuf:"bytes,3,opt,name=country" json:"country,omitempty"`   // ISO 3166-1 alpha-2 code from the GeoIP database
+	Url           *string                `protobuf:"bytes,4,opt,name=url" json:"url,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ExpandRequest) Reset() {
-	*x = ExpandRequest{}
+func (x *RoutingRule) Reset() {
+	*x = RoutingRule{}
 	mi := &file_proto_shortugo_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ExpandRequest) String() string {
+func (x *RoutingRule) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ExpandRequest) ProtoMessage() {}
+func (*RoutingRule) ProtoMessage() {}
 
-func (x *ExpandRequest) ProtoReflect() protoreflect.Message {
+func (x *RoutingRule) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_shortugo_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -335,63 +684,140 @@ func (x *ExpandRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-func (x *ExpandRequest) GetShortUrlId() string {
-	if x != nil && x.ShortUrlId != nil {
-		return *x.ShortUrlId
+func (x *RoutingRule) GetPlatform() string {
+	if x != nil && x.Platform != nil {
+		return *x.Platform
 	}
 	return ""
 }
 
-func (x *ExpandRequest) SetShortUrlId(v string) {
-	x.ShortUrlId = &v
+func (x *RoutingRule) GetLanguage() string {
+	if x != nil && x.Language != nil {
+		return *x.Language
+	}
+	return ""
 }
 
-func (x *ExpandRequest) HasShortUrlId() bool {
-	if x == nil {
-		return false
+func (x *RoutingRule) GetCountry() string {
+	if x != nil && x.Country != nil {
+		return *x.Country
 	}
-	return x.ShortUrlId != nil
+	return ""
 }
 
-func (x *ExpandRequest) ClearShortUrlId() {
-	x.ShortUrlId = nil
+func (x *RoutingRule) GetUrl() string {
+	if x != nil && x.Url != nil {
+		return *x.Url
+	}
+	return ""
 }
 
-type ExpandRequest_builder struct {
+func (x *RoutingRule) SetPlatform(v string) {
+	x.Platform = &v
+}
+
+func (x *RoutingRule) SetLanguage(v string) {
+	x.Language = &v
+}
+
+func (x *RoutingRule) SetCountry(v string) {
+	x.Country = &v
+}
+
+func (x *RoutingRule) SetUrl(v string) {
+	x.Url = &v
+}
+
+func (x *RoutingRule) HasPlatform() bool {
+	if x == nil {
+		return false
+	}
+	return x.Platform != nil
+}
+
+func (x *RoutingRule) HasLanguage() bool {
+	if x == nil {
+		return false
+	}
+	return x.Language != nil
+}
+
+func (x *RoutingRule) HasCountry() bool {
+	if x == nil {
+		return false
+	}
+	return x.Country != nil
+}
+
+func (x *RoutingRule) HasUrl() bool {
+	if x == nil {
+		return false
+	}
+	return x.Url != nil
+}
+
+func (x *RoutingRule) ClearPlatform() {
+	x.Platform = nil
+}
+
+func (x *RoutingRule) ClearLanguage() {
+	x.Language = nil
+}
+
+func (x *RoutingRule) ClearCountry() {
+	x.Country = nil
+}
+
+func (x *RoutingRule) ClearUrl() {
+	x.Url = nil
+}
+
+type RoutingRule_builder struct {
 	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
 
-	ShortUrlId *string
+	Platform *string
+	Language *string
+	Country  *string
+	Url      *string
 }
 
-func (b0 ExpandRequest_builder) Build() *ExpandRequest {
-	m0 := &ExpandRequest{}
+func (b0 RoutingRule_builder) Build() *RoutingRule {
+	m0 := &RoutingRule{}
 	b, x := &b0, m0
 	_, _ = b, x
-	x.ShortUrlId = b.ShortUrlId
+	x.Platform = b.Platform
+	x.Language = b.Language
+	x.Country = b.Country
+	x.Url = b.Url
 	return m0
 }
 
-type ExpandResponse struct {
+// Campaign tags appended to the destination as utm_* query parameters on redirect.
+type UTM struct {
 	state         protoimpl.MessageState `protogen:"hybrid.v1"`
-	OriginalUrl   *string                `protobuf:"bytes,1,opt,name=original_url,json=originalUrl" json:"original_url,omitempty"`
+	Source        *string                `protobuf:"bytes,1,opt,name=source" json:"source,omitempty"`
+	Medium        *string                `protobuf:"bytes,2,opt,name=medium" json:"medium,omitempty"`
+	Campaign      *string                `protobuf:"bytes,3,opt,name=campaign" json:"campaign,omitempty"`
+	Term          *string                `protobuf:"bytes,4,opt,name=term" json:"term,omitempty"`
+	Content       *string                `protobuf:"bytes,5,opt,name=content" json:"content,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ExpandResponse) Reset() {
-	*x = ExpandResponse{}
+func (x *UTM) Reset() {
+	*x = UTM{}
 	mi := &file_proto_shortugo_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ExpandResponse) String() string {
+func (x *UTM) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ExpandResponse) ProtoMessage() {}
+func (*UTM) ProtoMessage() {}
 
-func (x *ExpandResponse) ProtoReflect() protoreflect.Message {
+func (x *UTM) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_shortugo_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -403,64 +829,162 @@ func (x *ExpandResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-func (x *ExpandResponse) GetOriginalUrl() string {
-	if x != nil && x.OriginalUrl != nil {
-		return *x.OriginalUrl
+func (x *UTM) GetSource() string {
+	if x != nil && x.Source != nil {
+		return *x.Source
 	}
 	return ""
 }
 
-func (x *ExpandResponse) SetOriginalUrl(v string) {
-	x.OriginalUrl = &v
+func (x *UTM) GetMedium() string {
+	if x != nil && x.Medium != nil {
+		return *x.Medium
+	}
+	return ""
 }
 
-func (x *ExpandResponse) HasOriginalUrl() bool {
+func (x *UTM) GetCampaign() string {
+	if x != nil && x.Campaign != nil {
+		return *x.Campaign
+	}
+	return ""
+}
+
+func (x *UTM) GetTerm() string {
+	if x != nil && x.Term != nil {
+		return *x.Term
+	}
+	return ""
+}
+
+func (x *UTM) GetContent() string {
+	if x != nil && x.Content != nil {
+		return *x.Content
+	}
+	return ""
+}
+
+func (x *UTM) SetSource(v string) {
+	x.Source = &v
+}
+
+func (x *UTM) SetMedium(v string) {
+	x.Medium = &v
+}
+
+func (x *UTM) SetCampaign(v string) {
+	x.Campaign = &v
+}
+
+func (x *UTM) SetTerm(v string) {
+	x.Term = &v
+}
+
+func (x *UTM) SetContent(v string) {
+	x.Content = &v
+}
+
+func (x *UTM) HasSource() bool {
 	if x == nil {
 		return false
 	}
-	return x.OriginalUrl != nil
+	return x.Source != nil
 }
 
-func (x *ExpandResponse) ClearOriginalUrl() {
-	x.OriginalUrl = nil
+func (x *UTM) HasMedium() bool {
+	if x == nil {
+		return false
+	}
+	return x.Medium != nil
 }
 
-type ExpandResponse_builder struct {
+func (x *UTM) HasCampaign() bool {
+	if x == nil {
+		return false
+	}
+	return x.Campaign != nil
+}
+
+func (x *UTM) HasTerm() bool {
+	if x == nil {
+		return false
+	}
+	return x.Term != nil
+}
+
+func (x *UTM) HasContent() bool {
+	if x == nil {
+		return false
+	}
+	return x.Content != nil
+}
+
+func (x *UTM) ClearSource() {
+	x.Source = nil
+}
+
+func (x *UTM) ClearMedium() {
+	x.Medium = nil
+}
+
+func (x *UTM) ClearCampaign() {
+	x.Campaign = nil
+}
+
+func (x *UTM) ClearTerm() {
+	x.Term = nil
+}
+
+func (x *UTM) ClearContent() {
+	x.Content = nil
+}
+
+type UTM_builder struct {
 	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
 
-	OriginalUrl *string
+	Source   *string
+	Medium   *string
+	Campaign *string
+	Term     *string
+	Content  *string
 }
 
-func (b0 ExpandResponse_builder) Build() *ExpandResponse {
-	m0 := &ExpandResponse{}
+func (b0 UTM_builder) Build() *UTM {
+	m0 := &UTM{}
 	b, x := &b0, m0
 	_, _ = b, x
-	x.OriginalUrl = b.OriginalUrl
+	x.Source = b.Source
+	x.Medium = b.Medium
+	x.Campaign = b.Campaign
+	x.Term = b.Term
+	x.Content = b.Content
 	return m0
 }
 
-type ShortenBatchRequest struct {
+type ShortenRequest struct {
 	state         protoimpl.MessageState `protogen:"hybrid.v1"`
-	UserId        *string                `protobuf:"bytes,1,opt,name=user_id,json=userId" json:"user_id,omitempty"`
-	Urls          []*URLPair             `protobuf:"bytes,2,rep,name=urls" json:"urls,omitempty"`
+	OriginalUrl   *string                `protobuf:"bytes,1,opt,name=original_url,json=originalUrl" json:"original_url,omitempty"`
+	UserId        *string                `protobuf:"bytes,2,opt,name=user_id,json=userId" json:"user_id,omitempty"`
+	Options       *LinkOptions           `protobuf:"bytes,3,opt,name=options" json:"options,omitempty"`
+	Title         *string                `protobuf:"bytes,4,opt,name=title" json:"title,omitempty"` // up to 256 characters, found by SearchUserURLs
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ShortenBatchRequest) Reset() {
-	*x = ShortenBatchRequest{}
+func (x *ShortenRequest) Reset() {
+	*x = ShortenRequest{}
 	mi := &file_proto_shortugo_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ShortenBatchRequest) String() string {
+func (x *ShortenRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ShortenBatchRequest) ProtoMessage() {}
+func (*ShortenRequest) ProtoMessage() {}
 
-func (x *ShortenBatchRequest) ProtoReflect() protoreflect.Message {
+func (x *ShortenRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_shortugo_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -472,76 +996,135 @@ func (x *ShortenBatchRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-func (x *ShortenBatchRequest) GetUserId() string {
+func (x *ShortenRequest) GetOriginalUrl() string {
+	if x != nil && x.OriginalUrl != nil {
+		return *x.OriginalUrl
+	}
+	return ""
+}
+
+func (x *ShortenRequest) GetUserId() string {
 	if x != nil && x.UserId != nil {
 		return *x.UserId
 	}
 	return ""
 }
 
-func (x *ShortenBatchRequest) GetUrls() []*URLPair {
+func (x *ShortenRequest) GetOptions() *LinkOptions {
 	if x != nil {
-		return x.Urls
+		return x.Options
 	}
 	return nil
 }
 
-func (x *ShortenBatchRequest) SetUserId(v string) {
+func (x *ShortenRequest) GetTitle() string {
+	if x != nil && x.Title != nil {
+		return *x.Title
+	}
+	return ""
+}
+
+func (x *ShortenRequest) SetOriginalUrl(v string) {
+	x.OriginalUrl = &v
+}
+
+func (x *ShortenRequest) SetUserId(v string) {
 	x.UserId = &v
 }
 
-func (x *ShortenBatchRequest) SetUrls(v []*URLPair) {
-	x.Urls = v
+func (x *ShortenRequest) SetOptions(v *LinkOptions) {
+	x.Options = v
 }
 
-func (x *ShortenBatchRequest) HasUserId() bool {
+func (x *ShortenRequest) SetTitle(v string) {
+	x.Title = &v
+}
+
+func (x *ShortenRequest) HasOriginalUrl() bool {
+	if x == nil {
+		return false
+	}
+	return x.OriginalUrl != nil
+}
+
+func (x *ShortenRequest) HasUserId() bool {
 	if x == nil {
 		return false
 	}
 	return x.UserId != nil
 }
 
-func (x *ShortenBatchRequest) ClearUserId() {
+func (x *ShortenRequest) HasOptions() bool {
+	if x == nil {
+		return false
+	}
+	return x.Options != nil
+}
+
+func (x *ShortenRequest) HasTitle() bool {
+	if x == nil {
+		return false
+	}
+	return x.Title != nil
+}
+
+func (x *ShortenRequest) ClearOriginalUrl() {
+	x.OriginalUrl = nil
+}
+
+func (x *ShortenRequest) ClearUserId() {
 	x.UserId = nil
 }
 
-type ShortenBatchRequest_builder struct {
+func (x *ShortenRequest) ClearOptions() {
+	x.Options = nil
+}
+
+func (x *ShortenRequest) ClearTitle() {
+	x.Title = nil
+}
+
+type ShortenRequest_builder struct {
 	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
 
-	UserId *string
-	Urls   []*URLPair
+	OriginalUrl *string
+	UserId      *string
+	Options     *LinkOptions
+	Title       *string
 }
 
-func (b0 ShortenBatchRequest_builder) Build() *ShortenBatchRequest {
-	m0 := &ShortenBatchRequest{}
+func (b0 ShortenRequest_builder) Build() *ShortenRequest {
+	m0 := &ShortenRequest{}
 	b, x := &b0, m0
 	_, _ = b, x
+	x.OriginalUrl = b.OriginalUrl
 	x.UserId = b.UserId
-	x.Urls = b.Urls
+	x.Options = b.Options
+	x.Title = b.Title
 	return m0
 }
 
-type ShortenBatchResponse struct {
+type ShortenResponse struct {
 	state         protoimpl.MessageState `protogen:"hybrid.v1"`
-	Results       []*URLPair             `protobuf:"bytes,1,rep,name=results" json:"results,omitempty"`
+	ShortUrl      *string                `protobuf:"bytes,1,opt,name=short_url,json=shortUrl" json:"short_url,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ShortenBatchResponse) Reset() {
-	*x = ShortenBatchResponse{}
+func (x *ShortenResponse) Reset() {
+	*x = ShortenResponse{}
 	mi := &file_proto_shortugo_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ShortenBatchResponse) String() string {
+func (x *ShortenResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ShortenBatchResponse) ProtoMessage() {}
+func (*ShortenResponse) ProtoMessage() {}
 
-func (x *ShortenBatchResponse) ProtoReflect() protoreflect.Message {
+func (x *ShortenResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_shortugo_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -553,52 +1136,64 @@ func (x *ShortenBatchResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-func (x *ShortenBatchResponse) GetResults() []*URLPair {
-	if x != nil {
-		return x.Results
+func (x *ShortenResponse) GetShortUrl() string {
+	if x != nil && x.ShortUrl != nil {
+		return *x.ShortUrl
 	}
-	return nil
+	return ""
 }
 
-func (x *ShortenBatchResponse) SetResults(v []*URLPair) {
-	x.Results = v
+func (x *ShortenResponse) SetShortUrl(v string) {
+	x.ShortUrl = &v
 }
 
-type ShortenBatchResponse_builder struct {
+func (x *ShortenResponse) HasShortUrl() bool {
+	if x == nil {
+		return false
+	}
+	return x.ShortUrl != nil
+}
+
+func (x *ShortenResponse) ClearShortUrl() {
+	x.ShortUrl = nil
+}
+
+type ShortenResponse_builder struct {
 	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
 
-	Results []*URLPair
+	ShortUrl *string
 }
 
-func (b0 ShortenBatchResponse_builder) Build() *ShortenBatchResponse {
-	m0 := &ShortenBatchResponse{}
+func (b0 ShortenResponse_builder) Build() *ShortenResponse {
+	m0 := &ShortenResponse{}
 	b, x := &b0, m0
 	_, _ = b, x
-	x.Results = b.Results
+	x.ShortUrl = b.ShortUrl
 	return m0
 }
 
-type ListUserURLsRequest struct {
+type ExpandRequest struct {
 	state         protoimpl.MessageState `protogen:"hybrid.v1"`
-	UserId        *string                `protobuf:"bytes,1,opt,name=user_id,json=userId" json:"user_id,omitempty"`
+	ShortUrlId    *string                `protobuf:"bytes,1,opt,name=short_url_id,json=shortUrlId" json:"short_url_id,omitempty"`
+	Password      *string                `protobuf:"bytes,2,opt,name=password" json:"password,omitempty"` // required for password-protected links
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListUserURLsRequest) Reset() {
-	*x = ListUserURLsRequest{}
+func (x *ExpandRequest) Reset() {
+	*x = ExpandRequest{}
 	mi := &file_proto_shortugo_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListUserURLsRequest) String() string {
+func (x *ExpandRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListUserURLsRequest) ProtoMessage() {}
+func (*ExpandRequest) ProtoMessage() {}
 
-func (x *ListUserURLsRequest) ProtoReflect() protoreflect.Message {
+func (x *ExpandRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_shortugo_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -610,63 +1205,87 @@ func (x *ListUserURLsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-func (x *ListUserURLsRequest) GetUserId() string {
-	if x != nil && x.UserId != nil {
-		return *x.UserId
+func (x *ExpandRequest) GetShortUrlId() string {
+	if x != nil && x.ShortUrlId != nil {
+		return *x.ShortUrlId
 	}
 	return ""
 }
 
-func (x *ListUserURLsRequest) SetUserId(v string) {
-	x.UserId = &v
+func (x *ExpandRequest) GetPassword() string {
+	if x != nil && x.Password != nil {
+		return *x.Password
+	}
+	return ""
 }
 
-func (x *ListUserURLsRequest) HasUserId() bool {
+func (x *ExpandRequest) SetShortUrlId(v string) {
+	x.ShortUrlId = &v
+}
+
+func (x *ExpandRequest) SetPassword(v string) {
+	x.Password = &v
+}
+
+func (x *ExpandRequest) HasShortUrlId() bool {
 	if x == nil {
 		return false
 	}
-	return x.UserId != nil
+	return x.ShortUrlId != nil
 }
 
-func (x *ListUserURLsRequest) ClearUserId() {
-	x.UserId = nil
+func (x *ExpandRequest) HasPassword() bool {
+	if x == nil {
+		return false
+	}
+	return x.Password != nil
 }
 
-type ListUserURLsRequest_builder struct {
+func (x *ExpandRequest) ClearShortUrlId() {
+	x.ShortUrlId = nil
+}
+
+func (x *ExpandRequest) ClearPassword() {
+	x.Password = nil
+}
+
+type ExpandRequest_builder struct {
 	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
 
-	UserId *string
+	ShortUrlId *string
+	Password   *string
 }
 
-func (b0 ListUserURLsRequest_builder) Build() *ListUserURLsRequest {
-	m0 := &ListUserURLsRequest{}
+func (b0 ExpandRequest_builder) Build() *ExpandRequest {
+	m0 := &ExpandRequest{}
 	b, x := &b0, m0
 	_, _ = b, x
-	x.UserId = b.UserId
+	x.ShortUrlId = b.ShortUrlId
+	x.Password = b.Password
 	return m0
 }
 
-type ListUserURLsResponse struct {
+type ExpandResponse struct {
 	state         protoimpl.MessageState `protogen:"hybrid.v1"`
-	Urls          []*URLPair             `protobuf:"bytes,1,rep,name=urls" json:"urls,omitempty"`
+	OriginalUrl   *string                `protobuf:"bytes,1,opt,name=original_url,json=originalUrl" json:"original_url,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListUserURLsResponse) Reset() {
-	*x = ListUserURLsResponse{}
+func (x *ExpandResponse) Reset() {
+	*x = ExpandResponse{}
 	mi := &file_proto_shortugo_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListUserURLsResponse) String() string {
+func (x *ExpandResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListUserURLsResponse) ProtoMessage() {}
+func (*ExpandResponse) ProtoMessage() {}
 
-func (x *ListUserURLsResponse) ProtoReflect() protoreflect.Message {
+func (x *ExpandResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_shortugo_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -678,53 +1297,64 @@ func (x *ListUserURLsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-func (x *ListUserURLsResponse) GetUrls() []*URLPair {
-	if x != nil {
-		return x.Urls
+func (x *ExpandResponse) GetOriginalUrl() string {
+	if x != nil && x.OriginalUrl != nil {
+		return *x.OriginalUrl
 	}
-	return nil
+	return ""
 }
 
-func (x *ListUserURLsResponse) SetUrls(v []*URLPair) {
-	x.Urls = v
+func (x *ExpandResponse) SetOriginalUrl(v string) {
+	x.OriginalUrl = &v
 }
 
-type ListUserURLsResponse_builder struct {
+func (x *ExpandResponse) HasOriginalUrl() bool {
+	if x == nil {
+		return false
+	}
+	return x.OriginalUrl != nil
+}
+
+func (x *ExpandResponse) ClearOriginalUrl() {
+	x.OriginalUrl = nil
+}
+
+type ExpandResponse_builder struct {
 	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
 
-	Urls []*URLPair
+	OriginalUrl *string
 }
 
-func (b0 ListUserURLsResponse_builder) Build() *ListUserURLsResponse {
-	m0 := &ListUserURLsResponse{}
+func (b0 ExpandResponse_builder) Build() *ExpandResponse {
+	m0 := &ExpandResponse{}
 	b, x := &b0, m0
 	_, _ = b, x
-	x.Urls = b.Urls
+	x.OriginalUrl = b.OriginalUrl
 	return m0
 }
 
-type DeleteUserURLsRequest struct {
+type ShortenBatchRequest struct {
 	state         protoimpl.MessageState `protogen:"hybrid.v1"`
 	UserId        *string                `protobuf:"bytes,1,opt,name=user_id,json=userId" json:"user_id,omitempty"`
-	ShortUrlIds   []string               `protobuf:"bytes,2,rep,name=short_url_ids,json=shortUrlIds" json:"short_url_ids,omitempty"`
+	Urls          []*URLPair             `protobuf:"bytes,2,rep,name=urls" json:"urls,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteUserURLsRequest) Reset() {
-	*x = DeleteUserURLsRequest{}
+func (x *ShortenBatchRequest) Reset() {
+	*x = ShortenBatchRequest{}
 	mi := &file_proto_shortugo_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteUserURLsRequest) String() string {
+func (x *ShortenBatchRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteUserURLsRequest) ProtoMessage() {}
+func (*ShortenBatchRequest) ProtoMessage() {}
 
-func (x *DeleteUserURLsRequest) ProtoReflect() protoreflect.Message {
+func (x *ShortenBatchRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_shortugo_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -736,76 +1366,76 @@ func (x *DeleteUserURLsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-func (x *DeleteUserURLsRequest) GetUserId() string {
+func (x *ShortenBatchRequest) GetUserId() string {
 	if x != nil && x.UserId != nil {
 		return *x.UserId
 	}
 	return ""
 }
 
-func (x *DeleteUserURLsRequest) GetShortUrlIds() []string {
+func (x *ShortenBatchRequest) GetUrls() []*URLPair {
 	if x != nil {
-		return x.ShortUrlIds
+		return x.Urls
 	}
 	return nil
 }
 
-func (x *DeleteUserURLsRequest) SetUserId(v string) {
+func (x *ShortenBatchRequest) SetUserId(v string) {
 	x.UserId = &v
 }
 
-func (x *DeleteUserURLsRequest) SetShortUrlIds(v []string) {
-	x.ShortUrlIds = v
+func (x *ShortenBatchRequest) SetUrls(v []*URLPair) {
+	x.Urls = v
 }
 
-func (x *DeleteUserURLsRequest) HasUserId() bool {
+func (x *ShortenBatchRequest) HasUserId() bool {
 	if x == nil {
 		return false
 	}
 	return x.UserId != nil
 }
 
-func (x *DeleteUserURLsRequest) ClearUserId() {
+func (x *ShortenBatchRequest) ClearUserId() {
 	x.UserId = nil
 }
 
-type DeleteUserURLsRequest_builder struct {
+type ShortenBatchRequest_builder struct {
 	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
 
-	UserId      *string
-	ShortUrlIds []string
+	UserId *string
+	Urls   []*URLPair
 }
 
-func (b0 DeleteUserURLsRequest_builder) Build() *DeleteUserURLsRequest {
-	m0 := &DeleteUserURLsRequest{}
+func (b0 ShortenBatchRequest_builder) Build() *ShortenBatchRequest {
+	m0 := &ShortenBatchRequest{}
 	b, x := &b0, m0
 	_, _ = b, x
 	x.UserId = b.UserId
-	x.ShortUrlIds = b.ShortUrlIds
+	x.Urls = b.Urls
 	return m0
 }
 
-type DeleteUserURLsResponse struct {
+type ShortenBatchResponse struct {
 	state         protoimpl.MessageState `protogen:"hybrid.v1"`
-	Success       *bool                  `protobuf:"varint,1,opt,name=success" json:"success,omitempty"`
+	Results       []*URLPair             `protobuf:"bytes,1,rep,name=results" json:"results,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteUserURLsResponse) Reset() {
-	*x = DeleteUserURLsResponse{}
+func (x *ShortenBatchResponse) Reset() {
+	*x = ShortenBatchResponse{}
 	mi := &file_proto_shortugo_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteUserURLsResponse) String() string {
+func (x *ShortenBatchResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteUserURLsResponse) ProtoMessage() {}
+func (*ShortenBatchResponse) ProtoMessage() {}
 
-func (x *DeleteUserURLsResponse) ProtoReflect() protoreflect.Message {
+func (x *ShortenBatchResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_shortugo_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -817,107 +1447,3205 @@ func (x *DeleteUserURLsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-func (x *DeleteUserURLsResponse) GetSuccess() bool {
-	if x != nil && x.Success != nil {
-		return *x.Success
+func (x *ShortenBatchResponse) GetResults() []*URLPair {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *ShortenBatchResponse) SetResults(v []*URLPair) {
+	x.Results = v
+}
+
+type ShortenBatchResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Results []*URLPair
+}
+
+func (b0 ShortenBatchResponse_builder) Build() *ShortenBatchResponse {
+	m0 := &ShortenBatchResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.Results = b.Results
+	return m0
+}
+
+type ListUserURLsRequest struct {
+	state         protoimpl.MessageState `protogen:"hybrid.v1"`
+	UserId        *string                `protobuf:"bytes,1,opt,name=user_id,json=userId" json:"user_id,omitempty"`
+	Tag           *string                `protobuf:"bytes,2,opt,name=tag" json:"tag,omitempty"`       // only list links with this tag
+	Folder        *string                `protobuf:"bytes,3,opt,name=folder" json:"folder,omitempty"` // only list links in this folder
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUserURLsRequest) Reset() {
+	*x = ListUserURLsRequest{}
+	mi := &file_proto_shortugo_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUserURLsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUserURLsRequest) ProtoMessage() {}
+
+func (x *ListUserURLsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortugo_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *ListUserURLsRequest) GetUserId() string {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return ""
+}
+
+func (x *ListUserURLsRequest) GetTag() string {
+	if x != nil && x.Tag != nil {
+		return *x.Tag
+	}
+	return ""
+}
+
+func (x *ListUserURLsRequest) GetFolder() string {
+	if x != nil && x.Folder != nil {
+		return *x.Folder
+	}
+	return ""
+}
+
+func (x *ListUserURLsRequest) SetUserId(v string) {
+	x.UserId = &v
+}
+
+func (x *ListUserURLsRequest) SetTag(v string) {
+	x.Tag = &v
+}
+
+func (x *ListUserURLsRequest) SetFolder(v string) {
+	x.Folder = &v
+}
+
+func (x *ListUserURLsRequest) HasUserId() bool {
+	if x == nil {
+		return false
+	}
+	return x.UserId != nil
+}
+
+func (x *ListUserURLsRequest) HasTag() bool {
+	if x == nil {
+		return false
+	}
+	return x.Tag != nil
+}
+
+func (x *ListUserURLsRequest) HasFolder() bool {
+	if x == nil {
+		return false
+	}
+	return x.Folder != nil
+}
+
+func (x *ListUserURLsRequest) ClearUserId() {
+	x.UserId = nil
+}
+
+func (x *ListUserURLsRequest) ClearTag() {
+	x.Tag = nil
+}
+
+func (x *ListUserURLsRequest) ClearFolder() {
+	x.Folder = nil
+}
+
+type ListUserURLsRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	UserId *string
+	Tag    *string
+	Folder *string
+}
+
+func (b0 ListUserURLsRequest_builder) Build() *ListUserURLsRequest {
+	m0 := &ListUserURLsRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.UserId = b.UserId
+	x.Tag = b.Tag
+	x.Folder = b.Folder
+	return m0
+}
+
+type ListUserURLsResponse struct {
+	state         protoimpl.MessageState `protogen:"hybrid.v1"`
+	Urls          []*URLPair             `protobuf:"bytes,1,rep,name=urls" json:"urls,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUserURLsResponse) Reset() {
+	*x = ListUserURLsResponse{}
+	mi := &file_proto_shortugo_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUserURLsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUserURLsResponse) ProtoMessage() {}
+
+func (x *ListUserURLsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortugo_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *ListUserURLsResponse) GetUrls() []*URLPair {
+	if x != nil {
+		return x.Urls
+	}
+	return nil
+}
+
+func (x *ListUserURLsResponse) SetUrls(v []*URLPair) {
+	x.Urls = v
+}
+
+type ListUserURLsResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Urls []*URLPair
+}
+
+func (b0 ListUserURLsResponse_builder) Build() *ListUserURLsResponse {
+	m0 := &ListUserURLsResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.Urls = b.Urls
+	return m0
+}
+
+type SearchUserURLsRequest struct {
+	state         protoimpl.MessageState `protogen:"hybrid.v1"`
+	UserId        *string                `protobuf:"bytes,1,opt,name=user_id,json=userId" json:"user_id,omitempty"`
+	Q             *string                `protobuf:"bytes,2,opt,name=q" json:"q,omitempty"` // every word has to start a word of the link's URL, alias, title or tags
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchUserURLsRequest) Reset() {
+	*x = SearchUserURLsRequest{}
+	mi := &file_proto_shortugo_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchUserURLsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchUserURLsRequest) ProtoMessage() {}
+
+func (x *SearchUserURLsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortugo_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *SearchUserURLsRequest) GetUserId() string {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return ""
+}
+
+func (x *SearchUserURLsRequest) GetQ() string {
+	if x != nil && x.Q != nil {
+		return *x.Q
+	}
+	return ""
+}
+
+func (x *SearchUserURLsRequest) SetUserId(v string) {
+	x.UserId = &v
+}
+
+func (x *SearchUserURLsRequest) SetQ(v string) {
+	x.Q = &v
+}
+
+func (x *SearchUserURLsRequest) HasUserId() bool {
+	if x == nil {
+		return false
+	}
+	return x.UserId != nil
+}
+
+func (x *SearchUserURLsRequest) HasQ() bool {
+	if x == nil {
+		return false
+	}
+	return x.Q != nil
+}
+
+func (x *SearchUserURLsRequest) ClearUserId() {
+	x.UserId = nil
+}
+
+func (x *SearchUserURLsRequest) ClearQ() {
+	x.Q = nil
+}
+
+type SearchUserURLsRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	UserId *string
+	Q      *string
+}
+
+func (b0 SearchUserURLsRequest_builder) Build() *SearchUserURLsRequest {
+	m0 := &SearchUserURLsRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.UserId = b.UserId
+	x.Q = b.Q
+	return m0
+}
+
+type ShortenStreamRequest struct {
+	state         protoimpl.MessageState `protogen:"hybrid.v1"`
+	UserId        *string                `protobuf:"bytes,1,opt,name=user_id,json=userId" json:"user_id,omitempty"`
+	CorrelationId *string                `protobuf:"bytes,2,opt,name=correlation_id,json=correlationId" json:"correlation_id,omitempty"`
+	OriginalUrl   *string                `protobuf:"bytes,3,opt,name=original_url,json=originalUrl" json:"original_url,omitempty"`
+	Options       *LinkOptions           `protobuf:"bytes,4,opt,name=options" json:"options,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShortenStreamRequest) Reset() {
+	*x = ShortenStreamRequest{}
+	mi := &file_proto_shortugo_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShortenStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShortenStreamRequest) ProtoMessage() {}
+
+func (x *ShortenStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortugo_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *ShortenStreamRequest) GetUserId() string {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return ""
+}
+
+func (x *ShortenStreamRequest) GetCorrelationId() string {
+	if x != nil && x.CorrelationId != nil {
+		return *x.CorrelationId
+	}
+	return ""
+}
+
+func (x *ShortenStreamRequest) GetOriginalUrl() string {
+	if x != nil && x.OriginalUrl != nil {
+		return *x.OriginalUrl
+	}
+	return ""
+}
+
+func (x *ShortenStreamRequest) GetOptions() *LinkOptions {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *ShortenStreamRequest) SetUserId(v string) {
+	x.UserId = &v
+}
+
+func (x *ShortenStreamRequest) SetCorrelationId(v string) {
+	x.CorrelationId = &v
+}
+
+func (x *ShortenStreamRequest) SetOriginalUrl(v string) {
+	x.OriginalUrl = &v
+}
+
+func (x *ShortenStreamRequest) SetOptions(v *LinkOptions) {
+	x.Options = v
+}
+
+func (x *ShortenStreamRequest) HasUserId() bool {
+	if x == nil {
+		return false
+	}
+	return x.UserId != nil
+}
+
+func (x *ShortenStreamRequest) HasCorrelationId() bool {
+	if x == nil {
+		return false
+	}
+	return x.CorrelationId != nil
+}
+
+func (x *ShortenStreamRequest) HasOriginalUrl() bool {
+	if x == nil {
+		return false
+	}
+	return x.OriginalUrl != nil
+}
+
+func (x *ShortenStreamRequest) HasOptions() bool {
+	if x == nil {
+		return false
+	}
+	return x.Options != nil
+}
+
+func (x *ShortenStreamRequest) ClearUserId() {
+	x.UserId = nil
+}
+
+func (x *ShortenStreamRequest) ClearCorrelationId() {
+	x.CorrelationId = nil
+}
+
+func (x *ShortenStreamRequest) ClearOriginalUrl() {
+	x.OriginalUrl = nil
+}
+
+func (x *ShortenStreamRequest) ClearOptions() {
+	x.Options = nil
+}
+
+type ShortenStreamRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	UserId        *string
+	CorrelationId *string
+	OriginalUrl   *string
+	Options       *LinkOptions
+}
+
+func (b0 ShortenStreamRequest_builder) Build() *ShortenStreamRequest {
+	m0 := &ShortenStreamRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.UserId = b.UserId
+	x.CorrelationId = b.CorrelationId
+	x.OriginalUrl = b.OriginalUrl
+	x.Options = b.Options
+	return m0
+}
+
+type GetQRCodeRequest struct {
+	state         protoimpl.MessageState `protogen:"hybrid.v1"`
+	ShortUrlId    *string                `protobuf:"bytes,1,opt,name=short_url_id,json=shortUrlId" json:"short_url_id,omitempty"`
+	Format        *string                `protobuf:"bytes,2,opt,name=format" json:"format,omitempty"` // png (default) or svg
+	Size          *int32                 `protobuf:"varint,3,opt,name=size" json:"size,omitempty"`    // image width and height in pixels, 256 by default
+	Level         *string                `protobuf:"bytes,4,opt,name=level" json:"level,omitempty"`   // error-correction level: L, M (default), Q or H
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetQRCodeRequest) Reset() {
+	*x = GetQRCodeRequest{}
+	mi := &file_proto_shortugo_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetQRCodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetQRCodeRequest) ProtoMessage() {}
+
+func (x *GetQRCodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortugo_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *GetQRCodeRequest) GetShortUrlId() string {
+	if x != nil && x.ShortUrlId != nil {
+		return *x.ShortUrlId
+	}
+	return ""
+}
+
+func (x *GetQRCodeRequest) GetFormat() string {
+	if x != nil && x.Format != nil {
+		return *x.Format
+	}
+	return ""
+}
+
+func (x *GetQRCodeRequest) GetSize() int32 {
+	if x != nil && x.Size != nil {
+		return *x.Size
+	}
+	return 0
+}
+
+func (x *GetQRCodeRequest) GetLevel() string {
+	if x != nil && x.Level != nil {
+		return *x.Level
+	}
+	return ""
+}
+
+func (x *GetQRCodeRequest) SetShortUrlId(v string) {
+	x.ShortUrlId = &v
+}
+
+func (x *GetQRCodeRequest) SetFormat(v string) {
+	x.Format = &v
+}
+
+func (x *GetQRCodeRequest) SetSize(v int32) {
+	x.Size = &v
+}
+
+func (x *GetQRCodeRequest) SetLevel(v string) {
+	x.Level = &v
+}
+
+func (x *GetQRCodeRequest) HasShortUrlId() bool {
+	if x == nil {
+		return false
+	}
+	return x.ShortUrlId != nil
+}
+
+func (x *GetQRCodeRequest) HasFormat() bool {
+	if x == nil {
+		return false
+	}
+	return x.Format != nil
+}
+
+func (x *GetQRCodeRequest) HasSize() bool {
+	if x == nil {
+		return false
+	}
+	return x.Size != nil
+}
+
+func (x *GetQRCodeRequest) HasLevel() bool {
+	if x == nil {
+		return false
+	}
+	return x.Level != nil
+}
+
+func (x *GetQRCodeRequest) ClearShortUrlId() {
+	x.ShortUrlId = nil
+}
+
+func (x *GetQRCodeRequest) ClearFormat() {
+	x.Format = nil
+}
+
+func (x *GetQRCodeRequest) ClearSize() {
+	x.Size = nil
+}
+
+func (x *GetQRCodeRequest) ClearLevel() {
+	x.Level = nil
+}
+
+type GetQRCodeRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	ShortUrlId *string
+	Format     *string
+	Size       *int32
+	Level      *string
+}
+
+func (b0 GetQRCodeRequest_builder) Build() *GetQRCodeRequest {
+	m0 := &GetQRCodeRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.ShortUrlId = b.ShortUrlId
+	x.Format = b.Format
+	x.Size = b.Size
+	x.Level = b.Level
+	return m0
+}
+
+type GetQRCodeResponse struct {
+	state         protoimpl.MessageState `protogen:"hybrid.v1"`
+	Image         []byte                 `protobuf:"bytes,1,opt,name=image" json:"image,omitempty"`
+	ContentType   *string                `protobuf:"bytes,2,opt,name=content_type,json=contentType" json:"content_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetQRCodeResponse) Reset() {
+	*x = GetQRCodeResponse{}
+	mi := &file_proto_shortugo_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetQRCodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetQRCodeResponse) ProtoMessage() {}
+
+func (x *GetQRCodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortugo_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *GetQRCodeResponse) GetImage() []byte {
+	if x != nil {
+		return x.Image
+	}
+	return nil
+}
+
+func (x *GetQRCodeResponse) GetContentType() string {
+	if x != nil && x.ContentType != nil {
+		return *x.ContentType
+	}
+	return ""
+}
+
+func (x *GetQRCodeResponse) SetImage(v []byte) {
+	if v == nil {
+		v = []byte{}
+	}
+	x.Image = v
+}
+
+func (x *GetQRCodeResponse) SetContentType(v string) {
+	x.ContentType = &v
+}
+
+func (x *GetQRCodeResponse) HasImage() bool {
+	if x == nil {
+		return false
+	}
+	return x.Image != nil
+}
+
+func (x *GetQRCodeResponse) HasContentType() bool {
+	if x == nil {
+		return false
+	}
+	return x.ContentType != nil
+}
+
+func (x *GetQRCodeResponse) ClearImage() {
+	x.Image = nil
+}
+
+func (x *GetQRCodeResponse) ClearContentType() {
+	x.ContentType = nil
+}
+
+type GetQRCodeResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Image       []byte
+	ContentType *string
+}
+
+func (b0 GetQRCodeResponse_builder) Build() *GetQRCodeResponse {
+	m0 := &GetQRCodeResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.Image = b.Image
+	x.ContentType = b.ContentType
+	return m0
+}
+
+type GetUTMTemplateRequest struct {
+	state         protoimpl.MessageState `protogen:"hybrid.v1"`
+	UserId        *string                `protobuf:"bytes,1,opt,name=user_id,json=userId" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUTMTemplateRequest) Reset() {
+	*x = GetUTMTemplateRequest{}
+	mi := &file_proto_shortugo_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUTMTemplateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUTMTemplateRequest) ProtoMessage() {}
+
+func (x *GetUTMTemplateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortugo_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *GetUTMTemplateRequest) GetUserId() string {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return ""
+}
+
+func (x *GetUTMTemplateRequest) SetUserId(v string) {
+	x.UserId = &v
+}
+
+func (x *GetUTMTemplateRequest) HasUserId() bool {
+	if x == nil {
+		return false
+	}
+	return x.UserId != nil
+}
+
+func (x *GetUTMTemplateRequest) ClearUserId() {
+	x.UserId = nil
+}
+
+type GetUTMTemplateRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	UserId *string
+}
+
+func (b0 GetUTMTemplateRequest_builder) Build() *GetUTMTemplateRequest {
+	m0 := &GetUTMTemplateRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.UserId = b.UserId
+	return m0
+}
+
+type SetUTMTemplateRequest struct {
+	state         protoimpl.MessageState `protogen:"hybrid.v1"`
+	UserId        *string                `protobuf:"bytes,1,opt,name=user_id,json=userId" json:"user_id,omitempty"`
+	Template      *UTM                   `protobuf:"bytes,2,opt,name=template" json:"template,omitempty"` // an empty template removes it
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetUTMTemplateRequest) Reset() {
+	*x = SetUTMTemplateRequest{}
+	mi := &file_proto_shortugo_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetUTMTemplateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetUTMTemplateRequest) ProtoMessage() {}
+
+func (x *SetUTMTemplateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortugo_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *SetUTMTemplateRequest) GetUserId() string {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return ""
+}
+
+func (x *SetUTMTemplateRequest) GetTemplate() *UTM {
+	if x != nil {
+		return x.Template
+	}
+	return nil
+}
+
+func (x *SetUTMTemplateRequest) SetUserId(v string) {
+	x.UserId = &v
+}
+
+func (x *SetUTMTemplateRequest) SetTemplate(v *UTM) {
+	x.Template = v
+}
+
+func (x *SetUTMTemplateRequest) HasUserId() bool {
+	if x == nil {
+		return false
+	}
+	return x.UserId != nil
+}
+
+func (x *SetUTMTemplateRequest) HasTemplate() bool {
+	if x == nil {
+		return false
+	}
+	return x.Template != nil
+}
+
+func (x *SetUTMTemplateRequest) ClearUserId() {
+	x.UserId = nil
+}
+
+func (x *SetUTMTemplateRequest) ClearTemplate() {
+	x.Template = nil
+}
+
+type SetUTMTemplateRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	UserId   *string
+	Template *UTM
+}
+
+func (b0 SetUTMTemplateRequest_builder) Build() *SetUTMTemplateRequest {
+	m0 := &SetUTMTemplateRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.UserId = b.UserId
+	x.Template = b.Template
+	return m0
+}
+
+type UTMTemplateResponse struct {
+	state         protoimpl.MessageState `protogen:"hybrid.v1"`
+	Template      *UTM                   `protobuf:"bytes,1,opt,name=template" json:"template,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UTMTemplateResponse) Reset() {
+	*x = UTMTemplateResponse{}
+	mi := &file_proto_shortugo_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UTMTemplateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UTMTemplateResponse) ProtoMessage() {}
+
+func (x *UTMTemplateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortugo_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *UTMTemplateResponse) GetTemplate() *UTM {
+	if x != nil {
+		return x.Template
+	}
+	return nil
+}
+
+func (x *UTMTemplateResponse) SetTemplate(v *UTM) {
+	x.Template = v
+}
+
+func (x *UTMTemplateResponse) HasTemplate() bool {
+	if x == nil {
+		return false
+	}
+	return x.Template != nil
+}
+
+func (x *UTMTemplateResponse) ClearTemplate() {
+	x.Template = nil
+}
+
+type UTMTemplateResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Template *UTM
+}
+
+func (b0 UTMTemplateResponse_builder) Build() *UTMTemplateResponse {
+	m0 := &UTMTemplateResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.Template = b.Template
+	return m0
+}
+
+type SetLinkPasswordRequest struct {
+	state         protoimpl.MessageState `protogen:"hybrid.v1"`
+	UserId        *string                `protobuf:"bytes,1,opt,name=user_id,json=userId" json:"user_id,omitempty"`
+	ShortUrlId    *string                `protobuf:"bytes,2,opt,name=short_url_id,json=shortUrlId" json:"short_url_id,omitempty"`
+	Password      *string                `protobuf:"bytes,3,opt,name=password" json:"password,omitempty"` // empty removes the protection
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetLinkPasswordRequest) Reset() {
+	*x = SetLinkPasswordRequest{}
+	mi := &file_proto_shortugo_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetLinkPasswordRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetLinkPasswordRequest) ProtoMessage() {}
+
+func (x *SetLinkPasswordRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortugo_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *SetLinkPasswordRequest) GetUserId() string {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return ""
+}
+
+func (x *SetLinkPasswordRequest) GetShortUrlId() string {
+	if x != nil && x.ShortUrlId != nil {
+		return *x.ShortUrlId
+	}
+	return ""
+}
+
+func (x *SetLinkPasswordRequest) GetPassword() string {
+	if x != nil && x.Password != nil {
+		return *x.Password
+	}
+	return ""
+}
+
+func (x *SetLinkPasswordRequest) SetUserId(v string) {
+	x.UserId = &v
+}
+
+func (x *SetLinkPasswordRequest) SetShortUrlId(v string) {
+	x.ShortUrlId = &v
+}
+
+func (x *SetLinkPasswordRequest) SetPassword(v string) {
+	x.Password = &v
+}
+
+func (x *SetLinkPasswordRequest) HasUserId() bool {
+	if x == nil {
+		return false
+	}
+	return x.UserId != nil
+}
+
+func (x *SetLinkPasswordRequest) HasShortUrlId() bool {
+	if x == nil {
+		return false
+	}
+	return x.ShortUrlId != nil
+}
+
+func (x *SetLinkPasswordRequest) HasPassword() bool {
+	if x == nil {
+		return false
+	}
+	return x.Password != nil
+}
+
+func (x *SetLinkPasswordRequest) ClearUserId() {
+	x.UserId = nil
+}
+
+func (x *SetLinkPasswordRequest) ClearShortUrlId() {
+	x.ShortUrlId = nil
+}
+
+func (x *SetLinkPasswordRequest) ClearPassword() {
+	x.Password = nil
+}
+
+type SetLinkPasswordRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	UserId     *string
+	ShortUrlId *string
+	Password   *string
+}
+
+func (b0 SetLinkPasswordRequest_builder) Build() *SetLinkPasswordRequest {
+	m0 := &SetLinkPasswordRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.UserId = b.UserId
+	x.ShortUrlId = b.ShortUrlId
+	x.Password = b.Password
+	return m0
+}
+
+type SetLinkPasswordResponse struct {
+	state         protoimpl.MessageState `protogen:"hybrid.v1"`
+	Protected     *bool                  `protobuf:"varint,1,opt,name=protected" json:"protected,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetLinkPasswordResponse) Reset() {
+	*x = SetLinkPasswordResponse{}
+	mi := &file_proto_shortugo_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetLinkPasswordResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetLinkPasswordResponse) ProtoMessage() {}
+
+func (x *SetLinkPasswordResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortugo_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *SetLinkPasswordResponse) GetProtected() bool {
+	if x != nil && x.Protected != nil {
+		return *x.Protected
+	}
+	return false
+}
+
+func (x *SetLinkPasswordResponse) SetProtected(v bool) {
+	x.Protected = &v
+}
+
+func (x *SetLinkPasswordResponse) HasProtected() bool {
+	if x == nil {
+		return false
+	}
+	return x.Protected != nil
+}
+
+func (x *SetLinkPasswordResponse) ClearProtected() {
+	x.Protected = nil
+}
+
+type SetLinkPasswordResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Protected *bool
+}
+
+func (b0 SetLinkPasswordResponse_builder) Build() *SetLinkPasswordResponse {
+	m0 := &SetLinkPasswordResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.Protected = b.Protected
+	return m0
+}
+
+type GetLinkRulesRequest struct {
+	state         protoimpl.MessageState `protogen:"hybrid.v1"`
+	UserId        *string                `protobuf:"bytes,1,opt,name=user_id,json=userId" json:"user_id,omitempty"`
+	ShortUrlId    *string                `protobuf:"bytes,2,opt,name=short_url_id,json=shortUrlId" json:"short_url_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLinkRulesRequest) Reset() {
+	*x = GetLinkRulesRequest{}
+	mi := &file_proto_shortugo_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLinkRulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLinkRulesRequest) ProtoMessage() {}
+
+func (x *GetLinkRulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortugo_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *GetLinkRulesRequest) GetUserId() string {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return ""
+}
+
+func (x *GetLinkRulesRequest) GetShortUrlId() string {
+	if x != nil && x.ShortUrlId != nil {
+		return *x.ShortUrlId
+	}
+	return ""
+}
+
+func (x *GetLinkRulesRequest) SetUserId(v string) {
+	x.UserId = &v
+}
+
+func (x *GetLinkRulesRequest) SetShortUrlId(v string) {
+	x.ShortUrlId = &v
+}
+
+func (x *GetLinkRulesRequest) HasUserId() bool {
+	if x == nil {
+		return false
+	}
+	return x.UserId != nil
+}
+
+func (x *GetLinkRulesRequest) HasShortUrlId() bool {
+	if x == nil {
+		return false
+	}
+	return x.ShortUrlId != nil
+}
+
+func (x *GetLinkRulesRequest) ClearUserId() {
+	x.UserId = nil
+}
+
+func (x *GetLinkRulesRequest) ClearShortUrlId() {
+	x.ShortUrlId = nil
+}
+
+type GetLinkRulesRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	UserId     *string
+	ShortUrlId *string
+}
+
+func (b0 GetLinkRulesRequest_builder) Build() *GetLinkRulesRequest {
+	m0 := &GetLinkRulesRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.UserId = b.UserId
+	x.ShortUrlId = b.ShortUrlId
+	return m0
+}
+
+type SetLinkRulesRequest struct {
+	state         protoimpl.MessageState `protogen:"hybrid.v1"`
+	UserId        *string                `protobuf:"bytes,1,opt,name=user_id,json=userId" json:"user_id,omitempty"`
+	ShortUrlId    *string                `protobuf:"bytes,2,opt,name=short_url_id,json=shortUrlId" json:"short_url_id,omitempty"`
+	Rules         []*RoutingRule         `protobuf:"bytes,3,rep,name=rules" json:"rules,omitempty"` // empty removes the rules
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetLinkRulesRequest) Reset() {
+	*x = SetLinkRulesRequest{}
+	mi := &file_proto_shortugo_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetLinkRulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetLinkRulesRequest) ProtoMessage() {}
+
+func (x *SetLinkRulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortugo_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *SetLinkRulesRequest) GetUserId() string {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return ""
+}
+
+func (x *SetLinkRulesRequest) GetShortUrlId() string {
+	if x != nil && x.ShortUrlId != nil {
+		return *x.ShortUrlId
+	}
+	return ""
+}
+
+func (x *SetLinkRulesRequest) GetRules() []*RoutingRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+func (x *SetLinkRulesRequest) SetUserId(v string) {
+	x.UserId = &v
+}
+
+func (x *SetLinkRulesRequest) SetShortUrlId(v string) {
+	x.ShortUrlId = &v
+}
+
+func (x *SetLinkRulesRequest) SetRules(v []*RoutingRule) {
+	x.Rules = v
+}
+
+func (x *SetLinkRulesRequest) HasUserId() bool {
+	if x == nil {
+		return false
+	}
+	return x.UserId != nil
+}
+
+func (x *SetLinkRulesRequest) HasShortUrlId() bool {
+	if x == nil {
+		return false
+	}
+	return x.ShortUrlId != nil
+}
+
+func (x *SetLinkRulesRequest) ClearUserId() {
+	x.UserId = nil
+}
+
+func (x *SetLinkRulesRequest) ClearShortUrlId() {
+	x.ShortUrlId = nil
+}
+
+type SetLinkRulesRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	UserId     *string
+	ShortUrlId *string
+	Rules      []*RoutingRule
+}
+
+func (b0 SetLinkRulesRequest_builder) Build() *SetLinkRulesRequest {
+	m0 := &SetLinkRulesRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.UserId = b.UserId
+	x.ShortUrlId = b.ShortUrlId
+	x.Rules = b.Rules
+	return m0
+}
+
+type LinkRulesResponse struct {
+	state         protoimpl.MessageState `protogen:"hybrid.v1"`
+	Rules         []*RoutingRule         `protobuf:"bytes,1,rep,name=rules" json:"rules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LinkRulesResponse) Reset() {
+	*x = LinkRulesResponse{}
+	mi := &file_proto_shortugo_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LinkRulesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LinkRulesResponse) ProtoMessage() {}
+
+func (x *LinkRulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortugo_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *LinkRulesResponse) GetRules() []*RoutingRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+func (x *LinkRulesResponse) SetRules(v []*RoutingRule) {
+	x.Rules = v
+}
+
+type LinkRulesResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Rules []*RoutingRule
+}
+
+func (b0 LinkRulesResponse_builder) Build() *LinkRulesResponse {
+	m0 := &LinkRulesResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.Rules = b.Rules
+	return m0
+}
+
+type GetLinkVariantsRequest struct {
+	state         protoimpl.MessageState `protogen:"hybrid.v1"`
+	UserId        *string                `protobuf:"bytes,1,opt,name=user_id,json=userId" json:"user_id,omitempty"`
+	ShortUrlId    *string                `protobuf:"bytes,2,opt,name=short_url_id,json=shortUrlId" json:"short_url_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLinkVariantsRequest) Reset() {
+	*x = GetLinkVariantsRequest{}
+	mi := &file_proto_shortugo_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLinkVariantsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLinkVariantsRequest) ProtoMessage() {}
+
+func (x *GetLinkVariantsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortugo_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *GetLinkVariantsRequest) GetUserId() string {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return ""
+}
+
+func (x *GetLinkVariantsRequest) GetShortUrlId() string {
+	if x != nil && x.ShortUrlId != nil {
+		return *x.ShortUrlId
+	}
+	return ""
+}
+
+func (x *GetLinkVariantsRequest) SetUserId(v string) {
+	x.UserId = &v
+}
+
+func (x *GetLinkVariantsRequest) SetShortUrlId(v string) {
+	x.ShortUrlId = &v
+}
+
+func (x *GetLinkVariantsRequest) HasUserId() bool {
+	if x == nil {
+		return false
+	}
+	return x.UserId != nil
+}
+
+func (x *GetLinkVariantsRequest) HasShortUrlId() bool {
+	if x == nil {
+		return false
+	}
+	return x.ShortUrlId != nil
+}
+
+func (x *GetLinkVariantsRequest) ClearUserId() {
+	x.UserId = nil
+}
+
+func (x *GetLinkVariantsRequest) ClearShortUrlId() {
+	x.ShortUrlId = nil
+}
+
+type GetLinkVariantsRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	UserId     *string
+	ShortUrlId *string
+}
+
+func (b0 GetLinkVariantsRequest_builder) Build() *GetLinkVariantsRequest {
+	m0 := &GetLinkVariantsRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.UserId = b.UserId
+	x.ShortUrlId = b.ShortUrlId
+	return m0
+}
+
+type SetLinkVariantsRequest struct {
+	state         protoimpl.MessageState `protogen:"hybrid.v1"`
+	UserId        *string                `protobuf:"bytes,1,opt,name=user_id,json=userId" json:"user_id,omitempty"`
+	ShortUrlId    *string                `protobuf:"bytes,2,opt,name=short_url_id,json=shortUrlId" json:"short_url_id,omitempty"`
+	Variants      []*Variant             `protobuf:"bytes,3,rep,name=variants" json:"variants,omitempty"` // empty ends the A/B test
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetLinkVariantsRequest) Reset() {
+	*x = SetLinkVariantsRequest{}
+	mi := &file_proto_shortugo_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetLinkVariantsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetLinkVariantsRequest) ProtoMessage() {}
+
+func (x *SetLinkVariantsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortugo_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *SetLinkVariantsRequest) GetUserId() string {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return ""
+}
+
+func (x *SetLinkVariantsRequest) GetShortUrlId() string {
+	if x != nil && x.ShortUrlId != nil {
+		return *x.ShortUrlId
+	}
+	return ""
+}
+
+func (x *SetLinkVariantsRequest) GetVariants() []*Variant {
+	if x != nil {
+		return x.Variants
+	}
+	return nil
+}
+
+func (x *SetLinkVariantsRequest) SetUserId(v string) {
+	x.UserId = &v
+}
+
+func (x *SetLinkVariantsRequest) SetShortUrlId(v string) {
+	x.ShortUrlId = &v
+}
+
+func (x *SetLinkVariantsRequest) SetVariants(v []*Variant) {
+	x.Variants = v
+}
+
+func (x *SetLinkVariantsRequest) HasUserId() bool {
+	if x == nil {
+		return false
+	}
+	return x.UserId != nil
+}
+
+func (x *SetLinkVariantsRequest) HasShortUrlId() bool {
+	if x == nil {
+		return false
+	}
+	return x.ShortUrlId != nil
+}
+
+func (x *SetLinkVariantsRequest) ClearUserId() {
+	x.UserId = nil
+}
+
+func (x *SetLinkVariantsRequest) ClearShortUrlId() {
+	x.ShortUrlId = nil
+}
+
+type SetLinkVariantsRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	UserId     *string
+	ShortUrlId *string
+	Variants   []*Variant
+}
+
+func (b0 SetLinkVariantsRequest_builder) Build() *SetLinkVariantsRequest {
+	m0 := &SetLinkVariantsRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.UserId = b.UserId
+	x.ShortUrlId = b.ShortUrlId
+	x.Variants = b.Variants
+	return m0
+}
+
+type LinkVariantsResponse struct {
+	state         protoimpl.MessageState `protogen:"hybrid.v1"`
+	Variants      []*Variant             `protobuf:"bytes,1,rep,name=variants" json:"variants,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LinkVariantsResponse) Reset() {
+	*x = LinkVariantsResponse{}
+	mi := &file_proto_shortugo_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LinkVariantsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LinkVariantsResponse) ProtoMessage() {}
+
+func (x *LinkVariantsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortugo_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *LinkVariantsResponse) GetVariants() []*Variant {
+	if x != nil {
+		return x.Variants
+	}
+	return nil
+}
+
+func (x *LinkVariantsResponse) SetVariants(v []*Variant) {
+	x.Variants = v
+}
+
+type LinkVariantsResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Variants []*Variant
+}
+
+func (b0 LinkVariantsResponse_builder) Build() *LinkVariantsResponse {
+	m0 := &LinkVariantsResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.Variants = b.Variants
+	return m0
+}
+
+type LinkTagsRequest struct {
+	state         protoimpl.MessageState `protogen:"hybrid.v1"`
+	UserId        *string                `protobuf:"bytes,1,opt,name=user_id,json=userId" json:"user_id,omitempty"`
+	ShortUrlId    *string                `protobuf:"bytes,2,opt,name=short_url_id,json=shortUrlId" json:"short_url_id,omitempty"`
+	Tags          []string               `protobuf:"bytes,3,rep,name=tags" json:"tags,omitempty"` // trimmed and lowercased; up to 32 per link
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LinkTagsRequest) Reset() {
+	*x = LinkTagsRequest{}
+	mi := &file_proto_shortugo_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LinkTagsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LinkTagsRequest) ProtoMessage() {}
+
+func (x *LinkTagsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortugo_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *LinkTagsRequest) GetUserId() string {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return ""
+}
+
+func (x *LinkTagsRequest) GetShortUrlId() string {
+	if x != nil && x.ShortUrlId != nil {
+		return *x.ShortUrlId
+	}
+	return ""
+}
+
+func (x *LinkTagsRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *LinkTagsRequest) SetUserId(v string) {
+	x.UserId = &v
+}
+
+func (x *LinkTagsRequest) SetShortUrlId(v string) {
+	x.ShortUrlId = &v
+}
+
+func (x *LinkTagsRequest) SetTags(v []string) {
+	x.Tags = v
+}
+
+func (x *LinkTagsRequest) HasUserId() bool {
+	if x == nil {
+		return false
+	}
+	return x.UserId != nil
+}
+
+func (x *LinkTagsRequest) HasShortUrlId() bool {
+	if x == nil {
+		return false
+	}
+	return x.ShortUrlId != nil
+}
+
+func (x *LinkTagsRequest) ClearUserId() {
+	x.UserId = nil
+}
+
+func (x *LinkTagsRequest) ClearShortUrlId() {
+	x.ShortUrlId = nil
+}
+
+type LinkTagsRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	UserId     *string
+	ShortUrlId *string
+	Tags       []string
+}
+
+func (b0 LinkTagsRequest_builder) Build() *LinkTagsRequest {
+	m0 := &LinkTagsRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.UserId = b.UserId
+	x.ShortUrlId = b.ShortUrlId
+	x.Tags = b.Tags
+	return m0
+}
+
+type SetLinkFolderRequest struct {
+	state         protoimpl.MessageState `protogen:"hybrid.v1"`
+	UserId        *string                `protobuf:"bytes,1,opt,name=user_id,json=userId" json:"user_id,omitempty"`
+	ShortUrlId    *string                `protobuf:"bytes,2,opt,name=short_url_id,json=shortUrlId" json:"short_url_id,omitempty"`
+	Folder        *string                `protobuf:"bytes,3,opt,name=folder" json:"folder,omitempty"` // empty takes the link out of its folder
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetLinkFolderRequest) Reset() {
+	*x = SetLinkFolderRequest{}
+	mi := &file_proto_shortugo_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetLinkFolderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetLinkFolderRequest) ProtoMessage() {}
+
+func (x *SetLinkFolderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortugo_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *SetLinkFolderRequest) GetUserId() string {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return ""
+}
+
+func (x *SetLinkFolderRequest) GetShortUrlId() string {
+	if x != nil && x.ShortUrlId != nil {
+		return *x.ShortUrlId
+	}
+	return ""
+}
+
+func (x *SetLinkFolderRequest) GetFolder() string {
+	if x != nil && x.Folder != nil {
+		return *x.Folder
+	}
+	return ""
+}
+
+func (x *SetLinkFolderRequest) SetUserId(v string) {
+	x.UserId = &v
+}
+
+func (x *SetLinkFolderRequest) SetShortUrlId(v string) {
+	x.ShortUrlId = &v
+}
+
+func (x *SetLinkFolderRequest) SetFolder(v string) {
+	x.Folder = &v
+}
+
+func (x *SetLinkFolderRequest) HasUserId() bool {
+	if x == nil {
+		return false
+	}
+	return x.UserId != nil
+}
+
+func (x *SetLinkFolderRequest) HasShortUrlId() bool {
+	if x == nil {
+		return false
+	}
+	return x.ShortUrlId != nil
+}
+
+func (x *SetLinkFolderRequest) HasFolder() bool {
+	if x == nil {
+		return false
+	}
+	return x.Folder != nil
+}
+
+func (x *SetLinkFolderRequest) ClearUserId() {
+	x.UserId = nil
+}
+
+func (x *SetLinkFolderRequest) ClearShortUrlId() {
+	x.ShortUrlId = nil
+}
+
+func (x *SetLinkFolderRequest) ClearFolder() {
+	x.Folder = nil
+}
+
+type SetLinkFolderRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	UserId     *string
+	ShortUrlId *string
+	Folder     *string
+}
+
+func (b0 SetLinkFolderRequest_builder) Build() *SetLinkFolderRequest {
+	m0 := &SetLinkFolderRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.UserId = b.UserId
+	x.ShortUrlId = b.ShortUrlId
+	x.Folder = b.Folder
+	return m0
+}
+
+type LinkLabelsResponse struct {
+	state         protoimpl.MessageState `protogen:"hybrid.v1"`
+	Tags          []string               `protobuf:"bytes,1,rep,name=tags" json:"tags,omitempty"`
+	Folder        *string                `protobuf:"bytes,2,opt,name=folder" json:"folder,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LinkLabelsResponse) Reset() {
+	*x = LinkLabelsResponse{}
+	mi := &file_proto_shortugo_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LinkLabelsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LinkLabelsResponse) ProtoMessage() {}
+
+func (x *LinkLabelsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortugo_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *LinkLabelsResponse) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *LinkLabelsResponse) GetFolder() string {
+	if x != nil && x.Folder != nil {
+		return *x.Folder
+	}
+	return ""
+}
+
+func (x *LinkLabelsResponse) SetTags(v []string) {
+	x.Tags = v
+}
+
+func (x *LinkLabelsResponse) SetFolder(v string) {
+	x.Folder = &v
+}
+
+func (x *LinkLabelsResponse) HasFolder() bool {
+	if x == nil {
+		return false
+	}
+	return x.Folder != nil
+}
+
+func (x *LinkLabelsResponse) ClearFolder() {
+	x.Folder = nil
+}
+
+type LinkLabelsResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Tags   []string
+	Folder *string
+}
+
+func (b0 LinkLabelsResponse_builder) Build() *LinkLabelsResponse {
+	m0 := &LinkLabelsResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.Tags = b.Tags
+	x.Folder = b.Folder
+	return m0
+}
+
+type DeleteUserURLsRequest struct {
+	state         protoimpl.MessageState `protogen:"hybrid.v1"`
+	UserId        *string                `protobuf:"bytes,1,opt,name=user_id,json=userId" json:"user_id,omitempty"`
+	ShortUrlIds   []string               `protobuf:"bytes,2,rep,name=short_url_ids,json=shortUrlIds" json:"short_url_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteUserURLsRequest) Reset() {
+	*x = DeleteUserURLsRequest{}
+	mi := &file_proto_shortugo_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteUserURLsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteUserURLsRequest) ProtoMessage() {}
+
+func (x *DeleteUserURLsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortugo_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *DeleteUserURLsRequest) GetUserId() string {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return ""
+}
+
+func (x *DeleteUserURLsRequest) GetShortUrlIds() []string {
+	if x != nil {
+		return x.ShortUrlIds
+	}
+	return nil
+}
+
+func (x *DeleteUserURLsRequest) SetUserId(v string) {
+	x.UserId = &v
+}
+
+func (x *DeleteUserURLsRequest) SetShortUrlIds(v []string) {
+	x.ShortUrlIds = v
+}
+
+func (x *DeleteUserURLsRequest) HasUserId() bool {
+	if x == nil {
+		return false
+	}
+	return x.UserId != nil
+}
+
+func (x *DeleteUserURLsRequest) ClearUserId() {
+	x.UserId = nil
+}
+
+type DeleteUserURLsRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	UserId      *string
+	ShortUrlIds []string
+}
+
+func (b0 DeleteUserURLsRequest_builder) Build() *DeleteUserURLsRequest {
+	m0 := &DeleteUserURLsRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.UserId = b.UserId
+	x.ShortUrlIds = b.ShortUrlIds
+	return m0
+}
+
+type DeleteUserURLsResponse struct {
+	state         protoimpl.MessageState `protogen:"hybrid.v1"`
+	Success       *bool                  `protobuf:"varint,1,opt,name=success" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteUserURLsResponse) Reset() {
+	*x = DeleteUserURLsResponse{}
+	mi := &file_proto_shortugo_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteUserURLsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteUserURLsResponse) ProtoMessage() {}
+
+func (x *DeleteUserURLsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortugo_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *DeleteUserURLsResponse) GetSuccess() bool {
+	if x != nil && x.Success != nil {
+		return *x.Success
+	}
+	return false
+}
+
+func (x *DeleteUserURLsResponse) SetSuccess(v bool) {
+	x.Success = &v
+}
+
+func (x *DeleteUserURLsResponse) HasSuccess() bool {
+	if x == nil {
+		return false
+	}
+	return x.Success != nil
+}
+
+func (x *DeleteUserURLsResponse) ClearSuccess() {
+	x.Success = nil
+}
+
+type DeleteUserURLsResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Success *bool
+}
+
+func (b0 DeleteUserURLsResponse_builder) Build() *DeleteUserURLsResponse {
+	m0 := &DeleteUserURLsResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.Success = b.Success
+	return m0
+}
+
+type HealthCheckRequest struct {
+	state         protoimpl.MessageState `protogen:"hybrid.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HealthCheckRequest) Reset() {
+	*x = HealthCheckRequest{}
+	mi := &file_proto_shortugo_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HealthCheckRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthCheckRequest) ProtoMessage() {}
+
+func (x *HealthCheckRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortugo_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+type HealthCheckRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+}
+
+func (b0 HealthCheckRequest_builder) Build() *HealthCheckRequest {
+	m0 := &HealthCheckRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	return m0
+}
+
+type HealthCheckResponse struct {
+	state         protoimpl.MessageState `protogen:"hybrid.v1"`
+	Status        *string                `protobuf:"bytes,1,opt,name=status" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HealthCheckResponse) Reset() {
+	*x = HealthCheckResponse{}
+	mi := &file_proto_shortugo_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HealthCheckResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthCheckResponse) ProtoMessage() {}
+
+func (x *HealthCheckResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortugo_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *HealthCheckResponse) GetStatus() string {
+	if x != nil && x.Status != nil {
+		return *x.Status
+	}
+	return ""
+}
+
+func (x *HealthCheckResponse) SetStatus(v string) {
+	x.Status = &v
+}
+
+func (x *HealthCheckResponse) HasStatus() bool {
+	if x == nil {
+		return false
+	}
+	return x.Status != nil
+}
+
+func (x *HealthCheckResponse) ClearStatus() {
+	x.Status = nil
+}
+
+type HealthCheckResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Status *string
+}
+
+func (b0 HealthCheckResponse_builder) Build() *HealthCheckResponse {
+	m0 := &HealthCheckResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.Status = b.Status
+	return m0
+}
+
+type PingRequest struct {
+	state         protoimpl.MessageState `protogen:"hybrid.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PingRequest) Reset() {
+	*x = PingRequest{}
+	mi := &file_proto_shortugo_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PingRequest) ProtoMessage() {}
+
+func (x *PingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortugo_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+type PingRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+}
+
+func (b0 PingRequest_builder) Build() *PingRequest {
+	m0 := &PingRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	return m0
+}
+
+type PingResponse struct {
+	state         protoimpl.MessageState `protogen:"hybrid.v1"`
+	Status        *string                `protobuf:"bytes,1,opt,name=status" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PingResponse) Reset() {
+	*x = PingResponse{}
+	mi := &file_proto_shortugo_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PingResponse) ProtoMessage() {}
+
+func (x *PingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortugo_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *PingResponse) GetStatus() string {
+	if x != nil && x.Status != nil {
+		return *x.Status
+	}
+	return ""
+}
+
+func (x *PingResponse) SetStatus(v string) {
+	x.Status = &v
+}
+
+func (x *PingResponse) HasStatus() bool {
+	if x == nil {
+		return false
+	}
+	return x.Status != nil
+}
+
+func (x *PingResponse) ClearStatus() {
+	x.Status = nil
+}
+
+type PingResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Status *string
+}
+
+func (b0 PingResponse_builder) Build() *PingResponse {
+	m0 := &PingResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.Status = b.Status
+	return m0
+}
+
+type StatsRequest struct {
+	state         protoimpl.MessageState `protogen:"hybrid.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatsRequest) Reset() {
+	*x = StatsRequest{}
+	mi := &file_proto_shortugo_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatsRequest) ProtoMessage() {}
+
+func (x *StatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortugo_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+type StatsRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+}
+
+func (b0 StatsRequest_builder) Build() *StatsRequest {
+	m0 := &StatsRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	return m0
+}
+
+type StatsResponse struct {
+	state         protoimpl.MessageState `protogen:"hybrid.v1"`
+	UrlCount      *int64                 `protobuf:"varint,1,opt,name=url_count,json=urlCount" json:"url_count,omitempty"`
+	UserCount     *int64                 `protobuf:"varint,2,opt,name=user_count,json=userCount" json:"user_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatsResponse) Reset() {
+	*x = StatsResponse{}
+	mi := &file_proto_shortugo_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatsResponse) ProtoMessage() {}
+
+func (x *StatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortugo_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *StatsResponse) GetUrlCount() int64 {
+	if x != nil && x.UrlCount != nil {
+		return *x.UrlCount
+	}
+	return 0
+}
+
+func (x *StatsResponse) GetUserCount() int64 {
+	if x != nil && x.UserCount != nil {
+		return *x.UserCount
+	}
+	return 0
+}
+
+func (x *StatsResponse) SetUrlCount(v int64) {
+	x.UrlCount = &v
+}
+
+func (x *StatsResponse) SetUserCount(v int64) {
+	x.UserCount = &v
+}
+
+func (x *StatsResponse) HasUrlCount() bool {
+	if x == nil {
+		return false
+	}
+	return x.UrlCount != nil
+}
+
+func (x *StatsResponse) HasUserCount() bool {
+	if x == nil {
+		return false
+	}
+	return x.UserCount != nil
+}
+
+func (x *StatsResponse) ClearUrlCount() {
+	x.UrlCount = nil
+}
+
+func (x *StatsResponse) ClearUserCount() {
+	x.UserCount = nil
+}
+
+type StatsResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	UrlCount  *int64
+	UserCount *int64
+}
+
+func (b0 StatsResponse_builder) Build() *StatsResponse {
+	m0 := &StatsResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.UrlCount = b.UrlCount
+	x.UserCount = b.UserCount
+	return m0
+}
+
+type PurgeDeletedRequest struct {
+	state         protoimpl.MessageState `protogen:"hybrid.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PurgeDeletedRequest) Reset() {
+	*x = PurgeDeletedRequest{}
+	mi := &file_proto_shortugo_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PurgeDeletedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurgeDeletedRequest) ProtoMessage() {}
+
+func (x *PurgeDeletedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortugo_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+type PurgeDeletedRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+}
+
+func (b0 PurgeDeletedRequest_builder) Build() *PurgeDeletedRequest {
+	m0 := &PurgeDeletedRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	return m0
+}
+
+type PurgeDeletedResponse struct {
+	state         protoimpl.MessageState `protogen:"hybrid.v1"`
+	Purged        *int64                 `protobuf:"varint,1,opt,name=purged" json:"purged,omitempty"` // number of deleted links removed for good
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PurgeDeletedResponse) Reset() {
+	*x = PurgeDeletedResponse{}
+	mi := &file_proto_shortugo_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PurgeDeletedResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurgeDeletedResponse) ProtoMessage() {}
+
+func (x *PurgeDeletedResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortugo_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *PurgeDeletedResponse) GetPurged() int64 {
+	if x != nil && x.Purged != nil {
+		return *x.Purged
+	}
+	return 0
+}
+
+func (x *PurgeDeletedResponse) SetPurged(v int64) {
+	x.Purged = &v
+}
+
+func (x *PurgeDeletedResponse) HasPurged() bool {
+	if x == nil {
+		return false
+	}
+	return x.Purged != nil
+}
+
+func (x *PurgeDeletedResponse) ClearPurged() {
+	x.Purged = nil
+}
+
+type PurgeDeletedResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Purged *int64
+}
+
+func (b0 PurgeDeletedResponse_builder) Build() *PurgeDeletedResponse {
+	m0 := &PurgeDeletedResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.Purged = b.Purged
+	return m0
+}
+
+type BlockDomainRequest struct {
+	state         protoimpl.MessageState `protogen:"hybrid.v1"`
+	Domain        *string                `protobuf:"bytes,2,opt,name=domain" json:"domain,omitempty"` // host name or IP address; its subdomains are blocked too
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlockDomainRequest) Reset() {
+	*x = BlockDomainRequest{}
+	mi := &file_proto_shortugo_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlockDomainRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockDomainRequest) ProtoMessage() {}
+
+func (x *BlockDomainRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortugo_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *BlockDomainRequest) GetDomain() string {
+	if x != nil && x.Domain != nil {
+		return *x.Domain
+	}
+	return ""
+}
+
+func (x *BlockDomainRequest) SetDomain(v string) {
+	x.Domain = &v
+}
+
+func (x *BlockDomainRequest) HasDomain() bool {
+	if x == nil {
+		return false
+	}
+	return x.Domain != nil
+}
+
+func (x *BlockDomainRequest) ClearDomain() {
+	x.Domain = nil
+}
+
+type BlockDomainRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Domain *string
+}
+
+func (b0 BlockDomainRequest_builder) Build() *BlockDomainRequest {
+	m0 := &BlockDomainRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.Domain = b.Domain
+	return m0
+}
+
+type ListBlockedDomainsRequest struct {
+	state         protoimpl.MessageState `protogen:"hybrid.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBlockedDomainsRequest) Reset() {
+	*x = ListBlockedDomainsRequest{}
+	mi := &file_proto_shortugo_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBlockedDomainsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBlockedDomainsRequest) ProtoMessage() {}
+
+func (x *ListBlockedDomainsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortugo_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+type ListBlockedDomainsRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+}
+
+func (b0 ListBlockedDomainsRequest_builder) Build() *ListBlockedDomainsRequest {
+	m0 := &ListBlockedDomainsRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	return m0
+}
+
+type BlockedDomainsResponse struct {
+	state         protoimpl.MessageState `protogen:"hybrid.v1"`
+	Domains       []string               `protobuf:"bytes,1,rep,name=domains" json:"domains,omitempty"` // all blocked domains in alphabetical order
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlockedDomainsResponse) Reset() {
+	*x = BlockedDomainsResponse{}
+	mi := &file_proto_shortugo_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlockedDomainsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockedDomainsResponse) ProtoMessage() {}
+
+func (x *BlockedDomainsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortugo_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *BlockedDomainsResponse) GetDomains() []string {
+	if x != nil {
+		return x.Domains
+	}
+	return nil
+}
+
+func (x *BlockedDomainsResponse) SetDomains(v []string) {
+	x.Domains = v
+}
+
+type BlockedDomainsResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Domains []string
+}
+
+func (b0 BlockedDomainsResponse_builder) Build() *BlockedDomainsResponse {
+	m0 := &BlockedDomainsResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.Domains = b.Domains
+	return m0
+}
+
+type GetLinkOwnerRequest struct {
+	state         protoimpl.MessageState `protogen:"hybrid.v1"`
+	ShortUrlId    *string                `protobuf:"bytes,2,opt,name=short_url_id,json=shortUrlId" json:"short_url_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLinkOwnerRequest) Reset() {
+	*x = GetLinkOwnerRequest{}
+	mi := &file_proto_shortugo_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLinkOwnerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLinkOwnerRequest) ProtoMessage() {}
+
+func (x *GetLinkOwnerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortugo_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *GetLinkOwnerRequest) GetShortUrlId() string {
+	if x != nil && x.ShortUrlId != nil {
+		return *x.ShortUrlId
+	}
+	return ""
+}
+
+func (x *GetLinkOwnerRequest) SetShortUrlId(v string) {
+	x.ShortUrlId = &v
+}
+
+func (x *GetLinkOwnerRequest) HasShortUrlId() bool {
+	if x == nil {
+		return false
+	}
+	return x.ShortUrlId != nil
+}
+
+func (x *GetLinkOwnerRequest) ClearShortUrlId() {
+	x.ShortUrlId = nil
+}
+
+type GetLinkOwnerRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	ShortUrlId *string
+}
+
+func (b0 GetLinkOwnerRequest_builder) Build() *GetLinkOwnerRequest {
+	m0 := &GetLinkOwnerRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.ShortUrlId = b.ShortUrlId
+	return m0
+}
+
+type GetLinkOwnerResponse struct {
+	state         protoimpl.MessageState `protogen:"hybrid.v1"`
+	ShortUrlId    *string                `protobuf:"bytes,1,opt,name=short_url_id,json=shortUrlId" json:"short_url_id,omitempty"`
+	UserId        *string                `protobuf:"bytes,2,opt,name=user_id,json=userId" json:"user_id,omitempty"`
+	OriginalUrl   *string                `protobuf:"bytes,3,opt,name=original_url,json=originalUrl" json:"original_url,omitempty"`
+	CreatedAt     *string                `protobuf:"bytes,4,opt,name=created_at,json=createdAt" json:"created_at,omitempty"` // RFC 3339
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLinkOwnerResponse) Reset() {
+	*x = GetLinkOwnerResponse{}
+	mi := &file_proto_shortugo_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLinkOwnerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLinkOwnerResponse) ProtoMessage() {}
+
+func (x *GetLinkOwnerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortugo_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *GetLinkOwnerResponse) GetShortUrlId() string {
+	if x != nil && x.ShortUrlId != nil {
+		return *x.ShortUrlId
+	}
+	return ""
+}
+
+func (x *GetLinkOwnerResponse) GetUserId() string {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return ""
+}
+
+func (x *GetLinkOwnerResponse) GetOriginalUrl() string {
+	if x != nil && x.OriginalUrl != nil {
+		return *x.OriginalUrl
+	}
+	return ""
+}
+
+func (x *GetLinkOwnerResponse) GetCreatedAt() string {
+	if x != nil && x.CreatedAt != nil {
+		return *x.CreatedAt
+	}
+	return ""
+}
+
+func (x *GetLinkOwnerResponse) SetShortUrlId(v string) {
+	x.ShortUrlId = &v
+}
+
+func (x *GetLinkOwnerResponse) SetUserId(v string) {
+	x.UserId = &v
+}
+
+func (x *GetLinkOwnerResponse) SetOriginalUrl(v string) {
+	x.OriginalUrl = &v
+}
+
+func (x *GetLinkOwnerResponse) SetCreatedAt(v string) {
+	x.CreatedAt = &v
+}
+
+func (x *GetLinkOwnerResponse) HasShortUrlId() bool {
+	if x == nil {
+		return false
+	}
+	return x.ShortUrlId != nil
+}
+
+func (x *GetLinkOwnerResponse) HasUserId() bool {
+	if x == nil {
+		return false
+	}
+	return x.UserId != nil
+}
+
+func (x *GetLinkOwnerResponse) HasOriginalUrl() bool {
+	if x == nil {
+		return false
+	}
+	return x.OriginalUrl != nil
+}
+
+func (x *GetLinkOwnerResponse) HasCreatedAt() bool {
+	if x == nil {
+		return false
+	}
+	return x.CreatedAt != nil
+}
+
+func (x *GetLinkOwnerResponse) ClearShortUrlId() {
+	x.ShortUrlId = nil
+}
+
+func (x *GetLinkOwnerResponse) ClearUserId() {
+	x.UserId = nil
+}
+
+func (x *GetLinkOwnerResponse) ClearOriginalUrl() {
+	x.OriginalUrl = nil
+}
+
+func (x *GetLinkOwnerResponse) ClearCreatedAt() {
+	x.CreatedAt = nil
+}
+
+type GetLinkOwnerResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	ShortUrlId  *string
+	UserId      *string
+	OriginalUrl *string
+	CreatedAt   *string
+}
+
+func (b0 GetLinkOwnerResponse_builder) Build() *GetLinkOwnerResponse {
+	m0 := &GetLinkOwnerResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.ShortUrlId = b.ShortUrlId
+	x.UserId = b.UserId
+	x.OriginalUrl = b.OriginalUrl
+	x.CreatedAt = b.CreatedAt
+	return m0
+}
+
+type AdminLink struct {
+	state         protoimpl.MessageState `protogen:"hybrid.v1"`
+	ShortUrlId    *string                `protobuf:"bytes,1,opt,name=short_url_id,json=shortUrlId" json:"short_url_id,omitempty"`
+	ShortUrl      *string                `protobuf:"bytes,2,opt,name=short_url,json=shortUrl" json:"short_url,omitempty"`
+	OriginalUrl   *string                `protobuf:"bytes,3,opt,name=original_url,json=originalUrl" json:"original_url,omitempty"`
+	UserId        *string                `protobuf:"bytes,4,opt,name=user_id,json=userId" json:"user_id,omitempty"`
+	CreatedAt     *string                `protobuf:"bytes,5,opt,name=created_at,json=createdAt" json:"created_at,omitempty"` // RFC 3339
+	Title         *string                `protobuf:"bytes,6,opt,name=title" json:"title,omitempty"`
+	Deleted       *bool                  `protobuf:"varint,7,opt,name=deleted" json:"deleted,omitempty"`     // deleted by its owner
+	Disabled      *bool                  `protobuf:"varint,8,opt,name=disabled" json:"disabled,omitempty"`   // disabled by an admin
+	Exhausted     *bool                  `protobuf:"varint,9,opt,name=exhausted" json:"exhausted,omitempty"` // no redirects left
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdminLink) Reset() {
+	*x = AdminLink{}
+	mi := &file_proto_shortugo_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdminLink) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminLink) ProtoMessage() {}
+
+func (x *AdminLink) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortugo_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *AdminLink) GetShortUrlId() string {
+	if x != nil && x.ShortUrlId != nil {
+		return *x.ShortUrlId
+	}
+	return ""
+}
+
+func (x *AdminLink) GetShortUrl() string {
+	if x != nil && x.ShortUrl != nil {
+		return *x.ShortUrl
+	}
+	return ""
+}
+
+func (x *AdminLink) GetOriginalUrl() string {
+	if x != nil && x.OriginalUrl != nil {
+		return *x.OriginalUrl
+	}
+	return ""
+}
+
+func (x *AdminLink) GetUserId() string {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return ""
+}
+
+func (x *AdminLink) GetCreatedAt() string {
+	if x != nil && x.CreatedAt != nil {
+		return *x.CreatedAt
+	}
+	return ""
+}
+
+func (x *AdminLink) GetTitle() string {
+	if x != nil && x.Title != nil {
+		return *x.Title
+	}
+	return ""
+}
+
+func (x *AdminLink) GetDeleted() bool {
+	if x != nil && x.Deleted != nil {
+		return *x.Deleted
+	}
+	return false
+}
+
+func (x *AdminLink) GetDisabled() bool {
+	if x != nil && x.Disabled != nil {
+		return *x.Disabled
+	}
+	return false
+}
+
+func (x *AdminLink) GetExhausted() bool {
+	if x != nil && x.Exhausted != nil {
+		return *x.Exhausted
+	}
+	return false
+}
+
+func (x *AdminLink) SetShortUrlId(v string) {
+	x.ShortUrlId = &v
+}
+
+func (x *AdminLink) SetShortUrl(v string) {
+	x.ShortUrl = &v
+}
+
+func (x *AdminLink) SetOriginalUrl(v string) {
+	x.OriginalUrl = &v
+}
+
+func (x *AdminLink) SetUserId(v string) {
+	x.UserId = &v
+}
+
+func (x *AdminLink) SetCreatedAt(v string) {
+	x.CreatedAt = &v
+}
+
+func (x *AdminLink) SetTitle(v string) {
+	x.Title = &v
+}
+
+func (x *AdminLink) SetDeleted(v bool) {
+	x.Deleted = &v
+}
+
+func (x *AdminLink) SetDisabled(v bool) {
+	x.Disabled = &v
+}
+
+func (x *AdminLink) SetExhausted(v bool) {
+	x.Exhausted = &v
+}
+
+func (x *AdminLink) HasShortUrlId() bool {
+	if x == nil {
+		return false
+	}
+	return x.ShortUrlId != nil
+}
+
+func (x *AdminLink) HasShortUrl() bool {
+	if x == nil {
+		return false
+	}
+	return x.ShortUrl != nil
+}
+
+func (x *AdminLink) HasOriginalUrl() bool {
+	if x == nil {
+		return false
+	}
+	return x.OriginalUrl != nil
+}
+
+func (x *AdminLink) HasUserId() bool {
+	if x == nil {
+		return false
+	}
+	return x.UserId != nil
+}
+
+func (x *AdminLink) HasCreatedAt() bool {
+	if x == nil {
+		return false
+	}
+	return x.CreatedAt != nil
+}
+
+func (x *AdminLink) HasTitle() bool {
+	if x == nil {
+		return false
+	}
+	return x.Title != nil
+}
+
+func (x *AdminLink) HasDeleted() bool {
+	if x == nil {
+		return false
 	}
-	return false
+	return x.Deleted != nil
 }
 
-func (x *DeleteUserURLsResponse) SetSuccess(v bool) {
-	x.Success = &v
+func (x *AdminLink) HasDisabled() bool {
+	if x == nil {
+		return false
+	}
+	return x.Disabled != nil
 }
 
-func (x *DeleteUserURLsResponse) HasSuccess() bool {
+func (x *AdminLink) HasExhausted() bool {
 	if x == nil {
 		return false
 	}
-	return x.Success != nil
+	return x.Exhausted != nil
 }
 
-func (x *DeleteUserURLsResponse) ClearSuccess() {
-	x.Success = nil
+func (x *AdminLink) ClearShortUrlId() {
+	x.ShortUrlId = nil
 }
 
-type DeleteUserURLsResponse_builder struct {
-	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+func (x *AdminLink) ClearShortUrl() {
+	x.ShortUrl = nil
+}
 
-	Success *bool
+func (x *AdminLink) ClearOriginalUrl() {
+	x.OriginalUrl = nil
 }
 
-func (b0 DeleteUserURLsResponse_builder) Build() *DeleteUserURLsResponse {
-	m0 := &DeleteUserURLsResponse{}
-	b, x := &b0, m0
-	_, _ = b, x
-	x.Success = b.Success
-	return m0
+func (x *AdminLink) ClearUserId() {
+	x.UserId = nil
 }
 
-type HealthCheckRequest struct {
-	state         protoimpl.MessageState `protogen:"hybrid.v1"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *AdminLink) ClearCreatedAt() {
+	x.CreatedAt = nil
 }
 
-func (x *HealthCheckRequest) Reset() {
-	*x = HealthCheckRequest{}
-	mi := &file_proto_shortugo_proto_msgTypes[11]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *AdminLink) ClearTitle() {
+	x.Title = nil
 }
 
-func (x *HealthCheckRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *AdminLink) ClearDeleted() {
+	x.Deleted = nil
 }
 
-func (*HealthCheckRequest) ProtoMessage() {}
+func (x *AdminLink) ClearDisabled() {
+	x.Disabled = nil
+}
 
-func (x *HealthCheckRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_shortugo_proto_msgTypes[11]
-	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
-	}
-	return mi.MessageOf(x)
+func (x *AdminLink) ClearExhausted() {
+	x.Exhausted = nil
 }
 
-type HealthCheckRequest_builder struct {
+type AdminLink_builder struct {
 	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
 
+	ShortUrlId  *string
+	ShortUrl    *string
+	OriginalUrl *string
+	UserId      *string
+	CreatedAt   *string
+	Title       *string
+	Deleted     *bool
+	Disabled    *bool
+	Exhausted   *bool
 }
 
-func (b0 HealthCheckRequest_builder) Build() *HealthCheckRequest {
-	m0 := &HealthCheckRequest{}
+func (b0 AdminLink_builder) Build() *AdminLink {
+	m0 := &AdminLink{}
 	b, x := &b0, m0
 	_, _ = b, x
+	x.ShortUrlId = b.ShortUrlId
+	x.ShortUrl = b.ShortUrl
+	x.OriginalUrl = b.OriginalUrl
+	x.UserId = b.UserId
+	x.CreatedAt = b.CreatedAt
+	x.Title = b.Title
+	x.Deleted = b.Deleted
+	x.Disabled = b.Disabled
+	x.Exhausted = b.Exhausted
 	return m0
 }
 
-type HealthCheckResponse struct {
+type AdminLinkRequest struct {
 	state         protoimpl.MessageState `protogen:"hybrid.v1"`
-	Status        *string                `protobuf:"bytes,1,opt,name=status" json:"status,omitempty"`
+	ShortUrlId    *string                `protobuf:"bytes,1,opt,name=short_url_id,json=shortUrlId" json:"short_url_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *HealthCheckResponse) Reset() {
-	*x = HealthCheckResponse{}
-	mi := &file_proto_shortugo_proto_msgTypes[12]
+func (x *AdminLinkRequest) Reset() {
+	*x = AdminLinkRequest{}
+	mi := &file_proto_shortugo_proto_msgTypes[47]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *HealthCheckResponse) String() string {
+func (x *AdminLinkRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HealthCheckResponse) ProtoMessage() {}
+func (*AdminLinkRequest) ProtoMessage() {}
 
-func (x *HealthCheckResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_shortugo_proto_msgTypes[12]
+func (x *AdminLinkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortugo_proto_msgTypes[47]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -928,63 +4656,65 @@ func (x *HealthCheckResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-func (x *HealthCheckResponse) GetStatus() string {
-	if x != nil && x.Status != nil {
-		return *x.Status
+func (x *AdminLinkRequest) GetShortUrlId() string {
+	if x != nil && x.ShortUrlId != nil {
+		return *x.ShortUrlId
 	}
 	return ""
 }
 
-func (x *HealthCheckResponse) SetStatus(v string) {
-	x.Status = &v
+func (x *AdminLinkRequest) SetShortUrlId(v string) {
+	x.ShortUrlId = &v
 }
 
-func (x *HealthCheckResponse) HasStatus() bool {
+func (x *AdminLinkRequest) HasShortUrlId() bool {
 	if x == nil {
 		return false
 	}
-	return x.Status != nil
+	return x.ShortUrlId != nil
 }
 
-func (x *HealthCheckResponse) ClearStatus() {
-	x.Status = nil
+func (x *AdminLinkRequest) ClearShortUrlId() {
+	x.ShortUrlId = nil
 }
 
-type HealthCheckResponse_builder struct {
+type AdminLinkRequest_builder struct {
 	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
 
-	Status *string
+	ShortUrlId *string
 }
 
-func (b0 HealthCheckResponse_builder) Build() *HealthCheckResponse {
-	m0 := &HealthCheckResponse{}
+func (b0 AdminLinkRequest_builder) Build() *AdminLinkRequest {
+	m0 := &AdminLinkRequest{}
 	b, x := &b0, m0
 	_, _ = b, x
-	x.Status = b.Status
+	x.ShortUrlId = b.ShortUrlId
 	return m0
 }
 
-type PingRequest struct {
+type DisableLinkRequest struct {
 	state         protoimpl.MessageState `protogen:"hybrid.v1"`
+	ShortUrlId    *string                `protobuf:"bytes,1,opt,name=short_url_id,json=shortUrlId" json:"short_url_id,omitempty"`
+	Reason        *string                `protobuf:"bytes,2,opt,name=reason" json:"reason,omitempty"` // recorded in the audit entry
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PingRequest) Reset() {
-	*x = PingRequest{}
-	mi := &file_proto_shortugo_proto_msgTypes[13]
+func (x *DisableLinkRequest) Reset() {
+	*x = DisableLinkRequest{}
+	mi := &file_proto_shortugo_proto_msgTypes[48]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PingRequest) String() string {
+func (x *DisableLinkRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PingRequest) ProtoMessage() {}
+func (*DisableLinkRequest) ProtoMessage() {}
 
-func (x *PingRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_shortugo_proto_msgTypes[13]
+func (x *DisableLinkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortugo_proto_msgTypes[48]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -995,108 +4725,88 @@ func (x *PingRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-type PingRequest_builder struct {
-	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
-
-}
-
-func (b0 PingRequest_builder) Build() *PingRequest {
-	m0 := &PingRequest{}
-	b, x := &b0, m0
-	_, _ = b, x
-	return m0
-}
-
-type PingResponse struct {
-	state         protoimpl.MessageState `protogen:"hybrid.v1"`
-	Status        *string                `protobuf:"bytes,1,opt,name=status" json:"status,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *DisableLinkRequest) GetShortUrlId() string {
+	if x != nil && x.ShortUrlId != nil {
+		return *x.ShortUrlId
+	}
+	return ""
 }
 
-func (x *PingResponse) Reset() {
-	*x = PingResponse{}
-	mi := &file_proto_shortugo_proto_msgTypes[14]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *DisableLinkRequest) GetReason() string {
+	if x != nil && x.Reason != nil {
+		return *x.Reason
+	}
+	return ""
 }
 
-func (x *PingResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *DisableLinkRequest) SetShortUrlId(v string) {
+	x.ShortUrlId = &v
 }
 
-func (*PingResponse) ProtoMessage() {}
-
-func (x *PingResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_shortugo_proto_msgTypes[14]
-	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
-	}
-	return mi.MessageOf(x)
+func (x *DisableLinkRequest) SetReason(v string) {
+	x.Reason = &v
 }
 
-func (x *PingResponse) GetStatus() string {
-	if x != nil && x.Status != nil {
-		return *x.Status
+func (x *DisableLinkRequest) HasShortUrlId() bool {
+	if x == nil {
+		return false
 	}
-	return ""
-}
-
-func (x *PingResponse) SetStatus(v string) {
-	x.Status = &v
+	return x.ShortUrlId != nil
 }
 
-func (x *PingResponse) HasStatus() bool {
+func (x *DisableLinkRequest) HasReason() bool {
 	if x == nil {
 		return false
 	}
-	return x.Status != nil
+	return x.Reason != nil
 }
 
-func (x *PingResponse) ClearStatus() {
-	x.Status = nil
+func (x *DisableLinkRequest) ClearShortUrlId() {
+	x.ShortUrlId = nil
 }
 
-type PingResponse_builder struct {
+func (x *DisableLinkRequest) ClearReason() {
+	x.Reason = nil
+}
+
+type DisableLinkRequest_builder struct {
 	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
 
-	Status *string
+	ShortUrlId *string
+	Reason     *string
 }
 
-func (b0 PingResponse_builder) Build() *PingResponse {
-	m0 := &PingResponse{}
+func (b0 DisableLinkRequest_builder) Build() *DisableLinkRequest {
+	m0 := &DisableLinkRequest{}
 	b, x := &b0, m0
 	_, _ = b, x
-	x.Status = b.Status
+	x.ShortUrlId = b.ShortUrlId
+	x.Reason = b.Reason
 	return m0
 }
 
-type StatsRequest struct {
+type AdminUserLinksRequest struct {
 	state         protoimpl.MessageState `protogen:"hybrid.v1"`
-	Ip            *string                `protobuf:"bytes,1,opt,name=ip" json:"ip,omitempty"` // IP address of the client, used for trusted subnet validation
+	UserId        *string                `protobuf:"bytes,1,opt,name=user_id,json=userId" json:"user_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *StatsRequest) Reset() {
-	*x = StatsRequest{}
-	mi := &file_proto_shortugo_proto_msgTypes[15]
+func (x *AdminUserLinksRequest) Reset() {
+	*x = AdminUserLinksRequest{}
+	mi := &file_proto_shortugo_proto_msgTypes[49]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StatsRequest) String() string {
+func (x *AdminUserLinksRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StatsRequest) ProtoMessage() {}
+func (*AdminUserLinksRequest) ProtoMessage() {}
 
-func (x *StatsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_shortugo_proto_msgTypes[15]
+func (x *AdminUserLinksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortugo_proto_msgTypes[49]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1107,65 +4817,64 @@ func (x *StatsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-func (x *StatsRequest) GetIp() string {
-	if x != nil && x.Ip != nil {
-		return *x.Ip
+func (x *AdminUserLinksRequest) GetUserId() string {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
 	}
 	return ""
 }
 
-func (x *StatsRequest) SetIp(v string) {
-	x.Ip = &v
+func (x *AdminUserLinksRequest) SetUserId(v string) {
+	x.UserId = &v
 }
 
-func (x *StatsRequest) HasIp() bool {
+func (x *AdminUserLinksRequest) HasUserId() bool {
 	if x == nil {
 		return false
 	}
-	return x.Ip != nil
+	return x.UserId != nil
 }
 
-func (x *StatsRequest) ClearIp() {
-	x.Ip = nil
+func (x *AdminUserLinksRequest) ClearUserId() {
+	x.UserId = nil
 }
 
-type StatsRequest_builder struct {
+type AdminUserLinksRequest_builder struct {
 	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
 
-	Ip *string
+	UserId *string
 }
 
-func (b0 StatsRequest_builder) Build() *StatsRequest {
-	m0 := &StatsRequest{}
+func (b0 AdminUserLinksRequest_builder) Build() *AdminUserLinksRequest {
+	m0 := &AdminUserLinksRequest{}
 	b, x := &b0, m0
 	_, _ = b, x
-	x.Ip = b.Ip
+	x.UserId = b.UserId
 	return m0
 }
 
-type StatsResponse struct {
+type AdminLinksResponse struct {
 	state         protoimpl.MessageState `protogen:"hybrid.v1"`
-	UrlCount      *int64                 `protobuf:"varint,1,opt,name=url_count,json=urlCount" json:"url_count,omitempty"`
-	UserCount     *int64                 `protobuf:"varint,2,opt,name=user_count,json=userCount" json:"user_count,omitempty"`
+	Links         []*AdminLink           `protobuf:"bytes,1,rep,name=links" json:"links,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *StatsResponse) Reset() {
-	*x = StatsResponse{}
-	mi := &file_proto_shortugo_proto_msgTypes[16]
+func (x *AdminLinksResponse) Reset() {
+	*x = AdminLinksResponse{}
+	mi := &file_proto_shortugo_proto_msgTypes[50]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StatsResponse) String() string {
+func (x *AdminLinksResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StatsResponse) ProtoMessage() {}
+func (*AdminLinksResponse) ProtoMessage() {}
 
-func (x *StatsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_shortugo_proto_msgTypes[16]
+func (x *AdminLinksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortugo_proto_msgTypes[50]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1176,63 +4885,28 @@ func (x *StatsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-func (x *StatsResponse) GetUrlCount() int64 {
-	if x != nil && x.UrlCount != nil {
-		return *x.UrlCount
-	}
-	return 0
-}
-
-func (x *StatsResponse) GetUserCount() int64 {
-	if x != nil && x.UserCount != nil {
-		return *x.UserCount
-	}
-	return 0
-}
-
-func (x *StatsResponse) SetUrlCount(v int64) {
-	x.UrlCount = &v
-}
-
-func (x *StatsResponse) SetUserCount(v int64) {
-	x.UserCount = &v
-}
-
-func (x *StatsResponse) HasUrlCount() bool {
-	if x == nil {
-		return false
-	}
-	return x.UrlCount != nil
-}
-
-func (x *StatsResponse) HasUserCount() bool {
-	if x == nil {
-		return false
+func (x *AdminLinksResponse) GetLinks() []*AdminLink {
+	if x != nil {
+		return x.Links
 	}
-	return x.UserCount != nil
-}
-
-func (x *StatsResponse) ClearUrlCount() {
-	x.UrlCount = nil
+	return nil
 }
 
-func (x *StatsResponse) ClearUserCount() {
-	x.UserCount = nil
+func (x *AdminLinksResponse) SetLinks(v []*AdminLink) {
+	x.Links = v
 }
 
-type StatsResponse_builder struct {
+type AdminLinksResponse_builder struct {
 	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
 
-	UrlCount  *int64
-	UserCount *int64
+	Links []*AdminLink
 }
 
-func (b0 StatsResponse_builder) Build() *StatsResponse {
-	m0 := &StatsResponse{}
+func (b0 AdminLinksResponse_builder) Build() *AdminLinksResponse {
+	m0 := &AdminLinksResponse{}
 	b, x := &b0, m0
 	_, _ = b, x
-	x.UrlCount = b.UrlCount
-	x.UserCount = b.UserCount
+	x.Links = b.Links
 	return m0
 }
 
@@ -1240,30 +4914,134 @@ var File_proto_shortugo_proto protoreflect.FileDescriptor
 
 const file_proto_shortugo_proto_rawDesc = "" +
 	"\n" +
-	"\x14proto/shortugo.proto\x12\bshortugo\x1a!google/protobuf/go_features.proto\"p\n" +
+	"\x14proto/shortugo.proto\x12\bshortugo\x1a\x1cgoogle/api/annotations.proto\x1a!google/protobuf/go_features.proto\"\xe3\x01\n" +
 	"\aURLPair\x12%\n" +
 	"\x0ecorrelation_id\x18\x01 \x01(\tR\rcorrelationId\x12!\n" +
 	"\foriginal_url\x18\x02 \x01(\tR\voriginalUrl\x12\x1b\n" +
-	"\tshort_url\x18\x03 \x01(\tR\bshortUrl\"L\n" +
+	"\tshort_url\x18\x03 \x01(\tR\bshortUrl\x12/\n" +
+	"\aoptions\x18\x04 \x01(\v2\x15.shortugo.LinkOptionsR\aoptions\x12\x12\n" +
+	"\x04tags\x18\x05 \x03(\tR\x04tags\x12\x16\n" +
+	"\x06folder\x18\x06 \x01(\tR\x06folder\x12\x14\n" +
+	"\x05title\x18\a \x01(\tR\x05title\"\xf9\x02\n" +
+	"\vLinkOptions\x12#\n" +
+	"\rredirect_type\x18\x01 \x01(\x05R\fredirectType\x12#\n" +
+	"\rredirect_mode\x18\x02 \x01(\tR\fredirectMode\x12!\n" +
+	"\fquery_policy\x18\x03 \x01(\tR\vqueryPolicy\x12!\n" +
+	"\fforward_path\x18\x04 \x01(\bR\vforwardPath\x12\x1f\n" +
+	"\x03utm\x18\x05 \x01(\v2\r.shortugo.UTMR\x03utm\x12\x1a\n" +
+	"\bpassword\x18\x06 \x01(\tR\bpassword\x12\x1d\n" +
+	"\n" +
+	"max_clicks\x18\a \x01(\x05R\tmaxClicks\x12+\n" +
+	"\x05rules\x18\b \x03(\v2\x15.shortugo.RoutingRuleR\x05rules\x12-\n" +
+	"\bvariants\x18\t \x03(\v2\x11.shortugo.VariantR\bvariants\x12\"\n" +
+	"\finterstitial\x18\n" +
+	" \x01(\bR\finterstitial\"_\n" +
+	"\aVariant\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x16\n" +
+	"\x06weight\x18\x03 \x01(\x05R\x06weight\x12\x16\n" +
+	"\x06clicks\x18\x04 \x01(\x03R\x06clicks\"q\n" +
+	"\vRoutingRule\x12\x1a\n" +
+	"\bplatform\x18\x01 \x01(\tR\bplatform\x12\x1a\n" +
+	"\blanguage\x18\x02 \x01(\tR\blanguage\x12\x18\n" +
+	"\acountry\x18\x03 \x01(\tR\acountry\x12\x10\n" +
+	"\x03url\x18\x04 \x01(\tR\x03url\"\x7f\n" +
+	"\x03UTM\x12\x16\n" +
+	"\x06source\x18\x01 \x01(\tR\x06source\x12\x16\n" +
+	"\x06medium\x18\x02 \x01(\tR\x06medium\x12\x1a\n" +
+	"\bcampaign\x18\x03 \x01(\tR\bcampaign\x12\x12\n" +
+	"\x04term\x18\x04 \x01(\tR\x04term\x12\x18\n" +
+	"\acontent\x18\x05 \x01(\tR\acontent\"\x93\x01\n" +
 	"\x0eShortenRequest\x12!\n" +
 	"\foriginal_url\x18\x01 \x01(\tR\voriginalUrl\x12\x17\n" +
-	"\auser_id\x18\x02 \x01(\tR\x06userId\".\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12/\n" +
+	"\aoptions\x18\x03 \x01(\v2\x15.shortugo.LinkOptionsR\aoptions\x12\x14\n" +
+	"\x05title\x18\x04 \x01(\tR\x05title\".\n" +
 	"\x0fShortenResponse\x12\x1b\n" +
-	"\tshort_url\x18\x01 \x01(\tR\bshortUrl\"1\n" +
+	"\tshort_url\x18\x01 \x01(\tR\bshortUrl\"M\n" +
 	"\rExpandRequest\x12 \n" +
 	"\fshort_url_id\x18\x01 \x01(\tR\n" +
-	"shortUrlId\"3\n" +
+	"shortUrlId\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\"3\n" +
 	"\x0eExpandResponse\x12!\n" +
 	"\foriginal_url\x18\x01 \x01(\tR\voriginalUrl\"U\n" +
 	"\x13ShortenBatchRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12%\n" +
 	"\x04urls\x18\x02 \x03(\v2\x11.shortugo.URLPairR\x04urls\"C\n" +
 	"\x14ShortenBatchResponse\x12+\n" +
-	"\aresults\x18\x01 \x03(\v2\x11.shortugo.URLPairR\aresults\".\n" +
+	"\aresults\x18\x01 \x03(\v2\x11.shortugo.URLPairR\aresults\"X\n" +
 	"\x13ListUserURLsRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\"=\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x10\n" +
+	"\x03tag\x18\x02 \x01(\tR\x03tag\x12\x16\n" +
+	"\x06folder\x18\x03 \x01(\tR\x06folder\"=\n" +
 	"\x14ListUserURLsResponse\x12%\n" +
-	"\x04urls\x18\x01 \x03(\v2\x11.shortugo.URLPairR\x04urls\"T\n" +
+	"\x04urls\x18\x01 \x03(\v2\x11.shortugo.URLPairR\x04urls\">\n" +
+	"\x15SearchUserURLsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\f\n" +
+	"\x01q\x18\x02 \x01(\tR\x01q\"\xaa\x01\n" +
+	"\x14ShortenStreamRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12%\n" +
+	"\x0ecorrelation_id\x18\x02 \x01(\tR\rcorrelationId\x12!\n" +
+	"\foriginal_url\x18\x03 \x01(\tR\voriginalUrl\x12/\n" +
+	"\aoptions\x18\x04 \x01(\v2\x15.shortugo.LinkOptionsR\aoptions\"v\n" +
+	"\x10GetQRCodeRequest\x12 \n" +
+	"\fshort_url_id\x18\x01 \x01(\tR\n" +
+	"shortUrlId\x12\x16\n" +
+	"\x06format\x18\x02 \x01(\tR\x06format\x12\x12\n" +
+	"\x04size\x18\x03 \x01(\x05R\x04size\x12\x14\n" +
+	"\x05level\x18\x04 \x01(\tR\x05level\"L\n" +
+	"\x11GetQRCodeResponse\x12\x14\n" +
+	"\x05image\x18\x01 \x01(\fR\x05image\x12!\n" +
+	"\fcontent_type\x18\x02 \x01(\tR\vcontentType\"0\n" +
+	"\x15GetUTMTemplateRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"[\n" +
+	"\x15SetUTMTemplateRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12)\n" +
+	"\btemplate\x18\x02 \x01(\v2\r.shortugo.UTMR\btemplate\"@\n" +
+	"\x13UTMTemplateResponse\x12)\n" +
+	"\btemplate\x18\x01 \x01(\v2\r.shortugo.UTMR\btemplate\"o\n" +
+	"\x16SetLinkPasswordRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12 \n" +
+	"\fshort_url_id\x18\x02 \x01(\tR\n" +
+	"shortUrlId\x12\x1a\n" +
+	"\bpassword\x18\x03 \x01(\tR\bpassword\"7\n" +
+	"\x17SetLinkPasswordResponse\x12\x1c\n" +
+	"\tprotected\x18\x01 \x01(\bR\tprotected\"P\n" +
+	"\x13GetLinkRulesRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12 \n" +
+	"\fshort_url_id\x18\x02 \x01(\tR\n" +
+	"shortUrlId\"}\n" +
+	"\x13SetLinkRulesRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12 \n" +
+	"\fshort_url_id\x18\x02 \x01(\tR\n" +
+	"shortUrlId\x12+\n" +
+	"\x05rules\x18\x03 \x03(\v2\x15.shortugo.RoutingRuleR\x05rules\"@\n" +
+	"\x11LinkRulesResponse\x12+\n" +
+	"\x05rules\x18\x01 \x03(\v2\x15.shortugo.RoutingRuleR\x05rules\"S\n" +
+	"\x16GetLinkVariantsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12 \n" +
+	"\fshort_url_id\x18\x02 \x01(\tR\n" +
+	"shortUrlId\"\x82\x01\n" +
+	"\x16SetLinkVariantsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12 \n" +
+	"\fshort_url_id\x18\x02 \x01(\tR\n" +
+	"shortUrlId\x12-\n" +
+	"\bvariants\x18\x03 \x03(\v2\x11.shortugo.VariantR\bvariants\"E\n" +
+	"\x14LinkVariantsResponse\x12-\n" +
+	"\bvariants\x18\x01 \x03(\v2\x11.shortugo.VariantR\bvariants\"`\n" +
+	"\x0fLinkTagsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12 \n" +
+	"\fshort_url_id\x18\x02 \x01(\tR\n" +
+	"shortUrlId\x12\x12\n" +
+	"\x04tags\x18\x03 \x03(\tR\x04tags\"i\n" +
+	"\x14SetLinkFolderRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12 \n" +
+	"\fshort_url_id\x18\x02 \x01(\tR\n" +
+	"shortUrlId\x12\x16\n" +
+	"\x06folder\x18\x03 \x01(\tR\x06folder\"@\n" +
+	"\x12LinkLabelsResponse\x12\x12\n" +
+	"\x04tags\x18\x01 \x03(\tR\x04tags\x12\x16\n" +
+	"\x06folder\x18\x02 \x01(\tR\x06folder\"T\n" +
 	"\x15DeleteUserURLsRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\"\n" +
 	"\rshort_url_ids\x18\x02 \x03(\tR\vshortUrlIds\"2\n" +
@@ -1274,71 +5052,229 @@ const file_proto_shortugo_proto_rawDesc = "" +
 	"\x06status\x18\x01 \x01(\tR\x06status\"\r\n" +
 	"\vPingRequest\"&\n" +
 	"\fPingResponse\x12\x16\n" +
-	"\x06status\x18\x01 \x01(\tR\x06status\"\x1e\n" +
-	"\fStatsRequest\x12\x0e\n" +
-	"\x02ip\x18\x01 \x01(\tR\x02ip\"K\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\"\x14\n" +
+	"\fStatsRequestJ\x04\b\x01\x10\x02\"K\n" +
 	"\rStatsResponse\x12\x1b\n" +
 	"\turl_count\x18\x01 \x01(\x03R\burlCount\x12\x1d\n" +
 	"\n" +
-	"user_count\x18\x02 \x01(\x03R\tuserCount2\xff\x04\n" +
-	"\fURLShortener\x12>\n" +
-	"\aShorten\x12\x18.shortugo.ShortenRequest\x1a\x19.shortugo.ShortenResponse\x12B\n" +
-	"\vShortenJSON\x12\x18.shortugo.ShortenRequest\x1a\x19.shortugo.ShortenResponse\x12M\n" +
-	"\fShortenBatch\x12\x1d.shortugo.ShortenBatchRequest\x1a\x1e.shortugo.ShortenBatchResponse\x12;\n" +
-	"\x06Expand\x12\x17.shortugo.ExpandRequest\x1a\x18.shortugo.ExpandResponse\x12M\n" +
-	"\fListUserURLs\x12\x1d.shortugo.ListUserURLsRequest\x1a\x1e.shortugo.ListUserURLsResponse\x12S\n" +
-	"\x0eDeleteUserURLs\x12\x1f.shortugo.DeleteUserURLsRequest\x1a .shortugo.DeleteUserURLsResponse\x12J\n" +
-	"\vHealthCheck\x12\x1c.shortugo.HealthCheckRequest\x1a\x1d.shortugo.HealthCheckResponse\x125\n" +
-	"\x04Ping\x12\x15.shortugo.PingRequest\x1a\x16.shortugo.PingResponse\x128\n" +
-	"\x05Stats\x12\x16.shortugo.StatsRequest\x1a\x17.shortugo.StatsResponseB\x16Z\f/proto;proto\x92\x03\x05\xd2>\x02\x10\x02b\beditionsp\xe8\a"
-
-var file_proto_shortugo_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+	"user_count\x18\x02 \x01(\x03R\tuserCount\"\x1b\n" +
+	"\x13PurgeDeletedRequestJ\x04\b\x01\x10\x02\".\n" +
+	"\x14PurgeDeletedResponse\x12\x16\n" +
+	"\x06purged\x18\x01 \x01(\x03R\x06purged\"2\n" +
+	"\x12BlockDomainRequest\x12\x16\n" +
+	"\x06domain\x18\x02 \x01(\tR\x06domainJ\x04\b\x01\x10\x02\"!\n" +
+	"\x19ListBlockedDomainsRequestJ\x04\b\x01\x10\x02\"2\n" +
+	"\x16BlockedDomainsResponse\x12\x18\n" +
+	"\adomains\x18\x01 \x03(\tR\adomains\"=\n" +
+	"\x13GetLinkOwnerRequest\x12 \n" +
+	"\fshort_url_id\x18\x02 \x01(\tR\n" +
+	"shortUrlIdJ\x04\b\x01\x10\x02\"\x93\x01\n" +
+	"\x14GetLinkOwnerResponse\x12 \n" +
+	"\fshort_url_id\x18\x01 \x01(\tR\n" +
+	"shortUrlId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12!\n" +
+	"\foriginal_url\x18\x03 \x01(\tR\voriginalUrl\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\tR\tcreatedAt\"\x8f\x02\n" +
+	"\tAdminLink\x12 \n" +
+	"\fshort_url_id\x18\x01 \x01(\tR\n" +
+	"shortUrlId\x12\x1b\n" +
+	"\tshort_url\x18\x02 \x01(\tR\bshortUrl\x12!\n" +
+	"\foriginal_url\x18\x03 \x01(\tR\voriginalUrl\x12\x17\n" +
+	"\auser_id\x18\x04 \x01(\tR\x06userId\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\tR\tcreatedAt\x12\x14\n" +
+	"\x05title\x18\x06 \x01(\tR\x05title\x12\x18\n" +
+	"\adeleted\x18\a \x01(\bR\adeleted\x12\x1a\n" +
+	"\bdisabled\x18\b \x01(\bR\bdisabled\x12\x1c\n" +
+	"\texhausted\x18\t \x01(\bR\texhausted\"4\n" +
+	"\x10AdminLinkRequest\x12 \n" +
+	"\fshort_url_id\x18\x01 \x01(\tR\n" +
+	"shortUrlId\"N\n" +
+	"\x12DisableLinkRequest\x12 \n" +
+	"\fshort_url_id\x18\x01 \x01(\tR\n" +
+	"shortUrlId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"0\n" +
+	"\x15AdminUserLinksRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"?\n" +
+	"\x12AdminLinksResponse\x12)\n" +
+	"\x05links\x18\x01 \x03(\v2\x13.shortugo.AdminLinkR\x05links2\x87\x18\n" +
+	"\fURLShortener\x12Z\n" +
+	"\aShorten\x12\x18.shortugo.ShortenRequest\x1a\x19.shortugo.ShortenResponse\"\x1a\x82\xd3\xe4\x93\x02\x14:\x01*\"\x0f/api/v2/shorten\x12B\n" +
+	"\vShortenJSON\x12\x18.shortugo.ShortenRequest\x1a\x19.shortugo.ShortenResponse\x12o\n" +
+	"\fShortenBatch\x12\x1d.shortugo.ShortenBatchRequest\x1a\x1e.shortugo.ShortenBatchResponse\" \x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/api/v2/shorten/batch\x12\x89\x01\n" +
+	"\x06Expand\x12\x17.shortugo.ExpandRequest\x1a\x18.shortugo.ExpandResponse\"L\x82\xd3\xe4\x93\x02FZ':\x01*\"\"/api/v2/urls/{short_url_id}/expand\x12\x1b/api/v2/urls/{short_url_id}\x12s\n" +
+	"\fListUserURLs\x12\x1d.shortugo.ListUserURLsRequest\x1a\x1e.shortugo.ListUserURLsResponse\"$\x82\xd3\xe4\x93\x02\x1e\x12\x1c/api/v2/users/{user_id}/urls\x12~\n" +
+	"\x0eSearchUserURLs\x12\x1f.shortugo.SearchUserURLsRequest\x1a\x1e.shortugo.ListUserURLsResponse\"+\x82\xd3\xe4\x93\x02%\x12#/api/v2/users/{user_id}/urls/search\x12|\n" +
+	"\x0eDeleteUserURLs\x12\x1f.shortugo.DeleteUserURLsRequest\x1a .shortugo.DeleteUserURLsResponse\"'\x82\xd3\xe4\x93\x02!:\x01**\x1c/api/v2/users/{user_id}/urls\x12b\n" +
+	"\vHealthCheck\x12\x1c.shortugo.HealthCheckRequest\x1a\x1d.shortugo.HealthCheckResponse\"\x16\x82\xd3\xe4\x93\x02\x10\x12\x0e/api/v2/health\x12K\n" +
+	"\x04Ping\x12\x15.shortugo.PingRequest\x1a\x16.shortugo.PingResponse\"\x14\x82\xd3\xe4\x93\x02\x0e\x12\f/api/v2/ping\x128\n" +
+	"\x05Stats\x12\x16.shortugo.StatsRequest\x1a\x17.shortugo.StatsResponse\x12D\n" +
+	"\x0eStreamUserURLs\x12\x1d.shortugo.ListUserURLsRequest\x1a\x11.shortugo.URLPair0\x01\x12F\n" +
+	"\rShortenStream\x12\x1e.shortugo.ShortenStreamRequest\x1a\x11.shortugo.URLPair(\x010\x01\x12l\n" +
+	"\tGetQRCode\x12\x1a.shortugo.GetQRCodeRequest\x1a\x1b.shortugo.GetQRCodeResponse\"&\x82\xd3\xe4\x93\x02 \x12\x1e/api/v2/urls/{short_url_id}/qr\x12u\n" +
+	"\x0eGetUTMTemplate\x12\x1f.shortugo.GetUTMTemplateRequest\x1a\x1d.shortugo.UTMTemplateResponse\"#\x82\xd3\xe4\x93\x02\x1d\x12\x1b/api/v2/users/{user_id}/utm\x12x\n" +
+	"\x0eSetUTMTemplate\x12\x1f.shortugo.SetUTMTemplateRequest\x1a\x1d.shortugo.UTMTemplateResponse\"&\x82\xd3\xe4\x93\x02 :\x01*\x1a\x1b/api/v2/users/{user_id}/utm\x12\x97\x01\n" +
+	"\x0fSetLinkPassword\x12 .shortugo.SetLinkPasswordRequest\x1a!.shortugo.SetLinkPasswordResponse\"?\x82\xd3\xe4\x93\x029:\x01*\x1a4/api/v2/users/{user_id}/urls/{short_url_id}/password\x12\x85\x01\n" +
+	"\fGetLinkRules\x12\x1d.shortugo.GetLinkRulesRequest\x1a\x1b.shortugo.LinkRulesResponse\"9\x82\xd3\xe4\x93\x023\x121/api/v2/users/{user_id}/urls/{short_url_id}/rules\x12\x88\x01\n" +
+	"\fSetLinkRules\x12\x1d.shortugo.SetLinkRulesRequest\x1a\x1b.shortugo.LinkRulesResponse\"<\x82\xd3\xe4\x93\x026:\x01*\x1a1/api/v2/users/{user_id}/urls/{short_url_id}/rules\x12\x91\x01\n" +
+	"\x0fGetLinkVariants\x12 .shortugo.GetLinkVariantsRequest\x1a\x1e.shortugo.LinkVariantsResponse\"<\x82\xd3\xe4\x93\x026\x124/api/v2/users/{user_id}/urls/{short_url_id}/variants\x12\x94\x01\n" +
+	"\x0fSetLinkVariants\x12 .shortugo.SetLinkVariantsRequest\x1a\x1e.shortugo.LinkVariantsResponse\"?\x82\xd3\xe4\x93\x029:\x01*\x1a4/api/v2/users/{user_id}/urls/{short_url_id}/variants\x12\x83\x01\n" +
+	"\vAddLinkTags\x12\x19.shortugo.LinkTagsRequest\x1a\x1c.shortugo.LinkLabelsResponse\";\x82\xd3\xe4\x93\x025:\x01*\"0/api/v2/users/{user_id}/urls/{short_url_id}/tags\x12\x86\x01\n" +
+	"\x0eRemoveLinkTags\x12\x19.shortugo.LinkTagsRequest\x1a\x1c.shortugo.LinkLabelsResponse\";\x82\xd3\xe4\x93\x025:\x01**0/api/v2/users/{user_id}/urls/{short_url_id}/tags\x12\x8c\x01\n" +
+	"\rSetLinkFolder\x12\x1e.shortugo.SetLinkFolderRequest\x1a\x1c.shortugo.LinkLabelsResponse\"=\x82\xd3\xe4\x93\x027:\x01*\x1a2/api/v2/users/{user_id}/urls/{short_url_id}/folder\x12M\n" +
+	"\fPurgeDeleted\x12\x1d.shortugo.PurgeDeletedRequest\x1a\x1e.shortugo.PurgeDeletedResponse\x12M\n" +
+	"\vBlockDomain\x12\x1c.shortugo.BlockDomainRequest\x1a .shortugo.BlockedDomainsResponse\x12O\n" +
+	"\rUnblockDomain\x12\x1c.shortugo.BlockDomainRequest\x1a .shortugo.BlockedDomainsResponse\x12[\n" +
+	"\x12ListBlockedDomains\x12#.shortugo.ListBlockedDomainsRequest\x1a .shortugo.BlockedDomainsResponse\x12M\n" +
+	"\fGetLinkOwner\x12\x1d.shortugo.GetLinkOwnerRequest\x1a\x1e.shortugo.GetLinkOwnerResponse2\x94\x02\n" +
+	"\x05Admin\x12:\n" +
+	"\aGetLink\x12\x1a.shortugo.AdminLinkRequest\x1a\x13.shortugo.AdminLink\x12@\n" +
+	"\vDisableLink\x12\x1c.shortugo.DisableLinkRequest\x1a\x13.shortugo.AdminLink\x12=\n" +
+	"\n" +
+	"EnableLink\x12\x1a.shortugo.AdminLinkRequest\x1a\x13.shortugo.AdminLink\x12N\n" +
+	"\rListUserLinks\x12\x1f.shortugo.AdminUserLinksRequest\x1a\x1c.shortugo.AdminLinksResponseB\x16Z\f/proto;proto\x92\x03\x05\xd2>\x02\x10\x02b\beditionsp\xe8\a"
+
+var file_proto_shortugo_proto_msgTypes = make([]protoimpl.MessageInfo, 51)
 var file_proto_shortugo_proto_goTypes = []any{
-	(*URLPair)(nil),                // 0: shortugo.URLPair
-	(*ShortenRequest)(nil),         // 1: shortugo.ShortenRequest
-	(*ShortenResponse)(nil),        // 2: shortugo.ShortenResponse
-	(*ExpandRequest)(nil),          // 3: shortugo.ExpandRequest
-	(*ExpandResponse)(nil),         // 4: shortugo.ExpandResponse
-	(*ShortenBatchRequest)(nil),    // 5: shortugo.ShortenBatchRequest
-	(*ShortenBatchResponse)(nil),   // 6: shortugo.ShortenBatchResponse
-	(*ListUserURLsRequest)(nil),    // 7: shortugo.ListUserURLsRequest
-	(*ListUserURLsResponse)(nil),   // 8: shortugo.ListUserURLsResponse
-	(*DeleteUserURLsRequest)(nil),  // 9: shortugo.DeleteUserURLsRequest
-	(*DeleteUserURLsResponse)(nil), // 10: shortugo.DeleteUserURLsResponse
-	(*HealthCheckRequest)(nil),     // 11: shortugo.HealthCheckRequest
-	(*HealthCheckResponse)(nil),    // 12: shortugo.HealthCheckResponse
-	(*PingRequest)(nil),            // 13: shortugo.PingRequest
-	(*PingResponse)(nil),           // 14: shortugo.PingResponse
-	(*StatsRequest)(nil),           // 15: shortugo.StatsRequest
-	(*StatsResponse)(nil),          // 16: shortugo.StatsResponse
+	(*URLPair)(nil),                   // 0: shortugo.URLPair
+	(*LinkOptions)(nil),               // 1: shortugo.LinkOptions
+	(*Variant)(nil),                   // 2: shortugo.Variant
+	(*RoutingRule)(nil),               // 3: shortugo.RoutingRule
+	(*UTM)(nil),                       // 4: shortugo.UTM
+	(*ShortenRequest)(nil),            // 5: shortugo.ShortenRequest
+	(*ShortenResponse)(nil),           // 6: shortugo.ShortenResponse
+	(*ExpandRequest)(nil),             // 7: shortugo.ExpandRequest
+	(*ExpandResponse)(nil),            // 8: shortugo.ExpandResponse
+	(*ShortenBatchRequest)(nil),       // 9: shortugo.ShortenBatchRequest
+	(*ShortenBatchResponse)(nil),      // 10: shortugo.ShortenBatchResponse
+	(*ListUserURLsRequest)(nil),       // 11: shortugo.ListUserURLsRequest
+	(*ListUserURLsResponse)(nil),      // 12: shortugo.ListUserURLsResponse
+	(*SearchUserURLsRequest)(nil),     // 13: shortugo.SearchUserURLsRequest
+	(*ShortenStreamRequest)(nil),      // 14: shortugo.ShortenStreamRequest
+	(*GetQRCodeRequest)(nil),          // 15: shortugo.GetQRCodeRequest
+	(*GetQRCodeResponse)(nil),         // 16: shortugo.GetQRCodeResponse
+	(*GetUTMTemplateRequest)(nil),     // 17: shortugo.GetUTMTemplateRequest
+	(*SetUTMTemplateRequest)(nil),     // 18: shortugo.SetUTMTemplateRequest
+	(*UTMTemplateResponse)(nil),       // 19: shortugo.UTMTemplateResponse
+	(*SetLinkPasswordRequest)(nil),    // 20: shortugo.SetLinkPasswordRequest
+	(*SetLinkPasswordResponse)(nil),   // 21: shortugo.SetLinkPasswordResponse
+	(*GetLinkRulesRequest)(nil),       // 22: shortugo.GetLinkRulesRequest
+	(*SetLinkRulesRequest)(nil),       // 23: shortugo.SetLinkRulesRequest
+	(*LinkRulesResponse)(nil),         // 24: shortugo.LinkRulesResponse
+	(*GetLinkVariantsRequest)(nil),    // 25: shortugo.GetLinkVariantsRequest
+	(*SetLinkVariantsRequest)(nil),    // 26: shortugo.SetLinkVariantsRequest
+	(*LinkVariantsResponse)(nil),      // 27: shortugo.LinkVariantsResponse
+	(*LinkTagsRequest)(nil),           // 28: shortugo.LinkTagsRequest
+	(*SetLinkFolderRequest)(nil),      // 29: shortugo.SetLinkFolderRequest
+	(*LinkLabelsResponse)(nil),        // 30: shortugo.LinkLabelsResponse
+	(*DeleteUserURLsRequest)(nil),     // 31: shortugo.DeleteUserURLsRequest
+	(*DeleteUserURLsResponse)(nil),    // 32: shortugo.DeleteUserURLsResponse
+	(*HealthCheckRequest)(nil),        // 33: shortugo.HealthCheckRequest
+	(*HealthCheckResponse)(nil),       // 34: shortugo.HealthCheckResponse
+	(*PingRequest)(nil),               // 35: shortugo.PingRequest
+	(*PingResponse)(nil),              // 36: shortugo.PingResponse
+	(*StatsRequest)(nil),              // 37: shortugo.StatsRequest
+	(*StatsResponse)(nil),             // 38: shortugo.StatsResponse
+	(*PurgeDeletedRequest)(nil),       // 39: shortugo.PurgeDeletedRequest
+	(*PurgeDeletedResponse)(nil),      // 40: shortugo.PurgeDeletedResponse
+	(*BlockDomainRequest)(nil),        // 41: shortugo.BlockDomainRequest
+	(*ListBlockedDomainsRequest)(nil), // 42: shortugo.ListBlockedDomainsRequest
+	(*BlockedDomainsResponse)(nil),    // 43: shortugo.BlockedDomainsResponse
+	(*GetLinkOwnerRequest)(nil),       // 44: shortugo.GetLinkOwnerRequest
+	(*GetLinkOwnerResponse)(nil),      // 45: shortugo.GetLinkOwnerResponse
+	(*AdminLink)(nil),                 // 46: shortugo.AdminLink
+	(*AdminLinkRequest)(nil),          // 47: shortugo.AdminLinkRequest
+	(*DisableLinkRequest)(nil),        // 48: shortugo.DisableLinkRequest
+	(*AdminUserLinksRequest)(nil),     // 49: shortugo.AdminUserLinksRequest
+	(*AdminLinksResponse)(nil),        // 50: shortugo.AdminLinksResponse
 }
 var file_proto_shortugo_proto_depIdxs = []int32{
-	0,  // 0: shortugo.ShortenBatchRequest.urls:type_name -> shortugo.URLPair
-	0,  // 1: shortugo.ShortenBatchResponse.results:type_name -> shortugo.URLPair
-	0,  // 2: shortugo.ListUserURLsResponse.urls:type_name -> shortugo.URLPair
-	1,  // 3: shortugo.URLShortener.Shorten:input_type -> shortugo.ShortenRequest
-	1,  // 4: shortugo.URLShortener.ShortenJSON:input_type -> shortugo.ShortenRequest
-	5,  // 5: shortugo.URLShortener.ShortenBatch:input_type -> shortugo.ShortenBatchRequest
-	3,  // 6: shortugo.URLShortener.Expand:input_type -> shortugo.ExpandRequest
-	7,  // 7: shortugo.URLShortener.ListUserURLs:input_type -> shortugo.ListUserURLsRequest
-	9,  // 8: shortugo.URLShortener.DeleteUserURLs:input_type -> shortugo.DeleteUserURLsRequest
-	11, // 9: shortugo.URLShortener.HealthCheck:input_type -> shortugo.HealthCheckRequest
-	13, // 10: shortugo.URLShortener.Ping:input_type -> shortugo.PingRequest
-	15, // 11: shortugo.URLShortener.Stats:input_type -> shortugo.StatsRequest
-	2,  // 12: shortugo.URLShortener.Shorten:output_type -> shortugo.ShortenResponse
-	2,  // 13: shortugo.URLShortener.ShortenJSON:output_type -> shortugo.ShortenResponse
-	6,  // 14: shortugo.URLShortener.ShortenBatch:output_type -> shortugo.ShortenBatchResponse
-	4,  // 15: shortugo.URLShortener.Expand:output_type -> shortugo.ExpandResponse
-	8,  // 16: shortugo.URLShortener.ListUserURLs:output_type -> shortugo.ListUserURLsResponse
-	10, // 17: shortugo.URLShortener.DeleteUserURLs:output_type -> shortugo.DeleteUserURLsResponse
-	12, // 18: shortugo.URLShortener.HealthCheck:output_type -> shortugo.HealthCheckResponse
-	14, // 19: shortugo.URLShortener.Ping:output_type -> shortugo.PingResponse
-	16, // 20: shortugo.URLShortener.Stats:output_type -> shortugo.StatsResponse
-	12, // [12:21] is the sub-list for method output_type
-	3,  // [3:12] is the sub-list for method input_type
-	3,  // [3:3] is the sub-list for extension type_name
-	3,  // [3:3] is the sub-list for extension extendee
-	0,  // [0:3] is the sub-list for field type_name
+	1,  // 0: shortugo.URLPair.options:type_name -> shortugo.LinkOptions
+	4,  // 1: shortugo.LinkOptions.utm:type_name -> shortugo.UTM
+	3,  // 2: shortugo.LinkOptions.rules:type_name -> shortugo.RoutingRule
+	2,  // 3: shortugo.LinkOptions.variants:type_name -> shortugo.Variant
+	1,  // 4: shortugo.ShortenRequest.options:type_name -> shortugo.LinkOptions
+	0,  // 5: shortugo.ShortenBatchRequest.urls:type_name -> shortugo.URLPair
+	0,  // 6: shortugo.ShortenBatchResponse.results:type_name -> shortugo.URLPair
+	0,  // 7: shortugo.ListUserURLsResponse.urls:type_name -> shortugo.URLPair
+	1,  // 8: shortugo.ShortenStreamRequest.options:type_name -> shortugo.LinkOptions
+	4,  // 9: shortugo.SetUTMTemplateRequest.template:type_name -> shortugo.UTM
+	4,  // 10: shortugo.UTMTemplateResponse.template:type_name -> shortugo.UTM
+	3,  // 11: shortugo.SetLinkRulesRequest.rules:type_name -> shortugo.RoutingRule
+	3,  // 12: shortugo.LinkRulesResponse.rules:type_name -> shortugo.RoutingRule
+	2,  // 13: shortugo.SetLinkVariantsRequest.variants:type_name -> shortugo.Variant
+	2,  // 14: shortugo.LinkVariantsResponse.variants:type_name -> shortugo.Variant
+	46, // 15: shortugo.AdminLinksResponse.links:type_name -> shortugo.AdminLink
+	5,  // 16: shortugo.URLShortener.Shorten:input_type -> shortugo.ShortenRequest
+	5,  // 17: shortugo.URLShortener.ShortenJSON:input_type -> shortugo.ShortenRequest
+	9,  // 18: shortugo.URLShortener.ShortenBatch:input_type -> shortugo.ShortenBatchRequest
+	7,  // 19: shortugo.URLShortener.Expand:input_type -> shortugo.ExpandRequest
+	11, // 20: shortugo.URLShortener.ListUserURLs:input_type -> shortugo.ListUserURLsRequest
+	13, // 21: shortugo.URLShortener.SearchUserURLs:input_type -> shortugo.SearchUserURLsRequest
+	31, // 22: shortugo.URLShortener.DeleteUserURLs:input_type -> shortugo.DeleteUserURLsRequest
+	33, // 23: shortugo.URLShortener.HealthCheck:input_type -> shortugo.HealthCheckRequest
+	35, // 24: shortugo.URLShortener.Ping:input_type -> shortugo.PingRequest
+	37, // 25: shortugo.URLShortener.Stats:input_type -> shortugo.StatsRequest
+	11, // 26: shortugo.URLShortener.StreamUserURLs:input_type -> shortugo.ListUserURLsRequest
+	14, // 27: shortugo.URLShortener.ShortenStream:input_type -> shortugo.ShortenStreamRequest
+	15, // 28: shortugo.URLShortener.GetQRCode:input_type -> shortugo.GetQRCodeRequest
+	17, // 29: shortugo.URLShortener.GetUTMTemplate:input_type -> shortugo.GetUTMTemplateRequest
+	18, // 30: shortugo.URLShortener.SetUTMTemplate:input_type -> shortugo.SetUTMTemplateRequest
+	20, // 31: shortugo.URLShortener.SetLinkPassword:input_type -> shortugo.SetLinkPasswordRequest
+	22, // 32: shortugo.URLShortener.GetLinkRules:input_type -> shortugo.GetLinkRulesRequest
+	23, // 33: shortugo.URLShortener.SetLinkRules:input_type -> shortugo.SetLinkRulesRequest
+	25, // 34: shortugo.URLShortener.GetLinkVariants:input_type -> shortugo.GetLinkVariantsRequest
+	26, // 35: shortugo.URLShortener.SetLinkVariants:input_type -> shortugo.SetLinkVariantsRequest
+	28, // 36: shortugo.URLShortener.AddLinkTags:input_type -> shortugo.LinkTagsRequest
+	28, // 37: shortugo.URLShortener.RemoveLinkTags:input_type -> shortugo.LinkTagsRequest
+	29, // 38: shortugo.URLShortener.SetLinkFolder:input_type -> shortugo.SetLinkFolderRequest
+	39, // 39: shortugo.URLShortener.PurgeDeleted:input_type -> shortugo.PurgeDeletedRequest
+	41, // 40: shortugo.URLShortener.BlockDomain:input_type -> shortugo.BlockDomainRequest
+	41, // 41: shortugo.URLShortener.UnblockDomain:input_type -> shortugo.BlockDomainRequest
+	42, // 42: shortugo.URLShortener.ListBlockedDomains:input_type -> shortugo.ListBlockedDomainsRequest
+	44, // 43: shortugo.URLShortener.GetLinkOwner:input_type -> shortugo.GetLinkOwnerRequest
+	47, // 44: shortugo.Admin.GetLink:input_type -> shortugo.AdminLinkRequest
+	48, // 45: shortugo.Admin.DisableLink:input_type -> shortugo.DisableLinkRequest
+	47, // 46: shortugo.Admin.EnableLink:input_type -> shortugo.AdminLinkRequest
+	49, // 47: shortugo.Admin.ListUserLinks:input_type -> shortugo.AdminUserLinksRequest
+	6,  // 48: shortugo.URLShortener.Shorten:output_type -> shortugo.ShortenResponse
+	6,  // 49: shortugo.URLShortener.ShortenJSON:output_type -> shortugo.ShortenResponse
+	10, // 50: shortugo.URLShortener.ShortenBatch:output_type -> shortugo.ShortenBatchResponse
+	8,  // 51: shortugo.URLShortener.Expand:output_type -> shortugo.ExpandResponse
+	12, // 52: shortugo.URLShortener.ListUserURLs:output_type -> shortugo.ListUserURLsResponse
+	12, // 53: shortugo.URLShortener.SearchUserURLs:output_type -> shortugo.ListUserURLsResponse
+	32, // 54: shortugo.URLShortener.DeleteUserURLs:output_type -> shortugo.DeleteUserURLsResponse
+	34, // 55: shortugo.URLShortener.HealthCheck:output_type -> shortugo.HealthCheckResponse
+	36, // 56: shortugo.URLShortener.Ping:output_type -> shortugo.PingResponse
+	38, // 57: shortugo.URLShortener.Stats:output_type -> shortugo.StatsResponse
+	0,  // 58: shortugo.URLShortener.StreamUserURLs:output_type -> shortugo.URLPair
+	0,  // 59: shortugo.URLShortener.ShortenStream:output_type -> shortugo.URLPair
+	16, // 60: shortugo.URLShortener.GetQRCode:output_type -> shortugo.GetQRCodeResponse
+	19, // 61: shortugo.URLShortener.GetUTMTemplate:output_type -> shortugo.UTMTemplateResponse
+	19, // 62: shortugo.URLShortener.SetUTMTemplate:output_type -> shortugo.UTMTemplateResponse
+	21, // 63: shortugo.URLShortener.SetLinkPassword:output_type -> shortugo.SetLinkPasswordResponse
+	24, // 64: shortugo.URLShortener.GetLinkRules:output_type -> shortugo.LinkRulesResponse
+	24, // 65: shortugo.URLShortener.SetLinkRules:output_type -> shortugo.LinkRulesResponse
+	27, // 66: shortugo.URLShortener.GetLinkVariants:output_type -> shortugo.LinkVariantsResponse
+	27, // 67: shortugo.URLShortener.SetLinkVariants:output_type -> shortugo.LinkVariantsResponse
+	30, // 68: shortugo.URLShortener.AddLinkTags:output_type -> shortugo.LinkLabelsResponse
+	30, // 69: shortugo.URLShortener.RemoveLinkTags:output_type -> shortugo.LinkLabelsResponse
+	30, // 70: shortugo.URLShortener.SetLinkFolder:output_type -> shortugo.LinkLabelsResponse
+	40, // 71: shortugo.URLShortener.PurgeDeleted:output_type -> shortugo.PurgeDeletedResponse
+	43, // 72: shortugo.URLShortener.BlockDomain:output_type -> shortugo.BlockedDomainsResponse
+	43, // 73: shortugo.URLShortener.UnblockDomain:output_type -> shortugo.BlockedDomainsResponse
+	43, // 74: shortugo.URLShortener.ListBlockedDomains:output_type -> shortugo.BlockedDomainsResponse
+	45, // 75: shortugo.URLShortener.GetLinkOwner:output_type -> shortugo.GetLinkOwnerResponse
+	46, // 76: shortugo.Admin.GetLink:output_type -> shortugo.AdminLink
+	46, // 77: shortugo.Admin.DisableLink:output_type -> shortugo.AdminLink
+	46, // 78: shortugo.Admin.EnableLink:output_type -> shortugo.AdminLink
+	50, // 79: shortugo.Admin.ListUserLinks:output_type -> shortugo.AdminLinksResponse
+	48, // [48:80] is the sub-list for method output_type
+	16, // [16:48] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
 }
 
 func init() { file_proto_shortugo_proto_init() }
@@ -1352,9 +5288,9 @@ func file_proto_shortugo_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_shortugo_proto_rawDesc), len(file_proto_shortugo_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   17,
+			NumMessages:   51,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   2,
 		},
 		GoTypes:           file_proto_shortugo_proto_goTypes,
 		DependencyIndexes: file_proto_shortugo_proto_depIdxs,
@@ -19,30 +19,80 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	URLShortener_Shorten_FullMethodName        = "/shortugo.URLShortener/Shorten"
-	URLShortener_ShortenJSON_FullMethodName    = "/shortugo.URLShortener/ShortenJSON"
-	URLShortener_ShortenBatch_FullMethodName   = "/shortugo.URLShortener/ShortenBatch"
-	URLShortener_Expand_FullMethodName         = "/shortugo.URLShortener/Expand"
-	URLShortener_ListUserURLs_FullMethodName   = "/shortugo.URLShortener/ListUserURLs"
-	URLShortener_DeleteUserURLs_FullMethodName = "/shortugo.URLShortener/DeleteUserURLs"
-	URLShortener_HealthCheck_FullMethodName    = "/shortugo.URLShortener/HealthCheck"
-	URLShortener_Ping_FullMethodName           = "/shortugo.URLShortener/Ping"
-	URLShortener_Stats_FullMethodName          = "/shortugo.URLShortener/Stats"
+	URLShortener_Shorten_FullMethodName            = "/shortugo.URLShortener/Shorten"
+	URLShortener_ShortenJSON_FullMethodName        = "/shortugo.URLShortener/ShortenJSON"
+	URLShortener_ShortenBatch_FullMethodName       = "/shortugo.URLShortener/ShortenBatch"
+	URLShortener_Expand_FullMethodName             = "/shortugo.URLShortener/Expand"
+	URLShortener_ListUserURLs_FullMethodName       = "/shortugo.URLShortener/ListUserURLs"
+	URLShortener_SearchUserURLs_FullMethodName     = "/shortugo.URLShortener/SearchUserURLs"
+	URLShortener_DeleteUserURLs_FullMethodName     = "/shortugo.URLShortener/DeleteUserURLs"
+	URLShortener_HealthCheck_FullMethodName        = "/shortugo.URLShortener/HealthCheck"
+	URLShortener_Ping_FullMethodName               = "/shortugo.URLShortener/Ping"
+	URLShortener_Stats_FullMethodName              = "/shortugo.URLShortener/Stats"
+	URLShortener_StreamUserURLs_FullMethodName     = "/shortugo.URLShortener/StreamUserURLs"
+	URLShortener_ShortenStream_FullMethodName      = "/shortugo.URLShortener/ShortenStream"
+	URLShortener_GetQRCode_FullMethodName          = "/shortugo.URLShortener/GetQRCode"
+	URLShortener_GetUTMTemplate_FullMethodName     = "/shortugo.URLShortener/GetUTMTemplate"
+	URLShortener_SetUTMTemplate_FullMethodName     = "/shortugo.URLShortener/SetUTMTemplate"
+	URLShortener_SetLinkPassword_FullMethodName    = "/shortugo.URLShortener/SetLinkPassword"
+	URLShortener_GetLinkRules_FullMethodName       = "/shortugo.URLShortener/GetLinkRules"
+	URLShortener_SetLinkRules_FullMethodName       = "/shortugo.URLShortener/SetLinkRules"
+	URLShortener_GetLinkVariants_FullMethodName    = "/shortugo.URLShortener/GetLinkVariants"
+	URLShortener_SetLinkVariants_FullMethodName    = "/shortugo.URLShortener/SetLinkVariants"
+	URLShortener_AddLinkTags_FullMethodName        = "/shortugo.URLShortener/AddLinkTags"
+	URLShortener_RemoveLinkTags_FullMethodName     = "/shortugo.URLShortener/RemoveLinkTags"
+	URLShortener_SetLinkFolder_FullMethodName      = "/shortugo.URLShortener/SetLinkFolder"
+	URLShortener_PurgeDeleted_FullMethodName       = "/shortugo.URLShortener/PurgeDeleted"
+	URLShortener_BlockDomain_FullMethodName        = "/shortugo.URLShortener/BlockDomain"
+	URLShortener_UnblockDomain_FullMethodName      = "/shortugo.URLShortener/UnblockDomain"
+	URLShortener_ListBlockedDomains_FullMethodName = "/shortugo.URLShortener/ListBlockedDomains"
+	URLShortener_GetLinkOwner_FullMethodName       = "/shortugo.URLShortener/GetLinkOwner"
 )
 
 // URLShortenerClient is the client API for URLShortener service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// URLShortener is served over gRPC and, for the methods annotated with
+// google.api.http, as the /api/v2 REST API through the HTTP gateway.
 type URLShortenerClient interface {
 	Shorten(ctx context.Context, in *ShortenRequest, opts ...grpc.CallOption) (*ShortenResponse, error)
 	ShortenJSON(ctx context.Context, in *ShortenRequest, opts ...grpc.CallOption) (*ShortenResponse, error)
 	ShortenBatch(ctx context.Context, in *ShortenBatchRequest, opts ...grpc.CallOption) (*ShortenBatchResponse, error)
+	// Expand over REST takes the password of a protected link in the body of the POST binding only;
+	// the gateway refuses passwords in query strings, which end up in access logs.
 	Expand(ctx context.Context, in *ExpandRequest, opts ...grpc.CallOption) (*ExpandResponse, error)
 	ListUserURLs(ctx context.Context, in *ListUserURLsRequest, opts ...grpc.CallOption) (*ListUserURLsResponse, error)
+	SearchUserURLs(ctx context.Context, in *SearchUserURLsRequest, opts ...grpc.CallOption) (*ListUserURLsResponse, error)
 	DeleteUserURLs(ctx context.Context, in *DeleteUserURLsRequest, opts ...grpc.CallOption) (*DeleteUserURLsResponse, error)
 	HealthCheck(ctx context.Context, in *HealthCheckRequest, opts ...grpc.CallOption) (*HealthCheckResponse, error)
 	Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingResponse, error)
+	// Stats is only answered for callers connecting from the trusted subnet or
+	// sending an admin API key, and is not exposed through the gateway.
 	Stats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*StatsResponse, error)
+	StreamUserURLs(ctx context.Context, in *ListUserURLsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[URLPair], error)
+	ShortenStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ShortenStreamRequest, URLPair], error)
+	GetQRCode(ctx context.Context, in *GetQRCodeRequest, opts ...grpc.CallOption) (*GetQRCodeResponse, error)
+	GetUTMTemplate(ctx context.Context, in *GetUTMTemplateRequest, opts ...grpc.CallOption) (*UTMTemplateResponse, error)
+	SetUTMTemplate(ctx context.Context, in *SetUTMTemplateRequest, opts ...grpc.CallOption) (*UTMTemplateResponse, error)
+	SetLinkPassword(ctx context.Context, in *SetLinkPasswordRequest, opts ...grpc.CallOption) (*SetLinkPasswordResponse, error)
+	GetLinkRules(ctx context.Context, in *GetLinkRulesRequest, opts ...grpc.CallOption) (*LinkRulesResponse, error)
+	SetLinkRules(ctx context.Context, in *SetLinkRulesRequest, opts ...grpc.CallOption) (*LinkRulesResponse, error)
+	// A/B variants of a link with their click counters
+	GetLinkVariants(ctx context.Context, in *GetLinkVariantsRequest, opts ...grpc.CallOption) (*LinkVariantsResponse, error)
+	SetLinkVariants(ctx context.Context, in *SetLinkVariantsRequest, opts ...grpc.CallOption) (*LinkVariantsResponse, error)
+	// Tags and folder of a link
+	AddLinkTags(ctx context.Context, in *LinkTagsRequest, opts ...grpc.CallOption) (*LinkLabelsResponse, error)
+	RemoveLinkTags(ctx context.Context, in *LinkTagsRequest, opts ...grpc.CallOption) (*LinkLabelsResponse, error)
+	SetLinkFolder(ctx context.Context, in *SetLinkFolderRequest, opts ...grpc.CallOption) (*LinkLabelsResponse, error)
+	// Administration, used by shortugoctl. Like Stats, these methods are only answered
+	// for callers connecting from the trusted subnet or sending an admin API key in the
+	// "authorization: Bearer <key>" metadata, and are not exposed through the gateway.
+	PurgeDeleted(ctx context.Context, in *PurgeDeletedRequest, opts ...grpc.CallOption) (*PurgeDeletedResponse, error)
+	BlockDomain(ctx context.Context, in *BlockDomainRequest, opts ...grpc.CallOption) (*BlockedDomainsResponse, error)
+	UnblockDomain(ctx context.Context, in *BlockDomainRequest, opts ...grpc.CallOption) (*BlockedDomainsResponse, error)
+	ListBlockedDomains(ctx context.Context, in *ListBlockedDomainsRequest, opts ...grpc.CallOption) (*BlockedDomainsResponse, error)
+	GetLinkOwner(ctx context.Context, in *GetLinkOwnerRequest, opts ...grpc.CallOption) (*GetLinkOwnerResponse, error)
 }
 
 type uRLShortenerClient struct {
@@ -103,6 +153,16 @@ func (c *uRLShortenerClient) ListUserURLs(ctx context.Context, in *ListUserURLsR
 	return out, nil
 }
 
+func (c *uRLShortenerClient) SearchUserURLs(ctx context.Context, in *SearchUserURLsRequest, opts ...grpc.CallOption) (*ListUserURLsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListUserURLsResponse)
+	err := c.cc.Invoke(ctx, URLShortener_SearchUserURLs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *uRLShortenerClient) DeleteUserURLs(ctx context.Context, in *DeleteUserURLsRequest, opts ...grpc.CallOption) (*DeleteUserURLsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(DeleteUserURLsResponse)
@@ -143,19 +203,242 @@ func (c *uRLShortenerClient) Stats(ctx context.Context, in *StatsRequest, opts .
 	return out, nil
 }
 
+func (c *uRLShortenerClient) StreamUserURLs(ctx context.Context, in *ListUserURLsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[URLPair], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &URLShortener_ServiceDesc.Streams[0], URLShortener_StreamUserURLs_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListUserURLsRequest, URLPair]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type URLShortener_StreamUserURLsClient = grpc.ServerStreamingClient[URLPair]
+
+func (c *uRLShortenerClient) ShortenStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ShortenStreamRequest, URLPair], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &URLShortener_ServiceDesc.Streams[1], URLShortener_ShortenStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ShortenStreamRequest, URLPair]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type URLShortener_ShortenStreamClient = grpc.BidiStreamingClient[ShortenStreamRequest, URLPair]
+
+func (c *uRLShortenerClient) GetQRCode(ctx context.Context, in *GetQRCodeRequest, opts ...grpc.CallOption) (*GetQRCodeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetQRCodeResponse)
+	err := c.cc.Invoke(ctx, URLShortener_GetQRCode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) GetUTMTemplate(ctx context.Context, in *GetUTMTemplateRequest, opts ...grpc.CallOption) (*UTMTemplateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UTMTemplateResponse)
+	err := c.cc.Invoke(ctx, URLShortener_GetUTMTemplate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) SetUTMTemplate(ctx context.Context, in *SetUTMTemplateRequest, opts ...grpc.CallOption) (*UTMTemplateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UTMTemplateResponse)
+	err := c.cc.Invoke(ctx, URLShortener_SetUTMTemplate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) SetLinkPassword(ctx context.Context, in *SetLinkPasswordRequest, opts ...grpc.CallOption) (*SetLinkPasswordResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetLinkPasswordResponse)
+	err := c.cc.Invoke(ctx, URLShortener_SetLinkPassword_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) GetLinkRules(ctx context.Context, in *GetLinkRulesRequest, opts ...grpc.CallOption) (*LinkRulesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LinkRulesResponse)
+	err := c.cc.Invoke(ctx, URLShortener_GetLinkRules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) SetLinkRules(ctx context.Context, in *SetLinkRulesRequest, opts ...grpc.CallOption) (*LinkRulesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LinkRulesResponse)
+	err := c.cc.Invoke(ctx, URLShortener_SetLinkRules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) GetLinkVariants(ctx context.Context, in *GetLinkVariantsRequest, opts ...grpc.CallOption) (*LinkVariantsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LinkVariantsResponse)
+	err := c.cc.Invoke(ctx, URLShortener_GetLinkVariants_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) SetLinkVariants(ctx context.Context, in *SetLinkVariantsRequest, opts ...grpc.CallOption) (*LinkVariantsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LinkVariantsResponse)
+	err := c.cc.Invoke(ctx, URLShortener_SetLinkVariants_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) AddLinkTags(ctx context.Context, in *LinkTagsRequest, opts ...grpc.CallOption) (*LinkLabelsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LinkLabelsResponse)
+	err := c.cc.Invoke(ctx, URLShortener_AddLinkTags_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) RemoveLinkTags(ctx context.Context, in *LinkTagsRequest, opts ...grpc.CallOption) (*LinkLabelsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LinkLabelsResponse)
+	err := c.cc.Invoke(ctx, URLShortener_RemoveLinkTags_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) SetLinkFolder(ctx context.Context, in *SetLinkFolderRequest, opts ...grpc.CallOption) (*LinkLabelsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LinkLabelsResponse)
+	err := c.cc.Invoke(ctx, URLShortener_SetLinkFolder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) PurgeDeleted(ctx context.Context, in *PurgeDeletedRequest, opts ...grpc.CallOption) (*PurgeDeletedResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PurgeDeletedResponse)
+	err := c.cc.Invoke(ctx, URLShortener_PurgeDeleted_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) BlockDomain(ctx context.Context, in *BlockDomainRequest, opts ...grpc.CallOption) (*BlockedDomainsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BlockedDomainsResponse)
+	err := c.cc.Invoke(ctx, URLShortener_BlockDomain_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) UnblockDomain(ctx context.Context, in *BlockDomainRequest, opts ...grpc.CallOption) (*BlockedDomainsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BlockedDomainsResponse)
+	err := c.cc.Invoke(ctx, URLShortener_UnblockDomain_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) ListBlockedDomains(ctx context.Context, in *ListBlockedDomainsRequest, opts ...grpc.CallOption) (*BlockedDomainsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BlockedDomainsResponse)
+	err := c.cc.Invoke(ctx, URLShortener_ListBlockedDomains_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *uRLShortenerClient) GetLinkOwner(ctx context.Context, in *GetLinkOwnerRequest, opts ...grpc.CallOption) (*GetLinkOwnerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetLinkOwnerResponse)
+	err := c.cc.Invoke(ctx, URLShortener_GetLinkOwner_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // URLShortenerServer is the server API for URLShortener service.
 // All implementations must embed UnimplementedURLShortenerServer
 // for forward compatibility.
+//
+// URLShortener is served over gRPC and, for the methods annotated with
+// google.api.http, as the /api/v2 REST API through the HTTP gateway.
 type URLShortenerServer interface {
 	Shorten(context.Context, *ShortenRequest) (*ShortenResponse, error)
 	ShortenJSON(context.Context, *ShortenRequest) (*ShortenResponse, error)
 	ShortenBatch(context.Context, *ShortenBatchRequest) (*ShortenBatchResponse, error)
+	// Expand over REST takes the password of a protected link in the body of the POST binding only;
+	// the gateway refuses passwords in query strings, which end up in access logs.
 	Expand(context.Context, *ExpandRequest) (*ExpandResponse, error)
 	ListUserURLs(context.Context, *ListUserURLsRequest) (*ListUserURLsResponse, error)
+	SearchUserURLs(context.Context, *SearchUserURLsRequest) (*ListUserURLsResponse, error)
 	DeleteUserURLs(context.Context, *DeleteUserURLsRequest) (*DeleteUserURLsResponse, error)
 	HealthCheck(context.Context, *HealthCheckRequest) (*HealthCheckResponse, error)
 	Ping(context.Context, *PingRequest) (*PingResponse, error)
+	// Stats is only answered for callers connecting from the trusted subnet or
+	// sending an admin API key, and is not exposed through the gateway.
 	Stats(context.Context, *StatsRequest) (*StatsResponse, error)
+	StreamUserURLs(*ListUserURLsRequest, grpc.ServerStreamingServer[URLPair]) error
+	ShortenStream(grpc.BidiStreamingServer[ShortenStreamRequest, URLPair]) error
+	GetQRCode(context.Context, *GetQRCodeRequest) (*GetQRCodeResponse, error)
+	GetUTMTemplate(context.Context, *GetUTMTemplateRequest) (*UTMTemplateResponse, error)
+	SetUTMTemplate(context.Context, *SetUTMTemplateRequest) (*UTMTemplateResponse, error)
+	SetLinkPassword(context.Context, *SetLinkPasswordRequest) (*SetLinkPasswordResponse, error)
+	GetLinkRules(context.Context, *GetLinkRulesRequest) (*LinkRulesResponse, error)
+	SetLinkRules(context.Context, *SetLinkRulesRequest) (*LinkRulesResponse, error)
+	// A/B variants of a link with their click counters
+	GetLinkVariants(context.Context, *GetLinkVariantsRequest) (*LinkVariantsResponse, error)
+	SetLinkVariants(context.Context, *SetLinkVariantsRequest) (*LinkVariantsResponse, error)
+	// Tags and folder of a link
+	AddLinkTags(context.Context, *LinkTagsRequest) (*LinkLabelsResponse, error)
+	RemoveLinkTags(context.Context, *LinkTagsRequest) (*LinkLabelsResponse, error)
+	SetLinkFolder(context.Context, *SetLinkFolderRequest) (*LinkLabelsResponse, error)
+	// Administration, used by shortugoctl. Like Stats, these methods are only answered
+	// for callers connecting from the trusted subnet or sending an admin API key in the
+	// "authorization: Bearer <key>" metadata, and are not exposed through the gateway.
+	PurgeDeleted(context.Context, *PurgeDeletedRequest) (*PurgeDeletedResponse, error)
+	BlockDomain(context.Context, *BlockDomainRequest) (*BlockedDomainsResponse, error)
+	UnblockDomain(context.Context, *BlockDomainRequest) (*BlockedDomainsResponse, error)
+	ListBlockedDomains(context.Context, *ListBlockedDomainsRequest) (*BlockedDomainsResponse, error)
+	GetLinkOwner(context.Context, *GetLinkOwnerRequest) (*GetLinkOwnerResponse, error)
 	mustEmbedUnimplementedURLShortenerServer()
 }
 
@@ -181,6 +464,9 @@ func (UnimplementedURLShortenerServer) Expand(context.Context, *ExpandRequest) (
 func (UnimplementedURLShortenerServer) ListUserURLs(context.Context, *ListUserURLsRequest) (*ListUserURLsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListUserURLs not implemented")
 }
+func (UnimplementedURLShortenerServer) SearchUserURLs(context.Context, *SearchUserURLsRequest) (*ListUserURLsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchUserURLs not implemented")
+}
 func (UnimplementedURLShortenerServer) DeleteUserURLs(context.Context, *DeleteUserURLsRequest) (*DeleteUserURLsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteUserURLs not implemented")
 }
@@ -193,6 +479,60 @@ func (UnimplementedURLShortenerServer) Ping(context.Context, *PingRequest) (*Pin
 func (UnimplementedURLShortenerServer) Stats(context.Context, *StatsRequest) (*StatsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Stats not implemented")
 }
+func (UnimplementedURLShortenerServer) StreamUserURLs(*ListUserURLsRequest, grpc.ServerStreamingServer[URLPair]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamUserURLs not implemented")
+}
+func (UnimplementedURLShortenerServer) ShortenStream(grpc.BidiStreamingServer[ShortenStreamRequest, URLPair]) error {
+	return status.Errorf(codes.Unimplemented, "method ShortenStream not implemented")
+}
+func (UnimplementedURLShortenerServer) GetQRCode(context.Context, *GetQRCodeRequest) (*GetQRCodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetQRCode not implemented")
+}
+func (UnimplementedURLShortenerServer) GetUTMTemplate(context.Context, *GetUTMTemplateRequest) (*UTMTemplateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUTMTemplate not implemented")
+}
+func (UnimplementedURLShortenerServer) SetUTMTemplate(context.Context, *SetUTMTemplateRequest) (*UTMTemplateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetUTMTemplate not implemented")
+}
+func (UnimplementedURLShortenerServer) SetLinkPassword(context.Context, *SetLinkPasswordRequest) (*SetLinkPasswordResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetLinkPassword not implemented")
+}
+func (UnimplementedURLShortenerServer) GetLinkRules(context.Context, *GetLinkRulesRequest) (*LinkRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLinkRules not implemented")
+}
+func (UnimplementedURLShortenerServer) SetLinkRules(context.Context, *SetLinkRulesRequest) (*LinkRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetLinkRules not implemented")
+}
+func (UnimplementedURLShortenerServer) GetLinkVariants(context.Context, *GetLinkVariantsRequest) (*LinkVariantsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLinkVariants not implemented")
+}
+func (UnimplementedURLShortenerServer) SetLinkVariants(context.Context, *SetLinkVariantsRequest) (*LinkVariantsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetLinkVariants not implemented")
+}
+func (UnimplementedURLShortenerServer) AddLinkTags(context.Context, *LinkTagsRequest) (*LinkLabelsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddLinkTags not implemented")
+}
+func (UnimplementedURLShortenerServer) RemoveLinkTags(context.Context, *LinkTagsRequest) (*LinkLabelsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveLinkTags not implemented")
+}
+func (UnimplementedURLShortenerServer) SetLinkFolder(context.Context, *SetLinkFolderRequest) (*LinkLabelsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetLinkFolder not implemented")
+}
+func (UnimplementedURLShortenerServer) PurgeDeleted(context.Context, *PurgeDeletedRequest) (*PurgeDeletedResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurgeDeleted not implemented")
+}
+func (UnimplementedURLShortenerServer) BlockDomain(context.Context, *BlockDomainRequest) (*BlockedDomainsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BlockDomain not implemented")
+}
+func (UnimplementedURLShortenerServer) UnblockDomain(context.Context, *BlockDomainRequest) (*BlockedDomainsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnblockDomain not implemented")
+}
+func (UnimplementedURLShortenerServer) ListBlockedDomains(context.Context, *ListBlockedDomainsRequest) (*BlockedDomainsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBlockedDomains not implemented")
+}
+func (UnimplementedURLShortenerServer) GetLinkOwner(context.Context, *GetLinkOwnerRequest) (*GetLinkOwnerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLinkOwner not implemented")
+}
 func (UnimplementedURLShortenerServer) mustEmbedUnimplementedURLShortenerServer() {}
 func (UnimplementedURLShortenerServer) testEmbeddedByValue()                      {}
 
@@ -304,6 +644,24 @@ func _URLShortener_ListUserURLs_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _URLShortener_SearchUserURLs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchUserURLsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).SearchUserURLs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_SearchUserURLs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).SearchUserURLs(ctx, req.(*SearchUserURLsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _URLShortener_DeleteUserURLs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DeleteUserURLsRequest)
 	if err := dec(in); err != nil {
@@ -376,48 +734,658 @@ func _URLShortener_Stats_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
-// URLShortener_ServiceDesc is the grpc.ServiceDesc for URLShortener service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var URLShortener_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "shortugo.URLShortener",
-	HandlerType: (*URLShortenerServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "Shorten",
-			Handler:    _URLShortener_Shorten_Handler,
-		},
-		{
-			MethodName: "ShortenJSON",
-			Handler:    _URLShortener_ShortenJSON_Handler,
-		},
-		{
-			MethodName: "ShortenBatch",
-			Handler:    _URLShortener_ShortenBatch_Handler,
-		},
-		{
-			MethodName: "Expand",
-			Handler:    _URLShortener_Expand_Handler,
-		},
-		{
-			MethodName: "ListUserURLs",
-			Handler:    _URLShortener_ListUserURLs_Handler,
-		},
-		{
-			MethodName: "DeleteUserURLs",
-			Handler:    _URLShortener_DeleteUserURLs_Handler,
-		},
-		{
-			MethodName: "HealthCheck",
-			Handler:    _URLShortener_HealthCheck_Handler,
-		},
-		{
-			MethodName: "Ping",
-			Handler:    _URLShortener_Ping_Handler,
-		},
-		{
-			MethodName: "Stats",
-			Handler:    _URLShortener_Stats_Handler,
+func _URLShortener_StreamUserURLs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListUserURLsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(URLShortenerServer).StreamUserURLs(m, &grpc.GenericServerStream[ListUserURLsRequest, URLPair]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type URLShortener_StreamUserURLsServer = grpc.ServerStreamingServer[URLPair]
+
+func _URLShortener_ShortenStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(URLShortenerServer).ShortenStream(&grpc.GenericServerStream[ShortenStreamRequest, URLPair]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type URLShortener_ShortenStreamServer = grpc.BidiStreamingServer[ShortenStreamRequest, URLPair]
+
+func _URLShortener_GetQRCode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetQRCodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).GetQRCode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_GetQRCode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).GetQRCode(ctx, req.(*GetQRCodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_GetUTMTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUTMTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).GetUTMTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_GetUTMTemplate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).GetUTMTemplate(ctx, req.(*GetUTMTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_SetUTMTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetUTMTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).SetUTMTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_SetUTMTemplate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).SetUTMTemplate(ctx, req.(*SetUTMTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_SetLinkPassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetLinkPasswordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).SetLinkPassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_SetLinkPassword_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).SetLinkPassword(ctx, req.(*SetLinkPasswordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_GetLinkRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLinkRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).GetLinkRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_GetLinkRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).GetLinkRules(ctx, req.(*GetLinkRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_SetLinkRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetLinkRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).SetLinkRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_SetLinkRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).SetLinkRules(ctx, req.(*SetLinkRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_GetLinkVariants_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLinkVariantsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).GetLinkVariants(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_GetLinkVariants_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).GetLinkVariants(ctx, req.(*GetLinkVariantsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_SetLinkVariants_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetLinkVariantsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).SetLinkVariants(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_SetLinkVariants_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).SetLinkVariants(ctx, req.(*SetLinkVariantsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_AddLinkTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LinkTagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).AddLinkTags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_AddLinkTags_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).AddLinkTags(ctx, req.(*LinkTagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_RemoveLinkTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LinkTagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).RemoveLinkTags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_RemoveLinkTags_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).RemoveLinkTags(ctx, req.(*LinkTagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_SetLinkFolder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetLinkFolderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).SetLinkFolder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_SetLinkFolder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).SetLinkFolder(ctx, req.(*SetLinkFolderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_PurgeDeleted_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeDeletedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).PurgeDeleted(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_PurgeDeleted_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).PurgeDeleted(ctx, req.(*PurgeDeletedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_BlockDomain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlockDomainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).BlockDomain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_BlockDomain_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).BlockDomain(ctx, req.(*BlockDomainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_UnblockDomain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlockDomainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).UnblockDomain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_UnblockDomain_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).UnblockDomain(ctx, req.(*BlockDomainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_ListBlockedDomains_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBlockedDomainsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).ListBlockedDomains(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_ListBlockedDomains_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).ListBlockedDomains(ctx, req.(*ListBlockedDomainsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _URLShortener_GetLinkOwner_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLinkOwnerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(URLShortenerServer).GetLinkOwner(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: URLShortener_GetLinkOwner_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(URLShortenerServer).GetLinkOwner(ctx, req.(*GetLinkOwnerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// URLShortener_ServiceDesc is the grpc.ServiceDesc for URLShortener service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var URLShortener_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "shortugo.URLShortener",
+	HandlerType: (*URLShortenerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Shorten",
+			Handler:    _URLShortener_Shorten_Handler,
+		},
+		{
+			MethodName: "ShortenJSON",
+			Handler:    _URLShortener_ShortenJSON_Handler,
+		},
+		{
+			MethodName: "ShortenBatch",
+			Handler:    _URLShortener_ShortenBatch_Handler,
+		},
+		{
+			MethodName: "Expand",
+			Handler:    _URLShortener_Expand_Handler,
+		},
+		{
+			MethodName: "ListUserURLs",
+			Handler:    _URLShortener_ListUserURLs_Handler,
+		},
+		{
+			MethodName: "SearchUserURLs",
+			Handler:    _URLShortener_SearchUserURLs_Handler,
+		},
+		{
+			MethodName: "DeleteUserURLs",
+			Handler:    _URLShortener_DeleteUserURLs_Handler,
+		},
+		{
+			MethodName: "HealthCheck",
+			Handler:    _URLShortener_HealthCheck_Handler,
+		},
+		{
+			MethodName: "Ping",
+			Handler:    _URLShortener_Ping_Handler,
+		},
+		{
+			MethodName: "Stats",
+			Handler:    _URLShortener_Stats_Handler,
+		},
+		{
+			MethodName: "GetQRCode",
+			Handler:    _URLShortener_GetQRCode_Handler,
+		},
+		{
+			MethodName: "GetUTMTemplate",
+			Handler:    _URLShortener_GetUTMTemplate_Handler,
+		},
+		{
+			MethodName: "SetUTMTemplate",
+			Handler:    _URLShortener_SetUTMTemplate_Handler,
+		},
+		{
+			MethodName: "SetLinkPassword",
+			Handler:    _URLShortener_SetLinkPassword_Handler,
+		},
+		{
+			MethodName: "GetLinkRules",
+			Handler:    _URLShortener_GetLinkRules_Handler,
+		},
+		{
+			MethodName: "SetLinkRules",
+			Handler:    _URLShortener_SetLinkRules_Handler,
+		},
+		{
+			MethodName: "GetLinkVariants",
+			Handler:    _URLShortener_GetLinkVariants_Handler,
+		},
+		{
+			MethodName: "SetLinkVariants",
+			Handler:    _URLShortener_SetLinkVariants_Handler,
+		},
+		{
+			MethodName: "AddLinkTags",
+			Handler:    _URLShortener_AddLinkTags_Handler,
+		},
+		{
+			MethodName: "RemoveLinkTags",
+			Handler:    _URLShortener_RemoveLinkTags_Handler,
+		},
+		{
+			MethodName: "SetLinkFolder",
+			Handler:    _URLShortener_SetLinkFolder_Handler,
+		},
+		{
+			MethodName: "PurgeDeleted",
+			Handler:    _URLShortener_PurgeDeleted_Handler,
+		},
+		{
+			MethodName: "BlockDomain",
+			Handler:    _URLShortener_BlockDomain_Handler,
+		},
+		{
+			MethodName: "UnblockDomain",
+			Handler:    _URLShortener_UnblockDomain_Handler,
+		},
+		{
+			MethodName: "ListBlockedDomains",
+			Handler:    _URLShortener_ListBlockedDomains_Handler,
+		},
+		{
+			MethodName: "GetLinkOwner",
+			Handler:    _URLShortener_GetLinkOwner_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamUserURLs",
+			Handler:       _URLShortener_StreamUserURLs_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ShortenStream",
+			Handler:       _URLShortener_ShortenStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "proto/shortugo.proto",
+}
+
+const (
+	Admin_GetLink_FullMethodName       = "/shortugo.Admin/GetLink"
+	Admin_DisableLink_FullMethodName   = "/shortugo.Admin/DisableLink"
+	Admin_EnableLink_FullMethodName    = "/shortugo.Admin/EnableLink"
+	Admin_ListUserLinks_FullMethodName = "/shortugo.Admin/ListUserLinks"
+)
+
+// AdminClient is the client API for Admin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Admin is the API of support staff over any link and user. Callers authenticate
+// with an admin API key in the "authorization: Bearer <key>" metadata; every call
+// is audited. Not exposed through the gateway, see /api/admin on the HTTP server.
+type AdminClient interface {
+	GetLink(ctx context.Context, in *AdminLinkRequest, opts ...grpc.CallOption) (*AdminLink, error)
+	DisableLink(ctx context.Context, in *DisableLinkRequest, opts ...grpc.CallOption) (*AdminLink, error)
+	EnableLink(ctx context.Context, in *AdminLinkRequest, opts ...grpc.CallOption) (*AdminLink, error)
+	ListUserLinks(ctx context.Context, in *AdminUserLinksRequest, opts ...grpc.CallOption) (*AdminLinksResponse, error)
+}
+
+type adminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminClient(cc grpc.ClientConnInterface) AdminClient {
+	return &adminClient{cc}
+}
+
+func (c *adminClient) GetLink(ctx context.Context, in *AdminLinkRequest, opts ...grpc.CallOption) (*AdminLink, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AdminLink)
+	err := c.cc.Invoke(ctx, Admin_GetLink_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) DisableLink(ctx context.Context, in *DisableLinkRequest, opts ...grpc.CallOption) (*AdminLink, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AdminLink)
+	err := c.cc.Invoke(ctx, Admin_DisableLink_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) EnableLink(ctx context.Context, in *AdminLinkRequest, opts ...grpc.CallOption) (*AdminLink, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AdminLink)
+	err := c.cc.Invoke(ctx, Admin_EnableLink_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ListUserLinks(ctx context.Context, in *AdminUserLinksRequest, opts ...grpc.CallOption) (*AdminLinksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AdminLinksResponse)
+	err := c.cc.Invoke(ctx, Admin_ListUserLinks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServer is the server API for Admin service.
+// All implementations must embed UnimplementedAdminServer
+// for forward compatibility.
+//
+// Admin is the API of support staff over any link and user. Callers authenticate
+// with an admin API key in the "authorization: Bearer <key>" metadata; every call
+// is audited. Not exposed through the gateway, see /api/admin on the HTTP server.
+type AdminServer interface {
+	GetLink(context.Context, *AdminLinkRequest) (*AdminLink, error)
+	DisableLink(context.Context, *DisableLinkRequest) (*AdminLink, error)
+	EnableLink(context.Context, *AdminLinkRequest) (*AdminLink, error)
+	ListUserLinks(context.Context, *AdminUserLinksRequest) (*AdminLinksResponse, error)
+	mustEmbedUnimplementedAdminServer()
+}
+
+// UnimplementedAdminServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAdminServer struct{}
+
+func (UnimplementedAdminServer) GetLink(context.Context, *AdminLinkRequest) (*AdminLink, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLink not implemented")
+}
+func (UnimplementedAdminServer) DisableLink(context.Context, *DisableLinkRequest) (*AdminLink, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DisableLink not implemented")
+}
+func (UnimplementedAdminServer) EnableLink(context.Context, *AdminLinkRequest) (*AdminLink, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EnableLink not implemented")
+}
+func (UnimplementedAdminServer) ListUserLinks(context.Context, *AdminUserLinksRequest) (*AdminLinksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUserLinks not implemented")
+}
+func (UnimplementedAdminServer) mustEmbedUnimplementedAdminServer() {}
+func (UnimplementedAdminServer) testEmbeddedByValue()               {}
+
+// UnsafeAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServer will
+// result in compilation errors.
+type UnsafeAdminServer interface {
+	mustEmbedUnimplementedAdminServer()
+}
+
+func RegisterAdminServer(s grpc.ServiceRegistrar, srv AdminServer) {
+	// If the following call pancis, it indicates UnimplementedAdminServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Admin_ServiceDesc, srv)
+}
+
+func _Admin_GetLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminLinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).GetLink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_GetLink_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).GetLink(ctx, req.(*AdminLinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_DisableLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisableLinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).DisableLink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_DisableLink_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).DisableLink(ctx, req.(*DisableLinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_EnableLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminLinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).EnableLink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_EnableLink_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).EnableLink(ctx, req.(*AdminLinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ListUserLinks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminUserLinksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ListUserLinks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_ListUserLinks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ListUserLinks(ctx, req.(*AdminUserLinksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Admin_ServiceDesc is the grpc.ServiceDesc for Admin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Admin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "shortugo.Admin",
+	HandlerType: (*AdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetLink",
+			Handler:    _Admin_GetLink_Handler,
+		},
+		{
+			MethodName: "DisableLink",
+			Handler:    _Admin_DisableLink_Handler,
+		},
+		{
+			MethodName: "EnableLink",
+			Handler:    _Admin_EnableLink_Handler,
+		},
+		{
+			MethodName: "ListUserLinks",
+			Handler:    _Admin_ListUserLinks_Handler,
 		},
 	},
 	Streams:  []grpc.StreamDesc{},